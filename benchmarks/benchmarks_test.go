@@ -0,0 +1,223 @@
+package benchmarks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+)
+
+// benchPlayers is the board size the Go benchmarks seed
+const benchPlayers = 1000
+
+// openTestPostgres opens the postgres backend on BENCH_POSTGRES_URL, or returns nil
+// when it's unset
+func openTestPostgres(tb testing.TB) Store {
+	url := os.Getenv("BENCH_POSTGRES_URL")
+	if url == "" {
+		return nil
+	}
+	store, closeStore, err := OpenPostgres(context.Background(), url)
+	if err != nil {
+		tb.Fatalf("OpenPostgres failed: %v", err)
+	}
+	tb.Cleanup(closeStore)
+	return store
+}
+
+// benchDatabases returns the databases to benchmark against: the in-memory fake, which
+// measures the layouts' own cost, a SQLite file, and Valkey when one is reachable
+func benchDatabases(b *testing.B) map[string]func() database.DB {
+	dbs := map[string]func() database.DB{
+		"fake": func() database.DB { return database.NewFake() },
+	}
+	if sqlite, err := database.NewSQLiteDB(filepath.Join(b.TempDir(), "bench.db")); err == nil {
+		b.Cleanup(func() { sqlite.Close() })
+		dbs["sqlite"] = func() database.DB { return sqlite }
+	}
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		return dbs
+	}
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		return dbs
+	}
+	if err := db.Ping(context.Background()); err != nil {
+		db.Close()
+		return dbs
+	}
+	b.Cleanup(func() { db.Close() })
+	dbs["valkey"] = func() database.DB { return db }
+	return dbs
+}
+
+// benchStores runs fn against every backend available on every database, and Postgres
+// when BENCH_POSTGRES_URL names one, each with a game of its own
+func benchStores(b *testing.B, fn func(b *testing.B, store Store, gameID string)) {
+	if store := openTestPostgres(b); store != nil {
+		gameID := fmt.Sprintf("bench-%s-%d", b.Name(), time.Now().UnixNano())
+		b.Run(BackendPostgres, func(b *testing.B) {
+			fn(b, store, gameID)
+		})
+	}
+	for dbName, open := range benchDatabases(b) {
+		for _, backend := range Backends {
+			store, err := Open(backend, open())
+			if errors.Is(err, ErrBackendUnavailable) {
+				continue
+			}
+			if err != nil {
+				b.Fatalf("Open(%s) failed: %v", backend, err)
+			}
+			gameID := fmt.Sprintf("bench-%s-%d", b.Name(), time.Now().UnixNano())
+			b.Run(fmt.Sprintf("%s/%s", backend, dbName), func(b *testing.B) {
+				fn(b, store, gameID)
+			})
+		}
+	}
+}
+
+func BenchmarkSubmit(b *testing.B) {
+	ctx := context.Background()
+	benchStores(b, func(b *testing.B, store Store, gameID string) {
+		rng := rand.New(rand.NewSource(1))
+		b.ResetTimer()
+		for b.Loop() {
+			if err := store.Submit(ctx, gameID, PlayerInitials(rng.Intn(benchPlayers)), rng.Int63n(1_000_000)); err != nil {
+				b.Fatalf("Submit failed: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkTop(b *testing.B) {
+	ctx := context.Background()
+	benchStores(b, func(b *testing.B, store Store, gameID string) {
+		if err := Seed(ctx, store, gameID, benchPlayers, 1); err != nil {
+			b.Fatalf("Seed failed: %v", err)
+		}
+		b.ResetTimer()
+		for b.Loop() {
+			if _, err := store.Top(ctx, gameID, TopN); err != nil {
+				b.Fatalf("Top failed: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkRank(b *testing.B) {
+	ctx := context.Background()
+	benchStores(b, func(b *testing.B, store Store, gameID string) {
+		if err := Seed(ctx, store, gameID, benchPlayers, 1); err != nil {
+			b.Fatalf("Seed failed: %v", err)
+		}
+		rng := rand.New(rand.NewSource(1))
+		b.ResetTimer()
+		for b.Loop() {
+			if _, err := store.Rank(ctx, gameID, PlayerInitials(rng.Intn(benchPlayers))); err != nil {
+				b.Fatalf("Rank failed: %v", err)
+			}
+		}
+	})
+}
+
+// TestBackendsAgree keeps the layouts honest: a faster backend only counts if it
+// ranks players the same way. Postgres is checked when BENCH_POSTGRES_URL names one.
+func TestBackendsAgree(t *testing.T) {
+	ctx := context.Background()
+	const players = 50
+	gameID := fmt.Sprintf("agree-%d", time.Now().UnixNano())
+
+	sqlite, err := database.NewSQLiteDB(filepath.Join(t.TempDir(), "agree.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteDB failed: %v", err)
+	}
+	defer sqlite.Close()
+
+	var wantTop []string
+	var wantRanks []int
+	for _, backend := range Backends {
+		var store Store
+		switch backend {
+		case BackendPostgres:
+			if store = openTestPostgres(t); store == nil {
+				continue
+			}
+		case BackendSQLite:
+			if store, err = Open(backend, sqlite); err != nil {
+				t.Fatalf("Open(%s) failed: %v", backend, err)
+			}
+		default:
+			if store, err = Open(backend, database.NewFake()); err != nil {
+				t.Fatalf("Open(%s) failed: %v", backend, err)
+			}
+		}
+		if err := Seed(ctx, store, gameID, players, 7); err != nil {
+			t.Fatalf("Seed(%s) failed: %v", backend, err)
+		}
+		// A lower score doesn't replace a player's best
+		if err := store.Submit(ctx, gameID, PlayerInitials(0), 1); err != nil {
+			t.Fatalf("Submit(%s) failed: %v", backend, err)
+		}
+
+		top, err := store.Top(ctx, gameID, TopN)
+		if err != nil {
+			t.Fatalf("Top(%s) failed: %v", backend, err)
+		}
+		ranks := make([]int, players)
+		for i := range players {
+			if ranks[i], err = store.Rank(ctx, gameID, PlayerInitials(i)); err != nil {
+				t.Fatalf("Rank(%s, %s) failed: %v", backend, PlayerInitials(i), err)
+			}
+		}
+
+		if wantTop == nil {
+			wantTop, wantRanks = top, ranks
+			continue
+		}
+		if !slices.Equal(top, wantTop) {
+			t.Errorf("%s top %v, want %v", backend, top, wantTop)
+		}
+		if !slices.Equal(ranks, wantRanks) {
+			t.Errorf("%s ranks %v, want %v", backend, ranks, wantRanks)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	store, err := Open(BackendSortedSet, database.NewFake())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	for _, workload := range Workloads {
+		result, err := Run(ctx, BackendSortedSet, store, Workload{Name: workload, GameID: "run-" + workload, Players: 20, Ops: 100, Concurrency: 4, Seed: 1})
+		if err != nil {
+			t.Fatalf("Run(%s) failed: %v", workload, err)
+		}
+		if result.Ops != 100 || result.Errors != 0 || result.OpsPerSec <= 0 {
+			t.Errorf("Run(%s) = %+v, want 100 error-free ops", workload, result)
+		}
+		if result.P50Ns > result.P99Ns || result.P99Ns > result.MaxNs {
+			t.Errorf("Run(%s) latencies out of order: %+v", workload, result)
+		}
+	}
+
+	if _, err := Open(BackendSQLite, database.NewFake()); !errors.Is(err, ErrBackendUnavailable) {
+		t.Errorf("Expected sqlite to be unavailable without a SQLite database, got %v", err)
+	}
+	if _, err := Open(BackendPostgres, database.NewFake()); !errors.Is(err, ErrBackendUnavailable) {
+		t.Errorf("Expected postgres to need OpenPostgres, got %v", err)
+	}
+	if _, err := Run(ctx, BackendSortedSet, store, Workload{Name: "bogus", GameID: "bogus", Players: 1, Ops: 1}); err == nil {
+		t.Error("Expected an unknown workload to fail")
+	}
+}
@@ -0,0 +1,44 @@
+package benchmarks
+
+import (
+	"context"
+
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+)
+
+// blobStore runs the workloads through the leaderboard service, which keeps each game's
+// high scores, board and ranking as JSON documents. It's the baseline other layouts are
+// measured against, so it pays for everything a real submission does.
+type blobStore struct {
+	service *leaderboard.Service
+}
+
+func newBlobStore(db database.DB) *blobStore {
+	return &blobStore{service: leaderboard.NewService(db)}
+}
+
+func (b *blobStore) Submit(ctx context.Context, gameID, initials string, score int64) error {
+	return b.service.SubmitScore(ctx, gameID, initials, score)
+}
+
+func (b *blobStore) Top(ctx context.Context, gameID string, n int) ([]string, error) {
+	board, err := b.service.GetLeaderboard(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	entries := board.Entries[:min(n, len(board.Entries))]
+	initials := make([]string, len(entries))
+	for i, entry := range entries {
+		initials[i] = entry.Initials
+	}
+	return initials, nil
+}
+
+func (b *blobStore) Rank(ctx context.Context, gameID, initials string) (int, error) {
+	rank, err := b.service.GetPlayerRank(ctx, gameID, initials)
+	if err != nil {
+		return 0, err
+	}
+	return rank.Rank, nil
+}
@@ -0,0 +1,86 @@
+package benchmarks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"rawboard/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// sortedSetStore keeps each game's high scores in a sorted set, with every player's
+// best also under its own key so a submission only touches what it changes
+type sortedSetStore struct {
+	db   database.DB
+	sets database.SortedSets
+}
+
+func newSortedSetStore(db database.DB, sets database.SortedSets) *sortedSetStore {
+	return &sortedSetStore{db: db, sets: sets}
+}
+
+func boardSetKey(gameID string) string {
+	return fmt.Sprintf("bench:board:%s", gameID)
+}
+
+func bestScoreKey(gameID, initials string) string {
+	return fmt.Sprintf("bench:best:%s:%s", gameID, initials)
+}
+
+func (z *sortedSetStore) Submit(ctx context.Context, gameID, initials string, score int64) error {
+	best, found, err := z.best(ctx, gameID, initials)
+	if err != nil {
+		return err
+	}
+	if found && score <= best {
+		return nil
+	}
+	if err := z.db.Set(ctx, bestScoreKey(gameID, initials), strconv.FormatInt(score, 10)); err != nil {
+		return err
+	}
+	return z.sets.ZAdd(ctx, boardSetKey(gameID), database.ZMember{Score: float64(score), Member: initials})
+}
+
+func (z *sortedSetStore) Top(ctx context.Context, gameID string, n int) ([]string, error) {
+	return z.sets.ZRangeByScore(ctx, boardSetKey(gameID), database.ZRange{
+		Min: math.Inf(-1), Max: math.Inf(1), Reverse: true, Count: int64(n),
+	})
+}
+
+func (z *sortedSetStore) Rank(ctx context.Context, gameID, initials string) (int, error) {
+	best, found, err := z.best(ctx, gameID, initials)
+	if err != nil {
+		return 0, err
+	}
+	if !found {
+		return 0, fmt.Errorf("no scores found for player %s", initials)
+	}
+	// Everyone strictly ahead, so ties share the better rank
+	ahead, err := z.sets.ZRangeByScore(ctx, boardSetKey(gameID), database.ZRange{
+		Min: math.Nextafter(float64(best), math.Inf(1)), Max: math.Inf(1),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ahead) + 1, nil
+}
+
+// best returns a player's high score, and whether they have one
+func (z *sortedSetStore) best(ctx context.Context, gameID, initials string) (int64, bool, error) {
+	data, err := z.db.Get(ctx, bestScoreKey(gameID, initials))
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	score, err := strconv.ParseInt(data, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse high score: %w", err)
+	}
+	return score, true, nil
+}
@@ -0,0 +1,73 @@
+package benchmarks
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"rawboard/internal/database"
+)
+
+// sqliteSchema keeps every player's high score as a row, indexed in board order
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS bench_high_scores (game_id TEXT NOT NULL, initials TEXT NOT NULL, score INTEGER NOT NULL, PRIMARY KEY (game_id, initials));
+CREATE INDEX IF NOT EXISTS bench_high_scores_by_score ON bench_high_scores (game_id, score DESC, initials DESC);
+`
+
+// sqliteStore keeps each game's high scores in a table of the SQLite database, so a
+// submission is a single conditional upsert and ranks are counted by the index
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(db *database.SQLiteDB) (*sqliteStore, error) {
+	if _, err := db.SQL().Exec(sqliteSchema); err != nil {
+		return nil, fmt.Errorf("failed to create the sqlite layout: %w", err)
+	}
+	return &sqliteStore{db: db.SQL()}, nil
+}
+
+func (q *sqliteStore) Submit(ctx context.Context, gameID, initials string, score int64) error {
+	_, err := q.db.ExecContext(ctx, `INSERT INTO bench_high_scores (game_id, initials, score) VALUES (?, ?, ?)
+		ON CONFLICT (game_id, initials) DO UPDATE SET score = excluded.score WHERE excluded.score > bench_high_scores.score`,
+		gameID, initials, score)
+	return err
+}
+
+// Top orders ties by initials, descending, like a reversed sorted set
+func (q *sqliteStore) Top(ctx context.Context, gameID string, n int) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, `SELECT initials FROM bench_high_scores WHERE game_id = ?
+		ORDER BY score DESC, initials DESC LIMIT ?`, gameID, n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	top := []string{}
+	for rows.Next() {
+		var initials string
+		if err := rows.Scan(&initials); err != nil {
+			return nil, err
+		}
+		top = append(top, initials)
+	}
+	return top, rows.Err()
+}
+
+func (q *sqliteStore) Rank(ctx context.Context, gameID, initials string) (int, error) {
+	var best int64
+	err := q.db.QueryRowContext(ctx, `SELECT score FROM bench_high_scores WHERE game_id = ? AND initials = ?`, gameID, initials).Scan(&best)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("no scores found for player %s", initials)
+	}
+	if err != nil {
+		return 0, err
+	}
+	// Everyone strictly ahead, so ties share the better rank
+	var ahead int
+	if err := q.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM bench_high_scores WHERE game_id = ? AND score > ?`, gameID, best).Scan(&ahead); err != nil {
+		return 0, err
+	}
+	return ahead + 1, nil
+}
@@ -0,0 +1,61 @@
+// Package benchmarks compares ways of storing leaderboards under the same submit, read
+// and rank workloads, so changes to the storage layout can be measured before they ship
+package benchmarks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"rawboard/internal/database"
+)
+
+// Backend names
+const (
+	BackendJSONBlob  = "json-blob"  // The leaderboard service as it stores games today
+	BackendSortedSet = "sorted-set" // Native sorted sets holding each player's high score
+	BackendSQLite    = "sqlite"     // A table of high scores in a SQLite database
+	BackendPostgres  = "postgres"   // A table of high scores in a Postgres database
+)
+
+// Backends lists every backend the harness knows, in report order
+var Backends = []string{BackendJSONBlob, BackendSortedSet, BackendSQLite, BackendPostgres}
+
+// ErrBackendUnavailable is returned for backends that can't be opened against this
+// database
+var ErrBackendUnavailable = errors.New("backend unavailable")
+
+// Store is a leaderboard storage layout under benchmark. Players keep their highest
+// score, and boards rank players by it.
+type Store interface {
+	Submit(ctx context.Context, gameID, initials string, score int64) error
+	// Top returns the initials of the n best players, best first
+	Top(ctx context.Context, gameID string, n int) ([]string, error)
+	// Rank returns a player's 1-based position among every player of the game
+	Rank(ctx context.Context, gameID, initials string) (int, error)
+}
+
+// Open returns the named backend over db. Postgres isn't a rawboard database, so its
+// backend is opened with OpenPostgres instead.
+func Open(name string, db database.DB) (Store, error) {
+	switch name {
+	case BackendJSONBlob:
+		return newBlobStore(db), nil
+	case BackendSortedSet:
+		sets, ok := db.(database.SortedSets)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs a database with sorted sets", ErrBackendUnavailable, name)
+		}
+		return newSortedSetStore(db, sets), nil
+	case BackendSQLite:
+		sqlite, ok := db.(*database.SQLiteDB)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s needs a SQLite database", ErrBackendUnavailable, name)
+		}
+		return newSQLiteStore(sqlite)
+	case BackendPostgres:
+		return nil, fmt.Errorf("%w: %s is opened with OpenPostgres", ErrBackendUnavailable, name)
+	default:
+		return nil, fmt.Errorf("unknown backend %q", name)
+	}
+}
@@ -0,0 +1,193 @@
+package benchmarks
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// Workload names
+const (
+	WorkloadSubmit = "submit" // New scores from random players
+	WorkloadRead   = "read"   // Top 10 of a seeded board
+	WorkloadRank   = "rank"   // Absolute rank of random seeded players
+)
+
+// Workloads lists every workload, in report order
+var Workloads = []string{WorkloadSubmit, WorkloadRead, WorkloadRank}
+
+// TopN is how much of the board read workloads fetch, like an attract-mode display
+const TopN = 10
+
+// Workload is how hard to drive a store
+type Workload struct {
+	Name        string `json:"name"`
+	GameID      string `json:"game_id"`     // The game played, fresh for each run
+	Players     int    `json:"players"`     // Players seeded before reads, and drawn from by submits
+	Ops         int    `json:"ops"`         // Operations timed
+	Concurrency int    `json:"concurrency"` // Goroutines sharing the operations
+	Seed        int64  `json:"seed"`        // Seeds the random players and scores
+}
+
+// Result is how a backend performed under a workload. Latencies are in nanoseconds.
+type Result struct {
+	Backend   string   `json:"backend"`
+	Workload  Workload `json:"workload"`
+	Ops       int      `json:"ops"`
+	Errors    int      `json:"errors"`
+	ElapsedNs int64    `json:"elapsed_ns"`
+	OpsPerSec float64  `json:"ops_per_sec"`
+	P50Ns     int64    `json:"p50_ns"`
+	P95Ns     int64    `json:"p95_ns"`
+	P99Ns     int64    `json:"p99_ns"`
+	MaxNs     int64    `json:"max_ns"`
+}
+
+// Report is a benchmark run, published as JSON so runs can be compared over time
+type Report struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	GoVersion   string    `json:"go_version"`
+	GOOS        string    `json:"goos"`
+	GOARCH      string    `json:"goarch"`
+	Database    string    `json:"database"` // What json-blob and sorted-set ran against, e.g. valkey or fake; sqlite runs on a file of its own
+	Results     []Result  `json:"results"`
+}
+
+// NewReport starts a report for runs against database
+func NewReport(database string) *Report {
+	return &Report{
+		GeneratedAt: time.Now().UTC(),
+		GoVersion:   runtime.Version(),
+		GOOS:        runtime.GOOS,
+		GOARCH:      runtime.GOARCH,
+		Database:    database,
+	}
+}
+
+// Run seeds store as the workload needs, then times its operations
+func Run(ctx context.Context, backend string, store Store, w Workload) (Result, error) {
+	if w.GameID == "" {
+		return Result{}, fmt.Errorf("workload needs a game")
+	}
+	if w.Players < 1 || w.Players > MaxPlayers() || w.Ops < 1 {
+		return Result{}, fmt.Errorf("workload needs 1 to %d players and at least one operation", MaxPlayers())
+	}
+	w.Concurrency = max(w.Concurrency, 1)
+
+	op, err := operation(w)
+	if err != nil {
+		return Result{}, err
+	}
+	if w.Name != WorkloadSubmit {
+		if err := Seed(ctx, store, w.GameID, w.Players, w.Seed); err != nil {
+			return Result{}, fmt.Errorf("failed to seed %s: %w", backend, err)
+		}
+	}
+
+	latencies := make([]time.Duration, w.Ops)
+	failures := make([]bool, w.Ops)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for worker := range w.Concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(w.Seed + int64(worker) + 1))
+			for i := worker; i < w.Ops; i += w.Concurrency {
+				began := time.Now()
+				failures[i] = op(ctx, store, rng) != nil
+				latencies[i] = time.Since(began)
+			}
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	result := Result{Backend: backend, Workload: w, Ops: w.Ops, ElapsedNs: elapsed.Nanoseconds()}
+	for _, failed := range failures {
+		if failed {
+			result.Errors++
+		}
+	}
+	if elapsed > 0 {
+		result.OpsPerSec = float64(w.Ops) / elapsed.Seconds()
+	}
+	slices.Sort(latencies)
+	result.P50Ns = percentile(latencies, 0.50).Nanoseconds()
+	result.P95Ns = percentile(latencies, 0.95).Nanoseconds()
+	result.P99Ns = percentile(latencies, 0.99).Nanoseconds()
+	result.MaxNs = latencies[len(latencies)-1].Nanoseconds()
+	return result, nil
+}
+
+// Seed gives each of players a distinct high score in a game, so every backend ranks
+// them alike
+func Seed(ctx context.Context, store Store, gameID string, players int, seed int64) error {
+	rng := rand.New(rand.NewSource(seed))
+	for i, score := range rng.Perm(players) {
+		if err := store.Submit(ctx, gameID, PlayerInitials(i), int64(score+1)*10); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// playerInitials are the benchmark players' initials: every three letters from AAA
+// on, less those the blocklist refuses
+var playerInitials = sync.OnceValue(func() []string {
+	var initials []string
+	for a := 'A'; a <= 'Z'; a++ {
+		for b := 'A'; b <= 'Z'; b++ {
+			for c := 'A'; c <= 'Z'; c++ {
+				if candidate := string([]rune{a, b, c}); !models.IsStaticallyBlocked(candidate) {
+					initials = append(initials, candidate)
+				}
+			}
+		}
+	}
+	return initials
+})
+
+// MaxPlayers is how many distinct players a workload can have
+func MaxPlayers() int {
+	return len(playerInitials())
+}
+
+// PlayerInitials returns the initials of the nth benchmark player
+func PlayerInitials(n int) string {
+	initials := playerInitials()
+	return initials[n%len(initials)]
+}
+
+// operation returns one step of the named workload
+func operation(w Workload) (func(ctx context.Context, store Store, rng *rand.Rand) error, error) {
+	switch w.Name {
+	case WorkloadSubmit:
+		return func(ctx context.Context, store Store, rng *rand.Rand) error {
+			return store.Submit(ctx, w.GameID, PlayerInitials(rng.Intn(w.Players)), rng.Int63n(1_000_000))
+		}, nil
+	case WorkloadRead:
+		return func(ctx context.Context, store Store, rng *rand.Rand) error {
+			_, err := store.Top(ctx, w.GameID, TopN)
+			return err
+		}, nil
+	case WorkloadRank:
+		return func(ctx context.Context, store Store, rng *rand.Rand) error {
+			_, err := store.Rank(ctx, w.GameID, PlayerInitials(rng.Intn(w.Players)))
+			return err
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown workload %q", w.Name)
+	}
+}
+
+// percentile returns the latency at p of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[min(int(p*float64(len(sorted))), len(sorted)-1)]
+}
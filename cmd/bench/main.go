@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"rawboard/benchmarks"
+	"rawboard/internal/database"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run benchmarks and reports, returning the exit code, so deferred cleanup such as
+// removing a temporary SQLite file happens on failures too
+func run() int {
+	backends := flag.String("backends", strings.Join(benchmarks.Backends, ","), "comma-separated storage backends to compare")
+	workloads := flag.String("workloads", strings.Join(benchmarks.Workloads, ","), "comma-separated workloads to run")
+	players := flag.Int("players", 1000, "players on each board")
+	ops := flag.Int("ops", 2000, "operations timed per workload")
+	concurrency := flag.Int("concurrency", 8, "goroutines sharing the operations")
+	seed := flag.Int64("seed", 1, "seeds the random players and scores, for repeatable runs")
+	fake := flag.Bool("fake", false, "run against the in-memory database instead of Valkey")
+	sqlitePath := flag.String("sqlite", "", "SQLite file the sqlite backend runs on; a temporary one when empty")
+	postgresURL := flag.String("postgres", os.Getenv("BENCH_POSTGRES_URL"), "Postgres connection string the postgres backend runs on; skipped when empty unless listed in -backends")
+	out := flag.String("out", "", "write the JSON report to this file instead of stdout")
+	timeout := flag.Duration("timeout", 30*time.Minute, "maximum time to spend benchmarking")
+	flag.Parse()
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var db database.DB
+	target := "fake"
+	if *fake {
+		db = database.NewFake()
+	} else {
+		valkey, err := database.NewValkeyDB()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ Database initialization failed: %v\n", err)
+			return 1
+		}
+		defer valkey.Close()
+		db, target = valkey, "valkey"
+	}
+
+	// Every run plays a game of its own, so runs don't see each other's scores
+	runID := time.Now().UTC().Format("20060102T150405")
+	report := benchmarks.NewReport(target)
+	for _, backend := range strings.Split(*backends, ",") {
+		var store benchmarks.Store
+		var err error
+		switch backend {
+		case benchmarks.BackendSQLite:
+			sqlite, closeSQLite, openErr := openSQLite(*sqlitePath)
+			if openErr != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", openErr)
+				return 1
+			}
+			defer closeSQLite()
+			store, err = benchmarks.Open(backend, sqlite)
+		case benchmarks.BackendPostgres:
+			// Postgres is opt-in, since most runs have no scratch server at hand
+			if *postgresURL == "" && !explicit["backends"] {
+				fmt.Fprintf(os.Stderr, "⏭️  %s: skipped, no -postgres connection string\n", backend)
+				continue
+			}
+			var closePostgres func()
+			store, closePostgres, err = benchmarks.OpenPostgres(ctx, *postgresURL)
+			if err == nil {
+				defer closePostgres()
+			}
+		default:
+			store, err = benchmarks.Open(backend, db)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		for _, workload := range strings.Split(*workloads, ",") {
+			w := benchmarks.Workload{
+				Name:        workload,
+				GameID:      fmt.Sprintf("bench-%s-%s-%s", runID, backend, workload),
+				Players:     *players,
+				Ops:         *ops,
+				Concurrency: *concurrency,
+				Seed:        *seed,
+			}
+
+			fmt.Fprintf(os.Stderr, "⏱️  %s/%s: %d ops over %d players...\n", backend, workload, *ops, *players)
+			result, err := benchmarks.Run(ctx, backend, store, w)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %s/%s failed: %v\n", backend, workload, err)
+				return 1
+			}
+			fmt.Fprintf(os.Stderr, "   %.0f ops/s, p50 %s, p99 %s, %d errors\n",
+				result.OpsPerSec, time.Duration(result.P50Ns), time.Duration(result.P99Ns), result.Errors)
+			report.Results = append(report.Results, result)
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to encode report: %v\n", err)
+		return 1
+	}
+	if *out == "" {
+		fmt.Println(string(data))
+		return 0
+	}
+	if err := os.WriteFile(*out, append(data, '\n'), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to write report: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "📊 Report written to %s\n", *out)
+	return 0
+}
+
+// openSQLite opens the database the sqlite backend runs on. Without a path it's a
+// temporary file, which closing removes.
+func openSQLite(path string) (*database.SQLiteDB, func(), error) {
+	if path != "" {
+		db, err := database.NewSQLiteDB(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return db, func() { db.Close() }, nil
+	}
+
+	dir, err := os.MkdirTemp("", "rawboard-bench-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create a SQLite file: %w", err)
+	}
+	db, err := database.NewSQLiteDB(filepath.Join(dir, "bench.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, nil, err
+	}
+	return db, func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}, nil
+}
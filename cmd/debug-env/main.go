@@ -15,6 +15,9 @@ func main() {
 		"VALKEY_URL",
 		"REDIS_HOST",
 		"REDIS_PORT",
+		"VALKEY_CLUSTER_ADDRS",
+		"VALKEY_SENTINEL_ADDRS",
+		"VALKEY_SENTINEL_MASTER",
 	}
 
 	found := false
@@ -32,7 +35,7 @@ func main() {
 
 	if !found {
 		fmt.Println("\n⚠️  No database environment variables found!")
-		fmt.Println("Expected one of: VALKEY_URI, REDIS_URL, DATABASE_URL, VALKEY_URL, or REDIS_HOST+REDIS_PORT")
+		fmt.Println("Expected one of: VALKEY_URI, REDIS_URL, DATABASE_URL, VALKEY_URL, REDIS_HOST+REDIS_PORT, VALKEY_CLUSTER_ADDRS or VALKEY_SENTINEL_ADDRS")
 	}
 
 	fmt.Println("\n=== End Debug Info ===")
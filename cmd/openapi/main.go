@@ -0,0 +1,27 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"rawboard/internal/handlers"
+)
+
+func main() {
+	dir := flag.String("handlers", "internal/handlers", "directory of annotated handler sources")
+	output := flag.String("o", "internal/openapi/openapi.json", "file to write the OpenAPI document to")
+	flag.Parse()
+
+	spec, err := handlers.GenerateOpenAPI(*dir)
+	if err != nil {
+		fmt.Printf("❌ Failed to generate OpenAPI document: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(*output, spec, 0o644); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", *output, err)
+		os.Exit(1)
+	}
+	fmt.Printf("📄 Wrote %s\n", *output)
+}
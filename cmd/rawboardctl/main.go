@@ -0,0 +1,176 @@
+// Command rawboardctl applies a YAML manifest of games, API keys and blocked initials
+// to a running rawboard server, showing the plan before changing anything
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"rawboard/internal/handlers"
+	"rawboard/internal/manifest"
+	"rawboard/internal/models"
+)
+
+const usage = `Usage: rawboardctl <command> [flags]
+
+Commands:
+  plan    Show the changes applying the manifest would make
+  apply   Show the plan, then apply it after confirmation
+
+Flags:
+`
+
+func main() {
+	if len(os.Args) < 2 || (os.Args[1] != "plan" && os.Args[1] != "apply") {
+		fmt.Fprint(os.Stderr, usage)
+		newFlags("rawboardctl").PrintDefaults()
+		os.Exit(2)
+	}
+	command := os.Args[1]
+
+	opts := newFlags(command)
+	_ = opts.Parse(os.Args[2:])
+
+	doc, err := readManifest(opts.file)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+	client := &client{server: strings.TrimRight(opts.server, "/"), apiKey: opts.apiKey, http: http.DefaultClient}
+
+	plan, err := client.bootstrap(ctx, doc, true)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("📋 Plan for %s:\n\n", client.server)
+	manifest.WritePlan(os.Stdout, plan)
+	if command == "plan" || len(plan.Changes) == 0 {
+		return
+	}
+
+	if !opts.autoApprove && !confirm(os.Stdin) {
+		fmt.Println("Apply cancelled.")
+		return
+	}
+
+	result, err := client.bootstrap(ctx, doc, false)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("\n✅ Applied %d change(s)\n", len(result.Changes))
+	for _, key := range result.CreatedKeys {
+		fmt.Printf("🔑 %s: %s (shown once, store it now)\n", key.Name, key.Key)
+	}
+}
+
+// options holds a command's flags
+type options struct {
+	*flag.FlagSet
+	file        string
+	server      string
+	apiKey      string
+	autoApprove bool
+	timeout     time.Duration
+}
+
+// newFlags defines the flags shared by every command
+func newFlags(name string) *options {
+	opts := &options{FlagSet: flag.NewFlagSet(name, flag.ExitOnError)}
+	server := os.Getenv("RAWBOARD_URL")
+	if server == "" {
+		server = "http://localhost:8080"
+	}
+	opts.StringVar(&opts.file, "f", "rawboard.yaml", "manifest file, or - for stdin")
+	opts.StringVar(&opts.server, "server", server, "server URL (env RAWBOARD_URL)")
+	opts.StringVar(&opts.apiKey, "api-key", os.Getenv("RAWBOARD_API_KEY"), "master API key (env RAWBOARD_API_KEY)")
+	opts.BoolVar(&opts.autoApprove, "auto-approve", false, "apply without asking for confirmation")
+	opts.DurationVar(&opts.timeout, "timeout", 30*time.Second, "maximum time to wait for the server")
+	return opts
+}
+
+// readManifest loads the manifest at path, or from stdin for "-"
+func readManifest(path string) (*models.BootstrapDocument, error) {
+	if path == "-" {
+		return manifest.Load(os.Stdin)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	doc, err := manifest.Load(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// confirm asks whether to apply the plan
+func confirm(in io.Reader) bool {
+	fmt.Print("\nApply these changes? Only 'yes' will be accepted: ")
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	return strings.TrimSpace(answer) == "yes"
+}
+
+// client calls the admin bootstrap endpoint
+type client struct {
+	server string
+	apiKey string
+	http   *http.Client
+}
+
+// bootstrap submits doc, only computing the plan when dryRun is set
+func (c *client) bootstrap(ctx context.Context, doc *models.BootstrapDocument, dryRun bool) (*models.BootstrapResult, error) {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+
+	endpoint := c.server + "/api/v1/admin/bootstrap?" + url.Values{"dry_run": {fmt.Sprint(dryRun)}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", c.apiKey)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach %s: %w", c.server, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure handlers.StandardErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&failure) != nil || failure.Error.Message == "" {
+			return nil, fmt.Errorf("server returned %s", resp.Status)
+		}
+		if len(failure.Error.Details) > 0 {
+			details, _ := json.Marshal(failure.Error.Details)
+			return nil, fmt.Errorf("%s: %s", failure.Error.Message, details)
+		}
+		return nil, fmt.Errorf("%s", failure.Error.Message)
+	}
+
+	var result models.BootstrapResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"rawboard/internal/config"
+	"rawboard/internal/database"
+	"rawboard/internal/export"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/objectstore"
+)
+
+func main() {
+	exportID := flag.String("export", export.LatestExport, "export run ID to restore from, or \"latest\"")
+	gameID := flag.String("game", "", "restore a single game (all games in the export if empty)")
+	dryRun := flag.Bool("dry-run", false, "verify the export without writing to the database")
+	list := flag.Bool("list", false, "list available export runs and exit")
+	timeout := flag.Duration("timeout", 10*time.Minute, "maximum time to spend restoring")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+
+	if !cfg.HasObjectStore() {
+		fmt.Println("❌ OBJECT_STORE_BUCKET must be set to restore from exports")
+		os.Exit(1)
+	}
+
+	store, err := objectstore.NewS3Store(objectstore.S3Config{
+		Endpoint:        cfg.ObjectStoreEndpoint,
+		Region:          cfg.ObjectStoreRegion,
+		Bucket:          cfg.ObjectStoreBucket,
+		AccessKeyID:     cfg.ObjectStoreAccessKeyID,
+		SecretAccessKey: cfg.ObjectStoreSecretAccessKey,
+		UsePathStyle:    cfg.ObjectStorePathStyle,
+	})
+	if err != nil {
+		fmt.Printf("❌ Object storage configuration invalid: %v\n", err)
+		os.Exit(1)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	// Listing exports doesn't need the database
+	if *list {
+		exporter := export.NewExporter(nil, store, cfg.ExportPrefix)
+		exportIDs, err := exporter.ListExports(ctx)
+		if err != nil {
+			fmt.Printf("❌ %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("📦 %d export(s) in %s/%s\n", len(exportIDs), cfg.ObjectStoreBucket, cfg.ExportPrefix)
+		for _, id := range exportIDs {
+			fmt.Printf("   %s\n", id)
+		}
+		return
+	}
+
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		fmt.Printf("❌ Database initialization failed: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	exporter := export.NewExporter(leaderboard.NewService(db), store, cfg.ExportPrefix)
+
+	target := "all games"
+	if *gameID != "" {
+		target = "game " + *gameID
+	}
+	if *dryRun {
+		fmt.Printf("🔍 Verifying %s from export %s (dry run)...\n", target, *exportID)
+	} else {
+		fmt.Printf("♻️  Restoring %s from export %s...\n", target, *exportID)
+	}
+
+	report, err := exporter.Restore(ctx, *exportID, *gameID, *dryRun)
+	if err != nil {
+		fmt.Printf("❌ Restore failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, game := range report.Games {
+		status := "✅ verified"
+		if game.Restored {
+			status = "✅ restored"
+		}
+		fmt.Printf("%s %s: %d scores, %d players, %d leaderboard entries (sha256 %s)\n",
+			status, game.GameID, game.Scores, game.Players, game.LeaderboardEntries, game.SHA256[:12])
+	}
+	fmt.Printf("🎉 Export %s: %d game(s) processed\n", report.ExportID, len(report.Games))
+}
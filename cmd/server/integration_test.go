@@ -4,15 +4,21 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
 	"rawboard/internal/database"
 	"rawboard/internal/handlers"
 	"rawboard/internal/leaderboard"
 	"rawboard/internal/middleware"
+	"rawboard/internal/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -103,8 +109,620 @@ func TestAPIKeyIntegration(t *testing.T) {
 	})
 }
 
+func TestScopedAPIKeyIntegration(t *testing.T) {
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		t.Skip("Skipping integration tests - database tests disabled")
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		t.Skip("Skipping integration tests - no database available")
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Ping(ctx); err != nil {
+		t.Skip("Skipping integration tests - database connection failed")
+	}
+
+	leaderboardService := leaderboard.NewService(db)
+	keyStore := apikeys.NewStore(db)
+	masterKey := "test-master-key-123"
+	apiKeyMiddleware := middleware.APIKeyAuth(masterKey, keyStore)
+
+	router := gin.New()
+	handlers.SetupRoutes(router, leaderboardService, apiKeyMiddleware)
+	handlers.SetupAdminRoutes(router, leaderboardService, nil, audit.NewLog(db), keyStore, audit.NewUsageTracker(db), nil, apiKeyMiddleware)
+
+	cabinetKey, err := keyStore.Create(ctx, "cabinet", []string{"scoped-pacman"}, []string{models.ScopeSubmit})
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	submit := func(gameID, key string) int {
+		body, _ := json.Marshal(map[string]interface{}{"initials": "KEY", "score": 100})
+		req := httptest.NewRequest("POST", "/api/v1/games/"+gameID+"/scores", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("scoped key can submit to its own game", func(t *testing.T) {
+		if code := submit("scoped-pacman", cabinetKey.Key); code != http.StatusCreated {
+			t.Errorf("Expected 201, got %d", code)
+		}
+	})
+
+	t.Run("scoped key can't submit to other games", func(t *testing.T) {
+		if code := submit("scoped-tetris", cabinetKey.Key); code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", code)
+		}
+	})
+
+	t.Run("scoped key can't use scopes it wasn't granted", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/games/scoped-pacman/scores/all", nil)
+		req.Header.Set("X-API-Key", cabinetKey.Key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), handlers.ErrorCodeInsufficientRole) {
+			t.Errorf("Expected a submitter key to be refused for its role, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("scoped key can't manage keys", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/api/v1/admin/keys", nil)
+		req.Header.Set("X-API-Key", cabinetKey.Key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("revoked key is rejected", func(t *testing.T) {
+		req := httptest.NewRequest("DELETE", "/api/v1/admin/keys/"+cabinetKey.ID, nil)
+		req.Header.Set("X-API-Key", masterKey)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected master key to revoke, got %d: %s", w.Code, w.Body.String())
+		}
+
+		if code := submit("scoped-pacman", cabinetKey.Key); code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 after revocation, got %d", code)
+		}
+	})
+}
+
+func TestAdminSessionIntegration(t *testing.T) {
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		t.Skip("Skipping integration tests - database tests disabled")
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		t.Skip("Skipping integration tests - no database available")
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Ping(ctx); err != nil {
+		t.Skip("Skipping integration tests - database connection failed")
+	}
+
+	leaderboardService := leaderboard.NewService(db)
+	keyStore := apikeys.NewStore(db)
+	auditLog := audit.NewLog(db)
+	masterKey := "test-master-key-123"
+	sessions := apikeys.NewSessions(masterKey, time.Hour)
+	apiKeyMiddleware := middleware.APIKeyAuth(masterKey, keyStore, middleware.WithSessions(sessions))
+
+	router := gin.New()
+	handlers.SetupAdminRoutes(router, leaderboardService, nil, auditLog, keyStore, audit.NewUsageTracker(db), nil, apiKeyMiddleware)
+	handlers.SetupSessionRoutes(router, sessions, auditLog, apiKeyMiddleware)
+
+	call := func(method, path, key string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := call("POST", "/api/v1/admin/session", masterKey, nil)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 signing in, got %d: %s", w.Code, w.Body.String())
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != apikeys.SessionCookie || !cookies[0].HttpOnly || cookies[0].SameSite != http.SameSiteStrictMode {
+		t.Fatalf("Expected an HttpOnly, SameSite=Strict session cookie, got %+v", cookies)
+	}
+	session := cookies[0]
+
+	t.Run("session cookie authenticates admin calls", func(t *testing.T) {
+		if w := call("GET", "/api/v1/admin/keys", "", session); w.Code != http.StatusOK {
+			t.Errorf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		w := call("GET", "/api/v1/admin/session", "", session)
+		var got models.AdminSession
+		json.Unmarshal(w.Body.Bytes(), &got)
+		if w.Code != http.StatusOK || !got.Master || got.ExpiresAt == nil {
+			t.Errorf("Expected the master session with its expiry, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("a session can't start another", func(t *testing.T) {
+		if w := call("POST", "/api/v1/admin/session", "", session); w.Code != http.StatusForbidden {
+			t.Errorf("Expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("signing out clears the cookie", func(t *testing.T) {
+		w := call("DELETE", "/api/v1/admin/session", "", nil)
+		cookies := w.Result().Cookies()
+		if w.Code != http.StatusNoContent || len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+			t.Errorf("Expected 204 expiring the cookie, got %d with %+v", w.Code, cookies)
+		}
+	})
+}
+
+func TestPublicSummaryIntegration(t *testing.T) {
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		t.Skip("Skipping integration tests - database tests disabled")
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		t.Skip("Skipping integration tests - no database available")
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Ping(ctx); err != nil {
+		t.Skip("Skipping integration tests - database connection failed")
+	}
+
+	leaderboardService := leaderboard.NewService(db)
+	router := gin.New()
+	handlers.SetupPublicRoutes(router, leaderboardService, middleware.RateLimitMiddleware(middleware.RateLimitConfig{
+		RequestsPerSecond: 1,
+		BurstSize:         2,
+	}))
+
+	gameID := "public-summary-test"
+	for _, score := range []int64{1200, 4500} {
+		if err := leaderboardService.SubmitScore(ctx, gameID, "PUB", score); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+	}
+
+	t.Run("serves aggregates with CORS and caching headers", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/public/games/"+gameID+"/summary", nil)
+		req.Header.Set("Origin", "https://wiki.example.com")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if w.Header().Get("Access-Control-Allow-Origin") != "*" {
+			t.Error("Expected CORS to allow any origin")
+		}
+		if w.Header().Get("Cache-Control") == "" || w.Header().Get("ETag") == "" {
+			t.Error("Expected Cache-Control and ETag headers")
+		}
+
+		var summary models.GameSummary
+		if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+			t.Fatalf("Failed to decode summary: %v", err)
+		}
+		if summary.TopScore < 4500 || summary.PlayerCount < 1 || summary.LastActivity == nil {
+			t.Errorf("Unexpected summary: %+v", summary)
+		}
+
+		// A matching If-None-Match revalidates without a body
+		req = httptest.NewRequest("GET", "/public/games/"+gameID+"/summary", nil)
+		req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotModified {
+			t.Errorf("Expected 304 for a matching ETag, got %d", w.Code)
+		}
+	})
+
+	t.Run("answers CORS preflight requests", func(t *testing.T) {
+		req := httptest.NewRequest("OPTIONS", "/public/games/"+gameID+"/summary", nil)
+		req.Header.Set("Origin", "https://wiki.example.com")
+		req.Header.Set("Access-Control-Request-Method", "GET")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Methods") == "" {
+			t.Errorf("Expected 204 preflight response, got %d", w.Code)
+		}
+	})
+}
+
+func TestReceiptLookupIntegration(t *testing.T) {
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		t.Skip("Skipping integration tests - database tests disabled")
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		t.Skip("Skipping integration tests - no database available")
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Ping(ctx); err != nil {
+		t.Skip("Skipping integration tests - database connection failed")
+	}
+
+	leaderboardService := leaderboard.NewService(db)
+	router := gin.New()
+	handlers.SetupRoutes(router, leaderboardService, middleware.APIKeyMiddleware(""))
+	handlers.SetupPublicRoutes(router, leaderboardService, middleware.RateLimitMiddleware(middleware.RateLimitConfig{
+		RequestsPerSecond: 0.001,
+		BurstSize:         2,
+	}))
+
+	body, _ := json.Marshal(map[string]interface{}{"initials": "RCT", "score": 700})
+	req := httptest.NewRequest("POST", "/api/v1/games/receipt-test/scores", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var submitted handlers.ScoreSubmissionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &submitted); err != nil || submitted.ReceiptToken == "" {
+		t.Fatalf("Expected a receipt token in the submission response, got %s", w.Body.String())
+	}
+
+	t.Run("looks up the receipted score", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/public/receipts/"+submitted.ReceiptToken, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var status models.ReceiptStatus
+		if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("Expected receipt status, got %d: %s", w.Code, w.Body.String())
+		}
+		if status.Score != 700 || status.Initials != "RCT" || status.Rank < 1 {
+			t.Errorf("Unexpected receipt status: %+v", status)
+		}
+	})
+
+	t.Run("rate limits lookups", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/public/receipts/unknown-token", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404 for an unknown receipt, got %d", w.Code)
+		}
+
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/public/receipts/unknown-token", nil))
+		if w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected 429 once the burst is spent, got %d", w.Code)
+		}
+	})
+}
+
+func TestSharePageIntegration(t *testing.T) {
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		t.Skip("Skipping integration tests - database tests disabled")
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewValkeyDB()
+	if err != nil {
+		t.Skip("Skipping integration tests - no database available")
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	if err := db.Ping(ctx); err != nil {
+		t.Skip("Skipping integration tests - database connection failed")
+	}
+
+	leaderboardService := leaderboard.NewService(db)
+	router := gin.New()
+	handlers.SetupRoutes(router, leaderboardService, middleware.APIKeyMiddleware(""))
+	handlers.SetupPublicRoutes(router, leaderboardService, func(c *gin.Context) { c.Next() })
+
+	body, _ := json.Marshal(map[string]interface{}{"initials": "SHR", "score": 12500})
+	req := httptest.NewRequest("POST", "/api/v1/games/share-test/scores", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var submitted handlers.ScoreSubmissionResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &submitted); err != nil || submitted.ReceiptToken == "" {
+		t.Fatalf("Expected a receipt token in the submission response, got %s", w.Body.String())
+	}
+
+	t.Run("serves a page with preview tags", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/share/"+submitted.ReceiptToken, nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Host = "scores.example.com"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+			t.Fatalf("Expected an HTML page, got %d: %s", w.Code, w.Body.String())
+		}
+		page := w.Body.String()
+		for _, tag := range []string{
+			`<meta property="og:title" content="SHR scored 12,500 in share-test">`,
+			`<meta property="og:image" content="https://scores.example.com/share/` + submitted.ReceiptToken + `/card.png">`,
+			`<meta name="twitter:card" content="summary_large_image">`,
+		} {
+			if !strings.Contains(page, tag) {
+				t.Errorf("Expected %s in the page", tag)
+			}
+		}
+	})
+
+	t.Run("serves the card image", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/share/"+submitted.ReceiptToken+"/card.png", nil))
+		if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || !bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")) {
+			t.Errorf("Expected a PNG, got %d with %s", w.Code, w.Header().Get("Content-Type"))
+		}
+	})
+
+	t.Run("unknown receipts aren't found", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/share/unknown-token", nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d", w.Code)
+		}
+	})
+}
+
 func TestMain(m *testing.M) {
 	// Run tests
 	code := m.Run()
 	os.Exit(code)
 }
+
+func TestRequestValidationIntegration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validateRequests, err := handlers.ValidateRequests()
+	if err != nil {
+		t.Fatalf("ValidateRequests failed: %v", err)
+	}
+	router := gin.New()
+	router.Use(validateRequests)
+	handlers.SetupRoutes(router, leaderboard.NewService(database.NewFake()), middleware.APIKeyMiddleware(""))
+
+	submit := func(gameID string, body map[string]interface{}) *httptest.ResponseRecorder {
+		data, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/api/v1/games/"+gameID+"/scores", bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("valid bodies reach the handler intact", func(t *testing.T) {
+		if w := submit("pacman", map[string]interface{}{"initials": "AAA", "score": 1200}); w.Code != http.StatusCreated {
+			t.Errorf("Expected the score to be submitted, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("documented constraints are enforced", func(t *testing.T) {
+		w := submit("pacman", map[string]interface{}{"initials": "AAA", "score": "high"})
+		var response handlers.StandardErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil || w.Code != http.StatusBadRequest {
+			t.Fatalf("Expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+		if response.Error.Code != handlers.ErrorCodeValidationFailed || response.Error.Details["field"] != "score" {
+			t.Errorf("Expected VALIDATION_FAILED for score, got %+v", response.Error)
+		}
+
+		req := httptest.NewRequest("GET", "/api/v1/games/pacman/leaderboard?limit=ten", nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected a non-integer limit to be refused, got %d", w.Code)
+		}
+	})
+}
+
+func TestConditionalLeaderboardIntegration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	leaderboardService := leaderboard.NewService(database.NewFake())
+	router := gin.New()
+	handlers.SetupRoutes(router, leaderboardService, middleware.APIKeyMiddleware(""))
+	if err := leaderboardService.SubmitScore(context.Background(), "pacman", "AAA", 1200); err != nil {
+		t.Fatalf("SubmitScore failed: %v", err)
+	}
+
+	get := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/games/pacman/leaderboard", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("", "")
+	etag, modified := first.Header().Get("ETag"), first.Header().Get("Last-Modified")
+	if first.Code != http.StatusOK || etag == "" || modified == "" {
+		t.Fatalf("Expected the board with an ETag and Last-Modified, got %d: %v", first.Code, first.Header())
+	}
+	if w := get("If-None-Match", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("Expected 304 for a current ETag, got %d", w.Code)
+	}
+	if w := get("If-Modified-Since", modified); w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304 for a current Last-Modified, got %d", w.Code)
+	}
+
+	if err := leaderboardService.SubmitScore(context.Background(), "pacman", "BBB", 1500); err != nil {
+		t.Fatalf("SubmitScore failed: %v", err)
+	}
+	if w := get("If-None-Match", etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("Expected the changed board with a new ETag, got %d", w.Code)
+	}
+}
+
+func TestCacheControlIntegration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := database.NewFake()
+	leaderboardService := leaderboard.NewService(db)
+	keyStore := apikeys.NewStore(db)
+	apiKeyMiddleware := middleware.APIKeyAuth("test-key", keyStore)
+	router := gin.New()
+	router.Use(middleware.Compression())
+	router.Use(handlers.CacheControl())
+	handlers.SetupRoutes(router, leaderboardService, apiKeyMiddleware)
+	handlers.SetupAdminRoutes(router, leaderboardService, nil, audit.NewLog(db), keyStore, audit.NewUsageTracker(db), nil, apiKeyMiddleware)
+	for i := 0; i < 20; i++ {
+		if err := leaderboardService.SubmitScore(context.Background(), "pacman", fmt.Sprintf("A%02d", i), int64(1000*i)); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+	}
+
+	get := func(path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	board := get("/api/v1/games/pacman/leaderboard", "")
+	if board.Code != http.StatusOK || board.Header().Get("Cache-Control") != "public, max-age=5" {
+		t.Errorf("Expected the board cacheable for a few seconds, got %d: %v", board.Code, board.Header())
+	}
+	if board.Header().Get("ETag") == "" || board.Header().Get("Vary") != "Accept-Encoding" {
+		t.Errorf("Expected the board with an ETag, varying by encoding, got %v", board.Header())
+	}
+	if stats := get("/api/v1/games/pacman/players/A01/stats", ""); stats.Header().Get("Cache-Control") != "public, max-age=5" {
+		t.Errorf("Expected player stats cacheable for a few seconds, got %v", stats.Header())
+	}
+
+	for _, path := range []string{"/api/v1/admin/games", "/api/v1/games/pacman/scores/all"} {
+		if w := get(path, "test-key"); w.Code != http.StatusOK || w.Header().Get("Cache-Control") != "no-store" {
+			t.Errorf("%s: expected no-store, got %d: %v", path, w.Code, w.Header())
+		}
+	}
+	if history := get("/api/v1/games/pacman/scores/all", "test-key"); history.Header().Get("Content-Encoding") != "gzip" {
+		t.Errorf("Expected the score history gzipped, got %v", history.Header())
+	}
+	if w := get("/api/v1/admin/games", ""); w.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Expected rejected admin requests to be no-store too, got %v", w.Header())
+	}
+}
+
+func TestDeleteGameIntegration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := database.NewFake()
+	leaderboardService := leaderboard.NewService(db)
+	keyStore := apikeys.NewStore(db)
+	apiKeyMiddleware := middleware.APIKeyAuth("test-key", keyStore)
+	router := gin.New()
+	handlers.SetupRoutes(router, leaderboardService, apiKeyMiddleware)
+	handlers.SetupAdminRoutes(router, leaderboardService, nil, audit.NewLog(db), keyStore, audit.NewUsageTracker(db), nil, apiKeyMiddleware)
+	leaderboardService.SubmitScore(context.Background(), "pacman", "AAA", 1000)
+
+	deleteGame := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("DELETE", path, nil)
+		req.Header.Set("X-API-Key", "test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := deleteGame("/api/v1/games/pacman")
+	var refused handlers.StandardErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &refused)
+	token, _ := refused.Error.Details["confirm_token"].(string)
+	if w.Code != http.StatusConflict || refused.Error.Code != handlers.ErrorCodeConfirmationRequired || token == "" {
+		t.Fatalf("Expected 409 with a confirm token, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := deleteGame("/api/v1/games/pacman?confirm=stale"); w.Code != http.StatusConflict {
+		t.Errorf("Expected a wrong token refused, got %d", w.Code)
+	}
+
+	w = deleteGame("/api/v1/games/pacman?confirm=" + token)
+	var deletion models.GameDeletion
+	json.Unmarshal(w.Body.Bytes(), &deletion)
+	if w.Code != http.StatusOK || !deletion.Deleted || deletion.Scores != 1 {
+		t.Fatalf("Expected the game deleted, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := deleteGame("/api/v1/games/pacman?confirm=" + token); w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 once deleted, got %d", w.Code)
+	}
+}
+
+func TestFullDataRoutesNeedAdminRoleIntegration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := database.NewFake()
+	leaderboardService := leaderboard.NewService(db)
+	keyStore := apikeys.NewStore(db)
+	// An identity provider can grant admin scopes to a caller without the admin role
+	scopedOnly := func(c *gin.Context) {
+		c.Set(apikeys.PrincipalContextKey, &apikeys.Principal{
+			Name:    "viewer",
+			GameIDs: []string{models.AllGames},
+			Scopes:  []string{models.ScopeAdminRead, models.ScopeAdminWrite},
+			Role:    models.RoleSubmitter,
+			JWT:     true,
+		})
+		c.Next()
+	}
+	router := gin.New()
+	handlers.SetupRoutes(router, leaderboardService, scopedOnly)
+	handlers.SetupAdminRoutes(router, leaderboardService, nil, audit.NewLog(db), keyStore, audit.NewUsageTracker(db), nil, scopedOnly)
+	leaderboardService.SubmitScore(context.Background(), "pacman", "AAA", 1000)
+
+	for _, route := range []struct{ method, path, body string }{
+		{"GET", "/api/v1/games/pacman/leaderboard/export", ""},
+		{"GET", "/api/v1/games/pacman/scores?limit=10", ""},
+		{"POST", "/api/v1/games/pacman/import?format=json", `[{"initials":"BBB","score":1}]`},
+		{"POST", "/api/v1/admin/games/pacman/merge", `{"target":"galaga"}`},
+	} {
+		req := httptest.NewRequest(route.method, route.path, strings.NewReader(route.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), handlers.ErrorCodeInsufficientRole) {
+			t.Errorf("%s %s: expected 403 %s, got %d: %s", route.method, route.path, handlers.ErrorCodeInsufficientRole, w.Code, w.Body.String())
+		}
+	}
+	if history, _ := leaderboardService.GetAllScoresForGame(context.Background(), "pacman"); len(history.Scores) != 1 {
+		t.Errorf("Expected the history untouched, got %+v", history.Scores)
+	}
+}
@@ -0,0 +1,23 @@
+// Package adminui embeds the operator web UI served at /admin. The UI is static and
+// drives the admin API from the browser with the operator's API key or the session
+// started with it, so everything it does is also possible, and audited, through the API.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var static embed.FS
+
+// Files holds the UI's pages, scripts and styles, rooted at index.html
+var Files fs.FS = mustSub(static, "static")
+
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,25 @@
+package adminui
+
+import (
+	"io/fs"
+	"regexp"
+	"testing"
+)
+
+func TestFiles(t *testing.T) {
+	index, err := fs.ReadFile(Files, "index.html")
+	if err != nil {
+		t.Fatalf("Expected index.html at the root: %v", err)
+	}
+
+	// Every local script and stylesheet the page loads must be embedded with it
+	assets := regexp.MustCompile(`(?:src|href)="([^":]+)"`).FindAllSubmatch(index, -1)
+	if len(assets) == 0 {
+		t.Fatal("Expected index.html to load its script and styles")
+	}
+	for _, asset := range assets {
+		if _, err := fs.Stat(Files, string(asset[1])); err != nil {
+			t.Errorf("index.html loads %s, which isn't embedded: %v", asset[1], err)
+		}
+	}
+}
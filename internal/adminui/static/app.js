@@ -0,0 +1,460 @@
+// Rawboard admin UI: a thin client over the admin API. Every value from the server is
+// rendered with textContent, since initials, metadata and names are user-supplied.
+// Signing in trades the API key for an HttpOnly session cookie, or keeps the key in
+// this tab when the operator opts out of a session.
+"use strict";
+
+const keyStorage = "rawboard-admin-key";
+const scoresPageSize = 50;
+
+const state = {
+  games: [],
+  game: null,
+  tab: "board",
+  scoresOffset: 0,
+  scoresHasMore: false,
+  scoreFilter: {},
+};
+
+const $ = (id) => document.getElementById(id);
+
+// api calls the API with the stored key, or the session cookie when there's none,
+// returning the decoded body or throwing an Error carrying the server's message
+async function api(method, path, body, key) {
+  const options = { method, headers: {} };
+  key = key || sessionStorage.getItem(keyStorage);
+  if (key) {
+    options.headers["X-API-Key"] = key;
+  }
+  if (body !== undefined) {
+    options.headers["Content-Type"] = "application/json";
+    options.body = JSON.stringify(body);
+  }
+
+  const response = await fetch(path, options);
+  const data = await response.json().catch(() => null);
+  if (!response.ok) {
+    if (response.status === 401) {
+      sessionStorage.removeItem(keyStorage);
+      showView("login");
+    }
+    const message = data && data.error && data.error.message ? data.error.message : response.statusText;
+    throw new Error(message);
+  }
+  return data;
+}
+
+function gamePath(suffix) {
+  return "/api/v1/games/" + encodeURIComponent(state.game) + suffix;
+}
+
+function showMessage(text, isError) {
+  const box = $("message");
+  box.textContent = text;
+  box.classList.toggle("error", Boolean(isError));
+  box.hidden = false;
+}
+
+function clearMessage() {
+  $("message").hidden = true;
+}
+
+// run reports a failed action instead of leaving it to the console
+async function run(action) {
+  clearMessage();
+  try {
+    await action();
+  } catch (err) {
+    showMessage(err.message, true);
+  }
+}
+
+function cell(row, text, className) {
+  const td = document.createElement("td");
+  td.textContent = text === undefined || text === null ? "" : String(text);
+  if (className) {
+    td.className = className;
+  }
+  row.appendChild(td);
+  return td;
+}
+
+function button(label, onClick, className) {
+  const b = document.createElement("button");
+  b.type = "button";
+  b.textContent = label;
+  if (className) {
+    b.className = className;
+  }
+  b.addEventListener("click", () => run(onClick));
+  return b;
+}
+
+function emptyRow(tbody, columns, text) {
+  const row = document.createElement("tr");
+  const td = cell(row, text, "muted");
+  td.colSpan = columns;
+  tbody.appendChild(row);
+}
+
+function when(timestamp) {
+  return timestamp ? new Date(timestamp).toLocaleString() : "";
+}
+
+function scoreText(entry) {
+  return entry.display_score || entry.score;
+}
+
+function showView(view) {
+  for (const id of ["login", "games", "keys"]) {
+    $(id).hidden = id !== view;
+  }
+  $("nav").hidden = view === "login";
+  for (const b of document.querySelectorAll("#nav [data-view]")) {
+    b.classList.toggle("active", b.dataset.view === view);
+  }
+  if (view === "games") {
+    run(loadGames);
+  } else if (view === "keys") {
+    run(loadKeys);
+  }
+}
+
+// signIn checks the key and, unless the operator chose to keep it in this tab, trades
+// it for a session cookie
+async function signIn(key, useSession) {
+  sessionStorage.removeItem(keyStorage);
+  const session = useSession
+    ? await api("POST", "/api/v1/admin/session", undefined, key)
+    : await api("GET", "/api/v1/admin/session", undefined, key);
+  if (!useSession) {
+    sessionStorage.setItem(keyStorage, key);
+  }
+  showSignedIn(session);
+  showView("games");
+}
+
+async function signOut() {
+  sessionStorage.removeItem(keyStorage);
+  state.game = null;
+  showView("login");
+  await fetch("/api/v1/admin/session", { method: "DELETE" }).catch(() => null);
+}
+
+function showSignedIn(session) {
+  let text = "Signed in as " + session.name;
+  if (session.expires_at) {
+    text += " until " + when(session.expires_at);
+  }
+  $("signed-in").textContent = text;
+}
+
+// resume picks up a session cookie or key from an earlier visit
+async function resume() {
+  try {
+    showSignedIn(await api("GET", "/api/v1/admin/session"));
+    showView("games");
+  } catch (err) {
+    showView("login");
+  }
+}
+
+// Games
+
+async function loadGames() {
+  const data = await api("GET", "/api/v1/admin/games");
+  state.games = data.games || [];
+  renderGames();
+}
+
+function renderGames() {
+  const filter = document.querySelector("#game-search [name=filter]").value.trim().toLowerCase();
+  const list = $("game-list");
+  list.replaceChildren();
+  for (const gameID of state.games) {
+    if (filter && !gameID.toLowerCase().includes(filter)) {
+      continue;
+    }
+    const item = document.createElement("li");
+    const b = button(gameID, () => selectGame(gameID));
+    b.classList.toggle("active", gameID === state.game);
+    item.appendChild(b);
+    list.appendChild(item);
+  }
+  if (!list.children.length) {
+    const item = document.createElement("li");
+    item.className = "muted";
+    item.textContent = state.games.length ? "No matching games" : "No games yet";
+    list.appendChild(item);
+  }
+}
+
+async function selectGame(gameID) {
+  state.game = gameID;
+  state.scoresOffset = 0;
+  state.scoreFilter = {};
+  document.getElementById("score-filter").reset();
+  $("game-title").textContent = gameID;
+  $("game").hidden = false;
+  renderGames();
+  await showTab(state.tab);
+}
+
+async function showTab(tab) {
+  state.tab = tab;
+  for (const name of ["board", "scores", "webhooks"]) {
+    $("tab-" + name).hidden = name !== tab;
+  }
+  for (const b of document.querySelectorAll(".tabs [data-tab]")) {
+    b.classList.toggle("active", b.dataset.tab === tab);
+  }
+  if (tab === "board") {
+    await loadBoard();
+  } else if (tab === "scores") {
+    await loadScores();
+  } else {
+    await loadWebhooks();
+  }
+}
+
+async function loadBoard() {
+  const board = await api("GET", gamePath("/leaderboard"));
+  const tbody = $("board-rows");
+  tbody.replaceChildren();
+  (board.entries || []).forEach((entry, i) => {
+    const row = document.createElement("tr");
+    cell(row, i + 1);
+    cell(row, entry.initials);
+    cell(row, scoreText(entry));
+    cell(row, when(entry.timestamp));
+    const actions = cell(row, "");
+    actions.appendChild(button("Delete player", () => deletePlayer(entry.initials), "danger"));
+    tbody.appendChild(row);
+  });
+  if (!tbody.children.length) {
+    emptyRow(tbody, 5, "No scores on the board");
+  }
+}
+
+async function loadScores() {
+  const params = new URLSearchParams({ limit: scoresPageSize, offset: state.scoresOffset });
+  for (const [name, value] of Object.entries(state.scoreFilter)) {
+    params.set(name, value);
+  }
+  const data = await api("GET", gamePath("/scores?" + params));
+  state.scoresHasMore = data.has_more;
+
+  const tbody = $("score-rows");
+  tbody.replaceChildren();
+  for (const entry of data.scores || []) {
+    const row = document.createElement("tr");
+    cell(row, entry.initials);
+    cell(row, scoreText(entry));
+    cell(row, when(entry.timestamp));
+    const notes = [];
+    if (entry.non_counting) {
+      notes.push("not counted");
+    }
+    if (entry.flags && entry.flags.length) {
+      notes.push("flagged: " + entry.flags.map((f) => f.rule).join(", "));
+    }
+    cell(row, notes.join("; "), "muted");
+    const actions = cell(row, "");
+    actions.appendChild(button("Delete", () => deleteScore(entry), "danger"));
+    tbody.appendChild(row);
+  }
+  if (!tbody.children.length) {
+    emptyRow(tbody, 5, "No matching scores");
+  }
+  $("scores-prev").disabled = state.scoresOffset === 0;
+  $("scores-next").disabled = !state.scoresHasMore;
+}
+
+async function deleteScore(entry) {
+  if (!confirm("Delete " + entry.initials + "'s score of " + scoreText(entry) + "?")) {
+    return;
+  }
+  const params = new URLSearchParams({ initials: entry.initials, timestamp: entry.timestamp });
+  await api("DELETE", gamePath("/scores?" + params));
+  showMessage("Score deleted");
+  await loadScores();
+}
+
+async function deletePlayer(initials) {
+  if (!confirm("Delete every score " + initials + " has in " + state.game + "?")) {
+    return;
+  }
+  const result = await api("DELETE", gamePath("/players/" + encodeURIComponent(initials)));
+  showMessage("Deleted " + result.removed + " scores for " + initials);
+  await loadBoard();
+}
+
+// Webhooks
+
+async function loadWebhooks() {
+  const data = await api("GET", gamePath("/webhooks"));
+  const tbody = $("webhook-rows");
+  tbody.replaceChildren();
+  for (const hook of data.webhooks || []) {
+    const row = document.createElement("tr");
+    cell(row, hook.url);
+    cell(row, (hook.events || []).join(", "));
+    cell(row, hook.condition);
+    const actions = cell(row, "");
+    actions.appendChild(button("Test", () => testWebhook(hook)));
+    actions.appendChild(button("Delete", () => deleteWebhook(hook), "danger"));
+    tbody.appendChild(row);
+  }
+  if (!tbody.children.length) {
+    emptyRow(tbody, 4, "No webhooks");
+  }
+}
+
+async function testWebhook(hook) {
+  const result = await api("POST", gamePath("/webhooks/" + encodeURIComponent(hook.id) + "/test"));
+  if (result.delivered) {
+    showMessage("Test delivered in " + result.duration_ms + " ms");
+  } else {
+    showMessage("Test failed: " + (result.error || "status " + result.status_code), true);
+  }
+}
+
+async function deleteWebhook(hook) {
+  if (!confirm("Delete the webhook to " + hook.url + "?")) {
+    return;
+  }
+  await api("DELETE", gamePath("/webhooks/" + encodeURIComponent(hook.id)));
+  showMessage("Webhook deleted");
+  await loadWebhooks();
+}
+
+async function createWebhook(form) {
+  const body = { url: form.url.value.trim() };
+  const events = splitList(form.events.value);
+  if (events.length) {
+    body.events = events;
+  }
+  if (form.condition.value.trim()) {
+    body.condition = form.condition.value.trim();
+  }
+  const created = await api("POST", gamePath("/webhooks"), body);
+  form.reset();
+  showMessage("Webhook added. Its signing secret, shown only now: " + created.secret);
+  await loadWebhooks();
+}
+
+// API keys
+
+async function loadKeys() {
+  const data = await api("GET", "/api/v1/admin/keys");
+  const tbody = $("key-rows");
+  tbody.replaceChildren();
+  for (const key of data.keys || []) {
+    const row = document.createElement("tr");
+    cell(row, key.name);
+    cell(row, key.prefix);
+    cell(row, (key.game_ids || []).join(", "));
+    cell(row, (key.scopes || []).join(", "));
+    cell(row, key.role);
+    cell(row, when(key.created_at));
+    const actions = cell(row, "");
+    if (key.revoked_at) {
+      actions.textContent = "Revoked " + when(key.revoked_at);
+      actions.className = "muted";
+    } else {
+      actions.appendChild(button("Revoke", () => revokeKey(key), "danger"));
+    }
+    tbody.appendChild(row);
+  }
+  if (!tbody.children.length) {
+    emptyRow(tbody, 7, "No scoped keys");
+  }
+}
+
+async function createKey(form) {
+  const scopes = Array.from(form.querySelectorAll("[name=scope]:checked"), (box) => box.value);
+  const created = await api("POST", "/api/v1/admin/keys", {
+    name: form.elements.namedItem("name").value.trim(),
+    game_ids: splitList(form.games.value),
+    scopes,
+    role: form.role.value,
+  });
+  form.reset();
+  $("secret-value").textContent = created.key;
+  $("secret").hidden = false;
+  await loadKeys();
+}
+
+async function revokeKey(key) {
+  if (!confirm("Revoke " + key.name + "? Devices using it stop working at once.")) {
+    return;
+  }
+  await api("DELETE", "/api/v1/admin/keys/" + encodeURIComponent(key.id));
+  showMessage("Key revoked");
+  await loadKeys();
+}
+
+function splitList(text) {
+  return text.split(",").map((item) => item.trim()).filter(Boolean);
+}
+
+// Wiring
+
+$("login-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  const form = event.target;
+  const key = form.key.value.trim();
+  const useSession = form.session.checked;
+  form.reset();
+  run(() => signIn(key, useSession));
+});
+
+$("sign-out").addEventListener("click", () => run(signOut));
+
+for (const b of document.querySelectorAll("#nav [data-view]")) {
+  b.addEventListener("click", () => {
+    $("secret").hidden = true;
+    showView(b.dataset.view);
+  });
+}
+
+for (const b of document.querySelectorAll(".tabs [data-tab]")) {
+  b.addEventListener("click", () => run(() => showTab(b.dataset.tab)));
+}
+
+$("game-search").addEventListener("input", renderGames);
+$("game-search").addEventListener("submit", (event) => event.preventDefault());
+
+$("score-filter").addEventListener("submit", (event) => {
+  event.preventDefault();
+  state.scoreFilter = {};
+  for (const name of ["min", "max"]) {
+    if (event.target[name].value !== "") {
+      state.scoreFilter[name] = event.target[name].value;
+    }
+  }
+  state.scoresOffset = 0;
+  run(loadScores);
+});
+
+$("scores-prev").addEventListener("click", () => {
+  state.scoresOffset = Math.max(0, state.scoresOffset - scoresPageSize);
+  run(loadScores);
+});
+
+$("scores-next").addEventListener("click", () => {
+  state.scoresOffset += scoresPageSize;
+  run(loadScores);
+});
+
+$("webhook-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  run(() => createWebhook(event.target));
+});
+
+$("key-form").addEventListener("submit", (event) => {
+  event.preventDefault();
+  run(() => createKey(event.target));
+});
+
+resume();
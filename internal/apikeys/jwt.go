@@ -0,0 +1,316 @@
+package apikeys
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+// JWT signing algorithms
+const (
+	JWTAlgorithmHS256 = "HS256"
+	JWTAlgorithmRS256 = "RS256"
+)
+
+// Roles a JWT can grant, mapped onto API key scopes
+const (
+	RoleSubmitter = models.RoleSubmitter // Submit scores
+	RoleAdmin     = models.RoleAdmin     // Everything a scoped API key can do
+)
+
+// Default claims holding a token's games and role
+const (
+	DefaultGamesClaim = "games"
+	DefaultRoleClaim  = "role"
+)
+
+// tenantClaim names the tenant a token acts for, if the deployment has tenants
+const tenantClaim = "tenant"
+
+// jwtLeeway tolerates clock skew between the identity provider and the server
+const jwtLeeway = time.Minute
+
+// MinJWTSecretLength is the shortest HS256 secret accepted, the hash's own size
+const MinJWTSecretLength = 32
+
+// ErrInvalidJWT is returned for malformed, forged, expired or misdirected JWTs
+var ErrInvalidJWT = errors.New("invalid JWT")
+
+// JWTConfig is how bearer JWTs from an identity provider are verified and mapped onto
+// a principal
+type JWTConfig struct {
+	Algorithm string         // HS256 or RS256
+	Secret    []byte         // Verifies HS256 tokens
+	PublicKey *rsa.PublicKey // Verifies RS256 tokens
+	Issuer    string         // Required iss
+	Audience  string         // Required in aud
+
+	// Claims holding the token's games and role, as dotted paths into nested objects,
+	// e.g. realm_access.roles
+	GamesClaim string
+	RoleClaim  string
+}
+
+// JWTVerifier authenticates bearer JWTs in place of stored API keys, so deployments
+// behind an identity provider needn't hand out static keys. Tokens aren't stored: they
+// last until they expire, and are revoked at the provider.
+type JWTVerifier struct {
+	config JWTConfig
+	now    func() time.Time
+}
+
+// NewJWTVerifier checks config and creates a verifier for it
+func NewJWTVerifier(config JWTConfig) (*JWTVerifier, error) {
+	switch config.Algorithm {
+	case JWTAlgorithmHS256:
+		if len(config.Secret) < MinJWTSecretLength {
+			return nil, fmt.Errorf("HS256 secret must be at least %d bytes", MinJWTSecretLength)
+		}
+	case JWTAlgorithmRS256:
+		if config.PublicKey == nil {
+			return nil, fmt.Errorf("RS256 needs a public key")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q, must be %s or %s", config.Algorithm, JWTAlgorithmHS256, JWTAlgorithmRS256)
+	}
+	if config.Issuer == "" || config.Audience == "" {
+		return nil, fmt.Errorf("JWT issuer and audience are required")
+	}
+	if config.GamesClaim == "" {
+		config.GamesClaim = DefaultGamesClaim
+	}
+	if config.RoleClaim == "" {
+		config.RoleClaim = DefaultRoleClaim
+	}
+	return &JWTVerifier{config: config, now: time.Now}, nil
+}
+
+// ParseRSAPublicKey reads an RSA public key from PEM, as a PKIX public key, a PKCS #1
+// public key or a certificate
+func ParseRSAPublicKey(data []byte) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found")
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		key, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		var cert *x509.Certificate
+		if cert, err = x509.ParseCertificate(block.Bytes); err == nil {
+			key = cert.PublicKey
+		}
+	default:
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// LooksLikeJWT reports whether a bearer credential is a JWT rather than an API key
+func LooksLikeJWT(token string) bool {
+	return strings.HasPrefix(token, "eyJ") && strings.Count(token, ".") == 2
+}
+
+// Verify checks a JWT's signature, lifetime, issuer and audience and returns the
+// principal its claims map to: its subject, the games in the games claim ("*" for
+// all) and the scopes of the roles in the role claim
+func (v *JWTVerifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidJWT)
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("%w: malformed header", ErrInvalidJWT)
+	}
+	// Only the configured algorithm is accepted, so a token can't pick a weaker one
+	if header.Alg != v.config.Algorithm {
+		return nil, fmt.Errorf("%w: algorithm %q not accepted", ErrInvalidJWT, header.Alg)
+	}
+	if err := v.verifySignature(parts[0]+"."+parts[1], parts[2]); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]interface{}
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("%w: malformed claims", ErrInvalidJWT)
+	}
+	if err := v.checkRegisteredClaims(claims); err != nil {
+		return nil, err
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidJWT)
+	}
+	scopes := roleScopes(claimStrings(claimAt(claims, v.config.RoleClaim)))
+	if len(scopes) == 0 {
+		return nil, fmt.Errorf("%w: %s grants no role rawboard knows (%s or %s)", ErrInvalidJWT, v.config.RoleClaim, RoleSubmitter, RoleAdmin)
+	}
+	tenant, _ := claims[tenantClaim].(string)
+	if tenant != "" && tenants.Validate(tenant) != nil {
+		return nil, fmt.Errorf("%w: invalid tenant %q", ErrInvalidJWT, tenant)
+	}
+
+	var gameIDs []string
+	for _, gameID := range claimStrings(claimAt(claims, v.config.GamesClaim)) {
+		if gameID == "*" {
+			gameID = models.AllGames
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	return &Principal{
+		KeyID:   "jwt:" + subject,
+		Name:    subject,
+		GameIDs: gameIDs,
+		Scopes:  scopes,
+		Role:    models.RoleForScopes(scopes),
+		Tenant:  tenant,
+		JWT:     true,
+	}, nil
+}
+
+// verifySignature checks a token's signature over its header and claims
+func (v *JWTVerifier) verifySignature(signed, encoded string) error {
+	sig, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("%w: malformed signature", ErrInvalidJWT)
+	}
+	switch v.config.Algorithm {
+	case JWTAlgorithmHS256:
+		mac := hmac.New(sha256.New, v.config.Secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return fmt.Errorf("%w: bad signature", ErrInvalidJWT)
+		}
+	case JWTAlgorithmRS256:
+		digest := sha256.Sum256([]byte(signed))
+		if rsa.VerifyPKCS1v15(v.config.PublicKey, crypto.SHA256, digest[:], sig) != nil {
+			return fmt.Errorf("%w: bad signature", ErrInvalidJWT)
+		}
+	}
+	return nil
+}
+
+// checkRegisteredClaims requires an unexpired token from the configured issuer for the
+// configured audience
+func (v *JWTVerifier) checkRegisteredClaims(claims map[string]interface{}) error {
+	now := v.now()
+
+	exp, ok := claimTime(claims["exp"])
+	if !ok {
+		return fmt.Errorf("%w: missing exp", ErrInvalidJWT)
+	}
+	if !now.Before(exp.Add(jwtLeeway)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidJWT)
+	}
+	if nbf, ok := claimTime(claims["nbf"]); ok && now.Add(jwtLeeway).Before(nbf) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidJWT)
+	}
+
+	if issuer, _ := claims["iss"].(string); issuer != v.config.Issuer {
+		return fmt.Errorf("%w: issuer %q not accepted", ErrInvalidJWT, issuer)
+	}
+	for _, audience := range claimStrings(claims["aud"]) {
+		if audience == v.config.Audience {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: not issued for audience %q", ErrInvalidJWT, v.config.Audience)
+}
+
+// roleScopes returns the scopes roles grant
+func roleScopes(roles []string) []string {
+	var scopes []string
+	for _, role := range roles {
+		switch role {
+		case RoleAdmin:
+			return ValidScopes()
+		case RoleSubmitter:
+			scopes = []string{models.ScopeSubmit}
+		}
+	}
+	return scopes
+}
+
+// decodeSegment decodes a base64url JSON segment of a token, keeping numbers exact
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// claimAt returns the claim at a dotted path into nested objects, or nil
+func claimAt(claims map[string]interface{}, path string) interface{} {
+	var value interface{} = claims
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = object[name]
+	}
+	return value
+}
+
+// claimStrings reads a claim holding one string, a space- or comma-separated list, or an
+// array of strings
+func claimStrings(value interface{}) []string {
+	switch value := value.(type) {
+	case string:
+		return strings.FieldsFunc(value, func(r rune) bool { return r == ' ' || r == ',' })
+	case []interface{}:
+		var values []string
+		for _, item := range value {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// claimTime reads a NumericDate claim
+func claimTime(value interface{}) (time.Time, bool) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	seconds, err := number.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(seconds), 0), true
+}
@@ -0,0 +1,187 @@
+package apikeys
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// testJWT signs claims with alg, using an HMAC secret or an RSA key
+func testJWT(t *testing.T, alg string, key interface{}, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("failed to encode claims: %v", err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var sig []byte
+	switch key := key.(type) {
+	case string:
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		if sig, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]); err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// testClaims returns valid claims for the test issuer and audience, with overrides
+func testClaims(overrides map[string]interface{}) map[string]interface{} {
+	claims := map[string]interface{}{
+		"iss":   "https://id.example.com",
+		"aud":   "rawboard",
+		"sub":   "cabinet-ops",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"role":  RoleSubmitter,
+		"games": []string{"pacman"},
+	}
+	for name, value := range overrides {
+		if value == nil {
+			delete(claims, name)
+		} else {
+			claims[name] = value
+		}
+	}
+	return claims
+}
+
+func newTestJWTVerifier(t *testing.T, config JWTConfig) *JWTVerifier {
+	t.Helper()
+	config.Issuer, config.Audience = "https://id.example.com", "rawboard"
+	verifier, err := NewJWTVerifier(config)
+	if err != nil {
+		t.Fatalf("NewJWTVerifier failed: %v", err)
+	}
+	return verifier
+}
+
+func TestJWTVerifier(t *testing.T) {
+	hs256 := newTestJWTVerifier(t, JWTConfig{Algorithm: JWTAlgorithmHS256, Secret: []byte(testJWTSecret)})
+
+	t.Run("maps claims to a principal", func(t *testing.T) {
+		p, err := hs256.Verify(testJWT(t, "HS256", testJWTSecret, testClaims(nil)))
+		if err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if p.Name != "cabinet-ops" || p.KeyID != "jwt:cabinet-ops" || !p.JWT || p.Master {
+			t.Errorf("Unexpected principal: %+v", p)
+		}
+		if !p.HasScope(models.ScopeSubmit) || p.HasScope(models.ScopeAdminRead) || p.HasRole(RoleAdmin) {
+			t.Errorf("Expected a submitter to only submit, got %v", p.Scopes)
+		}
+		if !p.CanAccessGame("pacman") || p.CanAccessGame("galaga") {
+			t.Errorf("Expected access to pacman only, got %v", p.GameIDs)
+		}
+	})
+
+	t.Run("admins get every scope and * every game", func(t *testing.T) {
+		token := testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{
+			"role": []string{"viewer", RoleAdmin}, "games": "*", "aud": []string{"other", "rawboard"}, "tenant": "acme",
+		}))
+		p, err := hs256.Verify(token)
+		if err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if !slices.Equal(p.Scopes, ValidScopes()) || p.Role != RoleAdmin || !p.CanAccessGame("") || p.Tenant != "acme" {
+			t.Errorf("Unexpected admin principal: %+v", p)
+		}
+	})
+
+	t.Run("reads roles from nested claims", func(t *testing.T) {
+		verifier := newTestJWTVerifier(t, JWTConfig{Algorithm: JWTAlgorithmHS256, Secret: []byte(testJWTSecret), RoleClaim: "realm_access.roles"})
+		token := testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{
+			"role": nil, "realm_access": map[string]interface{}{"roles": []string{RoleAdmin}},
+		}))
+		p, err := verifier.Verify(token)
+		if err != nil || !p.HasScope(models.ScopeAdminWrite) {
+			t.Errorf("Expected the nested role to grant admin, got %+v, %v", p, err)
+		}
+	})
+
+	rejected := []struct {
+		name  string
+		token string
+	}{
+		{"wrong secret", testJWT(t, "HS256", "another-secret-another-secret-00", testClaims(nil))},
+		{"expired", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"exp": time.Now().Add(-time.Hour).Unix()}))},
+		{"no exp", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"exp": nil}))},
+		{"not valid yet", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"nbf": time.Now().Add(time.Hour).Unix()}))},
+		{"other issuer", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"iss": "https://evil.example.com"}))},
+		{"other audience", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"aud": "someone-else"}))},
+		{"no subject", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"sub": nil}))},
+		{"unknown role", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"role": "viewer"}))},
+		{"invalid tenant", testJWT(t, "HS256", testJWTSecret, testClaims(map[string]interface{}{"tenant": "Not A Tenant"}))},
+		{"alg none", strings.TrimSuffix(testJWT(t, "none", "", testClaims(nil)), ".") + "."},
+		{"not a JWT", "rbk_not-a-jwt"},
+	}
+	for _, tt := range rejected {
+		t.Run("rejects "+tt.name, func(t *testing.T) {
+			if _, err := hs256.Verify(tt.token); !errors.Is(err, ErrInvalidJWT) {
+				t.Errorf("Expected ErrInvalidJWT, got %v", err)
+			}
+		})
+	}
+}
+
+func TestJWTVerifierRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("GenerateKey failed: %v", err)
+	}
+	der, _ := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	publicKey, err := ParseRSAPublicKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParseRSAPublicKey failed: %v", err)
+	}
+	verifier := newTestJWTVerifier(t, JWTConfig{Algorithm: JWTAlgorithmRS256, PublicKey: publicKey})
+
+	if _, err := verifier.Verify(testJWT(t, "RS256", key, testClaims(nil))); err != nil {
+		t.Errorf("Expected an RS256 token to verify, got %v", err)
+	}
+
+	other, _ := rsa.GenerateKey(rand.Reader, 2048)
+	if _, err := verifier.Verify(testJWT(t, "RS256", other, testClaims(nil))); !errors.Is(err, ErrInvalidJWT) {
+		t.Errorf("Expected a token signed by another key to be rejected, got %v", err)
+	}
+
+	// An HS256 token keyed with the public key must not pass as RS256
+	confused := testJWT(t, "HS256", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})), testClaims(nil))
+	if _, err := verifier.Verify(confused); !errors.Is(err, ErrInvalidJWT) {
+		t.Errorf("Expected algorithm confusion to be rejected, got %v", err)
+	}
+}
+
+func TestNewJWTVerifier(t *testing.T) {
+	invalid := []JWTConfig{
+		{Algorithm: JWTAlgorithmHS256, Secret: []byte("short"), Issuer: "iss", Audience: "aud"},
+		{Algorithm: JWTAlgorithmRS256, Issuer: "iss", Audience: "aud"},
+		{Algorithm: "none", Issuer: "iss", Audience: "aud"},
+		{Algorithm: JWTAlgorithmHS256, Secret: []byte(testJWTSecret)},
+	}
+	for _, config := range invalid {
+		if _, err := NewJWTVerifier(config); err == nil {
+			t.Errorf("Expected %+v to be rejected", config)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package apikeys
+
+import (
+	"context"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// PrincipalContextKey is the gin context key holding the authenticated Principal
+const PrincipalContextKey = "apikeys.principal"
+
+// Principal is the caller an API key resolved to
+type Principal struct {
+	KeyID   string   `json:"key_id,omitempty"`
+	Name    string   `json:"name"`
+	GameIDs []string `json:"game_ids"`
+	Scopes  []string `json:"scopes"`
+	Role    string   `json:"role"`             // submitter or admin
+	Master  bool     `json:"master"`           // The RAWBOARD_API_KEY, which may do anything
+	Tenant  string   `json:"tenant,omitempty"` // The key's tenant; the master key may act for any
+	Device  string   `json:"device,omitempty"` // The enrolled device holding the key, which its submissions are attributed to
+
+	MaxGames  *int              `json:"-"` // The key's own game limit, if it overrides the default
+	RateLimit *models.RateLimit `json:"-"` // The key's own rate limit, if it overrides the deployment's
+	Session   *time.Time        `json:"-"` // When the admin session the caller signed in with expires, nil for an API key
+	JWT       bool              `json:"-"` // Authenticated with an identity provider's JWT rather than a stored key
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the authenticated caller, for services
+// that need to know who they're acting for
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the caller ctx carries, or nil when the call wasn't
+// authenticated, such as with authentication disabled or from background jobs
+func PrincipalFromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// MasterPrincipal is the principal for the deployment-wide RAWBOARD_API_KEY
+func MasterPrincipal() *Principal {
+	return &Principal{
+		Name:    "master",
+		GameIDs: []string{models.AllGames},
+		Scopes:  ValidScopes(),
+		Role:    models.RoleAdmin,
+		Master:  true,
+	}
+}
+
+// PrincipalFor returns the principal for a stored key
+func PrincipalFor(key *models.APIKey) *Principal {
+	return &Principal{
+		KeyID:   key.ID,
+		Name:    key.Name,
+		GameIDs: key.GameIDs,
+		Scopes:  key.Scopes,
+		Role:    key.EffectiveRole(),
+		Tenant:  key.Tenant,
+		Device:  key.DeviceID,
+
+		MaxGames:  key.MaxGames,
+		RateLimit: key.RateLimit,
+	}
+}
+
+// HasScope reports whether the principal may perform scope's actions
+func (p *Principal) HasScope(scope string) bool {
+	if p.Master {
+		return true
+	}
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the principal holds role; admins hold every role
+func (p *Principal) HasRole(role string) bool {
+	return p.Master || p.Role == role || p.Role == models.RoleAdmin
+}
+
+// CanAccessGame reports whether the principal may act on gameID
+// An empty gameID means a cross-game operation, which needs access to every game
+func (p *Principal) CanAccessGame(gameID string) bool {
+	if p.Master {
+		return true
+	}
+	for _, id := range p.GameIDs {
+		if id == models.AllGames || (gameID != "" && id == gameID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidScopes returns every scope a key can be granted
+func ValidScopes() []string {
+	return []string{models.ScopeSubmit, models.ScopeAdminRead, models.ScopeAdminWrite, models.ScopeEdge}
+}
+
+// IsValidScope reports whether scope is a known scope
+func IsValidScope(scope string) bool {
+	for _, s := range ValidScopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRole reports whether role is a known role
+func IsValidRole(role string) bool {
+	return role == models.RoleSubmitter || role == models.RoleAdmin
+}
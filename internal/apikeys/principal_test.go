@@ -0,0 +1,79 @@
+package apikeys
+
+import (
+	"context"
+	"testing"
+
+	"rawboard/internal/models"
+)
+
+func TestPrincipal(t *testing.T) {
+	t.Run("scoped keys only act on their games and scopes", func(t *testing.T) {
+		p := PrincipalFor(&models.APIKey{
+			ID:      "key-1",
+			GameIDs: []string{"pacman"},
+			Scopes:  []string{models.ScopeSubmit},
+		})
+
+		if !p.HasScope(models.ScopeSubmit) || p.HasScope(models.ScopeAdminRead) {
+			t.Errorf("Unexpected scopes for %+v", p)
+		}
+		if !p.CanAccessGame("pacman") || p.CanAccessGame("tetris") {
+			t.Errorf("Unexpected game access for %+v", p)
+		}
+		if p.CanAccessGame("") {
+			t.Error("Expected a single-game key to be denied cross-game operations")
+		}
+	})
+
+	t.Run("wildcard keys act on every game", func(t *testing.T) {
+		p := PrincipalFor(&models.APIKey{GameIDs: []string{models.AllGames}, Scopes: []string{models.ScopeAdminRead}})
+
+		if !p.CanAccessGame("tetris") || !p.CanAccessGame("") {
+			t.Errorf("Expected wildcard key to access every game: %+v", p)
+		}
+	})
+
+	t.Run("the master key may do anything", func(t *testing.T) {
+		p := MasterPrincipal()
+
+		for _, scope := range ValidScopes() {
+			if !p.HasScope(scope) {
+				t.Errorf("Expected master key to have scope %s", scope)
+			}
+		}
+		if !p.CanAccessGame("anything") {
+			t.Error("Expected master key to access every game")
+		}
+	})
+
+	t.Run("roles follow the key's role or scopes", func(t *testing.T) {
+		cabinet := PrincipalFor(&models.APIKey{Scopes: []string{models.ScopeSubmit}, Role: models.RoleSubmitter})
+		if !cabinet.HasRole(models.RoleSubmitter) || cabinet.HasRole(models.RoleAdmin) {
+			t.Errorf("Expected a submitter key to only be a submitter: %+v", cabinet)
+		}
+
+		// Keys stored before roles take theirs from their scopes
+		legacy := PrincipalFor(&models.APIKey{Scopes: []string{models.ScopeSubmit, models.ScopeAdminRead}})
+		if legacy.Role != models.RoleAdmin || !legacy.HasRole(models.RoleSubmitter) {
+			t.Errorf("Expected a key with an admin scope to be an admin, got %q", legacy.Role)
+		}
+
+		if !MasterPrincipal().HasRole(models.RoleAdmin) {
+			t.Error("Expected the master key to be an admin")
+		}
+	})
+
+	t.Run("travels in a request's context", func(t *testing.T) {
+		limit := 5
+		p := PrincipalFor(&models.APIKey{ID: "key-1", MaxGames: &limit})
+
+		ctx := WithPrincipal(context.Background(), p)
+		if got := PrincipalFromContext(ctx); got != p || *got.MaxGames != 5 {
+			t.Errorf("Expected the key's principal and game limit back, got %+v", got)
+		}
+		if got := PrincipalFromContext(context.Background()); got != nil {
+			t.Errorf("Expected no principal in an unauthenticated context, got %+v", got)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package apikeys
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// SessionCookie is the cookie the admin UI's session is kept in
+const SessionCookie = "rawboard_session"
+
+// sessionTokenPrefix marks admin session tokens so they aren't mistaken for API keys
+const sessionTokenPrefix = "rba_"
+
+// ErrInvalidSession is returned for malformed, forged or expired admin sessions
+var ErrInvalidSession = errors.New("invalid or expired admin session")
+
+// Sessions mints and verifies admin UI sessions, which stand in for the API key they
+// were signed in with. Like stream tokens they're signed rather than stored; the key
+// is looked up again on every request, so revoking it ends its sessions too.
+type Sessions struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewSessions creates a session issuer whose sessions last ttl. The signing key is
+// derived from the master key, so rotating it ends every session.
+func NewSessions(masterKey string, ttl time.Duration) *Sessions {
+	return &Sessions{secret: deriveSecret(masterKey, "rawboard admin sessions"), ttl: ttl, now: time.Now}
+}
+
+// Issue signs p in, returning the session token and when it expires. p may be nil
+// when authentication is disabled.
+func (s *Sessions) Issue(p *Principal) (string, time.Time, error) {
+	expiresAt := s.now().Add(s.ttl).UTC().Truncate(time.Second)
+	claims := models.AdminSessionClaims{ExpiresAt: expiresAt.Unix()}
+	if p != nil {
+		claims.KeyID, claims.Tenant = p.KeyID, p.Tenant
+	}
+
+	token, err := signToken(s.secret, sessionTokenPrefix, claims)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to encode admin session: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// Verify checks a session token's signature and expiry and returns its claims
+func (s *Sessions) Verify(token string) (*models.AdminSessionClaims, error) {
+	var claims models.AdminSessionClaims
+	if !verifyToken(s.secret, sessionTokenPrefix, token, &claims) {
+		return nil, ErrInvalidSession
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidSession
+	}
+	return &claims, nil
+}
@@ -0,0 +1,54 @@
+package apikeys
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	t.Run("verifies sessions it issued", func(t *testing.T) {
+		sessions := NewSessions("master", time.Hour)
+		token, expiresAt, err := sessions.Issue(&Principal{KeyID: "key-1", Tenant: "acme"})
+		if err != nil {
+			t.Fatalf("Issue failed: %v", err)
+		}
+
+		claims, err := sessions.Verify(token)
+		if err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if claims.KeyID != "key-1" || claims.Tenant != "acme" || claims.ExpiresAt != expiresAt.Unix() {
+			t.Errorf("Unexpected claims: %+v", claims)
+		}
+	})
+
+	t.Run("rejects expired sessions", func(t *testing.T) {
+		now := time.Now()
+		sessions := NewSessions("master", time.Hour)
+		sessions.now = func() time.Time { return now }
+		token, _, _ := sessions.Issue(nil)
+
+		now = now.Add(2 * time.Hour)
+		if _, err := sessions.Verify(token); !errors.Is(err, ErrInvalidSession) {
+			t.Errorf("Expected an expired session to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("rejects stream tokens and other masters' sessions", func(t *testing.T) {
+		sessions := NewSessions("master", time.Hour)
+		rotated, _, _ := NewSessions("rotated", time.Hour).Issue(nil)
+		stream := mustIssue(t, NewStreamTokens("master", time.Hour))
+
+		for name, candidate := range map[string]string{
+			"other master": rotated,
+			"stream token": stream,
+			"api key":      "rbk_not-a-session",
+			"empty":        "",
+		} {
+			if _, err := sessions.Verify(candidate); !errors.Is(err, ErrInvalidSession) {
+				t.Errorf("%s: expected rejection, got %v", name, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,359 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// indexKey is the database key holding the key ID to hash index
+	indexKey = "api_keys"
+	// secretPrefix marks rawboard API keys so leaked keys are easy to recognize
+	secretPrefix = "rbk_"
+	// displayPrefixLength is how much of the secret is kept for identification
+	displayPrefixLength = 12
+)
+
+// ErrNotFound is returned when an API key doesn't exist
+var ErrNotFound = errors.New("api key not found")
+
+// ErrSecretInUse is returned when importing a secret that already belongs to a key
+var ErrSecretInUse = errors.New("api key secret already in use")
+
+// ErrInvalidSecret is returned when importing a secret that isn't a rawboard key
+var ErrInvalidSecret = errors.New("api key secret must start with rbk_ and be at least 36 characters")
+
+// ErrSubmitterScopes is returned when a key with the submitter role is given admin scopes
+var ErrSubmitterScopes = errors.New("submitter keys may only have the submit scope")
+
+// Store manages per-game API keys in the database. Keys belong to the tenant of the
+// context they're created in, and only that tenant's keys can be read or changed;
+// Resolve alone looks across tenants, since it runs before the tenant is known.
+type Store struct {
+	db database.DB
+	mu sync.Mutex
+}
+
+// NewStore creates a new API key store
+func NewStore(db database.DB) *Store {
+	return &Store{db: db}
+}
+
+// Create generates and stores a new key, returning its secret once. Its role follows
+// from its scopes.
+func (s *Store) Create(ctx context.Context, name string, gameIDs, scopes []string) (*models.CreatedAPIKey, error) {
+	return s.CreateWithRole(ctx, name, models.RoleForScopes(scopes), gameIDs, scopes)
+}
+
+// CreateWithRole generates and stores a new key with an explicit role, returning its
+// secret once. Submitter keys can't hold admin scopes.
+func (s *Store) CreateWithRole(ctx context.Context, name, role string, gameIDs, scopes []string) (*models.CreatedAPIKey, error) {
+	if role == models.RoleSubmitter && models.RoleForScopes(scopes) != models.RoleSubmitter {
+		return nil, ErrSubmitterScopes
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(ctx, secret, models.APIKey{Name: name, GameIDs: gameIDs, Scopes: scopes, Role: role})
+}
+
+// CreateForDevice issues an enrolled device its key, named after it and limited to
+// submitting scores for gameIDs
+func (s *Store) CreateForDevice(ctx context.Context, deviceID string, gameIDs []string) (*models.CreatedAPIKey, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(ctx, secret, models.APIKey{
+		Name:     "device:" + deviceID,
+		GameIDs:  gameIDs,
+		Scopes:   []string{models.ScopeSubmit},
+		Role:     models.RoleSubmitter,
+		DeviceID: deviceID,
+	})
+}
+
+// Import stores a new key with a secret chosen by the caller, such as one generated
+// by an infrastructure-as-code tool
+func (s *Store) Import(ctx context.Context, name, secret string, gameIDs, scopes []string) (*models.CreatedAPIKey, error) {
+	if !ValidSecret(secret) {
+		return nil, ErrInvalidSecret
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.load(ctx, hashSecret(secret)); err == nil {
+		return nil, ErrSecretInUse
+	}
+	return s.create(ctx, secret, models.APIKey{Name: name, GameIDs: gameIDs, Scopes: scopes, Role: models.RoleForScopes(scopes)})
+}
+
+// create stores key, with its name, games, scopes and any device filled in, for
+// secret; s.mu must be held
+func (s *Store) create(ctx context.Context, secret string, key models.APIKey) (*models.CreatedAPIKey, error) {
+	key.ID = uuid.New().String()
+	key.Prefix = secret[:displayPrefixLength]
+	key.Tenant = tenants.FromContext(ctx)
+	key.CreatedAt = time.Now().UTC()
+	hash := hashSecret(secret)
+
+	if err := s.save(ctx, hash, &key); err != nil {
+		return nil, err
+	}
+
+	index := s.getIndex(ctx)
+	index.Keys[key.ID] = hash
+	index.Updated = key.CreatedAt
+	if err := saveJSON(ctx, s.db, indexKey, index); err != nil {
+		return nil, fmt.Errorf("failed to save api key index: %w", err)
+	}
+
+	return &models.CreatedAPIKey{APIKey: key, Key: secret}, nil
+}
+
+// Update changes an active key's games and scopes, keeping its secret. Its role follows
+// the new scopes.
+func (s *Store) Update(ctx context.Context, id string, gameIDs, scopes []string) (*models.APIKey, error) {
+	return s.modify(ctx, id, func(key *models.APIKey) {
+		key.GameIDs = gameIDs
+		key.Scopes = scopes
+		key.Role = models.RoleForScopes(scopes)
+	})
+}
+
+// SetMaxGames overrides how many games an active key may create; nil restores the
+// deployment default and 0 makes it unlimited
+func (s *Store) SetMaxGames(ctx context.Context, id string, maxGames *int) (*models.APIKey, error) {
+	return s.modify(ctx, id, func(key *models.APIKey) {
+		key.MaxGames = maxGames
+	})
+}
+
+// SetRateLimit overrides an active key's rate limit; nil restores the deployment's
+// limits and a rate of 0 exempts the key
+func (s *Store) SetRateLimit(ctx context.Context, id string, limit *models.RateLimit) (*models.APIKey, error) {
+	return s.modify(ctx, id, func(key *models.APIKey) {
+		key.RateLimit = limit
+	})
+}
+
+// modify applies change to an active key of ctx's tenant and saves it
+func (s *Store) modify(ctx context.Context, id string, change func(key *models.APIKey)) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, ok := s.getIndex(ctx).Keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	key, err := s.load(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if key.Revoked() || !inTenant(ctx, key) {
+		return nil, ErrNotFound
+	}
+
+	change(key)
+	if err := s.save(ctx, hash, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// HasSecret reports whether secret belongs to the key with id
+func (s *Store) HasSecret(ctx context.Context, id, secret string) bool {
+	hash, ok := s.getIndex(ctx).Keys[id]
+	return ok && hash == hashSecret(secret)
+}
+
+// Resolve returns the active key matching secret
+func (s *Store) Resolve(ctx context.Context, secret string) (*models.APIKey, error) {
+	if !strings.HasPrefix(secret, secretPrefix) {
+		return nil, ErrNotFound
+	}
+
+	key, err := s.load(ctx, hashSecret(secret))
+	if err != nil {
+		return nil, err
+	}
+	if key.Revoked() {
+		return nil, ErrNotFound
+	}
+
+	return key, nil
+}
+
+// Get returns a key by ID, including revoked keys
+func (s *Store) Get(ctx context.Context, id string) (*models.APIKey, error) {
+	hash, ok := s.getIndex(ctx).Keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	key, err := s.load(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !inTenant(ctx, key) {
+		return nil, ErrNotFound
+	}
+	return key, nil
+}
+
+// List returns every key in the tenant, including revoked keys, oldest first
+func (s *Store) List(ctx context.Context) ([]models.APIKey, error) {
+	index := s.getIndex(ctx)
+
+	keys := make([]models.APIKey, 0, len(index.Keys))
+	for _, hash := range index.Keys {
+		key, err := s.load(ctx, hash)
+		if err != nil {
+			continue // Index entry without a record
+		}
+		if inTenant(ctx, key) {
+			keys = append(keys, *key)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].CreatedAt.Before(keys[j].CreatedAt)
+	})
+	return keys, nil
+}
+
+// Revoke disables a key; revoked keys stay listed for auditing
+func (s *Store) Revoke(ctx context.Context, id string) (*models.APIKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, ok := s.getIndex(ctx).Keys[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	key, err := s.load(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !inTenant(ctx, key) {
+		return nil, ErrNotFound
+	}
+	if key.Revoked() {
+		return key, nil
+	}
+
+	now := time.Now().UTC()
+	key.RevokedAt = &now
+	if err := s.save(ctx, hash, key); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// inTenant reports whether key belongs to the tenant ctx acts for
+func inTenant(ctx context.Context, key *models.APIKey) bool {
+	return key.Tenant == tenants.FromContext(ctx)
+}
+
+// load reads the key record stored under hash
+func (s *Store) load(ctx context.Context, hash string) (*models.APIKey, error) {
+	data, err := s.db.Get(ctx, recordKey(hash))
+	if err != nil {
+		return nil, ErrNotFound
+	}
+
+	var key models.APIKey
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal api key: %w", err)
+	}
+	key.Role = key.EffectiveRole()
+
+	return &key, nil
+}
+
+// save writes a key record under hash
+func (s *Store) save(ctx context.Context, hash string, key *models.APIKey) error {
+	if err := saveJSON(ctx, s.db, recordKey(hash), key); err != nil {
+		return fmt.Errorf("failed to save api key: %w", err)
+	}
+	return nil
+}
+
+// getIndex reads the key index, returning an empty index if none is stored
+func (s *Store) getIndex(ctx context.Context) *models.APIKeyIndex {
+	index := &models.APIKeyIndex{Keys: map[string]string{}}
+
+	data, err := s.db.Get(ctx, indexKey)
+	if err != nil {
+		return index
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(index); err != nil || index.Keys == nil {
+		return &models.APIKeyIndex{Keys: map[string]string{}}
+	}
+
+	return index
+}
+
+// ValidSecret reports whether secret has the shape of a rawboard API key
+func ValidSecret(secret string) bool {
+	return strings.HasPrefix(secret, secretPrefix) && len(secret) >= 36
+}
+
+// recordKey returns the database key holding the record for a secret's hash
+func recordKey(hash string) string {
+	return fmt.Sprintf("api_key:%s", hash)
+}
+
+// generateSecret creates a new random API key secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
+
+// hashSecret returns the hex SHA-256 of a secret; keys are high-entropy so no salt is needed
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// saveJSON encodes a value as JSON and stores it under key
+func saveJSON(ctx context.Context, db database.DB, key string, value interface{}) error {
+	var buf strings.Builder
+	if err := json.NewEncoder(&buf).Encode(value); err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+
+	return db.Set(ctx, key, strings.TrimSuffix(buf.String(), "\n"))
+}
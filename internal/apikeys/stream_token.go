@@ -0,0 +1,58 @@
+package apikeys
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// streamTokenPrefix marks stream tokens so they aren't mistaken for API keys
+const streamTokenPrefix = "rbs_"
+
+// ErrInvalidStreamToken is returned for malformed, forged or expired stream tokens
+var ErrInvalidStreamToken = errors.New("invalid or expired stream token")
+
+// StreamTokens mints and verifies stream tokens. Tokens are signed rather than stored,
+// so any instance sharing the master key can verify them and nothing needs cleaning up.
+type StreamTokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewStreamTokens creates a stream token issuer whose tokens are valid for ttl.
+// The signing key is derived from the master key, so rotating it revokes every token.
+func NewStreamTokens(masterKey string, ttl time.Duration) *StreamTokens {
+	return &StreamTokens{secret: deriveSecret(masterKey, "rawboard stream tokens"), ttl: ttl, now: time.Now}
+}
+
+// Issue mints a token for the tenant's gameID on behalf of p, which may be nil when
+// authentication is disabled
+func (t *StreamTokens) Issue(p *Principal, tenant, gameID string) (*models.StreamToken, error) {
+	expiresAt := t.now().Add(t.ttl).UTC().Truncate(time.Second)
+	claims := models.StreamTokenClaims{GameID: gameID, Tenant: tenant, ExpiresAt: expiresAt.Unix()}
+	if p != nil {
+		claims.KeyID = p.KeyID
+	}
+
+	token, err := signToken(t.secret, streamTokenPrefix, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode stream token: %w", err)
+	}
+
+	return &models.StreamToken{Token: token, GameID: gameID, ExpiresAt: expiresAt}, nil
+}
+
+// Verify checks a token's signature and expiry and returns its claims
+func (t *StreamTokens) Verify(token string) (*models.StreamTokenClaims, error) {
+	var claims models.StreamTokenClaims
+	if !verifyToken(t.secret, streamTokenPrefix, token, &claims) {
+		return nil, ErrInvalidStreamToken
+	}
+	if t.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidStreamToken
+	}
+	return &claims, nil
+}
@@ -0,0 +1,71 @@
+package apikeys
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamTokens(t *testing.T) {
+	t.Run("verifies tokens it minted", func(t *testing.T) {
+		tokens := NewStreamTokens("master", time.Minute)
+		token, err := tokens.Issue(&Principal{KeyID: "key-1"}, "", "pacman")
+		if err != nil {
+			t.Fatalf("Issue failed: %v", err)
+		}
+		if !strings.HasPrefix(token.Token, streamTokenPrefix) || token.GameID != "pacman" {
+			t.Errorf("Unexpected token: %+v", token)
+		}
+
+		claims, err := tokens.Verify(token.Token)
+		if err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if claims.GameID != "pacman" || claims.KeyID != "key-1" {
+			t.Errorf("Unexpected claims: %+v", claims)
+		}
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		now := time.Now()
+		tokens := NewStreamTokens("master", time.Minute)
+		tokens.now = func() time.Time { return now }
+		token, _ := tokens.Issue(nil, "", "pacman")
+
+		now = now.Add(2 * time.Minute)
+		if _, err := tokens.Verify(token.Token); !errors.Is(err, ErrInvalidStreamToken) {
+			t.Errorf("Expected an expired token to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("rejects tampered and foreign tokens", func(t *testing.T) {
+		tokens := NewStreamTokens("master", time.Minute)
+		token, _ := tokens.Issue(nil, "", "pacman")
+
+		other, _ := tokens.Issue(nil, "", "tetris")
+		payload, _, _ := strings.Cut(other.Token, ".")
+		_, signature, _ := strings.Cut(token.Token, ".")
+
+		for name, candidate := range map[string]string{
+			"swapped payload": payload + "." + signature,
+			"other master":    mustIssue(t, NewStreamTokens("rotated", time.Minute)),
+			"api key":         "rbk_not-a-stream-token",
+			"empty":           "",
+		} {
+			if _, err := tokens.Verify(candidate); !errors.Is(err, ErrInvalidStreamToken) {
+				t.Errorf("%s: expected rejection, got %v", name, err)
+			}
+		}
+	})
+}
+
+// mustIssue mints a pacman token, failing the test on error
+func mustIssue(t *testing.T, tokens *StreamTokens) string {
+	t.Helper()
+	token, err := tokens.Issue(nil, "", "pacman")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	return token.Token
+}
@@ -0,0 +1,310 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// logKey is the database key holding the audit log on databases without streams, and
+	// the entries recorded before the log moved to a stream
+	logKey = "audit_log"
+	// maxEntries bounds the stored audit log; the oldest entries are dropped first
+	maxEntries = 10000
+
+	// streamKey is the database key of the append-only audit stream
+	streamKey = "audit_stream"
+	// maxStreamEntries bounds the audit stream, which also holds every authenticated
+	// write, score submissions included, so it keeps more than the JSON log
+	maxStreamEntries = 100000
+	// queryPageSize is how many stream entries a query reads at a time
+	queryPageSize = 500
+)
+
+// AdminActor names the master key in audit entries
+const AdminActor = "admin:api"
+
+// Audit actions
+const (
+	ActionRetentionPrune          = "retention.prune"
+	ActionRetentionPolicyUpdated  = "retention.policy_updated"
+	ActionLeaderboardSizeUpdated  = "leaderboard.size_updated"
+	ActionDailySubmissionsUpdated = "submissions.daily_budget_updated"
+	ActionAntiCheatUpdated        = "submissions.anti_cheat_updated"
+	ActionScoringUpdated          = "submissions.scoring_updated"
+	ActionScoreDeleted            = "score.deleted"
+	ActionPlayerDeleted           = "player.deleted"
+	ActionDeletionRestored        = "deletion.restored"
+	ActionDisputeResolved         = "dispute.resolved"
+	ActionGameReset               = "game.reset"
+	ActionGameDeleted             = "game.deleted"
+	ActionPlayerRecomputed        = "player.recomputed"
+	ActionInitialsBlocked         = "initials.blocked"
+	ActionInitialsUnblocked       = "initials.unblocked"
+	ActionAPIKeyCreated           = "api_key.created"
+	ActionAPIKeyRevoked           = "api_key.revoked"
+	ActionAPIKeyGameLimitUpdated  = "api_key.game_limit_updated"
+	ActionAPIKeyRateLimitUpdated  = "api_key.rate_limit_updated"
+	ActionBootstrapApplied        = "bootstrap.applied"
+	ActionWebhookCreated          = "webhook.created"
+	ActionWebhookDeleted          = "webhook.deleted"
+	ActionScoresImported          = "scores.imported"
+	ActionGameMerged              = "game.merged"
+	ActionDisplayUpdated          = "display.updated"
+	ActionDisplayDeleted          = "display.deleted"
+	ActionDisplayOffline          = "display.offline"
+	ActionDisplayOnline           = "display.online"
+	ActionDisplayDeviceRemoved    = "display.device_removed"
+	ActionSeasonStarted           = "season.started"
+	ActionSeasonEnded             = "season.ended"
+	ActionTournamentUpdated       = "tournament.updated"
+	ActionTournamentDeleted       = "tournament.deleted"
+	ActionDeviceCreated           = "device.created"
+	ActionDeviceEnrolled          = "device.enrolled"
+	ActionDeviceRevoked           = "device.revoked"
+	ActionProfileDeleted          = "profile.deleted"
+	ActionRequirePINUpdated       = "submissions.require_pin_updated"
+	ActionInitialsPolicyUpdated   = "submissions.initials_policy_updated"
+	ActionHappyHoursUpdated       = "submissions.happy_hours_updated"
+	ActionAchievementUpdated      = "achievement.updated"
+	ActionAchievementDeleted      = "achievement.deleted"
+	ActionAchievementBadgeUpdated = "achievement.badge_updated"
+	ActionAchievementBadgeDeleted = "achievement.badge_deleted"
+	ActionAchievementThemeUpdated = "achievement.theme_updated"
+	ActionDrainStarted            = "instance.drain_started"
+	ActionDrainStopped            = "instance.drain_stopped"
+	ActionSessionStarted          = "session.started"
+	ActionGameSunsetUpdated       = "game.sunset_updated"
+	ActionGameSunsetCleared       = "game.sunset_cleared"
+	ActionSigningSecretRotated    = "submissions.signing_secret_rotated"
+	ActionSigningDisabled         = "submissions.signing_disabled"
+	ActionEdgeReplayed            = "edge.replayed"
+	ActionAPIWrite                = "api.write" // An authenticated write its handler recorded nothing more specific for
+)
+
+// Log is an append-only audit log stored in the database: a Valkey stream on databases
+// with streams, a bounded JSON record elsewhere
+type Log struct {
+	db      database.DB
+	streams database.Streams // Nil when the database has none
+	mu      sync.Mutex
+}
+
+// Filter selects audit entries; empty fields match every entry
+type Filter struct {
+	GameID string
+	Actor  string
+	Action string
+	Method string
+	Route  string    // A route pattern, such as /api/v1/games/:gameId/scores
+	From   time.Time // Entries at or after
+	To     time.Time // Entries at or before
+	Limit  int       // At most this many entries; 0 for every match
+}
+
+// request is the API request entries are recorded for
+type request struct {
+	method   string
+	route    string
+	recorded atomic.Bool
+}
+
+type requestKey struct{}
+
+// NewLog creates a new audit log
+func NewLog(db database.DB) *Log {
+	l := &Log{db: db}
+	if streams, ok := db.(database.Streams); ok {
+		l.streams = streams
+	}
+	return l
+}
+
+// WithRequest returns a copy of ctx for the API request with method and route pattern.
+// Entries recorded with it name the route, and Recorded reports whether there were any.
+func WithRequest(ctx context.Context, method, route string) context.Context {
+	return context.WithValue(ctx, requestKey{}, &request{method: method, route: route})
+}
+
+// Recorded reports whether an entry was recorded for the API request ctx belongs to
+func Recorded(ctx context.Context) bool {
+	r, _ := ctx.Value(requestKey{}).(*request)
+	return r != nil && r.recorded.Load()
+}
+
+// Record appends an entry to the audit log, filling in its ID, timestamp and the ID,
+// method and route of the request ctx belongs to
+func (l *Log) Record(ctx context.Context, entry models.AuditEntry) error {
+	if entry.ID == "" {
+		entry.ID = uuid.New().String()
+	}
+	if entry.RequestID == "" {
+		entry.RequestID = logging.RequestID(ctx)
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now().UTC()
+	}
+	r, _ := ctx.Value(requestKey{}).(*request)
+	if r != nil && entry.Route == "" {
+		entry.Method, entry.Route = r.method, r.route
+	}
+
+	var err error
+	if l.streams != nil {
+		err = l.append(ctx, entry)
+	} else {
+		err = l.rewrite(ctx, entry)
+	}
+	if err == nil && r != nil {
+		r.recorded.Store(true)
+	}
+	return err
+}
+
+// append adds an entry to the audit stream
+func (l *Log) append(ctx context.Context, entry models.AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	_, err = l.streams.XAdd(ctx, streamKey, maxStreamEntries, map[string]string{"entry": string(data)})
+	return err
+}
+
+// rewrite adds an entry to the JSON audit log
+func (l *Log) rewrite(ctx context.Context, entry models.AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	record, err := l.load(ctx)
+	if err != nil {
+		record = &models.AuditLogRecord{Entries: []models.AuditEntry{}}
+	}
+
+	record.Entries = append(record.Entries, entry)
+	if len(record.Entries) > maxEntries {
+		record.Entries = record.Entries[len(record.Entries)-maxEntries:]
+	}
+	record.Updated = entry.Timestamp
+
+	var buf strings.Builder
+	if err := json.NewEncoder(&buf).Encode(record); err != nil {
+		return fmt.Errorf("failed to marshal audit log: %w", err)
+	}
+
+	return l.db.Set(ctx, logKey, strings.TrimSuffix(buf.String(), "\n"))
+}
+
+// Entries returns audit entries, newest first, optionally restricted to one game
+func (l *Log) Entries(ctx context.Context, gameID string, limit int) ([]models.AuditEntry, error) {
+	return l.Query(ctx, Filter{GameID: gameID, Limit: limit})
+}
+
+// Query returns the audit entries filter selects, newest first. On databases with
+// streams, entries recorded before the log moved to a stream follow the stream's.
+func (l *Log) Query(ctx context.Context, filter Filter) ([]models.AuditEntry, error) {
+	entries := make([]models.AuditEntry, 0)
+	if l.streams != nil {
+		var err error
+		if entries, err = l.queryStream(ctx, filter, entries); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Limit > 0 && len(entries) >= filter.Limit {
+		return entries, nil
+	}
+
+	record, err := l.load(ctx)
+	if err != nil {
+		return entries, nil
+	}
+	for i := len(record.Entries) - 1; i >= 0; i-- {
+		if !filter.matches(record.Entries[i]) {
+			continue
+		}
+		entries = append(entries, record.Entries[i])
+		if filter.Limit > 0 && len(entries) >= filter.Limit {
+			break
+		}
+	}
+	return entries, nil
+}
+
+// queryStream appends the stream's entries filter selects to entries, reading back a
+// page at a time from To back to From
+func (l *Log) queryStream(ctx context.Context, filter Filter, entries []models.AuditEntry) ([]models.AuditEntry, error) {
+	end := "+"
+	if !filter.To.IsZero() {
+		end = strconv.FormatInt(filter.To.UnixMilli(), 10)
+	}
+
+	for {
+		page, err := l.streams.XRevRange(ctx, streamKey, end, queryPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit stream: %w", err)
+		}
+		for _, item := range page {
+			var entry models.AuditEntry
+			if err := json.Unmarshal([]byte(item.Fields["entry"]), &entry); err != nil {
+				continue
+			}
+			if !filter.From.IsZero() && entry.Timestamp.Before(filter.From) {
+				return entries, nil
+			}
+			if !filter.matches(entry) {
+				continue
+			}
+			entries = append(entries, entry)
+			if filter.Limit > 0 && len(entries) >= filter.Limit {
+				return entries, nil
+			}
+		}
+		if len(page) < queryPageSize {
+			return entries, nil
+		}
+		end = "(" + page[len(page)-1].ID
+	}
+}
+
+// matches reports whether filter selects entry
+func (f Filter) matches(entry models.AuditEntry) bool {
+	switch {
+	case f.GameID != "" && entry.GameID != f.GameID,
+		f.Actor != "" && entry.Actor != f.Actor,
+		f.Action != "" && entry.Action != f.Action,
+		f.Method != "" && !strings.EqualFold(entry.Method, f.Method),
+		f.Route != "" && entry.Route != f.Route,
+		!f.From.IsZero() && entry.Timestamp.Before(f.From),
+		!f.To.IsZero() && entry.Timestamp.After(f.To):
+		return false
+	}
+	return true
+}
+
+// load reads the stored audit log
+func (l *Log) load(ctx context.Context) (*models.AuditLogRecord, error) {
+	data, err := l.db.Get(ctx, logKey)
+	if err != nil {
+		return nil, fmt.Errorf("no audit log found")
+	}
+
+	var record models.AuditLogRecord
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal audit log: %w", err)
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,96 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+)
+
+func TestLogRecordsRequestID(t *testing.T) {
+	log := NewLog(database.NewFake())
+	ctx := logging.WithRequestID(context.Background(), "req-123")
+
+	if err := log.Record(ctx, models.AuditEntry{Action: ActionAPIKeyCreated, Actor: "master"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := log.Record(context.Background(), models.AuditEntry{Action: ActionRetentionPrune, Actor: "system:retention"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	entries, err := log.Entries(ctx, "", 10)
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %+v, %v", entries, err)
+	}
+	for _, entry := range entries {
+		want := ""
+		if entry.Action == ActionAPIKeyCreated {
+			want = "req-123"
+		}
+		if entry.RequestID != want {
+			t.Errorf("%s: expected request ID %q, got %q", entry.Action, want, entry.RequestID)
+		}
+	}
+}
+
+func TestLogQuery(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+
+	// Entries recorded before the log moved to a stream stay readable after its own
+	legacy := NewLog(struct{ database.DB }{db})
+	if err := legacy.Record(ctx, models.AuditEntry{Action: ActionAPIKeyCreated, Actor: AdminActor}); err != nil {
+		t.Fatal(err)
+	}
+
+	log := NewLog(db)
+	start := time.Now()
+	for i := 0; i < queryPageSize+10; i++ {
+		entry := models.AuditEntry{Action: ActionAPIWrite, Actor: "api_key:cabinet", GameID: "pacman"}
+		if i%2 == 1 {
+			entry.GameID = "galaga"
+		}
+		if err := log.Record(WithRequest(ctx, "POST", "/api/v1/games/:gameId/scores"), entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := log.Record(ctx, models.AuditEntry{Action: ActionScoreDeleted, Actor: AdminActor, GameID: "pacman"}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := log.Query(ctx, Filter{GameID: "pacman", Action: ActionAPIWrite})
+	if err != nil || len(entries) != (queryPageSize+10)/2 {
+		t.Fatalf("Expected every pacman write across pages, got %d, %v", len(entries), err)
+	}
+	if entries[0].Route != "/api/v1/games/:gameId/scores" || entries[0].Method != "POST" {
+		t.Errorf("Expected entries to name their request's route, got %+v", entries[0])
+	}
+
+	entries, _ = log.Query(ctx, Filter{Actor: AdminActor})
+	if len(entries) != 2 || entries[0].Action != ActionScoreDeleted || entries[1].Action != ActionAPIKeyCreated {
+		t.Errorf("Expected the deletion, then the legacy key creation, got %+v", entries)
+	}
+
+	entries, _ = log.Query(ctx, Filter{From: start, Limit: 3})
+	if len(entries) != 3 || entries[0].Action != ActionScoreDeleted {
+		t.Errorf("Expected the 3 newest entries, got %+v", entries)
+	}
+	if entries, _ := log.Query(ctx, Filter{To: start.Add(-time.Hour)}); len(entries) != 0 {
+		t.Errorf("Expected nothing an hour before the first entry, got %d", len(entries))
+	}
+}
+
+func TestRecorded(t *testing.T) {
+	log := NewLog(database.NewFake())
+	ctx := WithRequest(context.Background(), "DELETE", "/api/v1/games/:gameId/scores")
+	if Recorded(ctx) {
+		t.Fatal("Expected nothing recorded yet")
+	}
+	log.Record(ctx, models.AuditEntry{Action: ActionScoreDeleted})
+	if !Recorded(ctx) || Recorded(context.Background()) {
+		t.Error("Expected only the request's context to report its entry")
+	}
+}
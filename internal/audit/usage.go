@@ -0,0 +1,297 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+const (
+	// MaxUsageWindow bounds how far a single usage report may reach
+	MaxUsageWindow = 31 * 24 * time.Hour
+	// MasterKeyID identifies the deployment-wide RAWBOARD_API_KEY in usage rows
+	MasterKeyID = "master"
+)
+
+// UsageRequest describes one authenticated request to be counted
+type UsageRequest struct {
+	KeyID   string
+	KeyName string
+	Method  string
+	Route   string
+	GameID  string
+	Tenant  string
+	Status  int
+	At      time.Time
+}
+
+// UsageFilter narrows a usage report; empty fields match everything except Tenant,
+// which always matches exactly so tenants only see their own usage
+type UsageFilter struct {
+	KeyID  string
+	Route  string
+	GameID string
+	Tenant string
+}
+
+// usageRowKey identifies a row within an hourly bucket
+type usageRowKey struct {
+	keyID  string
+	method string
+	route  string
+	gameID string
+	tenant string
+}
+
+// UsageTracker counts authenticated requests per key, route and game in hourly buckets.
+// Requests are aggregated in memory and written to the database by Flush, so tracking
+// adds no database round trips to the request path.
+type UsageTracker struct {
+	db      database.DB
+	mu      sync.Mutex
+	pending map[time.Time]map[usageRowKey]*models.UsageRow
+	flushMu sync.Mutex
+}
+
+// NewUsageTracker creates a new usage tracker
+func NewUsageTracker(db database.DB) *UsageTracker {
+	return &UsageTracker{
+		db:      db,
+		pending: make(map[time.Time]map[usageRowKey]*models.UsageRow),
+	}
+}
+
+// Track counts a request; it is written to the database on the next Flush
+func (t *UsageTracker) Track(req UsageRequest) {
+	at := req.At.UTC()
+	hour := at.Truncate(time.Hour)
+	key := usageRowKey{keyID: req.KeyID, method: req.Method, route: req.Route, gameID: req.GameID, tenant: req.Tenant}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	rows, ok := t.pending[hour]
+	if !ok {
+		rows = make(map[usageRowKey]*models.UsageRow)
+		t.pending[hour] = rows
+	}
+
+	row, ok := rows[key]
+	if !ok {
+		row = &models.UsageRow{
+			KeyID:     req.KeyID,
+			KeyName:   req.KeyName,
+			Method:    req.Method,
+			Route:     req.Route,
+			GameID:    req.GameID,
+			Tenant:    req.Tenant,
+			FirstSeen: at,
+		}
+		rows[key] = row
+	}
+
+	row.Requests++
+	if req.Status >= 400 {
+		row.Errors++
+	}
+	row.LastSeen = at
+}
+
+// Flush merges pending counts into the stored hourly buckets
+func (t *UsageTracker) Flush(ctx context.Context) error {
+	t.flushMu.Lock()
+	defer t.flushMu.Unlock()
+
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[time.Time]map[usageRowKey]*models.UsageRow)
+	t.mu.Unlock()
+
+	var failed []string
+	for hour, rows := range pending {
+		if err := t.mergeBucket(ctx, hour, rows); err != nil {
+			failed = append(failed, hour.Format(time.RFC3339))
+			t.requeue(hour, rows)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to flush usage for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// Report returns usage between from and to, grouped into hourly or daily periods
+func (t *UsageTracker) Report(ctx context.Context, from, to time.Time, granularity string, filter UsageFilter) (*models.UsageReport, error) {
+	from, to = from.UTC().Truncate(time.Hour), to.UTC()
+	if !to.After(from) {
+		return nil, fmt.Errorf("to must be after from")
+	}
+	if to.Sub(from) > MaxUsageWindow {
+		return nil, fmt.Errorf("window must not exceed %v", MaxUsageWindow)
+	}
+	if granularity != models.UsageGranularityHour && granularity != models.UsageGranularityDay {
+		return nil, fmt.Errorf("unknown granularity %q", granularity)
+	}
+
+	report := &models.UsageReport{
+		From:        from,
+		To:          to,
+		Granularity: granularity,
+		Periods:     []models.UsagePeriod{},
+	}
+
+	periods := make(map[time.Time]map[usageRowKey]*models.UsageRow)
+	totals := make(map[usageRowKey]*models.UsageRow)
+
+	for hour := from; hour.Before(to); hour = hour.Add(time.Hour) {
+		bucket, err := t.loadBucket(ctx, hour)
+		if err != nil {
+			continue // No usage that hour
+		}
+
+		start := hour
+		if granularity == models.UsageGranularityDay {
+			start = hour.Truncate(24 * time.Hour)
+		}
+
+		for _, row := range bucket.Rows {
+			if !filter.matches(row) {
+				continue
+			}
+			if periods[start] == nil {
+				periods[start] = make(map[usageRowKey]*models.UsageRow)
+			}
+			mergeRow(periods[start], row)
+			mergeRow(totals, row)
+		}
+	}
+
+	for start, rows := range periods {
+		report.Periods = append(report.Periods, models.UsagePeriod{Start: start, Rows: sortedRows(rows)})
+	}
+	sort.Slice(report.Periods, func(i, j int) bool {
+		return report.Periods[i].Start.Before(report.Periods[j].Start)
+	})
+	report.Totals = sortedRows(totals)
+
+	return report, nil
+}
+
+// matches reports whether a row passes the filter
+func (f UsageFilter) matches(row models.UsageRow) bool {
+	return (f.KeyID == "" || row.KeyID == f.KeyID) &&
+		(f.Route == "" || row.Route == f.Route) &&
+		(f.GameID == "" || row.GameID == f.GameID) &&
+		row.Tenant == f.Tenant
+}
+
+// mergeBucket adds rows to the stored bucket for hour
+func (t *UsageTracker) mergeBucket(ctx context.Context, hour time.Time, rows map[usageRowKey]*models.UsageRow) error {
+	merged := make(map[usageRowKey]*models.UsageRow)
+	if bucket, err := t.loadBucket(ctx, hour); err == nil {
+		for _, row := range bucket.Rows {
+			mergeRow(merged, row)
+		}
+	}
+	for _, row := range rows {
+		mergeRow(merged, *row)
+	}
+
+	bucket := &models.UsageBucket{Hour: hour, Rows: sortedRows(merged)}
+
+	var buf strings.Builder
+	if err := json.NewEncoder(&buf).Encode(bucket); err != nil {
+		return fmt.Errorf("failed to marshal usage bucket: %w", err)
+	}
+
+	return t.db.Set(ctx, usageKey(hour), strings.TrimSuffix(buf.String(), "\n"))
+}
+
+// requeue returns unflushed rows to the pending set so the next flush retries them
+func (t *UsageTracker) requeue(hour time.Time, rows map[usageRowKey]*models.UsageRow) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.pending[hour] == nil {
+		t.pending[hour] = make(map[usageRowKey]*models.UsageRow)
+	}
+	for _, row := range rows {
+		mergeRow(t.pending[hour], *row)
+	}
+}
+
+// loadBucket reads the stored bucket for hour
+func (t *UsageTracker) loadBucket(ctx context.Context, hour time.Time) (*models.UsageBucket, error) {
+	data, err := t.db.Get(ctx, usageKey(hour))
+	if err != nil {
+		return nil, fmt.Errorf("no usage recorded")
+	}
+
+	var bucket models.UsageBucket
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&bucket); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal usage bucket: %w", err)
+	}
+
+	return &bucket, nil
+}
+
+// usageKey returns the database key holding usage for an hour
+func usageKey(hour time.Time) string {
+	return fmt.Sprintf("audit_usage:%s", hour.UTC().Format("2006010215"))
+}
+
+// mergeRow adds row's counts into rows, widening the seen window
+func mergeRow(rows map[usageRowKey]*models.UsageRow, row models.UsageRow) {
+	key := usageRowKey{keyID: row.KeyID, method: row.Method, route: row.Route, gameID: row.GameID, tenant: row.Tenant}
+
+	existing, ok := rows[key]
+	if !ok {
+		copied := row
+		rows[key] = &copied
+		return
+	}
+
+	existing.Requests += row.Requests
+	existing.Errors += row.Errors
+	if row.FirstSeen.Before(existing.FirstSeen) {
+		existing.FirstSeen = row.FirstSeen
+	}
+	if row.LastSeen.After(existing.LastSeen) {
+		existing.LastSeen = row.LastSeen
+	}
+	if row.KeyName != "" {
+		existing.KeyName = row.KeyName
+	}
+}
+
+// sortedRows returns rows ordered by key, route and game
+func sortedRows(rows map[usageRowKey]*models.UsageRow) []models.UsageRow {
+	sorted := make([]models.UsageRow, 0, len(rows))
+	for _, row := range rows {
+		sorted = append(sorted, *row)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.KeyID != b.KeyID {
+			return a.KeyID < b.KeyID
+		}
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		return a.GameID < b.GameID
+	})
+	return sorted
+}
@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestUsageTracker(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 7, 16, 2, 0, 0, 0, time.UTC)
+	submit := func(keyID string, at time.Time, status int) UsageRequest {
+		return UsageRequest{
+			KeyID:  keyID,
+			Method: "POST",
+			Route:  "/api/v1/games/:gameId/scores",
+			GameID: "pacman",
+			Status: status,
+			At:     at,
+		}
+	}
+
+	t.Run("aggregates per key and route across flushes", func(t *testing.T) {
+		tracker := NewUsageTracker(database.NewFake())
+		tracker.Track(submit("cab-1", base.Add(5*time.Minute), 201))
+		tracker.Track(submit("cab-1", base.Add(10*time.Minute), 400))
+		if err := tracker.Flush(ctx); err != nil {
+			t.Fatalf("Unexpected flush error: %v", err)
+		}
+		tracker.Track(submit("cab-1", base.Add(50*time.Minute), 201))
+		tracker.Track(submit("cab-2", base.Add(55*time.Minute), 201))
+		if err := tracker.Flush(ctx); err != nil {
+			t.Fatalf("Unexpected flush error: %v", err)
+		}
+
+		report, err := tracker.Report(ctx, base, base.Add(time.Hour), models.UsageGranularityHour, UsageFilter{})
+		if err != nil {
+			t.Fatalf("Unexpected report error: %v", err)
+		}
+		if len(report.Periods) != 1 || len(report.Totals) != 2 {
+			t.Fatalf("Expected one period with two rows, got %+v", report)
+		}
+
+		row := report.Totals[0]
+		if row.KeyID != "cab-1" || row.Requests != 3 || row.Errors != 1 {
+			t.Errorf("Unexpected cab-1 totals: %+v", row)
+		}
+		if !row.FirstSeen.Equal(base.Add(5*time.Minute)) || !row.LastSeen.Equal(base.Add(50*time.Minute)) {
+			t.Errorf("Expected seen window 02:05-02:50, got %v-%v", row.FirstSeen, row.LastSeen)
+		}
+	})
+
+	t.Run("filters by key and groups by day", func(t *testing.T) {
+		tracker := NewUsageTracker(database.NewFake())
+		tracker.Track(submit("cab-1", base, 201))
+		tracker.Track(submit("cab-1", base.Add(3*time.Hour), 201))
+		tracker.Track(submit("cab-2", base.Add(3*time.Hour), 201))
+		if err := tracker.Flush(ctx); err != nil {
+			t.Fatalf("Unexpected flush error: %v", err)
+		}
+
+		report, err := tracker.Report(ctx, base, base.Add(6*time.Hour), models.UsageGranularityDay, UsageFilter{KeyID: "cab-1"})
+		if err != nil {
+			t.Fatalf("Unexpected report error: %v", err)
+		}
+		if len(report.Periods) != 1 || !report.Periods[0].Start.Equal(base.Truncate(24*time.Hour)) {
+			t.Fatalf("Expected a single daily period, got %+v", report.Periods)
+		}
+		if len(report.Totals) != 1 || report.Totals[0].Requests != 2 {
+			t.Errorf("Expected two cab-1 requests, got %+v", report.Totals)
+		}
+	})
+
+	t.Run("rejects oversized windows", func(t *testing.T) {
+		tracker := NewUsageTracker(database.NewFake())
+		if _, err := tracker.Report(ctx, base, base.Add(MaxUsageWindow+time.Hour), models.UsageGranularityHour, UsageFilter{}); err == nil {
+			t.Error("Expected an error for a window over the maximum")
+		}
+	})
+}
@@ -0,0 +1,485 @@
+// Package bootstrap reconciles a deployment's games, API keys and blocklist with a
+// declarative document, so they can be managed as code
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+// ValidationError reports a document field that can't be applied
+type ValidationError struct {
+	Field    string
+	Value    string
+	Expected string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %q: expected %s", e.Field, e.Value, e.Expected)
+}
+
+// Reconciler applies bootstrap documents
+type Reconciler struct {
+	service *leaderboard.Service
+	keys    *apikeys.Store
+}
+
+// NewReconciler creates a reconciler for the service's games and the key store
+func NewReconciler(service *leaderboard.Service, keys *apikeys.Store) *Reconciler {
+	return &Reconciler{service: service, keys: keys}
+}
+
+// step is one planned change and how to make it
+type step struct {
+	change models.BootstrapChange
+	apply  func(ctx context.Context, result *models.BootstrapResult) error
+}
+
+// Reconcile makes the stored state match doc. Applying the same document again
+// changes nothing. A dry run reports the changes without making them. If a change
+// fails, the result lists the changes made before it and the document can be re-applied.
+func (r *Reconciler) Reconcile(ctx context.Context, doc *models.BootstrapDocument, dryRun bool) (*models.BootstrapResult, error) {
+	if err := Validate(doc); err != nil {
+		return nil, err
+	}
+
+	gameSteps, err := r.planGames(ctx, doc.Games)
+	if err != nil {
+		return nil, err
+	}
+	keySteps, err := r.planKeys(ctx, doc.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+	steps := append(gameSteps, keySteps...)
+	steps = append(steps, r.planBlocklist(ctx, doc.BlockedInitials)...)
+
+	result := &models.BootstrapResult{
+		DryRun:      dryRun,
+		Changes:     []models.BootstrapChange{},
+		CreatedKeys: []models.CreatedAPIKey{},
+	}
+	for _, s := range steps {
+		if !dryRun {
+			if err := s.apply(ctx, result); err != nil {
+				return result, fmt.Errorf("failed to %s %s %s: %w", s.change.Action, s.change.Resource, s.change.ID, err)
+			}
+		}
+		result.Changes = append(result.Changes, s.change)
+	}
+	return result, nil
+}
+
+// Validate checks a document without touching stored state
+func Validate(doc *models.BootstrapDocument) error {
+	seenGames := make(map[string]bool)
+	for _, game := range doc.Games {
+		if len(game.GameID) < 1 || len(game.GameID) > 50 {
+			return &ValidationError{"games.game_id", game.GameID, "length between 1 and 50 characters"}
+		}
+		if seenGames[game.GameID] {
+			return &ValidationError{"games.game_id", game.GameID, "each game listed once"}
+		}
+		seenGames[game.GameID] = true
+
+		if size := game.Settings.MaxEntries; size < 0 || size > models.MaxLeaderboardEntries {
+			return &ValidationError{"games.settings.max_entries", fmt.Sprint(size), fmt.Sprintf("between 0 and %d", models.MaxLeaderboardEntries)}
+		}
+		if policy := game.Settings.InitialsPolicy; !models.ValidInitialsPolicy(policy) {
+			return &ValidationError{"games.settings.initials_policy", policy, "shared, device or claimed"}
+		}
+		if budget := game.Settings.DailySubmissions; budget < 0 || budget > models.MaxDailySubmissions {
+			return &ValidationError{"games.settings.daily_submissions", fmt.Sprint(budget), fmt.Sprintf("between 0 and %d", models.MaxDailySubmissions)}
+		}
+		if retention := game.Settings.Retention; retention != nil && retention.HistoryDays < 0 {
+			return &ValidationError{"games.settings.retention.history_days", fmt.Sprint(retention.HistoryDays), "zero (keep everything) or a positive number of days"}
+		}
+		if retention := game.Settings.Retention; retention != nil && retention.MaxScores < 0 {
+			return &ValidationError{"games.settings.retention.max_scores", fmt.Sprint(retention.MaxScores), "zero (keep everything) or a positive number of scores"}
+		}
+		if game.Settings.AntiCheat != nil {
+			rules := *game.Settings.AntiCheat // Validate defaults the action; leave that to normalizeSettings
+			if err := rules.Validate(); err != nil {
+				return &ValidationError{"games.settings.anti_cheat", fmt.Sprintf("%+v", rules), err.Error()}
+			}
+		}
+		if scoring := game.Settings.Scoring; scoring != nil {
+			if err := scoring.Validate(); err != nil {
+				return &ValidationError{"games.settings.scoring.decimals", fmt.Sprint(scoring.Decimals), err.Error()}
+			}
+		}
+		if err := models.ValidateHappyHours(normalizeSettings(game.Settings).HappyHours); err != nil {
+			return &ValidationError{"games.settings.happy_hours", game.GameID, err.Error()}
+		}
+		if !models.ValidAchievementTheme(game.Settings.AchievementTheme) {
+			return &ValidationError{"games.settings.achievement_theme", game.Settings.AchievementTheme, "one of classic, medals or retro"}
+		}
+		if sunset := game.Settings.Sunset; sunset != nil {
+			if err := sunset.Validate(); err != nil {
+				return &ValidationError{"games.settings.sunset", game.GameID, err.Error()}
+			}
+		}
+	}
+
+	seenKeys := make(map[string]bool)
+	for _, key := range doc.APIKeys {
+		if key.Name == "" || len(key.Name) > 100 {
+			return &ValidationError{"api_keys.name", key.Name, "between 1 and 100 characters"}
+		}
+		if seenKeys[key.Name] {
+			return &ValidationError{"api_keys.name", key.Name, "each key name listed once"}
+		}
+		seenKeys[key.Name] = true
+
+		if len(key.GameIDs) == 0 {
+			return &ValidationError{"api_keys.game_ids", key.Name, "at least one game, or \"*\" for every game"}
+		}
+		for _, gameID := range key.GameIDs {
+			if len(gameID) < 1 || len(gameID) > 50 {
+				return &ValidationError{"api_keys.game_ids", gameID, "length between 1 and 50 characters, or \"*\" for every game"}
+			}
+		}
+		if len(key.Scopes) == 0 {
+			return &ValidationError{"api_keys.scopes", key.Name, "at least one scope"}
+		}
+		for _, scope := range key.Scopes {
+			if !apikeys.IsValidScope(scope) {
+				return &ValidationError{"api_keys.scopes", scope, "one of submit, admin:read, admin:write"}
+			}
+		}
+		if key.Secret != "" && !apikeys.ValidSecret(key.Secret) {
+			return &ValidationError{"api_keys.secret", key.Name, "a secret starting with rbk_ and at least 36 characters"}
+		}
+	}
+
+	for _, initials := range doc.BlockedInitials {
+		normalized := models.NormalizeInitials(initials)
+		if len(normalized) != 3 || strings.Contains(normalized, " ") {
+			return &ValidationError{"blocked_initials", initials, "exactly 3 characters with no spaces"}
+		}
+	}
+
+	return nil
+}
+
+// planGames registers missing games and brings their settings in line
+func (r *Reconciler) planGames(ctx context.Context, games []models.BootstrapGame) ([]step, error) {
+	var steps []step
+	for _, declared := range games {
+		gameID := declared.GameID
+		want := normalizeSettings(declared.Settings)
+
+		action := models.BootstrapActionUpdate
+		var have models.GameSettings
+		if game, err := r.service.GetGame(ctx, gameID); err == nil {
+			have = normalizeSettings(game.Settings)
+			if settingsEqual(have, want) {
+				continue
+			}
+		} else {
+			action = models.BootstrapActionCreate
+		}
+
+		steps = append(steps, step{
+			change: models.BootstrapChange{Resource: models.BootstrapResourceGame, ID: gameID, Action: action},
+			apply: func(ctx context.Context, _ *models.BootstrapResult) error {
+				if _, err := r.service.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+					settings.Retention = want.Retention
+					settings.DailySubmissions = want.DailySubmissions
+					settings.AntiCheat = want.AntiCheat
+					settings.RequirePIN = want.RequirePIN
+					settings.HappyHours = want.HappyHours
+					settings.AchievementTheme = want.AchievementTheme
+					settings.Sunset = want.Sunset
+					// A new size regenerates the leaderboard, backfilling it from high scores
+					settings.MaxEntries = want.MaxEntries
+					return nil
+				}); err != nil {
+					return err
+				}
+				// Switching to or from device-scoped initials rekeys the game's high scores
+				if have.InitialsPolicy != want.InitialsPolicy {
+					if _, err := r.service.SetInitialsPolicy(ctx, gameID, want.InitialsPolicy); err != nil {
+						return err
+					}
+				}
+				// Decimals are locked once a game has scores, which SetScoring enforces
+				if !scoringEqual(have.Scoring, want.Scoring) {
+					if _, err := r.service.SetScoring(ctx, gameID, scoringOrZero(want.Scoring)); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		})
+	}
+	return steps, nil
+}
+
+// planKeys creates, updates and revokes keys so the active keys are exactly those declared.
+// Nil leaves keys alone; an empty list revokes every scoped key.
+func (r *Reconciler) planKeys(ctx context.Context, declared []models.BootstrapAPIKey) ([]step, error) {
+	if declared == nil {
+		return nil, nil
+	}
+
+	stored, err := r.keys.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	active := make(map[string][]models.APIKey) // By name, oldest first
+	for _, key := range stored {
+		if !key.Revoked() {
+			active[key.Name] = append(active[key.Name], key)
+		}
+	}
+
+	var steps []step
+	revoke := func(key models.APIKey) {
+		steps = append(steps, step{
+			change: models.BootstrapChange{Resource: models.BootstrapResourceAPIKey, ID: key.Name, Action: models.BootstrapActionRevoke},
+			apply: func(ctx context.Context, _ *models.BootstrapResult) error {
+				_, err := r.keys.Revoke(ctx, key.ID)
+				return err
+			},
+		})
+	}
+
+	for _, want := range declared {
+		want := want
+		gameIDs, scopes := sortedCopy(want.GameIDs), sortedCopy(want.Scopes)
+
+		// Keep the oldest key with this name, or the one holding the declared secret
+		var keep *models.APIKey
+		for i, key := range active[want.Name] {
+			if want.Secret == "" || r.keys.HasSecret(ctx, key.ID, want.Secret) {
+				keep = &active[want.Name][i]
+				break
+			}
+		}
+		for _, key := range active[want.Name] {
+			if keep == nil || key.ID != keep.ID {
+				revoke(key)
+			}
+		}
+		delete(active, want.Name)
+
+		switch {
+		case keep == nil:
+			steps = append(steps, step{
+				change: models.BootstrapChange{Resource: models.BootstrapResourceAPIKey, ID: want.Name, Action: models.BootstrapActionCreate},
+				apply: func(ctx context.Context, result *models.BootstrapResult) error {
+					if want.Secret != "" {
+						_, err := r.keys.Import(ctx, want.Name, want.Secret, gameIDs, scopes)
+						return err
+					}
+					created, err := r.keys.Create(ctx, want.Name, gameIDs, scopes)
+					if err != nil {
+						return err
+					}
+					result.CreatedKeys = append(result.CreatedKeys, *created)
+					return nil
+				},
+			})
+		case !equalStrings(sortedCopy(keep.GameIDs), gameIDs) || !equalStrings(sortedCopy(keep.Scopes), scopes):
+			id := keep.ID
+			steps = append(steps, step{
+				change: models.BootstrapChange{Resource: models.BootstrapResourceAPIKey, ID: want.Name, Action: models.BootstrapActionUpdate},
+				apply: func(ctx context.Context, _ *models.BootstrapResult) error {
+					_, err := r.keys.Update(ctx, id, gameIDs, scopes)
+					return err
+				},
+			})
+		}
+	}
+
+	// Whatever is left wasn't declared
+	names := make([]string, 0, len(active))
+	for name := range active {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, key := range active[name] {
+			revoke(key)
+		}
+	}
+
+	return steps, nil
+}
+
+// planBlocklist makes the managed blocklist exactly the declared initials.
+// Nil leaves it alone; built-in and configured entries are never affected.
+func (r *Reconciler) planBlocklist(ctx context.Context, declared []string) []step {
+	if declared == nil {
+		return nil
+	}
+
+	want := make(map[string]bool)
+	for _, initials := range declared {
+		want[models.NormalizeInitials(initials)] = true
+	}
+	have := make(map[string]bool)
+	for _, initials := range r.service.Blocklist(ctx).Managed {
+		have[initials] = true
+	}
+
+	var steps []step
+	for _, initials := range sortedKeys(want) {
+		if !have[initials] {
+			initials := initials
+			steps = append(steps, step{
+				change: models.BootstrapChange{Resource: models.BootstrapResourceBlockedInitials, ID: initials, Action: models.BootstrapActionBlock},
+				apply: func(ctx context.Context, _ *models.BootstrapResult) error {
+					return r.service.BlockInitials(ctx, initials)
+				},
+			})
+		}
+	}
+	for _, initials := range sortedKeys(have) {
+		if !want[initials] {
+			initials := initials
+			steps = append(steps, step{
+				change: models.BootstrapChange{Resource: models.BootstrapResourceBlockedInitials, ID: initials, Action: models.BootstrapActionUnblock},
+				apply: func(ctx context.Context, _ *models.BootstrapResult) error {
+					return r.service.UnblockInitials(ctx, initials)
+				},
+			})
+		}
+	}
+	return steps
+}
+
+// normalizeSettings treats anti-cheat rules with none enabled and default scoring as
+// no settings, and defaults the anti-cheat action, lowercases happy hour days and treats
+// the classic achievement theme as none, as the admin API does. A retention policy without limits is kept: it exempts the game from
+// the default policy.
+func normalizeSettings(settings models.GameSettings) models.GameSettings {
+	if len(settings.HappyHours) == 0 {
+		settings.HappyHours = nil
+	} else {
+		hours := make([]models.HappyHour, len(settings.HappyHours))
+		for i, happyHour := range settings.HappyHours {
+			happyHour.Days = append([]string(nil), happyHour.Days...)
+			for j, day := range happyHour.Days {
+				happyHour.Days[j] = strings.ToLower(strings.TrimSpace(day))
+			}
+			hours[i] = happyHour
+		}
+		settings.HappyHours = hours
+	}
+	if !settings.Scoring.Enabled() {
+		settings.Scoring = nil
+	}
+	if settings.InitialsPolicy == models.InitialsShared {
+		settings.InitialsPolicy = ""
+	}
+	if settings.AchievementTheme == models.AchievementThemeClassic {
+		settings.AchievementTheme = ""
+	}
+	if !settings.AntiCheat.Enabled() {
+		settings.AntiCheat = nil
+	} else if settings.AntiCheat.Action == "" {
+		rules := *settings.AntiCheat
+		rules.Action = models.AntiCheatReject
+		settings.AntiCheat = &rules
+	}
+	return settings
+}
+
+// settingsEqual compares normalized game settings
+func settingsEqual(a, b models.GameSettings) bool {
+	if a.MaxEntries != b.MaxEntries || a.DailySubmissions != b.DailySubmissions || a.RequirePIN != b.RequirePIN || a.InitialsPolicy != b.InitialsPolicy || a.AchievementTheme != b.AchievementTheme {
+		return false
+	}
+	if (a.AntiCheat == nil) != (b.AntiCheat == nil) || (a.AntiCheat != nil && *a.AntiCheat != *b.AntiCheat) {
+		return false
+	}
+	if !scoringEqual(a.Scoring, b.Scoring) || !happyHoursEqual(a.HappyHours, b.HappyHours) {
+		return false
+	}
+	if !sunsetEqual(a.Sunset, b.Sunset) {
+		return false
+	}
+	if a.Retention == nil || b.Retention == nil {
+		return a.Retention == nil && b.Retention == nil
+	}
+	return *a.Retention == *b.Retention
+}
+
+// scoringEqual compares normalized scoring settings
+func scoringEqual(a, b *models.ScoringSettings) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+// sunsetEqual compares sunsets, as instants
+func sunsetEqual(a, b *models.GameSunset) bool {
+	return (a == nil) == (b == nil) && (a == nil || a.At.Equal(b.At) && a.Message == b.Message)
+}
+
+// happyHoursEqual compares normalized happy hours, in order
+func happyHoursEqual(a, b []models.HappyHour) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		x, y := a[i], b[i]
+		if x.ID != y.ID || x.Name != y.Name || x.Multiplier != y.Multiplier || x.Start != y.Start || x.End != y.End || x.Timezone != y.Timezone {
+			return false
+		}
+		if strings.Join(x.Days, ",") != strings.Join(y.Days, ",") || !timesEqual(x.StartsAt, y.StartsAt) || !timesEqual(x.EndsAt, y.EndsAt) {
+			return false
+		}
+	}
+	return true
+}
+
+// timesEqual compares optional times
+func timesEqual(a, b *time.Time) bool {
+	return (a == nil) == (b == nil) && (a == nil || a.Equal(*b))
+}
+
+// scoringOrZero returns the scoring settings to apply, the defaults when nil
+func scoringOrZero(scoring *models.ScoringSettings) models.ScoringSettings {
+	if scoring == nil {
+		return models.ScoringSettings{}
+	}
+	return *scoring
+}
+
+// sortedCopy returns a sorted copy of values
+func sortedCopy(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// sortedKeys returns a set's members in sorted order
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// equalStrings compares two slices element by element
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,227 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+const importedSecret = "rbk_0123456789abcdef0123456789abcdef"
+
+func newTestReconciler() (*Reconciler, *leaderboard.Service, *apikeys.Store) {
+	db := database.NewFake()
+	service := leaderboard.NewService(db)
+	keys := apikeys.NewStore(db)
+	return NewReconciler(service, keys), service, keys
+}
+
+func testDocument() *models.BootstrapDocument {
+	return &models.BootstrapDocument{
+		Games: []models.BootstrapGame{
+			{GameID: "pacman", Settings: models.GameSettings{MaxEntries: 25}},
+			{GameID: "tetris", Settings: models.GameSettings{Retention: &models.RetentionPolicy{HistoryDays: 30}}},
+		},
+		APIKeys: []models.BootstrapAPIKey{
+			{Name: "cabinet", GameIDs: []string{"pacman"}, Scopes: []string{models.ScopeSubmit}},
+			{Name: "ci", GameIDs: []string{"*"}, Scopes: []string{models.ScopeAdminWrite, models.ScopeAdminRead}, Secret: importedSecret},
+		},
+		BlockedInitials: []string{"zzz"},
+	}
+}
+
+// activeKeys returns the active keys by name
+func activeKeys(t *testing.T, keys *apikeys.Store) map[string]models.APIKey {
+	t.Helper()
+	stored, err := keys.List(context.Background())
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	active := make(map[string]models.APIKey)
+	for _, key := range stored {
+		if !key.Revoked() {
+			active[key.Name] = key
+		}
+	}
+	return active
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("applies a document and is idempotent", func(t *testing.T) {
+		reconciler, service, keys := newTestReconciler()
+
+		result, err := reconciler.Reconcile(ctx, testDocument(), false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if len(result.Changes) != 5 {
+			t.Errorf("Expected 5 changes, got %+v", result.Changes)
+		}
+		if len(result.CreatedKeys) != 1 || result.CreatedKeys[0].Name != "cabinet" || result.CreatedKeys[0].Key == "" {
+			t.Errorf("Expected the generated key's secret, got %+v", result.CreatedKeys)
+		}
+
+		if game, err := service.GetGame(ctx, "pacman"); err != nil || game.Settings.MaxEntries != 25 {
+			t.Errorf("Expected pacman with 25 entries, got %+v (%v)", game, err)
+		}
+		if game, err := service.GetGame(ctx, "tetris"); err != nil || game.Settings.Retention == nil || game.Settings.Retention.HistoryDays != 30 {
+			t.Errorf("Expected tetris with 30 day retention, got %+v (%v)", game, err)
+		}
+		if key, err := keys.Resolve(ctx, importedSecret); err != nil || key.Name != "ci" {
+			t.Errorf("Expected the declared secret to resolve to ci, got %+v (%v)", key, err)
+		}
+		if managed := service.Blocklist(ctx).Managed; len(managed) != 1 || managed[0] != "ZZZ" {
+			t.Errorf("Expected ZZZ to be blocked, got %v", managed)
+		}
+
+		again, err := reconciler.Reconcile(ctx, testDocument(), false)
+		if err != nil {
+			t.Fatalf("Second reconcile failed: %v", err)
+		}
+		if len(again.Changes) != 0 || len(again.CreatedKeys) != 0 {
+			t.Errorf("Expected no changes on re-apply, got %+v", again)
+		}
+	})
+
+	t.Run("dry run reports changes without making them", func(t *testing.T) {
+		reconciler, service, keys := newTestReconciler()
+
+		result, err := reconciler.Reconcile(ctx, testDocument(), true)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if !result.DryRun || len(result.Changes) != 5 {
+			t.Errorf("Expected 5 planned changes, got %+v", result)
+		}
+		if games, _ := service.ListGames(ctx); len(games) != 0 {
+			t.Errorf("Expected no games, got %v", games)
+		}
+		if active := activeKeys(t, keys); len(active) != 0 {
+			t.Errorf("Expected no keys, got %v", active)
+		}
+	})
+
+	t.Run("updates, revokes and unblocks to match", func(t *testing.T) {
+		reconciler, service, keys := newTestReconciler()
+		if _, err := reconciler.Reconcile(ctx, testDocument(), false); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		stray, _ := keys.Create(ctx, "stray", []string{"*"}, []string{models.ScopeAdminRead})
+
+		doc := testDocument()
+		doc.Games = doc.Games[:1]
+		doc.Games[0].Settings.MaxEntries = 10
+		doc.APIKeys = doc.APIKeys[:1]
+		doc.APIKeys[0].GameIDs = []string{"pacman", "tetris"}
+		doc.BlockedInitials = []string{}
+
+		result, err := reconciler.Reconcile(ctx, doc, false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+
+		want := map[models.BootstrapChange]bool{
+			{Resource: models.BootstrapResourceGame, ID: "pacman", Action: models.BootstrapActionUpdate}:          true,
+			{Resource: models.BootstrapResourceAPIKey, ID: "cabinet", Action: models.BootstrapActionUpdate}:       true,
+			{Resource: models.BootstrapResourceAPIKey, ID: "ci", Action: models.BootstrapActionRevoke}:            true,
+			{Resource: models.BootstrapResourceAPIKey, ID: "stray", Action: models.BootstrapActionRevoke}:         true,
+			{Resource: models.BootstrapResourceBlockedInitials, ID: "ZZZ", Action: models.BootstrapActionUnblock}: true,
+		}
+		if len(result.Changes) != len(want) {
+			t.Errorf("Expected %d changes, got %+v", len(want), result.Changes)
+		}
+		for _, change := range result.Changes {
+			if !want[change] {
+				t.Errorf("Unexpected change %+v", change)
+			}
+		}
+
+		active := activeKeys(t, keys)
+		if len(active) != 1 || len(active["cabinet"].GameIDs) != 2 {
+			t.Errorf("Expected only cabinet for two games, got %+v", active)
+		}
+		if key, _ := keys.Get(ctx, stray.ID); !key.Revoked() {
+			t.Error("Expected the undeclared key to be revoked")
+		}
+		if game, _ := service.GetGame(ctx, "tetris"); game == nil {
+			t.Error("Expected unlisted games to be kept")
+		}
+	})
+
+	t.Run("rotates a key whose declared secret changed", func(t *testing.T) {
+		reconciler, _, keys := newTestReconciler()
+		if _, err := reconciler.Reconcile(ctx, testDocument(), false); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		previous := activeKeys(t, keys)["ci"]
+
+		doc := testDocument()
+		rotated := "rbk_fedcba9876543210fedcba9876543210"
+		doc.APIKeys[1].Secret = rotated
+		if _, err := reconciler.Reconcile(ctx, doc, false); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+
+		if key, err := keys.Resolve(ctx, rotated); err != nil || key.ID == previous.ID {
+			t.Errorf("Expected a new key for the rotated secret, got %+v (%v)", key, err)
+		}
+		if _, err := keys.Resolve(ctx, importedSecret); err == nil {
+			t.Error("Expected the old secret to stop working")
+		}
+	})
+
+	t.Run("omitted sections are left alone", func(t *testing.T) {
+		reconciler, service, keys := newTestReconciler()
+		keys.Create(ctx, "manual", []string{"*"}, []string{models.ScopeSubmit})
+		service.BlockInitials(ctx, "QXZ")
+
+		result, err := reconciler.Reconcile(ctx, &models.BootstrapDocument{}, false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if len(result.Changes) != 0 {
+			t.Errorf("Expected no changes, got %+v", result.Changes)
+		}
+		if len(activeKeys(t, keys)) != 1 || len(service.Blocklist(ctx).Managed) != 1 {
+			t.Error("Expected keys and blocklist to be untouched")
+		}
+	})
+
+	t.Run("rejects invalid documents before changing anything", func(t *testing.T) {
+		cases := map[string]func(doc *models.BootstrapDocument){
+			"duplicate game": func(doc *models.BootstrapDocument) { doc.Games = append(doc.Games, doc.Games[0]) },
+			"oversized board": func(doc *models.BootstrapDocument) {
+				doc.Games[0].Settings.MaxEntries = models.MaxLeaderboardEntries + 1
+			},
+			"negative daily budget": func(doc *models.BootstrapDocument) {
+				doc.Games[0].Settings.DailySubmissions = -1
+			},
+			"unknown anti-cheat action": func(doc *models.BootstrapDocument) {
+				doc.Games[0].Settings.AntiCheat = &models.AntiCheatRules{MaxScore: 1000, Action: "ban"}
+			},
+			"duplicate key": func(doc *models.BootstrapDocument) { doc.APIKeys = append(doc.APIKeys, doc.APIKeys[0]) },
+			"unknown scope": func(doc *models.BootstrapDocument) { doc.APIKeys[0].Scopes = []string{"root"} },
+			"short secret":  func(doc *models.BootstrapDocument) { doc.APIKeys[1].Secret = "rbk_short" },
+			"bad initials":  func(doc *models.BootstrapDocument) { doc.BlockedInitials = []string{"TOOLONG"} },
+		}
+		for name, mutate := range cases {
+			reconciler, service, _ := newTestReconciler()
+			doc := testDocument()
+			mutate(doc)
+
+			var invalid *ValidationError
+			if _, err := reconciler.Reconcile(ctx, doc, false); !errors.As(err, &invalid) {
+				t.Errorf("%s: expected a validation error, got %v", name, err)
+			}
+			if games, _ := service.ListGames(ctx); len(games) != 0 {
+				t.Errorf("%s: expected nothing to be applied, got %v", name, games)
+			}
+		}
+	})
+}
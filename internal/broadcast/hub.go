@@ -0,0 +1,303 @@
+// Package broadcast fans leaderboard changes out to live display clients
+package broadcast
+
+import (
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// Defaults for hub options
+const (
+	DefaultBufferSize        = 16
+	DefaultHistorySize       = 64
+	DefaultSlowClientTimeout = 30 * time.Second
+)
+
+// ErrSlowClient is returned by Next after the hub disconnected a client that stopped reading
+var ErrSlowClient = errors.New("client too slow, disconnected")
+
+// ErrClosed is returned by Next after the subscription was closed
+var ErrClosed = errors.New("subscription closed")
+
+// ErrShutdown is returned by Next after the hub was closed for server shutdown
+var ErrShutdown = errors.New("server shutting down")
+
+// Hub delivers board events to every subscriber of a game without ever blocking the
+// publisher. Each subscriber gets its own send buffer; when it fills, the subscriber's
+// queued events are dropped and replaced by a single resync marker, and a subscriber
+// that stays behind for longer than the slow client timeout is disconnected.
+type Hub struct {
+	mu                sync.Mutex
+	games             map[board]*game
+	bufferSize        int
+	historySize       int
+	slowClientTimeout time.Duration
+	logger            *slog.Logger
+	now               func() time.Time
+	closed            bool
+}
+
+// board identifies one game's stream; games in different tenants may share an ID
+type board struct {
+	tenant string
+	gameID string
+}
+
+// game holds one game's subscribers and recent events for resuming clients
+type game struct {
+	subscribers map[*Subscription]struct{}
+	history     []models.BoardEvent // Oldest first, consecutive versions
+}
+
+// Option configures optional Hub behavior
+type Option func(*Hub)
+
+// WithBufferSize sets how many events may queue for one subscriber
+func WithBufferSize(size int) Option {
+	return func(h *Hub) {
+		h.bufferSize = size
+	}
+}
+
+// WithHistorySize sets how many recent events per game are kept for resuming clients
+func WithHistorySize(size int) Option {
+	return func(h *Hub) {
+		h.historySize = size
+	}
+}
+
+// WithSlowClientTimeout sets how long a subscriber may stay behind before it's disconnected
+func WithSlowClientTimeout(timeout time.Duration) Option {
+	return func(h *Hub) {
+		h.slowClientTimeout = timeout
+	}
+}
+
+// WithLogger sets the logger used to report disconnected slow clients
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *Hub) {
+		h.logger = logger
+	}
+}
+
+// NewHub creates an empty hub
+func NewHub(opts ...Option) *Hub {
+	h := &Hub{
+		games:             make(map[board]*game),
+		bufferSize:        DefaultBufferSize,
+		historySize:       DefaultHistorySize,
+		slowClientTimeout: DefaultSlowClientTimeout,
+		logger:            slog.Default(),
+		now:               time.Now,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// Subscription is one client's view of a game's event stream
+type Subscription struct {
+	hub    *Hub
+	tenant string
+	gameID string
+	events chan models.BoardEvent
+	done   chan struct{}
+
+	// Guarded by hub.mu
+	lagging      bool
+	laggingSince time.Time
+	err          error
+}
+
+// Publish records event and queues it for every subscriber of its game
+func (h *Hub) Publish(event models.BoardEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	g := h.game(board{tenant: event.Tenant, gameID: event.GameID})
+
+	// A gap in versions (e.g. another instance wrote the board) breaks replay
+	if n := len(g.history); n > 0 && g.history[n-1].Version+1 != event.Version {
+		g.history = g.history[:0]
+	}
+	g.history = append(g.history, event)
+	if len(g.history) > h.historySize {
+		g.history = append(g.history[:0], g.history[len(g.history)-h.historySize:]...)
+	}
+
+	for sub := range g.subscribers {
+		h.deliver(sub, event)
+	}
+}
+
+// Subscribe starts a subscription to the tenant's gameID. A client resuming after
+// version since is sent the events it missed, or a resync when they're no longer available.
+func (h *Hub) Subscribe(tenant, gameID string, since int64) *Subscription {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub := &Subscription{
+		hub:    h,
+		tenant: tenant,
+		gameID: gameID,
+		events: make(chan models.BoardEvent, h.bufferSize),
+		done:   make(chan struct{}),
+	}
+	if h.closed {
+		sub.err = ErrShutdown
+		close(sub.done)
+		return sub
+	}
+	g := h.game(sub.board())
+	g.subscribers[sub] = struct{}{}
+
+	if since > 0 && len(g.history) > 0 {
+		if latest := g.history[len(g.history)-1]; latest.Version > since {
+			missed := g.history
+			if oldest := g.history[0].Version; oldest > since+1 {
+				missed = nil // Fell out of history
+			} else {
+				missed = g.history[since+1-oldest:]
+			}
+
+			if missed == nil || len(missed) > h.bufferSize {
+				h.markLagging(sub, latest.Version)
+			} else {
+				for _, event := range missed {
+					sub.events <- event
+				}
+			}
+		}
+	}
+
+	return sub
+}
+
+// Subscribers returns the number of connected clients for the tenant's gameID
+func (h *Hub) Subscribers(tenant, gameID string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if g, ok := h.games[board{tenant: tenant, gameID: gameID}]; ok {
+		return len(g.subscribers)
+	}
+	return 0
+}
+
+// Close disconnects every subscriber with ErrShutdown and refuses new ones, so
+// long-lived streams don't hold up a graceful shutdown
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, g := range h.games {
+		for sub := range g.subscribers {
+			h.remove(sub, ErrShutdown)
+		}
+	}
+}
+
+// game returns the state for a board, creating it if needed; h.mu must be held
+func (h *Hub) game(key board) *game {
+	g, ok := h.games[key]
+	if !ok {
+		g = &game{subscribers: make(map[*Subscription]struct{})}
+		h.games[key] = g
+	}
+	return g
+}
+
+// deliver queues event for sub without blocking; h.mu must be held
+func (h *Hub) deliver(sub *Subscription, event models.BoardEvent) {
+	if sub.lagging {
+		// The queued resync will carry the latest board; just check for a stalled client
+		if h.now().Sub(sub.laggingSince) > h.slowClientTimeout {
+			h.logger.Warn("disconnecting slow stream client", "game_id", sub.gameID, "behind_for", h.now().Sub(sub.laggingSince).String())
+			h.remove(sub, ErrSlowClient)
+		}
+		return
+	}
+
+	select {
+	case sub.events <- event:
+	default:
+		h.markLagging(sub, event.Version)
+	}
+}
+
+// markLagging drops sub's queued events in favor of one resync marker; h.mu must be held
+func (h *Hub) markLagging(sub *Subscription, version int64) {
+	for len(sub.events) > 0 {
+		<-sub.events
+	}
+	sub.events <- models.BoardEvent{Type: models.BoardEventResync, GameID: sub.gameID, Version: version, Tenant: sub.tenant}
+	sub.lagging = true
+	sub.laggingSince = h.now()
+}
+
+// remove disconnects sub with err; h.mu must be held
+func (h *Hub) remove(sub *Subscription, err error) {
+	g, ok := h.games[sub.board()]
+	if !ok {
+		return
+	}
+	if _, ok := g.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(g.subscribers, sub)
+	sub.err = err
+	close(sub.done)
+	if len(g.subscribers) == 0 && len(g.history) == 0 {
+		delete(h.games, sub.board())
+	}
+}
+
+// Next waits for the subscription's next event. A resync is returned with the latest
+// board the hub has seen, so the client can catch up without another request.
+func (s *Subscription) Next(done <-chan struct{}) (models.BoardEvent, error) {
+	select {
+	case event := <-s.events:
+		if event.Type == models.BoardEventResync {
+			event = s.resync(event)
+		}
+		return event, nil
+	case <-s.done:
+		s.hub.mu.Lock()
+		defer s.hub.mu.Unlock()
+		return models.BoardEvent{}, s.err
+	case <-done:
+		return models.BoardEvent{}, ErrClosed
+	}
+}
+
+// resync clears the lagging state and fills in the latest known board
+func (s *Subscription) resync(event models.BoardEvent) models.BoardEvent {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.lagging = false
+	if g, ok := s.hub.games[s.board()]; ok && len(g.history) > 0 {
+		latest := g.history[len(g.history)-1]
+		event.Version = latest.Version
+		event.Leaderboard = latest.Leaderboard
+	}
+	return event
+}
+
+// board identifies the stream the subscription follows
+func (s *Subscription) board() board {
+	return board{tenant: s.tenant, gameID: s.gameID}
+}
+
+// Close ends the subscription
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s, ErrClosed)
+}
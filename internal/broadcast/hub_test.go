@@ -0,0 +1,172 @@
+package broadcast
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// boardEvent returns an update event for version of a game's board
+func boardEvent(gameID string, version int64) models.BoardEvent {
+	return models.BoardEvent{
+		Type:        models.BoardEventUpdated,
+		GameID:      gameID,
+		Version:     version,
+		Leaderboard: &models.Leaderboard{GameID: gameID, Version: version},
+	}
+}
+
+// nextEvent reads one event, failing the test instead of blocking forever
+func nextEvent(t *testing.T, sub *Subscription) models.BoardEvent {
+	t.Helper()
+
+	timeout := make(chan struct{})
+	timer := time.AfterFunc(time.Second, func() { close(timeout) })
+	defer timer.Stop()
+
+	event, err := sub.Next(timeout)
+	if err != nil {
+		t.Fatalf("Expected an event, got %v", err)
+	}
+	return event
+}
+
+func TestHub(t *testing.T) {
+	t.Run("fans events out to the game's subscribers only", func(t *testing.T) {
+		hub := NewHub()
+		pacman := hub.Subscribe("", "pacman", 0)
+		tetris := hub.Subscribe("", "tetris", 0)
+
+		hub.Publish(boardEvent("pacman", 1))
+
+		if event := nextEvent(t, pacman); event.Version != 1 || event.Type != models.BoardEventUpdated {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		if len(tetris.events) != 0 {
+			t.Error("Expected no events for another game")
+		}
+	})
+
+	t.Run("keeps tenants' games with the same ID apart", func(t *testing.T) {
+		hub := NewHub()
+		acme := hub.Subscribe("acme", "pacman", 0)
+		other := hub.Subscribe("", "pacman", 0)
+
+		event := boardEvent("pacman", 1)
+		event.Tenant = "acme"
+		hub.Publish(event)
+
+		if event := nextEvent(t, acme); event.Version != 1 {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		if len(other.events) != 0 {
+			t.Error("Expected no events for another tenant's game")
+		}
+	})
+
+	t.Run("replaces a full buffer with a resync carrying the latest board", func(t *testing.T) {
+		hub := NewHub(WithBufferSize(2))
+		stalled := hub.Subscribe("", "pacman", 0)
+		healthy := hub.Subscribe("", "pacman", 0)
+
+		for version := int64(1); version <= 5; version++ {
+			hub.Publish(boardEvent("pacman", version)) // Must never block
+			nextEvent(t, healthy)
+		}
+
+		event := nextEvent(t, stalled)
+		if event.Type != models.BoardEventResync || event.Version != 5 || event.Leaderboard == nil || event.Leaderboard.Version != 5 {
+			t.Errorf("Expected a resync to version 5, got %+v", event)
+		}
+
+		// Once resynced, the client gets updates again
+		hub.Publish(boardEvent("pacman", 6))
+		if event := nextEvent(t, stalled); event.Type != models.BoardEventUpdated || event.Version != 6 {
+			t.Errorf("Expected normal delivery after resync, got %+v", event)
+		}
+	})
+
+	t.Run("disconnects clients that stay behind", func(t *testing.T) {
+		now := time.Now()
+		hub := NewHub(WithBufferSize(1), WithSlowClientTimeout(time.Second))
+		hub.now = func() time.Time { return now }
+		stalled := hub.Subscribe("", "pacman", 0)
+
+		hub.Publish(boardEvent("pacman", 1))
+		hub.Publish(boardEvent("pacman", 2)) // Buffer full, now lagging
+		now = now.Add(2 * time.Second)
+		hub.Publish(boardEvent("pacman", 3))
+
+		if hub.Subscribers("", "pacman") != 0 {
+			t.Error("Expected the stalled client to be removed")
+		}
+
+		// The queued resync is still delivered, then the disconnect is reported
+		select {
+		case <-stalled.done:
+		default:
+			t.Fatal("Expected the subscription to be closed")
+		}
+		if _, err := stalled.Next(nil); err != nil && !errors.Is(err, ErrSlowClient) {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("replays missed events to resuming clients", func(t *testing.T) {
+		hub := NewHub()
+		for version := int64(1); version <= 4; version++ {
+			hub.Publish(boardEvent("pacman", version))
+		}
+
+		sub := hub.Subscribe("", "pacman", 2)
+		for _, want := range []int64{3, 4} {
+			if event := nextEvent(t, sub); event.Version != want || event.Type != models.BoardEventUpdated {
+				t.Errorf("Expected replay of version %d, got %+v", want, event)
+			}
+		}
+
+		if current := hub.Subscribe("", "pacman", 4); len(current.events) != 0 {
+			t.Error("Expected nothing to replay for an up-to-date client")
+		}
+	})
+
+	t.Run("resyncs clients resuming from beyond the history", func(t *testing.T) {
+		hub := NewHub(WithHistorySize(2))
+		for version := int64(1); version <= 5; version++ {
+			hub.Publish(boardEvent("pacman", version))
+		}
+
+		event := nextEvent(t, hub.Subscribe("", "pacman", 1))
+		if event.Type != models.BoardEventResync || event.Version != 5 {
+			t.Errorf("Expected a resync to version 5, got %+v", event)
+		}
+	})
+
+	t.Run("stops on close", func(t *testing.T) {
+		hub := NewHub()
+		sub := hub.Subscribe("", "pacman", 0)
+		sub.Close()
+
+		if _, err := sub.Next(nil); !errors.Is(err, ErrClosed) {
+			t.Errorf("Expected ErrClosed, got %v", err)
+		}
+		if hub.Subscribers("", "pacman") != 0 {
+			t.Error("Expected the subscriber to be removed")
+		}
+	})
+
+	t.Run("disconnects everyone on shutdown", func(t *testing.T) {
+		hub := NewHub()
+		sub := hub.Subscribe("", "pacman", 0)
+		hub.Close()
+
+		if _, err := sub.Next(nil); !errors.Is(err, ErrShutdown) {
+			t.Errorf("Expected ErrShutdown, got %v", err)
+		}
+		if _, err := hub.Subscribe("", "pacman", 0).Next(nil); !errors.Is(err, ErrShutdown) {
+			t.Errorf("Expected new subscriptions to be refused, got %v", err)
+		}
+	})
+}
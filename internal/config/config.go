@@ -2,55 +2,248 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
 )
 
 // Config holds all application configuration
 type Config struct {
 	// Server configuration
-	Port        string
-	Environment string
+	Port            string
+	GRPCPort        string
+	Environment     string
+	TLSCertFile     string
+	TLSKeyFile      string
+	ShutdownTimeout time.Duration
+	DrainDelay      time.Duration // How long readiness fails before a shutdown stops accepting connections
+
+	// Logging configuration
+	LogLevel  string
+	LogFormat string
 
 	// Database configuration
-	DatabaseURL     string
-	DatabaseTimeout time.Duration
+	DatabaseBackend          string // valkey, sqlite for a local file on edge instances, or memory for a throwaway development database
+	SQLitePath               string
+	DatabaseURL              string        // The Valkey URI, from VALKEY_URI, REDIS_URL, DATABASE_URL and the other variables the database package reads
+	DatabaseTimeout          time.Duration // Bounds dialing Valkey and each read and write
+	DatabaseTopologyInterval time.Duration // How often to check a cluster or Sentinel for failovers
+
+	// Readiness probe configuration
+	ReadinessTimeout time.Duration
 
 	// Authentication configuration
-	APIKey string
+	APIKey          string
+	AdminSessionTTL time.Duration // How long an admin UI sign-in lasts
 
-	// Bugsnag configuration
+	// JWT bearer authentication, alongside API keys, for deployments behind an identity
+	// provider; enabled by JWT_SECRET or JWT_PUBLIC_KEY_FILE
+	JWTAlgorithm     string // HS256 or RS256
+	JWTSecret        string // Verifies HS256 tokens
+	JWTPublicKeyFile string // PEM public key or certificate verifying RS256 tokens
+	JWTIssuer        string
+	JWTAudience      string
+	JWTGamesClaim    string // Claim listing the games a token may act on
+	JWTRoleClaim     string // Claim holding the token's submitter or admin role
+
+	// Error reporting configuration (Bugsnag or Sentry)
+	ErrorReporter string
 	BugsnagAPIKey string
+	SentryDSN     string
+	Release       string
 
 	// Leaderboard configuration
 	MaxScoreEntries int
 	MaxScoreValue   int64
 	MaxGameIDLength int
+	BlockedInitials []string
+	MaxGamesPerKey  int // Games each stored API key may create, 0 is unlimited
+
+	// Object storage configuration (S3-compatible)
+	ObjectStoreEndpoint        string
+	ObjectStoreRegion          string
+	ObjectStoreBucket          string
+	ObjectStoreAccessKeyID     string
+	ObjectStoreSecretAccessKey string
+	ObjectStorePathStyle       bool
+
+	// Scheduled export configuration
+	ExportInterval time.Duration
+	ExportPrefix   string
+
+	// Retention pruning configuration
+	RetentionInterval    time.Duration
+	RetentionHistoryDays int // Default days of raw history to keep, 0 keeps everything
+	RetentionMaxScores   int // Default newest scores to keep per game, 0 keeps everything
+
+	// How long moderation deletions of scores and players can be undone, 0 for not at all
+	ModerationUndoWindow time.Duration
+
+	// Public receipt lookup rate limit (per client IP)
+	ReceiptLookupRate  float64
+	ReceiptLookupBurst int
+
+	// API rate limit, shared by every replica, per API key (or per game when
+	// authentication is disabled); API_RATE_LIMIT=0 disables it
+	APIRateLimit          float64
+	APIRateBurst          int
+	APIRateLimitOverrides string // name=rate:burst pairs for key IDs, key names or game IDs
+
+	// Clock skew monitoring between the server and the database
+	ClockSkewThreshold time.Duration
+	ClockSkewInterval  time.Duration
+
+	// Live leaderboard streams
+	StreamBufferSize        int
+	StreamSlowClientTimeout time.Duration
+	StreamTokenTTL          time.Duration
+
+	// Deliveries the development webhook log keeps, 0 disables it
+	WebhookLogSize int
+
+	// WebAssembly module run on each submission, for custom validation, transformation
+	// or side effects
+	SubmissionHookWASM    string
+	SubmissionHookTimeout time.Duration // How long each call into the module may run
+
+	// Venue display monitoring
+	DisplayOfflineAfter time.Duration
+	DisplayAlertURL     string // Receives a POST when a display device goes dark or comes back
+
+	// Inbound email gateway for legacy cabinets
+	MailgunSigningKey   string
+	SESWebhookSecret    string
+	EmailAllowedSenders []string
+
+	// Edge mode, for cabinets far from the central server: reads are served locally and
+	// submissions forwarded to the central instance in the background
+	EdgeUpstreamURL    string
+	EdgeUpstreamAPIKey string
+	EdgeSyncInterval   time.Duration // How often the central leaderboards are merged in
+	EdgeGames          []string      // Mirrored before their first local score
 }
 
 // Load loads configuration from environment variables with sensible defaults
 func Load() (*Config, error) {
 	config := &Config{
 		// Server defaults
-		Port:        getEnv("PORT", "8080"),
-		Environment: getEnv("ENVIRONMENT", "development"),
+		Port:            getEnv("PORT", "8080"),
+		GRPCPort:        getEnv("GRPC_PORT", "9090"),
+		Environment:     getEnv("ENVIRONMENT", "development"),
+		TLSCertFile:     getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:      getEnv("TLS_KEY_FILE", ""),
+		ShutdownTimeout: getDurationEnv("SHUTDOWN_TIMEOUT", 30*time.Second),
+		DrainDelay:      getDurationEnv("SHUTDOWN_DRAIN_DELAY", 0),
+
+		// Logging defaults (format defaults to JSON in production, text otherwise)
+		LogLevel:  strings.ToLower(getEnv("LOG_LEVEL", "info")),
+		LogFormat: strings.ToLower(getEnv("LOG_FORMAT", "")),
 
 		// Database defaults - check multiple common environment variable names
-		DatabaseURL:     getDatabaseURL(),
-		DatabaseTimeout: getDurationEnv("DATABASE_TIMEOUT", 5*time.Second),
+		DatabaseBackend:          strings.ToLower(getEnv("DATABASE_BACKEND", "valkey")),
+		DatabaseURL:              getDatabaseURL(),
+		SQLitePath:               getEnv("SQLITE_PATH", "rawboard.db"),
+		DatabaseTimeout:          getDurationEnv("DATABASE_TIMEOUT", 5*time.Second),
+		DatabaseTopologyInterval: getDurationEnv("VALKEY_TOPOLOGY_INTERVAL", 5*time.Second),
+
+		// Readiness probe defaults, well inside typical orchestrator probe timeouts
+		ReadinessTimeout: getDurationEnv("READINESS_TIMEOUT", time.Second),
 
 		// Authentication
-		APIKey: getEnv("RAWBOARD_API_KEY", ""),
+		APIKey:          getEnv("RAWBOARD_API_KEY", ""),
+		AdminSessionTTL: getDurationEnv("ADMIN_SESSION_TTL", 12*time.Hour),
 
-		// Bugsnag defaults
+		// JWT authentication (disabled unless a secret or public key is configured)
+		JWTAlgorithm:     strings.ToUpper(getEnv("JWT_ALGORITHM", "HS256")),
+		JWTSecret:        getEnv("JWT_SECRET", ""),
+		JWTPublicKeyFile: getEnv("JWT_PUBLIC_KEY_FILE", ""),
+		JWTIssuer:        getEnv("JWT_ISSUER", ""),
+		JWTAudience:      getEnv("JWT_AUDIENCE", ""),
+		JWTGamesClaim:    getEnv("JWT_GAMES_CLAIM", "games"),
+		JWTRoleClaim:     getEnv("JWT_ROLE_CLAIM", "role"),
+
+		// Error reporting defaults (disabled unless a provider is configured)
+		ErrorReporter: strings.ToLower(getEnv("ERROR_REPORTER", "")),
 		BugsnagAPIKey: getEnv("BUGSNAG_API_KEY", ""),
+		SentryDSN:     getEnv("SENTRY_DSN", ""),
+		Release:       getEnv("RELEASE", "1.0.0"),
 
 		// Leaderboard defaults (traditional arcade values)
 		MaxScoreEntries: getIntEnv("MAX_SCORE_ENTRIES", 10),
 		MaxScoreValue:   getInt64Env("MAX_SCORE_VALUE", 999999999),
 		MaxGameIDLength: getIntEnv("MAX_GAME_ID_LENGTH", 50),
+		BlockedInitials: getListEnv("RAWBOARD_BLOCKED_INITIALS"),
+		MaxGamesPerKey:  getIntEnv("MAX_GAMES_PER_KEY", 0),
+
+		// Object storage (exports are disabled unless a bucket is configured)
+		ObjectStoreEndpoint:        getEnv("OBJECT_STORE_ENDPOINT", ""),
+		ObjectStoreRegion:          getEnv("OBJECT_STORE_REGION", "us-east-1"),
+		ObjectStoreBucket:          getEnv("OBJECT_STORE_BUCKET", ""),
+		ObjectStoreAccessKeyID:     getEnv("OBJECT_STORE_ACCESS_KEY_ID", ""),
+		ObjectStoreSecretAccessKey: getEnv("OBJECT_STORE_SECRET_ACCESS_KEY", ""),
+		ObjectStorePathStyle:       getBoolEnv("OBJECT_STORE_PATH_STYLE", false),
+
+		// Scheduled export defaults
+		ExportInterval: getDurationEnv("EXPORT_INTERVAL", 24*time.Hour),
+		ExportPrefix:   getEnv("EXPORT_PREFIX", "exports"),
+
+		// Retention pruning defaults (games may set their own policy)
+		RetentionInterval:    getDurationEnv("RETENTION_INTERVAL", time.Hour),
+		RetentionHistoryDays: getIntEnv("RETENTION_HISTORY_DAYS", 0),
+		RetentionMaxScores:   getIntEnv("RETENTION_MAX_SCORES", 0),
+
+		ModerationUndoWindow: getDurationEnv("MODERATION_UNDO_WINDOW", 7*24*time.Hour),
+
+		// Public receipt lookup defaults
+		ReceiptLookupRate:  getFloatEnv("RECEIPT_LOOKUP_RATE", 1),
+		ReceiptLookupBurst: getIntEnv("RECEIPT_LOOKUP_BURST", 5),
+
+		// API rate limit defaults
+		APIRateLimit:          getFloatEnv("API_RATE_LIMIT", 20),
+		APIRateBurst:          getIntEnv("API_RATE_BURST", 40),
+		APIRateLimitOverrides: getEnv("API_RATE_LIMIT_OVERRIDES", ""),
+
+		// Clock skew monitoring defaults
+		ClockSkewThreshold: getDurationEnv("CLOCK_SKEW_THRESHOLD", 2*time.Second),
+		ClockSkewInterval:  getDurationEnv("CLOCK_SKEW_INTERVAL", 5*time.Minute),
+
+		// Live stream defaults
+		StreamBufferSize:        getIntEnv("STREAM_BUFFER_SIZE", 16),
+		StreamSlowClientTimeout: getDurationEnv("STREAM_SLOW_CLIENT_TIMEOUT", 30*time.Second),
+		StreamTokenTTL:          getDurationEnv("STREAM_TOKEN_TTL", 5*time.Minute),
+
+		WebhookLogSize: getIntEnv("WEBHOOK_LOG_SIZE", 50),
+
+		// Submission hook defaults (disabled)
+		SubmissionHookWASM:    getEnv("SUBMISSION_HOOK_WASM", ""),
+		SubmissionHookTimeout: getDurationEnv("SUBMISSION_HOOK_TIMEOUT", 100*time.Millisecond),
+
+		// Display monitoring defaults
+		DisplayOfflineAfter: getDurationEnv("DISPLAY_OFFLINE_AFTER", 2*time.Minute),
+		DisplayAlertURL:     getEnv("DISPLAY_ALERT_URL", ""),
+
+		// Email gateway defaults (disabled)
+		MailgunSigningKey:   getEnv("MAILGUN_SIGNING_KEY", ""),
+		SESWebhookSecret:    getEnv("SES_WEBHOOK_SECRET", ""),
+		EmailAllowedSenders: getListEnv("EMAIL_ALLOWED_SENDERS"),
+
+		// Edge mode defaults (disabled)
+		EdgeUpstreamURL:    strings.TrimSuffix(getEnv("EDGE_UPSTREAM_URL", ""), "/"),
+		EdgeUpstreamAPIKey: getEnv("EDGE_UPSTREAM_API_KEY", ""),
+		EdgeSyncInterval:   getDurationEnv("EDGE_SYNC_INTERVAL", 30*time.Second),
+		EdgeGames:          getListEnv("EDGE_GAMES"),
+	}
+
+	if config.LogFormat == "" {
+		config.LogFormat = "text"
+		if config.IsProduction() {
+			config.LogFormat = "json"
+		}
 	}
 
 	// Validate critical configuration
@@ -67,10 +260,54 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("PORT cannot be empty")
 	}
 
+	if c.GRPCPort == c.Port {
+		return fmt.Errorf("GRPC_PORT must differ from PORT")
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must be positive")
+	}
+
+	if c.DrainDelay < 0 {
+		return fmt.Errorf("SHUTDOWN_DRAIN_DELAY cannot be negative")
+	}
+
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		return fmt.Errorf("LOG_LEVEL must be one of debug, info, warn, error")
+	}
+
+	if c.LogFormat != "json" && c.LogFormat != "text" {
+		return fmt.Errorf("LOG_FORMAT must be json or text")
+	}
+
+	switch c.DatabaseBackend {
+	case "", "valkey":
+	case "sqlite":
+		if c.SQLitePath == "" {
+			return fmt.Errorf("SQLITE_PATH cannot be empty")
+		}
+	case "memory":
+		if c.IsProduction() {
+			return fmt.Errorf("DATABASE_BACKEND=memory is for development only")
+		}
+	default:
+		return fmt.Errorf("DATABASE_BACKEND must be valkey, sqlite or memory")
+	}
+
 	if c.DatabaseTimeout <= 0 {
 		return fmt.Errorf("DATABASE_TIMEOUT must be positive")
 	}
 
+	if c.DatabaseTopologyInterval < time.Second {
+		return fmt.Errorf("VALKEY_TOPOLOGY_INTERVAL must be at least 1s")
+	}
+
+	if c.ReadinessTimeout <= 0 {
+		return fmt.Errorf("READINESS_TIMEOUT must be positive")
+	}
+
 	if c.MaxScoreEntries <= 0 || c.MaxScoreEntries > 100 {
 		return fmt.Errorf("MAX_SCORE_ENTRIES must be between 1 and 100")
 	}
@@ -83,6 +320,144 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("MAX_GAME_ID_LENGTH must be between 1 and 100")
 	}
 
+	if c.MaxGamesPerKey < 0 {
+		return fmt.Errorf("MAX_GAMES_PER_KEY must not be negative")
+	}
+
+	if c.HasObjectStore() && c.ExportInterval < time.Minute {
+		return fmt.Errorf("EXPORT_INTERVAL must be at least 1m")
+	}
+
+	if c.RetentionInterval < time.Minute {
+		return fmt.Errorf("RETENTION_INTERVAL must be at least 1m")
+	}
+
+	if c.RetentionHistoryDays < 0 {
+		return fmt.Errorf("RETENTION_HISTORY_DAYS must not be negative")
+	}
+
+	if c.RetentionMaxScores < 0 {
+		return fmt.Errorf("RETENTION_MAX_SCORES must not be negative")
+	}
+
+	for _, initials := range c.BlockedInitials {
+		if len(initials) != 3 {
+			return fmt.Errorf("RAWBOARD_BLOCKED_INITIALS entries must be exactly 3 characters, got %q", initials)
+		}
+	}
+
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		return fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if c.ReceiptLookupRate <= 0 || c.ReceiptLookupBurst <= 0 {
+		return fmt.Errorf("RECEIPT_LOOKUP_RATE and RECEIPT_LOOKUP_BURST must be positive")
+	}
+
+	if c.APIRateLimit < 0 || (c.APIRateLimit > 0 && c.APIRateBurst < 1) {
+		return fmt.Errorf("API_RATE_LIMIT must not be negative, and API_RATE_BURST must be at least 1")
+	}
+	if _, err := c.RateLimitOverrides(); err != nil {
+		return err
+	}
+
+	if c.ClockSkewThreshold <= 0 {
+		return fmt.Errorf("CLOCK_SKEW_THRESHOLD must be positive")
+	}
+
+	if c.ClockSkewInterval < 10*time.Second {
+		return fmt.Errorf("CLOCK_SKEW_INTERVAL must be at least 10s")
+	}
+
+	if c.ModerationUndoWindow < 0 {
+		return fmt.Errorf("MODERATION_UNDO_WINDOW must not be negative")
+	}
+
+	if c.WebhookLogSize < 0 {
+		return fmt.Errorf("WEBHOOK_LOG_SIZE must not be negative")
+	}
+
+	if c.SubmissionHookWASM != "" && c.SubmissionHookTimeout <= 0 {
+		return fmt.Errorf("SUBMISSION_HOOK_TIMEOUT must be positive")
+	}
+
+	if c.StreamBufferSize < 1 {
+		return fmt.Errorf("STREAM_BUFFER_SIZE must be at least 1")
+	}
+
+	if c.StreamSlowClientTimeout <= 0 {
+		return fmt.Errorf("STREAM_SLOW_CLIENT_TIMEOUT must be positive")
+	}
+
+	if c.AdminSessionTTL <= 0 {
+		return fmt.Errorf("ADMIN_SESSION_TTL must be positive")
+	}
+
+	if c.HasJWT() {
+		if !c.HasAPIKey() {
+			return fmt.Errorf("JWT authentication needs RAWBOARD_API_KEY, without which authentication is disabled")
+		}
+		switch c.JWTAlgorithm {
+		case "HS256":
+			if len(c.JWTSecret) < 32 {
+				return fmt.Errorf("JWT_SECRET must be at least 32 characters for HS256")
+			}
+		case "RS256":
+			if c.JWTPublicKeyFile == "" {
+				return fmt.Errorf("JWT_PUBLIC_KEY_FILE is required for RS256")
+			}
+		default:
+			return fmt.Errorf("JWT_ALGORITHM must be HS256 or RS256")
+		}
+		if c.JWTIssuer == "" || c.JWTAudience == "" {
+			return fmt.Errorf("JWT_ISSUER and JWT_AUDIENCE are required for JWT authentication")
+		}
+	}
+
+	if c.StreamTokenTTL <= 0 {
+		return fmt.Errorf("STREAM_TOKEN_TTL must be positive")
+	}
+
+	if c.DisplayOfflineAfter < 10*time.Second {
+		return fmt.Errorf("DISPLAY_OFFLINE_AFTER must be at least 10s")
+	}
+
+	if c.DisplayAlertURL != "" {
+		if u, err := url.Parse(c.DisplayAlertURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("DISPLAY_ALERT_URL must be an absolute http or https URL")
+		}
+	}
+
+	switch c.ErrorReporter {
+	case "", "none":
+	case "bugsnag":
+		if c.BugsnagAPIKey == "" {
+			return fmt.Errorf("BUGSNAG_API_KEY is required when ERROR_REPORTER is bugsnag")
+		}
+	case "sentry":
+		if c.SentryDSN == "" {
+			return fmt.Errorf("SENTRY_DSN is required when ERROR_REPORTER is sentry")
+		}
+	default:
+		return fmt.Errorf("ERROR_REPORTER must be bugsnag, sentry or none")
+	}
+	if c.ErrorReporter == "" && c.BugsnagAPIKey != "" && c.SentryDSN != "" {
+		return fmt.Errorf("both BUGSNAG_API_KEY and SENTRY_DSN are set, choose one with ERROR_REPORTER")
+	}
+
+	if c.HasEmailGateway() && len(c.EmailAllowedSenders) == 0 {
+		return fmt.Errorf("EMAIL_ALLOWED_SENDERS is required when the email gateway is enabled")
+	}
+
+	if c.IsEdge() {
+		if u, err := url.Parse(c.EdgeUpstreamURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("EDGE_UPSTREAM_URL must be an absolute http or https URL")
+		}
+		if c.EdgeSyncInterval < time.Second {
+			return fmt.Errorf("EDGE_SYNC_INTERVAL must be at least 1s")
+		}
+	}
+
 	return nil
 }
 
@@ -96,14 +471,82 @@ func (c *Config) IsDevelopment() bool {
 	return c.Environment == "development"
 }
 
+// InMemoryDatabase returns true if data is kept in process memory instead of Valkey
+func (c *Config) InMemoryDatabase() bool {
+	return c.DatabaseBackend == "memory"
+}
+
+// SQLiteDatabase returns true if data is kept in a local SQLite file instead of Valkey
+func (c *Config) SQLiteDatabase() bool {
+	return c.DatabaseBackend == "sqlite"
+}
+
+// IsEdge returns true if submissions are forwarded to a central instance
+func (c *Config) IsEdge() bool {
+	return c.EdgeUpstreamURL != ""
+}
+
 // HasAPIKey returns true if API key authentication is configured
 func (c *Config) HasAPIKey() bool {
 	return c.APIKey != ""
 }
 
-// HasBugsnag returns true if Bugsnag monitoring is configured
-func (c *Config) HasBugsnag() bool {
-	return c.BugsnagAPIKey != ""
+// HasJWT returns true if JWT bearer authentication is configured
+func (c *Config) HasJWT() bool {
+	return c.JWTSecret != "" || c.JWTPublicKeyFile != ""
+}
+
+// HasTLS returns true if the server should serve HTTPS itself
+func (c *Config) HasTLS() bool {
+	return c.TLSCertFile != ""
+}
+
+// ErrorReportingProvider returns "bugsnag" or "sentry", following ERROR_REPORTER or
+// else whichever provider has credentials, or "" when error reporting is disabled
+func (c *Config) ErrorReportingProvider() string {
+	switch {
+	case c.ErrorReporter == "none":
+		return ""
+	case c.ErrorReporter != "":
+		return c.ErrorReporter
+	case c.SentryDSN != "":
+		return "sentry"
+	case c.BugsnagAPIKey != "":
+		return "bugsnag"
+	default:
+		return ""
+	}
+}
+
+// RateLimitOverrides parses API_RATE_LIMIT_OVERRIDES, such as
+// "tournament-desk=100:200,pacman=5:10", into limits by key ID, key name or game ID
+func (c *Config) RateLimitOverrides() (map[string]models.RateLimit, error) {
+	overrides := map[string]models.RateLimit{}
+	for _, entry := range strings.Split(c.APIRateLimitOverrides, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+
+		name, limit, ok := strings.Cut(entry, "=")
+		rate, burst, hasBurst := strings.Cut(limit, ":")
+		rps, rateErr := strconv.ParseFloat(rate, 64)
+		size, burstErr := strconv.Atoi(burst)
+		if !ok || !hasBurst || name == "" || rateErr != nil || burstErr != nil || rps <= 0 || size < 1 {
+			return nil, fmt.Errorf("API_RATE_LIMIT_OVERRIDES entries must look like name=rate:burst with a positive rate and burst, got %q", entry)
+		}
+		overrides[strings.TrimSpace(name)] = models.RateLimit{RequestsPerSecond: rps, Burst: size}
+	}
+	return overrides, nil
+}
+
+// HasEmailGateway returns true if an inbound email provider is configured
+func (c *Config) HasEmailGateway() bool {
+	return c.MailgunSigningKey != "" || c.SESWebhookSecret != ""
+}
+
+// HasObjectStore returns true if S3-compatible object storage is configured
+func (c *Config) HasObjectStore() bool {
+	return c.ObjectStoreBucket != ""
 }
 
 // Helper functions for environment variable parsing
@@ -142,42 +585,38 @@ func getFloatEnv(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
-func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+func getBoolEnv(key string, defaultValue bool) bool {
 	if value := os.Getenv(key); value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil {
+		if parsed, err := strconv.ParseBool(value); err == nil {
 			return parsed
 		}
 	}
 	return defaultValue
 }
 
-// getDatabaseURL tries multiple common environment variable names for database connection
-func getDatabaseURL() string {
-	// Try various common environment variable names
-	if url := os.Getenv("DATABASE_URL"); url != "" {
-		return url
-	}
-	if url := os.Getenv("REDIS_URL"); url != "" {
-		return url
-	}
-	if url := os.Getenv("VALKEY_URL"); url != "" {
-		// If it doesn't start with redis://, add the prefix
-		if !strings.HasPrefix(url, "redis://") {
-			return "redis://" + url
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
 		}
-		return url
-	}
-	if url := os.Getenv("VALKEY_URI"); url != "" {
-		return url
 	}
-	// Try building from component parts
-	if host := os.Getenv("REDIS_HOST"); host != "" {
-		port := os.Getenv("REDIS_PORT")
-		if port == "" {
-			port = "6379"
+	return defaultValue
+}
+
+// getListEnv parses a comma-separated list, upper-casing and trimming each entry
+func getListEnv(key string) []string {
+	values := []string{}
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.ToUpper(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
 		}
-		return "redis://" + host + ":" + port
 	}
-	// Default fallback
-	return "localhost:6379"
+	return values
+}
+
+// getDatabaseURL finds the Valkey URI the way the database package does, so the
+// configuration shows the URI the server connects to
+func getDatabaseURL() string {
+	uri, _ := database.ConnectionURI()
+	return uri
 }
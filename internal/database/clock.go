@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is implemented by databases that can report their own time
+type Clock interface {
+	Time(ctx context.Context) (time.Time, error)
+}
+
+// MeasureSkew returns how far the local clock is ahead of the database clock
+// (negative when behind), comparing against the midpoint of the round trip so
+// network latency doesn't count as skew
+func MeasureSkew(ctx context.Context, clock Clock) (time.Duration, error) {
+	sent := time.Now()
+	dbTime, err := clock.Time(ctx)
+	if err != nil {
+		return 0, err
+	}
+	received := time.Now()
+
+	midpoint := sent.Add(received.Sub(sent) / 2)
+	return midpoint.Sub(dbTime), nil
+}
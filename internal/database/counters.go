@@ -0,0 +1,26 @@
+package database
+
+import (
+	"context"
+
+	"rawboard/internal/logging"
+)
+
+// Counters is implemented by databases with atomic counters, which hand out sequence
+// numbers that stay monotonic across replicas
+type Counters interface {
+	// Incr adds one to the counter at key, starting from zero, and returns the new value
+	Incr(ctx context.Context, key string) (int64, error)
+}
+
+func (v *ValkeyDB) Incr(ctx context.Context, key string) (int64, error) {
+	var value int64
+	err := v.withRetry(ctx, key, func() (err error) {
+		value, err = v.client.Incr(ctx, key).Result()
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database incr failed", "key", key, "error", err)
+	}
+	return value, err
+}
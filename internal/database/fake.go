@@ -0,0 +1,488 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Op names a database operation for Fake error injection
+type Op string
+
+// Operations Fake can fail
+const (
+	OpSet   Op = "set"
+	OpSetNX Op = "setnx"
+	OpGet   Op = "get"
+	OpPing  Op = "ping"
+	OpTime  Op = "time"
+
+	OpZAdd   Op = "zadd"
+	OpZRange Op = "zrange"
+	OpZCard  Op = "zcard"
+	OpDel    Op = "del"
+
+	OpPublish   Op = "publish"
+	OpSubscribe Op = "subscribe"
+
+	OpTakeToken Op = "take_token"
+	OpIncr      Op = "incr"
+
+	OpXAdd   Op = "xadd"
+	OpXRange Op = "xrange"
+
+	OpScan Op = "scan"
+)
+
+// fakeSubscriberBuffer is how many messages a Fake subscriber may have waiting before
+// further messages to it are dropped
+const fakeSubscriberBuffer = 64
+
+// Fake is an in-memory DB, Clock, SortedSets, PubSub, RateLimiter, Counters, Streams and Scanner for unit tests. It behaves like ValkeyDB
+// for the calls rawboard makes: values are stored as strings, missing keys return
+// redis.Nil and calls after Close return redis.ErrClosed. Failures can be injected per
+// operation. It also backs the server's in-memory development database.
+type Fake struct {
+	mu          sync.Mutex
+	data        map[string]string
+	sortedSets  map[string]map[string]float64
+	streams     map[string][]StreamEntry
+	subscribers map[string][]chan string
+	buckets     map[string]*tokenBucket
+	failures    []*failure
+	calls       map[Op]int
+	clockOffset time.Duration
+	closed      bool
+}
+
+// failure is an injected error for calls to op on keys starting with prefix
+type failure struct {
+	op        Op
+	prefix    string
+	err       error
+	remaining int // Calls left to fail, or -1 for every call
+}
+
+// NewFake creates an empty fake database
+func NewFake() *Fake {
+	return &Fake{
+		data:        make(map[string]string),
+		sortedSets:  make(map[string]map[string]float64),
+		streams:     make(map[string][]StreamEntry),
+		subscribers: make(map[string][]chan string),
+		buckets:     make(map[string]*tokenBucket),
+		calls:       make(map[Op]int),
+	}
+}
+
+// Fail makes every call to op return err until Heal
+func (f *Fake) Fail(op Op, err error) {
+	f.inject(&failure{op: op, err: err, remaining: -1})
+}
+
+// FailNext makes only the next call to op return err
+func (f *Fake) FailNext(op Op, err error) {
+	f.inject(&failure{op: op, err: err, remaining: 1})
+}
+
+// FailKey makes calls to op on keys starting with prefix return err until Heal
+func (f *Fake) FailKey(op Op, prefix string, err error) {
+	f.inject(&failure{op: op, prefix: prefix, err: err, remaining: -1})
+}
+
+// Heal removes every injected failure
+func (f *Fake) Heal() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = nil
+}
+
+// Calls returns how many times op was called, including failed calls
+func (f *Fake) Calls(op Op) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[op]
+}
+
+// Keys returns the stored keys starting with prefix in sorted order
+func (f *Fake) Keys(prefix string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys := []string{}
+	for key := range f.data {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ScanKeys returns the stored keys starting with prefix
+func (f *Fake) ScanKeys(ctx context.Context, prefix string) ([]string, error) {
+	f.mu.Lock()
+	if err := f.call(OpScan, prefix); err != nil {
+		f.mu.Unlock()
+		return nil, err
+	}
+	f.mu.Unlock()
+	return f.Keys(prefix), nil
+}
+
+// Subscribers returns how many subscriptions to channel are open
+func (f *Fake) Subscribers(channel string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subscribers[channel])
+}
+
+// Reset removes every stored value, sorted set, stream and rate limit bucket. Subscriptions stay
+// open, like they would across a FLUSHALL.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data = make(map[string]string)
+	f.sortedSets = make(map[string]map[string]float64)
+	f.streams = make(map[string][]StreamEntry)
+	f.buckets = make(map[string]*tokenBucket)
+}
+
+// SetClockOffset sets how far the fake's Time is ahead of the local clock (negative for behind)
+func (f *Fake) SetClockOffset(offset time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.clockOffset = offset
+}
+
+func (f *Fake) Set(ctx context.Context, key string, value interface{}) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpSet, key); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case string:
+		f.data[key] = v
+	case []byte:
+		f.data[key] = string(v)
+	default:
+		f.data[key] = fmt.Sprint(v)
+	}
+	return nil
+}
+
+func (f *Fake) SetNX(ctx context.Context, key string, value interface{}) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpSetNX, key); err != nil {
+		return false, err
+	}
+
+	if _, exists := f.data[key]; exists {
+		return false, nil
+	}
+	switch v := value.(type) {
+	case string:
+		f.data[key] = v
+	case []byte:
+		f.data[key] = string(v)
+	default:
+		f.data[key] = fmt.Sprint(v)
+	}
+	return true, nil
+}
+
+func (f *Fake) Get(ctx context.Context, key string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpGet, key); err != nil {
+		return "", err
+	}
+
+	value, ok := f.data[key]
+	if !ok {
+		return "", redis.Nil
+	}
+	return value, nil
+}
+
+func (f *Fake) Ping(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.call(OpPing, "")
+}
+
+// Time returns the local clock shifted by the configured offset
+func (f *Fake) Time(ctx context.Context) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpTime, ""); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(f.clockOffset), nil
+}
+
+func (f *Fake) ZAdd(ctx context.Context, key string, members ...ZMember) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpZAdd, key); err != nil {
+		return err
+	}
+
+	set, ok := f.sortedSets[key]
+	if !ok {
+		set = make(map[string]float64)
+		f.sortedSets[key] = set
+	}
+	for _, m := range members {
+		set[m.Member] = m.Score
+	}
+	return nil
+}
+
+// ZRangeByScore orders members by score, then lexicographically, like Valkey
+func (f *Fake) ZRangeByScore(ctx context.Context, key string, query ZRange) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpZRange, key); err != nil {
+		return nil, err
+	}
+
+	members := []ZMember{}
+	for member, score := range f.sortedSets[key] {
+		if score >= query.Min && score <= query.Max {
+			members = append(members, ZMember{Score: score, Member: member})
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].Score != members[j].Score {
+			return members[i].Score < members[j].Score
+		}
+		return members[i].Member < members[j].Member
+	})
+	if query.Reverse {
+		for i, j := 0, len(members)-1; i < j; i, j = i+1, j-1 {
+			members[i], members[j] = members[j], members[i]
+		}
+	}
+
+	result := []string{}
+	for i := query.Offset; i < int64(len(members)); i++ {
+		if query.Count > 0 && int64(len(result)) == query.Count {
+			break
+		}
+		result = append(result, members[i].Member)
+	}
+	return result, nil
+}
+
+func (f *Fake) ZCard(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpZCard, key); err != nil {
+		return 0, err
+	}
+	return int64(len(f.sortedSets[key])), nil
+}
+
+func (f *Fake) Del(ctx context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpDel, key); err != nil {
+		return err
+	}
+	delete(f.data, key)
+	delete(f.sortedSets, key)
+	delete(f.streams, key)
+	return nil
+}
+
+// Publish delivers message to every open subscription to channel, dropping it for
+// subscribers that have fallen behind
+func (f *Fake) Publish(ctx context.Context, channel, message string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpPublish, channel); err != nil {
+		return err
+	}
+
+	for _, sub := range f.subscribers[channel] {
+		select {
+		case sub <- message:
+		default:
+		}
+	}
+	return nil
+}
+
+func (f *Fake) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpSubscribe, channel); err != nil {
+		return nil, err
+	}
+
+	sub := make(chan string, fakeSubscriberBuffer)
+	f.subscribers[channel] = append(f.subscribers[channel], sub)
+	go func() {
+		<-ctx.Done()
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		subs := f.subscribers[channel]
+		for i := range subs {
+			if subs[i] == sub {
+				f.subscribers[channel] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(sub)
+	}()
+	return sub, nil
+}
+
+// TakeToken keeps token buckets on the fake's clock
+func (f *Fake) TakeToken(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpTakeToken, key); err != nil {
+		return false, 0, err
+	}
+
+	now := time.Now().Add(f.clockOffset)
+	bucket, ok := f.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), updated: now}
+		f.buckets[key] = bucket
+	}
+	allowed, wait := bucket.take(now, rate, burst)
+	return allowed, wait, nil
+}
+
+// Incr keeps counters as decimal strings, like Valkey
+func (f *Fake) Incr(ctx context.Context, key string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpIncr, key); err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(f.data[key], 10, 64)
+	if err != nil && f.data[key] != "" {
+		return 0, fmt.Errorf("ERR value is not an integer or out of range")
+	}
+	value++
+	f.data[key] = strconv.FormatInt(value, 10)
+	return value, nil
+}
+
+// XAdd stamps entries with the fake's clock, like Valkey, and trims exactly to maxLen
+func (f *Fake) XAdd(ctx context.Context, key string, maxLen int64, fields map[string]string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpXAdd, key); err != nil {
+		return "", err
+	}
+
+	ms, seq := time.Now().Add(f.clockOffset).UnixMilli(), int64(0)
+	stream := f.streams[key]
+	if len(stream) > 0 {
+		lastMS, lastSeq := parseStreamID(stream[len(stream)-1].ID)
+		if ms <= lastMS {
+			ms, seq = lastMS, lastSeq+1
+		}
+	}
+	copied := make(map[string]string, len(fields))
+	for name, value := range fields {
+		copied[name] = value
+	}
+	id := strconv.FormatInt(ms, 10) + "-" + strconv.FormatInt(seq, 10)
+	stream = append(stream, StreamEntry{ID: id, Fields: copied})
+	if maxLen > 0 && int64(len(stream)) > maxLen {
+		stream = stream[int64(len(stream))-maxLen:]
+	}
+	f.streams[key] = stream
+	return id, nil
+}
+
+// XRevRange reads the stream newest first
+func (f *Fake) XRevRange(ctx context.Context, key, end string, count int64) ([]StreamEntry, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.call(OpXRange, key); err != nil {
+		return nil, err
+	}
+
+	exclusive := strings.HasPrefix(end, "(")
+	endMS, endSeq := parseStreamID(strings.TrimPrefix(end, "("))
+	if !strings.Contains(end, "-") {
+		endSeq = math.MaxInt64 // A bare end time takes every entry of its millisecond
+	}
+	entries := []StreamEntry{}
+	stream := f.streams[key]
+	for i := len(stream) - 1; i >= 0; i-- {
+		if count > 0 && int64(len(entries)) == count {
+			break
+		}
+		if end != "+" {
+			ms, seq := parseStreamID(stream[i].ID)
+			if ms > endMS || (ms == endMS && (seq > endSeq || (exclusive && seq == endSeq))) {
+				continue
+			}
+		}
+		entries = append(entries, stream[i])
+	}
+	return entries, nil
+}
+
+// parseStreamID splits a stream ID into its milliseconds and sequence
+func parseStreamID(id string) (ms, seq int64) {
+	msPart, seqPart, _ := strings.Cut(id, "-")
+	ms, _ = strconv.ParseInt(msPart, 10, 64)
+	seq, _ = strconv.ParseInt(seqPart, 10, 64)
+	return ms, seq
+}
+
+func (f *Fake) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	return nil
+}
+
+// inject adds an injected failure
+func (f *Fake) inject(fail *failure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, fail)
+}
+
+// call records a call and returns the error it should fail with, if any; f.mu must be held
+func (f *Fake) call(op Op, key string) error {
+	f.calls[op]++
+	if f.closed {
+		return redis.ErrClosed
+	}
+
+	for i, fail := range f.failures {
+		if fail.op != op || !strings.HasPrefix(key, fail.prefix) {
+			continue
+		}
+		if fail.remaining > 0 {
+			fail.remaining--
+			if fail.remaining == 0 {
+				f.failures = append(f.failures[:i], f.failures[i+1:]...)
+			}
+		}
+		return fail.err
+	}
+	return nil
+}
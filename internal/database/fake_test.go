@@ -0,0 +1,202 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestFake(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("behaves like Valkey for stored and missing keys", func(t *testing.T) {
+		db := NewFake()
+		if err := db.Set(ctx, "score", []byte("1500")); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		if got, err := db.Get(ctx, "score"); err != nil || got != "1500" {
+			t.Errorf("Expected 1500, got %q (%v)", got, err)
+		}
+		if _, err := db.Get(ctx, "missing"); !errors.Is(err, redis.Nil) {
+			t.Errorf("Expected redis.Nil for a missing key, got %v", err)
+		}
+
+		db.Close()
+		if _, err := db.Get(ctx, "score"); !errors.Is(err, redis.ErrClosed) {
+			t.Errorf("Expected redis.ErrClosed after Close, got %v", err)
+		}
+	})
+
+	t.Run("Reset empties the fake but keeps subscriptions", func(t *testing.T) {
+		db := NewFake()
+		db.Set(ctx, "score", "1500")
+		db.ZAdd(ctx, "index", ZMember{Score: 1, Member: "a"})
+		messages, _ := db.Subscribe(ctx, "events")
+
+		db.Reset()
+		if _, err := db.Get(ctx, "score"); !errors.Is(err, redis.Nil) {
+			t.Errorf("Expected redis.Nil after Reset, got %v", err)
+		}
+		if count, _ := db.ZCard(ctx, "index"); count != 0 {
+			t.Errorf("Expected an empty sorted set after Reset, got %d members", count)
+		}
+		db.Publish(ctx, "events", "hello")
+		if got := <-messages; got != "hello" {
+			t.Errorf("Expected the subscription to survive Reset, got %q", got)
+		}
+	})
+
+	t.Run("orders sorted set ranges like Valkey", func(t *testing.T) {
+		db := NewFake()
+		db.ZAdd(ctx, "index", ZMember{Score: 10, Member: "b"}, ZMember{Score: 10, Member: "a"}, ZMember{Score: 30, Member: "c"}, ZMember{Score: 50, Member: "d"})
+
+		all := ZRange{Min: math.Inf(-1), Max: math.Inf(1)}
+		if got, _ := db.ZRangeByScore(ctx, "index", all); strings.Join(got, "") != "abcd" {
+			t.Errorf("Expected abcd, got %v", got)
+		}
+		if got, _ := db.ZRangeByScore(ctx, "index", ZRange{Min: 10, Max: 30, Reverse: true, Offset: 1, Count: 1}); strings.Join(got, "") != "b" {
+			t.Errorf("Expected b, got %v", got)
+		}
+		if n, _ := db.ZCard(ctx, "index"); n != 4 {
+			t.Errorf("Expected 4 members, got %d", n)
+		}
+
+		db.Del(ctx, "index")
+		if got, _ := db.ZRangeByScore(ctx, "index", all); len(got) != 0 {
+			t.Errorf("Expected an empty range after Del, got %v", got)
+		}
+	})
+
+	t.Run("injects failures", func(t *testing.T) {
+		db := NewFake()
+		boom := errors.New("boom")
+
+		db.FailNext(OpSet, boom)
+		if err := db.Set(ctx, "a", "1"); !errors.Is(err, boom) {
+			t.Errorf("Expected the injected error, got %v", err)
+		}
+		if err := db.Set(ctx, "a", "1"); err != nil {
+			t.Errorf("Expected FailNext to fail only once, got %v", err)
+		}
+
+		db.FailKey(OpGet, "leaderboard:", boom)
+		if _, err := db.Get(ctx, "leaderboard:pacman"); !errors.Is(err, boom) {
+			t.Errorf("Expected matching keys to fail, got %v", err)
+		}
+		if _, err := db.Get(ctx, "a"); err != nil {
+			t.Errorf("Expected other keys to succeed, got %v", err)
+		}
+
+		db.Fail(OpPing, boom)
+		db.Ping(ctx)
+		if err := db.Ping(ctx); !errors.Is(err, boom) {
+			t.Errorf("Expected Fail to persist, got %v", err)
+		}
+
+		db.Heal()
+		if err := db.Ping(ctx); err != nil {
+			t.Errorf("Expected Heal to clear failures, got %v", err)
+		}
+		if db.Calls(OpPing) != 3 || db.Calls(OpSet) != 2 {
+			t.Errorf("Unexpected call counts: ping=%d set=%d", db.Calls(OpPing), db.Calls(OpSet))
+		}
+	})
+
+	t.Run("reports a shifted clock", func(t *testing.T) {
+		db := NewFake()
+		db.SetClockOffset(-10 * time.Second)
+
+		skew, err := MeasureSkew(ctx, db)
+		if err != nil {
+			t.Fatalf("MeasureSkew failed: %v", err)
+		}
+		if skew < 9*time.Second || skew > 11*time.Second {
+			t.Errorf("Expected about 10s of skew, got %v", skew)
+		}
+	})
+
+	t.Run("delivers published messages until the subscriber cancels", func(t *testing.T) {
+		db := NewFake()
+		subCtx, cancel := context.WithCancel(ctx)
+		messages, err := db.Subscribe(subCtx, "events")
+		if err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+
+		db.Publish(ctx, "other", "ignored")
+		db.Publish(ctx, "events", "hello")
+		if got := <-messages; got != "hello" {
+			t.Errorf("Expected hello, got %q", got)
+		}
+
+		cancel()
+		for range messages {
+		}
+		if db.Subscribers("events") != 0 {
+			t.Error("Expected the subscription to be removed")
+		}
+	})
+	t.Run("refills token buckets on its clock", func(t *testing.T) {
+		db := NewFake()
+		take := func() bool {
+			allowed, _, err := db.TakeToken(ctx, "bucket", 1, 2)
+			if err != nil {
+				t.Fatalf("TakeToken failed: %v", err)
+			}
+			return allowed
+		}
+
+		if !take() || !take() {
+			t.Fatal("Expected the burst to be allowed")
+		}
+		if _, retryAfter, _ := db.TakeToken(ctx, "bucket", 1, 2); retryAfter <= 0 || retryAfter > time.Second {
+			t.Errorf("Expected to wait up to a second for the next token, got %v", retryAfter)
+		}
+		db.SetClockOffset(2 * time.Second)
+		if !take() {
+			t.Error("Expected the bucket to refill")
+		}
+	})
+	t.Run("counts from zero like Valkey", func(t *testing.T) {
+		db := NewFake()
+		for want := int64(1); want <= 3; want++ {
+			if got, err := db.Incr(ctx, "counter"); err != nil || got != want {
+				t.Fatalf("Expected %d, got %d (%v)", want, got, err)
+			}
+		}
+		if value, _ := db.Get(ctx, "counter"); value != "3" {
+			t.Errorf("Expected the counter to be stored as a string, got %q", value)
+		}
+
+		db.Set(ctx, "name", "pacman")
+		if _, err := db.Incr(ctx, "name"); err == nil {
+			t.Error("Expected incrementing a non-integer to fail")
+		}
+	})
+
+	t.Run("reads streams back newest first and trims them", func(t *testing.T) {
+		db := NewFake()
+		var ids []string
+		for _, n := range []string{"1", "2", "3", "4"} {
+			id, err := db.XAdd(ctx, "log", 3, map[string]string{"n": n})
+			if err != nil {
+				t.Fatalf("XAdd failed: %v", err)
+			}
+			ids = append(ids, id)
+		}
+
+		entries, _ := db.XRevRange(ctx, "log", "+", 0)
+		if len(entries) != 3 || entries[0].Fields["n"] != "4" || entries[2].Fields["n"] != "2" {
+			t.Errorf("Expected the 3 newest entries, newest first, got %v", entries)
+		}
+		entries, _ = db.XRevRange(ctx, "log", "("+ids[3], 1)
+		if len(entries) != 1 || entries[0].ID != ids[2] {
+			t.Errorf("Expected the entry before the newest, got %v", entries)
+		}
+	})
+}
@@ -7,6 +7,8 @@ import (
 type DB interface {
 	Set(ctx context.Context, key string, value interface{}) error
 	Get(ctx context.Context, key string) (string, error)
+	// SetNX stores value only if key is unset, reporting whether it did
+	SetNX(ctx context.Context, key string, value interface{}) (bool, error)
 
 	Ping(ctx context.Context) error
 	Close() error
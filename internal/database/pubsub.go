@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+
+	"rawboard/internal/logging"
+)
+
+// PubSub is implemented by databases that can broadcast messages to every connected
+// client, which keeps per-replica state such as caches coherent across a deployment
+type PubSub interface {
+	Publish(ctx context.Context, channel, message string) error
+	// Subscribe delivers messages published to channel until ctx is cancelled, then
+	// closes the returned channel
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
+}
+
+func (v *ValkeyDB) Publish(ctx context.Context, channel, message string) error {
+	err := v.withRetry(ctx, channel, func() error {
+		return v.client.Publish(ctx, channel, message).Err()
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database publish failed", "channel", channel, "error", err)
+	}
+	return err
+}
+
+// Subscribe waits for Valkey to confirm the subscription before returning, so nothing
+// published afterwards is missed. go-redis resubscribes after reconnecting, but messages
+// published while disconnected are lost.
+func (v *ValkeyDB) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	sub := v.client.Subscribe(ctx, channel)
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, err
+	}
+
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		defer sub.Close()
+
+		incoming := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-incoming:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
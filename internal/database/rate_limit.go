@@ -0,0 +1,80 @@
+package database
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"rawboard/internal/logging"
+)
+
+// RateLimiter is implemented by databases that can keep token buckets, so every
+// replica draws from the same bucket
+type RateLimiter interface {
+	// TakeToken takes a token from the bucket at key, which holds up to burst tokens
+	// and refills at rate tokens per second. When the bucket is empty it reports how
+	// long until the next token instead.
+	TakeToken(ctx context.Context, key string, rate float64, burst int) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// takeTokenScript refills and takes from a token bucket in one step, using the server's
+// clock so replicas with skewed clocks agree. Idle buckets expire once they'd be full.
+var takeTokenScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) + tonumber(time[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(state[1]) or burst
+local updated = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - updated) * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+return {allowed, wait}
+`)
+
+func (v *ValkeyDB) TakeToken(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	var result []int64
+	err := v.withRetry(ctx, key, func() (err error) {
+		result, err = takeTokenScript.Run(ctx, v.client, []string{key}, rate, burst).Int64Slice()
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database rate limit failed", "key", key, "error", err)
+		return false, 0, err
+	}
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// tokenBucket is a Fake rate limit bucket
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// take refills the bucket up to now and takes a token, like takeTokenScript
+func (b *tokenBucket) take(now time.Time, rate float64, burst int) (bool, time.Duration) {
+	elapsed := math.Max(0, now.Sub(b.updated).Seconds())
+	b.tokens = math.Min(float64(burst), b.tokens+elapsed*rate)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := math.Ceil((1 - b.tokens) / rate * 1000)
+	return false, time.Duration(wait) * time.Millisecond
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Retry defaults, short enough that a player waits well under a second for a blip to pass
+const (
+	DefaultMaxRetries      = 3
+	DefaultMinRetryBackoff = 25 * time.Millisecond
+	DefaultMaxRetryBackoff = 500 * time.Millisecond
+)
+
+// retryPolicy retries transient errors with exponential backoff and full jitter
+type retryPolicy struct {
+	maxRetries int // Retries after the first attempt; 0 disables retrying
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// do runs fn, retrying while it fails transiently and ctx allows. onRetry is called
+// before each retry.
+func (p retryPolicy) do(ctx context.Context, fn func() error, onRetry func(attempt int, err error)) error {
+	err := fn()
+	for attempt := 1; attempt <= p.maxRetries && isTransient(err); attempt++ {
+		onRetry(attempt, err)
+
+		timer := time.NewTimer(p.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		err = fn()
+	}
+	return err
+}
+
+// backoff returns a random wait up to minBackoff doubled per attempt, capped at maxBackoff
+func (p retryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.minBackoff << (attempt - 1)
+	if ceiling <= 0 || ceiling > p.maxBackoff {
+		ceiling = p.maxBackoff
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling) + 1
+}
+
+// isTransient reports whether err is worth retrying: a dropped or refused connection,
+// a timeout, an exhausted pool, or a node that is loading or mid-failover. Missing
+// keys, command errors and cancellation are not.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, redis.ErrClosed) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, redis.ErrPoolTimeout) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	for _, prefix := range []string{"LOADING", "READONLY", "TRYAGAIN", "CLUSTERDOWN", "MASTERDOWN"} {
+		if redis.HasErrorPrefix(err, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// serverError is an error reply from Valkey
+type serverError string
+
+func (e serverError) Error() string { return string(e) }
+func (e serverError) RedisError()   {}
+
+func TestIsTransient(t *testing.T) {
+	for _, err := range []error{
+		io.EOF,
+		fmt.Errorf("read: %w", syscall.ECONNRESET),
+		&net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+		redis.ErrPoolTimeout,
+		serverError("LOADING Valkey is loading the dataset in memory"),
+		serverError("READONLY You can't write against a read only replica."),
+	} {
+		if !isTransient(err) {
+			t.Errorf("Expected %v to be transient", err)
+		}
+	}
+
+	for _, err := range []error{
+		nil,
+		redis.Nil,
+		redis.ErrClosed,
+		context.Canceled,
+		context.DeadlineExceeded,
+		serverError("WRONGTYPE Operation against a key holding the wrong kind of value"),
+		errors.New("LOADING but not from the server"),
+	} {
+		if isTransient(err) {
+			t.Errorf("Expected %v not to be transient", err)
+		}
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	newDB := func(maxRetries int) *ValkeyDB {
+		return &ValkeyDB{
+			logger: slog.New(slog.DiscardHandler),
+			retry:  retryPolicy{maxRetries: maxRetries, minBackoff: time.Millisecond, maxBackoff: 2 * time.Millisecond},
+		}
+	}
+
+	t.Run("recovers from a blip", func(t *testing.T) {
+		db := newDB(3)
+		calls := 0
+		err := db.withRetry(context.Background(), "k", func() error {
+			if calls++; calls < 3 {
+				return io.EOF
+			}
+			return nil
+		})
+		if err != nil || calls != 3 {
+			t.Errorf("Expected success on the third attempt, got %v after %d", err, calls)
+		}
+		if db.retries.Load() != 2 || db.retryFails.Load() != 0 {
+			t.Errorf("Expected 2 retries and no failures, got %d and %d", db.retries.Load(), db.retryFails.Load())
+		}
+	})
+
+	t.Run("gives up after the retry budget", func(t *testing.T) {
+		db := newDB(2)
+		calls := 0
+		err := db.withRetry(context.Background(), "k", func() error {
+			calls++
+			return io.EOF
+		})
+		if !errors.Is(err, io.EOF) || calls != 3 {
+			t.Errorf("Expected EOF after 3 attempts, got %v after %d", err, calls)
+		}
+		if db.retryFails.Load() != 1 {
+			t.Errorf("Expected one exhausted retry, got %d", db.retryFails.Load())
+		}
+	})
+
+	t.Run("does not retry missing keys", func(t *testing.T) {
+		db := newDB(3)
+		calls := 0
+		err := db.withRetry(context.Background(), "k", func() error {
+			calls++
+			return redis.Nil
+		})
+		if !errors.Is(err, redis.Nil) || calls != 1 {
+			t.Errorf("Expected a single attempt, got %v after %d", err, calls)
+		}
+	})
+
+	t.Run("stops when the request is cancelled", func(t *testing.T) {
+		db := newDB(3)
+		db.retry.minBackoff, db.retry.maxBackoff = time.Hour, time.Hour
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := db.withRetry(ctx, "k", func() error {
+			calls++
+			cancel()
+			return io.EOF
+		})
+		if !errors.Is(err, io.EOF) || calls != 1 {
+			t.Errorf("Expected to stop waiting once cancelled, got %v after %d", err, calls)
+		}
+	})
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := retryPolicy{minBackoff: 10 * time.Millisecond, maxBackoff: 50 * time.Millisecond}
+	for attempt, ceiling := range map[int]time.Duration{1: 10 * time.Millisecond, 2: 20 * time.Millisecond, 3: 40 * time.Millisecond, 4: 50 * time.Millisecond, 40: 50 * time.Millisecond} {
+		for range 20 {
+			if wait := policy.backoff(attempt); wait <= 0 || wait > ceiling {
+				t.Fatalf("Attempt %d waited %v, expected up to %v", attempt, wait, ceiling)
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+
+	"rawboard/internal/logging"
+)
+
+// scanBatch is how many keys each SCAN call asks for
+const scanBatch = 1000
+
+// Scanner is implemented by databases that can list their keys, which one-off
+// maintenance such as backfilling the game registry walks
+type Scanner interface {
+	// ScanKeys returns every key starting with prefix, in no particular order
+	ScanKeys(ctx context.Context, prefix string) ([]string, error)
+}
+
+// ScanKeys walks the keyspace with SCAN, on every primary of a cluster
+func (v *ValkeyDB) ScanKeys(ctx context.Context, prefix string) ([]string, error) {
+	var mu sync.Mutex
+	keys := []string{}
+	scan := func(ctx context.Context, client redis.UniversalClient) error {
+		iter := client.Scan(ctx, 0, globEscape(prefix)+"*", scanBatch).Iterator()
+		for iter.Next(ctx) {
+			mu.Lock()
+			keys = append(keys, iter.Val())
+			mu.Unlock()
+		}
+		return iter.Err()
+	}
+
+	var err error
+	if cluster, ok := v.client.(*redis.ClusterClient); ok {
+		err = cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return scan(ctx, client)
+		})
+	} else {
+		err = scan(ctx, v.client)
+	}
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database scan failed", "prefix", prefix, "error", err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+// globEscape escapes the characters SCAN's MATCH pattern treats specially
+func globEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`).Replace(s)
+}
@@ -0,0 +1,108 @@
+package database
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+
+	"rawboard/internal/logging"
+)
+
+// SortedSets is implemented by databases with native sorted sets, which back
+// secondary indexes that would otherwise need full scans of a JSON record
+type SortedSets interface {
+	ZAdd(ctx context.Context, key string, members ...ZMember) error
+	ZRangeByScore(ctx context.Context, key string, query ZRange) ([]string, error)
+	ZCard(ctx context.Context, key string) (int64, error)
+	Del(ctx context.Context, key string) error
+}
+
+// ZMember is a sorted set member and its score
+type ZMember struct {
+	Score  float64
+	Member string
+}
+
+// ZRange selects sorted set members with scores between Min and Max inclusive.
+// Use math.Inf for an open bound. Count of 0 returns every match.
+type ZRange struct {
+	Min, Max float64
+	Reverse  bool // Highest scores first
+	Offset   int64
+	Count    int64
+}
+
+func (v *ValkeyDB) ZAdd(ctx context.Context, key string, members ...ZMember) error {
+	zs := make([]redis.Z, len(members))
+	for i, m := range members {
+		zs[i] = redis.Z{Score: m.Score, Member: m.Member}
+	}
+	err := v.withRetry(ctx, key, func() error {
+		return v.client.ZAdd(ctx, key, zs...).Err()
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database write failed", "key", key, "error", err)
+	}
+	return err
+}
+
+func (v *ValkeyDB) ZRangeByScore(ctx context.Context, key string, query ZRange) ([]string, error) {
+	args := redis.ZRangeArgs{
+		Key:     key,
+		Start:   scoreBound(query.Min),
+		Stop:    scoreBound(query.Max),
+		ByScore: true,
+		Rev:     query.Reverse,
+		Offset:  query.Offset,
+		Count:   query.Count,
+	}
+	if query.Reverse {
+		args.Start, args.Stop = args.Stop, args.Start // ZRANGE BYSCORE REV takes max first
+	}
+	if args.Count == 0 {
+		args.Count = -1 // A LIMIT needs a count; -1 is unbounded
+	}
+
+	var members []string
+	err := v.withRetry(ctx, key, func() (err error) {
+		members, err = v.client.ZRangeArgs(ctx, args).Result()
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database read failed", "key", key, "error", err)
+	}
+	return members, err
+}
+
+func (v *ValkeyDB) ZCard(ctx context.Context, key string) (int64, error) {
+	var count int64
+	err := v.withRetry(ctx, key, func() (err error) {
+		count, err = v.client.ZCard(ctx, key).Result()
+		return err
+	})
+	return count, err
+}
+
+func (v *ValkeyDB) Del(ctx context.Context, key string) error {
+	err := v.withRetry(ctx, key, func() error {
+		return v.client.Del(ctx, key).Err()
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database write failed", "key", key, "error", err)
+	}
+	return err
+}
+
+// scoreBound formats a sorted set score bound, mapping infinities to -inf and +inf
+func scoreBound(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	}
+}
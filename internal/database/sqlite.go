@@ -0,0 +1,354 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	_ "modernc.org/sqlite" // Registers the pure-Go "sqlite" driver
+
+	"rawboard/internal/logging"
+)
+
+// SQLiteDB keeps data in a local SQLite file, for edge instances that must keep serving
+// and taking scores while the central database is out of reach. Sorted sets and streams
+// are tables of their own. Pub/sub only reaches subscribers in the same process, since
+// an edge instance runs alone on its file.
+type SQLiteDB struct {
+	db     *sql.DB
+	path   string
+	logger *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[string][]chan string
+}
+
+// sqliteSchema creates the tables behind plain keys, sorted sets and streams
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS kv (key TEXT PRIMARY KEY, value TEXT NOT NULL);
+CREATE TABLE IF NOT EXISTS zsets (key TEXT NOT NULL, member TEXT NOT NULL, score REAL NOT NULL, PRIMARY KEY (key, member));
+CREATE INDEX IF NOT EXISTS zsets_by_score ON zsets (key, score, member);
+CREATE TABLE IF NOT EXISTS streams (id INTEGER PRIMARY KEY AUTOINCREMENT, key TEXT NOT NULL, ms INTEGER NOT NULL, seq INTEGER NOT NULL, fields TEXT NOT NULL);
+CREATE INDEX IF NOT EXISTS streams_by_key ON streams (key, id);
+`
+
+// sqliteSubscriberBuffer is how many messages a subscriber may have waiting before
+// further messages to it are dropped
+const sqliteSubscriberBuffer = 64
+
+// SQLiteOption configures optional SQLiteDB behavior
+type SQLiteOption func(*SQLiteDB)
+
+// WithSQLiteLogger sets the logger used when a request carries no scoped logger
+func WithSQLiteLogger(logger *slog.Logger) SQLiteOption {
+	return func(s *SQLiteDB) {
+		s.logger = logger
+	}
+}
+
+// NewSQLiteDB opens the SQLite database at path, creating it if needed
+func NewSQLiteDB(path string, options ...SQLiteOption) (*SQLiteDB, error) {
+	s := &SQLiteDB{path: path, logger: slog.Default(), subscribers: make(map[string][]chan string)}
+	for _, opt := range options {
+		opt(s)
+	}
+
+	// WAL lets reads carry on during a write; the busy timeout waits out the writer
+	// instead of failing, since SQLite allows only one at a time
+	dsn := "file:" + path + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)&_pragma=synchronous(NORMAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to prepare SQLite database %s: %w", path, err)
+	}
+	s.db = db
+	return s, nil
+}
+
+// Path returns the database file
+func (s *SQLiteDB) Path() string {
+	return s.path
+}
+
+// SQL returns the underlying connection, for callers with tables of their own such as
+// the storage benchmarks
+func (s *SQLiteDB) SQL() *sql.DB {
+	return s.db
+}
+
+func (s *SQLiteDB) Set(ctx context.Context, key string, value interface{}) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO kv (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, sqliteText(value))
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database write failed", "key", key, "error", err)
+	}
+	return err
+}
+
+func (s *SQLiteDB) SetNX(ctx context.Context, key string, value interface{}) (bool, error) {
+	result, err := s.db.ExecContext(ctx, `INSERT INTO kv (key, value) VALUES (?, ?) ON CONFLICT (key) DO NOTHING`, key, sqliteText(value))
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database write failed", "key", key, "error", err)
+		return false, err
+	}
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return inserted == 1, nil
+}
+
+// sqliteText stores values as text, the way Valkey does
+func sqliteText(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Get returns redis.Nil for missing keys, like the other databases
+func (s *SQLiteDB) Get(ctx context.Context, key string) (string, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", redis.Nil
+	}
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database read failed", "key", key, "error", err)
+	}
+	return value, err
+}
+
+// ScanKeys lists the plain values stored under keys starting with prefix
+func (s *SQLiteDB) ScanKeys(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT key FROM kv WHERE substr(key, 1, length(?1)) = ?1`, prefix)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database scan failed", "prefix", prefix, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Incr adds one to the counter at key in a single statement, so concurrent callers get
+// distinct values
+func (s *SQLiteDB) Incr(ctx context.Context, key string) (int64, error) {
+	var value string
+	err := s.db.QueryRowContext(ctx, `INSERT INTO kv (key, value) VALUES (?, '1')
+		ON CONFLICT (key) DO UPDATE SET value = CAST(value AS INTEGER) + 1 RETURNING value`, key).Scan(&value)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database incr failed", "key", key, "error", err)
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// Del removes key whichever kind of value it holds
+func (s *SQLiteDB) Del(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM kv WHERE key = ?1; DELETE FROM zsets WHERE key = ?1; DELETE FROM streams WHERE key = ?1`, key)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database delete failed", "key", key, "error", err)
+	}
+	return err
+}
+
+func (s *SQLiteDB) ZAdd(ctx context.Context, key string, members ...ZMember) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, m := range members {
+		_, err := tx.ExecContext(ctx, `INSERT INTO zsets (key, member, score) VALUES (?, ?, ?)
+			ON CONFLICT (key, member) DO UPDATE SET score = excluded.score`, key, m.Member, m.Score)
+		if err != nil {
+			logging.FromContext(ctx, s.logger).Error("database write failed", "key", key, "error", err)
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ZRangeByScore orders members by score, then lexicographically, like Valkey
+func (s *SQLiteDB) ZRangeByScore(ctx context.Context, key string, query ZRange) ([]string, error) {
+	order := "ASC"
+	if query.Reverse {
+		order = "DESC"
+	}
+	limit := query.Count
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	rows, err := s.db.QueryContext(ctx, `SELECT member FROM zsets WHERE key = ? AND score >= ? AND score <= ?
+		ORDER BY score `+order+`, member `+order+` LIMIT ? OFFSET ?`, key, query.Min, query.Max, limit, query.Offset)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database read failed", "key", key, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []string{}
+	for rows.Next() {
+		var member string
+		if err := rows.Scan(&member); err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+func (s *SQLiteDB) ZCard(ctx context.Context, key string) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM zsets WHERE key = ?`, key).Scan(&count)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database read failed", "key", key, "error", err)
+	}
+	return count, err
+}
+
+// XAdd stamps entries with the current time, like Valkey, and trims exactly to maxLen
+func (s *SQLiteDB) XAdd(ctx context.Context, key string, maxLen int64, fields map[string]string) (string, error) {
+	encoded, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	ms, seq := time.Now().UnixMilli(), int64(0)
+	var lastMS, lastSeq int64
+	err = tx.QueryRowContext(ctx, `SELECT ms, seq FROM streams WHERE key = ? ORDER BY id DESC LIMIT 1`, key).Scan(&lastMS, &lastSeq)
+	if err == nil && ms <= lastMS {
+		ms, seq = lastMS, lastSeq+1
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO streams (key, ms, seq, fields) VALUES (?, ?, ?, ?)`, key, ms, seq, string(encoded)); err != nil {
+		logging.FromContext(ctx, s.logger).Error("database write failed", "key", key, "error", err)
+		return "", err
+	}
+	if maxLen > 0 {
+		_, err := tx.ExecContext(ctx, `DELETE FROM streams WHERE key = ?1 AND id <= (SELECT id FROM streams WHERE key = ?1 ORDER BY id DESC LIMIT 1 OFFSET ?2)`, key, maxLen)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(ms, 10) + "-" + strconv.FormatInt(seq, 10), nil
+}
+
+// XRevRange reads the stream newest first
+func (s *SQLiteDB) XRevRange(ctx context.Context, key, end string, count int64) ([]StreamEntry, error) {
+	limit := count
+	if limit <= 0 {
+		limit = -1 // No limit
+	}
+	query, args := `SELECT ms, seq, fields FROM streams WHERE key = ?`, []interface{}{key}
+	if end != "+" {
+		exclusive := strings.HasPrefix(end, "(")
+		endMS, endSeq := parseStreamID(strings.TrimPrefix(end, "("))
+		if !strings.Contains(end, "-") {
+			endSeq = math.MaxInt64 // A bare end time takes every entry of its millisecond
+		}
+		if exclusive {
+			query += ` AND (ms < ? OR (ms = ? AND seq < ?))`
+		} else {
+			query += ` AND (ms < ? OR (ms = ? AND seq <= ?))`
+		}
+		args = append(args, endMS, endMS, endSeq)
+	}
+	rows, err := s.db.QueryContext(ctx, query+` ORDER BY id DESC LIMIT ?`, append(args, limit)...)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("database read failed", "key", key, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []StreamEntry{}
+	for rows.Next() {
+		var ms, seq int64
+		var encoded string
+		if err := rows.Scan(&ms, &seq, &encoded); err != nil {
+			return nil, err
+		}
+		entry := StreamEntry{ID: strconv.FormatInt(ms, 10) + "-" + strconv.FormatInt(seq, 10)}
+		if err := json.Unmarshal([]byte(encoded), &entry.Fields); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (s *SQLiteDB) Publish(ctx context.Context, channel, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, sub := range s.subscribers[channel] {
+		select {
+		case sub <- message:
+		default:
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteDB) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sub := make(chan string, sqliteSubscriberBuffer)
+	s.subscribers[channel] = append(s.subscribers[channel], sub)
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		subs := s.subscribers[channel]
+		for i := range subs {
+			if subs[i] == sub {
+				s.subscribers[channel] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		close(sub)
+	}()
+	return sub, nil
+}
+
+func (s *SQLiteDB) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s *SQLiteDB) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+)
+
+func TestSQLiteDB(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps values across reopening the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "edge.db")
+		db, err := NewSQLiteDB(path)
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		if err := db.Set(ctx, "score", []byte("1500")); err != nil {
+			t.Fatalf("Set failed: %v", err)
+		}
+		db.Set(ctx, "score", 2500)
+		if _, err := db.Get(ctx, "missing"); !errors.Is(err, redis.Nil) {
+			t.Errorf("Expected redis.Nil for a missing key, got %v", err)
+		}
+		db.Close()
+
+		db, err = NewSQLiteDB(path)
+		if err != nil {
+			t.Fatalf("Reopening failed: %v", err)
+		}
+		defer db.Close()
+		if got, err := db.Get(ctx, "score"); err != nil || got != "2500" {
+			t.Errorf("Expected 2500 after reopening, got %q (%v)", got, err)
+		}
+	})
+
+	t.Run("hands out distinct counter values to concurrent callers", func(t *testing.T) {
+		db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "edge.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		defer db.Close()
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		seen := make(map[int64]bool)
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := db.Incr(ctx, "sequence")
+				if err != nil {
+					t.Errorf("Incr failed: %v", err)
+					return
+				}
+				mu.Lock()
+				seen[value] = true
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		if len(seen) != 20 || !seen[1] || !seen[20] {
+			t.Errorf("Expected the values 1 to 20 once each, got %v", seen)
+		}
+	})
+
+	t.Run("lists keys by prefix", func(t *testing.T) {
+		db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "edge.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		defer db.Close()
+
+		db.Set(ctx, "all_scores:pacman", "{}")
+		db.Set(ctx, "all_scores:50%_off", "{}")
+		db.Set(ctx, "leaderboard:pacman", "{}")
+		keys, err := db.ScanKeys(ctx, "all_scores:")
+		sort.Strings(keys)
+		if err != nil || len(keys) != 2 || keys[0] != "all_scores:50%_off" || keys[1] != "all_scores:pacman" {
+			t.Errorf("Expected both all_scores keys, got %v (%v)", keys, err)
+		}
+		if keys, _ := db.ScanKeys(ctx, "all_scores:50%"); len(keys) != 1 {
+			t.Errorf("Expected the prefix matched literally, got %v", keys)
+		}
+	})
+
+	t.Run("sets a key only if it's unset", func(t *testing.T) {
+		db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "edge.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		defer db.Close()
+
+		if set, err := db.SetNX(ctx, "salt", "first"); err != nil || !set {
+			t.Fatalf("Expected the first SetNX to store, got %v (%v)", set, err)
+		}
+		if set, err := db.SetNX(ctx, "salt", "second"); err != nil || set {
+			t.Errorf("Expected the second SetNX to be refused, got %v (%v)", set, err)
+		}
+		if value, _ := db.Get(ctx, "salt"); value != "first" {
+			t.Errorf("Expected the first value kept, got %q", value)
+		}
+	})
+
+	t.Run("orders sorted sets like Valkey", func(t *testing.T) {
+		db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "edge.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		defer db.Close()
+
+		db.ZAdd(ctx, "index", ZMember{Score: 300, Member: "c"}, ZMember{Score: 100, Member: "a"}, ZMember{Score: 100, Member: "b"})
+		db.ZAdd(ctx, "index", ZMember{Score: 200, Member: "c"})
+		all, err := db.ZRangeByScore(ctx, "index", ZRange{Min: math.Inf(-1), Max: math.Inf(1)})
+		if err != nil || !slices.Equal(all, []string{"a", "b", "c"}) {
+			t.Errorf("Expected a, b, c, got %v (%v)", all, err)
+		}
+		top, _ := db.ZRangeByScore(ctx, "index", ZRange{Min: 100, Max: 200, Reverse: true, Offset: 1, Count: 1})
+		if !slices.Equal(top, []string{"b"}) {
+			t.Errorf("Expected b second from the top, got %v", top)
+		}
+		if count, _ := db.ZCard(ctx, "index"); count != 3 {
+			t.Errorf("Expected 3 members, got %d", count)
+		}
+
+		db.Del(ctx, "index")
+		if count, _ := db.ZCard(ctx, "index"); count != 0 {
+			t.Errorf("Expected the set deleted, got %d members", count)
+		}
+	})
+
+	t.Run("reads streams newest first and trims them", func(t *testing.T) {
+		db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "edge.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		defer db.Close()
+
+		ids := []string{}
+		for _, action := range []string{"one", "two", "three", "four"} {
+			id, err := db.XAdd(ctx, "audit", 3, map[string]string{"action": action})
+			if err != nil {
+				t.Fatalf("XAdd failed: %v", err)
+			}
+			ids = append(ids, id)
+		}
+		entries, err := db.XRevRange(ctx, "audit", "+", 0)
+		if err != nil || len(entries) != 3 || entries[0].Fields["action"] != "four" || entries[2].Fields["action"] != "two" {
+			t.Fatalf("Expected four, three, two, got %+v (%v)", entries, err)
+		}
+		older, _ := db.XRevRange(ctx, "audit", "("+ids[3], 1)
+		if len(older) != 1 || older[0].ID != ids[2] {
+			t.Errorf("Expected only three before four, got %+v", older)
+		}
+	})
+
+	t.Run("delivers published messages to subscribers", func(t *testing.T) {
+		db, err := NewSQLiteDB(filepath.Join(t.TempDir(), "edge.db"))
+		if err != nil {
+			t.Fatalf("NewSQLiteDB failed: %v", err)
+		}
+		defer db.Close()
+
+		subCtx, cancel := context.WithCancel(ctx)
+		messages, _ := db.Subscribe(subCtx, "invalidate")
+		db.Publish(ctx, "invalidate", "pacman")
+		if got := <-messages; got != "pacman" {
+			t.Errorf("Expected pacman, got %q", got)
+		}
+		cancel()
+		if _, open := <-messages; open {
+			t.Error("Expected the subscription closed once its context ended")
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"rawboard/internal/logging"
+)
+
+// Streams is implemented by databases with append-only streams, which keep logs that are
+// only ever appended to without rewriting a whole JSON record per entry
+type Streams interface {
+	// XAdd appends an entry to the stream at key, trimming the stream to about maxLen
+	// entries when maxLen is positive, and returns the entry's ID
+	XAdd(ctx context.Context, key string, maxLen int64, fields map[string]string) (string, error)
+	// XRevRange returns up to count entries, newest first, from end back to the start of
+	// the stream. end is "+" for the newest entry, an ID, or "(" and an ID to start just
+	// before it. Count of 0 returns every entry.
+	XRevRange(ctx context.Context, key, end string, count int64) ([]StreamEntry, error)
+}
+
+// StreamEntry is one entry of a stream. IDs are "<unix ms>-<sequence>" and increase.
+type StreamEntry struct {
+	ID     string
+	Fields map[string]string
+}
+
+func (v *ValkeyDB) XAdd(ctx context.Context, key string, maxLen int64, fields map[string]string) (string, error) {
+	args := &redis.XAddArgs{Stream: key, Values: fields}
+	if maxLen > 0 {
+		args.MaxLen, args.Approx = maxLen, true
+	}
+
+	var id string
+	err := v.withRetry(ctx, key, func() (err error) {
+		id, err = v.client.XAdd(ctx, args).Result()
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database write failed", "key", key, "error", err)
+	}
+	return id, err
+}
+
+func (v *ValkeyDB) XRevRange(ctx context.Context, key, end string, count int64) ([]StreamEntry, error) {
+	var messages []redis.XMessage
+	err := v.withRetry(ctx, key, func() (err error) {
+		if count > 0 {
+			messages, err = v.client.XRevRangeN(ctx, key, end, "-", count).Result()
+		} else {
+			messages, err = v.client.XRevRange(ctx, key, end, "-").Result()
+		}
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database read failed", "key", key, "error", err)
+		return nil, err
+	}
+
+	entries := make([]StreamEntry, 0, len(messages))
+	for _, message := range messages {
+		fields := make(map[string]string, len(message.Values))
+		for name, value := range message.Values {
+			fields[name], _ = value.(string)
+		}
+		entries = append(entries, StreamEntry{ID: message.ID, Fields: fields})
+	}
+	return entries, nil
+}
@@ -0,0 +1,225 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+)
+
+// Topology event kinds
+const (
+	TopologyMasterChange = "master_change" // A primary failed over to a replica
+	TopologyReshard      = "reshard"       // Cluster slots moved between shards
+)
+
+// topologyEventLimit is how many recent topology events Stats reports
+const topologyEventLimit = 10
+
+// topologyWatch follows which nodes are primary, so failovers and resharding show up
+// in logs and Stats rather than only as a burst of retried commands
+type topologyWatch struct {
+	// snapshot maps each slot range (cluster) or master name (Sentinel) to its primary
+	snapshot func(ctx context.Context) (map[string]string, error)
+	// reload re-resolves the client's connections after a change, or is nil when the
+	// client does so on its own
+	reload func(ctx context.Context)
+	close  func() error
+
+	failovers atomic.Uint64
+	reshards  atomic.Uint64
+
+	mu      sync.Mutex
+	current map[string]string
+	events  []models.TopologyEvent
+}
+
+// clusterTopology watches a cluster's slot map, reloading the client's copy on change
+func clusterTopology(client *redis.ClusterClient) *topologyWatch {
+	return &topologyWatch{
+		snapshot: func(ctx context.Context) (map[string]string, error) {
+			slots, err := client.ClusterSlots(ctx).Result()
+			if err != nil {
+				return nil, err
+			}
+			primaries := make(map[string]string, len(slots))
+			for _, slot := range slots {
+				if len(slot.Nodes) > 0 {
+					primaries[fmt.Sprintf("%d-%d", slot.Start, slot.End)] = slot.Nodes[0].Addr
+				}
+			}
+			return primaries, nil
+		},
+		reload: client.ReloadState,
+	}
+}
+
+// sentinelTopology asks the Sentinels which node is primary. The failover client
+// follows +switch-master itself, so there is nothing to reload.
+func sentinelTopology(opts *redis.FailoverOptions) *topologyWatch {
+	sentinels := make([]*redis.SentinelClient, len(opts.SentinelAddrs))
+	for i, addr := range opts.SentinelAddrs {
+		sentinels[i] = redis.NewSentinelClient(&redis.Options{
+			Addr:         addr,
+			Username:     opts.SentinelUsername,
+			Password:     opts.SentinelPassword,
+			TLSConfig:    opts.TLSConfig,
+			DialTimeout:  opts.DialTimeout,
+			ReadTimeout:  opts.ReadTimeout,
+			WriteTimeout: opts.WriteTimeout,
+			PoolSize:     1,
+			MaxRetries:   -1,
+		})
+	}
+
+	return &topologyWatch{
+		snapshot: func(ctx context.Context) (map[string]string, error) {
+			var errs []error
+			for _, sentinel := range sentinels {
+				addr, err := sentinel.GetMasterAddrByName(ctx, opts.MasterName).Result()
+				if err == nil && len(addr) == 2 {
+					return map[string]string{opts.MasterName: net.JoinHostPort(addr[0], addr[1])}, nil
+				}
+				errs = append(errs, err)
+			}
+			return nil, fmt.Errorf("no sentinel answered for %s: %w", opts.MasterName, errors.Join(errs...))
+		},
+		close: func() error {
+			var errs []error
+			for _, sentinel := range sentinels {
+				errs = append(errs, sentinel.Close())
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// WatchTopology checks which nodes are primary every interval until ctx is cancelled,
+// logging and counting failovers and resharding and re-resolving connections after
+// them. It returns at once for a standalone server.
+func (v *ValkeyDB) WatchTopology(ctx context.Context, interval time.Duration) {
+	if v.topology == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		v.checkTopology(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkTopology takes one snapshot and records how it differs from the last
+func (v *ValkeyDB) checkTopology(ctx context.Context) {
+	snapshot, err := v.topology.snapshot(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logging.FromContext(ctx, v.logger).Warn("database topology check failed", "mode", v.mode, "error", err)
+		}
+		return
+	}
+
+	event := v.topology.observe(snapshot, time.Now())
+	if event == nil {
+		return
+	}
+	logging.FromContext(ctx, v.logger).Warn("database topology changed", "mode", v.mode, "kind", event.Kind, "detail", event.Detail)
+	if v.topology.reload != nil {
+		v.topology.reload(ctx)
+	}
+}
+
+// observe records snapshot as the current topology, returning the event it amounts to,
+// if any. The first snapshot is the baseline.
+func (w *topologyWatch) observe(snapshot map[string]string, at time.Time) *models.TopologyEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	previous := w.current
+	w.current = snapshot
+	if previous == nil {
+		return nil
+	}
+
+	kind, detail := diffTopology(previous, snapshot)
+	if kind == "" {
+		return nil
+	}
+	switch kind {
+	case TopologyMasterChange:
+		w.failovers.Add(1)
+	case TopologyReshard:
+		w.reshards.Add(1)
+	}
+
+	event := models.TopologyEvent{Kind: kind, Detail: detail, At: at.UTC()}
+	w.events = append(w.events, event)
+	if len(w.events) > topologyEventLimit {
+		w.events = w.events[len(w.events)-topologyEventLimit:]
+	}
+	return &event
+}
+
+// recent returns the latest topology events, oldest first
+func (w *topologyWatch) recent() []models.TopologyEvent {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]models.TopologyEvent(nil), w.events...)
+}
+
+// diffTopology describes how next differs from previous: a reshard when the slot ranges
+// changed, or a master change when the same ranges (or master name) got a new primary.
+// It returns an empty kind when nothing changed.
+func diffTopology(previous, next map[string]string) (kind, detail string) {
+	sameKeys := len(previous) == len(next)
+	for key := range next {
+		if _, ok := previous[key]; !ok {
+			sameKeys = false
+			break
+		}
+	}
+	if !sameKeys {
+		return TopologyReshard, fmt.Sprintf("slot ranges changed from %d to %d", len(previous), len(next))
+	}
+
+	var changes []string
+	for key, primary := range next {
+		if previous[key] != primary {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, previous[key], primary))
+		}
+	}
+	if len(changes) == 0 {
+		return "", ""
+	}
+	sort.Strings(changes)
+	return TopologyMasterChange, strings.Join(changes, ", ")
+}
+
+// newTopology watches the primaries of a cluster or Sentinel client, returning nil for
+// a single node
+func newTopology(uri string, settings connSettings, client redis.UniversalClient) *topologyWatch {
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		return clusterTopology(cluster)
+	}
+	if uri, mode, err := splitScheme(uri); err == nil && mode == ModeSentinel {
+		if opts, err := failoverOptions(uri, settings); err == nil {
+			return sentinelTopology(opts)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func TestTopologyWatch(t *testing.T) {
+	ctx := context.Background()
+
+	// newWatched returns a database whose topology snapshots are read from next
+	newWatched := func(next *map[string]string, reloads *int) *ValkeyDB {
+		return &ValkeyDB{
+			mode:   ModeCluster,
+			logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+			topology: &topologyWatch{
+				snapshot: func(context.Context) (map[string]string, error) {
+					if *next == nil {
+						return nil, errors.New("connection refused")
+					}
+					return *next, nil
+				},
+				reload: func(context.Context) { *reloads++ },
+			},
+		}
+	}
+
+	t.Run("counts failovers and reshards after the baseline", func(t *testing.T) {
+		var reloads int
+		next := map[string]string{"0-8191": "10.0.0.1:6379", "8192-16383": "10.0.0.2:6379"}
+		db := newWatched(&next, &reloads)
+
+		db.checkTopology(ctx)
+		db.checkTopology(ctx)
+		if events := db.topology.recent(); len(events) != 0 || reloads != 0 {
+			t.Fatalf("Expected the first snapshot to be the baseline, got %+v and %d reloads", events, reloads)
+		}
+
+		next = map[string]string{"0-8191": "10.0.0.1:6379", "8192-16383": "10.0.0.3:6379"}
+		db.checkTopology(ctx)
+		next = map[string]string{"0-5460": "10.0.0.1:6379", "5461-10922": "10.0.0.3:6379", "10923-16383": "10.0.0.4:6379"}
+		db.checkTopology(ctx)
+
+		events := db.topology.recent()
+		if len(events) != 2 || events[0].Kind != TopologyMasterChange || events[1].Kind != TopologyReshard {
+			t.Fatalf("Expected a master change then a reshard, got %+v", events)
+		}
+		if events[0].Detail != "8192-16383: 10.0.0.2:6379 -> 10.0.0.3:6379" {
+			t.Errorf("Expected the detail to name the new primary, got %q", events[0].Detail)
+		}
+		if db.topology.failovers.Load() != 1 || db.topology.reshards.Load() != 1 || reloads != 2 {
+			t.Errorf("Expected one failover, one reshard and two reloads, got %d, %d and %d",
+				db.topology.failovers.Load(), db.topology.reshards.Load(), reloads)
+		}
+	})
+
+	t.Run("keeps the baseline when a check fails", func(t *testing.T) {
+		var reloads int
+		next := map[string]string{"mymaster": "10.0.0.1:6379"}
+		db := newWatched(&next, &reloads)
+		db.checkTopology(ctx)
+
+		next = nil
+		db.checkTopology(ctx)
+		next = map[string]string{"mymaster": "10.0.0.1:6379"}
+		db.checkTopology(ctx)
+
+		if events := db.topology.recent(); len(events) != 0 {
+			t.Errorf("Expected a failed check not to count as a change, got %+v", events)
+		}
+	})
+
+	t.Run("keeps only the latest events", func(t *testing.T) {
+		var reloads int
+		primaries := []string{"10.0.0.1:6379", "10.0.0.2:6379"}
+		next := map[string]string{"mymaster": primaries[0]}
+		db := newWatched(&next, &reloads)
+
+		for i := 0; i <= topologyEventLimit+5; i++ {
+			next = map[string]string{"mymaster": primaries[i%2]}
+			db.checkTopology(ctx)
+		}
+		if events := db.topology.recent(); len(events) != topologyEventLimit {
+			t.Errorf("Expected %d events, got %d", topologyEventLimit, len(events))
+		}
+		if failovers := db.topology.failovers.Load(); failovers != topologyEventLimit+5 {
+			t.Errorf("Expected every failover to be counted, got %d", failovers)
+		}
+	})
+}
+
+func TestWatchTopologyStandalone(t *testing.T) {
+	db := &ValkeyDB{mode: ModeStandalone}
+	db.WatchTopology(context.Background(), 0) // Returns at once rather than ticking
+}
@@ -2,61 +2,111 @@ package database
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+
 	"github.com/redis/go-redis/v9"
 )
 
+// Connection modes, selected by the URI scheme
+const (
+	ModeStandalone = "standalone"
+	ModeCluster    = "cluster"
+	ModeSentinel   = "sentinel"
+)
+
+// DefaultSlowCommandThreshold is how long a command may take before it is logged as slow
+const DefaultSlowCommandThreshold = 100 * time.Millisecond
+
+// DefaultTimeout bounds dialing and each read and write
+const DefaultTimeout = 5 * time.Second
+
+// ValkeyDB stores data in a single Valkey/Redis node, a cluster, or a Sentinel-managed
+// primary. Cluster and Sentinel clients follow failovers and resharding on their own;
+// WatchTopology reports them.
 type ValkeyDB struct {
-	client *redis.Client
-}
-
-func NewValkeyDB() (*ValkeyDB, error) {
-	// Get connection URI from environment - try multiple common environment variables
-	uri := os.Getenv("VALKEY_URI")
-	envSource := "VALKEY_URI"
-	if uri == "" {
-		uri = os.Getenv("REDIS_URL")
-		envSource = "REDIS_URL"
-	}
-	if uri == "" {
-		uri = os.Getenv("DATABASE_URL")
-		envSource = "DATABASE_URL"
-	}
-	if uri == "" {
-		// Try building from VALKEY_URL or component parts
-		if valkeyURL := os.Getenv("VALKEY_URL"); valkeyURL != "" {
-			uri = "redis://" + valkeyURL
-			envSource = "VALKEY_URL (with redis:// prefix)"
-		} else if host := os.Getenv("REDIS_HOST"); host != "" {
-			port := os.Getenv("REDIS_PORT")
-			if port == "" {
-				port = "6379"
-			}
-			uri = "redis://" + host + ":" + port
-			envSource = "REDIS_HOST + REDIS_PORT"
-		} else {
-			uri = "redis://localhost:6379"
-			envSource = "default localhost"
-		}
+	client redis.UniversalClient
+	mode   string
+	logger *slog.Logger
+
+	retry      retryPolicy
+	retries    atomic.Uint64
+	retryFails atomic.Uint64
+
+	slowCommand time.Duration // Commands taking at least this long are logged; 0 disables
+
+	topology *topologyWatch // Nil for a single node
+
+	uri     string        // Set by WithURI; otherwise found in the environment
+	timeout time.Duration // Set by WithTimeout
+}
+
+// Option configures optional ValkeyDB behavior
+type Option func(*ValkeyDB)
+
+// WithLogger sets the logger used when a request carries no scoped logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(v *ValkeyDB) {
+		v.logger = logger
 	}
+}
 
-	// Log the connection attempt (without credentials for security)
-	fmt.Printf("🔌 Database connection attempt using %s\n", envSource)
+// WithURI connects to uri instead of the URI found in the environment. Credentials,
+// TLS and pool settings still come from the VALKEY_* variables.
+func WithURI(uri string) Option {
+	return func(v *ValkeyDB) {
+		v.uri = uri
+	}
+}
 
-	opts, err := redis.ParseURL(uri)
+// WithTimeout bounds dialing and each read and write, DefaultTimeout otherwise
+func WithTimeout(timeout time.Duration) Option {
+	return func(v *ValkeyDB) {
+		v.timeout = timeout
+	}
+}
+
+func NewValkeyDB(options ...Option) (*ValkeyDB, error) {
+	v := &ValkeyDB{logger: slog.Default()}
+	for _, opt := range options {
+		opt(v)
+	}
+
+	uri, envSource := ConnectionURI()
+	if v.uri != "" {
+		uri, envSource = v.uri, "configuration"
+	}
+	settings, err := settingsFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if v.timeout > 0 {
+		settings.timeout = v.timeout
+	}
+
+	client, mode, addrs, err := newClient(uri, settings)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse Valkey URI from %s: %w", envSource, err)
 	}
 
-	// Set reasonable timeouts for cloud deployments
-	opts.DialTimeout = 5 * time.Second
-	opts.ReadTimeout = 5 * time.Second
-	opts.WriteTimeout = 5 * time.Second
+	if settings.tls != nil && settings.tls.InsecureSkipVerify {
+		v.logger.Warn("database TLS certificate verification disabled by VALKEY_TLS_INSECURE_SKIP_VERIFY")
+	}
 
-	client := redis.NewClient(opts)
+	// Log the connection attempt (without credentials for security)
+	v.logger.Info("database connection attempt", "source", envSource, "mode", mode, "tls", settings.tls != nil || strings.HasPrefix(uri, "rediss"))
 
 	// Test connection with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
@@ -66,27 +116,389 @@ func NewValkeyDB() (*ValkeyDB, error) {
 		client.Close()
 		// Provide helpful debugging information without exposing credentials
 		hostInfo := "unknown"
-		if opts.Addr != "" {
-			hostInfo = opts.Addr
+		if len(addrs) > 0 {
+			hostInfo = strings.Join(addrs, ",")
 		}
-		return nil, fmt.Errorf("failed to connect to Valkey at %s (from %s): %w", hostInfo, envSource, err)
+		return nil, fmt.Errorf("failed to connect to Valkey %s at %s (from %s): %w", mode, hostInfo, envSource, err)
 	}
 
-	return &ValkeyDB{client: client}, nil
+	v.client = client
+	v.mode = mode
+	v.retry = settings.retry
+	v.slowCommand = settings.slowCommand
+	v.topology = newTopology(uri, settings, client)
+	return v, nil
+}
+
+// ConnectionURI finds the connection URI in the environment, trying several common
+// variables, and reports where it came from
+func ConnectionURI() (string, string) {
+	if uri := os.Getenv("VALKEY_URI"); uri != "" {
+		return uri, "VALKEY_URI"
+	}
+	if uri := os.Getenv("REDIS_URL"); uri != "" {
+		return uri, "REDIS_URL"
+	}
+	if uri := os.Getenv("DATABASE_URL"); uri != "" {
+		return uri, "DATABASE_URL"
+	}
+
+	// Clusters and Sentinel deployments may list their nodes instead
+	if addrs := splitAddrs(os.Getenv("VALKEY_CLUSTER_ADDRS")); len(addrs) > 0 {
+		return multiNodeURI("redis+cluster", addrs, url.Values{}), "VALKEY_CLUSTER_ADDRS"
+	}
+	if addrs := splitAddrs(os.Getenv("VALKEY_SENTINEL_ADDRS")); len(addrs) > 0 {
+		query := url.Values{"master_name": {os.Getenv("VALKEY_SENTINEL_MASTER")}}
+		return multiNodeURI("redis+sentinel", addrs, query), "VALKEY_SENTINEL_ADDRS"
+	}
+
+	// Try building from VALKEY_URL or component parts
+	if valkeyURL := os.Getenv("VALKEY_URL"); valkeyURL != "" {
+		return "redis://" + valkeyURL, "VALKEY_URL (with redis:// prefix)"
+	}
+	if host := os.Getenv("REDIS_HOST"); host != "" {
+		port := os.Getenv("REDIS_PORT")
+		if port == "" {
+			port = "6379"
+		}
+		return "redis://" + host + ":" + port, "REDIS_HOST + REDIS_PORT"
+	}
+	return "redis://localhost:6379", "default localhost"
+}
+
+// multiNodeURI builds a cluster or Sentinel URI listing every node. Sentinels
+// authenticate with VALKEY_SENTINEL_USERNAME and VALKEY_SENTINEL_PASSWORD; data nodes
+// use the connection settings.
+func multiNodeURI(scheme string, addrs []string, query url.Values) string {
+	for _, addr := range addrs[1:] {
+		query.Add("addr", addr)
+	}
+
+	u := url.URL{Scheme: scheme, Host: addrs[0], RawQuery: query.Encode()}
+	if scheme == "redis+sentinel" {
+		username, password := os.Getenv("VALKEY_SENTINEL_USERNAME"), os.Getenv("VALKEY_SENTINEL_PASSWORD")
+		if username != "" || password != "" {
+			u.User = url.UserPassword(username, password)
+		}
+	}
+	return u.String()
+}
+
+// splitAddrs parses a comma-separated list of host:port addresses
+func splitAddrs(list string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(list, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// connSettings are connection options set apart from the URI. Zero values keep
+// what the URI says.
+type connSettings struct {
+	username string
+	password string
+	tls      *tls.Config // Replaces the URI's TLS choice when set
+
+	poolSize       int
+	minIdleConns   int
+	maxActiveConns int
+
+	retry       retryPolicy
+	slowCommand time.Duration
+	timeout     time.Duration // Dial, read and write timeout
+}
+
+// settingsFromEnv reads the connection settings. Managed offerings hand out ACL
+// credentials and private CA bundles separately from the endpoint, so these apply
+// whichever variable the URI came from.
+func settingsFromEnv() (connSettings, error) {
+	settings := connSettings{
+		username: os.Getenv("VALKEY_USERNAME"),
+		password: os.Getenv("VALKEY_PASSWORD"),
+		retry: retryPolicy{
+			maxRetries: DefaultMaxRetries,
+			minBackoff: DefaultMinRetryBackoff,
+			maxBackoff: DefaultMaxRetryBackoff,
+		},
+		slowCommand: DefaultSlowCommandThreshold,
+		timeout:     DefaultTimeout,
+	}
+
+	enableTLS, err := boolEnv("VALKEY_TLS")
+	if err != nil {
+		return settings, err
+	}
+	skipVerify, err := boolEnv("VALKEY_TLS_INSECURE_SKIP_VERIFY")
+	if err != nil {
+		return settings, err
+	}
+	caFile := os.Getenv("VALKEY_TLS_CA_FILE")
+	if enableTLS || skipVerify || caFile != "" {
+		settings.tls = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: skipVerify}
+		if caFile != "" {
+			pem, err := os.ReadFile(caFile)
+			if err != nil {
+				return settings, fmt.Errorf("failed to read VALKEY_TLS_CA_FILE: %w", err)
+			}
+			settings.tls.RootCAs = x509.NewCertPool()
+			if !settings.tls.RootCAs.AppendCertsFromPEM(pem) {
+				return settings, fmt.Errorf("VALKEY_TLS_CA_FILE %s contains no PEM certificates", caFile)
+			}
+		}
+	}
+
+	for key, target := range map[string]*int{
+		"VALKEY_POOL_SIZE":        &settings.poolSize,
+		"VALKEY_MIN_IDLE_CONNS":   &settings.minIdleConns,
+		"VALKEY_MAX_ACTIVE_CONNS": &settings.maxActiveConns,
+		"VALKEY_MAX_RETRIES":      &settings.retry.maxRetries,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return settings, fmt.Errorf("%s must be a non-negative integer, got %q", key, value)
+		}
+		*target = n
+	}
+
+	for key, target := range map[string]*time.Duration{
+		"VALKEY_MIN_RETRY_BACKOFF": &settings.retry.minBackoff,
+		"VALKEY_MAX_RETRY_BACKOFF": &settings.retry.maxBackoff,
+	} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return settings, fmt.Errorf("%s must be a positive duration, got %q", key, value)
+		}
+		*target = d
+	}
+	if value := os.Getenv("VALKEY_SLOW_COMMAND_THRESHOLD"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return settings, fmt.Errorf("VALKEY_SLOW_COMMAND_THRESHOLD must be a non-negative duration, got %q", value)
+		}
+		settings.slowCommand = d
+	}
+	if settings.retry.minBackoff > settings.retry.maxBackoff {
+		return settings, fmt.Errorf("VALKEY_MIN_RETRY_BACKOFF must not exceed VALKEY_MAX_RETRY_BACKOFF")
+	}
+	return settings, nil
+}
+
+// boolEnv parses an optional boolean environment variable
+func boolEnv(key string) (bool, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return false, nil
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s must be true or false, got %q", key, value)
+	}
+	return enabled, nil
+}
+
+// newClient creates a client for uri without connecting. The scheme picks the mode:
+// redis:// or rediss:// for a single node, redis+cluster:// for a cluster, and
+// redis+sentinel:// for a Sentinel-managed primary (with ?master_name=). Further
+// nodes are listed as ?addr=host:port parameters.
+func newClient(uri string, settings connSettings) (redis.UniversalClient, string, []string, error) {
+	uri, mode, err := splitScheme(uri)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	switch mode {
+	case "":
+		opts, err := redis.ParseURL(uri)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = settings.timeout, settings.timeout, settings.timeout
+		opts.MaxRetries = -1 // ValkeyDB retries with its own policy
+		settings.apply(&opts.Username, &opts.Password, &opts.TLSConfig, &opts.PoolSize, &opts.MinIdleConns, &opts.MaxActiveConns)
+		return redis.NewClient(opts), ModeStandalone, []string{opts.Addr}, nil
+
+	case ModeCluster:
+		opts, err := redis.ParseClusterURL(uri)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = settings.timeout, settings.timeout, settings.timeout
+		opts.MaxRetries = -1 // ValkeyDB retries with its own policy
+		settings.apply(&opts.Username, &opts.Password, &opts.TLSConfig, &opts.PoolSize, &opts.MinIdleConns, &opts.MaxActiveConns)
+		return redis.NewClusterClient(opts), ModeCluster, opts.Addrs, nil
+
+	case ModeSentinel:
+		opts, err := failoverOptions(uri, settings)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		return redis.NewFailoverClient(opts), ModeSentinel, opts.SentinelAddrs, nil
+
+	default:
+		return nil, "", nil, fmt.Errorf("unsupported connection mode %q", mode)
+	}
+}
+
+// splitScheme separates the mode from a URI's scheme, returning the URI with a plain
+// redis:// or rediss:// scheme
+func splitScheme(uri string) (string, string, error) {
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return "", "", fmt.Errorf("missing URI scheme")
+	}
+	base, mode, _ := strings.Cut(scheme, "+")
+	return base + "://" + rest, mode, nil
+}
+
+// failoverOptions parses a Sentinel URI with its plain scheme
+func failoverOptions(uri string, settings connSettings) (*redis.FailoverOptions, error) {
+	opts, err := redis.ParseFailoverURL(uri)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MasterName == "" {
+		return nil, fmt.Errorf("sentinel URI requires master_name (or set VALKEY_SENTINEL_MASTER)")
+	}
+	opts.DialTimeout, opts.ReadTimeout, opts.WriteTimeout = settings.timeout, settings.timeout, settings.timeout
+	opts.MaxRetries = -1 // ValkeyDB retries with its own policy
+	settings.apply(&opts.Username, &opts.Password, &opts.TLSConfig, &opts.PoolSize, &opts.MinIdleConns, &opts.MaxActiveConns)
+	return opts, nil
+}
+
+// apply overrides the parsed client options with the settings that are set. The
+// three client types share no options struct, so fields are passed individually.
+func (s connSettings) apply(username, password *string, tlsConfig **tls.Config, poolSize, minIdleConns, maxActiveConns *int) {
+	if s.username != "" {
+		*username = s.username
+	}
+	if s.password != "" {
+		*password = s.password
+	}
+	if s.tls != nil {
+		*tlsConfig = s.tls.Clone() // ServerName is inferred from each node's address
+	}
+	if s.poolSize > 0 {
+		*poolSize = s.poolSize
+	}
+	if s.minIdleConns > 0 {
+		*minIdleConns = s.minIdleConns
+	}
+	if s.maxActiveConns > 0 {
+		*maxActiveConns = s.maxActiveConns
+	}
+}
+
+// Mode returns the connection mode: standalone, cluster or sentinel
+func (v *ValkeyDB) Mode() string {
+	return v.mode
+}
+
+// Stats returns the connection pool and retry counters, and any failovers seen
+func (v *ValkeyDB) Stats() models.DatabaseStats {
+	pool := v.client.PoolStats()
+	stats := models.DatabaseStats{
+		Mode:       v.mode,
+		Hits:       pool.Hits,
+		Misses:     pool.Misses,
+		Timeouts:   pool.Timeouts,
+		TotalConns: pool.TotalConns,
+		IdleConns:  pool.IdleConns,
+		StaleConns: pool.StaleConns,
+		Retries:    v.retries.Load(),
+		RetryFails: v.retryFails.Load(),
+	}
+	if v.topology != nil {
+		stats.Failovers = v.topology.failovers.Load()
+		stats.Reshards = v.topology.reshards.Load()
+		stats.TopologyEvents = v.topology.recent()
+	}
+	return stats
+}
+
+// withRetry runs a command for key, retrying transient failures such as a dropped
+// connection or a failover in progress. Commands slower than the slow command
+// threshold, retries included, are logged with the request that ran them.
+func (v *ValkeyDB) withRetry(ctx context.Context, key string, command func() error) error {
+	start := time.Now()
+	defer func() {
+		if elapsed := time.Since(start); v.slowCommand > 0 && elapsed >= v.slowCommand {
+			logging.FromContext(ctx, v.logger).Warn("slow database command", "key", key, "duration_ms", elapsed.Milliseconds())
+		}
+	}()
+
+	err := v.retry.do(ctx, command, func(attempt int, err error) {
+		v.retries.Add(1)
+		logging.FromContext(ctx, v.logger).Warn("retrying database command", "key", key, "attempt", attempt, "error", err)
+	})
+	if v.retry.maxRetries > 0 && isTransient(err) {
+		v.retryFails.Add(1)
+	}
+	return err
 }
 
 func (v *ValkeyDB) Set(ctx context.Context, key string, value interface{}) error {
-	return v.client.Set(ctx, key, value, 0).Err() // 0 = no expiration
+	err := v.withRetry(ctx, key, func() error {
+		return v.client.Set(ctx, key, value, 0).Err() // 0 = no expiration
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database write failed", "key", key, "error", err)
+	}
+	return err
+}
+
+func (v *ValkeyDB) SetNX(ctx context.Context, key string, value interface{}) (bool, error) {
+	var set bool
+	err := v.withRetry(ctx, key, func() (err error) {
+		set, err = v.client.SetNX(ctx, key, value, 0).Result()
+		return err
+	})
+	if err != nil {
+		logging.FromContext(ctx, v.logger).Error("database write failed", "key", key, "error", err)
+	}
+	return set, err
 }
 
 func (v *ValkeyDB) Get(ctx context.Context, key string) (string, error) {
-	return v.client.Get(ctx, key).Result()
+	var value string
+	err := v.withRetry(ctx, key, func() (err error) {
+		value, err = v.client.Get(ctx, key).Result()
+		return err
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		logging.FromContext(ctx, v.logger).Error("database read failed", "key", key, "error", err)
+	}
+	return value, err
 }
 
 func (v *ValkeyDB) Ping(ctx context.Context) error {
 	return v.client.Ping(ctx).Err()
 }
 
+// Time returns the server's clock from the TIME command
+func (v *ValkeyDB) Time(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	err := v.withRetry(ctx, "", func() (err error) {
+		now, err = v.client.Time(ctx).Result()
+		return err
+	})
+	return now, err
+}
+
 func (v *ValkeyDB) Close() error {
-	return v.client.Close()
+	err := v.client.Close()
+	if v.topology != nil && v.topology.close != nil {
+		err = errors.Join(err, v.topology.close())
+	}
+	return err
 }
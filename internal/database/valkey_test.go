@@ -2,8 +2,11 @@ package database
 
 import (
 	"context"
+	"math"
 	"os"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDatabaseOperations(t *testing.T) {
@@ -43,6 +46,15 @@ func TestDatabaseOperations(t *testing.T) {
 		}
 	})
 
+	t.Run("reports pool stats", func(t *testing.T) {
+		if _, err := db.Get(ctx, "player:test:score"); err != nil {
+			t.Fatal(err)
+		}
+		if stats := db.Stats(); stats.Hits+stats.Misses == 0 || stats.TotalConns == 0 || stats.Mode != ModeStandalone {
+			t.Errorf("Expected pool activity on a standalone connection, got %+v", stats)
+		}
+	})
+
 	t.Run("returns error for non-existent keys", func(t *testing.T) {
 		// Should return an error when key doesn't exist
 		_, err := db.Get(ctx, "player:nonexistent:score")
@@ -74,4 +86,65 @@ func TestDatabaseOperations(t *testing.T) {
 			t.Errorf("Should get updated value %q, got %q", newValue, got)
 		}
 	})
+
+	t.Run("ranges sorted sets by score", func(t *testing.T) {
+		key := "test:sorted:index"
+		defer db.Del(ctx, key)
+
+		if err := db.ZAdd(ctx, key, ZMember{Score: 10, Member: "a"}, ZMember{Score: 30, Member: "b"}, ZMember{Score: 50, Member: "c"}); err != nil {
+			t.Fatalf("ZAdd failed: %v", err)
+		}
+		got, err := db.ZRangeByScore(ctx, key, ZRange{Min: 20, Max: math.Inf(1), Reverse: true})
+		if err != nil {
+			t.Fatalf("ZRangeByScore failed: %v", err)
+		}
+		if strings.Join(got, "") != "cb" {
+			t.Errorf("Expected c then b, got %v", got)
+		}
+		if got, _ := db.ZRangeByScore(ctx, key, ZRange{Min: math.Inf(-1), Max: math.Inf(1), Offset: 1, Count: 1}); strings.Join(got, "") != "b" {
+			t.Errorf("Expected b at offset 1, got %v", got)
+		}
+	})
+
+	t.Run("delivers published messages to subscribers", func(t *testing.T) {
+		subCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		messages, err := db.Subscribe(subCtx, "test:channel")
+		if err != nil {
+			t.Fatalf("Subscribe failed: %v", err)
+		}
+		if err := db.Publish(ctx, "test:channel", "hello"); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+		select {
+		case got := <-messages:
+			if got != "hello" {
+				t.Errorf("Expected hello, got %q", got)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Timed out waiting for the message")
+		}
+
+		cancel()
+		for range messages {
+		}
+	})
+	t.Run("takes tokens from a shared bucket", func(t *testing.T) {
+		key := "test:ratelimit"
+		defer db.Del(ctx, key)
+
+		for i := 0; i < 2; i++ {
+			if allowed, _, err := db.TakeToken(ctx, key, 0.01, 2); err != nil || !allowed {
+				t.Fatalf("token %d: expected it to be allowed, got %v (%v)", i+1, allowed, err)
+			}
+		}
+		allowed, retryAfter, err := db.TakeToken(ctx, key, 0.01, 2)
+		if err != nil || allowed {
+			t.Fatalf("Expected the empty bucket to refuse, got %v (%v)", allowed, err)
+		}
+		if retryAfter < 90*time.Second || retryAfter > 100*time.Second {
+			t.Errorf("Expected about 100s until the next token, got %v", retryAfter)
+		}
+	})
 }
@@ -0,0 +1,279 @@
+// Package devices enrolls cabinets. An operator registers a device and types its
+// one-time code into the cabinet, which redeems it for an API key of its own, scoped to
+// submitting its games, and the config it needs to reach them.
+package devices
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// devicesKey is the database key holding every device
+	devicesKey = "devices"
+	// codeAlphabet leaves out letters and digits that are easily confused on a cabinet's screen
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// codeLength is the number of code characters, 60 bits, typed in groups of codeGroup
+	codeLength = 12
+	codeGroup  = 4
+)
+
+// Service errors
+var (
+	ErrNotFound    = errors.New("device not found")
+	ErrEnrolled    = errors.New("device is enrolled; revoke it before registering it again")
+	ErrInvalidCode = errors.New("invalid or expired enrollment code")
+)
+
+// record is a device as stored, with the hash of its pending enrollment code
+type record struct {
+	models.Device
+	CodeHash string `json:"code_hash,omitempty"`
+}
+
+// Service manages devices and their enrollment
+type Service struct {
+	db      database.DB
+	keys    *apikeys.Store
+	codeTTL time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+}
+
+// NewService creates a device service issuing keys from keys
+func NewService(db database.DB, keys *apikeys.Store) *Service {
+	return &Service{db: db, keys: keys, codeTTL: models.DefaultEnrollmentCodeTTL, now: time.Now}
+}
+
+// List returns every device, by ID
+func (s *Service) List(ctx context.Context) ([]models.Device, error) {
+	records, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Device, 0, len(records))
+	for _, record := range records {
+		list = append(list, record.Device)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].DeviceID < list[j].DeviceID })
+	return list, nil
+}
+
+// Create registers a pending device and returns the code it enrolls with. A pending or
+// revoked device is replaced, invalidating any earlier code; an enrolled one must be
+// revoked first.
+func (s *Service) Create(ctx context.Context, device models.Device) (*models.DeviceEnrollmentCode, error) {
+	if err := device.Validate(); err != nil {
+		return nil, err
+	}
+	code, err := generateCode()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if existing, ok := records[device.DeviceID]; ok && existing.Status == models.DeviceStatusActive {
+		return nil, ErrEnrolled
+	}
+
+	now := s.now().UTC()
+	expiresAt := now.Add(s.codeTTL)
+	device.Status = models.DeviceStatusPending
+	device.KeyID = ""
+	device.CreatedAt = now
+	device.CodeExpiry = &expiresAt
+	device.EnrolledAt, device.RevokedAt = nil, nil
+
+	records[device.DeviceID] = record{Device: device, CodeHash: hashCode(code)}
+	if err := s.save(ctx, records); err != nil {
+		return nil, err
+	}
+	return &models.DeviceEnrollmentCode{Device: device, Code: code, ExpiresAt: expiresAt}, nil
+}
+
+// Enroll redeems an enrollment code, issuing the device its key. Codes work once.
+func (s *Service) Enroll(ctx context.Context, code string) (*models.EnrolledDevice, error) {
+	hash := hashCode(normalizeCode(code))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var device *record
+	for id, record := range records {
+		if record.CodeHash == hash && record.Status == models.DeviceStatusPending {
+			device = &record
+			device.DeviceID = id
+			break
+		}
+	}
+	now := s.now().UTC()
+	if device == nil || device.CodeExpiry == nil || !now.Before(*device.CodeExpiry) {
+		return nil, ErrInvalidCode
+	}
+
+	key, err := s.keys.CreateForDevice(ctx, device.DeviceID, device.GameIDs())
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue device key: %w", err)
+	}
+
+	device.Status = models.DeviceStatusActive
+	device.KeyID = key.ID
+	device.EnrolledAt = &now
+	device.CodeExpiry = nil
+	device.CodeHash = ""
+	records[device.DeviceID] = *device
+	if err := s.save(ctx, records); err != nil {
+		s.keys.Revoke(ctx, key.ID) // Don't leave a key the device was never told about
+		return nil, err
+	}
+
+	return &models.EnrolledDevice{
+		Key: key.Key,
+		Config: models.DeviceConfig{
+			DeviceID:  device.DeviceID,
+			Games:     device.Games,
+			Endpoints: endpoints(ctx),
+		},
+	}, nil
+}
+
+// Revoke disables a device's key and any pending code; revoked devices stay listed
+func (s *Service) Revoke(ctx context.Context, deviceID string) (*models.Device, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	device, ok := records[deviceID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if device.Status == models.DeviceStatusRevoked {
+		return &device.Device, nil
+	}
+
+	if device.KeyID != "" {
+		if _, err := s.keys.Revoke(ctx, device.KeyID); err != nil && !errors.Is(err, apikeys.ErrNotFound) {
+			return nil, fmt.Errorf("failed to revoke device key: %w", err)
+		}
+	}
+	now := s.now().UTC()
+	device.Status = models.DeviceStatusRevoked
+	device.RevokedAt = &now
+	device.CodeExpiry = nil
+	device.CodeHash = ""
+	records[deviceID] = device
+	if err := s.save(ctx, records); err != nil {
+		return nil, err
+	}
+	return &device.Device, nil
+}
+
+// endpoints returns the API paths for devices of the tenant ctx acts for
+func endpoints(ctx context.Context) models.DeviceEndpoints {
+	base := "/api/v1"
+	if tenant := tenants.FromContext(ctx); tenant != "" {
+		base += "/tenants/" + tenant
+	}
+	return models.DeviceEndpoints{
+		SubmitScore: base + "/games/{gameId}/scores",
+		Leaderboard: base + "/games/{gameId}/leaderboard",
+		Events:      base + "/games/{gameId}/events",
+	}
+}
+
+// load reads every device, keyed by ID
+func (s *Service) load(ctx context.Context) (map[string]record, error) {
+	records := map[string]record{}
+	value, err := s.db.Get(ctx, devicesKey)
+	if errors.Is(err, redis.Nil) {
+		return records, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(value), &records); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", devicesKey, err)
+	}
+	if records == nil {
+		records = map[string]record{}
+	}
+	return records, nil
+}
+
+// save writes every device
+func (s *Service) save(ctx context.Context, records map[string]record) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", devicesKey, err)
+	}
+	if err := s.db.Set(ctx, devicesKey, string(data)); err != nil {
+		return fmt.Errorf("failed to save %s: %w", devicesKey, err)
+	}
+	return nil
+}
+
+// generateCode creates a random enrollment code in dash-separated groups
+func generateCode() (string, error) {
+	buf := make([]byte, codeLength)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate enrollment code: %w", err)
+	}
+
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%codeGroup == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(codeAlphabet[int(b)%len(codeAlphabet)]) // 256 is a multiple of 32, so unbiased
+	}
+	return code.String(), nil
+}
+
+// normalizeCode accepts codes typed in lower case or without their dashes
+func normalizeCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	var grouped strings.Builder
+	for i, r := range code {
+		if i > 0 && i%codeGroup == 0 {
+			grouped.WriteByte('-')
+		}
+		grouped.WriteRune(r)
+	}
+	return grouped.String()
+}
+
+// hashCode returns the hex SHA-256 of a code, which is all that's stored
+func hashCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
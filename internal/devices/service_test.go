@@ -0,0 +1,116 @@
+package devices
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+func TestService(t *testing.T) {
+	ctx := tenants.WithTenant(context.Background(), "acme")
+	newService := func() (*Service, *apikeys.Store) {
+		db := database.NewFake()
+		keys := apikeys.NewStore(db)
+		return NewService(tenants.NewDB(db), keys), keys
+	}
+	cabinet := models.Device{DeviceID: "cabinet-1", Games: map[string]string{"pacman": "pacman", "mspacman": "pacman", "galaga": "galaga"}}
+
+	t.Run("enrolls a device once with a key for its games", func(t *testing.T) {
+		service, keys := newService()
+		code, err := service.Create(ctx, cabinet)
+		if err != nil {
+			t.Fatalf("Failed to create device: %v", err)
+		}
+		if code.Device.Status != models.DeviceStatusPending {
+			t.Errorf("Expected a pending device, got %s", code.Device.Status)
+		}
+
+		// Codes are accepted however they're typed
+		enrolled, err := service.Enroll(ctx, strings.ToLower(strings.ReplaceAll(code.Code, "-", "")))
+		if err != nil {
+			t.Fatalf("Failed to enroll: %v", err)
+		}
+		if enrolled.Config.DeviceID != "cabinet-1" || enrolled.Config.Endpoints.SubmitScore != "/api/v1/tenants/acme/games/{gameId}/scores" {
+			t.Errorf("Unexpected config: %+v", enrolled.Config)
+		}
+
+		key, err := keys.Resolve(ctx, enrolled.Key)
+		if err != nil {
+			t.Fatalf("Expected the device key to resolve: %v", err)
+		}
+		p := apikeys.PrincipalFor(key)
+		if p.Tenant != "acme" || !p.HasScope(models.ScopeSubmit) || p.HasScope(models.ScopeAdminRead) ||
+			!p.CanAccessGame("galaga") || p.CanAccessGame("tetris") {
+			t.Errorf("Unexpected device key: %+v", p)
+		}
+
+		if _, err := service.Enroll(ctx, code.Code); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Expected a used code to be refused, got %v", err)
+		}
+		if _, err := service.Create(ctx, cabinet); !errors.Is(err, ErrEnrolled) {
+			t.Errorf("Expected an enrolled device ID to be refused, got %v", err)
+		}
+	})
+
+	t.Run("refuses expired codes", func(t *testing.T) {
+		service, _ := newService()
+		code, err := service.Create(ctx, cabinet)
+		if err != nil {
+			t.Fatalf("Failed to create device: %v", err)
+		}
+
+		service.now = func() time.Time { return time.Now().Add(models.DefaultEnrollmentCodeTTL + time.Minute) }
+		if _, err := service.Enroll(ctx, code.Code); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Expected an expired code to be refused, got %v", err)
+		}
+	})
+
+	t.Run("revoking disables the key and allows re-registration", func(t *testing.T) {
+		service, keys := newService()
+		code, err := service.Create(ctx, cabinet)
+		if err != nil {
+			t.Fatalf("Failed to create device: %v", err)
+		}
+		enrolled, err := service.Enroll(ctx, code.Code)
+		if err != nil {
+			t.Fatalf("Failed to enroll: %v", err)
+		}
+
+		device, err := service.Revoke(ctx, "cabinet-1")
+		if err != nil || device.Status != models.DeviceStatusRevoked {
+			t.Fatalf("Failed to revoke: %+v, %v", device, err)
+		}
+		if _, err := keys.Resolve(ctx, enrolled.Key); err == nil {
+			t.Error("Expected the device key to stop working")
+		}
+		if _, err := service.Revoke(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+
+		if _, err := service.Create(ctx, cabinet); err != nil {
+			t.Errorf("Expected a revoked device to be registered again, got %v", err)
+		}
+		list, err := service.List(ctx)
+		if err != nil || len(list) != 1 || list[0].Status != models.DeviceStatusPending {
+			t.Errorf("Unexpected devices: %+v, %v", list, err)
+		}
+	})
+
+	t.Run("keeps tenants' devices apart", func(t *testing.T) {
+		service, _ := newService()
+		code, err := service.Create(ctx, cabinet)
+		if err != nil {
+			t.Fatalf("Failed to create device: %v", err)
+		}
+		if _, err := service.Enroll(context.Background(), code.Code); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Expected another tenant's code to be refused, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package displays
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+// actor identifies the display monitor in the audit log
+const actor = "system:display-monitor"
+
+// alertTimeout bounds each post to the alert URL
+const alertTimeout = 10 * time.Second
+
+// Monitor watches display devices check in and reports those that go dark, and come
+// back, in the audit log and to an alert URL
+type Monitor struct {
+	store        *Store
+	audit        *audit.Log
+	logger       *slog.Logger
+	offlineAfter time.Duration
+	alertURL     string
+	client       *http.Client
+	now          func() time.Time
+}
+
+// NewMonitor creates a monitor treating devices silent for offlineAfter as dark;
+// alerts are only posted when alertURL is set
+func NewMonitor(store *Store, auditLog *audit.Log, logger *slog.Logger, offlineAfter time.Duration, alertURL string) *Monitor {
+	return &Monitor{
+		store:        store,
+		audit:        auditLog,
+		logger:       logger,
+		offlineAfter: offlineAfter,
+		alertURL:     alertURL,
+		client:       &http.Client{Timeout: alertTimeout},
+		now:          time.Now,
+	}
+}
+
+// HeartbeatInterval is how often devices should send heartbeats, so a few can be
+// missed before one is reported dark
+func (m *Monitor) HeartbeatInterval() time.Duration {
+	return max(m.offlineAfter/4, time.Second)
+}
+
+// Status reports which of a display's devices are online
+func (m *Monitor) Status(ctx context.Context, display *models.Display) (*models.DisplayStatus, error) {
+	devices, err := m.store.Devices(ctx, display.DisplayID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &models.DisplayStatus{DisplayID: display.DisplayID, Name: display.Name, Devices: devices}
+	now := m.now()
+	for i := range status.Devices {
+		status.Devices[i].Online = !m.dark(&status.Devices[i], now)
+		if status.Devices[i].Online {
+			status.Online++
+		} else {
+			status.Offline++
+		}
+	}
+	return status, nil
+}
+
+// Run checks every display's devices, alerting once when one goes dark and once when
+// it checks in again
+func (m *Monitor) Run(ctx context.Context) error {
+	list, err := m.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list displays: %w", err)
+	}
+
+	now := m.now()
+	for _, display := range list {
+		devices, err := m.store.Devices(ctx, display.DisplayID)
+		if err != nil {
+			return fmt.Errorf("failed to list devices for %s: %w", display.DisplayID, err)
+		}
+
+		for _, device := range devices {
+			dark := m.dark(&device, now)
+			if dark == (device.OfflineSince != nil) {
+				continue
+			}
+
+			event := models.DisplayEventOnline
+			if dark {
+				event = models.DisplayEventOffline
+			}
+			if _, err := m.store.TouchDevice(ctx, display.DisplayID, device.DeviceID, now, func(d *models.DisplayDevice) {
+				d.OfflineSince = nil
+				if dark {
+					d.OfflineSince = &now
+				}
+			}); err != nil {
+				return fmt.Errorf("failed to update device %s: %w", device.DeviceID, err)
+			}
+			m.alert(ctx, event, display.DisplayID, device)
+		}
+	}
+	return nil
+}
+
+// dark reports whether a device has been silent for too long at now
+func (m *Monitor) dark(device *models.DisplayDevice, now time.Time) bool {
+	return now.Sub(device.LastSeen()) >= m.offlineAfter
+}
+
+// alert records a device going dark or coming back and posts it to the alert URL
+func (m *Monitor) alert(ctx context.Context, event, displayID string, device models.DisplayDevice) {
+	alert := models.DisplayAlert{
+		Event:     event,
+		DisplayID: displayID,
+		DeviceID:  device.DeviceID,
+		Name:      device.Name,
+		Tenant:    tenants.FromContext(ctx),
+		LastSeen:  device.LastSeen(),
+	}
+	if event == models.DisplayEventOffline {
+		alert.Text = fmt.Sprintf("Display %s device %s went dark; last seen %s", displayID, device.DeviceID, alert.LastSeen.Format(time.RFC3339))
+		m.logger.Warn("display device went dark", "tenant", alert.Tenant, "display_id", displayID, "device_id", device.DeviceID, "last_seen", alert.LastSeen)
+	} else {
+		alert.Text = fmt.Sprintf("Display %s device %s is back online", displayID, device.DeviceID)
+		m.logger.Info("display device back online", "tenant", alert.Tenant, "display_id", displayID, "device_id", device.DeviceID)
+	}
+
+	action := audit.ActionDisplayOffline
+	if event == models.DisplayEventOnline {
+		action = audit.ActionDisplayOnline
+	}
+	if err := m.audit.Record(ctx, models.AuditEntry{
+		Action:  action,
+		Actor:   actor,
+		Details: map[string]interface{}{"display_id": displayID, "device_id": device.DeviceID, "last_seen": alert.LastSeen},
+	}); err != nil {
+		m.logger.Warn("failed to record display audit entry", "display_id", displayID, "error", err)
+	}
+
+	if m.alertURL == "" {
+		return
+	}
+	if err := m.post(ctx, alert); err != nil {
+		m.logger.Warn("failed to post display alert", "display_id", displayID, "device_id", device.DeviceID, "error", err)
+	}
+}
+
+// post sends an alert to the alert URL
+func (m *Monitor) post(ctx context.Context, alert models.DisplayAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.alertURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert URL answered %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package displays
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestMonitor(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+	store := NewStore(db)
+
+	alerts := make(chan models.DisplayAlert, 4)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert models.DisplayAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("Failed to decode alert: %v", err)
+		}
+		alerts <- alert
+	}))
+	defer receiver.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	monitor := NewMonitor(store, audit.NewLog(db), logger, time.Minute, receiver.URL)
+
+	if _, err := store.Put(ctx, models.Display{DisplayID: "lobby", Slides: []models.DisplaySlide{{GameID: "pacman", Seconds: 10}}}); err != nil {
+		t.Fatalf("Failed to store display: %v", err)
+	}
+	start := time.Now()
+	heartbeat := func(at time.Time) {
+		if _, err := store.TouchDevice(ctx, "lobby", "tv-1", at, func(device *models.DisplayDevice) {
+			device.LastHeartbeat = &at
+		}); err != nil {
+			t.Fatalf("Failed to record heartbeat: %v", err)
+		}
+	}
+	runAt := func(at time.Time) {
+		monitor.now = func() time.Time { return at }
+		if err := monitor.Run(ctx); err != nil {
+			t.Fatalf("Monitor run failed: %v", err)
+		}
+	}
+	expectAlert := func(event string) {
+		select {
+		case alert := <-alerts:
+			if alert.Event != event || alert.DeviceID != "tv-1" {
+				t.Errorf("Expected a %s alert for tv-1, got %+v", event, alert)
+			}
+		default:
+			t.Errorf("Expected a %s alert", event)
+		}
+	}
+
+	heartbeat(start)
+	runAt(start.Add(30 * time.Second))
+	if len(alerts) != 0 {
+		t.Fatalf("Expected no alert while the device checks in, got %+v", <-alerts)
+	}
+
+	// A device that goes quiet is reported dark once
+	runAt(start.Add(2 * time.Minute))
+	expectAlert(models.DisplayEventOffline)
+	runAt(start.Add(3 * time.Minute))
+	if len(alerts) != 0 {
+		t.Errorf("Expected a single offline alert, got %+v", <-alerts)
+	}
+
+	status, err := monitor.Status(ctx, &models.Display{DisplayID: "lobby"})
+	if err != nil || status.Offline != 1 || status.Devices[0].OfflineSince == nil {
+		t.Fatalf("Expected the device to be offline, got %+v (%v)", status, err)
+	}
+
+	// And reported again when it comes back
+	heartbeat(start.Add(4 * time.Minute))
+	runAt(start.Add(4 * time.Minute))
+	expectAlert(models.DisplayEventOnline)
+}
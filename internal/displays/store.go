@@ -0,0 +1,238 @@
+package displays
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// displaysKey is the database key holding every display's rotation
+	displaysKey = "displays"
+	// devicesPrefix is the database key prefix for the device IDs registered to each display
+	devicesPrefix = "display_devices:"
+	// devicePrefix is the database key prefix for each device's last report
+	devicePrefix = "display_device:"
+)
+
+// Store errors
+var (
+	ErrNotFound       = errors.New("display not found")
+	ErrDeviceNotFound = errors.New("display device not found")
+	ErrTooManyDevices = fmt.Errorf("a display can have at most %d devices", models.MaxDisplayDevices)
+)
+
+// Store manages the rotations configured for venue displays
+type Store struct {
+	db database.DB
+	mu sync.Mutex
+}
+
+// NewStore creates a new display store
+func NewStore(db database.DB) *Store {
+	return &Store{db: db}
+}
+
+// List returns every display, by ID
+func (s *Store) List(ctx context.Context) ([]models.Display, error) {
+	displays, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Display, 0, len(displays))
+	for _, display := range displays {
+		list = append(list, display)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].DisplayID < list[j].DisplayID })
+	return list, nil
+}
+
+// Get returns a display
+func (s *Store) Get(ctx context.Context, displayID string) (*models.Display, error) {
+	displays, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	display, ok := displays[displayID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &display, nil
+}
+
+// Put validates and stores a display's rotation, replacing any it had
+func (s *Store) Put(ctx context.Context, display models.Display) (*models.Display, error) {
+	if err := display.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	displays, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	display.Updated = time.Now().UTC()
+	displays[display.DisplayID] = display
+	if err := s.save(ctx, displays); err != nil {
+		return nil, err
+	}
+	return &display, nil
+}
+
+// Delete removes a display, returning it
+func (s *Store) Delete(ctx context.Context, displayID string) (*models.Display, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	displays, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	display, ok := displays[displayID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(displays, displayID)
+	if err := s.save(ctx, displays); err != nil {
+		return nil, err
+	}
+	if err := s.saveJSON(ctx, devicesPrefix+displayID, []string{}); err != nil {
+		return nil, err
+	}
+	return &display, nil
+}
+
+// Devices returns the devices registered to a display, by ID
+func (s *Store) Devices(ctx context.Context, displayID string) ([]models.DisplayDevice, error) {
+	deviceIDs, err := s.deviceIDs(ctx, displayID)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]models.DisplayDevice, 0, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		var device models.DisplayDevice
+		if found, err := s.loadJSON(ctx, devicePrefix+displayID+":"+deviceID, &device); err != nil {
+			return nil, err
+		} else if found {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// TouchDevice applies update to a device's record and stores it, registering the device
+// with the display first if it's new
+func (s *Store) TouchDevice(ctx context.Context, displayID, deviceID string, now time.Time, update func(device *models.DisplayDevice)) (*models.DisplayDevice, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deviceIDs, err := s.deviceIDs(ctx, displayID)
+	if err != nil {
+		return nil, err
+	}
+
+	device := models.DisplayDevice{DeviceID: deviceID, RegisteredAt: now}
+	if slices.Contains(deviceIDs, deviceID) {
+		if _, err := s.loadJSON(ctx, devicePrefix+displayID+":"+deviceID, &device); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(deviceIDs) >= models.MaxDisplayDevices {
+			return nil, ErrTooManyDevices
+		}
+		deviceIDs = append(deviceIDs, deviceID)
+		sort.Strings(deviceIDs)
+		if err := s.saveJSON(ctx, devicesPrefix+displayID, deviceIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	update(&device)
+	if err := s.saveJSON(ctx, devicePrefix+displayID+":"+deviceID, device); err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// RemoveDevice unregisters a device from a display
+func (s *Store) RemoveDevice(ctx context.Context, displayID, deviceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deviceIDs, err := s.deviceIDs(ctx, displayID)
+	if err != nil {
+		return err
+	}
+	i := slices.Index(deviceIDs, deviceID)
+	if i < 0 {
+		return ErrDeviceNotFound
+	}
+	return s.saveJSON(ctx, devicesPrefix+displayID, slices.Delete(deviceIDs, i, i+1))
+}
+
+// deviceIDs returns the IDs of the devices registered to a display
+func (s *Store) deviceIDs(ctx context.Context, displayID string) ([]string, error) {
+	deviceIDs := []string{}
+	if _, err := s.loadJSON(ctx, devicesPrefix+displayID, &deviceIDs); err != nil {
+		return nil, err
+	}
+	return deviceIDs, nil
+}
+
+// loadJSON reads key into v, reporting whether it was stored
+func (s *Store) loadJSON(ctx context.Context, key string, v interface{}) (bool, error) {
+	value, err := s.db.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return false, fmt.Errorf("failed to parse %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// saveJSON writes v to key
+func (s *Store) saveJSON(ctx context.Context, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	if err := s.db.Set(ctx, key, string(data)); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
+	return nil
+}
+
+// load reads every display, keyed by ID
+func (s *Store) load(ctx context.Context) (map[string]models.Display, error) {
+	displays := map[string]models.Display{}
+	if _, err := s.loadJSON(ctx, displaysKey, &displays); err != nil {
+		return nil, err
+	}
+	if displays == nil {
+		displays = map[string]models.Display{}
+	}
+	return displays, nil
+}
+
+// save writes every display
+func (s *Store) save(ctx context.Context, displays map[string]models.Display) error {
+	return s.saveJSON(ctx, displaysKey, displays)
+}
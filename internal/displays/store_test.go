@@ -0,0 +1,60 @@
+package displays
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(database.NewFake())
+
+	display, err := store.Put(ctx, models.Display{
+		DisplayID: "lobby",
+		Slides: []models.DisplaySlide{
+			{GameID: "pacman", Seconds: 20, Limit: 5},
+			{GameID: "galaga", View: models.DisplayViewSummary, Seconds: 10},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to store display: %v", err)
+	}
+	if display.Slides[0].View != models.DisplayViewLeaderboard || display.Updated.IsZero() {
+		t.Errorf("Expected the default view and an update time, got %+v", display)
+	}
+
+	rotation := display.Rotation()
+	if rotation.CycleSeconds != 30 {
+		t.Errorf("Expected a 30 second cycle, got %d", rotation.CycleSeconds)
+	}
+	if got := rotation.Slides[0].Path; got != "/api/v1/games/pacman/leaderboard?limit=5" {
+		t.Errorf("Unexpected leaderboard path %q", got)
+	}
+	if got := rotation.Slides[1].Path; got != "/public/games/galaga/summary" {
+		t.Errorf("Unexpected summary path %q", got)
+	}
+
+	for _, invalid := range []models.Display{
+		{DisplayID: "empty"},
+		{DisplayID: "short", Slides: []models.DisplaySlide{{GameID: "pacman", Seconds: 1}}},
+		{DisplayID: "view", Slides: []models.DisplaySlide{{GameID: "pacman", View: "attract", Seconds: 10}}},
+	} {
+		if _, err := store.Put(ctx, invalid); err == nil {
+			t.Errorf("Expected %s to be rejected", invalid.DisplayID)
+		}
+	}
+
+	if list, err := store.List(ctx); err != nil || len(list) != 1 {
+		t.Errorf("Expected one display, got %+v (%v)", list, err)
+	}
+	if _, err := store.Delete(ctx, "lobby"); err != nil {
+		t.Fatalf("Failed to delete display: %v", err)
+	}
+	if _, err := store.Get(ctx, "lobby"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after deleting, got %v", err)
+	}
+}
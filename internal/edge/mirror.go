@@ -0,0 +1,604 @@
+// Package edge runs rawboard at venues far from the central server: cabinets submit to
+// and read from a local instance, which forwards their scores to the central instance in
+// the background and merges the central leaderboards back in
+package edge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+// DefaultRequestTimeout is how long each call to the central instance may take
+const DefaultRequestTimeout = 10 * time.Second
+
+// maxOutbox is how many submissions wait for the central instance; the oldest are
+// dropped beyond it
+const maxOutbox = 10000
+
+// outboxKey holds the submissions waiting to be forwarded, for every tenant
+const outboxKey = "edge:outbox"
+
+// maxDeadLetters is how many refused submissions are kept for replay; the oldest are
+// dropped beyond it
+const maxDeadLetters = 10000
+
+// deadLettersKey holds the submissions the central instance refused, for every tenant
+const deadLettersKey = "edge:dead_letters"
+
+// userAgent identifies edge instances to the central one
+const userAgent = "rawboard-edge/1.0"
+
+// errUnreachable marks failures worth retrying: the central instance couldn't be
+// reached, failed, or refused the edge's credentials
+var errUnreachable = errors.New("central instance unavailable")
+
+// ErrDeadLetterNotFound is returned when replaying a refused submission the edge doesn't
+// keep
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+// refusedError is the central instance refusing a submission outright
+type refusedError struct {
+	status int
+	detail string
+}
+
+func (e *refusedError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.status, e.detail)
+}
+
+// forward is a submission waiting to be sent to the central instance
+type forward struct {
+	ScoreID   string                 `json:"score_id"` // The local entry, for logs and removal
+	GameID    string                 `json:"game_id"`
+	Tenant    string                 `json:"tenant,omitempty"`
+	Initials  string                 `json:"initials"`
+	Score     string                 `json:"score"` // As played, written with the game's decimals
+	Metadata  models.ScoreMetadata   `json:"metadata,omitempty"`
+	SessionID string                 `json:"session_id,omitempty"`
+	DeviceID  string                 `json:"device_id,omitempty"`
+	PlayedAt  time.Time              `json:"played_at"`
+	Sequence  int64                  `json:"sequence,omitempty"` // The local entry's, keeping the queue's order in ties
+	PIN       string                 `json:"pin,omitempty"`
+	Signature *models.ScoreSignature `json:"signature,omitempty"`
+	Queued    time.Time              `json:"queued"`
+}
+
+// deadLetter is a submission the central instance refused, with its answer
+type deadLetter struct {
+	forward
+	Status    int       `json:"status"`
+	Error     string    `json:"error"`
+	RefusedAt time.Time `json:"refused_at"`
+}
+
+// Mirror is a score listener queueing every counted local submission for the central
+// instance. The queue is kept in the local database, so scores taken while the central
+// instance is out of reach survive restarts and are sent once it is back.
+type Mirror struct {
+	db       database.DB // Holds the outbox, outside any tenant
+	upstream string
+	apiKey   string
+	games    []string // Mirrored even before they have a local score
+	client   *http.Client
+	logger   *slog.Logger
+
+	mu      sync.Mutex // Guards the read-modify-write of the outbox, and etags
+	flushMu sync.Mutex // One flush at a time, so each submission is sent once
+	etags   map[string]string
+	wake    chan struct{}
+}
+
+// Option configures optional Mirror behavior
+type Option func(*Mirror)
+
+// WithHTTPClient sets the client used to call the central instance
+func WithHTTPClient(client *http.Client) Option {
+	return func(m *Mirror) {
+		m.client = client
+	}
+}
+
+// WithLogger sets the logger used to report failed forwards and merges
+func WithLogger(logger *slog.Logger) Option {
+	return func(m *Mirror) {
+		m.logger = logger
+	}
+}
+
+// WithGames mirrors the central boards of games, in every tenant, before they are
+// played locally
+func WithGames(gameIDs ...string) Option {
+	return func(m *Mirror) {
+		m.games = append(m.games, gameIDs...)
+	}
+}
+
+// NewMirror creates a mirror of the instance at upstream, such as
+// https://scores.example.com, authenticating with apiKey if it is set. Nothing is sent
+// until Run or Flush; db holds the outbox.
+func NewMirror(db database.DB, upstream, apiKey string, opts ...Option) *Mirror {
+	m := &Mirror{
+		db:       db,
+		upstream: upstream,
+		apiKey:   apiKey,
+		client:   &http.Client{Timeout: DefaultRequestTimeout},
+		logger:   slog.Default(),
+		etags:    make(map[string]string),
+		wake:     make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// ScoreSubmitted queues a counted submission for the central instance, which applies
+// its own rules to it. Happy hours are the central instance's too, so the score as
+// played is sent, with when and on which device it was played and the PIN and
+// signature it came with. Only the local database is written; Run does the sending.
+func (m *Mirror) ScoreSubmitted(event models.ScoreEvent) {
+	ctx := context.Background()
+	item := forward{
+		ScoreID:   event.Entry.ID,
+		GameID:    event.GameID,
+		Tenant:    event.Tenant,
+		Initials:  event.Entry.Initials,
+		Score:     playedScore(event.Entry),
+		Metadata:  event.Entry.Metadata,
+		SessionID: event.Entry.SessionID,
+		DeviceID:  event.Entry.DeviceID,
+		PlayedAt:  event.Entry.Timestamp,
+		Sequence:  event.Entry.Sequence,
+		PIN:       event.PIN,
+		Signature: event.Signature,
+		Queued:    time.Now(),
+	}
+
+	m.mu.Lock()
+	outbox, err := m.outbox(ctx)
+	if err == nil {
+		outbox = append(outbox, item)
+		if dropped := len(outbox) - maxOutbox; dropped > 0 {
+			m.logger.Warn("edge outbox full, dropping the oldest submissions", "dropped", dropped)
+			outbox = outbox[dropped:]
+		}
+		err = m.saveOutbox(ctx, outbox)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		m.logger.Error("failed to queue submission for the central instance", "game_id", event.GameID, "score_id", event.Entry.ID, "error", err)
+		return
+	}
+	m.notify()
+}
+
+// notify wakes Run to flush the outbox
+func (m *Mirror) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default: // A flush is already due
+	}
+}
+
+// Run forwards queued submissions as they arrive until ctx is done. Submissions the
+// central instance couldn't take wait for the next Flush.
+func (m *Mirror) Run(ctx context.Context) {
+	// Send what was queued before a restart
+	m.notify()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.wake:
+			if _, err := m.Flush(ctx); err != nil && ctx.Err() == nil {
+				m.logger.Warn("failed to forward submissions to the central instance, will retry", "error", err)
+			}
+		}
+	}
+}
+
+// Flush sends queued submissions to the central instance, oldest first, and returns
+// how many it took. It stops at the first the central instance can't take right now;
+// those it refuses outright, such as for its anti-cheat rules, move to the dead letters
+// for an operator to look into and Replay.
+func (m *Mirror) Flush(ctx context.Context) (int, error) {
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+
+	m.mu.Lock()
+	pending, err := m.outbox(ctx)
+	m.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for i, item := range pending {
+		err := m.send(ctx, item)
+		if errors.Is(err, errUnreachable) {
+			return sent, fmt.Errorf("%w (%d submissions waiting)", err, len(pending)-i)
+		}
+		if err != nil {
+			m.logger.Warn("central instance refused a forwarded submission, keeping it as a dead letter",
+				"game_id", item.GameID, "tenant", item.Tenant, "initials", item.Initials, "score", item.Score, "error", err)
+			if err := m.bury(ctx, item, err); err != nil {
+				return sent, err
+			}
+			continue
+		}
+		sent++
+		if err := m.remove(ctx, item.ScoreID); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// Pending returns how many submissions wait for the central instance
+func (m *Mirror) Pending(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	outbox, err := m.outbox(ctx)
+	return len(outbox), err
+}
+
+// DeadLetters returns the submissions the central instance refused, oldest first
+func (m *Mirror) DeadLetters(ctx context.Context) (*models.EdgeDeadLetters, error) {
+	m.mu.Lock()
+	letters, err := m.deadLetters(ctx)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &models.EdgeDeadLetters{DeadLetters: make([]models.EdgeDeadLetter, 0, len(letters)), Total: len(letters)}
+	for _, letter := range letters {
+		// The PIN and signature stay out of listings
+		list.DeadLetters = append(list.DeadLetters, models.EdgeDeadLetter{
+			ScoreID:   letter.ScoreID,
+			GameID:    letter.GameID,
+			Tenant:    letter.Tenant,
+			Initials:  letter.Initials,
+			Score:     letter.Score,
+			Metadata:  letter.Metadata,
+			SessionID: letter.SessionID,
+			DeviceID:  letter.DeviceID,
+			PlayedAt:  letter.PlayedAt,
+			Status:    letter.Status,
+			Error:     letter.Error,
+			RefusedAt: letter.RefusedAt,
+		})
+	}
+	return list, nil
+}
+
+// Replay moves a refused submission back to the outbox, after the submissions already
+// waiting, and wakes Run to send it again; an empty scoreID replays every one. Unknown
+// submissions fail with ErrDeadLetterNotFound. It returns how many were replayed.
+func (m *Mirror) Replay(ctx context.Context, scoreID string) (int, error) {
+	m.mu.Lock()
+	letters, err := m.deadLetters(ctx)
+	if err != nil {
+		m.mu.Unlock()
+		return 0, err
+	}
+	var replayed, kept []deadLetter
+	for _, letter := range letters {
+		if scoreID == "" || letter.ScoreID == scoreID {
+			replayed = append(replayed, letter)
+		} else {
+			kept = append(kept, letter)
+		}
+	}
+	if scoreID != "" && len(replayed) == 0 {
+		m.mu.Unlock()
+		return 0, fmt.Errorf("%w: %s", ErrDeadLetterNotFound, scoreID)
+	}
+
+	outbox, err := m.outbox(ctx)
+	if err == nil {
+		for _, letter := range replayed {
+			// A submission refused before a crash could still be queued
+			if !slices.ContainsFunc(outbox, func(item forward) bool { return item.ScoreID == letter.ScoreID }) {
+				outbox = append(outbox, letter.forward)
+			}
+		}
+		// The outbox is written first, so a failure part way leaves a submission in both
+		// rather than in neither
+		err = m.saveOutbox(ctx, outbox)
+	}
+	if err == nil {
+		err = m.saveDeadLetters(ctx, kept)
+	}
+	m.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if len(replayed) > 0 {
+		m.notify()
+	}
+	return len(replayed), nil
+}
+
+// Pull merges the central leaderboards of the tenant in ctx into service's, for games
+// played here and those mirrored from the start. Each player keeps the better of their
+// local and central high scores, so local scores not yet forwarded stay on the board.
+func (m *Mirror) Pull(ctx context.Context, service *leaderboard.Service) error {
+	gameIDs, err := service.ListGames(ctx)
+	if err != nil {
+		return err
+	}
+	for _, gameID := range m.games {
+		if !slices.Contains(gameIDs, gameID) {
+			gameIDs = append(gameIDs, gameID)
+		}
+	}
+
+	var errs []error
+	for _, gameID := range gameIDs {
+		board, err := m.fetchLeaderboard(ctx, gameID)
+		if errors.Is(err, errUnreachable) {
+			return err // The rest would fail the same way
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("game %s: %w", gameID, err))
+			continue
+		}
+		if board == nil {
+			continue // Unchanged, or not played centrally yet
+		}
+		if _, err := service.MergeHighScores(ctx, gameID, board.Entries); err != nil {
+			errs = append(errs, fmt.Errorf("game %s: %w", gameID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// playedScore writes an entry's score as played, with the decimals the central
+// instance parses it with
+func playedScore(entry models.ScoreEntry) string {
+	played := entry.Score
+	if entry.HappyHour != "" {
+		played = entry.RawScore
+	}
+	var scoring models.ScoringSettings
+	if _, decimals, ok := strings.Cut(entry.DisplayScore, "."); ok {
+		scoring.Decimals = len(decimals)
+	}
+	return scoring.Format(played)
+}
+
+// send submits one queued submission to the central instance
+func (m *Mirror) send(ctx context.Context, item forward) error {
+	submission := map[string]interface{}{
+		"initials":   item.Initials,
+		"score":      json.Number(item.Score),
+		"metadata":   item.Metadata,
+		"session_id": item.SessionID,
+	}
+	// Empty fields are left out, as is the play time of submissions queued before it was kept
+	if !item.PlayedAt.IsZero() {
+		submission["played_at"] = item.PlayedAt
+	}
+	if item.DeviceID != "" {
+		submission["device_id"] = item.DeviceID
+	}
+	if item.Sequence != 0 {
+		submission["sequence"] = item.Sequence
+	}
+	if item.PIN != "" {
+		submission["pin"] = item.PIN
+	}
+	if item.Signature != nil {
+		submission["signature"] = item.Signature
+	}
+	body, err := json.Marshal(submission)
+	if err != nil {
+		return fmt.Errorf("failed to encode submission: %w", err)
+	}
+	req, err := m.request(ctx, http.MethodPost, item.Tenant, item.GameID, "scores", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnreachable, err)
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	if retryable(resp.StatusCode) {
+		return fmt.Errorf("%w: status %d", errUnreachable, resp.StatusCode)
+	}
+	return &refusedError{status: resp.StatusCode, detail: string(bytes.TrimSpace(detail))}
+}
+
+// fetchLeaderboard returns a game's central leaderboard, or nil if it hasn't changed
+// since the last fetch or the central instance has no board for the game
+func (m *Mirror) fetchLeaderboard(ctx context.Context, gameID string) (*models.Leaderboard, error) {
+	req, err := m.request(ctx, http.MethodGet, tenants.FromContext(ctx), gameID, "leaderboard", nil)
+	if err != nil {
+		return nil, err
+	}
+	cacheKey := tenants.FromContext(ctx) + "/" + gameID
+	m.mu.Lock()
+	if etag := m.etags[cacheKey]; etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+	m.mu.Unlock()
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errUnreachable, err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotModified, resp.StatusCode == http.StatusNotFound:
+		return nil, nil
+	case retryable(resp.StatusCode):
+		return nil, fmt.Errorf("%w: status %d", errUnreachable, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("central leaderboard returned status %d", resp.StatusCode)
+	}
+
+	var board models.Leaderboard
+	if err := json.NewDecoder(resp.Body).Decode(&board); err != nil {
+		return nil, fmt.Errorf("failed to decode central leaderboard: %w", err)
+	}
+	m.mu.Lock()
+	m.etags[cacheKey] = resp.Header.Get("ETag")
+	m.mu.Unlock()
+	return &board, nil
+}
+
+// request builds a call to a game's route on the central instance, in tenant's
+// namespace
+func (m *Mirror) request(ctx context.Context, method, tenant, gameID, route string, body io.Reader) (*http.Request, error) {
+	base := m.upstream + "/api/v1"
+	if tenant != "" {
+		base += "/tenants/" + url.PathEscape(tenant)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, base+"/games/"+url.PathEscape(gameID)+"/"+route, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	if m.apiKey != "" {
+		req.Header.Set("X-API-Key", m.apiKey)
+	}
+	return req, nil
+}
+
+// retryable reports whether a status means the central instance may take the request
+// later. Authentication failures are retried too: they mean the edge is misconfigured,
+// not that the submission is bad.
+func retryable(status int) bool {
+	switch status {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	}
+	return status >= 500
+}
+
+// bury moves a refused submission from the outbox to the dead letters
+func (m *Mirror) bury(ctx context.Context, item forward, refusal error) error {
+	letter := deadLetter{forward: item, Error: refusal.Error(), RefusedAt: time.Now()}
+	var refused *refusedError
+	if errors.As(refusal, &refused) {
+		letter.Status = refused.status
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	letters, err := m.deadLetters(ctx)
+	if err != nil {
+		return err
+	}
+	letters = append(letters, letter)
+	if dropped := len(letters) - maxDeadLetters; dropped > 0 {
+		m.logger.Warn("edge dead letters full, dropping the oldest", "dropped", dropped)
+		letters = letters[dropped:]
+	}
+	// The dead letter is written first, so a failure part way leaves the submission in
+	// both rather than in neither
+	if err := m.saveDeadLetters(ctx, letters); err != nil {
+		return err
+	}
+	outbox, err := m.outbox(ctx)
+	if err != nil {
+		return err
+	}
+	return m.saveOutbox(ctx, slices.DeleteFunc(outbox, func(queued forward) bool {
+		return queued.ScoreID == item.ScoreID
+	}))
+}
+
+// remove takes a sent submission off the outbox
+func (m *Mirror) remove(ctx context.Context, scoreID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	outbox, err := m.outbox(ctx)
+	if err != nil {
+		return err
+	}
+	kept := slices.DeleteFunc(outbox, func(item forward) bool {
+		return item.ScoreID == scoreID
+	})
+	return m.saveOutbox(ctx, kept)
+}
+
+// outbox returns the submissions waiting to be forwarded, oldest first
+func (m *Mirror) outbox(ctx context.Context) ([]forward, error) {
+	data, err := m.db.Get(ctx, outboxKey)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edge outbox: %w", err)
+	}
+	var outbox []forward
+	if err := json.Unmarshal([]byte(data), &outbox); err != nil {
+		return nil, fmt.Errorf("failed to decode edge outbox: %w", err)
+	}
+	return outbox, nil
+}
+
+func (m *Mirror) saveOutbox(ctx context.Context, outbox []forward) error {
+	data, err := json.Marshal(outbox)
+	if err != nil {
+		return fmt.Errorf("failed to encode edge outbox: %w", err)
+	}
+	if err := m.db.Set(ctx, outboxKey, string(data)); err != nil {
+		return fmt.Errorf("failed to save edge outbox: %w", err)
+	}
+	return nil
+}
+
+// deadLetters returns the submissions the central instance refused, oldest first
+func (m *Mirror) deadLetters(ctx context.Context) ([]deadLetter, error) {
+	data, err := m.db.Get(ctx, deadLettersKey)
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read edge dead letters: %w", err)
+	}
+	var letters []deadLetter
+	if err := json.Unmarshal([]byte(data), &letters); err != nil {
+		return nil, fmt.Errorf("failed to decode edge dead letters: %w", err)
+	}
+	return letters, nil
+}
+
+func (m *Mirror) saveDeadLetters(ctx context.Context, letters []deadLetter) error {
+	data, err := json.Marshal(letters)
+	if err != nil {
+		return fmt.Errorf("failed to encode edge dead letters: %w", err)
+	}
+	if err := m.db.Set(ctx, deadLettersKey, string(data)); err != nil {
+		return fmt.Errorf("failed to save edge dead letters: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package edge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+// central is a stand-in for the central instance, answering with status until told
+// otherwise
+type central struct {
+	mu       sync.Mutex
+	status   int
+	received []map[string]interface{}
+	paths    []string
+	board    models.Leaderboard
+}
+
+func (c *central) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paths = append(c.paths, r.Method+" "+r.URL.Path+" "+r.Header.Get("X-API-Key"))
+	if c.status != http.StatusOK {
+		w.WriteHeader(c.status)
+		return
+	}
+	if r.Method == http.MethodGet {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		json.NewEncoder(w).Encode(c.board)
+		return
+	}
+	var body map[string]interface{}
+	json.NewDecoder(r.Body).Decode(&body)
+	c.received = append(c.received, body)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (c *central) setStatus(status int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.status = status
+}
+
+func TestMirror(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("forwards submissions once the central instance is back", func(t *testing.T) {
+		upstream := &central{status: http.StatusServiceUnavailable}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+		db := database.NewFake()
+		mirror := NewMirror(db, server.URL, "edge-key")
+		service := leaderboard.NewService(db, leaderboard.WithScoreListener(mirror))
+
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		service.SubmitScore(ctx, "pacman", "BBB", 2000)
+		if _, err := mirror.Flush(ctx); err == nil {
+			t.Fatal("Expected Flush to fail while the central instance is down")
+		}
+
+		// A restarted edge picks up where the last one stopped
+		mirror = NewMirror(db, server.URL, "edge-key")
+		if pending, _ := mirror.Pending(ctx); pending != 2 {
+			t.Fatalf("Expected 2 submissions waiting, got %d", pending)
+		}
+
+		upstream.setStatus(http.StatusOK)
+		sent, err := mirror.Flush(ctx)
+		if err != nil || sent != 2 {
+			t.Fatalf("Expected 2 submissions sent, got %d (%v)", sent, err)
+		}
+		if pending, _ := mirror.Pending(ctx); pending != 0 {
+			t.Errorf("Expected an empty outbox, got %d", pending)
+		}
+		if len(upstream.received) != 2 || upstream.received[0]["initials"] != "AAA" || upstream.received[1]["score"] != float64(2000) {
+			t.Errorf("Expected AAA then BBB's scores in order, got %+v", upstream.received)
+		}
+		if last := upstream.paths[len(upstream.paths)-1]; last != "POST /api/v1/games/pacman/scores edge-key" {
+			t.Errorf("Expected the submission posted with the edge's key, got %q", last)
+		}
+	})
+
+	t.Run("keeps submissions the central instance refuses for replay", func(t *testing.T) {
+		upstream := &central{status: http.StatusUnprocessableEntity}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+		db := database.NewFake()
+		mirror := NewMirror(db, server.URL, "")
+		service := leaderboard.NewService(db, leaderboard.WithScoreListener(mirror))
+
+		service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 1000, PIN: "4821"})
+		service.SubmitScore(ctx, "pacman", "BBB", 2000)
+		if sent, err := mirror.Flush(ctx); err != nil || sent != 0 {
+			t.Errorf("Expected nothing sent and no error, got %d (%v)", sent, err)
+		}
+		if pending, _ := mirror.Pending(ctx); pending != 0 {
+			t.Errorf("Expected the refused submissions off the outbox, got %d waiting", pending)
+		}
+		letters, err := mirror.DeadLetters(ctx)
+		if err != nil || letters.Total != 2 {
+			t.Fatalf("Expected 2 dead letters, got %+v (%v)", letters, err)
+		}
+		if letter := letters.DeadLetters[0]; letter.Initials != "AAA" || letter.Status != http.StatusUnprocessableEntity || letter.PlayedAt.IsZero() {
+			t.Errorf("Expected AAA's score kept with the central answer, got %+v", letter)
+		}
+
+		if _, err := mirror.Replay(ctx, "unknown"); !errors.Is(err, ErrDeadLetterNotFound) {
+			t.Errorf("Expected ErrDeadLetterNotFound, got %v", err)
+		}
+		upstream.setStatus(http.StatusOK)
+		if replayed, err := mirror.Replay(ctx, letters.DeadLetters[0].ScoreID); err != nil || replayed != 1 {
+			t.Fatalf("Expected 1 replayed, got %d (%v)", replayed, err)
+		}
+		if sent, err := mirror.Flush(ctx); err != nil || sent != 1 {
+			t.Fatalf("Expected the replayed submission sent, got %d (%v)", sent, err)
+		}
+		if len(upstream.received) != 1 || upstream.received[0]["initials"] != "AAA" || upstream.received[0]["pin"] != "4821" {
+			t.Errorf("Expected AAA's score sent again with its PIN, got %+v", upstream.received)
+		}
+		if letters, _ := mirror.DeadLetters(ctx); letters.Total != 1 || letters.DeadLetters[0].Initials != "BBB" {
+			t.Errorf("Expected only BBB left, got %+v", letters)
+		}
+
+		if replayed, _ := mirror.Replay(ctx, ""); replayed != 1 {
+			t.Errorf("Expected the rest replayed, got %d", replayed)
+		}
+		if sent, _ := mirror.Flush(ctx); sent != 1 {
+			t.Errorf("Expected BBB sent, got %d", sent)
+		}
+	})
+
+	t.Run("forwards when and where the score was played", func(t *testing.T) {
+		upstream := &central{status: http.StatusOK}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+		db := database.NewFake()
+		mirror := NewMirror(db, server.URL, "")
+		service := leaderboard.NewService(db, leaderboard.WithScoreListener(mirror))
+
+		cabinet := apikeys.WithPrincipal(ctx, &apikeys.Principal{KeyID: "device:cab-1", Device: "cab-1", GameIDs: []string{models.AllGames}})
+		signature := &models.ScoreSignature{Timestamp: 1752692645, Nonce: "abcdefghijklmnop", Signature: "feed"}
+		result, err := service.Submit(cabinet, "pacman", models.Submission{Initials: "AAA", Score: 1000, Signature: signature})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if _, err := mirror.Flush(ctx); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+
+		body := upstream.received[0]
+		if body["device_id"] != "cab-1" {
+			t.Errorf("Expected the cabinet's device forwarded, got %v", body["device_id"])
+		}
+		if playedAt, _ := time.Parse(time.RFC3339Nano, body["played_at"].(string)); !playedAt.Equal(result.Entry.Timestamp) {
+			t.Errorf("Expected played_at %v, got %v", result.Entry.Timestamp, body["played_at"])
+		}
+		if body["sequence"] != float64(result.Entry.Sequence) {
+			t.Errorf("Expected the local sequence %d forwarded, got %v", result.Entry.Sequence, body["sequence"])
+		}
+		if sent, _ := body["signature"].(map[string]interface{}); sent["nonce"] != "abcdefghijklmnop" {
+			t.Errorf("Expected the signature forwarded, got %v", body["signature"])
+		}
+		if _, ok := body["pin"]; ok {
+			t.Errorf("Expected no PIN sent for a submission without one, got %v", body["pin"])
+		}
+	})
+
+	t.Run("sends happy hour scores as played", func(t *testing.T) {
+		entry := models.ScoreEntry{Score: 2500, RawScore: 1250, HappyHour: "friday", DisplayScore: "25.00"}
+		if got := playedScore(entry); got != "12.50" {
+			t.Errorf("Expected 12.50, got %q", got)
+		}
+	})
+
+	t.Run("merges the central board without losing local scores", func(t *testing.T) {
+		upstream := &central{status: http.StatusOK}
+		upstream.board = models.Leaderboard{GameID: "pacman", Entries: []models.ScoreEntry{
+			{Initials: "AAA", Score: 500, Timestamp: time.Now()},
+			{Initials: "CCC", Score: 9000, Timestamp: time.Now()},
+		}}
+		server := httptest.NewServer(upstream)
+		defer server.Close()
+		db := database.NewFake()
+		mirror := NewMirror(db, server.URL, "", WithGames("galaga"))
+		service := leaderboard.NewService(db)
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+
+		if err := mirror.Pull(ctx, service); err != nil {
+			t.Fatalf("Pull failed: %v", err)
+		}
+		board, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 2 || board.Entries[0].Initials != "CCC" || board.Entries[1].Score != 1000 {
+			t.Errorf("Expected CCC's 9000 above AAA's local 1000, got %+v", board.Entries)
+		}
+
+		if !slices.Contains(upstream.paths, "GET /api/v1/games/galaga/leaderboard ") {
+			t.Errorf("Expected the mirrored galaga board fetched before it is played, got %v", upstream.paths)
+		}
+	})
+}
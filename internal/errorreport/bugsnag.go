@@ -0,0 +1,69 @@
+package errorreport
+
+import (
+	"context"
+	"time"
+
+	"rawboard/internal/logging"
+
+	"github.com/bugsnag/bugsnag-go/v2"
+	"github.com/gin-gonic/gin"
+)
+
+// bugsnagReporter reports through the Bugsnag SDK's package-level notifier
+type bugsnagReporter struct{}
+
+// newBugsnag configures the Bugsnag SDK and returns a reporter using it
+func newBugsnag(cfg Config) *bugsnagReporter {
+	bugsnag.Configure(bugsnag.Configuration{
+		APIKey:          cfg.BugsnagAPIKey,
+		ReleaseStage:    cfg.Environment,
+		AppVersion:      cfg.Release,
+		Hostname:        "rawboard",
+		ProjectPackages: []string{"main", "rawboard/**"},
+		ParamsFilters:   []string{"password", "secret", "authorization", "cookie", "token", "api-key", "signature"},
+	})
+	return &bugsnagReporter{}
+}
+
+func (r *bugsnagReporter) Provider() string {
+	return ProviderBugsnag
+}
+
+func (r *bugsnagReporter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := bugsnag.AttachRequestData(c.Request.Context(), c.Request)
+		c.Request = c.Request.WithContext(ctx)
+		metadata := requestMetaData(c)
+
+		defer bugsnag.AutoNotify(ctx, metadata, bugsnag.HandledState{
+			SeverityReason:   bugsnag.SeverityReasonUnhandledMiddlewareError,
+			OriginalSeverity: bugsnag.SeverityError,
+			Unhandled:        true,
+			Framework:        "Gin",
+		})
+		c.Next()
+
+		if c.Writer.Status() >= 500 {
+			for _, err := range c.Errors {
+				bugsnag.Notify(err.Err, ctx, metadata)
+			}
+		}
+	}
+}
+
+func (r *bugsnagReporter) Report(ctx context.Context, err error) {
+	bugsnag.Notify(err, ctx)
+}
+
+// Flush does nothing: Bugsnag sends each report as soon as it is made
+func (r *bugsnagReporter) Flush(timeout time.Duration) {}
+
+// requestMetaData returns the request's log fields as a Bugsnag metadata tab
+func requestMetaData(c *gin.Context) bugsnag.MetaData {
+	tab := map[string]interface{}{}
+	for key, value := range requestTags(c, logging.RequestID(c.Request.Context())) {
+		tab[key] = value
+	}
+	return bugsnag.MetaData{"rawboard": tab}
+}
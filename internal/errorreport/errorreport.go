@@ -0,0 +1,90 @@
+// Package errorreport sends panics and unexpected errors to an error tracking service
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Supported providers
+const (
+	ProviderBugsnag = "bugsnag"
+	ProviderSentry  = "sentry"
+)
+
+// Reporter sends errors to an error tracking service, tagged with the release and the
+// request they happened in
+type Reporter interface {
+	// Middleware reports panics in later handlers, and errors attached to 5xx responses,
+	// then re-panics so gin.Recovery still answers 500. Use it after gin.Recovery.
+	Middleware() gin.HandlerFunc
+	// Report sends an unexpected error, with the request context carried by ctx if any
+	Report(ctx context.Context, err error)
+	// Flush waits up to timeout for queued reports to be sent
+	Flush(timeout time.Duration)
+	// Provider names the service reports go to
+	Provider() string
+}
+
+// Config selects and configures a provider
+type Config struct {
+	Provider      string // ProviderBugsnag or ProviderSentry
+	BugsnagAPIKey string
+	SentryDSN     string
+	Environment   string
+	Release       string
+}
+
+// New creates the reporter for cfg.Provider
+func New(cfg Config) (Reporter, error) {
+	switch cfg.Provider {
+	case ProviderBugsnag:
+		return newBugsnag(cfg), nil
+	case ProviderSentry:
+		return newSentry(cfg, nil)
+	default:
+		return nil, fmt.Errorf("unknown error reporting provider %q", cfg.Provider)
+	}
+}
+
+// requestTags returns the request fields rawboard's logs carry, for correlating a report
+// with them
+func requestTags(c *gin.Context, requestID string) map[string]string {
+	tags := map[string]string{"route": c.FullPath()}
+	if tags["route"] == "" {
+		tags["route"] = "unmatched"
+	}
+	if requestID != "" {
+		tags["request_id"] = requestID
+	}
+	if gameID := c.Param("gameId"); gameID != "" {
+		tags["game_id"] = gameID
+	}
+	return tags
+}
+
+// sensitiveParam reports whether a query parameter may hold a credential
+func sensitiveParam(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "token") || strings.Contains(name, "key") ||
+		strings.Contains(name, "secret") || strings.Contains(name, "signature")
+}
+
+// scrubQuery redacts credential-like values from a raw query string
+func scrubQuery(rawQuery string) string {
+	values, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return ""
+	}
+	for name := range values {
+		if sensitiveParam(name) {
+			values[name] = []string{"[Filtered]"}
+		}
+	}
+	return values.Encode()
+}
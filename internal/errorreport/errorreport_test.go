@@ -0,0 +1,105 @@
+package errorreport
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"rawboard/internal/middleware"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+func TestSentryReporter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// newRouter returns a router reporting to a mock Sentry transport
+	newRouter := func(t *testing.T) (*gin.Engine, *sentry.MockTransport) {
+		transport := &sentry.MockTransport{}
+		reporter, err := newSentry(Config{SentryDSN: "https://key@sentry.example.com/1", Environment: "test", Release: "2.3.4"}, transport)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		router := gin.New()
+		router.Use(gin.Recovery(), middleware.RequestID(), reporter.Middleware())
+		router.GET("/games/:gameId/boom", func(c *gin.Context) {
+			panic("kaboom")
+		})
+		router.GET("/games/:gameId/fail", func(c *gin.Context) {
+			c.Error(errors.New("database unavailable"))
+			c.Status(http.StatusInternalServerError)
+		})
+		router.GET("/games/:gameId/invalid", func(c *gin.Context) {
+			c.Error(errors.New("bad input"))
+			c.Status(http.StatusBadRequest)
+		})
+		return router, transport
+	}
+
+	t.Run("reports panics with the release and request context, then re-panics", func(t *testing.T) {
+		router, transport := newRouter(t)
+
+		req := httptest.NewRequest("GET", "/games/pacman/boom?token=s3cret&limit=5", nil)
+		req.Header.Set("X-Request-ID", "req-42")
+		req.Header.Set("X-API-Key", "rbk_secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("Expected gin.Recovery to answer 500, got %d", w.Code)
+		}
+		events := transport.Events()
+		if len(events) != 1 {
+			t.Fatalf("Expected one event, got %d", len(events))
+		}
+		event := events[0]
+		if event.Release != "2.3.4" || event.Environment != "test" {
+			t.Errorf("Expected release and environment tags, got %q and %q", event.Release, event.Environment)
+		}
+		if event.Tags["request_id"] != "req-42" || event.Tags["route"] != "/games/:gameId/boom" || event.Tags["game_id"] != "pacman" {
+			t.Errorf("Missing request tags: %v", event.Tags)
+		}
+		if event.Request == nil {
+			t.Fatal("Expected the request to be attached")
+		}
+		if _, ok := event.Request.Headers["X-Api-Key"]; ok {
+			t.Error("Expected the API key header to be scrubbed")
+		}
+		query, _ := url.ParseQuery(event.Request.QueryString)
+		if query.Get("token") != "[Filtered]" || query.Get("limit") != "5" {
+			t.Errorf("Expected only the token to be filtered, got %q", event.Request.QueryString)
+		}
+	})
+
+	t.Run("reports errors on server errors only", func(t *testing.T) {
+		router, transport := newRouter(t)
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/games/pacman/invalid", nil))
+		if len(transport.Events()) != 0 {
+			t.Errorf("Expected client errors to go unreported, got %d events", len(transport.Events()))
+		}
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/games/pacman/fail", nil))
+		events := transport.Events()
+		if len(events) != 1 || len(events[0].Exception) == 0 || events[0].Exception[len(events[0].Exception)-1].Value != "database unavailable" {
+			t.Errorf("Expected the server error to be reported, got %+v", events)
+		}
+	})
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{Provider: "rollbar"}); err == nil {
+		t.Error("Expected an unknown provider to be rejected")
+	}
+	if _, err := New(Config{Provider: ProviderSentry, SentryDSN: "not a dsn"}); err == nil {
+		t.Error("Expected an invalid DSN to be rejected")
+	}
+	reporter, err := New(Config{Provider: ProviderSentry, SentryDSN: "https://key@sentry.example.com/1"})
+	if err != nil || reporter.Provider() != ProviderSentry {
+		t.Errorf("Expected a Sentry reporter, got %v (%v)", reporter, err)
+	}
+}
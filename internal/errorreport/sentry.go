@@ -0,0 +1,82 @@
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"rawboard/internal/logging"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+// sentryReporter reports to Sentry through its own client, leaving the SDK's global
+// hub untouched
+type sentryReporter struct {
+	hub *sentry.Hub
+}
+
+// newSentry creates a Sentry reporter, sending through transport when it is non-nil
+func newSentry(cfg Config, transport sentry.Transport) (*sentryReporter, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:              cfg.SentryDSN,
+		Environment:      cfg.Environment,
+		Release:          cfg.Release,
+		AttachStacktrace: true,
+		Transport:        transport,
+		BeforeSend: func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+			if event.Request != nil {
+				event.Request.QueryString = scrubQuery(event.Request.QueryString)
+			}
+			return event
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid Sentry configuration: %w", err)
+	}
+	return &sentryReporter{hub: sentry.NewHub(client, sentry.NewScope())}, nil
+}
+
+func (r *sentryReporter) Provider() string {
+	return ProviderSentry
+}
+
+func (r *sentryReporter) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hub := r.hub.Clone()
+		hub.Scope().SetRequest(c.Request)
+		hub.Scope().SetTags(requestTags(c, logging.RequestID(c.Request.Context())))
+		ctx := sentry.SetHubOnContext(c.Request.Context(), hub)
+		c.Request = c.Request.WithContext(ctx)
+
+		defer func() {
+			if err := recover(); err != nil {
+				if err != http.ErrAbortHandler { // Deliberate aborts aren't bugs
+					hub.RecoverWithContext(ctx, err)
+				}
+				panic(err)
+			}
+		}()
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			for _, err := range c.Errors {
+				hub.CaptureException(err.Err)
+			}
+		}
+	}
+}
+
+func (r *sentryReporter) Report(ctx context.Context, err error) {
+	hub := sentry.GetHubFromContext(ctx)
+	if hub == nil {
+		hub = r.hub
+	}
+	hub.CaptureException(err)
+}
+
+func (r *sentryReporter) Flush(timeout time.Duration) {
+	r.hub.Flush(timeout)
+}
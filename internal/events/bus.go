@@ -0,0 +1,287 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+)
+
+// Channel is the Valkey pub/sub channel events travel between replicas on, as binary
+// protobuf Event messages
+const Channel = "rawboard:events"
+
+// Defaults for bus options
+const (
+	DefaultQueueSize  = 256
+	DefaultBufferSize = 64
+)
+
+// publishTimeout bounds publishing one event to pub/sub
+const publishTimeout = 5 * time.Second
+
+// Bus errors
+var (
+	ErrUnknownType    = fmt.Errorf("event types must be some of %s", strings.Join(Types, ", "))
+	ErrSlowSubscriber = errors.New("subscriber fell behind, events dropped")
+	ErrClosed         = errors.New("event bus closed")
+)
+
+// Bus publishes domain events and delivers them to in-process subscribers, such as gRPC
+// streams. It is a leaderboard score and reset listener; both only queue, and a worker
+// publishes to pub/sub so submissions never wait on it. On databases with pub/sub, every
+// replica's subscribers receive every replica's events once Run is watching the channel;
+// elsewhere events are delivered in-process.
+type Bus struct {
+	pubsub     database.PubSub // Nil when the database has none
+	logger     *slog.Logger
+	queueSize  int
+	bufferSize int
+
+	queue         chan *rawboardv1.Event
+	mu            sync.Mutex
+	subscriptions map[*Subscription]struct{}
+	stopped       chan struct{}
+	done          chan struct{}
+}
+
+// Subscription receives one game's events of the types it asked for
+type Subscription struct {
+	tenant string
+	gameID string
+	types  []string // Empty for every type
+	events chan *rawboardv1.Event
+	err    error // Why the events channel was closed
+}
+
+// Option configures optional Bus behavior
+type Option func(*Bus)
+
+// WithLogger sets the logger used to report dropped events and failed publishes
+func WithLogger(logger *slog.Logger) Option {
+	return func(b *Bus) {
+		b.logger = logger
+	}
+}
+
+// WithQueueSize sets how many events may wait to be published
+func WithQueueSize(size int) Option {
+	return func(b *Bus) {
+		b.queueSize = size
+	}
+}
+
+// WithBufferSize sets how many events may queue for one subscriber before it's dropped
+func WithBufferSize(size int) Option {
+	return func(b *Bus) {
+		b.bufferSize = size
+	}
+}
+
+// NewBus creates a bus publishing through db's pub/sub, if it has one, and starts its
+// worker; call Close to stop it
+func NewBus(db database.DB, opts ...Option) *Bus {
+	b := &Bus{
+		logger:        slog.Default(),
+		queueSize:     DefaultQueueSize,
+		bufferSize:    DefaultBufferSize,
+		subscriptions: make(map[*Subscription]struct{}),
+		stopped:       make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	if pubsub, ok := db.(database.PubSub); ok {
+		b.pubsub = pubsub
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.queue = make(chan *rawboardv1.Event, b.queueSize)
+
+	go b.run()
+	return b
+}
+
+// ScoreSubmitted queues the events a counted submission raised, dropping them if the
+// queue is full
+func (b *Bus) ScoreSubmitted(event models.ScoreEvent) {
+	for _, raised := range FromScore(event) {
+		b.enqueue(raised)
+	}
+}
+
+// BoardReset queues a board.reset event, dropping it if the queue is full
+func (b *Bus) BoardReset(event models.ResetEvent) {
+	b.enqueue(FromReset(event))
+}
+
+// enqueue queues an event unless the bus is closed or the queue is full
+func (b *Bus) enqueue(event *rawboardv1.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-b.stopped:
+		return
+	default:
+	}
+
+	select {
+	case b.queue <- event:
+	default:
+		b.logger.Warn("event queue full, dropping event", "type", event.GetType(), "game_id", event.GetGameId())
+	}
+}
+
+// run publishes queued events until the queue is closed
+func (b *Bus) run() {
+	defer close(b.done)
+	for event := range b.queue {
+		if b.pubsub == nil {
+			b.deliver(event)
+			continue
+		}
+
+		data, err := proto.Marshal(event)
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			err = b.pubsub.Publish(ctx, Channel, string(data))
+			cancel()
+		}
+		if err != nil {
+			b.logger.Warn("failed to publish event", "type", event.GetType(), "game_id", event.GetGameId(), "error", err)
+		}
+	}
+}
+
+// Run delivers events published by every replica to this replica's subscribers until
+// ctx is cancelled. It returns nil at once for databases without pub/sub, whose events
+// are delivered as they're queued.
+func (b *Bus) Run(ctx context.Context) error {
+	if b.pubsub == nil {
+		return nil
+	}
+
+	messages, err := b.pubsub.Subscribe(ctx, Channel)
+	if err != nil {
+		return err
+	}
+	for message := range messages {
+		event := &rawboardv1.Event{}
+		if err := proto.Unmarshal([]byte(message), event); err != nil {
+			b.logger.Warn("ignoring malformed event", "error", err)
+			continue
+		}
+		b.deliver(event)
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.New("event subscription closed")
+}
+
+// Subscribe starts delivering a game's events of the given types, or of every type when
+// none are given. Call Unsubscribe when done.
+func (b *Bus) Subscribe(tenant, gameID string, types ...string) (*Subscription, error) {
+	for _, eventType := range types {
+		if !slices.Contains(Types, eventType) {
+			return nil, fmt.Errorf("%w, got %q", ErrUnknownType, eventType)
+		}
+	}
+
+	sub := &Subscription{tenant: tenant, gameID: gameID, types: types, events: make(chan *rawboardv1.Event, b.bufferSize)}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	select {
+	case <-b.stopped:
+		sub.err = ErrClosed
+		close(sub.events)
+	default:
+		b.subscriptions[sub] = struct{}{}
+	}
+	return sub, nil
+}
+
+// Unsubscribe stops a subscription's deliveries and closes its events channel
+func (b *Bus) Unsubscribe(sub *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.drop(sub, nil)
+}
+
+// Events returns the channel the subscription's events arrive on. It is closed when the
+// subscription ends; Err then says why.
+func (s *Subscription) Events() <-chan *rawboardv1.Event {
+	return s.events
+}
+
+// Err returns why the events channel was closed: ErrSlowSubscriber, ErrClosed, or nil
+// after Unsubscribe. Only call it once the channel is closed.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// deliver sends an event to its game's subscribers, dropping those too far behind to
+// take it
+func (b *Bus) deliver(event *rawboardv1.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscriptions {
+		if sub.tenant != event.GetTenant() || sub.gameID != event.GetGameId() {
+			continue
+		}
+		if len(sub.types) > 0 && !slices.Contains(sub.types, event.GetType()) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			b.logger.Warn("event subscriber too slow, disconnecting", "game_id", sub.gameID)
+			b.drop(sub, ErrSlowSubscriber)
+		}
+	}
+}
+
+// drop ends a subscription with err. The caller holds mu.
+func (b *Bus) drop(sub *Subscription, err error) {
+	if _, ok := b.subscriptions[sub]; !ok {
+		return
+	}
+	delete(b.subscriptions, sub)
+	sub.err = err
+	close(sub.events)
+}
+
+// Close stops accepting events, waits for queued ones to be published until ctx
+// expires, then ends every subscription
+func (b *Bus) Close(ctx context.Context) {
+	b.mu.Lock()
+	select {
+	case <-b.stopped:
+	default:
+		close(b.stopped)
+		close(b.queue)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		b.logger.Warn("events did not finish publishing in time", "abandoned", len(b.queue))
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscriptions {
+		b.drop(sub, ErrClosed)
+	}
+}
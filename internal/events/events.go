@@ -0,0 +1,103 @@
+// Package events turns leaderboard changes into the versioned protobuf domain events of
+// proto/rawboard/v1/events.proto, and carries them between replicas over Valkey pub/sub
+package events
+
+import (
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+)
+
+// SchemaVersion is the events schema version stamped on every event. It only changes
+// for a change older consumers can't ignore; added fields and payloads keep it.
+const SchemaVersion = 1
+
+// Domain event types
+const (
+	TypeScoreSubmitted      = "score.submitted"      // A counted submission
+	TypeHighScoreNew        = "highscore.new"        // A player beat their own high score
+	TypeBoardReset          = "board.reset"          // A game's live board was emptied
+	TypeAchievementUnlocked = "achievement.unlocked" // A submission unlocked achievements
+)
+
+// Types lists every domain event type
+var Types = []string{TypeScoreSubmitted, TypeHighScoreNew, TypeBoardReset, TypeAchievementUnlocked}
+
+// newEvent returns an event envelope of the given type, stamped now
+func newEvent(eventType, gameID, tenant string) *rawboardv1.Event {
+	return &rawboardv1.Event{
+		Id:            uuid.New().String(),
+		Type:          eventType,
+		SchemaVersion: SchemaVersion,
+		GameId:        gameID,
+		Tenant:        tenant,
+		OccurredAt:    timestamppb.Now(),
+	}
+}
+
+// FromScore returns the events a counted submission raised: always score.submitted, then
+// highscore.new and achievement.unlocked when it beat the player's best or unlocked any
+func FromScore(event models.ScoreEvent) []*rawboardv1.Event {
+	submitted := newEvent(TypeScoreSubmitted, event.GameID, event.Tenant)
+	submitted.Payload = &rawboardv1.Event_ScoreSubmitted{ScoreSubmitted: &rawboardv1.ScoreSubmitted{
+		Entry: ScoreEntry(event.Entry),
+	}}
+	raised := []*rawboardv1.Event{submitted}
+
+	if event.NewHighScore {
+		highScore := newEvent(TypeHighScoreNew, event.GameID, event.Tenant)
+		highScore.Payload = &rawboardv1.Event_HighScoreNew{HighScoreNew: HighScoreNew(event.Entry, event.PreviousHighScore)}
+		raised = append(raised, highScore)
+	}
+	if len(event.Achievements) > 0 {
+		unlocked := newEvent(TypeAchievementUnlocked, event.GameID, event.Tenant)
+		unlocked.Payload = &rawboardv1.Event_AchievementUnlocked{AchievementUnlocked: AchievementUnlocked(event.Entry, event.Achievements)}
+		raised = append(raised, unlocked)
+	}
+	return raised
+}
+
+// FromReset returns the board.reset event for a live board reset
+func FromReset(event models.ResetEvent) *rawboardv1.Event {
+	reset := newEvent(TypeBoardReset, event.GameID, event.Tenant)
+	reset.Payload = &rawboardv1.Event_BoardReset{BoardReset: &rawboardv1.BoardReset{
+		Reason:   event.Reason,
+		SeasonId: event.SeasonID,
+	}}
+	return reset
+}
+
+// HighScoreNew converts a new high score and the one it beat, if any, to their protobuf form
+func HighScoreNew(entry models.ScoreEntry, previous *models.ScoreEntry) *rawboardv1.HighScoreNew {
+	highScore := &rawboardv1.HighScoreNew{Entry: ScoreEntry(entry)}
+	if previous != nil {
+		highScore.PreviousHighScore = ScoreEntry(*previous)
+	}
+	return highScore
+}
+
+// AchievementUnlocked converts a submission's unlocked achievements to their protobuf form
+func AchievementUnlocked(entry models.ScoreEntry, achievements []models.Achievement) *rawboardv1.AchievementUnlocked {
+	unlocked := &rawboardv1.AchievementUnlocked{Entry: ScoreEntry(entry)}
+	for _, achievement := range achievements {
+		unlocked.Achievements = append(unlocked.Achievements, &rawboardv1.Achievement{
+			Id:          achievement.ID,
+			Name:        achievement.Name,
+			Description: achievement.Description,
+			Icon:        achievement.Icon,
+			UnlockedAt:  timestamppb.New(achievement.UnlockedAt),
+		})
+	}
+	return unlocked
+}
+
+// ScoreEntry converts a model score to its protobuf form
+func ScoreEntry(entry models.ScoreEntry) *rawboardv1.ScoreEntry {
+	return &rawboardv1.ScoreEntry{
+		Initials:  entry.Initials,
+		Score:     entry.Score,
+		Timestamp: timestamppb.New(entry.Timestamp),
+	}
+}
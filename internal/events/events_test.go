@@ -0,0 +1,156 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+)
+
+// nextEvent reads one event, failing the test instead of blocking forever
+func nextEvent(t *testing.T, sub *Subscription) *rawboardv1.Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events():
+		if !ok {
+			t.Fatalf("Subscription ended: %v", sub.Err())
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for an event")
+		return nil
+	}
+}
+
+// runBus watches bus's pub/sub channel until the test ends, returning once subscribed
+func runBus(t *testing.T, bus *Bus, db *database.Fake) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	subscribers := db.Subscribers(Channel)
+	go bus.Run(ctx)
+	for deadline := time.Now().Add(2 * time.Second); db.Subscribers(Channel) == subscribers; {
+		if time.Now().After(deadline) {
+			t.Fatal("Bus never subscribed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestFromScore(t *testing.T) {
+	entry := models.ScoreEntry{Initials: "AAA", Score: 5000, Timestamp: time.Now()}
+	previous := models.ScoreEntry{Initials: "AAA", Score: 3000}
+
+	raised := FromScore(models.ScoreEvent{
+		GameID:            "pacman",
+		Tenant:            "acme",
+		Entry:             entry,
+		PreviousHighScore: &previous,
+		NewHighScore:      true,
+		Achievements:      []models.Achievement{{ID: "score_5k", Name: "Five Grand"}},
+	})
+	if len(raised) != 3 {
+		t.Fatalf("Expected score.submitted, highscore.new and achievement.unlocked, got %v", raised)
+	}
+	for i, want := range []string{TypeScoreSubmitted, TypeHighScoreNew, TypeAchievementUnlocked} {
+		event := raised[i]
+		if event.GetType() != want || event.GetSchemaVersion() != SchemaVersion || event.GetGameId() != "pacman" || event.GetTenant() != "acme" || event.GetId() == "" {
+			t.Errorf("Event %d = %v, want a stamped %s", i, event, want)
+		}
+	}
+	if got := raised[1].GetHighScoreNew().GetPreviousHighScore().GetScore(); got != 3000 {
+		t.Errorf("Expected the beaten 3000, got %d", got)
+	}
+	if got := raised[2].GetAchievementUnlocked().GetAchievements(); len(got) != 1 || got[0].GetId() != "score_5k" {
+		t.Errorf("Expected the unlocked achievement, got %v", got)
+	}
+
+	if raised := FromScore(models.ScoreEvent{GameID: "pacman", Entry: entry}); len(raised) != 1 || raised[0].GetScoreSubmitted().GetEntry().GetScore() != 5000 {
+		t.Errorf("Expected only score.submitted for a score that beat nothing, got %v", raised)
+	}
+}
+
+func TestBus(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+
+	// Two replicas sharing a database, each with its own bus
+	publisher, subscriber := NewBus(db), NewBus(db)
+	defer publisher.Close(ctx)
+	defer subscriber.Close(ctx)
+	runBus(t, subscriber, db)
+
+	service := leaderboard.NewService(db, leaderboard.WithScoreListener(publisher), leaderboard.WithResetListener(publisher))
+
+	scores, err := subscriber.Subscribe("", "pacman", TypeScoreSubmitted, TypeBoardReset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer subscriber.Unsubscribe(scores)
+	everything, _ := subscriber.Subscribe("", "pacman")
+	defer subscriber.Unsubscribe(everything)
+	otherTenant, _ := subscriber.Subscribe("acme", "pacman")
+	defer subscriber.Unsubscribe(otherTenant)
+
+	if err := service.SubmitScore(ctx, "pacman", "AAA", 5000); err != nil {
+		t.Fatal(err)
+	}
+	if event := nextEvent(t, scores); event.GetType() != TypeScoreSubmitted || event.GetScoreSubmitted().GetEntry().GetInitials() != "AAA" {
+		t.Errorf("Expected AAA's score.submitted, got %v", event)
+	}
+	for _, want := range []string{TypeScoreSubmitted, TypeHighScoreNew, TypeAchievementUnlocked} {
+		if event := nextEvent(t, everything); event.GetType() != want {
+			t.Errorf("Expected %s, got %v", want, event)
+		}
+	}
+
+	if _, err := service.StartSeason(ctx, "pacman", "spring", "Spring", nil); err != nil {
+		t.Fatal(err)
+	}
+	event := nextEvent(t, scores)
+	if reset := event.GetBoardReset(); reset == nil || reset.GetReason() != models.ResetSeasonStarted || reset.GetSeasonId() != "spring" {
+		t.Errorf("Expected a board.reset for the spring season, got %v", event)
+	}
+
+	select {
+	case event := <-otherTenant.Events():
+		t.Errorf("Expected another tenant's pacman to hear nothing, got %v", event)
+	default:
+	}
+
+	if _, err := subscriber.Subscribe("", "pacman", "score.deleted"); !errors.Is(err, ErrUnknownType) {
+		t.Errorf("Expected ErrUnknownType, got %v", err)
+	}
+}
+
+func TestBusInProcess(t *testing.T) {
+	ctx := context.Background()
+	// Hide the fake's pub/sub, like a database without it
+	bus := NewBus(struct{ database.DB }{database.NewFake()}, WithBufferSize(1))
+
+	sub, _ := bus.Subscribe("acme", "pacman")
+	slow, _ := bus.Subscribe("acme", "pacman")
+	bus.BoardReset(models.ResetEvent{GameID: "pacman", Tenant: "acme", Reason: models.ResetSeasonEnded})
+	if event := nextEvent(t, sub); event.GetType() != TypeBoardReset {
+		t.Errorf("Expected board.reset, got %v", event)
+	}
+
+	// slow never reads, so the second event overflows its buffer
+	bus.BoardReset(models.ResetEvent{GameID: "pacman", Tenant: "acme", Reason: models.ResetSeasonEnded})
+	nextEvent(t, sub)
+	for range slow.Events() {
+	}
+	if !errors.Is(slow.Err(), ErrSlowSubscriber) {
+		t.Errorf("Expected the slow subscriber to be dropped, got %v", slow.Err())
+	}
+
+	bus.Close(ctx)
+	if _, ok := <-sub.Events(); ok || !errors.Is(sub.Err(), ErrClosed) {
+		t.Errorf("Expected Close to end subscriptions, got %v", sub.Err())
+	}
+}
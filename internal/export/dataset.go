@@ -0,0 +1,147 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+const (
+	// datasetPrefix separates public datasets from restorable exports
+	datasetPrefix = "datasets"
+	// DatasetTimestampPrecision is the granularity of timestamps in public datasets,
+	// coarse enough that a score can't be matched to a single visit to a machine
+	DatasetTimestampPrecision = time.Hour
+)
+
+// ExportAnonymized writes a shareable dataset of every game's score history with
+// player identity hashed or stripped. Only game ID, score and a coarsened timestamp
+// are copied from each score; any other identifying fields are never exported.
+// Hash mode uses a random key that is discarded after the run, so pseudonyms are
+// stable within one dataset but can't be reversed or linked across datasets. Player ID
+// mode uses the tenant's public player IDs instead, which link across datasets and to
+// public history.
+func (e *Exporter) ExportAnonymized(ctx context.Context, mode string) (*models.DatasetManifest, error) {
+	var key []byte
+	switch mode {
+	case models.AnonymizeHash:
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate pseudonym key: %w", err)
+		}
+	case models.AnonymizePlayerID:
+		salt, err := e.service.PlayerIDSalt(ctx)
+		if err != nil {
+			return nil, err
+		}
+		key = salt
+	case models.AnonymizeStrip:
+	default:
+		return nil, fmt.Errorf("unknown anonymization mode %q (expected %s, %s or %s)", mode, models.AnonymizeHash, models.AnonymizePlayerID, models.AnonymizeStrip)
+	}
+
+	gameIDs, err := e.service.ListGames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+
+	now := time.Now().UTC()
+	manifest := &models.DatasetManifest{
+		DatasetID:          now.Format(exportIDFormat),
+		CreatedAt:          now,
+		Mode:               mode,
+		TimestampPrecision: DatasetTimestampPrecision.String(),
+		Games:              make([]models.DatasetGame, 0, len(gameIDs)),
+	}
+
+	for _, gameID := range gameIDs {
+		allScores, err := e.service.GetAllScoresForGame(ctx, gameID)
+		if err != nil || len(allScores.Scores) == 0 {
+			continue // Registered but nothing stored yet
+		}
+
+		records, summary := anonymizeScores(gameID, allScores.Scores, key)
+		data, err := encodeNDJSON(records)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode dataset for game %s: %w", gameID, err)
+		}
+
+		checksum := sha256.Sum256(data)
+		summary.Object = e.datasetGameKey(ctx, manifest.DatasetID, gameID)
+		summary.Bytes = len(data)
+		summary.SHA256 = hex.EncodeToString(checksum[:])
+
+		if err := e.store.Put(ctx, summary.Object, data, "application/gzip"); err != nil {
+			return nil, fmt.Errorf("failed to upload dataset for game %s: %w", gameID, err)
+		}
+		manifest.Games = append(manifest.Games, summary)
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal dataset manifest: %w", err)
+	}
+
+	if err := e.store.Put(ctx, e.datasetManifestKey(ctx, manifest.DatasetID), data, "application/json"); err != nil {
+		return nil, fmt.Errorf("failed to write dataset manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// anonymizeScores converts a game's history into anonymized records and a summary.
+// Players are pseudonymized with key, or dropped when key is nil.
+func anonymizeScores(gameID string, scores []models.ScoreEntry, key []byte) ([]models.AnonymizedScore, models.DatasetGame) {
+	records := make([]models.AnonymizedScore, 0, len(scores))
+	summary := models.DatasetGame{GameID: gameID, Scores: len(scores)}
+	players := make(map[string]struct{})
+
+	for i, entry := range scores {
+		record := models.AnonymizedScore{
+			GameID:    gameID,
+			Score:     entry.Score,
+			Timestamp: entry.Timestamp.UTC().Truncate(DatasetTimestampPrecision),
+		}
+		if key != nil {
+			record.Player = leaderboard.PlayerID(key, gameID, entry.Initials)
+			players[record.Player] = struct{}{}
+		}
+		records = append(records, record)
+
+		if i == 0 || entry.Score < summary.MinScore {
+			summary.MinScore = entry.Score
+		}
+		if i == 0 || entry.Score > summary.MaxScore {
+			summary.MaxScore = entry.Score
+		}
+		if i == 0 || record.Timestamp.Before(summary.FirstScoreAt) {
+			summary.FirstScoreAt = record.Timestamp
+		}
+		if i == 0 || record.Timestamp.After(summary.LastScoreAt) {
+			summary.LastScoreAt = record.Timestamp
+		}
+	}
+
+	summary.Players = len(players)
+	return records, summary
+}
+
+// datasetManifestKey returns the object key for a dataset run's manifest
+// Datasets don't use manifest.json so they're never listed as restorable exports
+func (e *Exporter) datasetManifestKey(ctx context.Context, datasetID string) string {
+	return path.Join(e.root(ctx), datasetPrefix, datasetID, "dataset.json")
+}
+
+// datasetGameKey returns the object key for a game's anonymized scores within a dataset
+func (e *Exporter) datasetGameKey(ctx context.Context, datasetID, gameID string) string {
+	return path.Join(e.root(ctx), datasetPrefix, datasetID, "games", url.PathEscape(gameID)+".ndjson.gz")
+}
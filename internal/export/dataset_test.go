@@ -0,0 +1,73 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestAnonymizeScores(t *testing.T) {
+	base := time.Date(2025, 7, 16, 15, 42, 17, 0, time.UTC)
+	scores := []models.ScoreEntry{
+		{Initials: "AAA", Score: 1000, Timestamp: base},
+		{Initials: "BBB", Score: 3000, Timestamp: base.Add(2 * time.Hour)},
+		{Initials: "AAA", Score: 500, Timestamp: base.Add(-time.Hour)},
+	}
+
+	t.Run("hash mode replaces initials with stable pseudonyms", func(t *testing.T) {
+		records, summary := anonymizeScores("pacman", scores, []byte("test-key"))
+
+		if records[0].Player == "" || records[0].Player != records[2].Player {
+			t.Errorf("Expected the same player to share a pseudonym, got %q and %q", records[0].Player, records[2].Player)
+		}
+		if records[0].Player == records[1].Player {
+			t.Error("Expected different players to get different pseudonyms")
+		}
+		for _, record := range records {
+			if strings.Contains(record.Player, "AAA") || strings.Contains(record.Player, "BBB") {
+				t.Errorf("Pseudonym leaks initials: %q", record.Player)
+			}
+		}
+		if summary.Players != 2 {
+			t.Errorf("Expected 2 distinct players, got %d", summary.Players)
+		}
+	})
+
+	t.Run("pseudonyms differ between keys", func(t *testing.T) {
+		first, _ := anonymizeScores("pacman", scores, []byte("key-one"))
+		second, _ := anonymizeScores("pacman", scores, []byte("key-two"))
+
+		if first[0].Player == second[0].Player {
+			t.Error("Expected pseudonyms not to be linkable across datasets")
+		}
+	})
+
+	t.Run("strip mode drops players entirely", func(t *testing.T) {
+		records, summary := anonymizeScores("pacman", scores, nil)
+
+		for _, record := range records {
+			if record.Player != "" {
+				t.Errorf("Expected no player in strip mode, got %q", record.Player)
+			}
+		}
+		if summary.Players != 0 {
+			t.Errorf("Expected no player count in strip mode, got %d", summary.Players)
+		}
+	})
+
+	t.Run("coarsens timestamps and summarizes the distribution", func(t *testing.T) {
+		records, summary := anonymizeScores("pacman", scores, nil)
+
+		if !records[0].Timestamp.Equal(time.Date(2025, 7, 16, 15, 0, 0, 0, time.UTC)) {
+			t.Errorf("Expected timestamp truncated to the hour, got %v", records[0].Timestamp)
+		}
+		if summary.MinScore != 500 || summary.MaxScore != 3000 || summary.Scores != 3 {
+			t.Errorf("Unexpected summary: %+v", summary)
+		}
+		if !summary.FirstScoreAt.Before(summary.LastScoreAt) {
+			t.Errorf("Expected first score before last score: %+v", summary)
+		}
+	})
+}
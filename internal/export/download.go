@@ -0,0 +1,153 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// Download formats
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat is returned for download formats other than csv and json
+var ErrUnknownFormat = errors.New("format must be csv or json")
+
+// Column headers of CSV downloads
+var (
+	leaderboardColumns = []string{"rank", "initials", "score", "display_score", "timestamp"}
+	historyColumns     = []string{"initials", "score", "display_score", "timestamp", "counted", "flags", "metadata", "device_id"}
+)
+
+// ContentType returns the media type of a download format
+func ContentType(format string) string {
+	if format == FormatCSV {
+		return "text/csv; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// CheckFormat returns the format, defaulting to json, or ErrUnknownFormat
+func CheckFormat(format string) (string, error) {
+	switch format = strings.ToLower(format); format {
+	case "":
+		return FormatJSON, nil
+	case FormatCSV, FormatJSON:
+		return format, nil
+	}
+	return "", fmt.Errorf("%w, got %q", ErrUnknownFormat, format)
+}
+
+// WriteRanking writes every player's high score in leaderboard order in format, one
+// ranked row per player for CSV
+func WriteRanking(w io.Writer, format string, ranking *models.Ranking) error {
+	if format == FormatJSON {
+		return json.NewEncoder(w).Encode(ranking)
+	}
+
+	out := csv.NewWriter(w)
+	out.Write(leaderboardColumns)
+	for i, entry := range ranking.Entries {
+		out.Write([]string{strconv.Itoa(i + 1), entry.Initials, strconv.FormatInt(entry.Score, 10), displayScore(entry), timestamp(entry.Timestamp)})
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteHistory writes every score in a game's history in format, oldest first. Entries
+// are encoded one at a time so the encoded download is never held in memory.
+func WriteHistory(w io.Writer, format string, history *models.AllScoresRecord) error {
+	if format == FormatJSON {
+		return writeHistoryJSON(w, history)
+	}
+
+	out := csv.NewWriter(w)
+	out.Write(historyColumns)
+	for _, entry := range history.Scores {
+		metadata := ""
+		if len(entry.Metadata) > 0 {
+			encoded, err := json.Marshal(entry.Metadata)
+			if err != nil {
+				return fmt.Errorf("failed to encode metadata: %w", err)
+			}
+			metadata = string(encoded)
+		}
+
+		out.Write([]string{
+			entry.Initials,
+			strconv.FormatInt(entry.Score, 10),
+			displayScore(entry),
+			timestamp(entry.Timestamp),
+			strconv.FormatBool(!entry.NonCounting),
+			flagRules(entry.Flags),
+			metadata,
+			entry.DeviceID,
+		})
+		if err := out.Error(); err != nil {
+			return err
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// writeHistoryJSON writes history as an models.AllScoresRecord, one entry at a time
+func writeHistoryJSON(w io.Writer, history *models.AllScoresRecord) error {
+	gameID, err := json.Marshal(history.GameID)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"game_id":%s,"scores":[`, gameID); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	for i, entry := range history.Scores {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	updated, err := json.Marshal(history.Updated)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, `],"updated":%s}`+"\n", updated)
+	return err
+}
+
+// displayScore returns the score as shown to players, with decimals in games that keep them
+func displayScore(entry models.ScoreEntry) string {
+	if entry.DisplayScore != "" {
+		return entry.DisplayScore
+	}
+	return strconv.FormatInt(entry.Score, 10)
+}
+
+// flagRules lists the anti-cheat rules an entry broke, separated by semicolons
+func flagRules(flags []models.ScoreViolation) string {
+	rules := make([]string, len(flags))
+	for i, flag := range flags {
+		rules[i] = flag.Rule
+	}
+	return strings.Join(rules, ";")
+}
+
+// timestamp formats a submission time for CSV
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
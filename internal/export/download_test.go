@@ -0,0 +1,103 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestCheckFormat(t *testing.T) {
+	for input, want := range map[string]string{"": FormatJSON, "json": FormatJSON, "CSV": FormatCSV} {
+		if got, err := CheckFormat(input); err != nil || got != want {
+			t.Errorf("CheckFormat(%q) = %q, %v; want %q", input, got, err, want)
+		}
+	}
+	if _, err := CheckFormat("xml"); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
+
+func TestWriteRanking(t *testing.T) {
+	at := time.Date(2025, 7, 16, 15, 30, 0, 0, time.UTC)
+	ranking := &models.Ranking{GameID: "speedrun", Entries: []models.ScoreEntry{
+		{Initials: "AAA", Score: 1250, DisplayScore: "12.50", Timestamp: at},
+		{Initials: "BBB", Score: 900, DisplayScore: "9.00", Timestamp: at},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteRanking(&buf, FormatCSV, ranking); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"rank", "initials", "score", "display_score", "timestamp"},
+		{"1", "AAA", "1250", "12.50", "2025-07-16T15:30:00Z"},
+		{"2", "BBB", "900", "9.00", "2025-07-16T15:30:00Z"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, rows)
+	}
+	for i := range want {
+		for j := range want[i] {
+			if rows[i][j] != want[i][j] {
+				t.Errorf("Row %d: expected %v, got %v", i, want[i], rows[i])
+				break
+			}
+		}
+	}
+}
+
+func TestWriteHistory(t *testing.T) {
+	at := time.Date(2025, 7, 16, 15, 30, 0, 0, time.UTC)
+	history := &models.AllScoresRecord{GameID: "pacman", Updated: at, Scores: []models.ScoreEntry{
+		{Initials: "AAA", Score: 5000, Timestamp: at, Metadata: models.ScoreMetadata{"level": 7.0}, DeviceID: "cabinet-1"},
+		{Initials: "BBB", Score: 9000, Timestamp: at.Add(time.Minute), NonCounting: true,
+			Flags: []models.ScoreViolation{{Rule: "max_delta"}, {Rule: "min_interval"}}},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteHistory(&buf, FormatCSV, history); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || len(rows[0]) != 8 {
+		t.Fatalf("Expected a header and two rows of 8 columns, got %v", rows)
+	}
+	if got := rows[1]; got[0] != "AAA" || got[2] != "5000" || got[4] != "true" || got[6] != `{"level":7}` || got[7] != "cabinet-1" {
+		t.Errorf("Unexpected first row %v", got)
+	}
+	if got := rows[2]; got[4] != "false" || got[5] != "max_delta;min_interval" || got[6] != "" || got[7] != "" {
+		t.Errorf("Unexpected second row %v", got)
+	}
+
+	buf.Reset()
+	if err := WriteHistory(&buf, FormatJSON, history); err != nil {
+		t.Fatal(err)
+	}
+	var decoded models.AllScoresRecord
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got %v: %s", err, buf.String())
+	}
+	if decoded.GameID != "pacman" || len(decoded.Scores) != 2 || !decoded.Updated.Equal(at) || !decoded.Scores[1].NonCounting {
+		t.Errorf("Expected the history to round-trip, got %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := WriteHistory(&buf, FormatJSON, &models.AllScoresRecord{GameID: "empty"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || len(decoded.Scores) != 0 {
+		t.Errorf("Expected an empty history to encode as valid JSON, got %v: %s", err, buf.String())
+	}
+}
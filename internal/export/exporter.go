@@ -0,0 +1,162 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/objectstore"
+	"rawboard/internal/tenants"
+)
+
+// exportIDFormat names export runs so they sort chronologically
+const exportIDFormat = "20060102T150405Z"
+
+// Exporter writes game history and boards to object storage as gzip-compressed NDJSON
+type Exporter struct {
+	service *leaderboard.Service
+	store   objectstore.Store
+	prefix  string
+}
+
+// NewExporter creates a new exporter writing under prefix in store
+func NewExporter(service *leaderboard.Service, store objectstore.Store, prefix string) *Exporter {
+	return &Exporter{
+		service: service,
+		store:   store,
+		prefix:  strings.Trim(prefix, "/"),
+	}
+}
+
+// ExportAll exports every registered game and writes a manifest for the run
+func (e *Exporter) ExportAll(ctx context.Context) (*models.ExportManifest, error) {
+	gameIDs, err := e.service.ListGames(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list games: %w", err)
+	}
+
+	now := time.Now().UTC()
+	manifest := &models.ExportManifest{
+		ExportID:  now.Format(exportIDFormat),
+		CreatedAt: now,
+		Games:     make([]models.ExportedGame, 0, len(gameIDs)),
+	}
+
+	for _, gameID := range gameIDs {
+		exported, err := e.exportGame(ctx, manifest.ExportID, gameID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export game %s: %w", gameID, err)
+		}
+		if exported != nil {
+			manifest.Games = append(manifest.Games, *exported)
+		}
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err := e.store.Put(ctx, e.manifestKey(ctx, manifest.ExportID), data, "application/json"); err != nil {
+		return nil, fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// exportGame writes a single game's export object, returning nil if the game has no history
+func (e *Exporter) exportGame(ctx context.Context, exportID, gameID string) (*models.ExportedGame, error) {
+	allScores, err := e.service.GetAllScoresForGame(ctx, gameID)
+	if errors.Is(err, leaderboard.ErrNoScoreHistory) {
+		return nil, nil // Registered but nothing stored yet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]models.ExportRecord, 0, len(allScores.Scores)+2)
+	exported := &models.ExportedGame{
+		GameID: gameID,
+		Object: e.gameKey(ctx, exportID, gameID),
+		Scores: len(allScores.Scores),
+	}
+
+	if board, err := e.service.GetLeaderboard(ctx, gameID); err == nil {
+		records = append(records, models.ExportRecord{Type: models.ExportRecordLeaderboard, GameID: gameID, Leaderboard: board})
+		exported.LeaderboardEntries = len(board.Entries)
+	}
+
+	if highScores, err := e.service.GetPlayerHighScores(ctx, gameID); err == nil {
+		records = append(records, models.ExportRecord{Type: models.ExportRecordHighScores, GameID: gameID, HighScores: highScores})
+		exported.Players = len(highScores.HighScores)
+	}
+
+	for i := range allScores.Scores {
+		records = append(records, models.ExportRecord{Type: models.ExportRecordScore, GameID: gameID, Score: &allScores.Scores[i]})
+	}
+
+	data, err := encodeNDJSON(records)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	exported.SHA256 = hex.EncodeToString(checksum[:])
+	exported.Bytes = len(data)
+
+	if err := e.store.Put(ctx, exported.Object, data, "application/gzip"); err != nil {
+		return nil, fmt.Errorf("failed to upload export: %w", err)
+	}
+
+	return exported, nil
+}
+
+// root returns where the tenant ctx acts for keeps its exports; the default namespace
+// uses the prefix itself
+func (e *Exporter) root(ctx context.Context) string {
+	if tenant := tenants.FromContext(ctx); tenant != "" {
+		return path.Join(e.prefix, "tenants", tenant)
+	}
+	return e.prefix
+}
+
+// manifestKey returns the object key for an export run's manifest
+func (e *Exporter) manifestKey(ctx context.Context, exportID string) string {
+	return path.Join(e.root(ctx), exportID, "manifest.json")
+}
+
+// gameKey returns the object key for a game's export within a run
+func (e *Exporter) gameKey(ctx context.Context, exportID, gameID string) string {
+	return path.Join(e.root(ctx), exportID, "games", url.PathEscape(gameID)+".ndjson.gz")
+}
+
+// encodeNDJSON writes records as gzip-compressed newline-delimited JSON
+func encodeNDJSON[T any](records []T) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+
+	encoder := json.NewEncoder(gz)
+	encoder.SetEscapeHTML(false)
+	for i, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, fmt.Errorf("failed to encode record %d: %w", i, err)
+		}
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress export: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
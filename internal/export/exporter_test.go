@@ -0,0 +1,40 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+)
+
+func TestExportAll(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("skips games with no history", func(t *testing.T) {
+		db := database.NewFake()
+		service := leaderboard.NewService(db)
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		db.Del(ctx, "all_scores:pacman")
+
+		manifest, err := NewExporter(service, newMemoryStore(), "exports").ExportAll(ctx)
+		if err != nil {
+			t.Fatalf("ExportAll failed: %v", err)
+		}
+		if len(manifest.Games) != 0 {
+			t.Errorf("Expected no games exported, got %+v", manifest.Games)
+		}
+	})
+
+	t.Run("fails when a game's history can't be read", func(t *testing.T) {
+		db := database.NewFake()
+		service := leaderboard.NewService(db)
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		db.FailKey(database.OpGet, "all_scores:", errors.New("connection reset"))
+
+		if _, err := NewExporter(service, newMemoryStore(), "exports").ExportAll(ctx); err == nil {
+			t.Error("Expected the export to fail rather than leave pacman out")
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// ErrNoScores is returned for an import without any score entries
+var ErrNoScores = errors.New("import contains no scores")
+
+// Columns an imported CSV must have; the others of a history download are optional
+var requiredColumns = []string{"initials", "score", "timestamp"}
+
+// ReadHistory reads the scores of a history or leaderboard download in format, so a
+// download can be imported into a game. The game ID in a JSON download is ignored.
+// CSV columns are matched by header name and unknown columns are skipped.
+func ReadHistory(r io.Reader, format string) ([]models.ScoreEntry, error) {
+	var (
+		scores []models.ScoreEntry
+		err    error
+	)
+	if format == FormatJSON {
+		scores, err = readJSON(r)
+	} else {
+		scores, err = readCSV(r)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(scores) == 0 {
+		return nil, ErrNoScores
+	}
+	return scores, nil
+}
+
+// readJSON reads a models.AllScoresRecord or a models.Ranking
+func readJSON(r io.Reader) ([]models.ScoreEntry, error) {
+	var download struct {
+		Scores  []models.ScoreEntry `json:"scores"`
+		Entries []models.ScoreEntry `json:"entries"`
+	}
+	if err := json.NewDecoder(r).Decode(&download); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+	return append(download.Scores, download.Entries...), nil
+}
+
+// readCSV reads a CSV download, reporting problems by line number
+func readCSV(r io.Reader) ([]models.ScoreEntry, error) {
+	in := csv.NewReader(r)
+	in.FieldsPerRecord = -1
+
+	header, err := in.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, ErrNoScores
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %s column", name)
+		}
+	}
+
+	var scores []models.ScoreEntry
+	for line := 2; ; line++ {
+		row, err := in.Read()
+		if errors.Is(err, io.EOF) {
+			return scores, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		entry, err := readRow(row, columns)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		scores = append(scores, entry)
+	}
+}
+
+// readRow decodes one CSV row into an entry
+func readRow(row []string, columns map[string]int) (models.ScoreEntry, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	entry := models.ScoreEntry{Initials: field("initials")}
+
+	score, err := strconv.ParseInt(field("score"), 10, 64)
+	if err != nil {
+		return entry, fmt.Errorf("score must be a whole number as stored, got %q", field("score"))
+	}
+	entry.Score = score
+
+	if entry.Timestamp, err = time.Parse(time.RFC3339Nano, field("timestamp")); err != nil {
+		return entry, fmt.Errorf("timestamp must be RFC 3339, got %q", field("timestamp"))
+	}
+
+	if counted := field("counted"); counted != "" {
+		value, err := strconv.ParseBool(counted)
+		if err != nil {
+			return entry, fmt.Errorf("counted must be true or false, got %q", counted)
+		}
+		entry.NonCounting = !value
+	}
+
+	if flags := field("flags"); flags != "" {
+		for _, rule := range strings.Split(flags, ";") {
+			entry.Flags = append(entry.Flags, models.ScoreViolation{Rule: rule})
+		}
+	}
+
+	if metadata := field("metadata"); metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &entry.Metadata); err != nil {
+			return entry, fmt.Errorf("metadata must be a JSON object: %w", err)
+		}
+	}
+
+	entry.DeviceID = field("device_id")
+
+	return entry, nil
+}
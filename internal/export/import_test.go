@@ -0,0 +1,75 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestReadHistory(t *testing.T) {
+	at := time.Date(2025, 7, 16, 15, 30, 0, 0, time.UTC)
+	history := &models.AllScoresRecord{GameID: "pacman", Updated: at, Scores: []models.ScoreEntry{
+		{Initials: "AAA", Score: 1000, Timestamp: at, Metadata: models.ScoreMetadata{"level": float64(3)}},
+		{Initials: "BBB", Score: 900, Timestamp: at.Add(time.Minute), NonCounting: true,
+			Flags: []models.ScoreViolation{{Rule: "max_delta"}, {Rule: "min_interval"}}},
+	}}
+
+	for _, format := range []string{FormatCSV, FormatJSON} {
+		var buf bytes.Buffer
+		if err := WriteHistory(&buf, format, history); err != nil {
+			t.Fatal(err)
+		}
+		scores, err := ReadHistory(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: ReadHistory failed: %v", format, err)
+		}
+		if len(scores) != 2 || scores[0].Metadata["level"] != float64(3) || !scores[0].Timestamp.Equal(at) {
+			t.Errorf("%s: expected the first score back, got %+v", format, scores)
+		}
+		if !scores[1].NonCounting || len(scores[1].Flags) != 2 || scores[1].Flags[1].Rule != "min_interval" {
+			t.Errorf("%s: expected the uncounted, flagged score back, got %+v", format, scores[1])
+		}
+	}
+
+	t.Run("reads leaderboard downloads", func(t *testing.T) {
+		var buf bytes.Buffer
+		WriteRanking(&buf, FormatCSV, &models.Ranking{GameID: "pacman", Entries: history.Scores[:1]})
+		scores, err := ReadHistory(&buf, FormatCSV)
+		if err != nil || len(scores) != 1 || scores[0].Initials != "AAA" || scores[0].NonCounting {
+			t.Errorf("Expected one counted score from a ranking CSV, got %+v (%v)", scores, err)
+		}
+
+		scores, err = ReadHistory(strings.NewReader(`{"game_id":"other","entries":[{"initials":"CCC","score":5,"timestamp":"2025-07-16T15:30:00Z"}]}`), FormatJSON)
+		if err != nil || len(scores) != 1 || scores[0].Initials != "CCC" {
+			t.Errorf("Expected one score from a ranking JSON, got %+v (%v)", scores, err)
+		}
+	})
+
+	t.Run("rejects malformed input", func(t *testing.T) {
+		for name, input := range map[string]string{
+			"missing column": "initials,score\nAAA,100\n",
+			"bad score":      "initials,score,timestamp\nAAA,12.5,2025-07-16T15:30:00Z\n",
+			"bad timestamp":  "initials,score,timestamp\nAAA,100,yesterday\n",
+			"bad counted":    "initials,score,timestamp,counted\nAAA,100,2025-07-16T15:30:00Z,maybe\n",
+			"bad metadata":   "initials,score,timestamp,metadata\nAAA,100,2025-07-16T15:30:00Z,[1]\n",
+		} {
+			if _, err := ReadHistory(strings.NewReader(input), FormatCSV); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+
+		if _, err := ReadHistory(strings.NewReader("initials,score,timestamp\n"), FormatCSV); !errors.Is(err, ErrNoScores) {
+			t.Errorf("Expected ErrNoScores for a header-only CSV, got %v", err)
+		}
+		if _, err := ReadHistory(strings.NewReader(`{"scores":[]}`), FormatJSON); !errors.Is(err, ErrNoScores) {
+			t.Errorf("Expected ErrNoScores for an empty history, got %v", err)
+		}
+		if _, err := ReadHistory(strings.NewReader(`not json`), FormatJSON); err == nil {
+			t.Error("Expected invalid JSON to be rejected")
+		}
+	})
+}
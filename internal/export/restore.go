@@ -0,0 +1,218 @@
+package export
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// LatestExport selects the most recent export run when restoring
+const LatestExport = "latest"
+
+// ListExports returns the IDs of the tenant's export runs with a manifest, oldest first
+func (e *Exporter) ListExports(ctx context.Context) ([]string, error) {
+	keys, err := e.store.List(ctx, e.root(ctx)+"/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exports: %w", err)
+	}
+
+	exportIDs := make([]string, 0)
+	for _, key := range keys {
+		exportID := path.Base(path.Dir(key))
+		if key != e.manifestKey(ctx, exportID) {
+			continue // Not a manifest, or another tenant's beneath the default namespace
+		}
+		exportIDs = append(exportIDs, exportID)
+	}
+
+	sort.Strings(exportIDs)
+	return exportIDs, nil
+}
+
+// GetManifest loads the manifest for an export run (or the latest run)
+func (e *Exporter) GetManifest(ctx context.Context, exportID string) (*models.ExportManifest, error) {
+	if exportID == LatestExport {
+		exportIDs, err := e.ListExports(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if len(exportIDs) == 0 {
+			return nil, fmt.Errorf("no exports found")
+		}
+		exportID = exportIDs[len(exportIDs)-1]
+	}
+
+	data, err := e.store.Get(ctx, e.manifestKey(ctx, exportID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest for export %s: %w", exportID, err)
+	}
+
+	var manifest models.ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+	}
+
+	return &manifest, nil
+}
+
+// gameSnapshot is a verified game export ready to be restored
+type gameSnapshot struct {
+	history    *models.AllScoresRecord
+	highScores *models.PlayerHighScores
+	board      *models.Leaderboard
+}
+
+// Restore rebuilds one game (or every game when gameID is empty) from an export run.
+// Every selected game is downloaded and verified against the manifest's checksum and
+// counts before anything is written, so a corrupt export never partially restores.
+func (e *Exporter) Restore(ctx context.Context, exportID, gameID string, dryRun bool) (*models.RestoreReport, error) {
+	manifest, err := e.GetManifest(ctx, exportID)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make([]models.ExportedGame, 0, len(manifest.Games))
+	for _, game := range manifest.Games {
+		if gameID == "" || game.GameID == gameID {
+			selected = append(selected, game)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("game %s not found in export %s", gameID, manifest.ExportID)
+	}
+
+	snapshots := make([]*gameSnapshot, len(selected))
+	for i, game := range selected {
+		snapshot, err := e.loadVerified(ctx, game)
+		if err != nil {
+			return nil, fmt.Errorf("verification failed for game %s: %w", game.GameID, err)
+		}
+		snapshots[i] = snapshot
+	}
+
+	report := &models.RestoreReport{
+		ExportID: manifest.ExportID,
+		DryRun:   dryRun,
+		Games:    make([]models.RestoredGame, 0, len(selected)),
+	}
+
+	for i, game := range selected {
+		restored := models.RestoredGame{
+			GameID:             game.GameID,
+			Scores:             game.Scores,
+			Players:            game.Players,
+			LeaderboardEntries: game.LeaderboardEntries,
+			SHA256:             game.SHA256,
+		}
+
+		if !dryRun {
+			if err := e.service.RestoreGame(ctx, snapshots[i].history, snapshots[i].highScores); err != nil {
+				return report, fmt.Errorf("failed to restore game %s: %w", game.GameID, err)
+			}
+			restored.Restored = true
+		}
+
+		report.Games = append(report.Games, restored)
+	}
+
+	report.CompletedAt = time.Now().UTC()
+	return report, nil
+}
+
+// loadVerified downloads a game's export and checks it against the manifest entry
+func (e *Exporter) loadVerified(ctx context.Context, game models.ExportedGame) (*gameSnapshot, error) {
+	data, err := e.store.Get(ctx, game.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	checksum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(checksum[:]); actual != game.SHA256 {
+		return nil, fmt.Errorf("checksum mismatch: manifest %s, object %s", game.SHA256, actual)
+	}
+
+	snapshot, err := decodeNDJSON(game.GameID, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(snapshot.history.Scores) != game.Scores {
+		return nil, fmt.Errorf("score count mismatch: manifest %d, object %d", game.Scores, len(snapshot.history.Scores))
+	}
+
+	players := 0
+	if snapshot.highScores != nil {
+		players = len(snapshot.highScores.HighScores)
+	}
+	if players != game.Players {
+		return nil, fmt.Errorf("player count mismatch: manifest %d, object %d", game.Players, players)
+	}
+
+	entries := 0
+	if snapshot.board != nil {
+		entries = len(snapshot.board.Entries)
+	}
+	if entries != game.LeaderboardEntries {
+		return nil, fmt.Errorf("leaderboard entry mismatch: manifest %d, object %d", game.LeaderboardEntries, entries)
+	}
+
+	return snapshot, nil
+}
+
+// decodeNDJSON reads a gzip-compressed NDJSON game export
+func decodeNDJSON(gameID string, data []byte) (*gameSnapshot, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress export: %w", err)
+	}
+	defer gz.Close()
+
+	snapshot := &gameSnapshot{
+		history: &models.AllScoresRecord{GameID: gameID, Scores: []models.ScoreEntry{}},
+	}
+
+	decoder := json.NewDecoder(gz)
+	for {
+		var record models.ExportRecord
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode export record: %w", err)
+		}
+
+		if record.GameID != gameID {
+			return nil, fmt.Errorf("record for game %s found in export of %s", record.GameID, gameID)
+		}
+
+		switch record.Type {
+		case models.ExportRecordLeaderboard:
+			snapshot.board = record.Leaderboard
+		case models.ExportRecordHighScores:
+			snapshot.highScores = record.HighScores
+		case models.ExportRecordScore:
+			if record.Score != nil {
+				snapshot.history.Scores = append(snapshot.history.Scores, *record.Score)
+				if record.Score.Timestamp.After(snapshot.history.Updated) {
+					snapshot.history.Updated = record.Score.Timestamp
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unknown export record type %q", strings.TrimSpace(record.Type))
+		}
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,183 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/objectstore"
+)
+
+// memoryStore is an in-memory objectstore.Store for tests
+type memoryStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{objects: make(map[string][]byte)}
+}
+
+func (m *memoryStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[key] = append([]byte(nil), data...)
+	return nil
+}
+
+func (m *memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", objectstore.ErrNotFound, key)
+	}
+	return data, nil
+}
+
+func (m *memoryStore) List(ctx context.Context, prefix string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	keys := make([]string, 0)
+	for key := range m.objects {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// writeTestExport stores a one-game export run and returns its manifest
+func writeTestExport(t *testing.T, exporter *Exporter, exportID string) *models.ExportManifest {
+	t.Helper()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	scores := []models.ScoreEntry{
+		{Initials: "AAA", Score: 1000, Timestamp: now.Add(-2 * time.Minute)},
+		{Initials: "BBB", Score: 3000, Timestamp: now.Add(-time.Minute)},
+		{Initials: "AAA", Score: 2000, Timestamp: now},
+	}
+	highScores := &models.PlayerHighScores{
+		GameID: "pacman",
+		HighScores: map[string]models.ScoreEntry{
+			"AAA": scores[2],
+			"BBB": scores[1],
+		},
+	}
+	board := &models.Leaderboard{GameID: "pacman", Entries: []models.ScoreEntry{scores[1], scores[2]}}
+
+	records := []models.ExportRecord{
+		{Type: models.ExportRecordLeaderboard, GameID: "pacman", Leaderboard: board},
+		{Type: models.ExportRecordHighScores, GameID: "pacman", HighScores: highScores},
+	}
+	for i := range scores {
+		records = append(records, models.ExportRecord{Type: models.ExportRecordScore, GameID: "pacman", Score: &scores[i]})
+	}
+
+	data, err := encodeNDJSON(records)
+	if err != nil {
+		t.Fatalf("Failed to encode export: %v", err)
+	}
+	checksum := sha256.Sum256(data)
+
+	manifest := &models.ExportManifest{
+		ExportID:  exportID,
+		CreatedAt: now,
+		Games: []models.ExportedGame{{
+			GameID:             "pacman",
+			Object:             exporter.gameKey(ctx, exportID, "pacman"),
+			Scores:             3,
+			Players:            2,
+			LeaderboardEntries: 2,
+			Bytes:              len(data),
+			SHA256:             hex.EncodeToString(checksum[:]),
+		}},
+	}
+
+	exporter.store.Put(ctx, manifest.Games[0].Object, data, "application/gzip")
+	manifestData, _ := json.Marshal(manifest)
+	exporter.store.Put(ctx, exporter.manifestKey(ctx, exportID), manifestData, "application/json")
+
+	return manifest
+}
+
+func TestRestoreVerification(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dry run verifies a valid export", func(t *testing.T) {
+		exporter := NewExporter(nil, newMemoryStore(), "exports")
+		writeTestExport(t, exporter, "20250716T150000Z")
+
+		report, err := exporter.Restore(ctx, "20250716T150000Z", "", true)
+		if err != nil {
+			t.Fatalf("Expected valid export to verify, got: %v", err)
+		}
+
+		if len(report.Games) != 1 || report.Games[0].Scores != 3 || report.Games[0].Restored {
+			t.Errorf("Unexpected dry run report: %+v", report.Games)
+		}
+	})
+
+	t.Run("latest selects the newest export run", func(t *testing.T) {
+		exporter := NewExporter(nil, newMemoryStore(), "exports")
+		writeTestExport(t, exporter, "20250716T150000Z")
+		writeTestExport(t, exporter, "20250717T150000Z")
+
+		report, err := exporter.Restore(ctx, LatestExport, "pacman", true)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if report.ExportID != "20250717T150000Z" {
+			t.Errorf("Expected latest export to be selected, got %s", report.ExportID)
+		}
+	})
+
+	t.Run("rejects objects that don't match the manifest checksum", func(t *testing.T) {
+		store := newMemoryStore()
+		exporter := NewExporter(nil, store, "exports")
+		manifest := writeTestExport(t, exporter, "20250716T150000Z")
+
+		data, _ := store.Get(ctx, manifest.Games[0].Object)
+		tampered := append(append([]byte(nil), data...), 0)
+		store.Put(ctx, manifest.Games[0].Object, tampered, "application/gzip")
+
+		if _, err := exporter.Restore(ctx, "20250716T150000Z", "", true); err == nil ||
+			!strings.Contains(err.Error(), "checksum mismatch") {
+			t.Errorf("Expected checksum mismatch, got %v", err)
+		}
+	})
+
+	t.Run("rejects exports whose counts don't match the manifest", func(t *testing.T) {
+		store := newMemoryStore()
+		exporter := NewExporter(nil, store, "exports")
+		manifest := writeTestExport(t, exporter, "20250716T150000Z")
+
+		manifest.Games[0].Scores = 4
+		manifestData, _ := json.Marshal(manifest)
+		store.Put(ctx, exporter.manifestKey(ctx, "20250716T150000Z"), manifestData, "application/json")
+
+		if _, err := exporter.Restore(ctx, "20250716T150000Z", "", true); err == nil ||
+			!strings.Contains(err.Error(), "score count mismatch") {
+			t.Errorf("Expected score count mismatch, got %v", err)
+		}
+	})
+
+	t.Run("reports games missing from the export", func(t *testing.T) {
+		exporter := NewExporter(nil, newMemoryStore(), "exports")
+		writeTestExport(t, exporter, "20250716T150000Z")
+
+		if _, err := exporter.Restore(ctx, "20250716T150000Z", "tetris", true); err == nil {
+			t.Error("Expected an error for a game not in the export")
+		}
+	})
+}
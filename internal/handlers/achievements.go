@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAchievements handles GET /api/v1/admin/games/:gameId/achievements
+// @Summary List a game's achievement definitions
+// @Description Games that haven't defined their own achievements use the defaults, reported with default true.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.AchievementDefinitionList
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to get achievements"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/achievements [get]
+func (h *AdminHandler) ListAchievements(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	list, err := h.service.AchievementDefinitions(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to get achievements", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get achievements"))
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// PutAchievement handles PUT /api/v1/admin/games/:gameId/achievements/:achievementId
+// @Summary Create or replace a game achievement
+// @Description threshold achievements unlock at a score of at least the threshold, plays after that many submissions, streak after submissions on that many consecutive UTC days, and rank on reaching that place on the leaderboard or better.
+// @Description A game using the defaults gets its own copy of them first. Players whose history already meets the achievement unlock it at once; unlocks are kept when an achievement is replaced.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param achievementId path string true "Achievement ID: lowercase letters, digits, underscores and hyphens"
+// @Param request body handlers.AchievementRequest true "Achievement definition"
+// @Success 200 {object} models.AchievementDefinition
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or definition"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to save the achievement"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/achievements/{achievementId} [put]
+func (h *AdminHandler) PutAchievement(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req AchievementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	definition := models.AchievementDefinition{
+		ID:          c.Param("achievementId"),
+		Name:        req.Name,
+		Description: req.Description,
+		Icon:        req.Icon,
+		Type:        req.Type,
+		Threshold:   req.Threshold,
+	}
+	if err := definition.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	saved, err := h.service.PutAchievement(c.Request.Context(), gameID, definition)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to save achievement", "achievement_id", definition.ID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to save achievement"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAchievementUpdated,
+		GameID:  gameID,
+		Details: map[string]interface{}{"achievement_id": saved.ID, "type": saved.Type, "threshold": saved.Threshold},
+	})
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeleteAchievement handles DELETE /api/v1/admin/games/:gameId/achievements/:achievementId
+// @Summary Delete a game achievement
+// @Description Removes the achievement and every player's unlock of it. A game using the defaults keeps the rest of them as its own.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param achievementId path string true "Achievement ID"
+// @Success 200 {object} models.AchievementDefinition "The deleted achievement"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "The game doesn't define the achievement"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to delete the achievement"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/achievements/{achievementId} [delete]
+func (h *AdminHandler) DeleteAchievement(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+	achievementID := c.Param("achievementId")
+
+	deleted, err := h.service.DeleteAchievement(c.Request.Context(), gameID, achievementID)
+	if errors.Is(err, leaderboard.ErrAchievementNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAchievementNotFound, "The game doesn't define this achievement",
+			map[string]interface{}{"game_id": gameID, "achievement_id": achievementID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete achievement", "achievement_id", achievementID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete achievement"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAchievementDeleted,
+		GameID:  gameID,
+		Details: map[string]interface{}{"achievement_id": deleted.ID, "name": deleted.Name},
+	})
+
+	c.JSON(http.StatusOK, deleted)
+}
+
+// PutAchievementBadge handles PUT /api/v1/admin/games/:gameId/achievements/:achievementId/badge
+// @Summary Upload an achievement's badge
+// @Description Stores a PNG, GIF, JPEG or WebP image of up to 64 KB, sent base64, to show for the achievement instead of its icon. Achievement payloads carry its URL as badge, versioned so displays can cache it until it's replaced.
+// @Description A game using the defaults gets its own copy of them first.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param achievementId path string true "Achievement ID"
+// @Param request body handlers.AchievementBadgeRequest true "Badge image"
+// @Success 200 {object} models.AchievementDefinition
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or image"
+// @Failure 404 {object} handlers.StandardErrorResponse "The game doesn't define the achievement"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to save the badge"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/achievements/{achievementId}/badge [put]
+func (h *AdminHandler) PutAchievementBadge(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+	achievementID := c.Param("achievementId")
+
+	var req AchievementBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	contentType, err := models.BadgeContentType(req.Image)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	saved, err := h.service.PutAchievementBadge(c.Request.Context(), gameID, achievementID, req.Image)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if errors.Is(err, leaderboard.ErrAchievementNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAchievementNotFound, "The game doesn't define this achievement",
+			map[string]interface{}{"game_id": gameID, "achievement_id": achievementID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to save achievement badge", "achievement_id", achievementID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to save badge"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAchievementBadgeUpdated,
+		GameID:  gameID,
+		Details: map[string]interface{}{"achievement_id": saved.ID, "content_type": contentType, "size": len(req.Image)},
+	})
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeleteAchievementBadge handles DELETE /api/v1/admin/games/:gameId/achievements/:achievementId/badge
+// @Summary Remove an achievement's badge
+// @Description The achievement is shown with its icon again.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param achievementId path string true "Achievement ID"
+// @Success 200 {object} models.AchievementDefinition
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "The game doesn't define the achievement, or it has no badge"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to remove the badge"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/achievements/{achievementId}/badge [delete]
+func (h *AdminHandler) DeleteAchievementBadge(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+	achievementID := c.Param("achievementId")
+
+	definition, err := h.service.DeleteAchievementBadge(c.Request.Context(), gameID, achievementID)
+	switch {
+	case errors.Is(err, leaderboard.ErrAchievementNotFound):
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAchievementNotFound, "The game doesn't define this achievement",
+			map[string]interface{}{"game_id": gameID, "achievement_id": achievementID}))
+		return
+	case errors.Is(err, leaderboard.ErrBadgeNotFound):
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeBadgeNotFound, "The achievement has no badge",
+			map[string]interface{}{"game_id": gameID, "achievement_id": achievementID}))
+		return
+	case err != nil:
+		requestLogger(c).Error("failed to remove achievement badge", "achievement_id", achievementID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to remove badge"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAchievementBadgeDeleted,
+		GameID:  gameID,
+		Details: map[string]interface{}{"achievement_id": definition.ID},
+	})
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// UpdateAchievementTheme handles PUT /api/v1/admin/games/:gameId/achievement-theme
+// @Summary Set the icon theme of a game's achievements
+// @Description Shows the game's achievements with a built-in icon set: classic (each achievement's own icon), medals or retro. Themes pick icons by achievement ID, then type, and apply wherever achievements are returned. Uploaded badges are shown instead of either.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.AchievementThemeRequest true "Icon theme"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or theme"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the theme"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/achievement-theme [put]
+func (h *AdminHandler) UpdateAchievementTheme(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req AchievementThemeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if !models.ValidAchievementTheme(req.Theme) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"theme", req.Theme, "one of classic, medals or retro"))
+		return
+	}
+
+	game, err := h.service.SetAchievementTheme(c.Request.Context(), gameID, req.Theme)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update achievement theme", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update achievement theme"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAchievementThemeUpdated,
+		GameID:  gameID,
+		Details: map[string]interface{}{"theme": req.Theme},
+	})
+
+	c.JSON(http.StatusOK, game)
+}
+
+// GetAchievementBadge handles GET /api/v1/games/:gameId/achievements/:achievementId/badge
+// @Summary Get an achievement's badge image
+// @Description Serves the uploaded image with an ETag, cacheable for a day. Achievement payloads link to it as badge.
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param achievementId path string true "Achievement ID"
+// @Success 200 "The badge image"
+// @Success 304 "The client's copy is current"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "The achievement has no badge"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to get the badge"
+// @Router /api/v1/games/{gameId}/achievements/{achievementId}/badge [get]
+func (h *LeaderboardHandler) GetAchievementBadge(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+	achievementID := c.Param("achievementId")
+
+	badge, err := h.service.AchievementBadge(c.Request.Context(), gameID, achievementID)
+	if errors.Is(err, leaderboard.ErrBadgeNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeBadgeNotFound, "The achievement has no badge",
+			map[string]interface{}{"game_id": gameID, "achievement_id": achievementID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to get achievement badge", "achievement_id", achievementID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get badge"))
+		return
+	}
+
+	etag := `"` + badge.Checksum[:16] + `"`
+	c.Header("Cache-Control", badgeCacheControl)
+	c.Header("ETag", etag)
+	c.Header("Last-Modified", badge.Updated.UTC().Format(http.TimeFormat))
+	// Uploaded bytes are only ever served as the image type they were checked to be
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Content-Security-Policy", "default-src 'none'")
+	if notModified(c.Request, etag, badge.Updated) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, badge.ContentType, badge.Data)
+}
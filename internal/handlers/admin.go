@@ -0,0 +1,1220 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/export"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/selfcheck"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminActor identifies admin API callers in the audit log
+const adminActor = audit.AdminActor
+
+// AdminHandler handles HTTP requests for operator-only data management
+type AdminHandler struct {
+	service  *leaderboard.Service
+	exporter *export.Exporter // nil when object storage isn't configured
+	audit    *audit.Log
+	keys     *apikeys.Store
+	usage    *audit.UsageTracker
+	checker  *selfcheck.Checker
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(service *leaderboard.Service, exporter *export.Exporter, auditLog *audit.Log, keys *apikeys.Store, usage *audit.UsageTracker, checker *selfcheck.Checker) *AdminHandler {
+	return &AdminHandler{
+		service:  service,
+		exporter: exporter,
+		audit:    auditLog,
+		keys:     keys,
+		usage:    usage,
+		checker:  checker,
+	}
+}
+
+// ListGames handles GET /api/v1/admin/games
+// @Summary List registered games
+// @Tags admin
+// @Success 200 {object} handlers.GameListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list games"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games [get]
+func (h *AdminHandler) ListGames(c *gin.Context) {
+	gameIDs, err := h.service.ListGames(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list games", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list games"))
+		return
+	}
+
+	c.JSON(http.StatusOK, GameListResponse{Games: gameIDs})
+}
+
+// GetGame handles GET /api/v1/admin/games/:gameId
+// @Summary Get a game's registry record
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameInfo
+// @Failure 404 {object} handlers.StandardErrorResponse "Game not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId} [get]
+func (h *AdminHandler) GetGame(c *gin.Context) {
+	gameID := c.Param("gameId")
+
+	game, err := h.service.GetGame(c.Request.Context(), gameID)
+	if errors.Is(err, leaderboard.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "Game not found",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to get game", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get game"))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// FindDuplicateGames handles GET /api/v1/admin/games/duplicates
+// @Summary Find registered games that are likely the same game
+// @Description Groups games whose IDs differ only in letter case, surrounding whitespace or, for IDs of 4 or more characters, one added, removed or changed character. Each group suggests the game with the most scores as the target to merge the others into with POST /api/v1/admin/games/{gameId}/merge.
+// @Tags admin
+// @Success 200 {object} models.DuplicateGameReport
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to compare games"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/duplicates [get]
+func (h *AdminHandler) FindDuplicateGames(c *gin.Context) {
+	report, err := h.service.FindDuplicateGames(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to find duplicate games", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to compare games"))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// MergeGame handles POST /api/v1/admin/games/:gameId/merge
+// @Summary Merge a game's scores into another game
+// @Description Adds the game's score history to the target's, skipping scores the target already has, and rebuilds the target's high scores and leaderboard. The game is then emptied and dropped from the game list, and later submissions for it go to the target. Needs admin:write for both games.
+// @Tags admin
+// @Param gameId path string true "Game ID to merge away" minlength(1) maxlength(50)
+// @Param request body handlers.MergeGameRequest true "Target game"
+// @Success 200 {object} models.GameMerge
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request, the same game, or games with different decimals"
+// @Failure 404 {object} handlers.StandardErrorResponse "Game not found or already merged"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to merge games"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/merge [post]
+func (h *AdminHandler) MergeGame(c *gin.Context) {
+	gameID := c.Param("gameId")
+
+	var req MergeGameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if p := principal(c); p != nil && !p.CanAccessGame(req.Into) {
+		c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+			ErrorCodeInsufficientScope, "API key is not scoped to the target game",
+			map[string]interface{}{"game_id": req.Into}))
+		return
+	}
+
+	result, err := h.service.MergeGames(c.Request.Context(), gameID, req.Into, req.DryRun)
+	if errors.Is(err, leaderboard.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "Game not found or already merged",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+	if errors.Is(err, leaderboard.ErrMergeSameGame) || errors.Is(err, leaderboard.ErrMergeScoringDiffers) {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error(),
+			map[string]interface{}{"game_id": gameID, "into": req.Into}))
+		return
+	}
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to merge games", "game_id", gameID, "into", req.Into, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to merge games"))
+		return
+	}
+
+	if result.Merged {
+		h.recordAudit(c, models.AuditEntry{
+			Action: audit.ActionGameMerged,
+			GameID: gameID,
+			Details: map[string]interface{}{
+				"into":       req.Into,
+				"moved":      result.Moved,
+				"duplicates": result.Duplicates,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateRetention handles PUT /api/v1/admin/games/:gameId/retention
+// @Summary Set a game's history retention policy
+// @Description The game's policy replaces the deployment default; zero limits keep everything
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.RetentionPolicyRequest true "Retention policy"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or policy"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the policy"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/retention [put]
+func (h *AdminHandler) UpdateRetention(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req RetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	if req.HistoryDays < 0 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"history_days", fmt.Sprintf("%d", req.HistoryDays), "zero (keep everything) or a positive number of days"))
+		return
+	}
+	if req.MaxScores < 0 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"max_scores", fmt.Sprintf("%d", req.MaxScores), "zero (keep everything) or a positive number of scores"))
+		return
+	}
+
+	policy := &models.RetentionPolicy{HistoryDays: req.HistoryDays, MaxScores: req.MaxScores}
+	h.updateRetention(c, gameID, policy, map[string]interface{}{
+		"history_days": req.HistoryDays,
+		"max_scores":   req.MaxScores,
+	})
+}
+
+// ResetRetention handles DELETE /api/v1/admin/games/:gameId/retention
+// @Summary Remove a game's history retention policy
+// @Description The game falls back to the deployment default policy
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the policy"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/retention [delete]
+func (h *AdminHandler) ResetRetention(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	h.updateRetention(c, gameID, nil, map[string]interface{}{"default": true})
+}
+
+// updateRetention stores a game's retention policy, nil for the default, and audits it
+func (h *AdminHandler) updateRetention(c *gin.Context, gameID string, policy *models.RetentionPolicy, details map[string]interface{}) {
+	ctx := c.Request.Context()
+	game, err := h.service.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.Retention = policy
+		return nil
+	})
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update retention policy", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update retention policy"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionRetentionPolicyUpdated,
+		Actor:   actor(c),
+		GameID:  gameID,
+		Details: details,
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionRetentionPolicyUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Retention policy updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateLeaderboardSize handles PUT /api/v1/admin/games/:gameId/leaderboard-size
+// @Summary Set a game's leaderboard size
+// @Description The leaderboard is regenerated at the new size straight away. A larger board is backfilled from players' existing high scores, reported as backfilled.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.LeaderboardSizeRequest true "Leaderboard size"
+// @Success 200 {object} models.LeaderboardResize
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or size"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the size"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/leaderboard-size [put]
+func (h *AdminHandler) UpdateLeaderboardSize(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req LeaderboardSizeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	if req.MaxEntries < 0 || req.MaxEntries > models.MaxLeaderboardEntries {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"max_entries", fmt.Sprintf("%d", req.MaxEntries),
+			fmt.Sprintf("zero (use the default) or between 1 and %d", models.MaxLeaderboardEntries)))
+		return
+	}
+
+	ctx := c.Request.Context()
+	resize, err := h.service.SetLeaderboardSize(ctx, gameID, req.MaxEntries)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update leaderboard size", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update leaderboard size"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionLeaderboardSizeUpdated,
+		Actor:   actor(c),
+		GameID:  gameID,
+		Details: map[string]interface{}{"max_entries": req.MaxEntries, "previous_size": resize.PreviousSize, "backfilled": resize.Backfilled},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionLeaderboardSizeUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Leaderboard size updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, resize)
+}
+
+// UpdateDailySubmissions handles PUT /api/v1/admin/games/:gameId/daily-submissions
+// @Summary Set a game's daily submission budget
+// @Description Limits how many submissions per initials count each UTC day, emulating token-limited
+// @Description tournament rules. Extra plays are still accepted but flagged non-counting.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.DailySubmissionsRequest true "Daily submission budget"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or budget"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the budget"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/daily-submissions [put]
+func (h *AdminHandler) UpdateDailySubmissions(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req DailySubmissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	if req.DailySubmissions < 0 || req.DailySubmissions > models.MaxDailySubmissions {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"daily_submissions", fmt.Sprintf("%d", req.DailySubmissions),
+			fmt.Sprintf("zero (unlimited) or between 1 and %d", models.MaxDailySubmissions)))
+		return
+	}
+
+	ctx := c.Request.Context()
+	game, err := h.service.SetDailySubmissions(ctx, gameID, req.DailySubmissions)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update daily submission budget", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update daily submission budget"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionDailySubmissionsUpdated,
+		Actor:   actor(c),
+		GameID:  gameID,
+		Details: map[string]interface{}{"daily_submissions": req.DailySubmissions},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionDailySubmissionsUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Daily submission budget updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateScoring handles PUT /api/v1/admin/games/:gameId/scoring
+// @Summary Set a game's scoring mode
+// @Description Lets a game keep decimal scores, stored as fixed-point integers, and accept negative scores. Send {} for whole, non-negative scores.
+// @Description The decimals can only change before the game's first score; allow_negative can change at any time and affects new submissions.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body models.ScoringSettings true "Scoring settings"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or settings"
+// @Failure 409 {object} handlers.StandardErrorResponse "The game already has scores stored with its current decimals"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the scoring mode"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/scoring [put]
+func (h *AdminHandler) UpdateScoring(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var scoring models.ScoringSettings
+	if err := c.ShouldBindJSON(&scoring); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if err := scoring.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	game, err := h.service.SetScoring(ctx, gameID, scoring)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if errors.Is(err, leaderboard.ErrScoringLocked) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeScoringLocked, "The game already has scores stored with its current decimals",
+			map[string]interface{}{"game_id": gameID, "decimals": scoring.Decimals}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update scoring mode", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update scoring mode"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action: audit.ActionScoringUpdated,
+		Actor:  actor(c),
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"decimals":       scoring.Decimals,
+			"allow_negative": scoring.AllowNegative,
+		},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionScoringUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Scoring mode updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateRequirePIN handles PUT /api/v1/admin/games/:gameId/require-pin
+// @Summary Require PINs for claimed initials in a game
+// @Description When on, submissions under initials claimed through /api/v1/players must carry the initials' PIN. Unclaimed initials submit as before.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.RequirePINRequest true "Whether to require PINs"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the setting"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/require-pin [put]
+func (h *AdminHandler) UpdateRequirePIN(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req RequirePINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	ctx := c.Request.Context()
+	game, err := h.service.SetRequirePIN(ctx, gameID, req.RequirePIN)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update PIN requirement", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update PIN requirement"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionRequirePINUpdated,
+		Actor:   actor(c),
+		GameID:  gameID,
+		Details: map[string]interface{}{"require_pin": req.RequirePIN},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionRequirePINUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "PIN requirement updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateInitialsPolicy handles PUT /api/v1/admin/games/:gameId/initials-policy
+// @Summary Set who a game's initials stand for
+// @Description shared treats everyone entering the same initials as one player. device scopes initials to the enrolled device that submitted them, so AAA on two cabinets holds two places on the board; stats and rank lookups take a device_id to pick one. claimed only accepts initials claimed through /api/v1/players, with their PIN.
+// @Description Switching to or from device rebuilds the game's high scores from history.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.InitialsPolicyRequest true "Initials policy"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or policy"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the policy"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/initials-policy [put]
+func (h *AdminHandler) UpdateInitialsPolicy(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req InitialsPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if !models.ValidInitialsPolicy(req.Policy) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"policy", req.Policy, "shared, device or claimed"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	game, err := h.service.SetInitialsPolicy(ctx, gameID, req.Policy)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update initials policy", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update initials policy"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionInitialsPolicyUpdated,
+		Actor:   actor(c),
+		GameID:  gameID,
+		Details: map[string]interface{}{"policy": req.Policy},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionInitialsPolicyUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Initials policy updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateHappyHours handles PUT /api/v1/admin/games/:gameId/happy-hours
+// @Summary Schedule a game's happy hours
+// @Description Replaces the game's score multiplier windows. Weekly windows run on their days between start and end in their time zone,
+// @Description e.g. 2x points Fridays 18:00 to 21:00; one-off windows run between starts_at and ends_at. Scores submitted during a window
+// @Description stand on the board multiplied, with raw_score, multiplier and happy_hour recorded on the entry. Overlapping windows don't
+// @Description stack: the highest multiplier applies. Anti-cheat rules judge the raw score. Send an empty list to remove them all.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.HappyHoursRequest true "Happy hours"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or happy hours"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the happy hours"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/happy-hours [put]
+func (h *AdminHandler) UpdateHappyHours(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req HappyHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if err := models.ValidateHappyHours(req.HappyHours); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	game, err := h.service.SetHappyHours(ctx, gameID, req.HappyHours)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update happy hours", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update happy hours"))
+		return
+	}
+
+	ids := make([]string, len(req.HappyHours))
+	for i, happyHour := range req.HappyHours {
+		ids[i] = happyHour.ID
+	}
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionHappyHoursUpdated,
+		Actor:   actor(c),
+		GameID:  gameID,
+		Details: map[string]interface{}{"happy_hours": ids},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionHappyHoursUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Happy hours updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateSunset handles PUT /api/v1/admin/games/:gameId/sunset
+// @Summary Schedule a game's sunset
+// @Description Winds a game down: from sunset_at, or at once when it's omitted or has passed, submissions fail with 410 GAME_SUNSET carrying the date and message,
+// @Description while its leaderboard, history and players stay readable. Until then, accepted submissions report the sunset in sunset and a Sunset header.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.GameSunsetRequest true "When the game stops taking submissions"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or sunset"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the sunset"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/sunset [put]
+func (h *AdminHandler) UpdateSunset(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req GameSunsetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	sunset := models.GameSunset{At: time.Now(), Message: req.Message}
+	if req.SunsetAt != nil {
+		sunset.At = *req.SunsetAt
+	}
+	if err := sunset.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	game, err := h.service.SetSunset(c.Request.Context(), gameID, sunset)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update sunset", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update sunset"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionGameSunsetUpdated,
+		GameID:  gameID,
+		Details: map[string]interface{}{"sunset_at": game.Settings.Sunset.At, "message": game.Settings.Sunset.Message},
+	})
+
+	c.JSON(http.StatusOK, game)
+}
+
+// ClearSunset handles DELETE /api/v1/admin/games/:gameId/sunset
+// @Summary Cancel a game's sunset
+// @Description The game takes submissions again, even if its sunset had passed
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the sunset"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/sunset [delete]
+func (h *AdminHandler) ClearSunset(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	game, err := h.service.ClearSunset(c.Request.Context(), gameID)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to clear sunset", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update sunset"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{Action: audit.ActionGameSunsetCleared, GameID: gameID})
+
+	c.JSON(http.StatusOK, game)
+}
+
+// CreateSigningSecret handles POST /api/v1/admin/games/:gameId/signing-secret
+// @Summary Require signed submissions for a game
+// @Description Generates a signing secret and from then on refuses score submissions not signed with it. The secret is only shown in this response.
+// @Description Creating a secret for a game that has one rotates it: the old secret stops working at once. Games requiring signatures refuse scores by email and gRPC, which can't carry one.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 201 {object} models.SigningSecret
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to create the secret"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/signing-secret [post]
+func (h *AdminHandler) CreateSigningSecret(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	secret, err := h.service.CreateSigningSecret(c.Request.Context(), gameID)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to create signing secret", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to create signing secret"))
+		return
+	}
+
+	// The secret itself stays out of the audit log
+	h.recordAudit(c, models.AuditEntry{Action: audit.ActionSigningSecretRotated, GameID: gameID})
+
+	c.JSON(http.StatusCreated, secret)
+}
+
+// DeleteSigningSecret handles DELETE /api/v1/admin/games/:gameId/signing-secret
+// @Summary Stop requiring signed submissions for a game
+// @Description Discards the game's signing secret; the game takes unsigned submissions again
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to delete the secret"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/signing-secret [delete]
+func (h *AdminHandler) DeleteSigningSecret(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	game, err := h.service.DeleteSigningSecret(c.Request.Context(), gameID)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete signing secret", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete signing secret"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{Action: audit.ActionSigningDisabled, GameID: gameID})
+
+	c.JSON(http.StatusOK, game)
+}
+
+// UpdateAntiCheat handles PUT /api/v1/admin/games/:gameId/anti-cheat
+// @Summary Set a game's anti-cheat rules
+// @Description Bounds plausible submissions: a maximum score, a maximum jump over the player's high score,
+// @Description and a minimum gap between submissions from the same initials. Zero disables a rule; send {} to remove them all.
+// @Description Violating submissions are rejected with SUSPICIOUS_SCORE, or accepted and listed for review when action is flag.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body models.AntiCheatRules true "Anti-cheat rules"
+// @Success 200 {object} models.GameInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or rules"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to update the rules"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/anti-cheat [put]
+func (h *AdminHandler) UpdateAntiCheat(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var rules models.AntiCheatRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if err := rules.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	game, err := h.service.SetAntiCheatRules(ctx, gameID, rules)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update anti-cheat rules", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update anti-cheat rules"))
+		return
+	}
+
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action: audit.ActionAntiCheatUpdated,
+		Actor:  actor(c),
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"max_score":            rules.MaxScore,
+			"max_delta":            rules.MaxDelta,
+			"min_interval_seconds": rules.MinIntervalSeconds,
+			"action":               rules.Action,
+		},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionAntiCheatUpdated, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Anti-cheat rules updated but audit entry failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
+
+// SimulateAntiCheat handles POST /api/v1/admin/games/:gameId/anti-cheat/simulate
+// @Summary Simulate anti-cheat rules on hypothetical submissions
+// @Description Runs a batch of hypothetical submissions, in order, through the game's anti-cheat rules, or the rules sent to try instead, and reports which would be accepted, flagged or rejected and which rules each broke, so thresholds can be tuned before they're enforced.
+// @Description Each submission is judged against the game's history and the batch's earlier submissions that would have been stored. Submissions that would be refused before the rules, for their initials, the blocklist or the game's scoring settings, are reported invalid. Nothing is stored.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.SimulateAntiCheatRequest true "Submissions, and rules to try"
+// @Success 200 {object} models.AntiCheatSimulation
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, submissions or rules"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to run the simulation"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/anti-cheat/simulate [post]
+func (h *AdminHandler) SimulateAntiCheat(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req SimulateAntiCheatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if req.Rules != nil {
+		if err := req.Rules.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+				ErrorCodeValidationFailed, err.Error()))
+			return
+		}
+	}
+
+	simulation, err := h.service.SimulateAntiCheat(c.Request.Context(), gameID, req.Rules, req.Submissions)
+	if err != nil {
+		requestLogger(c).Error("failed to simulate anti-cheat rules", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to run the simulation"))
+		return
+	}
+
+	c.JSON(http.StatusOK, simulation)
+}
+
+// GetFlaggedSubmissions handles GET /api/v1/admin/games/:gameId/flagged
+// @Summary List submissions flagged by anti-cheat rules
+// @Description Lists scores that were accepted but broke the game's anti-cheat rules, newest first, with the rules each broke.
+// @Description Remove a cheating score with DELETE /api/v1/games/{gameId}/scores.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.FlaggedSubmissionsResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/flagged [get]
+func (h *AdminHandler) GetFlaggedSubmissions(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.FlaggedSubmissions(c.Request.Context(), gameID))
+}
+
+// GetDeviceAnalytics handles GET /api/v1/admin/games/:gameId/devices
+// @Summary Compare the devices submitting a game's scores
+// @Description Per enrolled device over the last days whole UTC days: plays, plays per day, average and high score, how far its average is from the game's, and uptime inferred from the days it submitted anything, with the longest stretch without a submission. A cabinet whose average strays far from the others may be miscalibrated; one with gaps or falling plays may be failing. Submissions made without a device key are counted as unattributed.
+// @Tags admin
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param days query integer false "Days to compare, default 30, up to 90"
+// @Success 200 {object} models.DeviceAnalytics
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or days"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to compute device analytics"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/devices [get]
+func (h *AdminHandler) GetDeviceAnalytics(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	days := models.DefaultDeviceAnalyticsDays
+	if daysStr := c.Query("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > models.MaxDeviceAnalyticsDays {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"days", daysStr, fmt.Sprintf("integer between 1 and %d", models.MaxDeviceAnalyticsDays)))
+			return
+		}
+		days = parsed
+	}
+
+	analytics, err := h.service.DeviceAnalytics(c.Request.Context(), gameID, days, time.Now())
+	if err != nil {
+		requestLogger(c).Error("failed to compute device analytics", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to compute device analytics"))
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
+
+// GetSelfCheck handles GET /api/v1/admin/selfcheck
+// Returns the startup report, or runs the checks again with ?refresh=true
+// @Summary Get the startup self-check report
+// @Tags diagnostics
+// @Param refresh query boolean false "Run the checks again"
+// @Success 200 {object} models.SelfCheckReport
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/selfcheck [get]
+func (h *AdminHandler) GetSelfCheck(c *gin.Context) {
+	report := h.checker.Last()
+	if report == nil || c.Query("refresh") == "true" {
+		report = h.checker.Run(c.Request.Context())
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetClockSkew handles GET /api/v1/admin/clock-skew
+// @Summary Get the latest clock skew reading
+// @Tags diagnostics
+// @Success 200 {object} models.ClockSkewReading
+// @Failure 503 {object} handlers.StandardErrorResponse "Clock skew has not been measured"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/clock-skew [get]
+func (h *AdminHandler) GetClockSkew(c *gin.Context) {
+	monitor := h.checker.SkewMonitor()
+	if monitor == nil || monitor.Last() == nil {
+		c.JSON(http.StatusServiceUnavailable, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Clock skew has not been measured"))
+		return
+	}
+
+	c.JSON(http.StatusOK, monitor.Last())
+}
+
+// ListExports handles GET /api/v1/admin/exports
+// @Summary List export runs
+// @Tags exports
+// @Success 200 {object} handlers.ExportListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list exports"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/exports [get]
+func (h *AdminHandler) ListExports(c *gin.Context) {
+	exportIDs, err := h.exporter.ListExports(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list exports", "error", err)
+		c.JSON(http.StatusBadGateway, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list exports",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK, ExportListResponse{Exports: exportIDs})
+}
+
+// GetExport handles GET /api/v1/admin/exports/:exportId
+// @Summary Get an export run's manifest
+// @Tags exports
+// @Param exportId path string true "Export run ID"
+// @Success 200 {object} models.ExportManifest
+// @Failure 404 {object} handlers.StandardErrorResponse "Export not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/exports/{exportId} [get]
+func (h *AdminHandler) GetExport(c *gin.Context) {
+	exportID := c.Param("exportId")
+
+	manifest, err := h.exporter.GetManifest(c.Request.Context(), exportID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeExportNotFound, "Export not found",
+			map[string]interface{}{"export_id": exportID}))
+		return
+	}
+
+	c.JSON(http.StatusOK, manifest)
+}
+
+// CreateExport handles POST /api/v1/admin/exports (runs an export immediately)
+// @Summary Run an export now
+// @Tags exports
+// @Success 201 {object} models.ExportManifest
+// @Failure 500 {object} handlers.StandardErrorResponse "Export failed"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/exports [post]
+func (h *AdminHandler) CreateExport(c *gin.Context) {
+	manifest, err := h.exporter.ExportAll(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("export failed", "error", err)
+		c.JSON(http.StatusBadGateway, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Export failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusCreated, manifest)
+}
+
+// CreateDataset handles POST /api/v1/admin/datasets (publishes an anonymized dataset)
+// @Summary Publish an anonymized dataset
+// @Tags exports
+// @Param request body handlers.DatasetRequest false "Anonymization mode"
+// @Success 201 {object} models.DatasetManifest
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid mode"
+// @Failure 500 {object} handlers.StandardErrorResponse "Dataset export failed"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/datasets [post]
+func (h *AdminHandler) CreateDataset(c *gin.Context) {
+	var req DatasetRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+				ErrorCodeInvalidRequest, "Invalid request format",
+				map[string]interface{}{"validation_error": err.Error()}))
+			return
+		}
+	}
+
+	if req.Mode == "" {
+		req.Mode = models.AnonymizeHash
+	}
+	if req.Mode != models.AnonymizeHash && req.Mode != models.AnonymizePlayerID && req.Mode != models.AnonymizeStrip {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"mode", req.Mode, "one of hash, player_id, strip"))
+		return
+	}
+
+	manifest, err := h.exporter.ExportAnonymized(c.Request.Context(), req.Mode)
+	if err != nil {
+		requestLogger(c).Error("dataset export failed", "mode", req.Mode, "error", err)
+		c.JSON(http.StatusBadGateway, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Dataset export failed",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusCreated, manifest)
+}
+
+// Restore handles POST /api/v1/admin/restore
+// @Summary Restore data from an export run
+// @Tags exports
+// @Param request body handlers.RestoreRequest true "Export to restore"
+// @Success 200 {object} models.RestoreReport
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request"
+// @Failure 404 {object} handlers.StandardErrorResponse "Export not found"
+// @Failure 422 {object} handlers.StandardErrorResponse "Export failed verification"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/restore [post]
+func (h *AdminHandler) Restore(c *gin.Context) {
+	var req RestoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	report, err := h.exporter.Restore(c.Request.Context(), req.ExportID, req.GameID, req.DryRun)
+	if err != nil {
+		requestLogger(c).Error("restore failed", "export_id", req.ExportID, "error", err)
+		details := map[string]interface{}{
+			"export_id": req.ExportID,
+			"error":     err.Error(),
+		}
+		if report != nil {
+			details["partial_report"] = report
+		}
+		c.JSON(http.StatusUnprocessableEntity, NewStandardErrorResponse(c,
+			ErrorCodeRestoreFailed, "Restore failed", details))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"rawboard/internal/adminui"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminUIPolicy limits the admin UI to its own files and API, and keeps it out of frames
+// so its buttons can't be clickjacked
+const adminUIPolicy = "default-src 'self'; frame-ancestors 'none'; base-uri 'none'; form-action 'self'"
+
+// SetupAdminUIRoutes serves the embedded operator UI at /admin. The pages are public;
+// the UI asks for an API key and sends it with each admin API call it makes.
+func SetupAdminUIRoutes(r *gin.Engine) {
+	ui := r.Group("/admin")
+	ui.Use(adminUIHeaders())
+	ui.StaticFS("/", http.FS(adminui.Files)) // GET /admin/
+}
+
+// adminUIHeaders sets the security headers for the admin UI, and revalidates its files so
+// an upgraded server's UI is picked up at once
+func adminUIHeaders() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Content-Security-Policy", adminUIPolicy)
+		c.Header("X-Frame-Options", "DENY")
+		c.Header("X-Content-Type-Options", "nosniff")
+		c.Header("Referrer-Policy", "no-referrer")
+		c.Header("Cache-Control", "no-cache")
+		c.Next()
+	}
+}
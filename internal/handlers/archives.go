@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResetGame handles POST /api/v1/games/:gameId/reset
+// @Summary Archive a game and start it afresh
+// @Description Archives the game's leaderboard, high scores and complete score history under an ID named by the time, then clears them along with activity tallies, time series, top mover snapshots, undoable deletions and open disputes. Settings, seasons and achievement unlocks are kept. Live streams and event subscribers see the board reset with reason game.reset. Read archives back from GET /api/v1/admin/games/{gameId}/archives.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.ResetGameRequest false "Why the game is reset"
+// @Success 200 {object} models.GameArchiveInfo
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or request"
+// @Failure 404 {object} handlers.StandardErrorResponse "Game not found"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to archive or clear the game"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId}/reset [post]
+func (h *AdminHandler) ResetGame(c *gin.Context) {
+	gameID, ok := archiveGameID(c)
+	if !ok {
+		return
+	}
+
+	var req ResetGameRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+				ErrorCodeInvalidRequest, "Invalid request format",
+				map[string]interface{}{"validation_error": err.Error()}))
+			return
+		}
+	}
+
+	archive, err := h.service.ResetGame(c.Request.Context(), gameID, req.Reason)
+	if errors.Is(err, leaderboard.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "Game not found",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to reset game", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to reset the game",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action: audit.ActionGameReset,
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"archive_id": archive.ArchiveID,
+			"reason":     archive.Reason,
+			"scores":     archive.Scores,
+			"players":    archive.Players,
+		},
+	})
+
+	c.JSON(http.StatusOK, archive)
+}
+
+// DeleteGame handles DELETE /api/v1/games/:gameId
+// @Summary Delete a game and everything kept for it
+// @Description Removes the game's leaderboard, score history, high scores, achievements and badges, seasons, archives, settings, signing secret, webhooks and everything derived from them, and takes it out of the registry and its creator's game quota. Nothing is archived, so export the game first if it may be needed. Call without confirm to get 409 CONFIRMATION_REQUIRED with the game's confirm_token and what would be deleted, then repeat with ?confirm=<token>. The token changes whenever the game takes a score. Live streams and event subscribers see the board reset with reason game.deleted. Receipts already issued keep working.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param confirm query string false "Confirm token from a previous call"
+// @Success 200 {object} models.GameDeletion
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "Game not found"
+// @Failure 409 {object} handlers.StandardErrorResponse "Missing or outdated confirm token; details carry the current one"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to delete the game"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId} [delete]
+func (h *AdminHandler) DeleteGame(c *gin.Context) {
+	gameID, ok := archiveGameID(c)
+	if !ok {
+		return
+	}
+	ctx := c.Request.Context()
+
+	deletion, err := h.service.DeleteGame(ctx, gameID, c.Query("confirm"))
+	if errors.Is(err, models.ErrDeletionNotConfirmed) {
+		deletion, err = h.service.PlanGameDeletion(ctx, gameID)
+		if err == nil {
+			c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+				ErrorCodeConfirmationRequired, "Repeat the deletion with ?confirm= and the confirm token",
+				map[string]interface{}{
+					"game_id":       gameID,
+					"confirm_token": deletion.ConfirmToken,
+					"scores":        deletion.Scores,
+					"players":       deletion.Players,
+					"archives":      deletion.Archives,
+				}))
+			return
+		}
+	}
+	if errors.Is(err, leaderboard.ErrGameNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "Game not found",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete game", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete the game",
+			map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action: audit.ActionGameDeleted,
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"scores":   deletion.Scores,
+			"players":  deletion.Players,
+			"archives": deletion.Archives,
+			"keys":     deletion.Keys,
+		},
+	})
+
+	c.JSON(http.StatusOK, deletion)
+}
+
+// ListArchives handles GET /api/v1/admin/games/:gameId/archives
+// @Summary List a game's archives
+// @Description Lists what each reset of the game archived, newest first.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameArchivesResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to read archives"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/archives [get]
+func (h *AdminHandler) ListArchives(c *gin.Context) {
+	gameID, ok := archiveGameID(c)
+	if !ok {
+		return
+	}
+
+	archives, err := h.service.Archives(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to read archives", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to read archives"))
+		return
+	}
+
+	c.JSON(http.StatusOK, archives)
+}
+
+// GetArchive handles GET /api/v1/admin/games/:gameId/archives/:archiveId
+// @Summary Get an archive
+// @Description Returns the leaderboard, high scores and complete score history a reset archived.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param archiveId path string true "Archive ID" maxlength(64)
+// @Success 200 {object} models.GameArchive
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "Archive not found"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to read the archive"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/archives/{archiveId} [get]
+func (h *AdminHandler) GetArchive(c *gin.Context) {
+	gameID, ok := archiveGameID(c)
+	if !ok {
+		return
+	}
+	archiveID := c.Param("archiveId")
+
+	archive, err := h.service.Archive(c.Request.Context(), gameID, archiveID)
+	if errors.Is(err, models.ErrArchiveNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeArchiveNotFound, "Archive not found",
+			map[string]interface{}{"game_id": gameID, "archive_id": archiveID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to read archive", "game_id", gameID, "archive_id", archiveID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to read the archive"))
+		return
+	}
+
+	c.JSON(http.StatusOK, archive)
+}
+
+// archiveGameID reads and checks the game an archive route names
+func archiveGameID(c *gin.Context) (string, bool) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return "", false
+	}
+	return gameID, true
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"rawboard/internal/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log query limits
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// GetAuditLog handles GET /api/v1/admin/audit
+// @Summary Search the audit log
+// @Description Lists audit entries of the caller's tenant, newest first: admin changes, and every authenticated write, score submissions included, with its caller, route, status and a summary of its payload. Secrets in payloads are redacted. Every filter is optional.
+// @Tags admin
+// @Param game_id query string false "Only this game"
+// @Param actor query string false "Only this caller, such as api_key:<key ID> or admin:api"
+// @Param action query string false "Only this action, such as score.deleted or api.write"
+// @Param method query string false "Only this HTTP method"
+// @Param route query string false "Only this route pattern, such as /api/v1/games/:gameId/scores"
+// @Param from query string false "Only entries at or after (RFC 3339)"
+// @Param to query string false "Only entries at or before (RFC 3339)"
+// @Param limit query integer false "Maximum entries to return, default 100, at most 1000"
+// @Success 200 {object} handlers.AuditLogResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid filter"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to read the audit log"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/audit [get]
+func (h *AdminHandler) GetAuditLog(c *gin.Context) {
+	filter := audit.Filter{
+		GameID: c.Query("game_id"),
+		Actor:  c.Query("actor"),
+		Action: c.Query("action"),
+		Method: c.Query("method"),
+		Route:  c.Query("route"),
+		Limit:  defaultAuditLimit,
+	}
+
+	for field, bound := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		if raw := c.Query(field); raw != "" {
+			parsed, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, field, raw, "RFC 3339 timestamp"))
+				return
+			}
+			*bound = parsed
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "to", c.Query("to"), "not before from"))
+		return
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxAuditLimit {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "limit", raw, "integer between 1 and 1000"))
+			return
+		}
+		filter.Limit = limit
+	}
+
+	entries, err := h.audit.Query(c.Request.Context(), filter)
+	if err != nil {
+		requestLogger(c).Error("failed to read audit log", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to read the audit log"))
+		return
+	}
+
+	c.JSON(http.StatusOK, AuditLogResponse{Entries: entries})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetBlocklist handles GET /api/v1/admin/blocklist
+// @Summary Get the blocked initials
+// @Tags blocklist
+// @Success 200 {object} models.BlocklistResponse
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/blocklist [get]
+func (h *AdminHandler) GetBlocklist(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Blocklist(c.Request.Context()))
+}
+
+// BlockInitials handles PUT /api/v1/admin/blocklist/:initials
+// @Summary Block initials
+// @Tags blocklist
+// @Param initials path string true "Player initials"
+// @Success 200 {object} models.BlocklistResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid initials"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to block initials"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/blocklist/{initials} [put]
+func (h *AdminHandler) BlockInitials(c *gin.Context) {
+	initials, ok := blocklistInitials(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.BlockInitials(c.Request.Context(), initials); err != nil {
+		requestLogger(c).Error("failed to block initials", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to block initials"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionInitialsBlocked,
+		Details: map[string]interface{}{"initials": initials},
+	})
+
+	c.JSON(http.StatusOK, h.service.Blocklist(c.Request.Context()))
+}
+
+// UnblockInitials handles DELETE /api/v1/admin/blocklist/:initials
+// @Summary Unblock initials
+// @Tags blocklist
+// @Param initials path string true "Player initials"
+// @Success 200 {object} models.BlocklistResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid initials"
+// @Failure 404 {object} handlers.StandardErrorResponse "Initials are not on the managed blocklist"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/blocklist/{initials} [delete]
+func (h *AdminHandler) UnblockInitials(c *gin.Context) {
+	initials, ok := blocklistInitials(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.UnblockInitials(c.Request.Context(), initials)
+	if errors.Is(err, leaderboard.ErrNotBlocked) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Initials are not on the managed blocklist",
+			map[string]interface{}{
+				"initials": initials,
+				"message":  "Built-in and RAWBOARD_BLOCKED_INITIALS entries can't be removed at runtime",
+			}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to unblock initials", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to unblock initials"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionInitialsUnblocked,
+		Details: map[string]interface{}{"initials": initials},
+	})
+
+	c.JSON(http.StatusOK, h.service.Blocklist(c.Request.Context()))
+}
+
+// blocklistInitials validates the :initials parameter, writing the error response
+// and returning false if it isn't three characters
+func blocklistInitials(c *gin.Context) (string, bool) {
+	initials := models.NormalizeInitials(c.Param("initials"))
+	if len(initials) != 3 || strings.Contains(initials, " ") {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"initials", initials, "exactly 3 characters with no spaces"))
+		return "", false
+	}
+	return initials, true
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/bootstrap"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Bootstrap handles PUT /api/v1/admin/bootstrap
+// @Summary Reconcile games, keys and blocklist with a declarative document
+// @Description Requires the master key. Registers listed games and applies their settings, makes the active API keys exactly those listed (matched by name), and makes the managed blocklist exactly the listed initials. Omitted sections are left alone and unlisted games are never deleted. Applying the same document again changes nothing, so it's safe to run from Terraform or CI. Keys declared without a secret are created once and their secrets returned only then; declare a secret to keep it in your own secret store.
+// @Tags admin
+// @Param dry_run query boolean false "Report the changes without making them"
+// @Param request body models.BootstrapDocument true "Desired state"
+// @Success 200 {object} models.BootstrapResult
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid document"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed partway; re-apply the document"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/bootstrap [put]
+func (h *AdminHandler) Bootstrap(c *gin.Context) {
+	// Reject unknown sections rather than silently ignoring part of the desired state
+	var doc models.BootstrapDocument
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	reconciler := bootstrap.NewReconciler(h.service, h.keys)
+	result, err := reconciler.Reconcile(c.Request.Context(), &doc, dryRun)
+
+	var invalid *bootstrap.ValidationError
+	if errors.As(err, &invalid) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, invalid.Field, invalid.Value, invalid.Expected))
+		return
+	}
+	if result != nil && !dryRun && len(result.Changes) > 0 {
+		h.recordAudit(c, models.AuditEntry{
+			Action:  audit.ActionBootstrapApplied,
+			Details: map[string]interface{}{"changes": result.Changes},
+		})
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to apply bootstrap document", "error", err)
+		details := map[string]interface{}{}
+		if result != nil {
+			details["applied"] = result.Changes
+		}
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to apply bootstrap document", details))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
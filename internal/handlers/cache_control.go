@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache-Control policies for API responses
+const (
+	// leaderboardCacheControl lets clients and proxies reuse public game reads for a few
+	// seconds, then revalidate them
+	leaderboardCacheControl = "public, max-age=5"
+
+	// badgeCacheControl lets displays keep achievement badges for a day; their URLs
+	// change when they're replaced
+	badgeCacheControl = "public, max-age=86400"
+
+	// privateCacheControl keeps admin and authenticated responses out of every cache
+	privateCacheControl = "no-store"
+)
+
+// CacheControl sets a default Cache-Control header on API responses, which handlers can
+// replace: no-store for the admin API and anything requested with an API key, and a few
+// seconds of reuse for public reads of games, such as leaderboards and player stats
+func CacheControl() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		switch {
+		case route == "":
+		case strings.HasPrefix(route, "/api/v1/admin") || hasCredentials(c.Request):
+			c.Header("Cache-Control", privateCacheControl)
+		case c.Request.Method == http.MethodGet && strings.HasPrefix(route, "/api/v1/games/"):
+			c.Header("Cache-Control", leaderboardCacheControl)
+		}
+		c.Next()
+	}
+}
+
+// hasCredentials reports whether a request carries an API key, whose response may
+// depend on it
+func hasCredentials(r *http.Request) bool {
+	return r.Header.Get("X-API-Key") != "" || r.Header.Get("Authorization") != ""
+}
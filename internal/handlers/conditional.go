@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeConditionalJSON writes a JSON body with an ETag of its content and, when
+// modified is known, a Last-Modified time, answering 304 Not Modified when the
+// request's If-None-Match or If-Modified-Since shows the client already has it
+func writeConditionalJSON(c *gin.Context, body []byte, modified time.Time, cacheControl string) {
+	checksum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(checksum[:8]) + `"`
+
+	c.Header("Cache-Control", cacheControl)
+	c.Header("ETag", etag)
+	if !modified.IsZero() {
+		c.Header("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	if notModified(c.Request, etag, modified) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// notModified reports whether a request's validators match the current representation.
+// If-None-Match takes precedence over If-Modified-Since, as in RFC 9110.
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+	// Last-Modified only has whole seconds
+	return !modified.Truncate(time.Second).After(since)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+	"rawboard/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DevHandler serves development conveniences for servers running on the in-memory
+// database
+type DevHandler struct {
+	service *leaderboard.Service
+	wipe    func() // Empties the in-memory database
+}
+
+// NewDevHandler creates a new development handler
+func NewDevHandler(service *leaderboard.Service, wipe func()) *DevHandler {
+	return &DevHandler{service: service, wipe: wipe}
+}
+
+// Reset handles POST /api/v1/dev/reset
+// @Summary Wipe the development database
+// @Description Only served on the in-memory database (DATABASE_BACKEND=memory). Removes every game, score, key and setting of every tenant, as if the server had restarted, then with seed true submits demo scores for a few classic games.
+// @Tags dev
+// @Param request body handlers.DevResetRequest false "Whether to load demo data"
+// @Success 200 {object} models.DevReset
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to load demo data"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Not the master API key"
+// @Router /api/v1/dev/reset [post]
+func (h *DevHandler) Reset(c *gin.Context) {
+	var req DevResetRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+				ErrorCodeInvalidRequest, "Invalid request format",
+				map[string]interface{}{"validation_error": err.Error()}))
+			return
+		}
+	}
+
+	h.wipe()
+	h.service.ClearCaches()
+	requestLogger(c).Warn("development database wiped", "seed", req.Seed)
+
+	if !req.Seed {
+		c.JSON(http.StatusOK, models.DevReset{})
+		return
+	}
+	seeded, err := h.service.SeedDemo(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to seed demo data", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to load demo data"))
+		return
+	}
+	c.JSON(http.StatusOK, seeded)
+}
+
+// WebhookLogHandler serves the development log of webhook delivery attempts
+type WebhookLogHandler struct {
+	log *webhooks.DeliveryLog
+}
+
+// NewWebhookLogHandler creates a new webhook log handler
+func NewWebhookLogHandler(log *webhooks.DeliveryLog) *WebhookLogHandler {
+	return &WebhookLogHandler{log: log}
+}
+
+// WebhookLog handles GET /api/v1/dev/webhook-log
+// @Summary Show recent webhook deliveries
+// @Description Only served in development (ENVIRONMENT=development). Lists the server's most recent webhook delivery attempts, newest first, with the headers and body sent and the receiver's status, headers and body, so an integration can be checked without a public receiver. Retries and test deliveries are included. Protobuf bodies are base64. Requires the admin:read scope; keys scoped to some games must ask for one of them with game_id.
+// @Tags dev
+// @Param game_id query string false "Only this game's deliveries" maxlength(50)
+// @Param limit query integer false "Maximum deliveries to return, default all kept"
+// @Success 200 {object} handlers.WebhookLogResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid limit"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/dev/webhook-log [get]
+func (h *WebhookLogHandler) WebhookLog(c *gin.Context) {
+	gameID := c.Query("game_id")
+	p := principal(c)
+	if p != nil && !p.HasScope(models.ScopeAdminRead) {
+		c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+			ErrorCodeInsufficientScope, "API key lacks the required scope",
+			map[string]interface{}{"required_scope": models.ScopeAdminRead}))
+		return
+	}
+	if p != nil && !p.CanAccessGame(gameID) {
+		c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+			ErrorCodeInsufficientScope, "API key is not scoped to this game",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"limit", limitStr, "positive integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	tenant := tenants.FromContext(c.Request.Context())
+	c.JSON(http.StatusOK, WebhookLogResponse{Deliveries: h.log.Recent(tenant, gameID, limit)})
+}
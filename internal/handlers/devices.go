@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/devices"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeviceHandler enrolls cabinets and manages their devices
+type DeviceHandler struct {
+	service *devices.Service
+	audit   *audit.Log
+}
+
+// NewDeviceHandler creates a new device handler
+func NewDeviceHandler(service *devices.Service, auditLog *audit.Log) *DeviceHandler {
+	return &DeviceHandler{service: service, audit: auditLog}
+}
+
+// EnrollDevice handles POST /api/v1/devices/enroll
+// @Summary Enroll a cabinet
+// @Description Redeems a one-time enrollment code for the device's own API key, scoped to submitting scores for its games, and its config: the mapping from the names the cabinet uses to game IDs, and the endpoints to use them with. The key is only returned here, so the cabinet must store it. Cabinets of a tenant enroll under /api/v1/tenants/{tenantId}/devices/enroll. Rate limited per client IP.
+// @Tags devices
+// @Param request body handlers.EnrollDeviceRequest true "Enrollment code"
+// @Success 201 {object} models.EnrolledDevice
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request"
+// @Failure 401 {object} handlers.StandardErrorResponse "Invalid, used or expired enrollment code"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many attempts"
+// @Router /api/v1/devices/enroll [post]
+func (h *DeviceHandler) EnrollDevice(c *gin.Context) {
+	var req EnrollDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	ctx := c.Request.Context()
+	enrolled, err := h.service.Enroll(ctx, req.Code)
+	if errors.Is(err, devices.ErrInvalidCode) {
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodeInvalidEnrollmentCode, "Invalid or expired enrollment code"))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to enroll device", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to enroll device"))
+		return
+	}
+
+	// The device has no principal yet, so it's recorded as itself
+	if err := h.audit.Record(ctx, models.AuditEntry{
+		Action:  audit.ActionDeviceEnrolled,
+		Actor:   "device:" + enrolled.Config.DeviceID,
+		Details: map[string]interface{}{"device_id": enrolled.Config.DeviceID, "address": c.ClientIP()},
+	}); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", audit.ActionDeviceEnrolled, "error", err)
+	}
+
+	c.JSON(http.StatusCreated, enrolled)
+}
+
+// ListDevices handles GET /api/v1/admin/devices
+// @Summary List devices
+// @Description Every registered cabinet with its status: pending until it enrolls, active while its key works, or revoked. Requires the master key.
+// @Tags devices
+// @Success 200 {object} handlers.DeviceListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list devices"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/devices [get]
+func (h *DeviceHandler) ListDevices(c *gin.Context) {
+	list, err := h.service.List(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list devices", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list devices"))
+		return
+	}
+
+	c.JSON(http.StatusOK, DeviceListResponse{Devices: list})
+}
+
+// CreateDevice handles POST /api/v1/admin/devices
+// @Summary Register a device for enrollment
+// @Description Returns the one-time code the cabinet enrolls with, valid for 24 hours; it is only shown here. Registering a pending or revoked device again replaces it and its code. Requires the master key, since enrolling issues a key.
+// @Tags devices
+// @Param request body handlers.DeviceRequest true "Device ID, name and game mapping"
+// @Success 201 {object} models.DeviceEnrollmentCode
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid device"
+// @Failure 409 {object} handlers.StandardErrorResponse "The device is enrolled and must be revoked first"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/devices [post]
+func (h *DeviceHandler) CreateDevice(c *gin.Context) {
+	var req DeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+	if len(req.DeviceID) > 50 || len(req.DeviceID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"device_id", req.DeviceID, "length between 1 and 50 characters"))
+		return
+	}
+
+	device := models.Device{DeviceID: req.DeviceID, Name: req.Name, Games: req.Games}
+	if err := device.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, err.Error(),
+			map[string]interface{}{"device_id": req.DeviceID}))
+		return
+	}
+
+	code, err := h.service.Create(c.Request.Context(), device)
+	if errors.Is(err, devices.ErrEnrolled) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeDeviceEnrolled, err.Error(),
+			map[string]interface{}{"device_id": req.DeviceID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to create device", "device_id", req.DeviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to create device"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionDeviceCreated,
+		Details: map[string]interface{}{"device_id": req.DeviceID, "game_ids": device.GameIDs()},
+	})
+
+	c.JSON(http.StatusCreated, code)
+}
+
+// RevokeDevice handles DELETE /api/v1/admin/devices/:deviceId
+// @Summary Revoke a device
+// @Description Revokes the device's key, or its pending enrollment code. The device stays listed as revoked and can be registered again. Requires the master key.
+// @Tags devices
+// @Param deviceId path string true "Device ID"
+// @Success 200 {object} models.Device
+// @Failure 404 {object} handlers.StandardErrorResponse "Device not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/devices/{deviceId} [delete]
+func (h *DeviceHandler) RevokeDevice(c *gin.Context) {
+	deviceID := c.Param("deviceId")
+
+	device, err := h.service.Revoke(c.Request.Context(), deviceID)
+	if errors.Is(err, devices.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeDeviceNotFound, "Device not found",
+			map[string]interface{}{"device_id": deviceID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to revoke device", "device_id", deviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to revoke device"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionDeviceRevoked,
+		Details: map[string]interface{}{"device_id": deviceID, "key_id": device.KeyID},
+	})
+
+	c.JSON(http.StatusOK, device)
+}
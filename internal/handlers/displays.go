@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/displays"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxDeviceIDLength bounds the device IDs display clients register with
+const maxDeviceIDLength = 64
+
+// DisplayHandler serves and manages the attract-mode rotations of venue displays, and
+// tracks the devices showing them
+type DisplayHandler struct {
+	store   *displays.Store
+	monitor *displays.Monitor
+	audit   *audit.Log
+}
+
+// NewDisplayHandler creates a new display handler
+func NewDisplayHandler(store *displays.Store, monitor *displays.Monitor, auditLog *audit.Log) *DisplayHandler {
+	return &DisplayHandler{store: store, monitor: monitor, audit: auditLog}
+}
+
+// GetRotation handles GET /api/v1/displays/:displayId/rotation
+// @Summary Get a display's rotation
+// @Description The ordered playlist a venue display loops through, with how long to show each slide and the path its content is fetched from. CORS-open and never cached, so a display that polls it picks up changes; the updated time changes whenever the rotation is reconfigured.
+// @Tags displays
+// @Param displayId path string true "Display ID"
+// @Param device_id query string false "Registers the fetch as the device's last"
+// @Success 200 {object} models.DisplayRotation
+// @Failure 404 {object} handlers.StandardErrorResponse "Display not found"
+// @Router /api/v1/displays/{displayId}/rotation [get]
+func (h *DisplayHandler) GetRotation(c *gin.Context) {
+	displayID := c.Param("displayId")
+
+	display, err := h.store.Get(c.Request.Context(), displayID)
+	if errors.Is(err, displays.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeDisplayNotFound, "Display not found",
+			map[string]interface{}{"display_id": displayID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to get display", "display_id", displayID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get display"))
+		return
+	}
+
+	if deviceID := c.Query("device_id"); deviceID != "" && len(deviceID) <= maxDeviceIDLength {
+		now := time.Now().UTC()
+		if _, err := h.store.TouchDevice(c.Request.Context(), displayID, deviceID, now, func(device *models.DisplayDevice) {
+			device.LastFetch = &now
+			device.Address = c.ClientIP()
+		}); err != nil {
+			requestLogger(c).Warn("failed to record display fetch", "display_id", displayID, "device_id", deviceID, "error", err)
+		}
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.JSON(http.StatusOK, display.Rotation())
+}
+
+// Heartbeat handles POST /api/v1/displays/:displayId/heartbeat
+// @Summary Send a display device heartbeat
+// @Description Registers the device with the display on its first heartbeat and records what it is showing. Devices that stop sending heartbeats and fetching the rotation are reported dark. The response says how often to send heartbeats and when the rotation last changed. CORS-open.
+// @Tags displays
+// @Param displayId path string true "Display ID"
+// @Param request body handlers.DisplayHeartbeatRequest true "Device and what it is showing"
+// @Success 200 {object} models.DisplayHeartbeat
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid heartbeat"
+// @Failure 404 {object} handlers.StandardErrorResponse "Display not found"
+// @Failure 409 {object} handlers.StandardErrorResponse "The display already has the most devices allowed"
+// @Router /api/v1/displays/{displayId}/heartbeat [post]
+func (h *DisplayHandler) Heartbeat(c *gin.Context) {
+	displayID := c.Param("displayId")
+
+	var req DisplayHeartbeatRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	ctx := c.Request.Context()
+	display, err := h.store.Get(ctx, displayID)
+	if errors.Is(err, displays.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeDisplayNotFound, "Display not found",
+			map[string]interface{}{"display_id": displayID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to get display", "display_id", displayID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get display"))
+		return
+	}
+
+	now := time.Now().UTC()
+	_, err = h.store.TouchDevice(ctx, displayID, req.DeviceID, now, func(device *models.DisplayDevice) {
+		device.LastHeartbeat = &now
+		device.Address = c.ClientIP()
+		device.Showing = req.Showing
+		if req.Name != "" {
+			device.Name = req.Name
+		}
+		if req.Version != "" {
+			device.Version = req.Version
+		}
+	})
+	if errors.Is(err, displays.ErrTooManyDevices) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeDisplayDeviceLimit, err.Error(),
+			map[string]interface{}{"display_id": displayID, "max_devices": models.MaxDisplayDevices}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to record heartbeat", "display_id", displayID, "device_id", req.DeviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to record heartbeat"))
+		return
+	}
+
+	c.JSON(http.StatusOK, models.DisplayHeartbeat{
+		DisplayID:        displayID,
+		DeviceID:         req.DeviceID,
+		Updated:          display.Updated,
+		HeartbeatSeconds: int(h.monitor.HeartbeatInterval().Seconds()),
+	})
+}
+
+// ListDisplays handles GET /api/v1/admin/displays
+// @Summary List displays
+// @Tags displays
+// @Success 200 {object} handlers.DisplayListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list displays"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/displays [get]
+func (h *DisplayHandler) ListDisplays(c *gin.Context) {
+	list, err := h.store.List(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list displays", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list displays"))
+		return
+	}
+
+	c.JSON(http.StatusOK, DisplayListResponse{Displays: list})
+}
+
+// PutDisplay handles PUT /api/v1/admin/displays/:displayId
+// @Summary Configure a display's rotation
+// @Description Creates the display or replaces its rotation. Each slide shows a game's leaderboard (the default view) or public summary for 3 to 3600 seconds.
+// @Tags displays
+// @Param displayId path string true "Display ID"
+// @Param request body handlers.DisplayRequest true "Name and slides"
+// @Success 200 {object} models.Display
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid display ID or rotation"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to save the display"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/displays/{displayId} [put]
+func (h *DisplayHandler) PutDisplay(c *gin.Context) {
+	displayID := c.Param("displayId")
+	if len(displayID) > 50 || len(displayID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"displayId", displayID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req DisplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	display := models.Display{DisplayID: displayID, Name: req.Name, Slides: req.Slides}
+	if err := display.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, err.Error(),
+			map[string]interface{}{"display_id": displayID}))
+		return
+	}
+
+	saved, err := h.store.Put(c.Request.Context(), display)
+	if err != nil {
+		requestLogger(c).Error("failed to save display", "display_id", displayID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to save display"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionDisplayUpdated,
+		Details: map[string]interface{}{"display_id": displayID, "slides": len(saved.Slides)},
+	})
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// ListDisplayStatus handles GET /api/v1/admin/displays/status
+// @Summary List which display devices are online
+// @Description Every display with the devices showing it, what each last reported showing and when it last sent a heartbeat or fetched its rotation.
+// @Tags displays
+// @Success 200 {object} handlers.DisplayStatusResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list displays"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/displays/status [get]
+func (h *DisplayHandler) ListDisplayStatus(c *gin.Context) {
+	ctx := c.Request.Context()
+	list, err := h.store.List(ctx)
+	if err != nil {
+		requestLogger(c).Error("failed to list displays", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list displays"))
+		return
+	}
+
+	response := DisplayStatusResponse{Displays: make([]models.DisplayStatus, 0, len(list))}
+	for i := range list {
+		status, err := h.monitor.Status(ctx, &list[i])
+		if err != nil {
+			requestLogger(c).Error("failed to get display status", "display_id", list[i].DisplayID, "error", err)
+			c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+				ErrorCodeInternalError, "Failed to get display status"))
+			return
+		}
+		response.Displays = append(response.Displays, *status)
+		response.Online += status.Online
+		response.Offline += status.Offline
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RemoveDisplayDevice handles DELETE /api/v1/admin/displays/:displayId/devices/:deviceId
+// @Summary Unregister a display device
+// @Description For retired screens, which would otherwise be reported dark. A device that sends another heartbeat registers again.
+// @Tags displays
+// @Param displayId path string true "Display ID"
+// @Param deviceId path string true "Device ID"
+// @Success 204 "Device unregistered"
+// @Failure 404 {object} handlers.StandardErrorResponse "Device not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/displays/{displayId}/devices/{deviceId} [delete]
+func (h *DisplayHandler) RemoveDisplayDevice(c *gin.Context) {
+	displayID, deviceID := c.Param("displayId"), c.Param("deviceId")
+
+	err := h.store.RemoveDevice(c.Request.Context(), displayID, deviceID)
+	if errors.Is(err, displays.ErrDeviceNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeDisplayNotFound, "Display device not found",
+			map[string]interface{}{"display_id": displayID, "device_id": deviceID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to remove display device", "display_id", displayID, "device_id", deviceID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to remove display device"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionDisplayDeviceRemoved,
+		Details: map[string]interface{}{"display_id": displayID, "device_id": deviceID},
+	})
+
+	c.Status(http.StatusNoContent)
+}
+
+// DeleteDisplay handles DELETE /api/v1/admin/displays/:displayId
+// @Summary Delete a display
+// @Tags displays
+// @Param displayId path string true "Display ID"
+// @Success 200 {object} models.Display "The deleted display"
+// @Failure 404 {object} handlers.StandardErrorResponse "Display not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/displays/{displayId} [delete]
+func (h *DisplayHandler) DeleteDisplay(c *gin.Context) {
+	displayID := c.Param("displayId")
+
+	display, err := h.store.Delete(c.Request.Context(), displayID)
+	if errors.Is(err, displays.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeDisplayNotFound, "Display not found",
+			map[string]interface{}{"display_id": displayID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete display", "display_id", displayID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete display"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionDisplayDeleted,
+		Details: map[string]interface{}{"display_id": displayID},
+	})
+
+	c.JSON(http.StatusOK, display)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FlagScore handles POST /api/v1/games/:gameId/scores/:scoreId/flag
+// @Summary Flag a suspicious score
+// @Description Reports a score as suspicious with a reason, opening a dispute operators review at GET /api/v1/admin/games/{gameId}/disputes, or adding to the score's open one. Scores are named by the id leaderboard and history entries carry; scores stored before ids were recorded are named by the first 16 hex digits of the SHA-256 of initials|timestamp, the timestamp in UTC RFC 3339 with nanoseconds. Scores an operator has reviewed and kept can't be flagged again. No API key is needed, so players can flag scores; rate limited per client IP.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param scoreId path string true "Score ID" maxlength(64)
+// @Param request body handlers.FlagScoreRequest true "Why the score is suspicious"
+// @Success 200 {object} models.ScoreDispute
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, score ID or reason"
+// @Failure 404 {object} handlers.StandardErrorResponse "No such score in the game's history"
+// @Failure 409 {object} handlers.StandardErrorResponse "The score was already reviewed and kept"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many requests, or too many open disputes for the game"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to save the flag"
+// @Router /api/v1/games/{gameId}/scores/{scoreId}/flag [post]
+func (h *PlayerHandler) FlagScore(c *gin.Context) {
+	gameID, scoreID, ok := disputeTarget(c)
+	if !ok {
+		return
+	}
+
+	var req FlagScoreRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	dispute, err := h.service.FlagScore(c.Request.Context(), gameID, scoreID, req.Reason)
+	switch {
+	case errors.Is(err, models.ErrScoreNotFound):
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeScoreNotFound, "No matching score found",
+			map[string]interface{}{"game_id": gameID, "score_id": scoreID}))
+		return
+	case errors.Is(err, models.ErrDisputeClosed):
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeDisputeClosed, "The score was already reviewed and kept",
+			map[string]interface{}{"game_id": gameID, "score_id": scoreID}))
+		return
+	case errors.Is(err, models.ErrTooManyDisputes):
+		c.JSON(http.StatusTooManyRequests, NewStandardErrorResponse(c,
+			ErrorCodeRateLimitExceeded, "The game has too many open disputes, try again later",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	case err != nil:
+		requestLogger(c).Error("failed to flag score", "game_id", gameID, "score_id", scoreID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to save the flag"))
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// ListDisputes handles GET /api/v1/admin/games/:gameId/disputes
+// @Summary List flagged scores awaiting review
+// @Description Lists the game's open disputes, most flagged first, with each score as it is in history and the latest reasons given. Resolve one with POST /api/v1/admin/games/{gameId}/disputes/{scoreId}/resolve.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.DisputesResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to read disputes"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/disputes [get]
+func (h *AdminHandler) ListDisputes(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	disputes, err := h.service.Disputes(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to read disputes", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to read disputes"))
+		return
+	}
+
+	c.JSON(http.StatusOK, disputes)
+}
+
+// ResolveDispute handles POST /api/v1/admin/games/:gameId/disputes/:scoreId/resolve
+// @Summary Resolve a disputed score
+// @Description Closes a dispute: keep leaves the score standing and stops it being flagged again, remove deletes it as DELETE /api/v1/games/{gameId}/scores does, returning the deletion's tombstone_id so it can be undone within the undo window. A dispute over a score that has since been deleted can be closed with keep.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param scoreId path string true "Score ID" maxlength(64)
+// @Param request body handlers.ResolveDisputeRequest true "Decision"
+// @Success 200 {object} models.DisputeResolution
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, score ID or action"
+// @Failure 404 {object} handlers.StandardErrorResponse "No open dispute for the score, or the score to remove is gone"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to resolve the dispute"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/disputes/{scoreId}/resolve [post]
+func (h *AdminHandler) ResolveDispute(c *gin.Context) {
+	gameID, scoreID, ok := disputeTarget(c)
+	if !ok {
+		return
+	}
+
+	var req ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	result, err := h.service.ResolveDispute(c.Request.Context(), gameID, scoreID, req.Action)
+	switch {
+	case errors.Is(err, models.ErrDisputeNotFound):
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeDisputeNotFound, "No open dispute for the score",
+			map[string]interface{}{"game_id": gameID, "score_id": scoreID}))
+		return
+	case errors.Is(err, models.ErrScoreNotFound):
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeScoreNotFound, "The disputed score is no longer in history, close the dispute with keep",
+			map[string]interface{}{"game_id": gameID, "score_id": scoreID}))
+		return
+	case err != nil:
+		requestLogger(c).Error("failed to resolve dispute", "game_id", gameID, "score_id", scoreID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to resolve the dispute"))
+		return
+	}
+
+	details := map[string]interface{}{"score_id": scoreID, "action": result.Action, "flags": result.Flags}
+	if result.Deletion != nil {
+		details["initials"] = result.Deletion.Initials
+		details["tombstone_id"] = result.Deletion.TombstoneID
+	}
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionDisputeResolved,
+		GameID:  gameID,
+		Details: details,
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// disputeTarget reads and checks the game and score a dispute route names
+func disputeTarget(c *gin.Context) (gameID, scoreID string, ok bool) {
+	gameID = c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return "", "", false
+	}
+	scoreID = c.Param("scoreId")
+	if len(scoreID) > 64 || len(scoreID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"scoreId", scoreID, "length between 1 and 64 characters"))
+		return "", "", false
+	}
+	return gameID, scoreID, true
+}
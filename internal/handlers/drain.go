@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+	"rawboard/internal/selfcheck"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DrainHandler takes the instance out of and back into load balancer rotation
+type DrainHandler struct {
+	drain *selfcheck.Drain
+	audit *audit.Log
+}
+
+// NewDrainHandler creates a new drain handler
+func NewDrainHandler(drain *selfcheck.Drain, auditLog *audit.Log) *DrainHandler {
+	return &DrainHandler{drain: drain, audit: auditLog}
+}
+
+// GetDrain handles GET /api/v1/admin/drain
+// @Summary Get whether this instance is draining
+// @Description Draining is per instance: call the instance directly, not through the load balancer.
+// @Tags admin
+// @Success 200 {object} models.DrainStatus
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Requires the master API key"
+// @Router /api/v1/admin/drain [get]
+func (h *DrainHandler) GetDrain(c *gin.Context) {
+	c.JSON(http.StatusOK, h.drain.Status())
+}
+
+// StartDrain handles POST /api/v1/admin/drain
+// @Summary Drain this instance ahead of maintenance
+// @Description Fails /health/ready with status draining so load balancers stop sending the instance new requests, while /health/live stays healthy and requests it still gets are served.
+// @Description Draining is per instance and held in memory: call the instance directly, not through the load balancer, and a restart returns it to service. Draining an instance already draining keeps when it started.
+// @Tags admin
+// @Param request body handlers.DrainRequest false "Why the instance is drained"
+// @Success 200 {object} models.DrainStatus
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Requires the master API key"
+// @Router /api/v1/admin/drain [post]
+func (h *DrainHandler) StartDrain(c *gin.Context) {
+	var req DrainRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+				ErrorCodeInvalidRequest, "Invalid request format",
+				map[string]interface{}{"validation_error": err.Error()}))
+			return
+		}
+	}
+
+	status := h.drain.Start(req.Reason)
+	requestLogger(c).Warn("instance draining", "reason", status.Reason)
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionDrainStarted,
+		Details: map[string]interface{}{"reason": status.Reason},
+	})
+
+	c.JSON(http.StatusOK, status)
+}
+
+// StopDrain handles DELETE /api/v1/admin/drain
+// @Summary Return this instance to service
+// @Description /health/ready reports the instance's dependencies again. An instance draining to shut down can't be returned.
+// @Tags admin
+// @Success 200 {object} models.DrainStatus
+// @Failure 409 {object} handlers.StandardErrorResponse "The instance is shutting down"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Requires the master API key"
+// @Router /api/v1/admin/drain [delete]
+func (h *DrainHandler) StopDrain(c *gin.Context) {
+	status, err := h.drain.Stop()
+	if errors.Is(err, selfcheck.ErrShuttingDown) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeShuttingDown, "The instance is shutting down"))
+		return
+	}
+
+	requestLogger(c).Info("instance returned to service")
+	recordAudit(c, h.audit, models.AuditEntry{Action: audit.ActionDrainStopped})
+
+	c.JSON(http.StatusOK, status)
+}
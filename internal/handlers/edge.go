@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/edge"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EdgeHandler lets operators of an edge instance look into and replay the submissions
+// the central instance refused
+type EdgeHandler struct {
+	mirror *edge.Mirror
+	audit  *audit.Log
+}
+
+// NewEdgeHandler creates a new edge handler
+func NewEdgeHandler(mirror *edge.Mirror, auditLog *audit.Log) *EdgeHandler {
+	return &EdgeHandler{mirror: mirror, audit: auditLog}
+}
+
+// GetDeadLetters handles GET /api/v1/admin/edge/dead-letters
+// @Summary List the submissions the central instance refused
+// @Description Only on edge instances. Submissions the central instance refuses outright, such as for its anti-cheat rules, are kept here, oldest first, rather than dropped; status and error hold its answer. The PIN and signature they were sent with are kept for replays but not listed. Dead letters of every tenant are listed.
+// @Tags admin
+// @Success 200 {object} models.EdgeDeadLetters
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Requires the master API key"
+// @Router /api/v1/admin/edge/dead-letters [get]
+func (h *EdgeHandler) GetDeadLetters(c *gin.Context) {
+	letters, err := h.mirror.DeadLetters(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list edge dead letters", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list dead letters"))
+		return
+	}
+	c.JSON(http.StatusOK, letters)
+}
+
+// ReplayDeadLetters handles POST /api/v1/admin/edge/dead-letters/replay
+// @Summary Forward every refused submission again
+// @Description Only on edge instances. Moves every dead letter back to the outbox, after the submissions already waiting, to be forwarded in the background; those refused again return to the dead letters. Audited.
+// @Tags admin
+// @Success 200 {object} models.EdgeReplay
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Requires the master API key"
+// @Router /api/v1/admin/edge/dead-letters/replay [post]
+func (h *EdgeHandler) ReplayDeadLetters(c *gin.Context) {
+	h.replay(c, "")
+}
+
+// ReplayDeadLetter handles POST /api/v1/admin/edge/dead-letters/:scoreId/replay
+// @Summary Forward a refused submission again
+// @Description Only on edge instances. Moves the dead letter back to the outbox, after the submissions already waiting, to be forwarded in the background; if it is refused again it returns to the dead letters. Audited.
+// @Tags admin
+// @Param scoreId path string true "The dead letter's score_id"
+// @Success 200 {object} models.EdgeReplay
+// @Failure 404 {object} handlers.StandardErrorResponse "No dead letter with that score_id"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Requires the master API key"
+// @Router /api/v1/admin/edge/dead-letters/{scoreId}/replay [post]
+func (h *EdgeHandler) ReplayDeadLetter(c *gin.Context) {
+	h.replay(c, c.Param("scoreId"))
+}
+
+// replay puts the dead letter with scoreID, or every one if it is empty, back in the
+// outbox
+func (h *EdgeHandler) replay(c *gin.Context, scoreID string) {
+	ctx := c.Request.Context()
+	replayed, err := h.mirror.Replay(ctx, scoreID)
+	if errors.Is(err, edge.ErrDeadLetterNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeScoreNotFound, "No dead letter with that score ID",
+			map[string]interface{}{"score_id": scoreID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to replay edge dead letters", "score_id", scoreID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to replay dead letters"))
+		return
+	}
+
+	details := map[string]interface{}{"replayed": replayed}
+	if scoreID != "" {
+		details["score_id"] = scoreID
+	}
+	recordAudit(c, h.audit, models.AuditEntry{Action: audit.ActionEdgeReplayed, Details: details})
+
+	pending, err := h.mirror.Pending(ctx)
+	if err != nil {
+		requestLogger(c).Warn("failed to count edge outbox", "error", err)
+	}
+	c.JSON(http.StatusOK, models.EdgeReplay{Replayed: replayed, Pending: pending})
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"rawboard/internal/inbound"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+// maxInboundEmailSize caps webhook bodies; score emails are tiny but may carry attachments
+const maxInboundEmailSize = 10 << 20
+
+// EmailHandler accepts scores emailed by legacy cabinets through a mail provider's webhook.
+// Rejected emails get 406 so providers don't retry them; failures on our side get 503 so they do.
+type EmailHandler struct {
+	service *leaderboard.Service
+	config  inbound.Config
+}
+
+// NewEmailHandler creates a new email gateway handler
+func NewEmailHandler(service *leaderboard.Service, config inbound.Config) *EmailHandler {
+	return &EmailHandler{service: service, config: config}
+}
+
+// ReceiveMailgun handles POST /api/v1/inbound/email/mailgun
+// @Summary Submit a score by email (Mailgun)
+// @Description Target for a Mailgun route that forwards to this URL. Takes Mailgun's form-encoded post, verified with MAILGUN_SIGNING_KEY. The plain-text body must contain GAME:, INITIALS: and SCORE: lines, and the sender must be in EMAIL_ALLOWED_SENDERS.
+// @Tags email
+// @Success 201 {object} handlers.ScoreSubmissionResponse
+// @Failure 401 {object} handlers.StandardErrorResponse "Invalid Mailgun signature"
+// @Failure 406 {object} handlers.StandardErrorResponse "Email rejected: sender not allowed, or no valid score"
+// @Failure 503 {object} handlers.StandardErrorResponse "Score could not be saved; Mailgun will retry"
+// @Router /api/v1/inbound/email/mailgun [post]
+func (h *EmailHandler) ReceiveMailgun(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundEmailSize)
+	if err := c.Request.ParseMultipartForm(maxInboundEmailSize); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid Mailgun post",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	email, err := inbound.ParseMailgun(c.Request.PostForm, h.config.MailgunSigningKey, time.Now())
+	if errors.Is(err, inbound.ErrInvalidSignature) {
+		requestLogger(c).Warn("rejected email webhook with an invalid signature", "provider", "mailgun")
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodeInvalidSignature, "Invalid Mailgun signature"))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, err.Error()))
+		return
+	}
+
+	h.submit(c, "mailgun", email)
+}
+
+// ReceiveSES handles POST /api/v1/inbound/email/ses
+// @Summary Submit a score by email (Amazon SES)
+// @Description Target for an SNS topic subscription fed by an SES receipt rule's SNS action. The subscription URL must include ?secret=<SES_WEBHOOK_SECRET>. Subscription confirmations are logged with the URL to visit. The email's plain-text body must contain GAME:, INITIALS: and SCORE: lines, and the sender must be in EMAIL_ALLOWED_SENDERS.
+// @Tags email
+// @Param secret query string true "SES_WEBHOOK_SECRET"
+// @Param request body inbound.SNSMessage true "SNS notification wrapping the SES receipt notification"
+// @Success 200 "Subscription confirmation or other SNS message acknowledged"
+// @Success 201 {object} handlers.ScoreSubmissionResponse
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid secret"
+// @Failure 406 {object} handlers.StandardErrorResponse "Email rejected: sender not allowed, or no valid score"
+// @Failure 503 {object} handlers.StandardErrorResponse "Score could not be saved; SNS will retry"
+// @Router /api/v1/inbound/email/ses [post]
+func (h *EmailHandler) ReceiveSES(c *gin.Context) {
+	if subtle.ConstantTimeCompare([]byte(c.Query("secret")), []byte(h.config.SESSecret)) != 1 {
+		requestLogger(c).Warn("rejected email webhook with an invalid secret", "provider", "ses")
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodeInvalidSignature, "Invalid webhook secret"))
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxInboundEmailSize))
+	if err != nil {
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Failed to read SNS message"))
+		return
+	}
+
+	msg, err := inbound.ParseSNS(body)
+	if err != nil {
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, err.Error()))
+		return
+	}
+
+	switch msg.Type {
+	case inbound.SNSNotification:
+	case inbound.SNSSubscriptionConfirmation:
+		requestLogger(c).Warn("confirm the SES email gateway subscription by visiting subscribe_url",
+			"topic_arn", msg.TopicArn, "subscribe_url", msg.SubscribeURL)
+		c.Status(http.StatusOK)
+		return
+	default:
+		c.Status(http.StatusOK)
+		return
+	}
+
+	email, err := inbound.ParseSES(msg.Message)
+	if err != nil {
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, err.Error()))
+		return
+	}
+
+	h.submit(c, "ses", email)
+}
+
+// submit validates an authenticated email's sender and score and submits it
+func (h *EmailHandler) submit(c *gin.Context, provider string, email *inbound.Email) {
+	logger := requestLogger(c).With("provider", provider, "sender", email.From, "subject", email.Subject)
+
+	if !h.config.Allows(email.From) {
+		logger.Warn("rejected score email from a sender not in EMAIL_ALLOWED_SENDERS")
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeSenderNotAllowed, "Sender is not allowed to submit scores",
+			map[string]interface{}{"sender": email.From}))
+		return
+	}
+
+	parsed, err := inbound.ParseScore(email.Body)
+	if err != nil {
+		logger.Warn("rejected score email", "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	if len(parsed.GameID) > 50 {
+		c.JSON(http.StatusNotAcceptable, NewValidationErrorResponse(c,
+			"GAME", parsed.GameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	entry := &models.ScoreEntry{Initials: parsed.Initials, Score: parsed.Score}
+	if err := entry.Validate(); err != nil {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	parsed.GameID = h.service.ResolveGameID(c.Request.Context(), parsed.GameID)
+	err = h.service.SubmitScore(c.Request.Context(), parsed.GameID, entry.Initials, entry.Score)
+	if errors.Is(err, models.ErrBlockedInitials) {
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeBlockedInitials, "These initials are not allowed",
+			map[string]interface{}{"initials": entry.Initials}))
+		return
+	}
+	if errors.Is(err, models.ErrInvalidScore) {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+	if errors.Is(err, leaderboard.ErrClaimRequired) {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeClaimRequired, "This game only accepts claimed initials",
+			map[string]interface{}{"initials": entry.Initials}))
+		return
+	}
+	if errors.Is(err, leaderboard.ErrPINRequired) {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodePINRequired, "These initials are claimed; this game needs their PIN",
+			map[string]interface{}{"initials": entry.Initials}))
+		return
+	}
+	var sunset *models.GameSunsetError
+	if errors.As(err, &sunset) {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeGameSunset, "This game no longer takes submissions",
+			sunsetDetails(sunset)))
+		return
+	}
+	if errors.Is(err, models.ErrSignatureRequired) {
+		// Emails can't carry a signature, so games requiring one can't take scores by email
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeSignatureRequired, "This game only accepts signed submissions"))
+		return
+	}
+	var suspicious *models.SuspiciousScoreError
+	if errors.As(err, &suspicious) {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeSuspiciousScore, "Score rejected by the game's anti-cheat rules",
+			map[string]interface{}{"violations": suspicious.Violations}))
+		return
+	}
+	var rejected *models.SubmissionRejectedError
+	if errors.As(err, &rejected) {
+		logger.Warn("rejected score email", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusNotAcceptable, NewStandardErrorResponse(c,
+			ErrorCodeSubmissionRejected, "Score rejected by a submission hook",
+			map[string]interface{}{"reason": rejected.Reason}))
+		return
+	}
+	if err != nil {
+		logger.Error("score submission by email failed", "game_id", parsed.GameID, "error", err)
+		c.JSON(http.StatusServiceUnavailable, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to save score"))
+		return
+	}
+
+	receipt, err := h.service.IssueReceipt(c.Request.Context(), parsed.GameID, entry.Initials, entry.Score)
+	if err != nil {
+		logger.Warn("failed to issue score receipt", "error", err)
+	}
+	logger.Info("score submitted by email", "game_id", parsed.GameID, "initials", entry.Initials, "score", entry.Score)
+
+	response := ScoreSubmissionResponse{
+		Message:      "Score submitted successfully",
+		Entry:        entry,
+		ReceiptToken: receipt,
+	}
+	if board, err := h.service.GetLeaderboard(c.Request.Context(), parsed.GameID); err == nil {
+		response.Leaderboard = board
+		response.Rank = playerRank(board, entry.Initials)
+	}
+	c.JSON(http.StatusCreated, response)
+}
@@ -3,6 +3,9 @@ package handlers
 import (
 	"time"
 
+	"rawboard/internal/logging"
+
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
@@ -39,10 +42,54 @@ const (
 	ErrorCodeRateLimitExceeded      = "RATE_LIMIT_EXCEEDED"
 	ErrorCodeInternalError          = "INTERNAL_ERROR"
 	ErrorCodeInvalidRequest         = "INVALID_REQUEST"
+	ErrorCodeExportNotFound         = "EXPORT_NOT_FOUND"
+	ErrorCodeRestoreFailed          = "RESTORE_FAILED"
+	ErrorCodeInsufficientScope      = "INSUFFICIENT_SCOPE"
+	ErrorCodeInsufficientRole       = "INSUFFICIENT_ROLE"
+	ErrorCodeAPIKeyNotFound         = "API_KEY_NOT_FOUND"
+	ErrorCodeReceiptNotFound        = "RECEIPT_NOT_FOUND"
+	ErrorCodeScoreNotFound          = "SCORE_NOT_FOUND"
+	ErrorCodeBlockedInitials        = "BLOCKED_INITIALS"
+	ErrorCodeInvalidStreamToken     = "INVALID_STREAM_TOKEN"
+	ErrorCodeInvalidSignature       = "INVALID_SIGNATURE"
+	ErrorCodeSenderNotAllowed       = "SENDER_NOT_ALLOWED"
+	ErrorCodeWebhookNotFound        = "WEBHOOK_NOT_FOUND"
+	ErrorCodeSuspiciousScore        = "SUSPICIOUS_SCORE"
+	ErrorCodeScoringLocked          = "SCORING_LOCKED"
+	ErrorCodeInvalidImport          = "INVALID_IMPORT"
+	ErrorCodeGameLimitExceeded      = "GAME_LIMIT_EXCEEDED"
+	ErrorCodeDisplayNotFound        = "DISPLAY_NOT_FOUND"
+	ErrorCodeDisplayDeviceLimit     = "DISPLAY_DEVICE_LIMIT"
+	ErrorCodeSeasonNotFound         = "SEASON_NOT_FOUND"
+	ErrorCodeSeasonConflict         = "SEASON_CONFLICT"
+	ErrorCodeTournamentNotFound     = "TOURNAMENT_NOT_FOUND"
+	ErrorCodeTenantMismatch         = "TENANT_MISMATCH"
+	ErrorCodeDeviceNotFound         = "DEVICE_NOT_FOUND"
+	ErrorCodeDeviceEnrolled         = "DEVICE_ENROLLED"
+	ErrorCodeInvalidEnrollmentCode  = "INVALID_ENROLLMENT_CODE"
+	ErrorCodeProfileNotFound        = "PROFILE_NOT_FOUND"
+	ErrorCodeProfileExists          = "PROFILE_EXISTS"
+	ErrorCodeWrongPIN               = "WRONG_PIN"
+	ErrorCodeProfileLocked          = "PROFILE_LOCKED"
+	ErrorCodePINRequired            = "PIN_REQUIRED"
+	ErrorCodeClaimRequired          = "CLAIM_REQUIRED"
+	ErrorCodeAchievementNotFound    = "ACHIEVEMENT_NOT_FOUND"
+	ErrorCodeBadgeNotFound          = "BADGE_NOT_FOUND"
+	ErrorCodeShuttingDown           = "SHUTTING_DOWN"
+	ErrorCodeInvalidSession         = "INVALID_SESSION"
+	ErrorCodeInvalidToken           = "INVALID_TOKEN"
+	ErrorCodeGameSunset             = "GAME_SUNSET"
+	ErrorCodeSignatureRequired      = "SIGNATURE_REQUIRED"
+	ErrorCodeSubmissionRejected     = "SUBMISSION_REJECTED"
+	ErrorCodeTombstoneNotFound      = "TOMBSTONE_NOT_FOUND"
+	ErrorCodeDisputeNotFound        = "DISPUTE_NOT_FOUND"
+	ErrorCodeDisputeClosed          = "DISPUTE_CLOSED"
+	ErrorCodeArchiveNotFound        = "ARCHIVE_NOT_FOUND"
+	ErrorCodeConfirmationRequired   = "CONFIRMATION_REQUIRED"
 )
 
-// NewStandardErrorResponse creates a standardized error response
-func NewStandardErrorResponse(code, message string, details ...map[string]interface{}) *StandardErrorResponse {
+// NewStandardErrorResponse creates a standardized error response carrying the request's ID
+func NewStandardErrorResponse(c *gin.Context, code, message string, details ...map[string]interface{}) *StandardErrorResponse {
 	errorDetails := make(map[string]interface{})
 	if len(details) > 0 && details[0] != nil {
 		errorDetails = details[0]
@@ -55,15 +102,15 @@ func NewStandardErrorResponse(code, message string, details ...map[string]interf
 			Details: errorDetails,
 		},
 		Meta: ErrorMeta{
-			RequestID: uuid.New().String(),
+			RequestID: requestID(c),
 			Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
 		},
 	}
 }
 
 // NewValidationErrorResponse creates a validation error with field details
-func NewValidationErrorResponse(field, value, constraint string) *StandardErrorResponse {
-	return NewStandardErrorResponse(
+func NewValidationErrorResponse(c *gin.Context, field, value, constraint string) *StandardErrorResponse {
+	return NewStandardErrorResponse(c,
 		ErrorCodeValidationFailed,
 		"Validation failed",
 		map[string]interface{}{
@@ -73,3 +120,18 @@ func NewValidationErrorResponse(field, value, constraint string) *StandardErrorR
 		},
 	)
 }
+
+// requestID returns the ID the RequestID middleware assigned to the request. Requests
+// that bypassed it are given one for the rest of the request, so every error and log
+// line about them agrees.
+func requestID(c *gin.Context) string {
+	if c == nil || c.Request == nil {
+		return uuid.New().String()
+	}
+	if id := logging.RequestID(c.Request.Context()); id != "" {
+		return id
+	}
+	id := uuid.New().String()
+	c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+	return id
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/selfcheck"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealthRoutes configures the orchestrator probes. Liveness only shows the process
+// is serving requests; readiness pings each dependency, giving each up to timeout, and
+// fails while drain is draining.
+func SetupHealthRoutes(r *gin.Engine, timeout time.Duration, drain *selfcheck.Drain, dependencies ...selfcheck.Dependency) {
+	r.GET("/health/live", getLiveness)                                 // GET /health/live
+	r.GET("/health/ready", getReadiness(timeout, drain, dependencies)) // GET /health/ready
+}
+
+// getLiveness handles GET /health/live
+// @Summary Liveness probe
+// @Description Always succeeds while the process can serve requests. Touches no dependencies, so
+// @Description orchestrators can poll it cheaply and restart the server only when it hangs.
+// @Tags health
+// @Success 200 {object} handlers.HealthResponse
+// @Router /health/live [get]
+func getLiveness(c *gin.Context) {
+	c.JSON(http.StatusOK, NewHealthResponse("alive", "rawboard", "1.0.0", time.Now().UTC().Format(time.RFC3339)))
+}
+
+// getReadiness handles GET /health/ready
+// @Summary Readiness probe
+// @Description Pings every dependency with a short timeout and reports each one's status and
+// @Description latency. Returns 503 when any is down, or while the instance is draining, so traffic is routed elsewhere.
+// @Tags health
+// @Success 200 {object} models.ReadinessReport
+// @Failure 503 {object} models.ReadinessReport "A dependency is down, or the instance is draining"
+// @Router /health/ready [get]
+func getReadiness(timeout time.Duration, drain *selfcheck.Drain, dependencies []selfcheck.Dependency) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := selfcheck.Probe(c.Request.Context(), timeout, dependencies)
+		if status := drain.Status(); status.Draining {
+			report.Status = models.ReadinessDraining
+			report.Drain = &status
+		}
+
+		status := http.StatusOK
+		if report.Status != models.ReadinessReady {
+			status = http.StatusServiceUnavailable
+		}
+		if report.Status == models.ReadinessNotReady {
+			requestLogger(c).Warn("readiness probe failed", "dependencies", report.Dependencies)
+		}
+		c.Header("Cache-Control", "no-store")
+		c.JSON(status, report)
+	}
+}
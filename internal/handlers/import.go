@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/export"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize caps import bodies; the whole import is validated before anything is written
+const maxImportSize = 32 << 20
+
+// ImportScores handles POST /api/v1/games/:gameId/import
+// @Summary Seed or restore a game from a score download
+// @Description Accepts the CSV or JSON of a score history or leaderboard download, from this or another server, and makes it the game's history (mode=replace) or adds it to the history, skipping scores already there (mode=append). High scores and the leaderboard are rebuilt from the result. Scores are validated like submissions, but anti-cheat rules and daily budgets aren't applied again. If any score is invalid nothing is imported and the report lists the problems. The game ID inside a JSON download is ignored. CSV needs the columns initials, score (as stored, without decimals) and timestamp (RFC 3339); counted, flags and metadata are optional.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param format query string false "csv or json; defaults to csv when the body is sent as text/csv and json otherwise"
+// @Param mode query string false "replace (default) or append"
+// @Param dry_run query boolean false "Validate and report without importing"
+// @Param request body models.AllScoresRecord true "Score history or leaderboard download"
+// @Success 200 {object} models.ImportReport
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, format, mode or payload"
+// @Failure 413 {object} handlers.StandardErrorResponse "Import too large"
+// @Failure 422 {object} handlers.StandardErrorResponse "Some scores are invalid; details.report lists them"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to import scores"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/import [post]
+func (h *AdminHandler) ImportScores(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	raw := c.Query("format")
+	if raw == "" && strings.HasPrefix(c.ContentType(), "text/csv") {
+		raw = export.FormatCSV
+	}
+	format, err := export.CheckFormat(raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "format", raw, "csv or json"))
+		return
+	}
+
+	mode := c.DefaultQuery("mode", models.ImportReplace)
+	if mode != models.ImportReplace && mode != models.ImportAppend {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "mode", mode, "replace or append"))
+		return
+	}
+	dryRun := c.Query("dry_run") == "true"
+
+	scores, err := export.ReadHistory(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize), format)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Import too large",
+			map[string]interface{}{"max_bytes": maxImportSize}))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid import",
+			map[string]interface{}{"format": format, "error": err.Error()}))
+		return
+	}
+
+	report, err := h.service.ImportScores(c.Request.Context(), gameID, mode, scores, dryRun)
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if errors.Is(err, leaderboard.ErrInvalidImport) {
+		c.JSON(http.StatusUnprocessableEntity, NewStandardErrorResponse(c,
+			ErrorCodeInvalidImport, "Some imported scores are invalid; nothing was imported",
+			map[string]interface{}{"report": report}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to import scores", "game_id", gameID, "mode", mode, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to import scores"))
+		return
+	}
+
+	if report.Imported {
+		h.recordAudit(c, models.AuditEntry{
+			Action: audit.ActionScoresImported,
+			GameID: gameID,
+			Details: map[string]interface{}{
+				"format":     format,
+				"mode":       mode,
+				"received":   report.Received,
+				"duplicates": report.Duplicates,
+				"scores":     report.Scores,
+				"players":    report.Players,
+			},
+		})
+	}
+
+	c.JSON(http.StatusOK, report)
+}
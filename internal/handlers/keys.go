@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListAPIKeys handles GET /api/v1/admin/keys
+// @Summary List API keys
+// @Description Requires the master key. Only keys of the tenant the request acts for are listed.
+// @Tags keys
+// @Success 200 {object} handlers.APIKeyListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list keys"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/keys [get]
+func (h *AdminHandler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.keys.List(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list api keys", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list API keys"))
+		return
+	}
+
+	c.JSON(http.StatusOK, APIKeyListResponse{Keys: keys})
+}
+
+// CreateAPIKey handles POST /api/v1/admin/keys
+// @Summary Create a scoped API key
+// @Description Requires the master key. The key belongs to the tenant the request acts for: created under /api/v1/tenants/{tenantId}, it may only act for that tenant. Submitter keys, for cabinets, may only submit; reading every score and deleting anything needs an admin key.
+// @Tags keys
+// @Param request body handlers.CreateAPIKeyRequest true "Key name, games and scopes"
+// @Success 201 {object} models.CreatedAPIKey "The key secret is only returned once"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/keys [post]
+func (h *AdminHandler) CreateAPIKey(c *gin.Context) {
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	for _, gameID := range req.GameIDs {
+		if len(gameID) > 50 || len(gameID) < 1 {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"game_ids", gameID, "length between 1 and 50 characters, or \"*\" for every game"))
+			return
+		}
+	}
+
+	for _, scope := range req.Scopes {
+		if !apikeys.IsValidScope(scope) {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"scopes", scope, "one of submit, admin:read, admin:write"))
+			return
+		}
+	}
+
+	if req.Role == "" {
+		req.Role = models.RoleForScopes(req.Scopes)
+	} else if !apikeys.IsValidRole(req.Role) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"role", req.Role, "one of submitter, admin"))
+		return
+	}
+
+	ctx := c.Request.Context()
+	created, err := h.keys.CreateWithRole(ctx, req.Name, req.Role, req.GameIDs, req.Scopes)
+	if errors.Is(err, apikeys.ErrSubmitterScopes) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"scopes", strings.Join(req.Scopes, ","), "only submit for a submitter key"))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to create api key", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to create API key"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action: audit.ActionAPIKeyCreated,
+		Details: map[string]interface{}{
+			"key_id":   created.ID,
+			"name":     created.Name,
+			"game_ids": created.GameIDs,
+			"scopes":   created.Scopes,
+			"role":     created.Role,
+		},
+	})
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// RevokeAPIKey handles DELETE /api/v1/admin/keys/:keyId
+// @Summary Revoke an API key
+// @Description Requires the master key.
+// @Tags keys
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} models.APIKey
+// @Failure 404 {object} handlers.StandardErrorResponse "Key not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/keys/{keyId} [delete]
+func (h *AdminHandler) RevokeAPIKey(c *gin.Context) {
+	keyID := c.Param("keyId")
+
+	key, err := h.keys.Revoke(c.Request.Context(), keyID)
+	if errors.Is(err, apikeys.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAPIKeyNotFound, "API key not found",
+			map[string]interface{}{"key_id": keyID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to revoke api key", "key_id", keyID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to revoke API key"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAPIKeyRevoked,
+		Details: map[string]interface{}{"key_id": key.ID, "name": key.Name},
+	})
+
+	c.JSON(http.StatusOK, key)
+}
+
+// GetGameQuota handles GET /api/v1/admin/keys/:keyId/game-quota
+// @Summary Get the games an API key has created against its game limit
+// @Description Requires the master key.
+// @Tags keys
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} models.GameQuota
+// @Failure 404 {object} handlers.StandardErrorResponse "Key not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/keys/{keyId}/game-quota [get]
+func (h *AdminHandler) GetGameQuota(c *gin.Context) {
+	keyID := c.Param("keyId")
+
+	ctx := c.Request.Context()
+	key, err := h.keys.Get(ctx, keyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAPIKeyNotFound, "API key not found",
+			map[string]interface{}{"key_id": keyID}))
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.GameQuota(ctx, key.ID, key.MaxGames))
+}
+
+// UpdateGameQuota handles PUT /api/v1/admin/keys/:keyId/game-quota
+// @Summary Override how many games an API key may create
+// @Description Requires the master key. Games the key already created are kept even when the new limit is lower.
+// @Tags keys
+// @Param keyId path string true "API key ID"
+// @Param request body handlers.UpdateGameQuotaRequest true "The key's game limit"
+// @Success 200 {object} models.GameQuota
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid request"
+// @Failure 404 {object} handlers.StandardErrorResponse "Key not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/keys/{keyId}/game-quota [put]
+func (h *AdminHandler) UpdateGameQuota(c *gin.Context) {
+	keyID := c.Param("keyId")
+
+	var req UpdateGameQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	ctx := c.Request.Context()
+	key, err := h.keys.SetMaxGames(ctx, keyID, req.MaxGames)
+	if errors.Is(err, apikeys.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAPIKeyNotFound, "API key not found",
+			map[string]interface{}{"key_id": keyID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update api key game limit", "key_id", keyID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update the API key's game limit"))
+		return
+	}
+
+	quota := h.service.GameQuota(ctx, key.ID, key.MaxGames)
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionAPIKeyGameLimitUpdated,
+		Details: map[string]interface{}{"key_id": key.ID, "name": key.Name, "limit": quota.Limit, "default": quota.Default},
+	})
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// UpdateRateLimit handles PUT /api/v1/admin/keys/:keyId/rate-limit
+// @Summary Override an API key's rate limit
+// @Description Requires the master key. The key's own limit takes precedence over API_RATE_LIMIT and API_RATE_LIMIT_OVERRIDES; a requests_per_second of 0 exempts the key, and null restores the deployment's limits. Applies from the key's next request, on every replica.
+// @Tags keys
+// @Param keyId path string true "API key ID"
+// @Param request body handlers.UpdateRateLimitRequest true "The key's rate limit"
+// @Success 200 {object} models.APIKey
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid rate limit"
+// @Failure 404 {object} handlers.StandardErrorResponse "Key not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/keys/{keyId}/rate-limit [put]
+func (h *AdminHandler) UpdateRateLimit(c *gin.Context) {
+	keyID := c.Param("keyId")
+
+	var req UpdateRateLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	var limit *models.RateLimit
+	if req.RequestsPerSecond != nil {
+		limit = &models.RateLimit{RequestsPerSecond: *req.RequestsPerSecond, Burst: req.Burst}
+		if err := limit.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+				ErrorCodeValidationFailed, err.Error()))
+			return
+		}
+	}
+
+	key, err := h.keys.SetRateLimit(c.Request.Context(), keyID, limit)
+	if errors.Is(err, apikeys.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeAPIKeyNotFound, "API key not found",
+			map[string]interface{}{"key_id": keyID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to update api key rate limit", "key_id", keyID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to update the API key's rate limit"))
+		return
+	}
+
+	details := map[string]interface{}{"key_id": key.ID, "name": key.Name, "default": limit == nil}
+	if limit != nil {
+		details["requests_per_second"] = limit.RequestsPerSecond
+		details["burst"] = limit.Burst
+	}
+	h.recordAudit(c, models.AuditEntry{Action: audit.ActionAPIKeyRateLimitUpdated, Details: details})
+
+	c.JSON(http.StatusOK, key)
+}
+
+// recordAudit records an admin action, logging rather than failing the request on error
+func (h *AdminHandler) recordAudit(c *gin.Context, entry models.AuditEntry) {
+	recordAudit(c, h.audit, entry)
+}
+
+// recordAudit records an action to auditLog as the request's caller
+func recordAudit(c *gin.Context, auditLog *audit.Log, entry models.AuditEntry) {
+	entry.Actor = actor(c)
+	if err := auditLog.Record(c.Request.Context(), entry); err != nil {
+		requestLogger(c).Error("failed to record audit entry", "action", entry.Action, "error", err)
+	}
+}
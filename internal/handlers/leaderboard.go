@@ -1,10 +1,16 @@
 package handlers
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
+	"rawboard/internal/export"
 	"rawboard/internal/leaderboard"
 	"rawboard/internal/models"
 
@@ -22,128 +28,382 @@ func NewLeaderboardHandler(service *leaderboard.Service) *LeaderboardHandler {
 }
 
 // SubmitScore handles POST /api/v1/games/:gameId/scores
+// @Summary Submit a score
+// @Description Stores the score in the full history, updates the player's high score and regenerates the leaderboard. The response includes a receipt token for later lookups.
+// @Description In games with a daily submission budget, plays over budget are stored in history flagged non_counting, leave the leaderboard unchanged and get no receipt; the response reports the remaining budget.
+// @Description Scores breaking the game's anti-cheat rules are rejected with SUSPICIOUS_SCORE, or accepted with their violations in entry.flags when the game flags them for review.
+// @Description Scores are whole and non-negative unless the game's scoring settings allow decimals or negatives. Decimal games store scores as fixed-point integers (12.5 as 1250 with 2 decimals) and return them formatted in display_score.
+// @Description In games requiring PINs, submissions under initials claimed through /api/v1/players must carry the PIN, failing with PIN_REQUIRED or WRONG_PIN.
+// @Description Games whose initials policy is claimed refuse unclaimed initials with CLAIM_REQUIRED; in device-scoped games the player is the initials on the submitting device.
+// @Description Equal scores rank newest first, then by the sequence the server assigns in arrival order. Entries synced with a client_sequence rank after those, by it; client sequences are only compared with each other.
+// @Description newly_unlocked_achievements lists the achievements the score unlocked for the player, so cabinets can celebrate them straight away.
+// @Description Games with a scheduled sunset report it in sunset and a Sunset header; from then on their submissions fail with 410 GAME_SUNSET, while reads keep working.
+// @Description Games with a signing secret require a signature: the hex HMAC-SHA256, under the secret, of the game ID, initials, score as the game formats it, Unix timestamp and nonce, joined by newlines. Unsigned submissions fail with SIGNATURE_REQUIRED; forged ones, ones more than 5 minutes from the server's clock and reused nonces with INVALID_SIGNATURE.
+// @Description Keys with the edge scope, held by edge instances forwarding their venue's scores, may send played_at, device_id and sequence; the score is stored, and judged against sunsets, budgets, anti-cheat rules, happy hours and the signature window, as of played_at. Other keys sending them get 403 INSUFFICIENT_SCOPE.
+// @Tags scores
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.ScoreSubmissionRequest true "Score to submit"
+// @Success 201 {object} handlers.ScoreSubmissionResponse "Score stored"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, initials, score or blocked initials"
+// @Failure 410 {object} handlers.StandardErrorResponse "The game is past its sunset and no longer takes submissions"
+// @Failure 422 {object} handlers.StandardErrorResponse "Score rejected by the game's anti-cheat rules or a submission hook"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many wrong PINs for the initials"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key, the initials' PIN is missing or wrong, or the score signature is missing or invalid"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game, played_at or device_id was sent without the edge scope, or the game only accepts claimed initials"
+// @Router /api/v1/games/{gameId}/scores [post]
 func (h *LeaderboardHandler) SubmitScore(c *gin.Context) {
 	gameID := c.Param("gameId")
 	if gameID == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidGameID, "Game ID is required"))
 		return
 	}
 
 	// Validate gameID format (prevent injection attacks and ensure reasonable length)
 	if len(gameID) > 50 || len(gameID) < 1 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"gameId", gameID, "length between 1 and 50 characters"))
 		return
 	}
 
 	var req ScoreSubmissionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidRequest, "Invalid request format",
 			map[string]interface{}{"validation_error": err.Error()}))
 		return
 	}
 
+	// Submit variants of an existing game's ID to that game rather than a new one
+	gameID = h.service.ResolveGameID(c.Request.Context(), gameID)
+
+	// Read the score in the game's scoring mode
+	score, err := h.service.ParseScore(c.Request.Context(), gameID, req.Score.String())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
 	// Convert to score entry and validate
-	entry := req.ToScoreEntry()
+	entry := req.ToScoreEntry(score)
 	if err := entry.Validate(); err != nil {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		if errors.Is(err, models.ErrBlockedInitials) {
+			blockedInitialsResponse(c, entry.Initials)
+			return
+		}
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeValidationFailed, err.Error()))
 		return
 	}
 
+	if err := models.ValidateSessionID(req.SessionID); err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"session_id", req.SessionID, fmt.Sprintf("letters, digits, '.', '_', ':' and '-', at most %d characters", models.MaxSessionIDLength)))
+		return
+	}
+
+	// Only edge instances vouch for when, where and in which order a score was played
+	if p := principal(c); p != nil && !p.HasScope(models.ScopeEdge) && (req.PlayedAt != nil || req.DeviceID != "" || req.Sequence != 0) {
+		c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+			ErrorCodeInsufficientScope, "API key lacks the required scope to set played_at, device_id or sequence",
+			map[string]interface{}{"required_scope": models.ScopeEdge}))
+		return
+	}
+	submission := models.Submission{
+		Initials:  entry.Initials,
+		Score:     entry.Score,
+		Metadata:  entry.Metadata,
+		Sequence:  req.Sequence,
+		PIN:       req.PIN,
+		SessionID: req.SessionID,
+		Signature: req.Signature,
+		DeviceID:  req.DeviceID,
+	}
+	if req.PlayedAt != nil {
+		submission.PlayedAt = *req.PlayedAt
+	}
+
 	// Submit the score
-	err := h.service.SubmitScore(c.Request.Context(), gameID, entry.Initials, entry.Score)
+	result, err := h.service.Submit(c.Request.Context(), gameID, submission)
+	if errors.Is(err, models.ErrBlockedInitials) {
+		blockedInitialsResponse(c, entry.Initials)
+		return
+	}
+	if pinErrorResponse(c, entry.Initials, err) {
+		return
+	}
+	if errors.Is(err, models.ErrInvalidScore) {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+	var suspicious *models.SuspiciousScoreError
+	if errors.As(err, &suspicious) {
+		c.JSON(http.StatusUnprocessableEntity, NewStandardErrorResponse(c,
+			ErrorCodeSuspiciousScore, "Score rejected by the game's anti-cheat rules",
+			map[string]interface{}{"violations": suspicious.Violations}))
+		return
+	}
+	var rejected *models.SubmissionRejectedError
+	if errors.As(err, &rejected) {
+		c.JSON(http.StatusUnprocessableEntity, NewStandardErrorResponse(c,
+			ErrorCodeSubmissionRejected, "Score rejected by a submission hook",
+			map[string]interface{}{"reason": rejected.Reason}))
+		return
+	}
+	if gameLimitResponse(c, err) || gameSunsetResponse(c, err) || signatureResponse(c, err) {
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		requestLogger(c).Error("score submission failed", "error", err)
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInternalError, err.Error()))
 		return
 	}
 
+	budget := result.Budget
+	unlocked := result.Achievements
+	if unlocked == nil {
+		unlocked = []models.Achievement{}
+	}
+	entry.ID = result.Entry.ID
+	// Submission hooks may have changed what was stored
+	entry.Initials = result.Entry.Initials
+	entry.Score = result.Entry.Score
+	entry.Metadata = result.Entry.Metadata
+	entry.RawScore = result.Entry.RawScore
+	entry.Multiplier = result.Entry.Multiplier
+	entry.HappyHour = result.Entry.HappyHour
+	entry.Flags = result.Entry.Flags
+	entry.DisplayScore = result.Entry.DisplayScore
+	entry.Sequence = result.Entry.Sequence
+	entry.ClientSequence = result.Entry.ClientSequence
+	entry.SessionID = result.Entry.SessionID
+	entry.Timestamp = result.Entry.Timestamp
+	entry.DeviceID = result.Entry.DeviceID
+	if result.Sunset != nil {
+		c.Header("Sunset", result.Sunset.At.Format(http.TimeFormat))
+	}
+	message := "Score submitted successfully"
+	var receipt string
+	if budget != nil && !budget.Counted {
+		// Over-budget plays never stand on the board, so there is nothing to look up later
+		entry.NonCounting = true
+		message = "Score recorded but not counted: daily submission budget used up"
+	} else {
+		if len(entry.Flags) > 0 {
+			message = "Score submitted and flagged for review"
+		}
+		// Issue a receipt so the player can check on this score later
+		receipt, err = h.service.IssueReceipt(c.Request.Context(), gameID, entry.Initials, entry.Score)
+		if err != nil {
+			requestLogger(c).Warn("failed to issue score receipt", "error", err)
+		}
+	}
+
 	// Get updated leaderboard to include in response
 	leaderboard, err := h.service.GetLeaderboard(c.Request.Context(), gameID)
 	if err != nil {
 		// If we can't get the leaderboard, still return success for the submission
 		c.JSON(http.StatusCreated, ScoreSubmissionResponse{
-			Message: "Score submitted successfully",
-			Entry:   entry,
+			Message:                   message,
+			Entry:                     entry,
+			ReceiptToken:              receipt,
+			Budget:                    budget,
+			NewlyUnlockedAchievements: unlocked,
+			Sunset:                    result.Sunset,
 		})
 		return
 	}
 
-	// Find the rank of the submitted score or the player's current position
-	var rank *int
-	for i, scoreEntry := range leaderboard.Entries {
-		if scoreEntry.Initials == entry.Initials {
-			// Player is on the leaderboard - return their current rank
-			// This could be either the just-submitted score (if it's their new high score)
-			// or their existing high score (if this submission was lower)
-			rankValue := i + 1
-			rank = &rankValue
-			break
+	c.JSON(http.StatusCreated, ScoreSubmissionResponse{
+		Message:                   message,
+		Entry:                     entry,
+		Leaderboard:               leaderboard,
+		Rank:                      playerRank(leaderboard, entry.Initials),
+		ReceiptToken:              receipt,
+		Budget:                    budget,
+		NewlyUnlockedAchievements: unlocked,
+		Sunset:                    result.Sunset,
+	})
+}
+
+// playerRank returns the player's position on the board, or nil if they're not on it.
+// After a submission this is either the new score (if it's their new high score)
+// or their existing high score (if the submission was lower).
+func playerRank(board *models.Leaderboard, initials string) *int {
+	for i, scoreEntry := range board.Entries {
+		if scoreEntry.Initials == initials {
+			rank := i + 1
+			return &rank
 		}
 	}
-	// If rank is still nil, the player is not in the top 10
+	return nil
+}
 
-	c.JSON(http.StatusCreated, ScoreSubmissionResponse{
-		Message:     "Score submitted successfully",
-		Entry:       entry,
-		Leaderboard: leaderboard,
-		Rank:        rank,
-	})
+// playerContext returns the request's context, scoped to the device_id query parameter
+// for player lookups in device-scoped games
+func playerContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if deviceID := c.Query("device_id"); deviceID != "" {
+		ctx = leaderboard.WithDevice(ctx, deviceID)
+	}
+	return ctx
+}
+
+// blockedInitialsResponse rejects a submission whose initials are on the blocklist
+func blockedInitialsResponse(c *gin.Context, initials string) {
+	c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+		ErrorCodeBlockedInitials, "These initials are not allowed",
+		map[string]interface{}{"initials": initials}))
+}
+
+// gameLimitResponse responds with 403 GAME_LIMIT_EXCEEDED and returns true when err
+// is models.ErrGameLimitExceeded
+func gameLimitResponse(c *gin.Context, err error) bool {
+	if !errors.Is(err, models.ErrGameLimitExceeded) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+		ErrorCodeGameLimitExceeded, "This API key has created as many games as it may",
+		map[string]interface{}{"game_id": c.Param("gameId"), "error": err.Error()}))
+	return true
+}
+
+// gameSunsetResponse answers 410 when err is a submission to a game past its sunset,
+// reporting whether it did
+func gameSunsetResponse(c *gin.Context, err error) bool {
+	var sunset *models.GameSunsetError
+	if !errors.As(err, &sunset) {
+		return false
+	}
+	c.JSON(http.StatusGone, NewStandardErrorResponse(c,
+		ErrorCodeGameSunset, "This game no longer takes submissions",
+		sunsetDetails(sunset)))
+	return true
+}
+
+// signatureResponse answers 401 when err refuses a submission's signature, reporting
+// whether it did
+func signatureResponse(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, models.ErrSignatureRequired):
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodeSignatureRequired, "This game only accepts signed submissions"))
+	case errors.Is(err, models.ErrInvalidScoreSignature):
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodeInvalidSignature, "Invalid score signature",
+			map[string]interface{}{"error": err.Error()}))
+	default:
+		return false
+	}
+	return true
+}
+
+// sunsetDetails describes a refused submission's sunset for the error response
+func sunsetDetails(sunset *models.GameSunsetError) map[string]interface{} {
+	details := map[string]interface{}{"game_id": sunset.GameID, "sunset_at": sunset.Sunset.At}
+	if sunset.Sunset.Message != "" {
+		details["message"] = sunset.Sunset.Message
+	}
+	return details
 }
 
 // GetLeaderboard handles GET /api/v1/games/:gameId/leaderboard
+// @Summary Get a game's leaderboard
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param limit query integer false "Maximum entries to return, up to the game's leaderboard size"
+// @Success 200 {object} models.Leaderboard "Highest score per player, best first; supports If-None-Match and If-Modified-Since"
+// @Success 304 "Leaderboard unchanged since the given ETag or time"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or limit"
+// @Failure 404 {object} handlers.StandardErrorResponse "No leaderboard for this game"
+// @Router /api/v1/games/{gameId}/leaderboard [get]
 func (h *LeaderboardHandler) GetLeaderboard(c *gin.Context) {
 	gameID := c.Param("gameId")
 	if gameID == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidGameID, "Game ID is required"))
 		return
 	}
 
 	// Validate gameID format
 	if len(gameID) > 50 || len(gameID) < 1 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"gameId", gameID, "length between 1 and 50 characters"))
 		return
 	}
 
-	leaderboard, err := h.service.GetLeaderboard(c.Request.Context(), gameID)
+	ctx := c.Request.Context()
+
+	// Parse the optional entry limit (up to the game's leaderboard size)
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		maxEntries := h.service.LeaderboardSize(ctx, gameID)
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 || parsed > maxEntries {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"limit", limitStr, fmt.Sprintf("integer between 1 and %d", maxEntries)))
+			return
+		}
+		limit = parsed
+	}
+
+	leaderboard, err := h.service.GetLeaderboard(ctx, gameID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, NewStandardErrorResponse(
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
 			ErrorCodeGameNotFound, "No leaderboard found for this game",
 			map[string]interface{}{"game_id": gameID}))
 		return
 	}
 
-	// Return the models.Leaderboard directly - no need for conversion
-	// Ensure it's typed as models.Leaderboard for documentation
-	var response *models.Leaderboard = leaderboard
-	c.JSON(http.StatusOK, response)
+	if limit > 0 && len(leaderboard.Entries) > limit {
+		leaderboard.Entries = leaderboard.Entries[:limit]
+	}
+
+	body, err := json.Marshal(leaderboard)
+	if err != nil {
+		requestLogger(c).Error("failed to encode leaderboard", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to encode leaderboard"))
+		return
+	}
+
+	// Clients reuse the board for a few seconds, then revalidate it rather than refetch
+	writeConditionalJSON(c, body, leaderboard.Updated, leaderboardCacheControl)
 }
 
 // GetPlayerStats handles GET /api/v1/games/:gameId/players/:initials/stats
+// @Summary Get a player's statistics
+// @Tags players
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials path string true "Player initials"
+// @Param device_id query string false "In device-scoped games, the device to look the initials up on; defaults to the calling device, else the initials on any device"
+// @Success 200 {object} models.PlayerStats
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or initials"
+// @Failure 404 {object} handlers.StandardErrorResponse "Player not found"
+// @Router /api/v1/games/{gameId}/players/{initials}/stats [get]
 func (h *LeaderboardHandler) GetPlayerStats(c *gin.Context) {
 	gameID := c.Param("gameId")
 	initials := c.Param("initials")
 
 	if gameID == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidGameID, "Game ID is required"))
 		return
 	}
 
 	if initials == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidInitials, "Player initials are required"))
 		return
 	}
 
 	// Validate gameID format
 	if len(gameID) > 50 || len(gameID) < 1 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"gameId", gameID, "length between 1 and 50 characters"))
 		return
 	}
@@ -151,14 +411,14 @@ func (h *LeaderboardHandler) GetPlayerStats(c *gin.Context) {
 	// Validate initials format
 	initials = strings.ToUpper(strings.TrimSpace(initials))
 	if len(initials) != 3 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"initials", initials, "exactly 3 characters"))
 		return
 	}
 
-	stats, err := h.service.GetPlayerStats(c.Request.Context(), gameID, initials)
+	stats, err := h.service.GetPlayerStats(playerContext(c), gameID, initials)
 	if err != nil {
-		c.JSON(http.StatusNotFound, NewStandardErrorResponse(
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
 			ErrorCodePlayerNotFound, "No stats found for this player",
 			map[string]interface{}{
 				"game_id":  gameID,
@@ -170,25 +430,183 @@ func (h *LeaderboardHandler) GetPlayerStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetPlayerRank handles GET /api/v1/games/:gameId/players/:initials/rank
+// @Summary Get a player's absolute rank
+// @Tags players
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials path string true "Player initials"
+// @Param device_id query string false "In device-scoped games, the device to look the initials up on; defaults to the calling device, else the initials on any device"
+// @Success 200 {object} models.PlayerRank
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or initials"
+// @Failure 404 {object} handlers.StandardErrorResponse "Player not found"
+// @Router /api/v1/games/{gameId}/players/{initials}/rank [get]
+func (h *LeaderboardHandler) GetPlayerRank(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	initials := strings.ToUpper(strings.TrimSpace(c.Param("initials")))
+	if len(initials) != 3 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"initials", initials, "exactly 3 characters"))
+		return
+	}
+
+	rank, err := h.service.GetPlayerRank(playerContext(c), gameID, initials)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodePlayerNotFound, "No rank found for this player",
+			map[string]interface{}{
+				"game_id":  gameID,
+				"initials": initials,
+			}))
+		return
+	}
+
+	c.JSON(http.StatusOK, rank)
+}
+
+// GetLeaderboardAround handles GET /api/v1/games/:gameId/leaderboard/around/:initials
+// @Summary Get the leaderboard around a player
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials path string true "Player initials"
+// @Param window query integer false "Entries above and below the player (0-25, default 3)"
+// @Param device_id query string false "In device-scoped games, the device to look the initials up on; defaults to the calling device, else the initials on any device"
+// @Success 200 {object} models.AroundMeResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, initials or window"
+// @Failure 404 {object} handlers.StandardErrorResponse "Player not found"
+// @Router /api/v1/games/{gameId}/leaderboard/around/{initials} [get]
+func (h *LeaderboardHandler) GetLeaderboardAround(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	initials := strings.ToUpper(strings.TrimSpace(c.Param("initials")))
+	if len(initials) != 3 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"initials", initials, "exactly 3 characters"))
+		return
+	}
+
+	window := 3
+	if windowStr := c.Query("window"); windowStr != "" {
+		parsed, err := strconv.Atoi(windowStr)
+		if err != nil || parsed < 0 || parsed > leaderboard.MaxAroundWindow {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"window", windowStr, fmt.Sprintf("integer between 0 and %d", leaderboard.MaxAroundWindow)))
+			return
+		}
+		window = parsed
+	}
+
+	around, err := h.service.GetLeaderboardAround(playerContext(c), gameID, initials, window)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodePlayerNotFound, "Player not found on this leaderboard",
+			map[string]interface{}{
+				"game_id":  gameID,
+				"initials": initials,
+			}))
+		return
+	}
+
+	c.JSON(http.StatusOK, around)
+}
+
+// GetLeaderboardChanges handles GET /api/v1/games/:gameId/leaderboard/changes
+// @Summary Get a game's top movers and new entrants
+// @Description Compares the board with a snapshot of it from since ago. Snapshots are taken hourly and kept hourly for two days, then daily for 30 days; when none is that old the oldest is used, and since in the response says when the compared snapshot was taken.
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param since query string false "How far back to compare, e.g. 7d or 24h; default 7d, from 1h to 30d"
+// @Success 200 {object} models.LeaderboardChanges
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or since"
+// @Failure 404 {object} handlers.StandardErrorResponse "No leaderboard, or no snapshot of it yet"
+// @Router /api/v1/games/{gameId}/leaderboard/changes [get]
+func (h *LeaderboardHandler) GetLeaderboardChanges(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	since := 7 * 24 * time.Hour
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		parsed, err := parseWindow(sinceStr)
+		if err != nil || parsed < time.Hour || parsed > models.MaxChangesWindow {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"since", sinceStr, "duration such as 7d or 24h, from 1h to 30d"))
+			return
+		}
+		since = parsed
+	}
+
+	changes, err := h.service.LeaderboardChanges(c.Request.Context(), gameID, since, time.Now())
+	if errors.Is(err, leaderboard.ErrNoSnapshot) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "No snapshot of this leaderboard yet; one is taken every hour",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "No leaderboard found for this game",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+// parseWindow parses a duration given in days, like 7d, or as a Go duration like 24h
+func parseWindow(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
 // GetAllScores handles GET /api/v1/games/:gameId/scores/all (admin endpoint)
+// @Summary Get a game's complete score history
+// @Tags scores
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.AllScoresRecord
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "No score history for this game"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId}/scores/all [get]
 func (h *LeaderboardHandler) GetAllScores(c *gin.Context) {
 	gameID := c.Param("gameId")
 	if gameID == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidGameID, "Game ID is required"))
 		return
 	}
 
 	// Validate gameID format
 	if len(gameID) > 50 || len(gameID) < 1 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"gameId", gameID, "length between 1 and 50 characters"))
 		return
 	}
 
 	allScores, err := h.service.GetAllScoresForGame(c.Request.Context(), gameID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, NewStandardErrorResponse(
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
 			ErrorCodeScoreHistoryEmpty, "No score history found for this game",
 			map[string]interface{}{"game_id": gameID}))
 		return
@@ -197,26 +615,297 @@ func (h *LeaderboardHandler) GetAllScores(c *gin.Context) {
 	c.JSON(http.StatusOK, allScores)
 }
 
+// ExportLeaderboard handles GET /api/v1/games/:gameId/leaderboard/export (admin endpoint)
+// @Summary Download a game's full ranking as CSV or JSON
+// @Description Every player's high score in leaderboard order, including players below the
+// @Description leaderboard's size. CSV has the columns rank, initials, score, display_score and timestamp.
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param format query string false "csv or json (default)"
+// @Success 200 {object} models.Ranking "JSON, or CSV with one row per player"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or format"
+// @Failure 404 {object} handlers.StandardErrorResponse "No leaderboard for this game"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/leaderboard/export [get]
+func (h *LeaderboardHandler) ExportLeaderboard(c *gin.Context) {
+	gameID, format, ok := downloadRequest(c)
+	if !ok {
+		return
+	}
+
+	ranking, err := h.service.GetRanking(c.Request.Context(), gameID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "No leaderboard found for this game",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+
+	startDownload(c, gameID+"-leaderboard", format)
+	if err := export.WriteRanking(c.Writer, format, ranking); err != nil {
+		requestLogger(c).Warn("leaderboard export interrupted", "game_id", gameID, "error", err)
+	}
+}
+
+// ExportScores handles GET /api/v1/games/:gameId/scores/all/export (admin endpoint)
+// @Summary Download a game's complete score history as CSV or JSON
+// @Description Every submission, oldest first, encoded as it is sent. CSV has the columns initials,
+// @Description score, display_score, timestamp, counted (false for plays over a daily budget), flags
+// @Description (the anti-cheat rules broken, separated by semicolons), metadata (as JSON) and device_id
+// @Description (the enrolled device that submitted it).
+// @Tags scores
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param format query string false "csv or json (default)"
+// @Success 200 {object} models.AllScoresRecord "JSON, or CSV with one row per submission"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or format"
+// @Failure 404 {object} handlers.StandardErrorResponse "No score history for this game"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId}/scores/all/export [get]
+func (h *LeaderboardHandler) ExportScores(c *gin.Context) {
+	gameID, format, ok := downloadRequest(c)
+	if !ok {
+		return
+	}
+
+	allScores, err := h.service.GetAllScoresForGame(c.Request.Context(), gameID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeScoreHistoryEmpty, "No score history found for this game",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+
+	startDownload(c, gameID+"-scores", format)
+	if err := export.WriteHistory(c.Writer, format, allScores); err != nil {
+		requestLogger(c).Warn("score history export interrupted", "game_id", gameID, "error", err)
+	}
+}
+
+// downloadRequest validates a download's game ID and format, responding with 400 when
+// either is invalid
+func downloadRequest(c *gin.Context) (gameID, format string, ok bool) {
+	gameID = c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return "", "", false
+	}
+
+	format, err := export.CheckFormat(c.Query("format"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"format", c.Query("format"), "csv or json"))
+		return "", "", false
+	}
+	return gameID, format, true
+}
+
+// startDownload sends the headers of a file download named after name and format
+func startDownload(c *gin.Context, name, format string) {
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+	c.Status(http.StatusOK)
+}
+
+// QueryScores handles GET /api/v1/games/:gameId/scores (admin endpoint)
+// @Summary Query a game's score history by score range and time window
+// @Description Returns individual submissions, not just each player's best. Results are ordered by score (highest first) when min or max is given, and by time (newest first) otherwise. Page through results with offset until has_more is false.
+// @Tags scores
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param min query number false "Lowest score to include, with decimals in games that keep them"
+// @Param max query number false "Highest score to include, with decimals in games that keep them"
+// @Param from query string false "Earliest submission time to include (RFC 3339)"
+// @Param to query string false "Latest submission time to include (RFC 3339)"
+// @Param limit query integer false "Page size, default 50, up to 500"
+// @Param offset query integer false "Matches to skip"
+// @Success 200 {object} models.ScoreQueryResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or query"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to query scores"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/scores [get]
+func (h *LeaderboardHandler) QueryScores(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	query := models.ScoreQuery{Limit: leaderboard.DefaultScoreQueryLimit}
+	var ok bool
+	if query.MinScore, ok = h.scoreBound(c, gameID, "min"); !ok {
+		return
+	}
+	if query.MaxScore, ok = h.scoreBound(c, gameID, "max"); !ok {
+		return
+	}
+	if query.MinScore != nil && query.MaxScore != nil && *query.MinScore > *query.MaxScore {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "max", c.Query("max"), "at least min"))
+		return
+	}
+
+	if query.From, ok = timeBound(c, "from"); !ok {
+		return
+	}
+	if query.To, ok = timeBound(c, "to"); !ok {
+		return
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "to", c.Query("to"), "not before from"))
+		return
+	}
+
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > leaderboard.MaxScoreQueryLimit {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"limit", limitStr, fmt.Sprintf("integer between 1 and %d", leaderboard.MaxScoreQueryLimit)))
+			return
+		}
+		query.Limit = limit
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"offset", offsetStr, "non-negative integer"))
+			return
+		}
+		query.Offset = offset
+	}
+
+	response, err := h.service.QueryScores(c.Request.Context(), gameID, query)
+	if err != nil {
+		requestLogger(c).Error("failed to query scores", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to query scores"))
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetScoreTimeseries handles GET /api/v1/games/:gameId/scores/timeseries (admin endpoint)
+// @Summary Chart a game's submissions over time
+// @Description Counts submissions, distinct players and the average score in each UTC hour or day up to now, including empty buckets. The counts are kept as scores are submitted, so deleting scores doesn't change them. Hours go back a week and days a year.
+// @Tags scores
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param bucket query string false "hour or day, default day"
+// @Param count query integer false "Buckets to return, ending with the current one; default 24 hours or 30 days"
+// @Success 200 {object} models.ScoreTimeseries
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, bucket or count"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to get the time series"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/scores/timeseries [get]
+func (h *LeaderboardHandler) GetScoreTimeseries(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	bucket := c.DefaultQuery("bucket", models.TimeseriesDay)
+	count, maxCount := 30, models.MaxTimeseriesDays
+	switch bucket {
+	case models.TimeseriesDay:
+	case models.TimeseriesHour:
+		count, maxCount = 24, models.MaxTimeseriesHours
+	default:
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"bucket", bucket, "hour or day"))
+		return
+	}
+	if countStr := c.Query("count"); countStr != "" {
+		parsed, err := strconv.Atoi(countStr)
+		if err != nil || parsed < 1 || parsed > maxCount {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"count", countStr, fmt.Sprintf("integer between 1 and %d", maxCount)))
+			return
+		}
+		count = parsed
+	}
+
+	series, err := h.service.ScoreTimeseries(c.Request.Context(), gameID, bucket, count, time.Now())
+	if err != nil {
+		requestLogger(c).Error("failed to get score time series", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get score time series"))
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// scoreBound parses an optional score query parameter in the game's scoring mode,
+// responding with a validation error if it's malformed
+func (h *LeaderboardHandler) scoreBound(c *gin.Context, gameID, name string) (*int64, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, true
+	}
+	parsed, err := h.service.ParseScore(c.Request.Context(), gameID, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, name, raw, "score in the game's scoring mode"))
+		return nil, false
+	}
+	return &parsed, true
+}
+
+// timeBound parses an optional RFC 3339 query parameter, responding with a
+// validation error if it's malformed
+func timeBound(c *gin.Context, name string) (time.Time, bool) {
+	raw := c.Query(name)
+	if raw == "" {
+		return time.Time{}, true
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, name, raw, "RFC 3339 timestamp"))
+		return time.Time{}, false
+	}
+	return parsed, true
+}
+
 // GetEnhancedPlayerStats handles GET /api/v1/games/:gameId/players/:initials/stats/enhanced
+// @Summary Get a player's enhanced statistics
+// @Description sessions groups the player's plays into visits: by the session_id they were submitted with, or by pauses of up to 30 minutes
+// @Tags players
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials path string true "Player initials"
+// @Param include_history query boolean false "Include the player's full score history"
+// @Param device_id query string false "In device-scoped games, the device to look the initials up on; defaults to the calling device, else the initials on any device"
+// @Success 200 {object} models.EnhancedPlayerStats
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or initials"
+// @Failure 404 {object} handlers.StandardErrorResponse "Player not found"
+// @Router /api/v1/games/{gameId}/players/{initials}/stats/enhanced [get]
 func (h *LeaderboardHandler) GetEnhancedPlayerStats(c *gin.Context) {
 	gameID := c.Param("gameId")
 	initials := c.Param("initials")
 
 	if gameID == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidGameID, "Game ID is required"))
 		return
 	}
 
 	if initials == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidInitials, "Player initials are required"))
 		return
 	}
 
 	// Validate gameID format
 	if len(gameID) > 50 || len(gameID) < 1 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"gameId", gameID, "length between 1 and 50 characters"))
 		return
 	}
@@ -224,7 +913,7 @@ func (h *LeaderboardHandler) GetEnhancedPlayerStats(c *gin.Context) {
 	// Validate initials format
 	initials = strings.ToUpper(strings.TrimSpace(initials))
 	if len(initials) != 3 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"initials", initials, "exactly 3 characters"))
 		return
 	}
@@ -232,9 +921,9 @@ func (h *LeaderboardHandler) GetEnhancedPlayerStats(c *gin.Context) {
 	// Check if score history should be included
 	includeHistory := c.Query("include_history") == "true"
 
-	stats, err := h.service.GetEnhancedPlayerStats(c.Request.Context(), gameID, initials, includeHistory)
+	stats, err := h.service.GetEnhancedPlayerStats(playerContext(c), gameID, initials, includeHistory)
 	if err != nil {
-		c.JSON(http.StatusNotFound, NewStandardErrorResponse(
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
 			ErrorCodePlayerNotFound, "No stats found for this player",
 			map[string]interface{}{
 				"game_id":  gameID,
@@ -247,32 +936,57 @@ func (h *LeaderboardHandler) GetEnhancedPlayerStats(c *gin.Context) {
 }
 
 // GetScoreAnalysis handles GET /api/v1/games/:gameId/scores/analyze
+// @Summary Get a game's score analysis
+// @Tags scores
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param top_players query integer false "Top players to include (1-100, default 5)"
+// @Param offset query integer false "Offset into the ranked players (max 10000)"
+// @Success 200 {object} models.ScoreAnalysisResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or paging"
+// @Failure 404 {object} handlers.StandardErrorResponse "No score history for this game"
+// @Router /api/v1/games/{gameId}/scores/analyze [get]
 func (h *LeaderboardHandler) GetScoreAnalysis(c *gin.Context) {
 	gameID := c.Param("gameId")
 	if gameID == "" {
-		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
 			ErrorCodeInvalidGameID, "Game ID is required"))
 		return
 	}
 
 	// Validate gameID format
 	if len(gameID) > 50 || len(gameID) < 1 {
-		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
 			"gameId", gameID, "length between 1 and 50 characters"))
 		return
 	}
 
-	// Parse top players limit (default to 5, max 10)
+	// Parse top players limit (default to 5, max 100)
 	topPlayersLimit := 5
 	if limitStr := c.Query("top_players"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 && limit <= 10 {
-			topPlayersLimit = limit
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit <= 0 || limit > leaderboard.MaxTopPlayersLimit {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"top_players", limitStr, fmt.Sprintf("integer between 1 and %d", leaderboard.MaxTopPlayersLimit)))
+			return
+		}
+		topPlayersLimit = limit
+	}
+
+	// Parse top players page offset (default to 0)
+	offset := 0
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 || parsed > leaderboard.MaxAnalysisOffset {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"offset", offsetStr, fmt.Sprintf("integer between 0 and %d", leaderboard.MaxAnalysisOffset)))
+			return
 		}
+		offset = parsed
 	}
 
-	analysis, err := h.service.GetScoreAnalysis(c.Request.Context(), gameID, topPlayersLimit)
+	analysis, err := h.service.GetScoreAnalysisPage(c.Request.Context(), gameID, topPlayersLimit, offset)
 	if err != nil {
-		c.JSON(http.StatusNotFound, NewStandardErrorResponse(
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
 			ErrorCodeScoreHistoryEmpty, "No score analysis available for this game",
 			map[string]interface{}{"game_id": gameID}))
 		return
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"rawboard/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLogger returns the request-scoped logger attached by the logging middleware
+func requestLogger(c *gin.Context) *slog.Logger {
+	return logging.FromContext(c.Request.Context(), nil)
+}
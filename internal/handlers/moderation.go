@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteScore handles DELETE /api/v1/games/:gameId/scores?initials=AAA&timestamp=<RFC 3339>
+// The timestamp must match the score's stored timestamp exactly, as returned by /scores/all
+// @Summary Delete one score
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials query string true "Player initials"
+// @Param timestamp query string true "Exact RFC 3339 timestamp of the score, as returned by /scores/all"
+// @Success 200 {object} models.ModerationResult
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID, initials or timestamp"
+// @Failure 404 {object} handlers.StandardErrorResponse "No matching score"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId}/scores [delete]
+func (h *AdminHandler) DeleteScore(c *gin.Context) {
+	gameID, initials, ok := moderationTarget(c, c.Query("initials"))
+	if !ok {
+		return
+	}
+
+	raw := c.Query("timestamp")
+	timestamp, err := time.Parse(time.RFC3339Nano, raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"timestamp", raw, "RFC 3339 timestamp of the score, as returned by /scores/all"))
+		return
+	}
+
+	result, err := h.service.DeleteScore(c.Request.Context(), gameID, initials, timestamp)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeScoreNotFound, "No matching score found",
+			map[string]interface{}{"game_id": gameID, "initials": initials, "timestamp": raw}))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionScoreDeleted,
+		GameID:  gameID,
+		Details: map[string]interface{}{"initials": initials, "timestamp": raw, "removed": result.Removed, "tombstone_id": result.TombstoneID},
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// DeletePlayer handles DELETE /api/v1/games/:gameId/players/:initials
+// @Summary Delete every score for a player
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials path string true "Player initials"
+// @Success 200 {object} models.ModerationResult
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or initials"
+// @Failure 404 {object} handlers.StandardErrorResponse "Player not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId}/players/{initials} [delete]
+func (h *AdminHandler) DeletePlayer(c *gin.Context) {
+	gameID, initials, ok := moderationTarget(c, c.Param("initials"))
+	if !ok {
+		return
+	}
+
+	result, err := h.service.DeletePlayer(c.Request.Context(), gameID, initials)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodePlayerNotFound, "Player not found",
+			map[string]interface{}{"game_id": gameID, "initials": initials}))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionPlayerDeleted,
+		GameID:  gameID,
+		Details: map[string]interface{}{"initials": initials, "removed": result.Removed, "tombstone_id": result.TombstoneID},
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListTombstones handles GET /api/v1/admin/games/:gameId/tombstones
+// @Summary List moderation deletions that can be undone
+// @Description Lists the game's score and player deletions still within the undo window, newest first, with what each removed. Undo one with POST /api/v1/admin/games/{gameId}/restore.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.TombstonesResponse
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to read tombstones"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/tombstones [get]
+func (h *AdminHandler) ListTombstones(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	tombstones, err := h.service.Tombstones(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to read tombstones", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to read tombstones"))
+		return
+	}
+
+	c.JSON(http.StatusOK, tombstones)
+}
+
+// RestoreDeletion handles POST /api/v1/admin/games/:gameId/restore
+// @Summary Undo a score or player deletion
+// @Description Puts back what a moderation deletion removed, using the tombstone_id its response returned: the scores return to history, high scores the deletion replaced come back unless the player has since beaten them, a deleted player's achievements are unlocked again, and the leaderboard is regenerated. Deletions can be undone once, within the undo window (7 days by default).
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.RestoreDeletionRequest true "Deletion to undo"
+// @Success 200 {object} models.RestoreResult
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or request"
+// @Failure 404 {object} handlers.StandardErrorResponse "No such deletion, it was already undone, or its undo window has passed"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to restore the deletion"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/games/{gameId}/restore [post]
+func (h *AdminHandler) RestoreDeletion(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req RestoreDeletionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	result, err := h.service.RestoreDeletion(c.Request.Context(), gameID, req.TombstoneID)
+	if errors.Is(err, models.ErrTombstoneNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeTombstoneNotFound, "No deletion to undo: it is unknown, already undone or past its undo window",
+			map[string]interface{}{"game_id": gameID, "tombstone_id": req.TombstoneID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to restore deletion", "game_id", gameID, "tombstone_id", req.TombstoneID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to restore the deletion"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action: audit.ActionDeletionRestored,
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"initials":     result.Initials,
+			"tombstone_id": result.TombstoneID,
+			"restored":     result.Restored,
+		},
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RecomputePlayer handles POST /api/v1/games/:gameId/players/:initials/recompute
+// @Summary Rebuild a player's derived data from history
+// @Description Recomputes the player's high score from their counted scores in history and rebuilds the game's leaderboard, ranking and score index. A high score older than the retention window is replaced by the best score still in history.
+// @Tags moderation
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param initials path string true "Player initials"
+// @Success 200 {object} models.RecomputeResult
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or initials"
+// @Failure 404 {object} handlers.StandardErrorResponse "No scores in history for the player"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to save the rebuilt data"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/players/{initials}/recompute [post]
+func (h *AdminHandler) RecomputePlayer(c *gin.Context) {
+	gameID, initials, ok := moderationTarget(c, c.Param("initials"))
+	if !ok {
+		return
+	}
+
+	result, err := h.service.RecomputePlayer(c.Request.Context(), gameID, initials)
+	if errors.Is(err, leaderboard.ErrNoHistory) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodePlayerNotFound, "No scores in history for the player",
+			map[string]interface{}{"game_id": gameID, "initials": initials}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to recompute player", "game_id", gameID, "initials", initials, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to recompute player"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action: audit.ActionPlayerRecomputed,
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"initials":   initials,
+			"changed":    result.Changed,
+			"high_score": result.HighScore.Score,
+		},
+	})
+
+	c.JSON(http.StatusOK, result)
+}
+
+// moderationTarget validates the game ID and initials of a moderation request,
+// writing the error response and returning false if either is invalid
+func moderationTarget(c *gin.Context, initials string) (string, string, bool) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return "", "", false
+	}
+
+	initials = strings.ToUpper(strings.TrimSpace(initials))
+	if len(initials) != 3 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"initials", initials, "exactly 3 characters"))
+		return "", "", false
+	}
+
+	return gameID, initials, true
+}
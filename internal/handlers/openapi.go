@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+
+	"rawboard/internal/inbound"
+	"rawboard/internal/models"
+	"rawboard/internal/openapi"
+)
+
+// openAPIInfo describes the API in the generated document
+var openAPIInfo = openapi.Info{
+	Title:       "Rawboard Arcade API",
+	Description: "Arcade-style leaderboards with full score history, player statistics and admin tooling.",
+	Version:     "2.0.0",
+}
+
+// openAPIModels lists every type referenced by handler annotations
+var openAPIModels = []interface{}{
+	ScoreSubmissionRequest{},
+	ScoreSubmissionResponse{},
+	RestoreRequest{},
+	CreateAPIKeyRequest{},
+	UpdateGameQuotaRequest{},
+	UpdateRateLimitRequest{},
+	PINRequest{},
+	RequirePINRequest{},
+	GameSunsetRequest{},
+	InitialsPolicyRequest{},
+	HappyHoursRequest{},
+	PINVerification{},
+	AchievementRequest{},
+	AchievementBadgeRequest{},
+	AchievementThemeRequest{},
+	DevResetRequest{},
+	DrainRequest{},
+	MergeGameRequest{},
+	RestoreDeletionRequest{},
+	FlagScoreRequest{},
+	ResolveDisputeRequest{},
+	ResetGameRequest{},
+	StartSeasonRequest{},
+	DatasetRequest{},
+	RetentionPolicyRequest{},
+	LeaderboardSizeRequest{},
+	DailySubmissionsRequest{},
+	GameListResponse{},
+	ExportListResponse{},
+	APIKeyListResponse{},
+	CreateWebhookRequest{},
+	WebhookListResponse{},
+	WebhookLogResponse{},
+	AuditLogResponse{},
+	SimulateAntiCheatRequest{},
+	TournamentRequest{},
+	TournamentListResponse{},
+	DisplayRequest{},
+	DisplayListResponse{},
+	DisplayHeartbeatRequest{},
+	DisplayStatusResponse{},
+	DeviceRequest{},
+	DeviceListResponse{},
+	EnrollDeviceRequest{},
+	CreateProfileRequest{},
+	UpdateProfileRequest{},
+	DeadLetterListResponse{},
+	StandardErrorResponse{},
+	HealthResponse{},
+	models.Leaderboard{},
+	models.PlayerStats{},
+	models.PlayerRank{},
+	models.EnhancedPlayerStats{},
+	models.ScoreAnalysisResponse{},
+	models.AroundMeResponse{},
+	models.LeaderboardChanges{},
+	models.AllScoresRecord{},
+	models.Ranking{},
+	models.ImportReport{},
+	models.GameQuota{},
+	models.DuplicateGameReport{},
+	models.GameMerge{},
+	models.TombstonesResponse{},
+	models.RestoreResult{},
+	models.ScoreDispute{},
+	models.DisputesResponse{},
+	models.DisputeResolution{},
+	models.GameArchive{},
+	models.GameArchiveInfo{},
+	models.GameArchivesResponse{},
+	models.GameDeletion{},
+	models.GameSummary{},
+	models.PublicHistory{},
+	models.Season{},
+	models.GameSeasons{},
+	models.SeasonLeaderboard{},
+	models.ReceiptStatus{},
+	models.GameInfo{},
+	models.LeaderboardResize{},
+	models.ModerationResult{},
+	models.RecomputeResult{},
+	models.ExportManifest{},
+	models.DatasetManifest{},
+	models.RestoreReport{},
+	models.APIKey{},
+	models.CreatedAPIKey{},
+	models.StreamToken{},
+	models.AdminSession{},
+	models.SigningSecret{},
+	models.BootstrapDocument{},
+	models.BootstrapResult{},
+	models.ScoreQueryResponse{},
+	models.ScoreTimeseries{},
+	models.Webhook{},
+	models.CreatedWebhook{},
+	models.Tournament{},
+	models.TournamentStandings{},
+	models.Display{},
+	models.DisplayRotation{},
+	models.DisplayHeartbeat{},
+	models.Device{},
+	models.DeviceEnrollmentCode{},
+	models.EnrolledDevice{},
+	models.DeviceAnalytics{},
+	models.PlayerProfile{},
+	models.AchievementDefinition{},
+	models.DevReset{},
+	models.AchievementDefinitionList{},
+	models.WebhookDeadLetter{},
+	models.WebhookTestResult{},
+	models.BlocklistResponse{},
+	models.UsageReport{},
+	models.SelfCheckReport{},
+	models.ClockSkewReading{},
+	models.AntiCheatRules{},
+	models.HappyHour{},
+	models.ScoringSettings{},
+	models.FlaggedSubmissionsResponse{},
+	models.AntiCheatSimulation{},
+	models.ReadinessReport{},
+	models.DrainStatus{},
+	models.EdgeDeadLetters{},
+	models.EdgeReplay{},
+	inbound.SNSMessage{},
+}
+
+// OpenAPITypes maps the qualified type names used in annotations to their Go types
+func OpenAPITypes() map[string]reflect.Type {
+	types := make(map[string]reflect.Type, len(openAPIModels))
+	for _, model := range openAPIModels {
+		t := reflect.TypeOf(model)
+		types[t.String()] = t
+	}
+	return types
+}
+
+// GenerateOpenAPI builds the OpenAPI document from the annotated handlers in dir
+func GenerateOpenAPI(dir string) ([]byte, error) {
+	annotations, err := openapi.ParseDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := openapi.Generate(openAPIInfo, annotations, OpenAPITypes())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// getOpenAPISpec serves the generated OpenAPI document
+func getOpenAPISpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec)
+}
+
+// docsPage loads Swagger UI from a CDN and points it at the generated document
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Rawboard API Docs</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/api/v1/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// getDocs serves the interactive API documentation
+func getDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
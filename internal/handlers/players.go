@@ -0,0 +1,281 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PlayerHandler registers and serves player profiles
+type PlayerHandler struct {
+	service *leaderboard.Service
+	audit   *audit.Log
+}
+
+// NewPlayerHandler creates a new player profile handler
+func NewPlayerHandler(service *leaderboard.Service, auditLog *audit.Log) *PlayerHandler {
+	return &PlayerHandler{service: service, audit: auditLog}
+}
+
+// CreateProfile handles POST /api/v1/players
+// @Summary Register a player profile
+// @Description Claims initials across every game with a display name, avatar emoji and country, shown with the player's stats. The PIN, 4 to 8 digits, is needed to change the profile later and is only stored hashed. Rate limited per client IP.
+// @Tags players
+// @Param request body handlers.CreateProfileRequest true "Initials, PIN and profile"
+// @Success 201 {object} models.PlayerProfile
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid or blocked initials, PIN or profile"
+// @Failure 409 {object} handlers.StandardErrorResponse "The initials already have a profile"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many requests"
+// @Router /api/v1/players [post]
+func (h *PlayerHandler) CreateProfile(c *gin.Context) {
+	var req CreateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	profile, err := h.service.CreateProfile(c.Request.Context(), models.PlayerProfile{
+		Initials:    req.Initials,
+		DisplayName: req.DisplayName,
+		Avatar:      req.Avatar,
+		Country:     req.Country,
+	}, req.PIN)
+	if errors.Is(err, models.ErrBlockedInitials) {
+		blockedInitialsResponse(c, models.NormalizeInitials(req.Initials))
+		return
+	}
+	if errors.Is(err, leaderboard.ErrProfileExists) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeProfileExists, "These initials already have a profile",
+			map[string]interface{}{"initials": models.NormalizeInitials(req.Initials)}))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// GetProfile handles GET /api/v1/players/:initials
+// @Summary Get a player profile
+// @Tags players
+// @Param initials path string true "Player initials"
+// @Success 200 {object} models.PlayerProfile
+// @Failure 404 {object} handlers.StandardErrorResponse "No profile for these initials"
+// @Router /api/v1/players/{initials} [get]
+func (h *PlayerHandler) GetProfile(c *gin.Context) {
+	initials := c.Param("initials")
+
+	profile, err := h.service.Profile(c.Request.Context(), initials)
+	if errors.Is(err, leaderboard.ErrProfileNotFound) {
+		profileNotFoundResponse(c, initials)
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to get profile", "initials", initials, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to get profile"))
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile handles PUT /api/v1/players/:initials
+// @Summary Update a player profile
+// @Description Replaces the display name, avatar and country, given the profile's PIN. After 5 wrong PINs in a row the profile refuses every PIN for 15 minutes. Rate limited per client IP.
+// @Tags players
+// @Param initials path string true "Player initials"
+// @Param request body handlers.UpdateProfileRequest true "PIN and profile"
+// @Success 200 {object} models.PlayerProfile
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid profile"
+// @Failure 401 {object} handlers.StandardErrorResponse "Wrong PIN"
+// @Failure 404 {object} handlers.StandardErrorResponse "No profile for these initials"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many wrong PINs or requests"
+// @Router /api/v1/players/{initials} [put]
+func (h *PlayerHandler) UpdateProfile(c *gin.Context) {
+	initials := c.Param("initials")
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(c.Request.Context(), initials, req.PIN, models.PlayerProfile{
+		DisplayName: req.DisplayName,
+		Avatar:      req.Avatar,
+		Country:     req.Country,
+	})
+	if pinErrorResponse(c, initials, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// ClaimInitials handles POST /api/v1/players/:initials/claim
+// @Summary Claim initials with a PIN
+// @Description Registers a profile for the initials with only a PIN, displayed as the initials until updated. Games requiring PINs then refuse submissions under the initials without it. Rate limited per client IP.
+// @Tags players
+// @Param initials path string true "Player initials"
+// @Param request body handlers.PINRequest true "The PIN to claim them with"
+// @Success 201 {object} models.PlayerProfile
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid or blocked initials or PIN"
+// @Failure 409 {object} handlers.StandardErrorResponse "The initials are already claimed"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many requests"
+// @Router /api/v1/players/{initials}/claim [post]
+func (h *PlayerHandler) ClaimInitials(c *gin.Context) {
+	initials := c.Param("initials")
+
+	var req PINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	profile, err := h.service.ClaimInitials(c.Request.Context(), initials, req.PIN)
+	if errors.Is(err, models.ErrBlockedInitials) {
+		blockedInitialsResponse(c, models.NormalizeInitials(initials))
+		return
+	}
+	if errors.Is(err, leaderboard.ErrProfileExists) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeProfileExists, "These initials already have a profile",
+			map[string]interface{}{"initials": models.NormalizeInitials(initials)}))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// VerifyPIN handles POST /api/v1/players/:initials/verify
+// @Summary Check a player's PIN
+// @Description Lets a cabinet confirm a player's PIN before the game starts rather than when the score is submitted. Wrong PINs count towards the profile's lockout. Rate limited per client IP.
+// @Tags players
+// @Param initials path string true "Player initials"
+// @Param request body handlers.PINRequest true "The PIN to check"
+// @Success 200 {object} handlers.PINVerification
+// @Failure 401 {object} handlers.StandardErrorResponse "Wrong PIN"
+// @Failure 404 {object} handlers.StandardErrorResponse "The initials aren't claimed"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many wrong PINs or requests"
+// @Router /api/v1/players/{initials}/verify [post]
+func (h *PlayerHandler) VerifyPIN(c *gin.Context) {
+	initials := c.Param("initials")
+
+	var req PINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	profile, err := h.service.VerifyPIN(c.Request.Context(), initials, req.PIN)
+	if pinErrorResponse(c, initials, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to verify PIN", "initials", initials, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to verify PIN"))
+		return
+	}
+
+	c.JSON(http.StatusOK, PINVerification{Initials: profile.Initials, Verified: true})
+}
+
+// DeleteProfile handles DELETE /api/v1/admin/players/:initials
+// @Summary Delete a player profile
+// @Description For offensive names or forgotten PINs. The initials can be registered again; their scores are kept.
+// @Tags players
+// @Param initials path string true "Player initials"
+// @Success 200 {object} models.PlayerProfile "The deleted profile"
+// @Failure 404 {object} handlers.StandardErrorResponse "No profile for these initials"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/players/{initials} [delete]
+func (h *PlayerHandler) DeleteProfile(c *gin.Context) {
+	initials := c.Param("initials")
+
+	profile, err := h.service.DeleteProfile(c.Request.Context(), initials)
+	if errors.Is(err, leaderboard.ErrProfileNotFound) {
+		profileNotFoundResponse(c, initials)
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete profile", "initials", initials, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete profile"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionProfileDeleted,
+		Details: map[string]interface{}{"initials": profile.Initials, "display_name": profile.DisplayName},
+	})
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// profileNotFoundResponse responds with 404 PROFILE_NOT_FOUND
+func profileNotFoundResponse(c *gin.Context, initials string) {
+	c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+		ErrorCodeProfileNotFound, "No profile for these initials",
+		map[string]interface{}{"initials": models.NormalizeInitials(initials)}))
+}
+
+// pinErrorResponse responds to a missing profile or claim, missing or wrong PIN or locked
+// profile and returns true, or returns false for any other err
+func pinErrorResponse(c *gin.Context, initials string, err error) bool {
+	switch {
+	case errors.Is(err, leaderboard.ErrProfileNotFound):
+		profileNotFoundResponse(c, initials)
+	case errors.Is(err, leaderboard.ErrClaimRequired):
+		c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+			ErrorCodeClaimRequired, "This game only accepts claimed initials",
+			map[string]interface{}{"initials": models.NormalizeInitials(initials)}))
+	case errors.Is(err, leaderboard.ErrPINRequired):
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodePINRequired, "These initials are claimed; this game needs their PIN",
+			map[string]interface{}{"initials": models.NormalizeInitials(initials)}))
+	case errors.Is(err, leaderboard.ErrWrongPIN):
+		c.JSON(http.StatusUnauthorized, NewStandardErrorResponse(c,
+			ErrorCodeWrongPIN, "Wrong PIN",
+			map[string]interface{}{"initials": models.NormalizeInitials(initials)}))
+	case errors.Is(err, leaderboard.ErrProfileLocked):
+		c.Header("Retry-After", strconv.Itoa(int(models.PINLockout.Seconds())))
+		c.JSON(http.StatusTooManyRequests, NewStandardErrorResponse(c,
+			ErrorCodeProfileLocked, "Too many wrong PINs; try again later",
+			map[string]interface{}{"initials": models.NormalizeInitials(initials)}))
+	default:
+		return false
+	}
+	return true
+}
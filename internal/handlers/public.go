@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// publicCacheControl lets browsers and CDNs cache public widgets briefly
+const publicCacheControl = "public, max-age=60, stale-while-revalidate=300"
+
+// publicCORS opens public widget endpoints to any origin; they carry no credentials
+func publicCORS() gin.HandlerFunc {
+	return openCORS("GET, OPTIONS", "If-None-Match")
+}
+
+// openCORS lets any origin call an endpoint with methods, sending headers
+func openCORS(methods, headers string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Access-Control-Allow-Origin", "*")
+		c.Header("Access-Control-Allow-Methods", methods)
+		c.Header("Access-Control-Allow-Headers", headers)
+		c.Header("Access-Control-Expose-Headers", "ETag, X-Request-ID")
+		c.Header("Access-Control-Max-Age", "86400")
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// GetPublicSummary handles GET /public/games/:gameId/summary
+// @Summary Get a game's public summary
+// @Tags public
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameSummary "CORS-open and cacheable; supports If-None-Match"
+// @Success 304 "Summary unchanged since the given ETag"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "Game not found"
+// @Router /public/games/{gameId}/summary [get]
+func (h *LeaderboardHandler) GetPublicSummary(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	summary, err := h.service.GetGameSummary(c.Request.Context(), gameID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "No scores found for this game",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+
+	body, err := json.Marshal(summary)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to encode summary"))
+		return
+	}
+
+	writeConditionalJSON(c, body, time.Time{}, publicCacheControl)
+}
+
+// GetPublicHistory handles GET /public/games/:gameId/history
+// @Summary Get a game's public score history
+// @Description The game's most recent counted scores, newest first, with players identified by a public ID instead of their initials and timestamps truncated to the hour. IDs are stable for the same initials in the same game, so public analytics can count distinct players, but are salted per tenant and can't be reversed.
+// @Tags public
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param limit query integer false "Scores to return, default 50, up to 500"
+// @Success 200 {object} models.PublicHistory "CORS-open and cacheable"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or limit"
+// @Failure 404 {object} handlers.StandardErrorResponse "Game not found"
+// @Router /public/games/{gameId}/history [get]
+func (h *LeaderboardHandler) GetPublicHistory(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	limit := models.DefaultPublicHistoryLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 || parsed > models.MaxPublicHistoryLimit {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"limit", limitStr, fmt.Sprintf("integer between 1 and %d", models.MaxPublicHistoryLimit)))
+			return
+		}
+		limit = parsed
+	}
+
+	history, err := h.service.PublicHistory(c.Request.Context(), gameID, limit)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeGameNotFound, "No scores found for this game",
+			map[string]interface{}{"game_id": gameID}))
+		return
+	}
+
+	c.Header("Cache-Control", publicCacheControl)
+	c.JSON(http.StatusOK, history)
+}
+
+// GetReceipt handles GET /public/receipts/:token
+// @Summary Look up a score receipt
+// @Tags public
+// @Param token path string true "Receipt token from the score submission"
+// @Success 200 {object} models.ReceiptStatus
+// @Failure 404 {object} handlers.StandardErrorResponse "Receipt not found"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many lookups"
+// @Router /public/receipts/{token} [get]
+func (h *LeaderboardHandler) GetReceipt(c *gin.Context) {
+	token := c.Param("token")
+
+	status, err := h.service.LookupReceipt(c.Request.Context(), token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeReceiptNotFound, "Receipt not found"))
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, status)
+}
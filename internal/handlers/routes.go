@@ -4,7 +4,19 @@ import (
 	"net/http"
 	"time"
 
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/broadcast"
+	"rawboard/internal/devices"
+	"rawboard/internal/displays"
+	"rawboard/internal/edge"
+	"rawboard/internal/export"
+	"rawboard/internal/inbound"
 	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/selfcheck"
+	"rawboard/internal/tournaments"
+	"rawboard/internal/webhooks"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,6 +27,9 @@ var startTime = time.Now()
 func SetupRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, apiKeyMiddleware gin.HandlerFunc) {
 	leaderboardHandler := NewLeaderboardHandler(leaderboardService)
 
+	// Interactive API documentation (public)
+	r.GET("/docs", getDocs)
+
 	// API v1 routes
 	v1 := r.Group("/api/v1")
 	{
@@ -32,6 +47,9 @@ func SetupRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, apiKeyM
 			})
 		})
 
+		// Generated OpenAPI document (public)
+		v1.GET("/openapi.json", getOpenAPISpec)
+
 		// Game routes
 		games := v1.Group("/games")
 		{
@@ -40,18 +58,330 @@ func SetupRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, apiKeyM
 			games.GET("/:gameId/players/:initials/stats", leaderboardHandler.GetPlayerStats)                  // GET /api/v1/games/:gameId/players/:initials/stats
 			games.GET("/:gameId/players/:initials/stats/enhanced", leaderboardHandler.GetEnhancedPlayerStats) // GET /api/v1/games/:gameId/players/:initials/stats/enhanced
 			games.GET("/:gameId/scores/analyze", leaderboardHandler.GetScoreAnalysis)                         // GET /api/v1/games/:gameId/scores/analyze
+			games.GET("/:gameId/players/:initials/rank", leaderboardHandler.GetPlayerRank)                    // GET /api/v1/games/:gameId/players/:initials/rank
+			games.GET("/:gameId/leaderboard/around/:initials", leaderboardHandler.GetLeaderboardAround)       // GET /api/v1/games/:gameId/leaderboard/around/:initials
+			games.GET("/:gameId/leaderboard/changes", leaderboardHandler.GetLeaderboardChanges)               // GET /api/v1/games/:gameId/leaderboard/changes
+			games.GET("/:gameId/seasons", leaderboardHandler.ListSeasons)                                     // GET /api/v1/games/:gameId/seasons
+			games.GET("/:gameId/seasons/:seasonId/leaderboard", leaderboardHandler.GetSeasonLeaderboard)      // GET /api/v1/games/:gameId/seasons/:seasonId/leaderboard
+			games.GET("/:gameId/achievements/:achievementId/badge", leaderboardHandler.GetAchievementBadge)   // GET /api/v1/games/:gameId/achievements/:achievementId/badge
 
 			// Protected endpoints (API key required)
 			protected := games.Group("")
 			protected.Use(apiKeyMiddleware)
 			{
-				protected.POST("/:gameId/scores", leaderboardHandler.SubmitScore)     // POST /api/v1/games/:gameId/scores
-				protected.GET("/:gameId/scores/all", leaderboardHandler.GetAllScores) // GET /api/v1/games/:gameId/scores/all (admin)
+				protected.POST("/:gameId/scores", requireScope(models.ScopeSubmit), leaderboardHandler.SubmitScore)                                                    // POST /api/v1/games/:gameId/scores
+				protected.GET("/:gameId/scores/all", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminRead), leaderboardHandler.GetAllScores)              // GET /api/v1/games/:gameId/scores/all (admin)
+				protected.GET("/:gameId/scores/all/export", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminRead), leaderboardHandler.ExportScores)       // GET /api/v1/games/:gameId/scores/all/export (admin)
+				protected.GET("/:gameId/leaderboard/export", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminRead), leaderboardHandler.ExportLeaderboard) // GET /api/v1/games/:gameId/leaderboard/export (admin)
+				protected.GET("/:gameId/scores", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminRead), leaderboardHandler.QueryScores)                   // GET /api/v1/games/:gameId/scores (admin)
+				protected.GET("/:gameId/scores/timeseries", requireScope(models.ScopeAdminRead), leaderboardHandler.GetScoreTimeseries)                                // GET /api/v1/games/:gameId/scores/timeseries (admin)
 			}
 		}
 	}
 }
 
+// SetupPublicRoutes configures the keyless, CORS-open routes for widgets and players,
+// and the share pages players post links to
+// lookupRateLimit throttles receipt lookups so tokens can't be enumerated
+func SetupPublicRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, lookupRateLimit gin.HandlerFunc) {
+	leaderboardHandler := NewLeaderboardHandler(leaderboardService)
+
+	public := r.Group("/public")
+	public.Use(publicCORS())
+	{
+		public.GET("/games/:gameId/summary", leaderboardHandler.GetPublicSummary)      // GET /public/games/:gameId/summary
+		public.OPTIONS("/games/:gameId/summary", leaderboardHandler.GetPublicSummary)  // CORS preflight
+		public.GET("/games/:gameId/history", leaderboardHandler.GetPublicHistory)      // GET /public/games/:gameId/history
+		public.OPTIONS("/games/:gameId/history", leaderboardHandler.GetPublicHistory)  // CORS preflight
+		public.GET("/receipts/:token", lookupRateLimit, leaderboardHandler.GetReceipt) // GET /public/receipts/:token
+	}
+
+	share := r.Group("/share")
+	{
+		share.GET("/:receiptToken", lookupRateLimit, leaderboardHandler.GetSharePage)          // GET /share/:receiptToken
+		share.GET("/:receiptToken/card.png", lookupRateLimit, leaderboardHandler.GetShareCard) // GET /share/:receiptToken/card.png
+	}
+}
+
+// SetupAdminRoutes configures the operator-only admin API
+// Export and restore routes are only registered when an exporter is provided,
+// and the self-check report only when a checker is
+func SetupAdminRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, exporter *export.Exporter, auditLog *audit.Log, keys *apikeys.Store, usage *audit.UsageTracker, checker *selfcheck.Checker, apiKeyMiddleware gin.HandlerFunc) {
+	adminHandler := NewAdminHandler(leaderboardService, exporter, auditLog, keys, usage, checker)
+	read := requireScope(models.ScopeAdminRead)
+	write := requireScope(models.ScopeAdminWrite)
+
+	admin := r.Group("/api/v1/admin")
+	admin.Use(apiKeyMiddleware)
+	{
+		admin.GET("/games", read, adminHandler.ListGames)                                                                                           // GET /api/v1/admin/games
+		admin.GET("/games/duplicates", read, adminHandler.FindDuplicateGames)                                                                       // GET /api/v1/admin/games/duplicates
+		admin.GET("/games/:gameId", read, adminHandler.GetGame)                                                                                     // GET /api/v1/admin/games/:gameId
+		admin.PUT("/games/:gameId/retention", write, adminHandler.UpdateRetention)                                                                  // PUT /api/v1/admin/games/:gameId/retention
+		admin.DELETE("/games/:gameId/retention", requireRole(models.RoleAdmin), write, adminHandler.ResetRetention)                                 // DELETE /api/v1/admin/games/:gameId/retention
+		admin.PUT("/games/:gameId/leaderboard-size", write, adminHandler.UpdateLeaderboardSize)                                                     // PUT /api/v1/admin/games/:gameId/leaderboard-size
+		admin.PUT("/games/:gameId/daily-submissions", write, adminHandler.UpdateDailySubmissions)                                                   // PUT /api/v1/admin/games/:gameId/daily-submissions
+		admin.PUT("/games/:gameId/anti-cheat", write, adminHandler.UpdateAntiCheat)                                                                 // PUT /api/v1/admin/games/:gameId/anti-cheat
+		admin.POST("/games/:gameId/anti-cheat/simulate", read, adminHandler.SimulateAntiCheat)                                                      // POST /api/v1/admin/games/:gameId/anti-cheat/simulate
+		admin.PUT("/games/:gameId/scoring", write, adminHandler.UpdateScoring)                                                                      // PUT /api/v1/admin/games/:gameId/scoring
+		admin.PUT("/games/:gameId/require-pin", write, adminHandler.UpdateRequirePIN)                                                               // PUT /api/v1/admin/games/:gameId/require-pin
+		admin.PUT("/games/:gameId/initials-policy", write, adminHandler.UpdateInitialsPolicy)                                                       // PUT /api/v1/admin/games/:gameId/initials-policy
+		admin.PUT("/games/:gameId/happy-hours", write, adminHandler.UpdateHappyHours)                                                               // PUT /api/v1/admin/games/:gameId/happy-hours
+		admin.PUT("/games/:gameId/sunset", write, adminHandler.UpdateSunset)                                                                        // PUT /api/v1/admin/games/:gameId/sunset
+		admin.DELETE("/games/:gameId/sunset", requireRole(models.RoleAdmin), write, adminHandler.ClearSunset)                                       // DELETE /api/v1/admin/games/:gameId/sunset
+		admin.POST("/games/:gameId/signing-secret", write, adminHandler.CreateSigningSecret)                                                        // POST /api/v1/admin/games/:gameId/signing-secret
+		admin.DELETE("/games/:gameId/signing-secret", requireRole(models.RoleAdmin), write, adminHandler.DeleteSigningSecret)                       // DELETE /api/v1/admin/games/:gameId/signing-secret
+		admin.POST("/games/:gameId/merge", requireRole(models.RoleAdmin), write, adminHandler.MergeGame)                                            // POST /api/v1/admin/games/:gameId/merge
+		admin.GET("/games/:gameId/flagged", read, adminHandler.GetFlaggedSubmissions)                                                               // GET /api/v1/admin/games/:gameId/flagged
+		admin.GET("/games/:gameId/tombstones", read, adminHandler.ListTombstones)                                                                   // GET /api/v1/admin/games/:gameId/tombstones
+		admin.POST("/games/:gameId/restore", requireRole(models.RoleAdmin), write, adminHandler.RestoreDeletion)                                    // POST /api/v1/admin/games/:gameId/restore
+		admin.GET("/games/:gameId/archives", read, adminHandler.ListArchives)                                                                       // GET /api/v1/admin/games/:gameId/archives
+		admin.GET("/games/:gameId/archives/:archiveId", requireRole(models.RoleAdmin), read, adminHandler.GetArchive)                               // GET /api/v1/admin/games/:gameId/archives/:archiveId
+		admin.GET("/games/:gameId/disputes", read, adminHandler.ListDisputes)                                                                       // GET /api/v1/admin/games/:gameId/disputes
+		admin.POST("/games/:gameId/disputes/:scoreId/resolve", requireRole(models.RoleAdmin), write, adminHandler.ResolveDispute)                   // POST /api/v1/admin/games/:gameId/disputes/:scoreId/resolve
+		admin.GET("/games/:gameId/devices", read, adminHandler.GetDeviceAnalytics)                                                                  // GET /api/v1/admin/games/:gameId/devices
+		admin.GET("/games/:gameId/achievements", read, adminHandler.ListAchievements)                                                               // GET /api/v1/admin/games/:gameId/achievements
+		admin.PUT("/games/:gameId/achievements/:achievementId", write, adminHandler.PutAchievement)                                                 // PUT /api/v1/admin/games/:gameId/achievements/:achievementId
+		admin.DELETE("/games/:gameId/achievements/:achievementId", requireRole(models.RoleAdmin), write, adminHandler.DeleteAchievement)            // DELETE /api/v1/admin/games/:gameId/achievements/:achievementId
+		admin.PUT("/games/:gameId/achievements/:achievementId/badge", write, adminHandler.PutAchievementBadge)                                      // PUT /api/v1/admin/games/:gameId/achievements/:achievementId/badge
+		admin.DELETE("/games/:gameId/achievements/:achievementId/badge", requireRole(models.RoleAdmin), write, adminHandler.DeleteAchievementBadge) // DELETE /api/v1/admin/games/:gameId/achievements/:achievementId/badge
+		admin.PUT("/games/:gameId/achievement-theme", write, adminHandler.UpdateAchievementTheme)                                                   // PUT /api/v1/admin/games/:gameId/achievement-theme
+		admin.GET("/usage", read, adminHandler.GetUsage)                                                                                            // GET /api/v1/admin/usage
+		admin.GET("/audit", read, adminHandler.GetAuditLog)                                                                                         // GET /api/v1/admin/audit
+		admin.GET("/blocklist", read, adminHandler.GetBlocklist)                                                                                    // GET /api/v1/admin/blocklist
+		admin.PUT("/blocklist/:initials", write, adminHandler.BlockInitials)                                                                        // PUT /api/v1/admin/blocklist/:initials
+		admin.DELETE("/blocklist/:initials", requireRole(models.RoleAdmin), write, adminHandler.UnblockInitials)                                    // DELETE /api/v1/admin/blocklist/:initials
+
+		if checker != nil {
+			admin.GET("/selfcheck", read, adminHandler.GetSelfCheck)  // GET /api/v1/admin/selfcheck
+			admin.GET("/clock-skew", read, adminHandler.GetClockSkew) // GET /api/v1/admin/clock-skew
+		}
+
+		if exporter != nil {
+			admin.GET("/exports", read, adminHandler.ListExports)         // GET /api/v1/admin/exports
+			admin.POST("/exports", write, adminHandler.CreateExport)      // POST /api/v1/admin/exports
+			admin.GET("/exports/:exportId", read, adminHandler.GetExport) // GET /api/v1/admin/exports/:exportId
+			admin.POST("/restore", write, adminHandler.Restore)           // POST /api/v1/admin/restore
+			admin.POST("/datasets", write, adminHandler.CreateDataset)    // POST /api/v1/admin/datasets
+		}
+
+		// Key management is limited to the master key so scoped keys can't escalate
+		keyAdmin := admin.Group("/keys")
+		keyAdmin.Use(requireMaster())
+		{
+			keyAdmin.GET("", adminHandler.ListAPIKeys)                       // GET /api/v1/admin/keys
+			keyAdmin.POST("", adminHandler.CreateAPIKey)                     // POST /api/v1/admin/keys
+			keyAdmin.DELETE("/:keyId", adminHandler.RevokeAPIKey)            // DELETE /api/v1/admin/keys/:keyId
+			keyAdmin.GET("/:keyId/game-quota", adminHandler.GetGameQuota)    // GET /api/v1/admin/keys/:keyId/game-quota
+			keyAdmin.PUT("/:keyId/game-quota", adminHandler.UpdateGameQuota) // PUT /api/v1/admin/keys/:keyId/game-quota
+			keyAdmin.PUT("/:keyId/rate-limit", adminHandler.UpdateRateLimit) // PUT /api/v1/admin/keys/:keyId/rate-limit
+		}
+		admin.PUT("/bootstrap", requireMaster(), adminHandler.Bootstrap) // PUT /api/v1/admin/bootstrap
+	}
+
+	// Moderation, imports and seasons sit beside the game's public routes and need admin:write for that game
+	moderation := r.Group("/api/v1/games/:gameId")
+	moderation.Use(apiKeyMiddleware, write)
+	{
+		moderation.DELETE("/scores", requireRole(models.RoleAdmin), adminHandler.DeleteScore)             // DELETE /api/v1/games/:gameId/scores
+		moderation.DELETE("/players/:initials", requireRole(models.RoleAdmin), adminHandler.DeletePlayer) // DELETE /api/v1/games/:gameId/players/:initials
+		moderation.POST("/players/:initials/recompute", adminHandler.RecomputePlayer)                     // POST /api/v1/games/:gameId/players/:initials/recompute
+		moderation.POST("/reset", requireRole(models.RoleAdmin), adminHandler.ResetGame)                  // POST /api/v1/games/:gameId/reset
+		moderation.DELETE("", requireRole(models.RoleAdmin), adminHandler.DeleteGame)                     // DELETE /api/v1/games/:gameId
+		moderation.POST("/import", requireRole(models.RoleAdmin), adminHandler.ImportScores)              // POST /api/v1/games/:gameId/import
+		moderation.POST("/seasons", adminHandler.StartSeason)                                             // POST /api/v1/games/:gameId/seasons
+		moderation.POST("/seasons/:seasonId/end", adminHandler.EndSeason)                                 // POST /api/v1/games/:gameId/seasons/:seasonId/end
+	}
+}
+
+// SetupWebhookRoutes configures webhook management beside each game's routes
+func SetupWebhookRoutes(r *gin.Engine, store *webhooks.Store, dispatcher *webhooks.Dispatcher, auditLog *audit.Log, apiKeyMiddleware gin.HandlerFunc) {
+	webhookHandler := NewWebhookHandler(store, dispatcher, auditLog)
+
+	hooks := r.Group("/api/v1/games/:gameId/webhooks")
+	hooks.Use(apiKeyMiddleware)
+	{
+		hooks.GET("", requireScope(models.ScopeAdminRead), webhookHandler.ListWebhooks)                                                // GET /api/v1/games/:gameId/webhooks
+		hooks.POST("", requireScope(models.ScopeAdminWrite), webhookHandler.CreateWebhook)                                             // POST /api/v1/games/:gameId/webhooks
+		hooks.GET("/dead-letters", requireScope(models.ScopeAdminRead), webhookHandler.ListDeadLetters)                                // GET /api/v1/games/:gameId/webhooks/dead-letters
+		hooks.DELETE("/:webhookId", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminWrite), webhookHandler.DeleteWebhook) // DELETE /api/v1/games/:gameId/webhooks/:webhookId
+		hooks.POST("/:webhookId/test", requireScope(models.ScopeAdminWrite), webhookHandler.TestWebhook)                               // POST /api/v1/games/:gameId/webhooks/:webhookId/test
+	}
+}
+
+// SetupDisplayRoutes configures the public rotation feed and heartbeats for venue
+// displays, and their management under the admin API
+func SetupDisplayRoutes(r *gin.Engine, store *displays.Store, monitor *displays.Monitor, auditLog *audit.Log, apiKeyMiddleware gin.HandlerFunc) {
+	displayHandler := NewDisplayHandler(store, monitor, auditLog)
+
+	feed := r.Group("/api/v1/displays/:displayId")
+	{
+		feed.GET("/rotation", publicCORS(), displayHandler.GetRotation)                                 // GET /api/v1/displays/:displayId/rotation
+		feed.OPTIONS("/rotation", publicCORS(), displayHandler.GetRotation)                             // CORS preflight
+		feed.POST("/heartbeat", openCORS("POST, OPTIONS", "Content-Type"), displayHandler.Heartbeat)    // POST /api/v1/displays/:displayId/heartbeat
+		feed.OPTIONS("/heartbeat", openCORS("POST, OPTIONS", "Content-Type"), displayHandler.Heartbeat) // CORS preflight
+	}
+
+	admin := r.Group("/api/v1/admin/displays")
+	admin.Use(apiKeyMiddleware)
+	{
+		admin.GET("", requireScope(models.ScopeAdminRead), displayHandler.ListDisplays)                                                                        // GET /api/v1/admin/displays
+		admin.GET("/status", requireScope(models.ScopeAdminRead), displayHandler.ListDisplayStatus)                                                            // GET /api/v1/admin/displays/status
+		admin.PUT("/:displayId", requireScope(models.ScopeAdminWrite), displayHandler.PutDisplay)                                                              // PUT /api/v1/admin/displays/:displayId
+		admin.DELETE("/:displayId", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminWrite), displayHandler.DeleteDisplay)                         // DELETE /api/v1/admin/displays/:displayId
+		admin.DELETE("/:displayId/devices/:deviceId", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminWrite), displayHandler.RemoveDisplayDevice) // DELETE /api/v1/admin/displays/:displayId/devices/:deviceId
+	}
+}
+
+// SetupTournamentRoutes configures the public tournament standings, and tournament
+// management under the admin API
+func SetupTournamentRoutes(r *gin.Engine, service *tournaments.Service, auditLog *audit.Log, apiKeyMiddleware gin.HandlerFunc) {
+	tournamentHandler := NewTournamentHandler(service, auditLog)
+
+	r.GET("/api/v1/tournaments/:tournamentId/standings", tournamentHandler.GetStandings) // GET /api/v1/tournaments/:tournamentId/standings
+
+	admin := r.Group("/api/v1/admin/tournaments")
+	admin.Use(apiKeyMiddleware)
+	{
+		admin.GET("", requireScope(models.ScopeAdminRead), tournamentHandler.ListTournaments)                                                   // GET /api/v1/admin/tournaments
+		admin.PUT("/:tournamentId", requireScope(models.ScopeAdminWrite), tournamentHandler.PutTournament)                                      // PUT /api/v1/admin/tournaments/:tournamentId
+		admin.DELETE("/:tournamentId", requireRole(models.RoleAdmin), requireScope(models.ScopeAdminWrite), tournamentHandler.DeleteTournament) // DELETE /api/v1/admin/tournaments/:tournamentId
+	}
+}
+
+// SetupDeviceRoutes configures cabinet enrollment, rate limited per client IP by
+// limiter, and device management under the admin API
+func SetupDeviceRoutes(r *gin.Engine, service *devices.Service, auditLog *audit.Log, apiKeyMiddleware, limiter gin.HandlerFunc) {
+	deviceHandler := NewDeviceHandler(service, auditLog)
+
+	r.POST("/api/v1/devices/enroll", limiter, deviceHandler.EnrollDevice) // POST /api/v1/devices/enroll
+
+	// Devices are issued keys, so like keys they're managed by the master key only
+	admin := r.Group("/api/v1/admin/devices")
+	admin.Use(apiKeyMiddleware, requireMaster())
+	{
+		admin.GET("", deviceHandler.ListDevices)               // GET /api/v1/admin/devices
+		admin.POST("", deviceHandler.CreateDevice)             // POST /api/v1/admin/devices
+		admin.DELETE("/:deviceId", deviceHandler.RevokeDevice) // DELETE /api/v1/admin/devices/:deviceId
+	}
+}
+
+// SetupPlayerRoutes configures player profiles, with registration, claims and
+// PIN-checked requests rate limited per client IP by limiter, and their moderation under
+// the admin API. Flagging suspicious scores is limited the same way.
+func SetupPlayerRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, auditLog *audit.Log, apiKeyMiddleware, limiter gin.HandlerFunc) {
+	playerHandler := NewPlayerHandler(leaderboardService, auditLog)
+
+	players := r.Group("/api/v1/players")
+	{
+		players.POST("", limiter, playerHandler.CreateProfile)                 // POST /api/v1/players
+		players.GET("/:initials", playerHandler.GetProfile)                    // GET /api/v1/players/:initials
+		players.PUT("/:initials", limiter, playerHandler.UpdateProfile)        // PUT /api/v1/players/:initials
+		players.POST("/:initials/claim", limiter, playerHandler.ClaimInitials) // POST /api/v1/players/:initials/claim
+		players.POST("/:initials/verify", limiter, playerHandler.VerifyPIN)    // POST /api/v1/players/:initials/verify
+	}
+
+	// Anyone can flag a suspicious score for operators to review
+	r.POST("/api/v1/games/:gameId/scores/:scoreId/flag", limiter, playerHandler.FlagScore) // POST /api/v1/games/:gameId/scores/:scoreId/flag
+
+	r.DELETE("/api/v1/admin/players/:initials", apiKeyMiddleware, requireRole(models.RoleAdmin), requireScope(models.ScopeAdminWrite), playerHandler.DeleteProfile) // DELETE /api/v1/admin/players/:initials
+}
+
+// SetupDrainRoutes configures draining this instance out of load balancer rotation.
+// It affects every tenant served here, so it takes the master key.
+func SetupDrainRoutes(r *gin.Engine, drain *selfcheck.Drain, auditLog *audit.Log, apiKeyMiddleware gin.HandlerFunc) {
+	drainHandler := NewDrainHandler(drain, auditLog)
+
+	admin := r.Group("/api/v1/admin/drain")
+	admin.Use(apiKeyMiddleware, requireMaster())
+	{
+		admin.GET("", drainHandler.GetDrain)     // GET /api/v1/admin/drain
+		admin.POST("", drainHandler.StartDrain)  // POST /api/v1/admin/drain
+		admin.DELETE("", drainHandler.StopDrain) // DELETE /api/v1/admin/drain
+	}
+}
+
+// SetupEdgeRoutes configures looking into and replaying the submissions an edge
+// instance's central instance refused. They span every tenant, so they take the master
+// key.
+func SetupEdgeRoutes(r *gin.Engine, mirror *edge.Mirror, auditLog *audit.Log, apiKeyMiddleware gin.HandlerFunc) {
+	edgeHandler := NewEdgeHandler(mirror, auditLog)
+
+	admin := r.Group("/api/v1/admin/edge/dead-letters")
+	admin.Use(apiKeyMiddleware, requireMaster())
+	{
+		admin.GET("", edgeHandler.GetDeadLetters)                    // GET /api/v1/admin/edge/dead-letters
+		admin.POST("/replay", edgeHandler.ReplayDeadLetters)         // POST /api/v1/admin/edge/dead-letters/replay
+		admin.POST("/:scoreId/replay", edgeHandler.ReplayDeadLetter) // POST /api/v1/admin/edge/dead-letters/:scoreId/replay
+	}
+}
+
+// SetupSessionRoutes configures signing the admin UI in and out. Signing out needs no
+// key, so an expired session can still be cleared.
+func SetupSessionRoutes(r *gin.Engine, sessions *apikeys.Sessions, auditLog *audit.Log, apiKeyMiddleware gin.HandlerFunc) {
+	sessionHandler := NewSessionHandler(sessions, auditLog)
+
+	session := r.Group("/api/v1/admin/session")
+	{
+		session.GET("", apiKeyMiddleware, sessionHandler.GetSession)     // GET /api/v1/admin/session
+		session.POST("", apiKeyMiddleware, sessionHandler.CreateSession) // POST /api/v1/admin/session
+		session.DELETE("", sessionHandler.DeleteSession)                 // DELETE /api/v1/admin/session
+	}
+}
+
+// SetupDevRoutes configures the development reset for servers on the in-memory
+// database, which wipe empties. It wipes every tenant, so it takes the master key.
+func SetupDevRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, wipe func(), apiKeyMiddleware gin.HandlerFunc) {
+	devHandler := NewDevHandler(leaderboardService, wipe)
+
+	r.POST("/api/v1/dev/reset", apiKeyMiddleware, requireMaster(), devHandler.Reset) // POST /api/v1/dev/reset
+}
+
+// SetupWebhookLogRoutes configures the development webhook delivery log. Keys with the
+// admin:read scope read it for their games; the handler checks both, since the game is
+// a query parameter.
+func SetupWebhookLogRoutes(r *gin.Engine, log *webhooks.DeliveryLog, apiKeyMiddleware gin.HandlerFunc) {
+	webhookLogHandler := NewWebhookLogHandler(log)
+
+	r.GET("/api/v1/dev/webhook-log", apiKeyMiddleware, webhookLogHandler.WebhookLog) // GET /api/v1/dev/webhook-log
+}
+
+// SetupStreamRoutes configures the live leaderboard streams for display clients, which
+// authenticate with an API key or a stream token minted by the game's key
+func SetupStreamRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, hub *broadcast.Hub, tokens *apikeys.StreamTokens, apiKeyMiddleware, streamAuth gin.HandlerFunc) {
+	streamHandler := NewStreamHandler(leaderboardService, hub, tokens)
+
+	games := r.Group("/api/v1/games")
+	{
+		games.GET("/:gameId/events", streamAuth, streamHandler.StreamEvents)                                         // GET /api/v1/games/:gameId/events (SSE)
+		games.GET("/:gameId/ws", streamAuth, streamHandler.StreamWebSocket)                                          // GET /api/v1/games/:gameId/ws (WebSocket)
+		games.POST("/:gameId/stream-tokens", apiKeyMiddleware, requireGameAccess(), streamHandler.CreateStreamToken) // POST /api/v1/games/:gameId/stream-tokens
+	}
+}
+
+// SetupEmailRoutes configures the inbound email webhooks for each configured provider.
+// Providers can't send API keys, so each webhook authenticates its provider itself.
+func SetupEmailRoutes(r *gin.Engine, leaderboardService *leaderboard.Service, config inbound.Config) {
+	emailHandler := NewEmailHandler(leaderboardService, config)
+
+	email := r.Group("/api/v1/inbound/email")
+	{
+		if config.MailgunSigningKey != "" {
+			email.POST("/mailgun", emailHandler.ReceiveMailgun) // POST /api/v1/inbound/email/mailgun
+		}
+		if config.SESSecret != "" {
+			email.POST("/ses", emailHandler.ReceiveSES) // POST /api/v1/inbound/email/ses
+		}
+	}
+}
+
+// SetupGRPCWebRoutes routes gRPC-Web calls and their CORS preflights for service to
+// grpcWeb, which authenticates protected methods itself from request metadata
+func SetupGRPCWebRoutes(r *gin.Engine, service string, grpcWeb gin.HandlerFunc) {
+	r.POST("/"+service+"/:method", grpcWeb)    // POST /rawboard.v1.LeaderboardService/:method
+	r.OPTIONS("/"+service+"/:method", grpcWeb) // CORS preflight
+}
+
 func welcomeHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message":     "Welcome to Rawboard Arcade API!",
@@ -62,11 +392,42 @@ func welcomeHandler(c *gin.Context) {
 		"endpoints": gin.H{
 			"health":                    "/health",
 			"submit_score":              "POST /api/v1/games/:gameId/scores (API key required)",
-			"get_leaderboard":           "GET /api/v1/games/:gameId/leaderboard (public)",
+			"get_leaderboard":           "GET /api/v1/games/:gameId/leaderboard?limit=10 (public)",
 			"get_player_stats":          "GET /api/v1/games/:gameId/players/:initials/stats (public)",
 			"get_enhanced_player_stats": "GET /api/v1/games/:gameId/players/:initials/stats/enhanced (public)",
 			"get_score_analysis":        "GET /api/v1/games/:gameId/scores/analyze (public)",
+			"get_player_rank":           "GET /api/v1/games/:gameId/players/:initials/rank (public)",
+			"get_leaderboard_around":    "GET /api/v1/games/:gameId/leaderboard/around/:initials?window=3 (public)",
+			"list_seasons":              "GET /api/v1/games/:gameId/seasons (public)",
+			"get_season_leaderboard":    "GET /api/v1/games/:gameId/seasons/:seasonId/leaderboard?limit= (public)",
+			"manage_seasons":            "POST /api/v1/games/:gameId/seasons, POST /api/v1/games/:gameId/seasons/:seasonId/end (API key required, admin)",
 			"get_all_scores":            "GET /api/v1/games/:gameId/scores/all (API key required, admin)",
+			"query_scores":              "GET /api/v1/games/:gameId/scores?min=&max=&from=&to=&limit=50&offset=0 (API key required, admin)",
+			"delete_score":              "DELETE /api/v1/games/:gameId/scores?initials=AAA&timestamp=... (API key required, admin)",
+			"delete_player":             "DELETE /api/v1/games/:gameId/players/:initials (API key required, admin)",
+			"undo_deletion":             "GET /api/v1/admin/games/:gameId/tombstones, POST /api/v1/admin/games/:gameId/restore (API key required, admin)",
+			"flag_score":                "POST /api/v1/games/:gameId/scores/:scoreId/flag (public, rate limited)",
+			"reset_game":                "POST /api/v1/games/:gameId/reset, GET /api/v1/admin/games/:gameId/archives[/:archiveId] (API key required, admin)",
+			"delete_game":               "DELETE /api/v1/games/:gameId?confirm=<token> (API key required, admin)",
+			"review_disputes":           "GET /api/v1/admin/games/:gameId/disputes, POST /api/v1/admin/games/:gameId/disputes/:scoreId/resolve (API key required, admin)",
+			"recompute_player":          "POST /api/v1/games/:gameId/players/:initials/recompute (API key required, admin)",
+			"import_scores":             "POST /api/v1/games/:gameId/import?format=csv|json&mode=replace|append&dry_run=true (API key required, admin)",
+			"manage_webhooks":           "GET|POST /api/v1/games/:gameId/webhooks, DELETE /api/v1/games/:gameId/webhooks/:webhookId, POST /api/v1/games/:gameId/webhooks/:webhookId/test, GET /api/v1/games/:gameId/webhooks/dead-letters (API key required, admin)",
+			"stream_events":             "GET /api/v1/games/:gameId/events?since=<version>&token=<stream token> (API key or stream token, server-sent events)",
+			"stream_websocket":          "GET /api/v1/games/:gameId/ws?since=<version>&token=<stream token> (API key or stream token, WebSocket)",
+			"create_stream_token":       "POST /api/v1/games/:gameId/stream-tokens (API key required)",
+			"get_public_summary":        "GET /public/games/:gameId/summary (public, CORS)",
+			"get_display_rotation":      "GET /api/v1/displays/:displayId/rotation?device_id= (public, CORS)",
+			"get_tournament_standings":  "GET /api/v1/tournaments/:tournamentId/standings?limit= (public)",
+			"display_heartbeat":         "POST /api/v1/displays/:displayId/heartbeat (public, CORS)",
+			"lookup_receipt":            "GET /public/receipts/:token (public, rate limited)",
+			"share_score":               "GET /share/:receiptToken, GET /share/:receiptToken/card.png (public, rate limited, link preview page and image)",
+			"enroll_device":             "POST /api/v1/devices/enroll (enrollment code, rate limited)",
+			"register_player":           "POST /api/v1/players, GET|PUT /api/v1/players/:initials (public, PIN to update, rate limited)",
+			"openapi_spec":              "GET /api/v1/openapi.json (public)",
+			"api_docs":                  "GET /docs (public)",
+			"admin_ui":                  "GET /admin/ (web UI, sign in with an admin API key)",
+			"grpc_web":                  "POST /rawboard.v1.LeaderboardService/{SubmitScore,GetLeaderboard,GetPlayerStats} (gRPC-Web; native gRPC on GRPC_PORT)",
 		},
 		"authentication": gin.H{
 			"type": "API Key",
@@ -77,13 +438,46 @@ func welcomeHandler(c *gin.Context) {
 			"required_for": []string{
 				"POST /api/v1/games/:gameId/scores",
 				"GET /api/v1/games/:gameId/scores/all",
+				"DELETE /api/v1/games/:gameId/scores",
+				"DELETE /api/v1/games/:gameId/players/:initials",
+				"POST /api/v1/games/:gameId/players/:initials/recompute",
+				"POST /api/v1/games/:gameId/stream-tokens",
+				"GET /api/v1/games/:gameId/events (or ?token=<stream token>)",
+				"GET /api/v1/games/:gameId/ws (or ?token=<stream token>)",
 			},
 			"public_endpoints": []string{
 				"GET /api/v1/games/:gameId/leaderboard",
 				"GET /api/v1/games/:gameId/players/:initials/stats",
 				"GET /api/v1/games/:gameId/players/:initials/stats/enhanced",
 				"GET /api/v1/games/:gameId/scores/analyze",
+				"GET /api/v1/games/:gameId/players/:initials/rank",
+				"GET /api/v1/games/:gameId/leaderboard/around/:initials",
+				"GET /public/games/:gameId/summary",
+				"GET /public/receipts/:token",
+				"GET /share/:receiptToken",
+				"GET /api/v1/tournaments/:tournamentId/standings",
+				"POST /api/v1/devices/enroll",
+				"POST /api/v1/players",
+				"GET /api/v1/players/:initials",
+				"PUT /api/v1/players/:initials",
+				"POST /api/v1/games/:gameId/scores/:scoreId/flag",
 				"GET /health",
+				"GET /api/v1/openapi.json",
+				"GET /docs",
+				"GET /admin/",
+			},
+			"admin_role_required_for": []string{
+				"GET /api/v1/games/:gameId/scores/all",
+				"GET /api/v1/games/:gameId/scores/all/export",
+				"GET /api/v1/games/:gameId/leaderboard/export",
+				"GET /api/v1/games/:gameId/scores",
+				"POST /api/v1/games/:gameId/import",
+				"POST /api/v1/admin/games/:gameId/merge",
+				"POST /api/v1/admin/games/:gameId/restore",
+				"POST /api/v1/admin/games/:gameId/disputes/:scoreId/resolve",
+				"POST /api/v1/games/:gameId/reset",
+				"GET /api/v1/admin/games/:gameId/archives/:archiveId",
+				"every DELETE endpoint",
 			},
 		},
 		"usage": gin.H{
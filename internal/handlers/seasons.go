@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListSeasons handles GET /api/v1/games/:gameId/seasons
+// @Summary List a game's seasons
+// @Tags seasons
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 200 {object} models.GameSeasons "Seasons, oldest first"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to load seasons"
+// @Router /api/v1/games/{gameId}/seasons [get]
+func (h *LeaderboardHandler) ListSeasons(c *gin.Context) {
+	gameID, ok := seasonGameID(c)
+	if !ok {
+		return
+	}
+
+	seasons, err := h.service.Seasons(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to load seasons", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to load seasons"))
+		return
+	}
+
+	c.JSON(http.StatusOK, seasons)
+}
+
+// GetSeasonLeaderboard handles GET /api/v1/games/:gameId/seasons/:seasonId/leaderboard
+// @Summary Get a season's leaderboard
+// @Description The live board while the season is active, and its archived final board once it has ended
+// @Tags seasons
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param seasonId path string true "Season ID"
+// @Param limit query integer false "Maximum entries to return, default all"
+// @Success 200 {object} models.SeasonLeaderboard
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or limit"
+// @Failure 404 {object} handlers.StandardErrorResponse "Season not found"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to load the season"
+// @Router /api/v1/games/{gameId}/seasons/{seasonId}/leaderboard [get]
+func (h *LeaderboardHandler) GetSeasonLeaderboard(c *gin.Context) {
+	gameID, ok := seasonGameID(c)
+	if !ok {
+		return
+	}
+	seasonID := c.Param("seasonId")
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"limit", limitStr, "positive integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	board, err := h.service.SeasonLeaderboard(c.Request.Context(), gameID, seasonID, limit)
+	if errors.Is(err, leaderboard.ErrSeasonNotFound) {
+		seasonNotFound(c, gameID, seasonID)
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to load season leaderboard", "game_id", gameID, "season_id", seasonID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to load the season"))
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+// StartSeason handles POST /api/v1/games/:gameId/seasons
+// @Summary Start a season
+// @Description Ends the game's active season, if any, and resets the live leaderboard so it only ranks scores from the new season. A season with ends_at is archived automatically at that time. Score history is kept whole.
+// @Tags seasons
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param request body handlers.StartSeasonRequest true "Season to start"
+// @Success 201 {object} models.Season
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or season"
+// @Failure 409 {object} handlers.StandardErrorResponse "The game already has a season with this ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to start the season"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/seasons [post]
+func (h *AdminHandler) StartSeason(c *gin.Context) {
+	gameID, ok := seasonGameID(c)
+	if !ok {
+		return
+	}
+
+	var req StartSeasonRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	season, err := h.service.StartSeason(c.Request.Context(), gameID, req.SeasonID, req.Name, req.EndsAt)
+	if errors.Is(err, leaderboard.ErrInvalidSeason) {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	}
+	if errors.Is(err, leaderboard.ErrSeasonExists) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeSeasonConflict, "The game already has a season with this ID",
+			map[string]interface{}{"game_id": gameID, "season_id": req.SeasonID}))
+		return
+	}
+	if gameLimitResponse(c, err) {
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to start season", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to start the season"))
+		return
+	}
+
+	details := map[string]interface{}{"season_id": season.SeasonID}
+	if season.EndsAt != nil {
+		details["ends_at"] = season.EndsAt
+	}
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionSeasonStarted,
+		GameID:  gameID,
+		Details: details,
+	})
+
+	c.JSON(http.StatusCreated, season)
+}
+
+// EndSeason handles POST /api/v1/games/:gameId/seasons/:seasonId/end
+// @Summary End the active season now
+// @Description Archives the season's leaderboard and resets the live board, which ranks the scores played until the next season starts
+// @Tags seasons
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param seasonId path string true "Season ID"
+// @Success 200 {object} models.Season
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 404 {object} handlers.StandardErrorResponse "Season not found"
+// @Failure 409 {object} handlers.StandardErrorResponse "Season has already ended"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to end the season"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/seasons/{seasonId}/end [post]
+func (h *AdminHandler) EndSeason(c *gin.Context) {
+	gameID, ok := seasonGameID(c)
+	if !ok {
+		return
+	}
+	seasonID := c.Param("seasonId")
+
+	season, err := h.service.EndSeason(c.Request.Context(), gameID, seasonID)
+	if errors.Is(err, leaderboard.ErrSeasonNotFound) {
+		seasonNotFound(c, gameID, seasonID)
+		return
+	}
+	if errors.Is(err, leaderboard.ErrSeasonNotActive) {
+		c.JSON(http.StatusConflict, NewStandardErrorResponse(c,
+			ErrorCodeSeasonConflict, "Season has already ended",
+			map[string]interface{}{"game_id": gameID, "season_id": seasonID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to end season", "game_id", gameID, "season_id", seasonID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to end the season"))
+		return
+	}
+
+	h.recordAudit(c, models.AuditEntry{
+		Action:  audit.ActionSeasonEnded,
+		GameID:  gameID,
+		Details: map[string]interface{}{"season_id": seasonID, "players": season.Players},
+	})
+
+	c.JSON(http.StatusOK, season)
+}
+
+// seasonGameID validates the game ID of a season request, writing the error response
+// and returning false if it is invalid
+func seasonGameID(c *gin.Context) (string, bool) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return "", false
+	}
+	return gameID, true
+}
+
+// seasonNotFound writes the response for an unknown season
+func seasonNotFound(c *gin.Context, gameID, seasonID string) {
+	c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+		ErrorCodeSeasonNotFound, fmt.Sprintf("Season %s not found", seasonID),
+		map[string]interface{}{"game_id": gameID, "season_id": seasonID}))
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionCookiePath sends the session cookie with API calls, including tenant paths,
+// and nowhere else
+const sessionCookiePath = "/api/"
+
+// SessionHandler signs the admin UI in and out with an HttpOnly session cookie, so the
+// browser never has to hold the API key itself
+type SessionHandler struct {
+	sessions *apikeys.Sessions
+	audit    *audit.Log
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(sessions *apikeys.Sessions, auditLog *audit.Log) *SessionHandler {
+	return &SessionHandler{sessions: sessions, audit: auditLog}
+}
+
+// CreateSession handles POST /api/v1/admin/session
+// @Summary Sign in to the admin UI
+// @Description Exchanges the API key sent with the request for an HttpOnly, SameSite=Strict session cookie that authenticates admin API calls from the browser in its place, until ADMIN_SESSION_TTL passes. The session acts with the key's games and scopes, and ends early if the key is revoked or the master key rotated. Neither a session nor a JWT can be used to start one.
+// @Tags admin
+// @Success 201 {object} models.AdminSession
+// @Failure 403 {object} handlers.StandardErrorResponse "Signed in with a session or JWT rather than an API key"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to start session"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Router /api/v1/admin/session [post]
+func (h *SessionHandler) CreateSession(c *gin.Context) {
+	p := principal(c)
+	if p != nil && (p.Session != nil || p.JWT) {
+		c.JSON(http.StatusForbidden, NewStandardErrorResponse(c,
+			ErrorCodeAuthenticationRequired, "Sign in with an API key to start a session"))
+		return
+	}
+
+	token, expiresAt, err := h.sessions.Issue(p)
+	if err != nil {
+		requestLogger(c).Error("failed to start admin session", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to start session"))
+		return
+	}
+
+	setSessionCookie(c, token, expiresAt)
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionSessionStarted,
+		Details: map[string]interface{}{"expires_at": expiresAt},
+	})
+
+	c.JSON(http.StatusCreated, adminSession(p, &expiresAt))
+}
+
+// GetSession handles GET /api/v1/admin/session
+// @Summary Get who the admin UI is signed in as
+// @Description Describes the key the request authenticated with, by API key or session cookie, so the UI can tell whether it's still signed in and what it may do.
+// @Tags admin
+// @Success 200 {object} models.AdminSession
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key, or the session expired"
+// @Router /api/v1/admin/session [get]
+func (h *SessionHandler) GetSession(c *gin.Context) {
+	p := principal(c)
+	var expiresAt *time.Time
+	if p != nil {
+		expiresAt = p.Session
+	}
+	c.JSON(http.StatusOK, adminSession(p, expiresAt))
+}
+
+// DeleteSession handles DELETE /api/v1/admin/session
+// @Summary Sign out of the admin UI
+// @Description Clears the session cookie. Sessions aren't stored, so a copy of the cookie taken before signing out keeps working until it expires; revoke the key to end it sooner.
+// @Tags admin
+// @Success 204 "Signed out"
+// @Router /api/v1/admin/session [delete]
+func (h *SessionHandler) DeleteSession(c *gin.Context) {
+	setSessionCookie(c, "", time.Unix(0, 0))
+	c.Status(http.StatusNoContent)
+}
+
+// setSessionCookie stores the session token in the browser until expiresAt, or clears it
+// when token is empty
+func setSessionCookie(c *gin.Context, token string, expiresAt time.Time) {
+	maxAge := int(time.Until(expiresAt).Seconds())
+	if token == "" {
+		maxAge = -1
+	}
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     apikeys.SessionCookie,
+		Value:    token,
+		Path:     sessionCookiePath,
+		Expires:  expiresAt,
+		MaxAge:   maxAge,
+		Secure:   strings.HasPrefix(requestOrigin(c), "https:"),
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// adminSession describes p, which is nil when authentication is disabled and so acts
+// as the master key
+func adminSession(p *apikeys.Principal, expiresAt *time.Time) models.AdminSession {
+	if p == nil {
+		p = apikeys.MasterPrincipal()
+	}
+	return models.AdminSession{
+		Name:      p.Name,
+		KeyID:     p.KeyID,
+		Master:    p.Master,
+		Tenant:    p.Tenant,
+		GameIDs:   p.GameIDs,
+		Scopes:    p.Scopes,
+		ExpiresAt: expiresAt,
+	}
+}
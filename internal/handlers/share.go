@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"rawboard/internal/models"
+	"rawboard/internal/sharecard"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sharePolicy lets a share page show its card and nothing else
+const sharePolicy = "default-src 'none'; img-src 'self'; style-src 'unsafe-inline'; base-uri 'none'; form-action 'none'"
+
+// shareCacheControl lets chat apps and CDNs reuse a preview while the score's standing
+// is unlikely to have moved much
+const shareCacheControl = "public, max-age=300"
+
+var sharePage = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<meta name="description" content="{{.Description}}">
+<meta property="og:type" content="website">
+<meta property="og:site_name" content="Rawboard">
+<meta property="og:title" content="{{.Title}}">
+<meta property="og:description" content="{{.Description}}">
+<meta property="og:url" content="{{.URL}}">
+<meta property="og:image" content="{{.Image}}">
+<meta property="og:image:type" content="image/png">
+<meta property="og:image:width" content="{{.Width}}">
+<meta property="og:image:height" content="{{.Height}}">
+<meta property="og:image:alt" content="{{.Title}}">
+<meta name="twitter:card" content="summary_large_image">
+<meta name="twitter:title" content="{{.Title}}">
+<meta name="twitter:description" content="{{.Description}}">
+<meta name="twitter:image" content="{{.Image}}">
+</head>
+<body style="margin:0;background:#10101a;color:#fff;font-family:monospace;text-align:center">
+<img src="{{.Image}}" alt="{{.Title}}" width="{{.Width}}" height="{{.Height}}" style="max-width:100%;height:auto">
+<p>{{.Description}}</p>
+</body>
+</html>
+`))
+
+// sharePreview is what a share page and its card say about a score
+type sharePreview struct {
+	Title       string
+	Description string
+	URL         string
+	Image       string
+	Width       int
+	Height      int
+	card        sharecard.Card
+}
+
+// GetSharePage handles GET /share/:receiptToken
+// @Summary Get a shareable page for a score
+// @Description An HTML page with OpenGraph and Twitter card tags for the score a receipt was issued for, so a link to it shared in a chat app previews the game, initials, score and current standing with the card image. Rate limited per client IP like receipt lookups.
+// @Tags public
+// @Param receiptToken path string true "Receipt token from the score submission"
+// @Success 200 "text/html page"
+// @Failure 404 {object} handlers.StandardErrorResponse "Receipt not found"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many lookups"
+// @Router /share/{receiptToken} [get]
+func (h *LeaderboardHandler) GetSharePage(c *gin.Context) {
+	preview, ok := h.sharePreview(c)
+	if !ok {
+		return
+	}
+
+	var page bytes.Buffer
+	if err := sharePage.Execute(&page, preview); err != nil {
+		requestLogger(c).Error("failed to render share page", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to render share page"))
+		return
+	}
+
+	c.Header("Cache-Control", shareCacheControl)
+	c.Header("Content-Security-Policy", sharePolicy)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Referrer-Policy", "no-referrer")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+// GetShareCard handles GET /share/:receiptToken/card.png
+// @Summary Get the preview image for a shared score
+// @Description A 1200x630 PNG of the game, initials, score and current standing, linked from the share page's og:image.
+// @Tags public
+// @Param receiptToken path string true "Receipt token from the score submission"
+// @Success 200 "image/png card"
+// @Failure 404 {object} handlers.StandardErrorResponse "Receipt not found"
+// @Failure 429 {object} handlers.StandardErrorResponse "Too many lookups"
+// @Router /share/{receiptToken}/card.png [get]
+func (h *LeaderboardHandler) GetShareCard(c *gin.Context) {
+	preview, ok := h.sharePreview(c)
+	if !ok {
+		return
+	}
+
+	image, err := sharecard.Render(preview.card)
+	if err != nil {
+		requestLogger(c).Error("failed to render share card", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to render share card"))
+		return
+	}
+
+	c.Header("Cache-Control", shareCacheControl)
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// sharePreview looks up the receipt a share link is for and describes its score,
+// answering 404 itself when there's no such receipt
+func (h *LeaderboardHandler) sharePreview(c *gin.Context) (*sharePreview, bool) {
+	token := c.Param("receiptToken")
+	ctx := c.Request.Context()
+
+	status, err := h.service.LookupReceipt(ctx, token)
+	if err != nil {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeReceiptNotFound, "Receipt not found"))
+		return nil, false
+	}
+
+	var scoring *models.ScoringSettings
+	if game, err := h.service.GetGame(ctx, status.GameID); err == nil {
+		scoring = game.Settings.Scoring
+	}
+	score := groupDigits(scoring.Format(status.Score))
+
+	var description, footer string
+	switch status.Status {
+	case models.ReceiptStatusHighScore:
+		description = fmt.Sprintf("Ranked #%d of %d players.", status.Rank, status.TotalPlayers)
+		footer = fmt.Sprintf("RANK #%d OF %d", status.Rank, status.TotalPlayers)
+	case models.ReceiptStatusSuperseded:
+		description = fmt.Sprintf("Since beaten by their own high score; it would rank #%d of %d.", status.Rank, status.TotalPlayers)
+		footer = "PERSONAL BEST SINCE BEATEN"
+	default:
+		description = "This score is no longer on the leaderboard."
+	}
+
+	pageURL := requestOrigin(c) + "/share/" + token
+	return &sharePreview{
+		Title:       fmt.Sprintf("%s scored %s in %s", status.Initials, score, status.GameID),
+		Description: description,
+		URL:         pageURL,
+		Image:       pageURL + "/card.png",
+		Width:       sharecard.Width,
+		Height:      sharecard.Height,
+		card:        sharecard.Card{Title: status.GameID, Initials: status.Initials, Score: score, Footer: footer},
+	}, true
+}
+
+// requestOrigin returns the scheme and host a request was made to, for the absolute
+// URLs link previews need. Behind a proxy, X-Forwarded-Proto gives the scheme.
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	return scheme + "://" + c.Request.Host
+}
+
+// groupDigits separates the thousands of a formatted score with commas, e.g. 12500.50
+// as 12,500.50
+func groupDigits(score string) string {
+	sign, digits := "", score
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(digits, ".")
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if hasFraction {
+		return sign + grouped.String() + "." + fraction
+	}
+	return sign + grouped.String()
+}
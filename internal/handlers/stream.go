@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"nhooyr.io/websocket"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/broadcast"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+const (
+	// streamWriteTimeout bounds each write to a live client so a stalled socket is dropped
+	streamWriteTimeout = 10 * time.Second
+
+	// streamKeepAlive keeps idle streams open through proxies
+	streamKeepAlive = 25 * time.Second
+)
+
+// StreamHandler serves live leaderboard updates to display clients
+type StreamHandler struct {
+	service *leaderboard.Service
+	hub     *broadcast.Hub
+	tokens  *apikeys.StreamTokens
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(service *leaderboard.Service, hub *broadcast.Hub, tokens *apikeys.StreamTokens) *StreamHandler {
+	return &StreamHandler{service: service, hub: hub, tokens: tokens}
+}
+
+// CreateStreamToken handles POST /api/v1/games/:gameId/stream-tokens
+// @Summary Mint a stream token
+// @Description Returns a short-lived token that opens this game's event and WebSocket streams via ?token=, for clients that can't send an API key header. Any key scoped to the game may mint one. Tokens can't be revoked individually; they expire after STREAM_TOKEN_TTL.
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Success 201 {object} models.StreamToken
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to mint token"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/stream-tokens [post]
+func (h *StreamHandler) CreateStreamToken(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	token, err := h.tokens.Issue(principal(c), tenants.FromContext(c.Request.Context()), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to mint stream token", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to mint stream token"))
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+// liveStream is an open subscription plus the version the client already has
+type liveStream struct {
+	sub      *broadcast.Subscription
+	snapshot *models.BoardEvent
+	version  int64
+}
+
+// next returns the next event newer than anything the client has seen
+func (s *liveStream) next(done <-chan struct{}) (models.BoardEvent, error) {
+	if s.snapshot != nil {
+		event := *s.snapshot
+		s.snapshot = nil
+		s.version = event.Version
+		return event, nil
+	}
+
+	for {
+		event, err := s.sub.Next(done)
+		if err != nil {
+			return event, err
+		}
+		if event.Version > s.version {
+			s.version = event.Version
+			return event, nil
+		}
+	}
+}
+
+// pump delivers the stream's events on a channel until stop is closed or the stream ends
+func (s *liveStream) pump(stop <-chan struct{}) (<-chan models.BoardEvent, <-chan error) {
+	events := make(chan models.BoardEvent)
+	streamErr := make(chan error, 1)
+	go func() {
+		for {
+			event, err := s.next(stop)
+			if err != nil {
+				streamErr <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return events, streamErr
+}
+
+// openStream validates the request and subscribes to the game's events
+// A client that sends the version it has (since, or SSE's Last-Event-ID) is only sent
+// newer events; otherwise it starts with a snapshot of the current board.
+func (h *StreamHandler) openStream(c *gin.Context) (*liveStream, bool) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return nil, false
+	}
+
+	sinceStr := c.Query("since")
+	if sinceStr == "" {
+		sinceStr = c.GetHeader("Last-Event-ID")
+	}
+	var since int64
+	if sinceStr != "" {
+		parsed, err := strconv.ParseInt(sinceStr, 10, 64)
+		if err != nil || parsed < 0 {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"since", sinceStr, "non-negative board version"))
+			return nil, false
+		}
+		since = parsed
+	}
+
+	// Subscribe before reading the board so no update falls between the two
+	stream := &liveStream{sub: h.hub.Subscribe(tenants.FromContext(c.Request.Context()), gameID, since), version: since}
+	if board, err := h.service.GetLeaderboard(c.Request.Context(), gameID); err == nil && board.Version > since {
+		stream.snapshot = &models.BoardEvent{
+			Type:        models.BoardEventSnapshot,
+			GameID:      gameID,
+			Version:     board.Version,
+			Leaderboard: board,
+		}
+	}
+
+	return stream, true
+}
+
+// StreamEvents handles GET /api/v1/games/:gameId/events
+// @Summary Stream leaderboard updates (server-sent events)
+// @Description Sends a snapshot of the board, then a leaderboard.updated event whenever it changes. Each event's id is the board version, so reconnecting clients resume with Last-Event-ID. Requires an API key scoped to the game or a stream token. A client that falls behind gets a resync event carrying the latest board, and one that stops reading is disconnected.
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param since query integer false "Board version the client already has"
+// @Param token query string false "Stream token, instead of an API key header"
+// @Success 200 "text/event-stream of models.BoardEvent"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or version"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key or stream token"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credentials are not valid for this game"
+// @Router /api/v1/games/{gameId}/events [get]
+func (h *StreamHandler) StreamEvents(c *gin.Context) {
+	stream, ok := h.openStream(c)
+	if !ok {
+		return
+	}
+	defer stream.sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("Access-Control-Allow-Origin", "*")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	controller := http.NewResponseController(c.Writer)
+	write := func(payload string) error {
+		_ = controller.SetWriteDeadline(time.Now().Add(streamWriteTimeout)) // Unsupported by test recorders
+		if _, err := c.Writer.WriteString(payload); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	stop := make(chan struct{})
+	defer close(stop)
+	events, streamErr := stream.pump(stop)
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				requestLogger(c).Error("failed to encode board event", "error", err)
+				return
+			}
+			if err := write(fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.Version, event.Type, data)); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := write(": keep-alive\n\n"); err != nil {
+				return
+			}
+		case err := <-streamErr:
+			if errors.Is(err, broadcast.ErrSlowClient) {
+				requestLogger(c).Info("stream client disconnected for falling behind")
+			}
+			return
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// StreamWebSocket handles GET /api/v1/games/:gameId/ws
+// @Summary Stream leaderboard updates (WebSocket)
+// @Description Upgrades to a WebSocket that carries the same JSON board events as the server-sent event stream, one per text message. Reconnecting clients resume with ?since=<version>. Requires an API key scoped to the game or a stream token.
+// @Tags leaderboard
+// @Param gameId path string true "Game ID" minlength(1) maxlength(50)
+// @Param since query integer false "Board version the client already has"
+// @Param token query string false "Stream token, instead of an API key header"
+// @Success 101 "Switched to a WebSocket of models.BoardEvent messages"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid game ID or version"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key or stream token"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credentials are not valid for this game"
+// @Router /api/v1/games/{gameId}/ws [get]
+func (h *StreamHandler) StreamWebSocket(c *gin.Context) {
+	stream, ok := h.openStream(c)
+	if !ok {
+		return
+	}
+	defer stream.sub.Close()
+
+	// Displays on other origins connect with a key or stream token they were given, which
+	// another site can't send for them. Browsers send the admin session cookie on their
+	// own, so connections signed in with it must come from this server's origin.
+	p := principal(c)
+	sameOrigin := p != nil && p.Session != nil
+	conn, err := websocket.Accept(c.Writer, c.Request, &websocket.AcceptOptions{InsecureSkipVerify: !sameOrigin})
+	if err != nil {
+		return
+	}
+	defer conn.Close(websocket.StatusInternalError, "stream ended")
+
+	// Clients only listen; reading in the background handles pings and close frames
+	ctx := conn.CloseRead(c.Request.Context())
+
+	stop := make(chan struct{})
+	defer close(stop)
+	events, streamErr := stream.pump(stop)
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	write := func(send func(ctx context.Context) error) error {
+		writeCtx, cancel := context.WithTimeout(ctx, streamWriteTimeout)
+		defer cancel()
+		return send(writeCtx)
+	}
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				requestLogger(c).Error("failed to encode board event", "error", err)
+				return
+			}
+			if err := write(func(ctx context.Context) error { return conn.Write(ctx, websocket.MessageText, data) }); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := write(conn.Ping); err != nil {
+				return
+			}
+		case err := <-streamErr:
+			if errors.Is(err, broadcast.ErrSlowClient) {
+				conn.Close(websocket.StatusTryAgainLater, "client fell behind; reconnect with ?since=<version>")
+				return
+			}
+			if errors.Is(err, broadcast.ErrShutdown) {
+				conn.Close(websocket.StatusGoingAway, "server shutting down; reconnect with ?since=<version>")
+				return
+			}
+			conn.Close(websocket.StatusNormalClosure, "")
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+	"rawboard/internal/tournaments"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TournamentHandler serves tournament standings and manages tournaments
+type TournamentHandler struct {
+	service *tournaments.Service
+	audit   *audit.Log
+}
+
+// NewTournamentHandler creates a new tournament handler
+func NewTournamentHandler(service *tournaments.Service, auditLog *audit.Log) *TournamentHandler {
+	return &TournamentHandler{service: service, audit: auditLog}
+}
+
+// GetStandings handles GET /api/v1/tournaments/:tournamentId/standings
+// @Summary Get a tournament's standings
+// @Description Ranks players on their counted scores played in the tournament's window, taking each player's best score per member game and then the best of those or their total, by the tournament's scoring. Players with equal scores share a rank.
+// @Tags tournaments
+// @Param tournamentId path string true "Tournament ID"
+// @Param limit query integer false "Maximum standings to return, default all"
+// @Success 200 {object} models.TournamentStandings
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid limit"
+// @Failure 404 {object} handlers.StandardErrorResponse "Tournament not found"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to compute the standings"
+// @Router /api/v1/tournaments/{tournamentId}/standings [get]
+func (h *TournamentHandler) GetStandings(c *gin.Context) {
+	tournamentID := c.Param("tournamentId")
+
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+				"limit", limitStr, "positive integer"))
+			return
+		}
+		limit = parsed
+	}
+
+	standings, err := h.service.Standings(c.Request.Context(), tournamentID, limit, time.Now().UTC())
+	if errors.Is(err, tournaments.ErrNotFound) {
+		tournamentNotFound(c, tournamentID)
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to compute tournament standings", "tournament_id", tournamentID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to compute the standings"))
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
+
+// ListTournaments handles GET /api/v1/admin/tournaments
+// @Summary List tournaments
+// @Tags tournaments
+// @Success 200 {object} handlers.TournamentListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list tournaments"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/tournaments [get]
+func (h *TournamentHandler) ListTournaments(c *gin.Context) {
+	list, err := h.service.List(c.Request.Context())
+	if err != nil {
+		requestLogger(c).Error("failed to list tournaments", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list tournaments"))
+		return
+	}
+
+	c.JSON(http.StatusOK, TournamentListResponse{Tournaments: list})
+}
+
+// PutTournament handles PUT /api/v1/admin/tournaments/:tournamentId
+// @Summary Create or replace a tournament
+// @Description Member games need no setup: standings are derived from their score histories, so a tournament can be created before its games have scores, and changed while it runs. Registered member games must use the same decimals.
+// @Tags tournaments
+// @Param tournamentId path string true "Tournament ID"
+// @Param request body handlers.TournamentRequest true "Games, window and scoring"
+// @Success 200 {object} models.Tournament
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid tournament ID or tournament, or games with different decimals"
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to save the tournament"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/tournaments/{tournamentId} [put]
+func (h *TournamentHandler) PutTournament(c *gin.Context) {
+	tournamentID := c.Param("tournamentId")
+	if len(tournamentID) > 50 || len(tournamentID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"tournamentId", tournamentID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req TournamentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	tournament := models.Tournament{
+		TournamentID: tournamentID,
+		Name:         req.Name,
+		GameIDs:      req.GameIDs,
+		Scoring:      req.Scoring,
+		StartsAt:     req.StartsAt.UTC(),
+		EndsAt:       req.EndsAt.UTC(),
+	}
+	if err := tournament.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, err.Error(),
+			map[string]interface{}{"tournament_id": tournamentID}))
+		return
+	}
+
+	saved, err := h.service.Put(c.Request.Context(), tournament)
+	if errors.Is(err, tournaments.ErrScoringDiffers) {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error(),
+			map[string]interface{}{"tournament_id": tournamentID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to save tournament", "tournament_id", tournamentID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to save tournament"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionTournamentUpdated,
+		Details: map[string]interface{}{"tournament_id": tournamentID, "games": saved.GameIDs, "scoring": saved.Scoring},
+	})
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// DeleteTournament handles DELETE /api/v1/admin/tournaments/:tournamentId
+// @Summary Delete a tournament
+// @Description The member games' scores are kept
+// @Tags tournaments
+// @Param tournamentId path string true "Tournament ID"
+// @Success 200 {object} models.Tournament "The deleted tournament"
+// @Failure 404 {object} handlers.StandardErrorResponse "Tournament not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/admin/tournaments/{tournamentId} [delete]
+func (h *TournamentHandler) DeleteTournament(c *gin.Context) {
+	tournamentID := c.Param("tournamentId")
+
+	tournament, err := h.service.Delete(c.Request.Context(), tournamentID)
+	if errors.Is(err, tournaments.ErrNotFound) {
+		tournamentNotFound(c, tournamentID)
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete tournament", "tournament_id", tournamentID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete tournament"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionTournamentDeleted,
+		Details: map[string]interface{}{"tournament_id": tournamentID},
+	})
+
+	c.JSON(http.StatusOK, tournament)
+}
+
+// tournamentNotFound writes the response for an unknown tournament
+func tournamentNotFound(c *gin.Context, tournamentID string) {
+	c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+		ErrorCodeTournamentNotFound, "Tournament not found",
+		map[string]interface{}{"tournament_id": tournamentID}))
+}
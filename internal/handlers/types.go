@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"encoding/json"
+	"time"
+
 	"rawboard/internal/models"
 )
 
@@ -9,25 +12,140 @@ import (
 // system-generated fields like timestamp
 type ScoreSubmissionRequest struct {
 	Initials string `json:"initials" binding:"required" example:"AAA" minLength:"3" maxLength:"3"`
-	Score    int64  `json:"score" binding:"required,min=0" example:"12500" minimum:"0" maximum:"999999999"`
+	// Whole and non-negative unless the game's scoring settings allow decimals or negatives
+	Score json.Number `json:"score" binding:"required" swaggertype:"number" example:"12500" minimum:"-999999999" maximum:"999999999"`
+
+	// Optional game-specific detail, e.g. {"level": 12, "character": "ms_pacman"}: up to 16
+	// lowercase keys with string, number or boolean values, 1 KB in all
+	Metadata models.ScoreMetadata `json:"metadata,omitempty" swaggertype:"object"`
+
+	// Order of the play in a synced queue, for edge instances forwarding it; needs a key
+	// with the edge scope. Stored as client_sequence, it breaks ties between equal
+	// scores stored in the same instant only among entries that have one.
+	Sequence int64 `json:"sequence,omitempty" binding:"min=0" example:"17"`
+
+	// The initials' PIN, needed when they're claimed and the game requires PINs
+	PIN string `json:"pin,omitempty" example:"4821"`
+
+	// Optional ID of the play session, grouping plays into visits in enhanced player
+	// stats. Plays without one are grouped by pauses of up to 30 minutes.
+	SessionID string `json:"session_id,omitempty" binding:"max=64" example:"cabinet-1:2025-07-16T19"`
+
+	// HMAC of the submission under the game's signing secret, required when the game
+	// has one
+	Signature *models.ScoreSignature `json:"signature,omitempty"`
+
+	// When the score was played, for edge instances forwarding it later; needs a key
+	// with the edge scope. Defaults to now, and later times read as now.
+	PlayedAt *time.Time `json:"played_at,omitempty" example:"2025-07-16T19:04:05Z"`
+
+	// The device the score was played on, for edge instances forwarding it; needs a
+	// key with the edge scope. Defaults to the key's enrolled device.
+	DeviceID string `json:"device_id,omitempty" binding:"max=64" example:"pacman-cabinet-1"`
 }
 
-// ToScoreEntry converts a submission request to a models.ScoreEntry
-func (r *ScoreSubmissionRequest) ToScoreEntry() *models.ScoreEntry {
+// ToScoreEntry converts a submission request to a models.ScoreEntry, with the score as
+// parsed for the game
+func (r *ScoreSubmissionRequest) ToScoreEntry(score int64) *models.ScoreEntry {
 	return &models.ScoreEntry{
 		Initials: r.Initials,
-		Score:    r.Score,
+		Score:    score,
+		Metadata: r.Metadata,
 		// Timestamp will be set during validation
 	}
 }
 
+// RestoreRequest represents a request to restore data from an export run
+type RestoreRequest struct {
+	ExportID string `json:"export_id" binding:"required" example:"20250716T153000Z"` // Export run ID, or "latest"
+	GameID   string `json:"game_id,omitempty" example:"pacman"`                      // Restore a single game; all games if empty
+	DryRun   bool   `json:"dry_run" example:"false"`                                 // Verify the export without writing anything
+}
+
+// CreateAPIKeyRequest represents a request to create a scoped API key
+type CreateAPIKeyRequest struct {
+	Name    string   `json:"name" binding:"required,max=100" example:"pacman-cabinet-1"`
+	GameIDs []string `json:"game_ids" binding:"required,min=1" example:"pacman"` // Games the key may act on, or "*" for every game
+	Scopes  []string `json:"scopes" binding:"required,min=1" example:"submit"`   // submit, admin:read, admin:write, edge
+	Role    string   `json:"role" enums:"submitter,admin" example:"submitter"`   // Defaults to admin for keys with an admin scope, submitter otherwise
+}
+
+// UpdateGameQuotaRequest overrides how many games an API key may create
+type UpdateGameQuotaRequest struct {
+	MaxGames *int `json:"max_games" binding:"omitempty,min=0" example:"500"` // 0 is unlimited, null restores MAX_GAMES_PER_KEY
+}
+
+// UpdateRateLimitRequest overrides an API key's rate limit
+type UpdateRateLimitRequest struct {
+	RequestsPerSecond *float64 `json:"requests_per_second" example:"100"` // 0 is unlimited, null restores the deployment's limits
+	Burst             int      `json:"burst,omitempty" example:"200"`     // Requests at once, needed unless unlimited
+}
+
+// MergeGameRequest names the game to merge a game's scores into
+type MergeGameRequest struct {
+	Into   string `json:"into" binding:"required,max=50" example:"pacman"`
+	DryRun bool   `json:"dry_run,omitempty" example:"false"` // Report the merge without making it
+}
+
+// RestoreDeletionRequest names the moderation deletion to undo
+type RestoreDeletionRequest struct {
+	TombstoneID string `json:"tombstone_id" binding:"required,max=64" example:"8f14e45f-ceea-4c7a-9a0b-2f1c6b1d6e0f"` // As returned by the deletion
+}
+
+// FlagScoreRequest says why a score is suspicious
+type FlagScoreRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"Impossible score for level 3"`
+}
+
+// ResolveDisputeRequest is an operator's decision about a disputed score
+type ResolveDisputeRequest struct {
+	Action string `json:"action" binding:"required,oneof=keep remove" enums:"keep,remove" example:"remove"` // keep the score, or remove it undoably
+}
+
+// ResetGameRequest says why a game is reset
+type ResetGameRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=200" example:"Cabinet moved to a new venue"`
+}
+
+// StartSeasonRequest starts a season of a game
+type StartSeasonRequest struct {
+	SeasonID string     `json:"season_id,omitempty" binding:"max=50" example:"summer-2025"` // Defaults to season-N
+	Name     string     `json:"name,omitempty" binding:"max=100" example:"Summer 2025"`
+	EndsAt   *time.Time `json:"ends_at,omitempty" example:"2025-09-01T00:00:00Z"` // The season is archived automatically at this time; omit to end it by hand
+}
+
+// DatasetRequest represents a request to publish an anonymized dataset
+type DatasetRequest struct {
+	Mode string `json:"mode,omitempty" example:"hash"` // "hash" (default) pseudonymizes players, "player_id" uses their public IDs, "strip" removes them
+}
+
+// RetentionPolicyRequest sets how much of a game's raw score history is kept
+type RetentionPolicyRequest struct {
+	HistoryDays int `json:"history_days" example:"180"`           // Days of raw history to keep, 0 keeps everything
+	MaxScores   int `json:"max_scores,omitempty" example:"50000"` // Newest scores to keep, 0 keeps everything
+}
+
+// DailySubmissionsRequest sets how many of each player's submissions count per day
+type DailySubmissionsRequest struct {
+	DailySubmissions int `json:"daily_submissions" example:"5"` // 0 removes the limit
+}
+
+// LeaderboardSizeRequest overrides how many entries a game's leaderboard keeps
+type LeaderboardSizeRequest struct {
+	MaxEntries int `json:"max_entries" example:"25"` // 0 falls back to MAX_SCORE_ENTRIES
+}
+
 // ScoreSubmissionResponse represents the response after submitting a score
 // This includes both the submitted entry and the current leaderboard state
 type ScoreSubmissionResponse struct {
-	Message     string              `json:"message" example:"Score submitted successfully"`
-	Entry       *models.ScoreEntry  `json:"entry"`
-	Leaderboard *models.Leaderboard `json:"leaderboard"`
-	Rank        *int                `json:"rank,omitempty" example:"3"` // Position in leaderboard (1-10), nil if not in top 10
+	Message                   string                   `json:"message" example:"Score submitted successfully"`
+	Entry                     *models.ScoreEntry       `json:"entry"`
+	Leaderboard               *models.Leaderboard      `json:"leaderboard"`
+	Rank                      *int                     `json:"rank,omitempty" example:"3"`                                 // Position in leaderboard (1-10), nil if not in top 10
+	ReceiptToken              string                   `json:"receipt_token,omitempty" example:"q3VZ8x2Lm0aTnR4cW1pY7kHe"` // Look up this score later at /public/receipts/:token
+	Budget                    *models.SubmissionBudget `json:"budget,omitempty"`                                           // Only for games with a daily submission budget
+	NewlyUnlockedAchievements []models.Achievement     `json:"newly_unlocked_achievements"`                                // Achievements this score unlocked, empty when none did
+	Sunset                    *models.GameSunset       `json:"sunset,omitempty"`                                           // When the game stops taking submissions, if scheduled
 }
 
 // ErrorResponse represents a standardized error response
@@ -51,6 +169,8 @@ type HealthResponse struct {
 	Service   string `json:"service" example:"rawboard"`               // Service name
 	Version   string `json:"version" example:"1.0.0"`                  // Service version
 	Timestamp string `json:"timestamp" example:"2025-07-13T19:30:00Z"` // Current server time
+
+	Database *models.DatabaseStats `json:"database,omitempty"` // Connection pool and retry counters
 }
 
 // NewHealthResponse creates a standardized health response
@@ -110,3 +230,190 @@ type AllScoresResponse struct {
 	HighestScore int64   `json:"highest_score" example:"50000"`   // Highest score across all players
 	AverageScore float64 `json:"average_score" example:"12500.5"` // Average score across all submissions
 }
+
+// GameListResponse lists the IDs of registered games
+type GameListResponse struct {
+	Games []string `json:"games" example:"pacman,tetris"`
+}
+
+// ExportListResponse lists the IDs of export runs, oldest first
+type ExportListResponse struct {
+	Exports []string `json:"exports" example:"20250716T150000Z"`
+}
+
+// APIKeyListResponse lists every scoped API key without its secret
+type APIKeyListResponse struct {
+	Keys []models.APIKey `json:"keys"`
+}
+
+// CreateWebhookRequest registers a webhook for a game
+type CreateWebhookRequest struct {
+	URL       string   `json:"url" binding:"required" example:"https://hooks.example.com/rawboard"`
+	Events    []string `json:"events,omitempty" example:"score.high_score,leaderboard.top_1"` // Defaults to leaderboard.position
+	Condition string   `json:"condition,omitempty" example:"any enters top 3"`                // Required for leaderboard.position, e.g. "player XYZ drops out of top 10"
+	Format    string   `json:"format,omitempty" example:"json" enums:"json,protobuf"`         // Delivery body format, json by default
+}
+
+// WebhookListResponse lists a game's webhooks
+type WebhookListResponse struct {
+	GameID   string           `json:"game_id" example:"pacman"`
+	Webhooks []models.Webhook `json:"webhooks"`
+}
+
+// TournamentRequest configures a tournament
+type TournamentRequest struct {
+	Name     string    `json:"name,omitempty" example:"Spring Cup"`
+	GameIDs  []string  `json:"game_ids" binding:"required" example:"pacman,galaga"`
+	Scoring  string    `json:"scoring,omitempty" example:"total"` // best or total (the default)
+	StartsAt time.Time `json:"starts_at" binding:"required" example:"2025-04-01T00:00:00Z"`
+	EndsAt   time.Time `json:"ends_at" binding:"required" example:"2025-04-30T00:00:00Z"` // Scores at or after this time don't count
+}
+
+// TournamentListResponse lists every tournament, soonest start first
+type TournamentListResponse struct {
+	Tournaments []models.Tournament `json:"tournaments"`
+}
+
+// DisplayRequest configures a display's rotation
+type DisplayRequest struct {
+	Name   string                `json:"name,omitempty" example:"Lobby TV"`
+	Slides []models.DisplaySlide `json:"slides" binding:"required"` // Shown in order, then repeated
+}
+
+// DisplayListResponse lists every display
+type DisplayListResponse struct {
+	Displays []models.Display `json:"displays"`
+}
+
+// DisplayHeartbeatRequest reports that a display device is alive and what it shows
+type DisplayHeartbeatRequest struct {
+	DeviceID string                 `json:"device_id" binding:"required,max=64" example:"lobby-tv-1"` // Chosen by the device, stable across restarts
+	Name     string                 `json:"name,omitempty" binding:"max=100" example:"Lobby TV by the door"`
+	Version  string                 `json:"version,omitempty" binding:"max=50" example:"1.4.0"`
+	Showing  *models.DisplayShowing `json:"showing,omitempty"`
+}
+
+// DisplayStatusResponse lists every display's devices and how many are online
+type DisplayStatusResponse struct {
+	Online   int                    `json:"online" example:"3"`
+	Offline  int                    `json:"offline" example:"1"`
+	Displays []models.DisplayStatus `json:"displays"`
+}
+
+// DeviceRequest registers a cabinet for enrollment
+type DeviceRequest struct {
+	DeviceID string            `json:"device_id" binding:"required" example:"pacman-cabinet-1"`
+	Name     string            `json:"name,omitempty" example:"Pac-Man by the bar"`
+	Games    map[string]string `json:"games" binding:"required"` // Names the cabinet uses for its games mapped to game IDs
+}
+
+// DeviceListResponse lists every device
+type DeviceListResponse struct {
+	Devices []models.Device `json:"devices"`
+}
+
+// EnrollDeviceRequest redeems a device's enrollment code
+type EnrollDeviceRequest struct {
+	Code string `json:"code" binding:"required,max=50" example:"K7QM-2XRD-9HPA"`
+}
+
+// CreateProfileRequest registers a player profile for initials
+type CreateProfileRequest struct {
+	Initials    string `json:"initials" binding:"required" example:"AAA"`
+	PIN         string `json:"pin" binding:"required" example:"4821"` // 4 to 8 digits, needed to change the profile
+	DisplayName string `json:"display_name" binding:"required" example:"Ada"`
+	Avatar      string `json:"avatar,omitempty" example:"👾"`   // A single emoji
+	Country     string `json:"country,omitempty" example:"GB"` // ISO 3166-1 alpha-2 code
+}
+
+// UpdateProfileRequest replaces a player profile, authorized by its PIN
+type UpdateProfileRequest struct {
+	PIN         string `json:"pin" binding:"required" example:"4821"`
+	DisplayName string `json:"display_name" binding:"required" example:"Ada"`
+	Avatar      string `json:"avatar,omitempty" example:"👾"`
+	Country     string `json:"country,omitempty" example:"GB"`
+}
+
+// PINRequest carries a player's PIN, to claim initials or check it
+type PINRequest struct {
+	PIN string `json:"pin" binding:"required" example:"4821"` // 4 to 8 digits
+}
+
+// RequirePINRequest turns PIN enforcement for claimed initials on or off for a game
+type RequirePINRequest struct {
+	RequirePIN bool `json:"require_pin" example:"true"`
+}
+
+// InitialsPolicyRequest sets who a game's initials stand for
+type InitialsPolicyRequest struct {
+	Policy string `json:"policy" binding:"required" example:"device"` // shared, device or claimed
+}
+
+// GameSunsetRequest schedules when a game stops taking submissions
+type GameSunsetRequest struct {
+	SunsetAt *time.Time `json:"sunset_at,omitempty" example:"2025-09-01T00:00:00Z"`                           // Omit to stop submissions at once
+	Message  string     `json:"message,omitempty" binding:"max=200" example:"Pac-Man has moved to pacman-ce"` // Shown to integrators whose submissions are refused
+}
+
+// HappyHoursRequest replaces a game's scheduled score multipliers; an empty list removes them
+type HappyHoursRequest struct {
+	HappyHours []models.HappyHour `json:"happy_hours" binding:"max=20"`
+}
+
+// PINVerification reports that a player's PIN matched
+type PINVerification struct {
+	Initials string `json:"initials" example:"AAA"`
+	Verified bool   `json:"verified" example:"true"`
+}
+
+// AchievementRequest defines one of a game's achievements
+type AchievementRequest struct {
+	Name        string `json:"name" binding:"required" example:"High Achiever"`
+	Description string `json:"description,omitempty" example:"Reach 10000 points"`
+	Icon        string `json:"icon,omitempty" example:"💫"`                  // A single emoji
+	Type        string `json:"type" binding:"required" example:"threshold"` // threshold, plays, streak or rank
+	Threshold   int64  `json:"threshold" example:"10000"`                   // The score, submissions, days or rank to reach
+}
+
+// AchievementBadgeRequest uploads the image an achievement is shown with
+type AchievementBadgeRequest struct {
+	Image []byte `json:"image" binding:"required"` // Base64 PNG, GIF, JPEG or WebP, at most 64 KB
+}
+
+// AchievementThemeRequest sets the icon theme of a game's achievements
+type AchievementThemeRequest struct {
+	Theme string `json:"theme" example:"medals"` // classic, medals or retro; empty is classic
+}
+
+// DeadLetterListResponse lists a game's failed webhook deliveries
+type DeadLetterListResponse struct {
+	GameID      string                     `json:"game_id" example:"pacman"`
+	DeadLetters []models.WebhookDeadLetter `json:"dead_letters"`
+}
+
+// DrainRequest says why an instance is being drained
+type DrainRequest struct {
+	Reason string `json:"reason,omitempty" binding:"max=200" example:"kernel upgrade"`
+}
+
+// WebhookLogResponse lists recent webhook delivery attempts, newest first
+type WebhookLogResponse struct {
+	Deliveries []models.WebhookDelivery `json:"deliveries"`
+}
+
+// DevResetRequest chooses whether to load demo data after a development reset
+type DevResetRequest struct {
+	Seed bool `json:"seed" example:"true"` // Submit demo scores for a few classic games
+}
+
+// AuditLogResponse lists audit entries, newest first
+type AuditLogResponse struct {
+	Entries []models.AuditEntry `json:"entries"`
+}
+
+// SimulateAntiCheatRequest is a batch of hypothetical submissions for an anti-cheat
+// simulation, with rules to try instead of the game's
+type SimulateAntiCheatRequest struct {
+	Submissions []models.SimulatedSubmission `json:"submissions" binding:"required,min=1,max=1000,dive"` // Judged in order
+	Rules       *models.AntiCheatRules       `json:"rules,omitempty"`                                    // Rules to try, default the game's own
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultUsageWindow is how far back a usage report reaches when from isn't given
+const defaultUsageWindow = 24 * time.Hour
+
+// GetUsage handles GET /api/v1/admin/usage
+// Optional query parameters: from and to (RFC 3339), granularity (hour or day),
+// and key_id, route and game_id filters
+// @Summary Get API key usage by route
+// @Description Only usage in the caller's tenant is reported
+// @Tags admin
+// @Param from query string false "Window start (RFC 3339), default 24 hours before to"
+// @Param to query string false "Window end (RFC 3339), default now"
+// @Param granularity query string false "hour (default) or day"
+// @Param key_id query string false "Only this key, or master"
+// @Param route query string false "Only this route pattern"
+// @Param game_id query string false "Only this game"
+// @Success 200 {object} models.UsageReport
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid window or filter"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/admin/usage [get]
+func (h *AdminHandler) GetUsage(c *gin.Context) {
+	to := time.Now().UTC()
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "to", raw, "RFC 3339 timestamp"))
+			return
+		}
+		to = parsed
+	}
+
+	from := to.Add(-defaultUsageWindow)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "from", raw, "RFC 3339 timestamp"))
+			return
+		}
+		from = parsed
+	}
+
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "to", c.Query("to"), "after from"))
+		return
+	}
+	if to.Sub(from) > audit.MaxUsageWindow {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "from", c.Query("from"), "within 31 days of to"))
+		return
+	}
+
+	granularity := c.DefaultQuery("granularity", models.UsageGranularityHour)
+	if granularity != models.UsageGranularityHour && granularity != models.UsageGranularityDay {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c, "granularity", granularity, "one of hour, day"))
+		return
+	}
+
+	filter := audit.UsageFilter{
+		KeyID:  c.Query("key_id"),
+		Route:  c.Query("route"),
+		GameID: c.Query("game_id"),
+		Tenant: tenants.FromContext(c.Request.Context()),
+	}
+
+	report, err := h.usage.Report(c.Request.Context(), from, to, granularity, filter)
+	if err != nil {
+		requestLogger(c).Error("failed to build usage report", "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to build usage report"))
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
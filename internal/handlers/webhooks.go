@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/models"
+	"rawboard/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler manages each game's webhooks
+type WebhookHandler struct {
+	store      *webhooks.Store
+	dispatcher *webhooks.Dispatcher
+	audit      *audit.Log
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(store *webhooks.Store, dispatcher *webhooks.Dispatcher, auditLog *audit.Log) *WebhookHandler {
+	return &WebhookHandler{store: store, dispatcher: dispatcher, audit: auditLog}
+}
+
+// ListWebhooks handles GET /api/v1/games/:gameId/webhooks
+// @Summary List a game's webhooks
+// @Description Requires the admin:read scope for the game.
+// @Tags webhooks
+// @Param gameId path string true "Game identifier" minlength(1) maxlength(50)
+// @Success 200 {object} handlers.WebhookListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list webhooks"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	gameID := c.Param("gameId")
+
+	hooks, err := h.store.List(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to list webhooks", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list webhooks"))
+		return
+	}
+
+	c.JSON(http.StatusOK, WebhookListResponse{GameID: gameID, Webhooks: hooks})
+}
+
+// CreateWebhook handles POST /api/v1/games/:gameId/webhooks
+// @Summary Register a webhook
+// @Description Requires the admin:write scope for the game. The webhook receives the events it
+// @Description subscribes to: leaderboard.position (the default), score.high_score,
+// @Description leaderboard.top_1 and achievement.unlocked. Position events are only sent when a
+// @Description leaderboard change meets the webhook's condition, such as "any enters top 3" or
+// @Description "player XYZ drops out of top 10". Deliveries are signed with the secret returned
+// @Description here, which is not shown again. Webhooks taking only score.high_score and
+// @Description achievement.unlocked events may choose the protobuf format, receiving a binary
+// @Description rawboard.v1.Event (see proto/rawboard/v1/events.proto) instead of JSON.
+// @Tags webhooks
+// @Param gameId path string true "Game identifier" minlength(1) maxlength(50)
+// @Param request body handlers.CreateWebhookRequest true "Receiver URL, events and condition"
+// @Success 201 {object} models.CreatedWebhook "The condition is returned in canonical form"
+// @Failure 400 {object} handlers.StandardErrorResponse "Invalid URL, events, condition or format, or too many webhooks"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	gameID := c.Param("gameId")
+	if len(gameID) > 50 || len(gameID) < 1 {
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"gameId", gameID, "length between 1 and 50 characters"))
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeInvalidRequest, "Invalid request format",
+			map[string]interface{}{"validation_error": err.Error()}))
+		return
+	}
+
+	hook, err := h.store.CreateWithFormat(c.Request.Context(), gameID, req.URL, req.Condition, req.Format, req.Events...)
+	switch {
+	case errors.Is(err, webhooks.ErrInvalidURL):
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"url", req.URL, "absolute http or https URL"))
+		return
+	case errors.Is(err, webhooks.ErrInvalidEvents):
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"events", strings.Join(req.Events, ","), "some of "+strings.Join(models.WebhookEventTypes, ", ")))
+		return
+	case errors.Is(err, webhooks.ErrInvalidCondition):
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"condition", req.Condition, `"any|player <initials> enters|leaves top <n>"`))
+		return
+	case errors.Is(err, webhooks.ErrInvalidFormat):
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"format", req.Format, "json, or protobuf with only score.high_score and achievement.unlocked events"))
+		return
+	case errors.Is(err, webhooks.ErrTooMany):
+		c.JSON(http.StatusBadRequest, NewStandardErrorResponse(c,
+			ErrorCodeValidationFailed, err.Error()))
+		return
+	case err != nil:
+		requestLogger(c).Error("failed to create webhook", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to create webhook"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action: audit.ActionWebhookCreated,
+		GameID: gameID,
+		Details: map[string]interface{}{
+			"webhook_id": hook.ID,
+			"url":        hook.URL,
+			"events":     hook.Events,
+			"condition":  hook.Condition,
+			"format":     hook.Format,
+		},
+	})
+
+	c.JSON(http.StatusCreated, hook)
+}
+
+// DeleteWebhook handles DELETE /api/v1/games/:gameId/webhooks/:webhookId
+// @Summary Delete a webhook
+// @Description Requires the admin:write scope for the game.
+// @Tags webhooks
+// @Param gameId path string true "Game identifier" minlength(1) maxlength(50)
+// @Param webhookId path string true "Webhook ID"
+// @Success 200 {object} models.Webhook
+// @Failure 404 {object} handlers.StandardErrorResponse "Webhook not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "Credential lacks the admin role, or the required scope or game"
+// @Router /api/v1/games/{gameId}/webhooks/{webhookId} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	gameID, webhookID := c.Param("gameId"), c.Param("webhookId")
+
+	hook, err := h.store.Delete(c.Request.Context(), gameID, webhookID)
+	if errors.Is(err, webhooks.ErrNotFound) {
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeWebhookNotFound, "Webhook not found",
+			map[string]interface{}{"game_id": gameID, "webhook_id": webhookID}))
+		return
+	}
+	if err != nil {
+		requestLogger(c).Error("failed to delete webhook", "game_id", gameID, "webhook_id", webhookID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to delete webhook"))
+		return
+	}
+
+	recordAudit(c, h.audit, models.AuditEntry{
+		Action:  audit.ActionWebhookDeleted,
+		GameID:  gameID,
+		Details: map[string]interface{}{"webhook_id": hook.ID, "url": hook.URL},
+	})
+
+	c.JSON(http.StatusOK, hook)
+}
+
+// TestWebhook handles POST /api/v1/games/:gameId/webhooks/:webhookId/test
+// @Summary Send a test event to a webhook
+// @Description Requires the admin:write scope for the game. Sends a synthetic event, signed like a
+// @Description real one and marked "test": true, and reports how the receiver answered. The event
+// @Description is a webhook.test ping unless the event query names a type the webhook subscribes
+// @Description to, in which case it carries made-up content of that type. Test deliveries are
+// @Description never retried. A receiver that fails still returns 200, with delivered false.
+// @Tags webhooks
+// @Param gameId path string true "Game identifier" minlength(1) maxlength(50)
+// @Param webhookId path string true "Webhook ID"
+// @Param event query string false "Event type to simulate, e.g. score.high_score"
+// @Success 200 {object} models.WebhookTestResult
+// @Failure 400 {object} handlers.StandardErrorResponse "The webhook doesn't subscribe to the event"
+// @Failure 404 {object} handlers.StandardErrorResponse "Webhook not found"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/webhooks/{webhookId}/test [post]
+func (h *WebhookHandler) TestWebhook(c *gin.Context) {
+	gameID, webhookID := c.Param("gameId"), c.Param("webhookId")
+	eventType := c.Query("event")
+
+	result, err := h.dispatcher.Test(c.Request.Context(), gameID, webhookID, eventType)
+	switch {
+	case errors.Is(err, webhooks.ErrNotFound):
+		c.JSON(http.StatusNotFound, NewStandardErrorResponse(c,
+			ErrorCodeWebhookNotFound, "Webhook not found",
+			map[string]interface{}{"game_id": gameID, "webhook_id": webhookID}))
+		return
+	case errors.Is(err, webhooks.ErrInvalidEvents):
+		c.JSON(http.StatusBadRequest, NewValidationErrorResponse(c,
+			"event", eventType, "an event the webhook subscribes to"))
+		return
+	case err != nil:
+		requestLogger(c).Error("failed to test webhook", "game_id", gameID, "webhook_id", webhookID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to test webhook"))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ListDeadLetters handles GET /api/v1/games/:gameId/webhooks/dead-letters
+// @Summary List failed webhook deliveries
+// @Description Requires the admin:read scope for the game. Deliveries that failed every retry
+// @Description are kept here, newest first, up to 100 per game, with the event that was sent.
+// @Tags webhooks
+// @Param gameId path string true "Game identifier" minlength(1) maxlength(50)
+// @Success 200 {object} handlers.DeadLetterListResponse
+// @Failure 500 {object} handlers.StandardErrorResponse "Failed to list dead letters"
+// @Security ApiKeyAuth
+// @Failure 401 {object} handlers.StandardErrorResponse "Missing or invalid API key"
+// @Failure 403 {object} handlers.StandardErrorResponse "API key lacks the required scope or game"
+// @Router /api/v1/games/{gameId}/webhooks/dead-letters [get]
+func (h *WebhookHandler) ListDeadLetters(c *gin.Context) {
+	gameID := c.Param("gameId")
+
+	letters, err := h.store.DeadLetters(c.Request.Context(), gameID)
+	if err != nil {
+		requestLogger(c).Error("failed to list webhook dead letters", "game_id", gameID, "error", err)
+		c.JSON(http.StatusInternalServerError, NewStandardErrorResponse(c,
+			ErrorCodeInternalError, "Failed to list dead letters"))
+		return
+	}
+
+	c.JSON(http.StatusOK, DeadLetterListResponse{GameID: gameID, DeadLetters: letters})
+}
@@ -0,0 +1,411 @@
+// Package hooks runs operator-supplied WebAssembly modules on score submissions, so
+// deployments can add bespoke validation, transformation or side effects without forking
+// the service.
+//
+// A module exchanges JSON with rawboard through its exported memory. It exports:
+//
+//	memory                                      its linear memory
+//	alloc(size i32) i32                         room for an input of size bytes
+//	before_submit(ptr i32, len i32) i64         optional, see below
+//	after_submit(ptr i32, len i32)              optional, see below
+//
+// before_submit receives the submission as {"game_id", "initials", "score", "metadata",
+// "session_id", "device_id"} and returns 0 to accept it unchanged, or the pointer and
+// length of a JSON reply packed as ptr<<32 | len. The reply refuses the submission with
+// {"reject": "reason"}, or replaces any of "initials", "score" and "metadata".
+// after_submit receives each stored, counted score as {"game_id", "tenant", "entry",
+// "previous_high_score", "new_high_score"} once the submission has been answered.
+//
+// Modules may import rawboard.log(ptr i32, len i32) to write a message to the server
+// log, and WASI for language runtimes that need it; they get no files, network or
+// clock beyond what WASI offers by default. A reactor's _initialize runs on
+// instantiation.
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/models"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+)
+
+// Defaults for WASM hook options
+const (
+	DefaultTimeout     = 100 * time.Millisecond
+	DefaultInstances   = 8
+	DefaultQueueSize   = 256
+	DefaultMemoryPages = 256 // 16 MiB
+)
+
+// maxReply caps a before_submit reply, well above any submission's size
+const maxReply = 1 << 20
+
+// WASMHook runs a WebAssembly module's exports on submissions. It is a leaderboard
+// submission hook for before_submit and a score listener for after_submit. Instances of
+// the module are pooled, each running one call at a time, and calls wait for one while
+// as many are busy as WithInstances allows; an instance whose call fails is discarded
+// rather than reused.
+type WASMHook struct {
+	runtime   wazero.Runtime
+	compiled  wazero.CompiledModule
+	logger    *slog.Logger
+	timeout   time.Duration
+	instances chan api.Module
+	busy      chan struct{} // Holds a token per instance running a call
+	memory    uint32
+	before    bool
+	after     bool
+
+	queue  chan models.ScoreEvent
+	mu     sync.Mutex // Guards closed against ScoreSubmitted
+	closed bool
+	done   chan struct{}
+}
+
+// Option configures optional WASMHook behavior
+type Option func(*WASMHook)
+
+// WithLogger sets the logger for module messages and failed calls
+func WithLogger(logger *slog.Logger) Option {
+	return func(h *WASMHook) {
+		h.logger = logger
+	}
+}
+
+// WithTimeout limits how long each call into the module may run
+func WithTimeout(timeout time.Duration) Option {
+	return func(h *WASMHook) {
+		h.timeout = timeout
+	}
+}
+
+// WithInstances sets how many instances of the module may run calls at once, and how
+// many idle ones are kept for reuse; at least one
+func WithInstances(n int) Option {
+	return func(h *WASMHook) {
+		n = max(n, 1)
+		h.instances = make(chan api.Module, n)
+		h.busy = make(chan struct{}, n)
+	}
+}
+
+// WithMemoryLimit caps each instance's memory, in 64 KiB pages
+func WithMemoryLimit(pages uint32) Option {
+	return func(h *WASMHook) {
+		h.memory = pages
+	}
+}
+
+// LoadWASM compiles the module at path, like NewWASMHook
+func LoadWASM(ctx context.Context, path string, opts ...Option) (*WASMHook, error) {
+	module, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read submission hook: %w", err)
+	}
+	return NewWASMHook(ctx, module, opts...)
+}
+
+// NewWASMHook compiles a module and checks it exports what the hooks need, then
+// instantiates it once so broken modules fail here rather than on submissions. Close
+// releases it.
+func NewWASMHook(ctx context.Context, module []byte, opts ...Option) (*WASMHook, error) {
+	h := &WASMHook{
+		logger:    slog.Default(),
+		timeout:   DefaultTimeout,
+		instances: make(chan api.Module, DefaultInstances),
+		busy:      make(chan struct{}, DefaultInstances),
+		memory:    DefaultMemoryPages,
+		queue:     make(chan models.ScoreEvent, DefaultQueueSize),
+		done:      make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.runtime = wazero.NewRuntimeWithConfig(ctx, wazero.NewRuntimeConfig().
+		WithCloseOnContextDone(true).
+		WithMemoryLimitPages(h.memory))
+	ok := false
+	defer func() {
+		if !ok {
+			h.runtime.Close(ctx)
+		}
+	}()
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, h.runtime); err != nil {
+		return nil, fmt.Errorf("failed to set up WASI: %w", err)
+	}
+	_, err := h.runtime.NewHostModuleBuilder("rawboard").
+		NewFunctionBuilder().WithFunc(h.hostLog).Export("log").
+		Instantiate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up host functions: %w", err)
+	}
+
+	h.compiled, err = h.runtime.CompileModule(ctx, module)
+	if err != nil {
+		return nil, fmt.Errorf("invalid submission hook module: %w", err)
+	}
+	exports := h.compiled.ExportedFunctions()
+	if _, found := exports["alloc"]; !found {
+		return nil, errors.New("submission hook module must export alloc")
+	}
+	if _, found := h.compiled.ExportedMemories()["memory"]; !found {
+		return nil, errors.New("submission hook module must export memory")
+	}
+	_, h.before = exports["before_submit"]
+	_, h.after = exports["after_submit"]
+	if !h.before && !h.after {
+		return nil, errors.New("submission hook module must export before_submit or after_submit")
+	}
+
+	instance, err := h.instantiate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.keep(ctx, instance)
+
+	ok = true
+	go h.run()
+	return h, nil
+}
+
+// BeforeSubmit hands a submission to the module's before_submit, applying its changes
+// or refusing the submission as it replies
+func (h *WASMHook) BeforeSubmit(ctx context.Context, gameID string, submission *models.Submission) error {
+	if !h.before {
+		return nil
+	}
+
+	input := beforeSubmitInput{
+		GameID:    gameID,
+		Initials:  submission.Initials,
+		Score:     submission.Score,
+		Metadata:  submission.Metadata,
+		SessionID: submission.SessionID,
+	}
+	if p := apikeys.PrincipalFromContext(ctx); p != nil {
+		input.DeviceID = p.Device
+	}
+	reply, err := h.call(ctx, "before_submit", input)
+	if err != nil || reply == nil {
+		return err
+	}
+
+	var output beforeSubmitOutput
+	if err := json.Unmarshal(reply, &output); err != nil {
+		return fmt.Errorf("before_submit replied with invalid JSON: %w", err)
+	}
+	if output.Reject != nil {
+		return &models.SubmissionRejectedError{Reason: *output.Reject}
+	}
+	if output.Initials != nil {
+		submission.Initials = *output.Initials
+	}
+	if output.Score != nil {
+		submission.Score = *output.Score
+	}
+	if output.Metadata != nil {
+		submission.Metadata = output.Metadata
+	}
+	return nil
+}
+
+// ScoreSubmitted queues a stored score for the module's after_submit, dropping it when
+// the module has fallen too far behind
+func (h *WASMHook) ScoreSubmitted(event models.ScoreEvent) {
+	if !h.after {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	select {
+	case h.queue <- event:
+	default:
+		h.logger.Warn("submission hook queue full, dropping after_submit call", "game_id", event.GameID)
+	}
+}
+
+// Close waits for queued after_submit calls, up to ctx's deadline, then releases the
+// module
+func (h *WASMHook) Close(ctx context.Context) error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	select {
+	case <-h.done:
+	case <-ctx.Done():
+	}
+	return h.runtime.Close(ctx)
+}
+
+// run feeds queued scores to after_submit one at a time
+func (h *WASMHook) run() {
+	defer close(h.done)
+	for event := range h.queue {
+		input := afterSubmitInput{
+			GameID:            event.GameID,
+			Tenant:            event.Tenant,
+			Entry:             event.Entry,
+			PreviousHighScore: event.PreviousHighScore,
+			NewHighScore:      event.NewHighScore,
+		}
+		if _, err := h.call(context.Background(), "after_submit", input); err != nil {
+			h.logger.Warn("submission hook after_submit failed", "game_id", event.GameID, "error", err)
+		}
+	}
+}
+
+// call passes input to one of the module's exports in an idle instance, returning the
+// reply a before_submit left in memory
+func (h *WASMHook) call(ctx context.Context, name string, input interface{}) ([]byte, error) {
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	instance, err := h.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := h.invoke(ctx, instance, name, payload)
+	if err != nil {
+		// A trapped or timed out instance may be left in any state
+		instance.Close(context.Background())
+		<-h.busy
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	h.release(ctx, instance)
+	return reply, nil
+}
+
+func (h *WASMHook) invoke(ctx context.Context, instance api.Module, name string, payload []byte) ([]byte, error) {
+	results, err := instance.ExportedFunction("alloc").Call(ctx, uint64(len(payload)))
+	if err != nil {
+		return nil, err
+	}
+	ptr := uint32(results[0])
+	if !instance.Memory().Write(ptr, payload) {
+		return nil, fmt.Errorf("alloc returned %d, outside the module's memory", ptr)
+	}
+
+	results, err = instance.ExportedFunction(name).Call(ctx, uint64(ptr), uint64(len(payload)))
+	if err != nil || len(results) == 0 || results[0] == 0 {
+		return nil, err
+	}
+
+	replyPtr, replyLen := uint32(results[0]>>32), uint32(results[0])
+	if replyLen > maxReply {
+		return nil, fmt.Errorf("reply of %d bytes is over the %d byte limit", replyLen, maxReply)
+	}
+	reply, ok := instance.Memory().Read(replyPtr, replyLen)
+	if !ok {
+		return nil, fmt.Errorf("reply at %d is outside the module's memory", replyPtr)
+	}
+	// The view is the module's memory, which the next call may overwrite
+	return append([]byte(nil), reply...), nil
+}
+
+// acquire waits until fewer instances than allowed are busy, up to ctx's deadline,
+// then returns an idle instance, or a new one when none is idle
+func (h *WASMHook) acquire(ctx context.Context) (api.Module, error) {
+	select {
+	case h.busy <- struct{}{}:
+	case <-ctx.Done():
+		return nil, fmt.Errorf("all %d submission hook instances are busy: %w", cap(h.busy), ctx.Err())
+	}
+
+	select {
+	case instance := <-h.instances:
+		return instance, nil
+	default:
+		instance, err := h.instantiate(ctx)
+		if err != nil {
+			<-h.busy
+			return nil, err
+		}
+		return instance, nil
+	}
+}
+
+// release returns a busy instance to the pool
+func (h *WASMHook) release(ctx context.Context, instance api.Module) {
+	h.keep(ctx, instance)
+	<-h.busy
+}
+
+// keep holds an instance for reuse, closing it when enough are idle
+func (h *WASMHook) keep(ctx context.Context, instance api.Module) {
+	select {
+	case h.instances <- instance:
+	default:
+		instance.Close(ctx)
+	}
+}
+
+func (h *WASMHook) instantiate(ctx context.Context) (api.Module, error) {
+	// Instances are anonymous so any number can run side by side
+	config := wazero.NewModuleConfig().WithName("").WithStartFunctions("_initialize")
+	instance, err := h.runtime.InstantiateModule(ctx, h.compiled, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to instantiate submission hook: %w", err)
+	}
+	return instance, nil
+}
+
+// hostLog is rawboard.log, writing a module's message to the server log
+func (h *WASMHook) hostLog(ctx context.Context, module api.Module, ptr, length uint32) {
+	message, ok := module.Memory().Read(ptr, length)
+	if !ok {
+		return
+	}
+	h.logger.Info("submission hook", "message", string(message))
+}
+
+// beforeSubmitInput is the submission passed to before_submit
+type beforeSubmitInput struct {
+	GameID    string               `json:"game_id"`
+	Initials  string               `json:"initials"`
+	Score     int64                `json:"score"`
+	Metadata  models.ScoreMetadata `json:"metadata,omitempty"`
+	SessionID string               `json:"session_id,omitempty"`
+	DeviceID  string               `json:"device_id,omitempty"`
+}
+
+// beforeSubmitOutput is a before_submit reply; absent fields are left alone
+type beforeSubmitOutput struct {
+	Reject   *string              `json:"reject"`
+	Initials *string              `json:"initials"`
+	Score    *int64               `json:"score"`
+	Metadata models.ScoreMetadata `json:"metadata"`
+}
+
+// afterSubmitInput is the stored score passed to after_submit
+type afterSubmitInput struct {
+	GameID            string             `json:"game_id"`
+	Tenant            string             `json:"tenant,omitempty"`
+	Entry             models.ScoreEntry  `json:"entry"`
+	PreviousHighScore *models.ScoreEntry `json:"previous_high_score,omitempty"`
+	NewHighScore      bool               `json:"new_high_score"`
+}
@@ -0,0 +1,231 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// Opcodes the test modules use
+const (
+	opLoop     = 0x03
+	opBr       = 0x0c
+	opEnd      = 0x0b
+	opCall     = 0x10
+	opLocalGet = 0x20
+	opI32Const = 0x41
+	opI64Const = 0x42
+)
+
+// dataOffset is where a test module keeps its reply
+const dataOffset = 16
+
+// testModule assembles a module whose before_submit logs its input with rawboard.log and
+// returns reply, or 0 when reply is empty, and whose after_submit logs its input. A
+// non-nil beforeBody replaces before_submit's body.
+func testModule(reply string, beforeBody []byte) []byte {
+	const i32, i64 = 0x7f, 0x7e
+	types := vec(
+		append([]byte{0x60}, append(vec([]byte{i32}), vec([]byte{i32})...)...),              // alloc
+		append([]byte{0x60}, append(vec([]byte{i32}, []byte{i32}), vec([]byte{i64})...)...), // before_submit
+		append([]byte{0x60}, append(vec([]byte{i32}, []byte{i32}), vec()...)...),            // log, after_submit
+	)
+	imports := vec(concat(name("rawboard"), name("log"), []byte{0x00, 2}))
+	functions := vec([]byte{0}, []byte{1}, []byte{2})
+	memory := vec([]byte{0x00, 1})
+	exports := vec(
+		concat(name("memory"), []byte{0x02, 0}),
+		concat(name("alloc"), []byte{0x00, 1}),
+		concat(name("before_submit"), []byte{0x00, 2}),
+		concat(name("after_submit"), []byte{0x00, 3}),
+	)
+
+	logInput := []byte{opLocalGet, 0, opLocalGet, 1, opCall, 0}
+	var packed int64
+	if reply != "" {
+		packed = dataOffset<<32 | int64(len(reply))
+	}
+	if beforeBody == nil {
+		beforeBody = concat(logInput, []byte{opI64Const}, sleb(packed), []byte{opEnd})
+	}
+	code := vec(
+		body(concat([]byte{opI32Const}, sleb(1024), []byte{opEnd})),
+		body(beforeBody),
+		body(concat(logInput, []byte{opEnd})),
+	)
+	data := vec(concat([]byte{0x00, opI32Const}, sleb(dataOffset), []byte{opEnd}, name(reply)))
+
+	return concat(
+		[]byte{0x00, 'a', 's', 'm', 0x01, 0x00, 0x00, 0x00},
+		section(1, types), section(2, imports), section(3, functions), section(5, memory),
+		section(7, exports), section(10, code), section(11, data),
+	)
+}
+
+func section(id byte, contents []byte) []byte {
+	return concat([]byte{id}, uleb(uint64(len(contents))), contents)
+}
+
+func vec(items ...[]byte) []byte {
+	return concat(append([][]byte{uleb(uint64(len(items)))}, items...)...)
+}
+
+func name(s string) []byte {
+	return concat(uleb(uint64(len(s))), []byte(s))
+}
+
+// body is a function body without locals
+func body(instructions []byte) []byte {
+	b := concat([]byte{0x00}, instructions)
+	return concat(uleb(uint64(len(b))), b)
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func uleb(v uint64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if v == 0 {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func sleb(v int64) []byte {
+	var out []byte
+	for {
+		b := byte(v & 0x7f)
+		v >>= 7
+		if (v == 0 && b&0x40 == 0) || (v == -1 && b&0x40 != 0) {
+			return append(out, b)
+		}
+		out = append(out, b|0x80)
+	}
+}
+
+func newHook(t *testing.T, module []byte, opts ...Option) *WASMHook {
+	t.Helper()
+	hook, err := NewWASMHook(context.Background(), module, opts...)
+	if err != nil {
+		t.Fatalf("NewWASMHook() error = %v", err)
+	}
+	t.Cleanup(func() { hook.Close(context.Background()) })
+	return hook
+}
+
+func TestWASMHookBeforeSubmit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepts unchanged", func(t *testing.T) {
+		var logs bytes.Buffer
+		hook := newHook(t, testModule("", nil), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+		submission := models.Submission{Initials: "ABC", Score: 100}
+		if err := hook.BeforeSubmit(ctx, "pacman", &submission); err != nil {
+			t.Fatalf("BeforeSubmit() error = %v", err)
+		}
+		if submission.Initials != "ABC" || submission.Score != 100 {
+			t.Errorf("submission = %+v, want it unchanged", submission)
+		}
+		if !strings.Contains(logs.String(), `game_id\":\"pacman`) {
+			t.Errorf("module logged %q, want the submission's game", logs.String())
+		}
+	})
+
+	t.Run("transforms", func(t *testing.T) {
+		hook := newHook(t, testModule(`{"initials":"ZZZ","score":42,"metadata":{"level":3}}`, nil))
+
+		submission := models.Submission{Initials: "ABC", Score: 100, SessionID: "s1"}
+		if err := hook.BeforeSubmit(ctx, "pacman", &submission); err != nil {
+			t.Fatalf("BeforeSubmit() error = %v", err)
+		}
+		if submission.Initials != "ZZZ" || submission.Score != 42 || submission.Metadata["level"] != float64(3) {
+			t.Errorf("submission = %+v, want the module's changes", submission)
+		}
+		if submission.SessionID != "s1" {
+			t.Errorf("SessionID = %q, want fields the reply left out kept", submission.SessionID)
+		}
+	})
+
+	t.Run("rejects", func(t *testing.T) {
+		hook := newHook(t, testModule(`{"reject":"no bots"}`, nil))
+
+		err := hook.BeforeSubmit(ctx, "pacman", &models.Submission{Initials: "ABC", Score: 100})
+		var rejected *models.SubmissionRejectedError
+		if !errors.As(err, &rejected) || rejected.Reason != "no bots" {
+			t.Fatalf("BeforeSubmit() error = %v, want a rejection for no bots", err)
+		}
+		if !errors.Is(err, models.ErrSubmissionRejected) {
+			t.Error("rejection should match ErrSubmissionRejected")
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		// before_submit loops forever
+		spin := []byte{opLoop, 0x40, opBr, 0, opEnd, opI64Const, 0, opEnd}
+		hook := newHook(t, testModule("", spin), WithTimeout(20*time.Millisecond))
+
+		err := hook.BeforeSubmit(ctx, "pacman", &models.Submission{Initials: "ABC", Score: 100})
+		if err == nil || errors.Is(err, models.ErrSubmissionRejected) {
+			t.Fatalf("BeforeSubmit() error = %v, want a failed call", err)
+		}
+	})
+}
+
+func TestWASMHookInstances(t *testing.T) {
+	ctx := context.Background()
+	hook := newHook(t, testModule("", nil), WithInstances(1))
+
+	instance, err := hook.acquire(ctx)
+	if err != nil {
+		t.Fatalf("acquire() error = %v", err)
+	}
+	waiting, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if _, err := hook.acquire(waiting); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("acquire() error = %v, want a timeout while the only instance is busy", err)
+	}
+
+	hook.release(ctx, instance)
+	if err := hook.BeforeSubmit(ctx, "pacman", &models.Submission{Initials: "ABC", Score: 100}); err != nil {
+		t.Errorf("BeforeSubmit() error = %v, want the released instance reused", err)
+	}
+}
+
+func TestWASMHookAfterSubmit(t *testing.T) {
+	var logs bytes.Buffer
+	hook := newHook(t, testModule("", nil), WithLogger(slog.New(slog.NewTextHandler(&logs, nil))))
+
+	hook.ScoreSubmitted(models.ScoreEvent{
+		GameID:       "pacman",
+		Entry:        models.ScoreEntry{Initials: "ABC", Score: 100},
+		NewHighScore: true,
+	})
+	// Close waits for the queued call
+	if err := hook.Close(context.Background()); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !strings.Contains(logs.String(), `new_high_score\":true`) {
+		t.Errorf("module logged %q, want the stored score", logs.String())
+	}
+
+	// Scores after Close are dropped rather than panicking
+	hook.ScoreSubmitted(models.ScoreEvent{GameID: "pacman"})
+}
+
+func TestNewWASMHookInvalid(t *testing.T) {
+	if _, err := NewWASMHook(context.Background(), []byte("not wasm")); err == nil {
+		t.Error("NewWASMHook() should refuse a file that isn't WebAssembly")
+	}
+}
@@ -0,0 +1,162 @@
+// Package inbound parses score submissions that arrive as email, for legacy
+// cabinets and kiosk software that can't speak HTTP
+package inbound
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"strconv"
+	"strings"
+)
+
+// ErrNoScore is returned for emails that don't carry a complete score
+var ErrNoScore = errors.New("email does not contain a GAME, INITIALS and SCORE line")
+
+// Email is an inbound message after provider-specific decoding
+type Email struct {
+	From    string // Bare sender address, e.g. cabinet7@arcade.example
+	Subject string
+	Body    string // Plain-text body
+}
+
+// ScoreEmail is the score an email submits
+type ScoreEmail struct {
+	GameID   string
+	Initials string
+	Score    int64
+}
+
+// ParseScore reads a score from an email body of "Key: value" lines:
+//
+//	GAME: pacman
+//	INITIALS: AAA
+//	SCORE: 12,500
+//
+// Keys are case-insensitive and any other lines, such as signatures, are ignored.
+// The first occurrence of each key wins.
+func ParseScore(body string) (*ScoreEmail, error) {
+	fields := make(map[string]string)
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if _, seen := fields[key]; !seen {
+			fields[key] = strings.TrimSpace(value)
+		}
+	}
+
+	gameID, initials, scoreStr := fields["GAME"], fields["INITIALS"], fields["SCORE"]
+	if gameID == "" || initials == "" || scoreStr == "" {
+		return nil, ErrNoScore
+	}
+
+	// Kiosk software often formats scores with thousands separators
+	score, err := strconv.ParseInt(strings.NewReplacer(",", "", "_", "", " ", "").Replace(scoreStr), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SCORE %q", scoreStr)
+	}
+
+	return &ScoreEmail{GameID: gameID, Initials: strings.ToUpper(initials), Score: score}, nil
+}
+
+// parseAddress returns the bare address from a From header value
+func parseAddress(from string) (string, error) {
+	address, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	return strings.ToLower(address.Address), nil
+}
+
+// parseMIME decodes a raw RFC 5322 message, extracting its first text/plain part
+func parseMIME(raw io.Reader) (*Email, error) {
+	msg, err := mail.ReadMessage(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email: %w", err)
+	}
+
+	from, err := parseAddress(msg.Header.Get("From"))
+	if err != nil {
+		return nil, err
+	}
+
+	subject := msg.Header.Get("Subject")
+	if decoded, err := new(mime.WordDecoder).DecodeHeader(subject); err == nil {
+		subject = decoded
+	}
+
+	body, err := plainText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Email{From: from, Subject: subject, Body: body}, nil
+}
+
+// plainText returns the text/plain content of a MIME entity, searching multipart bodies
+func plainText(contentType, encoding string, body io.Reader) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if contentType == "" || err != nil {
+		mediaType = "text/plain" // RFC 2045 default
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		parts := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := parts.NextRawPart()
+			if err == io.EOF {
+				return "", fmt.Errorf("email has no text/plain part")
+			}
+			if err != nil {
+				return "", fmt.Errorf("invalid multipart email: %w", err)
+			}
+			text, err := plainText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err == nil {
+				return text, nil
+			}
+		}
+	}
+
+	if mediaType != "text/plain" {
+		return "", fmt.Errorf("unsupported content type %s", mediaType)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	text, err := io.ReadAll(body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read email body: %w", err)
+	}
+	return string(text), nil
+}
+
+// Config enables the email gateway's provider webhooks
+type Config struct {
+	MailgunSigningKey string   // Enables the Mailgun webhook
+	SESSecret         string   // Enables the SES webhook, which must be called with ?secret=
+	AllowedSenders    []string // Addresses that may submit scores
+}
+
+// Allows reports whether sender may submit scores
+func (c Config) Allows(sender string) bool {
+	for _, allowed := range c.AllowedSenders {
+		if strings.EqualFold(allowed, sender) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseScore(t *testing.T) {
+	t.Run("reads key-value lines and ignores the rest", func(t *testing.T) {
+		body := "High score report\r\n\r\ngame: pacman\r\nInitials: aaa\r\nSCORE: 12,500\r\n\r\n--\r\nSent from KioskMail 2.1: do not reply\r\n"
+		score, err := ParseScore(body)
+		if err != nil {
+			t.Fatalf("ParseScore failed: %v", err)
+		}
+		if score.GameID != "pacman" || score.Initials != "AAA" || score.Score != 12500 {
+			t.Errorf("Unexpected score: %+v", score)
+		}
+	})
+
+	t.Run("requires every field", func(t *testing.T) {
+		if _, err := ParseScore("GAME: pacman\nSCORE: 100\n"); !errors.Is(err, ErrNoScore) {
+			t.Errorf("Expected ErrNoScore, got %v", err)
+		}
+	})
+
+	t.Run("rejects non-numeric scores", func(t *testing.T) {
+		if _, err := ParseScore("GAME: pacman\nINITIALS: AAA\nSCORE: lots\n"); err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+// signMailgun adds a Mailgun signature to form
+func signMailgun(form url.Values, key string, sent time.Time) {
+	timestamp := strconv.FormatInt(sent.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(timestamp + "token-1"))
+	form.Set("timestamp", timestamp)
+	form.Set("token", "token-1")
+	form.Set("signature", hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestParseMailgun(t *testing.T) {
+	now := time.Now()
+	form := url.Values{
+		"from":       {"Cabinet 7 <Cabinet7@Arcade.example>"},
+		"subject":    {"Score"},
+		"body-plain": {"GAME: pacman\nINITIALS: AAA\nSCORE: 100\n"},
+	}
+
+	t.Run("accepts signed posts", func(t *testing.T) {
+		signMailgun(form, "signing-key", now)
+		email, err := ParseMailgun(form, "signing-key", now)
+		if err != nil {
+			t.Fatalf("ParseMailgun failed: %v", err)
+		}
+		if email.From != "cabinet7@arcade.example" || !strings.Contains(email.Body, "pacman") {
+			t.Errorf("Unexpected email: %+v", email)
+		}
+	})
+
+	t.Run("rejects bad signatures and stale posts", func(t *testing.T) {
+		signMailgun(form, "other-key", now)
+		if _, err := ParseMailgun(form, "signing-key", now); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature for the wrong key, got %v", err)
+		}
+
+		signMailgun(form, "signing-key", now.Add(-time.Hour))
+		if _, err := ParseMailgun(form, "signing-key", now); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature for a replayed post, got %v", err)
+		}
+	})
+}
+
+// sesMessage wraps raw MIME in an SES receipt notification
+func sesMessage(t *testing.T, content string) string {
+	t.Helper()
+	data, err := json.Marshal(map[string]interface{}{
+		"notificationType": "Received",
+		"mail":             map[string]interface{}{"source": "bounce@arcade.example"},
+		"content":          content,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestParseSES(t *testing.T) {
+	multipartEmail := strings.Join([]string{
+		"From: cabinet7@arcade.example",
+		"Subject: =?UTF-8?Q?High_score?=",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/alternative; boundary="b1"`,
+		"",
+		"--b1",
+		"Content-Type: text/html",
+		"",
+		"<p>GAME: tetris</p>",
+		"--b1",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+		"",
+		"GAME: pacman=0D",
+		"INITIALS: AAA=0D",
+		"SCORE: 12=",
+		"500",
+		"--b1--",
+		"",
+	}, "\r\n")
+
+	for name, content := range map[string]string{
+		"raw MIME":    multipartEmail,
+		"base64 MIME": base64.StdEncoding.EncodeToString([]byte(multipartEmail)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			email, err := ParseSES(sesMessage(t, content))
+			if err != nil {
+				t.Fatalf("ParseSES failed: %v", err)
+			}
+			if email.From != "cabinet7@arcade.example" || email.Subject != "High score" {
+				t.Errorf("Unexpected email: %+v", email)
+			}
+
+			score, err := ParseScore(email.Body)
+			if err != nil {
+				t.Fatalf("ParseScore failed: %v", err)
+			}
+			if score.GameID != "pacman" || score.Score != 12500 {
+				t.Errorf("Expected the text/plain part, got %+v", score)
+			}
+		})
+	}
+
+	t.Run("requires the message content", func(t *testing.T) {
+		if _, err := ParseSES(sesMessage(t, "")); err == nil {
+			t.Error("Expected an error for a notification without content")
+		}
+	})
+}
+
+func TestConfigAllows(t *testing.T) {
+	config := Config{AllowedSenders: []string{"CABINET7@ARCADE.EXAMPLE"}}
+	if !config.Allows("cabinet7@arcade.example") || config.Allows("intruder@arcade.example") {
+		t.Error("Expected case-insensitive sender matching")
+	}
+}
@@ -0,0 +1,47 @@
+package inbound
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MailgunMaxAge bounds how old a Mailgun webhook may be, limiting replays
+const MailgunMaxAge = 15 * time.Minute
+
+// ErrInvalidSignature is returned for webhooks that can't be shown to come from the provider
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// ParseMailgun verifies and decodes a message forwarded by a Mailgun route.
+// Mailgun signs each post with the account's webhook signing key.
+func ParseMailgun(form url.Values, signingKey string, now time.Time) (*Email, error) {
+	timestamp, token, signature := form.Get("timestamp"), form.Get("token"), form.Get("signature")
+
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(timestamp + token))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if signingKey == "" || !hmac.Equal([]byte(signature), []byte(expected)) {
+		return nil, ErrInvalidSignature
+	}
+
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || now.Sub(time.Unix(sent, 0)).Abs() > MailgunMaxAge {
+		return nil, ErrInvalidSignature
+	}
+
+	// from is the header, sender the envelope; legacy relays sometimes only set one
+	from := form.Get("from")
+	if from == "" {
+		from = form.Get("sender")
+	}
+	address, err := parseAddress(from)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Email{From: address, Subject: form.Get("subject"), Body: form.Get("body-plain")}, nil
+}
@@ -0,0 +1,64 @@
+package inbound
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SNS message types delivered to the SES webhook
+const (
+	SNSNotification             = "Notification"
+	SNSSubscriptionConfirmation = "SubscriptionConfirmation"
+)
+
+// SNSMessage is the Amazon SNS envelope SES receipt notifications arrive in
+type SNSMessage struct {
+	Type         string `json:"Type"`
+	MessageID    string `json:"MessageId"`
+	TopicArn     string `json:"TopicArn"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL,omitempty"` // Visit to confirm a new subscription
+}
+
+// sesNotification is the part of an SES "Received" notification the gateway reads
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	Content          string `json:"content"` // Raw MIME, base64-encoded when the SNS action's encoding is Base64
+}
+
+// ParseSNS decodes an SNS HTTP delivery
+func ParseSNS(body []byte) (*SNSMessage, error) {
+	var msg SNSMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, fmt.Errorf("invalid SNS message: %w", err)
+	}
+	if msg.Type == "" {
+		return nil, fmt.Errorf("invalid SNS message: missing Type")
+	}
+	return &msg, nil
+}
+
+// ParseSES decodes the email in an SES receipt notification. The receipt rule must
+// use an SNS action, since only that includes the message content.
+func ParseSES(message string) (*Email, error) {
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(message), &notification); err != nil {
+		return nil, fmt.Errorf("invalid SES notification: %w", err)
+	}
+	if notification.NotificationType != "Received" {
+		return nil, fmt.Errorf("unsupported SES notification type %q", notification.NotificationType)
+	}
+	if notification.Content == "" {
+		return nil, fmt.Errorf("SES notification has no content; use an SNS receipt action")
+	}
+
+	// Raw MIME always contains characters outside the base64 alphabet
+	raw := []byte(notification.Content)
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(notification.Content)); err == nil {
+		raw = decoded
+	}
+	return parseMIME(bytes.NewReader(raw))
+}
@@ -0,0 +1,109 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Job is a task run periodically by the scheduler
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs registered jobs on their intervals until stopped
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []Job
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running bool
+	logger  *slog.Logger
+}
+
+// NewScheduler creates an empty scheduler that logs job runs to logger
+func NewScheduler(logger *slog.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Add registers a job; jobs added after Start are not run
+func (s *Scheduler) Add(job Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job in its own goroutine
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running {
+		return
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	s.running = true
+
+	for _, job := range s.jobs {
+		if job.Interval <= 0 {
+			s.logger.Warn("job has no interval, skipping", "job", job.Name)
+			continue
+		}
+
+		s.wg.Add(1)
+		go s.loop(ctx, job)
+	}
+}
+
+// Stop cancels running jobs and waits for in-flight runs to finish
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	if !s.running {
+		s.mu.Unlock()
+		return
+	}
+	s.cancel()
+	s.running = false
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+// loop runs a job every interval until the context is cancelled
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.runOnce(ctx, job)
+		}
+	}
+}
+
+// runOnce executes a job, recovering from panics so one bad run can't stop the loop
+func (s *Scheduler) runOnce(ctx context.Context, job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Error("job panicked", "job", job.Name, "panic", fmt.Sprint(r))
+		}
+	}()
+
+	start := time.Now()
+	if err := job.Run(ctx); err != nil {
+		s.logger.Error("job failed", "job", job.Name, "duration_ms", time.Since(start).Milliseconds(), "error", err)
+		return
+	}
+	s.logger.Info("job completed", "job", job.Name, "duration_ms", time.Since(start).Milliseconds())
+}
@@ -0,0 +1,144 @@
+package leaderboard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrBadgeNotFound is returned for achievements without an uploaded badge
+var ErrBadgeNotFound = errors.New("achievement badge not found")
+
+func achievementBadgeKey(gameID, achievementID string) string {
+	return fmt.Sprintf("achievement_badge:%s:%s", gameID, achievementID)
+}
+
+// badgePath returns where an achievement's badge is served for the tenant ctx acts for.
+// The checksum versions the path, so displays can cache a badge until it's replaced.
+func badgePath(ctx context.Context, gameID, achievementID, checksum string) string {
+	base := "/api/v1"
+	if tenant := tenants.FromContext(ctx); tenant != "" {
+		base += "/tenants/" + tenant
+	}
+	return fmt.Sprintf("%s/games/%s/achievements/%s/badge?v=%s",
+		base, url.PathEscape(gameID), url.PathEscape(achievementID), checksum[:8])
+}
+
+// PutAchievementBadge uploads the image one of a game's achievements is shown with,
+// replacing any before it. A game using the defaults gets its own copy of them first.
+func (s *Service) PutAchievementBadge(ctx context.Context, gameID, achievementID string, data []byte) (*models.AchievementDefinition, error) {
+	contentType, err := models.BadgeContentType(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("failed to register game: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	badge := models.AchievementBadge{ContentType: contentType, Data: data, Checksum: hex.EncodeToString(sum[:]), Updated: time.Now()}
+	return s.setAchievementBadge(ctx, gameID, achievementID, func(definition *models.AchievementDefinition) error {
+		if err := s.saveJSON(ctx, achievementBadgeKey(gameID, achievementID), badge); err != nil {
+			return err
+		}
+		definition.Badge = badgePath(ctx, gameID, achievementID, badge.Checksum)
+		return nil
+	})
+}
+
+// DeleteAchievementBadge removes an achievement's uploaded badge, so it's shown with its
+// icon again
+func (s *Service) DeleteAchievementBadge(ctx context.Context, gameID, achievementID string) (*models.AchievementDefinition, error) {
+	return s.setAchievementBadge(ctx, gameID, achievementID, func(definition *models.AchievementDefinition) error {
+		if definition.Badge == "" {
+			return ErrBadgeNotFound
+		}
+		// An empty badge reads as none
+		if err := s.saveJSON(ctx, achievementBadgeKey(gameID, achievementID), models.AchievementBadge{}); err != nil {
+			return err
+		}
+		definition.Badge = ""
+		return nil
+	})
+}
+
+// AchievementBadge returns the badge uploaded for one of a game's achievements
+func (s *Service) AchievementBadge(ctx context.Context, gameID, achievementID string) (*models.AchievementBadge, error) {
+	data, err := s.db.Get(ctx, achievementBadgeKey(gameID, achievementID))
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrBadgeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get achievement badge: %w", err)
+	}
+	var badge models.AchievementBadge
+	if err := json.Unmarshal([]byte(data), &badge); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal achievement badge: %w", err)
+	}
+	if len(badge.Data) == 0 {
+		return nil, ErrBadgeNotFound
+	}
+	return &badge, nil
+}
+
+// SetAchievementTheme changes the built-in icon theme a game's achievements are shown
+// with; "" or classic shows each achievement's own icon. Uploaded badges are unaffected.
+func (s *Service) SetAchievementTheme(ctx context.Context, gameID, theme string) (*models.GameInfo, error) {
+	if !models.ValidAchievementTheme(theme) {
+		return nil, fmt.Errorf("unknown achievement theme %q", theme)
+	}
+	if theme == models.AchievementThemeClassic {
+		theme = ""
+	}
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.AchievementTheme = theme
+		return nil
+	})
+}
+
+// setAchievementBadge updates one of a game's definitions with change and saves them
+func (s *Service) setAchievementBadge(ctx context.Context, gameID, achievementID string, change func(*models.AchievementDefinition) error) (*models.AchievementDefinition, error) {
+	s.achievementMu.Lock()
+	defer s.achievementMu.Unlock()
+
+	definitions, _, err := s.achievementDefinitions(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range definitions {
+		if definitions[i].ID != achievementID {
+			continue
+		}
+		if err := change(&definitions[i]); err != nil {
+			return nil, err
+		}
+		if err := s.saveJSON(ctx, achievementDefinitionsKey(gameID), achievementDefinitions{Achievements: definitions, Updated: time.Now()}); err != nil {
+			return nil, err
+		}
+		return &definitions[i], nil
+	}
+	return nil, ErrAchievementNotFound
+}
+
+// themedAchievements returns definitions with their icons from the game's theme
+func (s *Service) themedAchievements(ctx context.Context, gameID string, definitions []models.AchievementDefinition) []models.AchievementDefinition {
+	theme, ok := models.AchievementThemes[s.gameSettings(ctx, gameID).AchievementTheme]
+	if !ok {
+		return definitions
+	}
+	themed := make([]models.AchievementDefinition, len(definitions))
+	for i, definition := range definitions {
+		themed[i] = theme.Apply(definition)
+	}
+	return themed
+}
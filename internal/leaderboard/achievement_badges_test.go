@@ -0,0 +1,145 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+// pngHeader is enough of a PNG for content sniffing
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestAchievementBadges(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("uploaded badges appear in achievement payloads", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		definition, err := service.PutAchievementBadge(ctx, "pacman", "first_score", pngHeader)
+		if err != nil {
+			t.Fatalf("PutAchievementBadge failed: %v", err)
+		}
+		if !strings.HasPrefix(definition.Badge, "/api/v1/games/pacman/achievements/first_score/badge?v=") {
+			t.Errorf("Expected a versioned badge path, got %q", definition.Badge)
+		}
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if len(result.Achievements) != 1 || result.Achievements[0].Badge != definition.Badge {
+			t.Errorf("Expected the unlock to carry the badge, got %+v", result.Achievements)
+		}
+
+		badge, err := service.AchievementBadge(ctx, "pacman", "first_score")
+		if err != nil {
+			t.Fatalf("AchievementBadge failed: %v", err)
+		}
+		if badge.ContentType != "image/png" || string(badge.Data) != string(pngHeader) {
+			t.Errorf("Expected the uploaded PNG, got %s", badge.ContentType)
+		}
+
+		// Replacing the definition keeps its badge
+		replaced := models.AchievementDefinition{ID: "first_score", Name: "Welcome", Type: models.AchievementPlays, Threshold: 1}
+		if _, err := service.PutAchievement(ctx, "pacman", replaced); err != nil {
+			t.Fatalf("PutAchievement failed: %v", err)
+		}
+		list, _ := service.AchievementDefinitions(ctx, "pacman")
+		if list.Achievements[0].Badge != definition.Badge {
+			t.Errorf("Expected the replaced achievement to keep its badge, got %+v", list.Achievements[0])
+		}
+	})
+
+	t.Run("removing a badge restores the icon", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.PutAchievementBadge(ctx, "pacman", "score_1k", pngHeader); err != nil {
+			t.Fatalf("PutAchievementBadge failed: %v", err)
+		}
+		definition, err := service.DeleteAchievementBadge(ctx, "pacman", "score_1k")
+		if err != nil {
+			t.Fatalf("DeleteAchievementBadge failed: %v", err)
+		}
+		if definition.Badge != "" || definition.Icon != "⭐" {
+			t.Errorf("Expected the icon without a badge, got %+v", definition)
+		}
+		if _, err := service.AchievementBadge(ctx, "pacman", "score_1k"); !errors.Is(err, ErrBadgeNotFound) {
+			t.Errorf("Expected ErrBadgeNotFound, got %v", err)
+		}
+		if _, err := service.DeleteAchievementBadge(ctx, "pacman", "score_1k"); !errors.Is(err, ErrBadgeNotFound) {
+			t.Errorf("Expected ErrBadgeNotFound removing it again, got %v", err)
+		}
+	})
+
+	t.Run("badges are served under the tenant's path", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		definition, err := service.PutAchievementBadge(tenants.WithTenant(ctx, "acme"), "pacman", "first_score", pngHeader)
+		if err != nil {
+			t.Fatalf("PutAchievementBadge failed: %v", err)
+		}
+		if !strings.HasPrefix(definition.Badge, "/api/v1/tenants/acme/games/pacman/") {
+			t.Errorf("Expected a tenant badge path, got %q", definition.Badge)
+		}
+	})
+
+	t.Run("rejects unknown achievements and images", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.PutAchievementBadge(ctx, "pacman", "missing", pngHeader); !errors.Is(err, ErrAchievementNotFound) {
+			t.Errorf("Expected ErrAchievementNotFound, got %v", err)
+		}
+		for name, data := range map[string][]byte{
+			"empty":     nil,
+			"svg":       []byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`),
+			"too large": append(append([]byte(nil), pngHeader...), make([]byte, models.MaxBadgeSize)...),
+		} {
+			if _, err := service.PutAchievementBadge(ctx, "pacman", "first_score", data); err == nil {
+				t.Errorf("Expected %s to be rejected", name)
+			}
+		}
+	})
+}
+
+func TestAchievementThemes(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake())
+
+	if _, err := service.SetAchievementTheme(ctx, "pacman", "neon"); err == nil {
+		t.Errorf("Expected an unknown theme to be rejected")
+	}
+	if _, err := service.SetAchievementTheme(ctx, "pacman", models.AchievementThemeMedals); err != nil {
+		t.Fatalf("SetAchievementTheme failed: %v", err)
+	}
+
+	result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 10000})
+	if err != nil {
+		t.Fatalf("Submit failed: %v", err)
+	}
+	icons := map[string]string{}
+	for _, achievement := range result.Achievements {
+		icons[achievement.ID] = achievement.Icon
+	}
+	if icons["score_10k"] != "🥇" || icons["first_score"] != "🎖️" {
+		t.Errorf("Expected medal icons by ID and type, got %v", icons)
+	}
+
+	// Definitions keep their own icons, so leaving the theme restores them
+	list, _ := service.AchievementDefinitions(ctx, "pacman")
+	if list.Theme != models.AchievementThemeMedals || list.Achievements[0].Icon != "🎯" {
+		t.Errorf("Expected unthemed definitions under the medals theme, got %+v", list)
+	}
+	if _, err := service.SetAchievementTheme(ctx, "pacman", models.AchievementThemeClassic); err != nil {
+		t.Fatalf("SetAchievementTheme failed: %v", err)
+	}
+	stats, err := service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+	if err != nil {
+		t.Fatalf("GetEnhancedPlayerStats failed: %v", err)
+	}
+	for _, achievement := range stats.Achievements {
+		if achievement.ID == "score_10k" && achievement.Icon != "💫" {
+			t.Errorf("Expected the classic icon, got %q", achievement.Icon)
+		}
+	}
+}
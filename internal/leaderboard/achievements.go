@@ -0,0 +1,405 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrAchievementNotFound is returned for achievements a game doesn't define
+var ErrAchievementNotFound = errors.New("achievement not found")
+
+// achievementDefinitions is a game's own achievement definitions, in display order
+type achievementDefinitions struct {
+	Achievements []models.AchievementDefinition `json:"achievements"`
+	Updated      time.Time                      `json:"updated"`
+}
+
+// achievementUnlocks records when each player of a game unlocked each achievement. A
+// player is listed once their history has been evaluated, even with nothing unlocked.
+type achievementUnlocks struct {
+	Players map[string]map[string]time.Time `json:"players"` // initials -> achievement ID -> unlocked at
+	Updated time.Time                       `json:"updated"`
+}
+
+func achievementDefinitionsKey(gameID string) string {
+	return fmt.Sprintf("achievement_definitions:%s", gameID)
+}
+
+func achievementUnlocksKey(gameID string) string {
+	return fmt.Sprintf("achievement_unlocks:%s", gameID)
+}
+
+// AchievementDefinitions returns the achievements a game's players can unlock
+func (s *Service) AchievementDefinitions(ctx context.Context, gameID string) (*models.AchievementDefinitionList, error) {
+	definitions, custom, err := s.achievementDefinitions(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AchievementDefinitionList{
+		GameID:       gameID,
+		Default:      !custom,
+		Theme:        s.gameSettings(ctx, gameID).AchievementTheme,
+		Achievements: definitions,
+	}, nil
+}
+
+// PutAchievement creates or replaces one of a game's achievements. A game using the
+// defaults gets its own copy of them first. Players whose history already meets the
+// achievement unlock it at once; unlocks and the badge of a replaced achievement are
+// kept.
+func (s *Service) PutAchievement(ctx context.Context, gameID string, definition models.AchievementDefinition) (*models.AchievementDefinition, error) {
+	if err := definition.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("failed to register game: %w", err)
+	}
+
+	s.achievementMu.Lock()
+	definitions, _, err := s.achievementDefinitions(ctx, gameID)
+	if err == nil {
+		replaced := false
+		for i, existing := range definitions {
+			if existing.ID == definition.ID {
+				definition.Badge = existing.Badge
+				definitions[i], replaced = definition, true
+			}
+		}
+		if !replaced && len(definitions) >= models.MaxAchievements {
+			err = fmt.Errorf("a game can define at most %d achievements", models.MaxAchievements)
+		} else if !replaced {
+			definitions = append(definitions, definition)
+		}
+	}
+	if err == nil {
+		err = s.saveJSON(ctx, achievementDefinitionsKey(gameID), achievementDefinitions{Achievements: definitions, Updated: time.Now()})
+	}
+	s.achievementMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	// Players are evaluated again on their next submission if this fails
+	if allScores, err := s.getAllScores(ctx, gameID); err == nil {
+		if _, _, _, err := s.recordAchievements(ctx, gameID, scoresByPlayer(allScores.Scores), false, time.Now()); err != nil {
+			s.log(ctx).Warn("failed to unlock achievement from history", "game_id", gameID, "achievement_id", definition.ID, "error", err)
+		}
+	}
+	return &definition, nil
+}
+
+// DeleteAchievement removes one of a game's achievements, its badge and every player's
+// unlock of it. A game using the defaults keeps the rest of them as its own.
+func (s *Service) DeleteAchievement(ctx context.Context, gameID, achievementID string) (*models.AchievementDefinition, error) {
+	s.achievementMu.Lock()
+	defer s.achievementMu.Unlock()
+
+	definitions, _, err := s.achievementDefinitions(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	var deleted *models.AchievementDefinition
+	kept := make([]models.AchievementDefinition, 0, len(definitions))
+	for _, definition := range definitions {
+		if definition.ID == achievementID {
+			deleted = &definition
+			continue
+		}
+		kept = append(kept, definition)
+	}
+	if deleted == nil {
+		return nil, ErrAchievementNotFound
+	}
+	if err := s.saveJSON(ctx, achievementDefinitionsKey(gameID), achievementDefinitions{Achievements: kept, Updated: time.Now()}); err != nil {
+		return nil, err
+	}
+	if deleted.Badge != "" {
+		if err := s.saveJSON(ctx, achievementBadgeKey(gameID, achievementID), models.AchievementBadge{}); err != nil {
+			return nil, err
+		}
+	}
+
+	unlocks, err := s.getAchievementUnlocks(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, unlocked := range unlocks.Players {
+		delete(unlocked, achievementID)
+	}
+	unlocks.Updated = time.Now()
+	if err := s.saveJSON(ctx, achievementUnlocksKey(gameID), unlocks); err != nil {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+// unlockAchievements records the achievements a counted entry, already in history,
+// unlocked for its player and returns them
+func (s *Service) unlockAchievements(ctx context.Context, gameID string, entry models.ScoreEntry, history *models.AllScoresRecord) []models.Achievement {
+	players := map[string][]models.ScoreEntry{entry.Initials: nil}
+	for _, scored := range history.Scores {
+		if scored.Initials == entry.Initials {
+			players[entry.Initials] = append(players[entry.Initials], scored)
+		}
+	}
+
+	_, _, added, err := s.recordAchievements(ctx, gameID, players, false, entry.Timestamp)
+	if err != nil {
+		s.log(ctx).Warn("failed to record achievements", "game_id", gameID, "initials", entry.Initials, "error", err)
+		return nil
+	}
+
+	// Achievements a player from before unlocks were stored met earlier aren't this entry's
+	var unlocked []models.Achievement
+	for _, achievement := range added[entry.Initials] {
+		if !achievement.UnlockedAt.Before(entry.Timestamp) {
+			unlocked = append(unlocked, achievement)
+		}
+	}
+	return unlocked
+}
+
+// playerAchievements returns the achievements players have unlocked, by initials,
+// evaluating the history of any player not yet evaluated
+func (s *Service) playerAchievements(ctx context.Context, gameID string, players map[string][]models.ScoreEntry) map[string][]models.Achievement {
+	definitions, unlocks, _, err := s.recordAchievements(ctx, gameID, players, true, time.Now())
+	if err != nil {
+		s.log(ctx).Warn("failed to get achievements", "game_id", gameID, "error", err)
+		return map[string][]models.Achievement{}
+	}
+
+	achievements := make(map[string][]models.Achievement, len(unlocks.Players))
+	for initials, unlocked := range unlocks.Players {
+		achievements[initials] = achievementsOf(definitions, unlocked)
+	}
+	return achievements
+}
+
+// forgetAchievements removes a player's unlocks, for players deleted from a game, and
+// returns them
+func (s *Service) forgetAchievements(ctx context.Context, gameID, initials string) (map[string]time.Time, error) {
+	s.achievementMu.Lock()
+	defer s.achievementMu.Unlock()
+
+	unlocks, err := s.getAchievementUnlocks(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	forgotten, ok := unlocks.Players[initials]
+	if !ok {
+		return nil, nil
+	}
+	delete(unlocks.Players, initials)
+	unlocks.Updated = time.Now()
+	return forgotten, s.saveJSON(ctx, achievementUnlocksKey(gameID), unlocks)
+}
+
+// restoreAchievements gives a player back unlocks forgetAchievements removed, keeping
+// the earlier time of any they have unlocked again since
+func (s *Service) restoreAchievements(ctx context.Context, gameID, initials string, restored map[string]time.Time) error {
+	if len(restored) == 0 {
+		return nil
+	}
+	s.achievementMu.Lock()
+	defer s.achievementMu.Unlock()
+
+	unlocks, err := s.getAchievementUnlocks(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	player := unlocks.Players[initials]
+	if player == nil {
+		player = map[string]time.Time{}
+		unlocks.Players[initials] = player
+	}
+	for id, at := range restored {
+		if current, ok := player[id]; !ok || at.Before(current) {
+			player[id] = at
+		}
+	}
+	unlocks.Updated = time.Now()
+	return s.saveJSON(ctx, achievementUnlocksKey(gameID), unlocks)
+}
+
+// recordAchievements evaluates players' histories against the game's definitions and
+// records what they meet but haven't unlocked. With missingOnly, only players never
+// evaluated are. Rank achievements are judged on the current ranking, unlocking at now.
+// Returns the definitions, with the game's icon theme, every unlock in the game and the
+// achievements added, by initials.
+func (s *Service) recordAchievements(ctx context.Context, gameID string, players map[string][]models.ScoreEntry, missingOnly bool, now time.Time) ([]models.AchievementDefinition, *achievementUnlocks, map[string][]models.Achievement, error) {
+	definitions, _, err := s.achievementDefinitions(ctx, gameID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	definitions = s.themedAchievements(ctx, gameID, definitions)
+
+	s.achievementMu.Lock()
+	defer s.achievementMu.Unlock()
+
+	unlocks, err := s.getAchievementUnlocks(ctx, gameID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var ranking []models.ScoreEntry
+	for _, definition := range definitions {
+		if definition.Type == models.AchievementRank {
+			if ranked, err := s.getRanking(ctx, gameID); err == nil {
+				ranking = ranked.Entries
+			}
+			break
+		}
+	}
+
+	added := map[string][]models.Achievement{}
+	changed := false
+	for initials, playerScores := range players {
+		unlocked, evaluated := unlocks.Players[initials]
+		if evaluated && missingOnly {
+			continue
+		}
+		if !evaluated {
+			unlocked = map[string]time.Time{}
+			unlocks.Players[initials] = unlocked
+			changed = true
+		}
+
+		rank, _, _ := findRank(ranking, initials)
+		met := evaluateAchievements(definitions, playerScores, rank, now)
+		for _, definition := range definitions {
+			at, ok := met[definition.ID]
+			if _, has := unlocked[definition.ID]; !ok || has {
+				continue
+			}
+			unlocked[definition.ID] = at
+			added[initials] = append(added[initials], definition.Unlocked(at))
+			changed = true
+		}
+	}
+
+	if changed {
+		unlocks.Updated = time.Now()
+		if err := s.saveJSON(ctx, achievementUnlocksKey(gameID), unlocks); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to save achievement unlocks: %w", err)
+		}
+	}
+	return definitions, unlocks, added, nil
+}
+
+// evaluateAchievements returns when a player's history first met each definition it
+// meets. rank is the player's current rank, or 0 if unranked, and rank achievements it
+// meets unlock at now.
+func evaluateAchievements(definitions []models.AchievementDefinition, playerScores []models.ScoreEntry, rank int, now time.Time) map[string]time.Time {
+	scores := append([]models.ScoreEntry(nil), playerScores...)
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Timestamp.Before(scores[j].Timestamp) })
+
+	met := map[string]time.Time{}
+	var lastDay time.Time
+	streak := 0
+	for i, entry := range scores {
+		year, month, day := entry.Timestamp.UTC().Date()
+		today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		switch {
+		case i == 0 || today.After(lastDay.AddDate(0, 0, 1)):
+			streak = 1
+		case today.After(lastDay):
+			streak++
+		}
+		lastDay = today
+
+		for _, definition := range definitions {
+			if _, ok := met[definition.ID]; ok {
+				continue
+			}
+			switch definition.Type {
+			case models.AchievementThreshold:
+				if entry.Score >= definition.Threshold {
+					met[definition.ID] = entry.Timestamp
+				}
+			case models.AchievementPlays:
+				if int64(i+1) >= definition.Threshold {
+					met[definition.ID] = entry.Timestamp
+				}
+			case models.AchievementStreak:
+				if int64(streak) >= definition.Threshold {
+					met[definition.ID] = entry.Timestamp
+				}
+			}
+		}
+	}
+
+	if rank > 0 {
+		for _, definition := range definitions {
+			if definition.Type == models.AchievementRank && int64(rank) <= definition.Threshold {
+				met[definition.ID] = now
+			}
+		}
+	}
+	return met
+}
+
+// achievementsOf returns a player's unlocked achievements in definition order
+func achievementsOf(definitions []models.AchievementDefinition, unlocked map[string]time.Time) []models.Achievement {
+	achievements := make([]models.Achievement, 0, len(unlocked))
+	for _, definition := range definitions {
+		if at, ok := unlocked[definition.ID]; ok {
+			achievements = append(achievements, definition.Unlocked(at))
+		}
+	}
+	return achievements
+}
+
+// scoresByPlayer groups history entries by initials
+func scoresByPlayer(scores []models.ScoreEntry) map[string][]models.ScoreEntry {
+	players := map[string][]models.ScoreEntry{}
+	for _, entry := range scores {
+		players[entry.Initials] = append(players[entry.Initials], entry)
+	}
+	return players
+}
+
+// achievementDefinitions returns a game's definitions and whether they're its own
+// rather than a copy of the defaults
+func (s *Service) achievementDefinitions(ctx context.Context, gameID string) ([]models.AchievementDefinition, bool, error) {
+	data, err := s.db.Get(ctx, achievementDefinitionsKey(gameID))
+	if errors.Is(err, redis.Nil) {
+		return append([]models.AchievementDefinition(nil), models.DefaultAchievements...), false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get achievement definitions: %w", err)
+	}
+	var stored achievementDefinitions
+	if err := json.Unmarshal([]byte(data), &stored); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal achievement definitions: %w", err)
+	}
+	if stored.Achievements == nil {
+		stored.Achievements = []models.AchievementDefinition{}
+	}
+	return stored.Achievements, true, nil
+}
+
+// getAchievementUnlocks reads a game's unlock records
+func (s *Service) getAchievementUnlocks(ctx context.Context, gameID string) (*achievementUnlocks, error) {
+	unlocks := &achievementUnlocks{}
+	data, err := s.db.Get(ctx, achievementUnlocksKey(gameID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get achievement unlocks: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), unlocks); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal achievement unlocks: %w", err)
+		}
+	}
+	if unlocks.Players == nil {
+		unlocks.Players = map[string]map[string]time.Time{}
+	}
+	return unlocks, nil
+}
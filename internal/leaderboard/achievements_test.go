@@ -0,0 +1,193 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestAchievements(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("games without definitions use the defaults", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		list, err := service.AchievementDefinitions(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("AchievementDefinitions failed: %v", err)
+		}
+		if !list.Default || len(list.Achievements) != len(models.DefaultAchievements) {
+			t.Errorf("Expected the defaults, got %+v", list)
+		}
+	})
+
+	t.Run("unlocks are stored and outlive the history that earned them", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 5000); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 200); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+
+		// Deleting the scores that earned them leaves the achievements in place
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if _, err := service.DeleteScore(ctx, "pacman", "AAA", history.Scores[0].Timestamp); err != nil {
+			t.Fatalf("DeleteScore failed: %v", err)
+		}
+		stats, err := service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+		if err != nil {
+			t.Fatalf("GetEnhancedPlayerStats failed: %v", err)
+		}
+		if !hasAchievement(stats.Achievements, "score_5k") {
+			t.Errorf("Expected score_5k to stay unlocked, got %+v", stats.Achievements)
+		}
+
+		if _, err := service.DeletePlayer(ctx, "pacman", "AAA"); err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		unlocks, _ := service.getAchievementUnlocks(ctx, "pacman")
+		if _, ok := unlocks.Players["AAA"]; ok {
+			t.Errorf("Expected a deleted player's unlocks to be removed")
+		}
+	})
+
+	t.Run("submissions report the achievements they newly unlock", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 1500})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if !hasAchievement(result.Achievements, "first_score") || !hasAchievement(result.Achievements, "score_1k") {
+			t.Errorf("Expected first_score and score_1k, got %+v", result.Achievements)
+		}
+
+		result, err = service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 1200})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if len(result.Achievements) != 0 {
+			t.Errorf("Expected nothing new on a second score, got %+v", result.Achievements)
+		}
+	})
+
+	t.Run("custom definitions replace the defaults and unlock from history", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for _, score := range []int64{300, 700} {
+			if err := service.SubmitScore(ctx, "pacman", "AAA", score); err != nil {
+				t.Fatalf("SubmitScore failed: %v", err)
+			}
+		}
+
+		if _, err := service.PutAchievement(ctx, "pacman", models.AchievementDefinition{
+			ID: "score_500", Name: "Half a K", Type: models.AchievementThreshold, Threshold: 500,
+		}); err != nil {
+			t.Fatalf("PutAchievement failed: %v", err)
+		}
+		list, _ := service.AchievementDefinitions(ctx, "pacman")
+		if list.Default || len(list.Achievements) != len(models.DefaultAchievements)+1 {
+			t.Errorf("Expected the defaults copied with the new achievement, got %+v", list)
+		}
+
+		stats, err := service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+		if err != nil || !hasAchievement(stats.Achievements, "score_500") {
+			t.Errorf("Expected history to unlock score_500, got %+v, %v", stats, err)
+		}
+
+		if _, err := service.DeleteAchievement(ctx, "pacman", "first_score"); err != nil {
+			t.Fatalf("DeleteAchievement failed: %v", err)
+		}
+		if _, err := service.DeleteAchievement(ctx, "pacman", "first_score"); !errors.Is(err, ErrAchievementNotFound) {
+			t.Errorf("Expected ErrAchievementNotFound, got %v", err)
+		}
+		stats, _ = service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+		if hasAchievement(stats.Achievements, "first_score") {
+			t.Errorf("Expected first_score to be gone, got %+v", stats.Achievements)
+		}
+	})
+
+	t.Run("rank achievements unlock on reaching the board position", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.PutAchievement(ctx, "pacman", models.AchievementDefinition{
+			ID: "champion", Name: "Champion", Type: models.AchievementRank, Threshold: 1,
+		}); err != nil {
+			t.Fatalf("PutAchievement failed: %v", err)
+		}
+
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		service.SubmitScore(ctx, "pacman", "BBB", 500)
+		service.SubmitScore(ctx, "pacman", "BBB", 2000)
+
+		for initials, want := range map[string]bool{"AAA": true, "BBB": true} {
+			stats, err := service.GetEnhancedPlayerStats(ctx, "pacman", initials, false)
+			if err != nil || hasAchievement(stats.Achievements, "champion") != want {
+				t.Errorf("%s: expected champion %v, got %+v, %v", initials, want, stats, err)
+			}
+		}
+	})
+
+	t.Run("validates definitions", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		invalid := []models.AchievementDefinition{
+			{ID: "Upper", Name: "Upper", Type: models.AchievementThreshold},
+			{ID: "no_name", Type: models.AchievementThreshold},
+			{ID: "bad_type", Name: "Bad", Type: "combo"},
+			{ID: "no_days", Name: "No days", Type: models.AchievementStreak},
+			{ID: "text_icon", Name: "Icon", Icon: "A", Type: models.AchievementPlays, Threshold: 1},
+		}
+		for _, definition := range invalid {
+			if _, err := service.PutAchievement(ctx, "pacman", definition); err == nil {
+				t.Errorf("%s: expected the definition to be refused", definition.ID)
+			}
+		}
+	})
+}
+
+func TestEvaluateAchievements(t *testing.T) {
+	day := time.Date(2025, 7, 1, 20, 0, 0, 0, time.UTC)
+	definitions := []models.AchievementDefinition{
+		{ID: "streak_3", Type: models.AchievementStreak, Threshold: 3},
+		{ID: "plays_4", Type: models.AchievementPlays, Threshold: 4},
+		{ID: "score_100", Type: models.AchievementThreshold, Threshold: 100},
+		{ID: "top_3", Type: models.AchievementRank, Threshold: 3},
+	}
+	// Days 1, 2, a gap, then 4, 5 (twice) and 6: the streak reaches 3 on day 6
+	scores := []models.ScoreEntry{
+		{Score: 10, Timestamp: day},
+		{Score: 20, Timestamp: day.AddDate(0, 0, 1)},
+		{Score: 150, Timestamp: day.AddDate(0, 0, 3)},
+		{Score: 30, Timestamp: day.AddDate(0, 0, 4)},
+		{Score: 40, Timestamp: day.AddDate(0, 0, 4).Add(time.Hour)},
+		{Score: 50, Timestamp: day.AddDate(0, 0, 5)},
+	}
+	now := day.AddDate(0, 0, 10)
+
+	met := evaluateAchievements(definitions, scores, 2, now)
+	want := map[string]time.Time{
+		"streak_3":  scores[5].Timestamp,
+		"plays_4":   scores[3].Timestamp,
+		"score_100": scores[2].Timestamp,
+		"top_3":     now,
+	}
+	for id, at := range want {
+		if !met[id].Equal(at) {
+			t.Errorf("%s: expected unlock at %v, got %v", id, at, met[id])
+		}
+	}
+
+	if met := evaluateAchievements(definitions, scores, 4, now); !met["top_3"].IsZero() {
+		t.Errorf("Expected rank 4 to miss top_3")
+	}
+}
+
+func hasAchievement(achievements []models.Achievement, id string) bool {
+	for _, achievement := range achievements {
+		if achievement.ID == id {
+			return true
+		}
+	}
+	return false
+}
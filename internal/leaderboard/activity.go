@@ -0,0 +1,230 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// activityWeeks is how many weeks of play counts are kept for the weekly trend
+const activityWeeks = 8
+
+// dayLayout names the UTC days and weeks activity is counted by
+const dayLayout = "2006-01-02"
+
+// playerActivityRecord is the running tally of when each player of a game plays,
+// updated on every submission so stats don't rescan history. A player is listed once
+// their history has been tallied.
+type playerActivityRecord struct {
+	Players map[string]*activityTally `json:"players"`
+	Updated time.Time                 `json:"updated"`
+}
+
+// activityTally is one player's running tally
+type activityTally struct {
+	Plays    int            `json:"plays"`
+	LastDay  string         `json:"last_day"` // UTC day of the latest play
+	Streak   int            `json:"streak"`   // Consecutive days played ending on LastDay
+	Longest  int            `json:"longest"`
+	Weekdays [7]int         `json:"weekdays"` // Plays by UTC weekday, Sunday first
+	Hours    [24]int        `json:"hours"`    // Plays by UTC hour
+	Weeks    map[string]int `json:"weeks"`    // Plays by the Monday starting their UTC week
+
+	// Sums for the least-squares line of score against play number
+	SumX  float64 `json:"sum_x"`
+	SumY  float64 `json:"sum_y"`
+	SumXY float64 `json:"sum_xy"`
+	SumXX float64 `json:"sum_xx"`
+}
+
+func playerActivityKey(gameID string) string {
+	return fmt.Sprintf("player_activity:%s", gameID)
+}
+
+// add counts a play, which must not be older than the tally's latest
+func (t *activityTally) add(entry models.ScoreEntry) {
+	at := entry.Timestamp.UTC()
+	day := at.Format(dayLayout)
+	switch {
+	case day == t.LastDay:
+	case t.LastDay != "" && at.AddDate(0, 0, -1).Format(dayLayout) == t.LastDay:
+		t.Streak++
+	default:
+		t.Streak = 1
+	}
+	t.LastDay = day
+	t.Longest = max(t.Longest, t.Streak)
+
+	t.Weekdays[at.Weekday()]++
+	t.Hours[at.Hour()]++
+	if t.Weeks == nil {
+		t.Weeks = map[string]int{}
+	}
+	week := weekStart(at)
+	t.Weeks[week.Format(dayLayout)]++
+	oldest := week.AddDate(0, 0, -7*(activityWeeks-1)).Format(dayLayout)
+	for start := range t.Weeks {
+		if start < oldest {
+			delete(t.Weeks, start)
+		}
+	}
+
+	t.Plays++
+	x, y := float64(t.Plays), float64(entry.Score)
+	t.SumX += x
+	t.SumY += y
+	t.SumXY += x * y
+	t.SumXX += x * x
+}
+
+// activity reports the tally as of now
+func (t *activityTally) activity(now time.Time) *models.PlayerActivity {
+	now = now.UTC()
+	activity := &models.PlayerActivity{
+		LongestStreak:  t.Longest,
+		MostActiveDay:  time.Weekday(busiest(t.Weekdays[:])).String(),
+		MostActiveHour: busiest(t.Hours[:]),
+	}
+	// A streak is still alive until a whole UTC day passes without a play
+	if t.LastDay == now.Format(dayLayout) || t.LastDay == now.AddDate(0, 0, -1).Format(dayLayout) {
+		activity.CurrentStreak = t.Streak
+	}
+
+	week := weekStart(now).AddDate(0, 0, -7*(activityWeeks-1))
+	for i := 0; i < activityWeeks; i++ {
+		activity.WeeklyScores = append(activity.WeeklyScores, models.WeeklyPlays{
+			WeekStart: week,
+			Scores:    t.Weeks[week.Format(dayLayout)],
+		})
+		week = week.AddDate(0, 0, 7)
+	}
+
+	n := float64(t.Plays)
+	if denominator := n*t.SumXX - t.SumX*t.SumX; t.Plays > 1 && denominator != 0 {
+		activity.ImprovementRate = (n*t.SumXY - t.SumX*t.SumY) / denominator
+	}
+	return activity
+}
+
+// weekStart returns midnight UTC on the Monday starting at's week
+func weekStart(at time.Time) time.Time {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+}
+
+// busiest returns the index of the largest count, the first on ties
+func busiest(counts []int) int {
+	best := 0
+	for i, count := range counts {
+		if count > counts[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// tallyActivity adds a stored play to its player's tally. Players not yet tallied are
+// tallied from history, which already holds the play, as are players given a play from
+// before their last, such as a forwarded one, since streaks are counted in order.
+func (s *Service) tallyActivity(ctx context.Context, gameID string, entry models.ScoreEntry, history *models.AllScoresRecord) {
+	s.activityMu.Lock()
+	defer s.activityMu.Unlock()
+
+	record, err := s.getPlayerActivity(ctx, gameID)
+	if err == nil {
+		latest := history.Scores[len(history.Scores)-1].ID == entry.ID
+		if tally, ok := record.Players[entry.Initials]; ok && latest {
+			tally.add(entry)
+		} else {
+			record.Players[entry.Initials] = tallyHistory(history.Scores, entry.Initials)
+		}
+		record.Updated = time.Now()
+		err = s.saveJSON(ctx, playerActivityKey(gameID), record)
+	}
+	if err != nil {
+		s.log(ctx).Warn("failed to record player activity", "game_id", gameID, "initials", entry.Initials, "error", err)
+	}
+}
+
+// playerActivity returns a player's activity, tallying their history first if it
+// hasn't been
+func (s *Service) playerActivity(ctx context.Context, gameID, initials string, playerScores []models.ScoreEntry) *models.PlayerActivity {
+	s.activityMu.Lock()
+	defer s.activityMu.Unlock()
+
+	record, err := s.getPlayerActivity(ctx, gameID)
+	if err != nil {
+		s.log(ctx).Warn("failed to get player activity", "game_id", gameID, "error", err)
+		return tallyHistory(playerScores, initials).activity(time.Now())
+	}
+	tally, ok := record.Players[initials]
+	if !ok {
+		tally = tallyHistory(playerScores, initials)
+		record.Players[initials] = tally
+		record.Updated = time.Now()
+		if err := s.saveJSON(ctx, playerActivityKey(gameID), record); err != nil {
+			s.log(ctx).Warn("failed to save player activity", "game_id", gameID, "error", err)
+		}
+	}
+	return tally.activity(time.Now())
+}
+
+// forgetActivity drops a player's tally, so it is tallied again from what is left of
+// their history when next needed
+func (s *Service) forgetActivity(ctx context.Context, gameID, initials string) error {
+	s.activityMu.Lock()
+	defer s.activityMu.Unlock()
+
+	record, err := s.getPlayerActivity(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	if _, ok := record.Players[initials]; !ok {
+		return nil
+	}
+	delete(record.Players, initials)
+	record.Updated = time.Now()
+	return s.saveJSON(ctx, playerActivityKey(gameID), record)
+}
+
+// tallyHistory tallies a player's plays in scores, oldest first
+func tallyHistory(scores []models.ScoreEntry, initials string) *activityTally {
+	var plays []models.ScoreEntry
+	for _, entry := range scores {
+		if entry.Initials == initials {
+			plays = append(plays, entry)
+		}
+	}
+	sort.SliceStable(plays, func(i, j int) bool { return plays[i].Timestamp.Before(plays[j].Timestamp) })
+
+	tally := &activityTally{}
+	for _, entry := range plays {
+		tally.add(entry)
+	}
+	return tally
+}
+
+// getPlayerActivity reads a game's activity tallies, empty if there are none
+func (s *Service) getPlayerActivity(ctx context.Context, gameID string) (*playerActivityRecord, error) {
+	record := &playerActivityRecord{}
+	data, err := s.db.Get(ctx, playerActivityKey(gameID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get player activity: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal player activity: %w", err)
+		}
+	}
+	if record.Players == nil {
+		record.Players = map[string]*activityTally{}
+	}
+	return record, nil
+}
@@ -0,0 +1,77 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestActivityTally(t *testing.T) {
+	// Wednesday 2 July 2025, 20:00 UTC
+	day := time.Date(2025, 7, 2, 20, 0, 0, 0, time.UTC)
+	plays := []models.ScoreEntry{
+		{Score: 100, Timestamp: day},
+		{Score: 200, Timestamp: day.AddDate(0, 0, 1)},
+		{Score: 300, Timestamp: day.AddDate(0, 0, 1).Add(time.Hour)},
+		{Score: 400, Timestamp: day.AddDate(0, 0, 2)},
+		{Score: 500, Timestamp: day.AddDate(0, 0, 5)},
+	}
+	tally := &activityTally{}
+	for _, entry := range plays {
+		tally.add(entry)
+	}
+
+	activity := tally.activity(day.AddDate(0, 0, 6))
+	if activity.LongestStreak != 3 || activity.CurrentStreak != 1 {
+		t.Errorf("Expected a longest streak of 3 and a current one of 1, got %+v", activity)
+	}
+	if activity.MostActiveDay != "Thursday" || activity.MostActiveHour != 20 {
+		t.Errorf("Expected Thursday at 20h, got %s at %dh", activity.MostActiveDay, activity.MostActiveHour)
+	}
+	if activity.ImprovementRate != 100 {
+		t.Errorf("Expected 100 points per play, got %v", activity.ImprovementRate)
+	}
+
+	weeks := activity.WeeklyScores
+	if len(weeks) != activityWeeks || weeks[len(weeks)-1].Scores != 1 || weeks[len(weeks)-2].Scores != 4 {
+		t.Errorf("Expected 4 plays last week and 1 this week, got %+v", weeks)
+	}
+	if !weeks[len(weeks)-1].WeekStart.Equal(time.Date(2025, 7, 7, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected weeks to start on Monday, got %v", weeks[len(weeks)-1].WeekStart)
+	}
+
+	if gone := tally.activity(day.AddDate(0, 0, 8)); gone.CurrentStreak != 0 {
+		t.Errorf("Expected the streak to lapse after a day without plays, got %d", gone.CurrentStreak)
+	}
+}
+
+func TestPlayerActivity(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake())
+	for _, score := range []int64{100, 300} {
+		if err := service.SubmitScore(ctx, "pacman", "AAA", score); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+	}
+
+	record, _ := service.getPlayerActivity(ctx, "pacman")
+	if tally := record.Players["AAA"]; tally == nil || tally.Plays != 2 {
+		t.Fatalf("Expected submissions to be tallied, got %+v", tally)
+	}
+	stats, err := service.GetPlayerStats(ctx, "pacman", "AAA")
+	if err != nil || stats.Activity == nil || stats.Activity.CurrentStreak != 1 || stats.Activity.ImprovementRate != 200 {
+		t.Fatalf("Expected activity in the stats, got %+v, %v", stats, err)
+	}
+
+	history, _ := service.GetAllScoresForGame(ctx, "pacman")
+	if _, err := service.DeleteScore(ctx, "pacman", "AAA", history.Scores[1].Timestamp); err != nil {
+		t.Fatalf("DeleteScore failed: %v", err)
+	}
+	enhanced, err := service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+	if err != nil || enhanced.Activity == nil || enhanced.Activity.ImprovementRate != 0 {
+		t.Errorf("Expected activity tallied again without the deleted score, got %+v, %v", enhanced, err)
+	}
+}
@@ -0,0 +1,188 @@
+package leaderboard
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+const (
+	// analyticsFreshTTL is how long a cached analysis is served without refreshing
+	analyticsFreshTTL = 30 * time.Second
+	// analyticsStaleTTL is how long past freshness a cached analysis may still be
+	// served while a background refresh runs
+	analyticsStaleTTL = 5 * time.Minute
+	// analyticsRefreshTimeout bounds background refreshes, which outlive the request
+	analyticsRefreshTimeout = 10 * time.Second
+	// analyticsMaxEntries bounds how many analyses are cached at once
+	analyticsMaxEntries = 1000
+)
+
+// analyticsLoader computes a fresh score analysis
+type analyticsLoader func(ctx context.Context) (*models.ScoreAnalysisResponse, error)
+
+// analyticsKey identifies a cached analysis by tenant, game and top players page
+type analyticsKey struct {
+	tenant          string
+	gameID          string
+	topPlayersLimit int
+	offset          int
+}
+
+// analyticsEntry is a cached score analysis and its refresh state
+type analyticsEntry struct {
+	value      *models.ScoreAnalysisResponse
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// analyticsCache caches GetScoreAnalysis results per game with stale-while-revalidate
+// semantics: fresh entries are served directly, stale entries are served while a
+// single background refresh runs, and expired entries are recomputed inline.
+// Cached responses are shared between callers and must be treated as read-only.
+// Once maxEntries are cached, expired entries are dropped and then the oldest.
+type analyticsCache struct {
+	mu         sync.Mutex
+	entries    map[analyticsKey]*analyticsEntry
+	freshTTL   time.Duration
+	staleTTL   time.Duration
+	maxEntries int
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// newAnalyticsCache creates an analytics cache with the given freshness windows
+func newAnalyticsCache(freshTTL, staleTTL time.Duration) *analyticsCache {
+	return &analyticsCache{
+		entries:    make(map[analyticsKey]*analyticsEntry),
+		freshTTL:   freshTTL,
+		staleTTL:   staleTTL,
+		maxEntries: analyticsMaxEntries,
+		now:        time.Now,
+		logger:     slog.Default(),
+	}
+}
+
+// get returns the cached analysis for key, loading or refreshing it as needed
+func (c *analyticsCache) get(ctx context.Context, key analyticsKey, load analyticsLoader) (*models.ScoreAnalysisResponse, error) {
+	c.mu.Lock()
+	entry, exists := c.entries[key]
+	if exists {
+		age := c.now().Sub(entry.fetchedAt)
+		if age < c.freshTTL {
+			value := entry.value
+			c.mu.Unlock()
+			return value, nil
+		}
+
+		if age < c.freshTTL+c.staleTTL {
+			value := entry.value
+			if !entry.refreshing {
+				entry.refreshing = true
+				go c.refresh(key, load)
+			}
+			c.mu.Unlock()
+			return value, nil
+		}
+	}
+	c.mu.Unlock()
+
+	// Nothing usable in the cache - compute inline
+	value, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(key, value)
+	return value, nil
+}
+
+// refresh recomputes an entry in the background, keeping the stale value on failure
+func (c *analyticsCache) refresh(key analyticsKey, load analyticsLoader) {
+	ctx, cancel := context.WithTimeout(tenants.WithTenant(context.Background(), key.tenant), analyticsRefreshTimeout)
+	defer cancel()
+
+	value, err := load(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.entries[key]
+	if err != nil {
+		if exists {
+			entry.refreshing = false
+		}
+		c.logger.Warn("analytics refresh failed", "tenant", key.tenant, "game_id", key.gameID, "error", err)
+		return
+	}
+	if !exists {
+		// Evicted while refreshing
+		return
+	}
+
+	c.entries[key] = &analyticsEntry{value: value, fetchedAt: c.now()}
+}
+
+// store saves a freshly computed analysis
+func (c *analyticsCache) store(key analyticsKey, value *models.ScoreAnalysisResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists {
+		c.makeRoom()
+	}
+	c.entries[key] = &analyticsEntry{value: value, fetchedAt: c.now()}
+}
+
+// makeRoom evicts entries until another fits under maxEntries, dropping expired
+// entries first and then the oldest. The caller must hold c.mu.
+func (c *analyticsCache) makeRoom() {
+	if c.maxEntries <= 0 || len(c.entries) < c.maxEntries {
+		return
+	}
+
+	expiredAt := c.now().Add(-(c.freshTTL + c.staleTTL))
+	for key, entry := range c.entries {
+		if !entry.fetchedAt.After(expiredAt) {
+			delete(c.entries, key)
+		}
+	}
+
+	for len(c.entries) >= c.maxEntries {
+		var oldest analyticsKey
+		var oldestAt time.Time
+		first := true
+		for key, entry := range c.entries {
+			if first || entry.fetchedAt.Before(oldestAt) {
+				oldest, oldestAt, first = key, entry.fetchedAt, false
+			}
+		}
+		delete(c.entries, oldest)
+	}
+}
+
+// clear drops every cached analysis
+func (c *analyticsCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[analyticsKey]*analyticsEntry)
+}
+
+// invalidateGame marks every cached analysis for the tenant's game as stale so the next
+// read triggers a background refresh while still serving the previous result
+func (c *analyticsCache) invalidateGame(tenant, gameID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	staleAt := c.now().Add(-c.freshTTL)
+	for key, entry := range c.entries {
+		if key.tenant == tenant && key.gameID == gameID && entry.fetchedAt.After(staleAt) {
+			entry.fetchedAt = staleAt
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestAnalyticsCache(t *testing.T) {
+	ctx := context.Background()
+	key := analyticsKey{gameID: "pacman", topPlayersLimit: 5}
+
+	// newClockedCache returns a cache whose clock can be advanced by the test
+	newClockedCache := func() (*analyticsCache, func(time.Duration)) {
+		var mu sync.Mutex
+		current := time.Now()
+		cache := newAnalyticsCache(30*time.Second, time.Minute)
+		cache.now = func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return current
+		}
+		advance := func(d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			current = current.Add(d)
+		}
+		return cache, advance
+	}
+
+	// countingLoader returns a loader that reports how many times it ran
+	countingLoader := func(calls *int32) analyticsLoader {
+		return func(ctx context.Context) (*models.ScoreAnalysisResponse, error) {
+			n := atomic.AddInt32(calls, 1)
+			return &models.ScoreAnalysisResponse{GameID: "pacman", TotalScores: int(n)}, nil
+		}
+	}
+
+	t.Run("serves fresh entries without reloading", func(t *testing.T) {
+		cache, advance := newClockedCache()
+		var calls int32
+		load := countingLoader(&calls)
+
+		first, err := cache.get(ctx, key, load)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		advance(10 * time.Second)
+		second, err := cache.get(ctx, key, load)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if first != second {
+			t.Error("Expected the cached analysis to be reused while fresh")
+		}
+		if atomic.LoadInt32(&calls) != 1 {
+			t.Errorf("Expected 1 load, got %d", calls)
+		}
+	})
+
+	t.Run("serves stale entries while refreshing in the background", func(t *testing.T) {
+		cache, advance := newClockedCache()
+		var calls int32
+		release := make(chan struct{})
+		refreshed := make(chan struct{})
+		load := func(ctx context.Context) (*models.ScoreAnalysisResponse, error) {
+			n := atomic.AddInt32(&calls, 1)
+			if n > 1 {
+				<-release
+				defer close(refreshed)
+			}
+			return &models.ScoreAnalysisResponse{TotalScores: int(n)}, nil
+		}
+
+		if _, err := cache.get(ctx, key, load); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		advance(45 * time.Second)
+
+		// Both reads should return the stale value immediately and share one refresh
+		for i := 0; i < 2; i++ {
+			stale, err := cache.get(ctx, key, load)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if stale.TotalScores != 1 {
+				t.Errorf("Expected stale analysis to be served, got version %d", stale.TotalScores)
+			}
+		}
+
+		close(release)
+		select {
+		case <-refreshed:
+		case <-time.After(time.Second):
+			t.Fatal("Background refresh did not run")
+		}
+
+		// Wait for the refreshed value to be stored
+		deadline := time.Now().Add(time.Second)
+		for {
+			current, _ := cache.get(ctx, key, load)
+			if current.TotalScores == 2 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected refreshed analysis, got version %d", current.TotalScores)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		if atomic.LoadInt32(&calls) != 2 {
+			t.Errorf("Expected exactly 2 loads, got %d", calls)
+		}
+	})
+
+	t.Run("reloads inline once the stale window has passed", func(t *testing.T) {
+		cache, advance := newClockedCache()
+		var calls int32
+		load := countingLoader(&calls)
+
+		if _, err := cache.get(ctx, key, load); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		advance(2 * time.Minute)
+		current, err := cache.get(ctx, key, load)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if current.TotalScores != 2 {
+			t.Errorf("Expected an inline reload after expiry, got version %d", current.TotalScores)
+		}
+	})
+
+	t.Run("does not cache load failures", func(t *testing.T) {
+		cache, _ := newClockedCache()
+		failing := func(ctx context.Context) (*models.ScoreAnalysisResponse, error) {
+			return nil, errors.New("no scores found for game")
+		}
+
+		if _, err := cache.get(ctx, key, failing); err == nil {
+			t.Fatal("Expected load error to be returned")
+		}
+
+		var calls int32
+		if _, err := cache.get(ctx, key, countingLoader(&calls)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if atomic.LoadInt32(&calls) != 1 {
+			t.Error("Expected a failed load not to be cached")
+		}
+	})
+
+	t.Run("invalidation marks a game's entries stale", func(t *testing.T) {
+		cache, _ := newClockedCache()
+		var calls int32
+		load := countingLoader(&calls)
+		other := analyticsKey{gameID: "tetris", topPlayersLimit: 5}
+
+		cache.get(ctx, key, load)
+		cache.get(ctx, other, load)
+
+		cache.invalidateGame("", "pacman")
+
+		if !cache.entries[key].fetchedAt.Before(cache.now().Add(-29 * time.Second)) {
+			t.Error("Expected invalidated entry to be stale")
+		}
+		if cache.entries[other].fetchedAt.Before(cache.now()) {
+			t.Error("Expected other games to be unaffected by invalidation")
+		}
+	})
+
+	t.Run("evicts the oldest entries past the cap", func(t *testing.T) {
+		cache, advance := newClockedCache()
+		cache.maxEntries = 3
+		var calls int32
+		load := countingLoader(&calls)
+
+		for offset := 0; offset < 10; offset++ {
+			cache.get(ctx, analyticsKey{gameID: "pacman", topPlayersLimit: 5, offset: offset}, load)
+			advance(time.Second)
+			if len(cache.entries) > 3 {
+				t.Fatalf("Expected at most 3 cached analyses, got %d", len(cache.entries))
+			}
+		}
+		if _, ok := cache.entries[analyticsKey{gameID: "pacman", topPlayersLimit: 5, offset: 9}]; !ok {
+			t.Error("Expected the newest analysis kept")
+		}
+		if _, ok := cache.entries[analyticsKey{gameID: "pacman", topPlayersLimit: 5, offset: 0}]; ok {
+			t.Error("Expected the oldest analysis evicted")
+		}
+	})
+}
+
+func TestScoreAnalysisOffsetIsBounded(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake())
+	service.SubmitScore(ctx, "pacman", "AAA", 1000)
+
+	for _, offset := range []int{MaxAnalysisOffset, MaxAnalysisOffset + 1, MaxAnalysisOffset * 10} {
+		if _, err := service.GetScoreAnalysisPage(ctx, "pacman", 5, offset); err != nil {
+			t.Fatalf("GetScoreAnalysisPage failed: %v", err)
+		}
+	}
+	if len(service.analytics.entries) != 1 {
+		t.Errorf("Expected offsets past the maximum to share one cached analysis, got %d", len(service.analytics.entries))
+	}
+}
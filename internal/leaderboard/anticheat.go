@@ -0,0 +1,239 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/models"
+)
+
+// checkAntiCheat returns the game's anti-cheat rules that a submission of score at now
+// breaks, along with the rules, which are nil when the game has none
+func (s *Service) checkAntiCheat(ctx context.Context, gameID, initials string, score int64, now time.Time) (*models.AntiCheatRules, []models.ScoreViolation) {
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil || !game.Settings.AntiCheat.Enabled() {
+		return nil, nil
+	}
+	rules := game.Settings.AntiCheat
+
+	var highScore *int64
+	if rules.MaxDelta > 0 {
+		var deviceID string
+		if p := apikeys.PrincipalFromContext(ctx); p != nil {
+			deviceID = p.Device
+		}
+		if highScores, err := s.getPlayerHighScores(ctx, gameID); err == nil {
+			if previous, ok := highScores.HighScores[game.Settings.PlayerKey(initials, deviceID)]; ok {
+				highScore = &previous.Score
+			}
+		}
+	}
+
+	var last time.Time
+	if rules.MinIntervalSeconds > 0 {
+		last, _ = s.lastSubmission(ctx, gameID, initials)
+	}
+
+	return rules, judgeSubmission(rules, score, highScore, last, now)
+}
+
+// judgeSubmission returns the rules a submission of score at now breaks, given the
+// player's high score, nil for their first, and when they last submitted, zero if never
+func judgeSubmission(rules *models.AntiCheatRules, score int64, highScore *int64, last, now time.Time) []models.ScoreViolation {
+	var violations []models.ScoreViolation
+	if rules.MaxScore > 0 && score > rules.MaxScore {
+		violations = append(violations, models.ScoreViolation{
+			Rule:    models.RuleMaxScore,
+			Message: fmt.Sprintf("score %d is above the plausible maximum of %d", score, rules.MaxScore),
+		})
+	}
+
+	if rules.MaxDelta > 0 && highScore != nil && score-*highScore > rules.MaxDelta {
+		violations = append(violations, models.ScoreViolation{
+			Rule:    models.RuleMaxDelta,
+			Message: fmt.Sprintf("score is %d above the player's high score, more than %d", score-*highScore, rules.MaxDelta),
+		})
+	}
+
+	if rules.MinIntervalSeconds > 0 && !last.IsZero() {
+		interval := time.Duration(rules.MinIntervalSeconds) * time.Second
+		if now.Sub(last) < interval {
+			violations = append(violations, models.ScoreViolation{
+				Rule:    models.RuleMinInterval,
+				Message: fmt.Sprintf("submitted %s after the player's previous score, sooner than %s", now.Sub(last).Round(time.Second), interval),
+			})
+		}
+	}
+
+	return violations
+}
+
+// SimulateAntiCheat reports what anti-cheat rules would do with a batch of hypothetical
+// submissions, in order, without storing anything. rules replaces the game's own rules
+// for the simulation; nil simulates the game's. Each submission is judged against the
+// game's history and the batch's earlier submissions that would have been stored, and
+// is first checked like a real one for its initials, blocklist and scoring settings.
+func (s *Service) SimulateAntiCheat(ctx context.Context, gameID string, rules *models.AntiCheatRules, submissions []models.SimulatedSubmission) (*models.AntiCheatSimulation, error) {
+	var settings models.GameSettings
+	if game, err := s.GetGame(ctx, gameID); err == nil {
+		settings = game.Settings
+	}
+	if rules == nil {
+		rules = settings.AntiCheat
+	} else if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	if !rules.Enabled() {
+		rules = nil
+	}
+
+	// What the rules judge against: players' high scores and when initials last submitted
+	highScores := make(map[string]int64)
+	if stored, err := s.getPlayerHighScores(ctx, gameID); err == nil {
+		for key, entry := range stored.HighScores {
+			highScores[key] = entry.Score
+		}
+	}
+	lastSubmitted := make(map[string]time.Time)
+	if allScores, err := s.getAllScores(ctx, gameID); err == nil {
+		for _, entry := range allScores.Scores {
+			if entry.Timestamp.After(lastSubmitted[entry.Initials]) {
+				lastSubmitted[entry.Initials] = entry.Timestamp
+			}
+		}
+	}
+
+	simulation := &models.AntiCheatSimulation{
+		GameID:  gameID,
+		Rules:   rules,
+		Results: make([]models.SimulatedResult, 0, len(submissions)),
+		ByRule:  make(map[string]int),
+	}
+	now := time.Now()
+	for i, submission := range submissions {
+		initials := strings.ToUpper(strings.TrimSpace(submission.Initials))
+		result := models.SimulatedResult{Index: i, Initials: initials}
+		at := submission.Timestamp
+		if at.IsZero() {
+			at = now
+		}
+
+		score, err := s.ParseScore(ctx, gameID, submission.Score.String())
+		if err == nil {
+			result.Score = score
+			err = s.checkSimulated(ctx, initials, score, settings.Scoring)
+		}
+		if err != nil {
+			result.Outcome, result.Error = models.SimulationInvalid, err.Error()
+			simulation.Invalid++
+			simulation.Results = append(simulation.Results, result)
+			continue
+		}
+
+		playerKey := settings.PlayerKey(initials, submission.DeviceID)
+		if rules != nil {
+			var highScore *int64
+			if previous, ok := highScores[playerKey]; ok {
+				highScore = &previous
+			}
+			result.Violations = judgeSubmission(rules, score, highScore, lastSubmitted[initials], at)
+		}
+
+		switch {
+		case len(result.Violations) == 0:
+			result.Outcome = models.SimulationAccepted
+			simulation.Accepted++
+		case rules.Flags():
+			result.Outcome = models.SimulationFlagged
+			simulation.Flagged++
+		default:
+			result.Outcome = models.SimulationRejected
+			simulation.Rejected++
+		}
+		for _, violation := range result.Violations {
+			simulation.ByRule[violation.Rule]++
+		}
+
+		// Stored submissions shape how later ones are judged
+		if result.Outcome != models.SimulationRejected {
+			if at.After(lastSubmitted[initials]) {
+				lastSubmitted[initials] = at
+			}
+			if previous, ok := highScores[playerKey]; !ok || score > previous {
+				highScores[playerKey] = score
+			}
+		}
+		simulation.Results = append(simulation.Results, result)
+	}
+	return simulation, nil
+}
+
+// checkSimulated checks a simulated submission the way Submit does before its
+// anti-cheat rules
+func (s *Service) checkSimulated(ctx context.Context, initials string, score int64, scoring *models.ScoringSettings) error {
+	if len(initials) != 3 || strings.Contains(initials, " ") {
+		return fmt.Errorf("initials must be exactly 3 characters with no spaces")
+	}
+	if s.IsBlocked(ctx, initials) {
+		return fmt.Errorf("%w: %s", models.ErrBlockedInitials, initials)
+	}
+	return scoring.Check(score)
+}
+
+// lastSubmission returns when initials last submitted a score to the game, counted or not
+func (s *Service) lastSubmission(ctx context.Context, gameID, initials string) (time.Time, bool) {
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var last time.Time
+	for _, entry := range allScores.Scores {
+		if entry.Initials == initials && entry.Timestamp.After(last) {
+			last = entry.Timestamp
+		}
+	}
+	return last, !last.IsZero()
+}
+
+// SetAntiCheatRules replaces a game's anti-cheat rules, or removes them when rules has
+// none enabled. Existing scores are not re-checked.
+func (s *Service) SetAntiCheatRules(ctx context.Context, gameID string, rules models.AntiCheatRules) (*models.GameInfo, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.AntiCheat = nil
+		if rules.Enabled() {
+			settings.AntiCheat = &rules
+		}
+		return nil
+	})
+}
+
+// FlaggedSubmissions returns the game's submissions that were accepted but flagged by
+// its anti-cheat rules, newest first, for as long as retention keeps them in history
+func (s *Service) FlaggedSubmissions(ctx context.Context, gameID string) *models.FlaggedSubmissionsResponse {
+	response := &models.FlaggedSubmissionsResponse{GameID: gameID, Submissions: []models.ScoreEntry{}}
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return response // No history yet
+	}
+
+	for _, entry := range allScores.Scores {
+		if len(entry.Flags) > 0 {
+			response.Submissions = append(response.Submissions, entry)
+		}
+	}
+	sort.SliceStable(response.Submissions, func(i, j int) bool {
+		return response.Submissions[i].Timestamp.After(response.Submissions[j].Timestamp)
+	})
+	response.Count = len(response.Submissions)
+	return response
+}
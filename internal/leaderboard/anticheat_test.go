@@ -0,0 +1,181 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestAntiCheat(t *testing.T) {
+	ctx := context.Background()
+
+	// newService returns a service whose pacman game has rules, with AAA's high score at 1000
+	newService := func(t *testing.T, rules models.AntiCheatRules) *Service {
+		service := NewService(database.NewFake())
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 1000); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		if _, err := service.SetAntiCheatRules(ctx, "pacman", rules); err != nil {
+			t.Fatalf("SetAntiCheatRules failed: %v", err)
+		}
+		return service
+	}
+	// violatedRules returns the rules a rejected submission broke
+	violatedRules := func(t *testing.T, err error) []string {
+		var suspicious *models.SuspiciousScoreError
+		if !errors.As(err, &suspicious) || !errors.Is(err, models.ErrSuspiciousScore) {
+			t.Fatalf("Expected a suspicious score error, got %v", err)
+		}
+		rules := make([]string, len(suspicious.Violations))
+		for i, v := range suspicious.Violations {
+			rules[i] = v.Rule
+		}
+		return rules
+	}
+
+	t.Run("rejects implausible scores and jumps", func(t *testing.T) {
+		service := newService(t, models.AntiCheatRules{MaxScore: 50000, MaxDelta: 10000})
+
+		if rules := violatedRules(t, service.SubmitScore(ctx, "pacman", "AAA", 60000)); len(rules) != 2 ||
+			rules[0] != models.RuleMaxScore || rules[1] != models.RuleMaxDelta {
+			t.Errorf("Expected max_score and max_delta violations, got %v", rules)
+		}
+		if rules := violatedRules(t, service.SubmitScore(ctx, "pacman", "AAA", 20000)); len(rules) != 1 || rules[0] != models.RuleMaxDelta {
+			t.Errorf("Expected a max_delta violation, got %v", rules)
+		}
+
+		// A player's first score has no high score to jump from
+		if err := service.SubmitScore(ctx, "pacman", "BBB", 40000); err != nil {
+			t.Errorf("Expected a new player's plausible score to be accepted, got %v", err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 11000); err != nil {
+			t.Errorf("Expected a jump within max_delta to be accepted, got %v", err)
+		}
+
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if len(history.Scores) != 3 {
+			t.Errorf("Expected rejected scores to stay out of history, got %+v", history.Scores)
+		}
+	})
+
+	t.Run("rejects submissions sooner than the minimum interval", func(t *testing.T) {
+		service := newService(t, models.AntiCheatRules{MinIntervalSeconds: 60})
+
+		if rules := violatedRules(t, service.SubmitScore(ctx, "pacman", "AAA", 900)); len(rules) != 1 || rules[0] != models.RuleMinInterval {
+			t.Errorf("Expected a min_interval violation, got %v", rules)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "BBB", 900); err != nil {
+			t.Errorf("Expected the interval to apply per initials, got %v", err)
+		}
+	})
+
+	t.Run("accepts and lists flagged scores", func(t *testing.T) {
+		service := newService(t, models.AntiCheatRules{MaxDelta: 100, Action: models.AntiCheatFlag})
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 5000})
+		if err != nil {
+			t.Fatalf("Expected the flagged score to be accepted, got %v", err)
+		}
+		if len(result.Entry.Flags) != 1 || result.Entry.Flags[0].Rule != models.RuleMaxDelta {
+			t.Errorf("Expected the entry to carry its violation, got %+v", result.Entry.Flags)
+		}
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); len(board.Entries) != 1 || board.Entries[0].Score != 5000 {
+			t.Errorf("Expected the flagged score to count, got %+v", board.Entries)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 5050); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+
+		flagged := service.FlaggedSubmissions(ctx, "pacman")
+		if flagged.Count != 1 || flagged.Submissions[0].Score != 5000 {
+			t.Errorf("Expected only the flagged score to be listed, got %+v", flagged)
+		}
+		if flagged := service.FlaggedSubmissions(ctx, "galaga"); flagged.Count != 0 || flagged.Submissions == nil {
+			t.Errorf("Expected an empty list for a game without history, got %+v", flagged)
+		}
+	})
+
+	t.Run("validates and clears rules", func(t *testing.T) {
+		service := newService(t, models.AntiCheatRules{MaxScore: 500})
+
+		if _, err := service.SetAntiCheatRules(ctx, "pacman", models.AntiCheatRules{MaxScore: 500, Action: "ban"}); err == nil {
+			t.Error("Expected an unknown action to be rejected")
+		}
+		if _, err := service.SetAntiCheatRules(ctx, "pacman", models.AntiCheatRules{MaxDelta: -1}); err == nil {
+			t.Error("Expected a negative limit to be rejected")
+		}
+
+		game, err := service.SetAntiCheatRules(ctx, "pacman", models.AntiCheatRules{})
+		if err != nil || game.Settings.AntiCheat != nil {
+			t.Fatalf("Expected empty rules to clear the game's rules, got %+v (%v)", game, err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "CCC", 999999); err != nil {
+			t.Errorf("Expected any score once rules are cleared, got %v", err)
+		}
+	})
+}
+
+func TestSimulateAntiCheat(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake())
+	if err := service.SubmitScore(ctx, "pacman", "AAA", 1000); err != nil {
+		t.Fatalf("SubmitScore failed: %v", err)
+	}
+	if _, err := service.SetAntiCheatRules(ctx, "pacman", models.AntiCheatRules{MaxScore: 50000}); err != nil {
+		t.Fatalf("SetAntiCheatRules failed: %v", err)
+	}
+
+	later := time.Now().Add(time.Hour)
+	batch := []models.SimulatedSubmission{
+		{Initials: "AAA", Score: "60000"},                   // Above max_score
+		{Initials: "bbb", Score: "5000", Timestamp: later},  // A first score
+		{Initials: "BBB", Score: "20000", Timestamp: later}, // Jumps from the batch's own 5000, too soon after it
+		{Initials: "CC", Score: "100"},                      // Malformed initials
+		{Initials: "DDD", Score: "12.5"},                    // Not a whole score
+	}
+
+	t.Run("judges the game's rules", func(t *testing.T) {
+		simulation, err := service.SimulateAntiCheat(ctx, "pacman", nil, batch)
+		if err != nil {
+			t.Fatalf("SimulateAntiCheat failed: %v", err)
+		}
+		want := []string{models.SimulationRejected, models.SimulationAccepted, models.SimulationAccepted, models.SimulationInvalid, models.SimulationInvalid}
+		for i, result := range simulation.Results {
+			if result.Outcome != want[i] {
+				t.Errorf("Submission %d: expected %s, got %+v", i, want[i], result)
+			}
+		}
+		if simulation.Rejected != 1 || simulation.Accepted != 2 || simulation.Invalid != 2 || simulation.ByRule[models.RuleMaxScore] != 1 {
+			t.Errorf("Unexpected totals: %+v", simulation)
+		}
+	})
+
+	t.Run("tries other rules against the batch's earlier submissions", func(t *testing.T) {
+		rules := &models.AntiCheatRules{MaxDelta: 10000, MinIntervalSeconds: 60, Action: models.AntiCheatFlag}
+		simulation, err := service.SimulateAntiCheat(ctx, "pacman", rules, batch[:3])
+		if err != nil {
+			t.Fatalf("SimulateAntiCheat failed: %v", err)
+		}
+		if result := simulation.Results[0]; result.Outcome != models.SimulationFlagged || result.Violations[0].Rule != models.RuleMaxDelta {
+			t.Errorf("Expected AAA's jump flagged, got %+v", result)
+		}
+		if result := simulation.Results[2]; result.Outcome != models.SimulationFlagged || len(result.Violations) != 2 {
+			t.Errorf("Expected BBB's second score flagged for its jump and interval, got %+v", result)
+		}
+	})
+
+	t.Run("stores nothing", func(t *testing.T) {
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if len(history.Scores) != 1 {
+			t.Errorf("Expected only the real score in history, got %+v", history.Scores)
+		}
+	})
+
+	if _, err := service.SimulateAntiCheat(ctx, "pacman", &models.AntiCheatRules{MaxScore: -1}, batch); err == nil {
+		t.Error("Expected invalid rules to be refused")
+	}
+}
@@ -0,0 +1,132 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// blocklistKey is the database key holding operator-managed blocked initials
+const blocklistKey = "blocked_initials"
+
+// ErrNotBlocked is returned when unblocking initials that aren't on the managed blocklist
+var ErrNotBlocked = errors.New("initials are not on the managed blocklist")
+
+// WithBlockedInitials blocks the deployment's configured initials, such as
+// RAWBOARD_BLOCKED_INITIALS, alongside the built-in and operator-managed blocklists
+func WithBlockedInitials(initials []string) Option {
+	return func(s *Service) {
+		s.blocked = make(map[string]bool, len(initials))
+		for _, value := range initials {
+			if value = models.NormalizeInitials(value); value != "" {
+				s.blocked[value] = true
+			}
+		}
+	}
+}
+
+// IsBlocked reports whether initials are blocked by the built-in, configured or
+// operator-managed blocklist
+func (s *Service) IsBlocked(ctx context.Context, initials string) bool {
+	initials = models.NormalizeInitials(initials)
+	if models.IsStaticallyBlocked(initials) || s.blocked[initials] {
+		return true
+	}
+
+	record, err := s.getBlocklist(ctx)
+	if err != nil {
+		return false
+	}
+	for _, blocked := range record.Initials {
+		if blocked == initials {
+			return true
+		}
+	}
+	return false
+}
+
+// Blocklist returns every source of blocked initials
+func (s *Service) Blocklist(ctx context.Context) *models.BlocklistResponse {
+	response := &models.BlocklistResponse{
+		BuiltIn:    append([]string(nil), models.DefaultBlockedInitials...),
+		Configured: make([]string, 0, len(s.blocked)),
+		Managed:    []string{},
+	}
+	for initials := range s.blocked {
+		response.Configured = append(response.Configured, initials)
+	}
+	sort.Strings(response.Configured)
+
+	if record, err := s.getBlocklist(ctx); err == nil {
+		response.Managed = record.Initials
+	}
+	return response
+}
+
+// BlockInitials adds initials to the operator-managed blocklist
+func (s *Service) BlockInitials(ctx context.Context, initials string) error {
+	initials = models.NormalizeInitials(initials)
+
+	record, err := s.getBlocklist(ctx)
+	if err != nil {
+		record = &models.BlockedInitialsRecord{Initials: []string{}}
+	}
+
+	for _, blocked := range record.Initials {
+		if blocked == initials {
+			return nil // Already blocked
+		}
+	}
+
+	record.Initials = append(record.Initials, initials)
+	sort.Strings(record.Initials)
+	record.Updated = time.Now()
+	return s.saveJSON(ctx, blocklistKey, record)
+}
+
+// UnblockInitials removes initials from the operator-managed blocklist
+// Built-in and configured entries can't be removed at runtime
+func (s *Service) UnblockInitials(ctx context.Context, initials string) error {
+	initials = models.NormalizeInitials(initials)
+
+	record, err := s.getBlocklist(ctx)
+	if err != nil {
+		return ErrNotBlocked
+	}
+
+	kept := make([]string, 0, len(record.Initials))
+	for _, blocked := range record.Initials {
+		if blocked != initials {
+			kept = append(kept, blocked)
+		}
+	}
+	if len(kept) == len(record.Initials) {
+		return ErrNotBlocked
+	}
+
+	record.Initials = kept
+	record.Updated = time.Now()
+	return s.saveJSON(ctx, blocklistKey, record)
+}
+
+// getBlocklist retrieves the operator-managed blocklist
+func (s *Service) getBlocklist(ctx context.Context) (*models.BlockedInitialsRecord, error) {
+	data, err := s.db.Get(ctx, blocklistKey)
+	if err != nil {
+		return nil, fmt.Errorf("no managed blocklist")
+	}
+
+	var record models.BlockedInitialsRecord
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal blocklist: %w", err)
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,72 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestStaticBlocklist(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake(), WithBlockedInitials([]string{"zzz"}))
+
+	entry := models.ScoreEntry{Initials: "kkk", Score: 100}
+	if err := entry.Validate(); !errors.Is(err, models.ErrBlockedInitials) {
+		t.Errorf("Expected built-in KKK to be blocked, got %v", err)
+	}
+	for _, initials := range []string{"kkk", "ZZZ"} {
+		if err := service.SubmitScore(ctx, "pacman", initials, 100); !errors.Is(err, models.ErrBlockedInitials) {
+			t.Errorf("Expected %s to be blocked, got %v", initials, err)
+		}
+	}
+	if configured := service.Blocklist(ctx).Configured; len(configured) != 1 || configured[0] != "ZZZ" {
+		t.Errorf("Expected ZZZ listed as configured, got %v", configured)
+	}
+
+	entry = models.ScoreEntry{Initials: "AAA", Score: 100}
+	if err := entry.Validate(); err != nil {
+		t.Errorf("Expected AAA to be allowed, got %v", err)
+	}
+	if err := NewService(database.NewFake()).SubmitScore(ctx, "pacman", "ZZZ", 100); err != nil {
+		t.Errorf("Expected ZZZ allowed by a service without it configured, got %v", err)
+	}
+
+	// Stored leaderboards keep serving entries blocked after the fact
+	board := models.Leaderboard{GameID: "pacman", Entries: []models.ScoreEntry{{Initials: "ZZZ", Score: 100}}}
+	if err := board.Validate(); err != nil {
+		t.Errorf("Expected existing blocked entries to serialize, got %v", err)
+	}
+}
+
+func TestManagedBlocklist(t *testing.T) {
+	ctx := context.Background()
+	db := setupTestDatabase(t)
+	defer db.Close()
+	service := NewService(db)
+
+	initials := "QXZ"
+	gameID := "test_blocklist_" + generateTestID()
+
+	if err := service.BlockInitials(ctx, initials); err != nil {
+		t.Fatalf("Failed to block initials: %v", err)
+	}
+	defer service.UnblockInitials(ctx, initials)
+
+	if err := service.SubmitScore(ctx, gameID, initials, 1000); !errors.Is(err, models.ErrBlockedInitials) {
+		t.Errorf("Expected blocked submission, got %v", err)
+	}
+
+	if err := service.UnblockInitials(ctx, initials); err != nil {
+		t.Fatalf("Failed to unblock initials: %v", err)
+	}
+	if err := service.SubmitScore(ctx, gameID, initials, 1000); err != nil {
+		t.Errorf("Expected submission after unblocking, got %v", err)
+	}
+
+	if err := service.UnblockInitials(ctx, "KKK"); !errors.Is(err, ErrNotBlocked) {
+		t.Errorf("Expected built-in entries not to be removable, got %v", err)
+	}
+}
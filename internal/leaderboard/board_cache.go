@@ -0,0 +1,99 @@
+package leaderboard
+
+import (
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// boardCacheTTL bounds how long a cached board is served without rereading it, for
+// replicas sharing a database without pub/sub, where invalidations only reach the
+// replica that made the change
+const boardCacheTTL = time.Minute
+
+// boardKey identifies a cached board by tenant and game
+type boardKey struct {
+	tenant string
+	gameID string
+}
+
+// cachedBoard is a decoded board and when it was read
+type cachedBoard struct {
+	board     *models.Leaderboard
+	fetchedAt time.Time
+}
+
+// boardCache keeps decoded leaderboards in memory between submissions. Every write
+// bumps the game's generation, so a read that started before a write can't store the
+// board it read after the write has invalidated it.
+type boardCache struct {
+	mu          sync.Mutex
+	entries     map[boardKey]cachedBoard
+	generations map[boardKey]uint64
+	ttl         time.Duration
+	now         func() time.Time
+}
+
+// newBoardCache creates a board cache serving entries for up to ttl
+func newBoardCache(ttl time.Duration) *boardCache {
+	return &boardCache{
+		entries:     make(map[boardKey]cachedBoard),
+		generations: make(map[boardKey]uint64),
+		ttl:         ttl,
+		now:         time.Now,
+	}
+}
+
+// get returns a copy of the cached board for key, or the generation to store a freshly
+// read one under
+func (c *boardCache) get(key boardKey) (*models.Leaderboard, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || c.now().Sub(entry.fetchedAt) >= c.ttl {
+		return nil, c.generations[key], false
+	}
+	return cloneBoard(entry.board), 0, true
+}
+
+// store caches a copy of board, read at generation, unless the game has been written since
+func (c *boardCache) store(key boardKey, board *models.Leaderboard, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generations[key] != generation {
+		return
+	}
+	c.entries[key] = cachedBoard{board: cloneBoard(board), fetchedAt: c.now()}
+}
+
+// invalidate drops the cached board for key
+func (c *boardCache) invalidate(key boardKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+	c.generations[key]++
+}
+
+// clear drops every cached board
+func (c *boardCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[boardKey]cachedBoard)
+	for key := range c.generations {
+		c.generations[key]++
+	}
+}
+
+// cloneBoard copies a board deeply enough that callers may reorder, truncate or append
+// to its entries
+func cloneBoard(board *models.Leaderboard) *models.Leaderboard {
+	clone := *board
+	clone.Entries = make([]models.ScoreEntry, len(board.Entries))
+	copy(clone.Entries, board.Entries)
+	return &clone
+}
@@ -0,0 +1,62 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestBoardCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reads are served from memory until the board is written", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+
+		board, err := service.GetLeaderboard(ctx, "pacman")
+		if err != nil || len(board.Entries) != 1 || board.Updated.IsZero() {
+			t.Fatalf("Expected a board with one entry and its save time, got %+v, %v", board, err)
+		}
+		// Callers own what they're given
+		board.Entries = append(board.Entries[:0], models.ScoreEntry{Initials: "ZZZ"})
+
+		// Another replica's write is only seen here once it's invalidated
+		other := NewService(db)
+		if err := other.SubmitScore(ctx, "pacman", "BBB", 200); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		cached, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(cached.Entries) != 1 || cached.Entries[0].Initials != "AAA" {
+			t.Errorf("Expected the cached board, untouched by the caller, got %+v", cached.Entries)
+		}
+		service.invalidateLocal("", "pacman")
+		fresh, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(fresh.Entries) != 2 || fresh.Version <= cached.Version {
+			t.Errorf("Expected the invalidated board to be reread, got %+v", fresh)
+		}
+
+		// This replica's own writes invalidate at once
+		if err := service.SubmitScore(ctx, "pacman", "CCC", 300); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); len(board.Entries) != 3 {
+			t.Errorf("Expected the new score on the board, got %+v", board.Entries)
+		}
+	})
+
+	t.Run("reads that race a write don't cache what they read", func(t *testing.T) {
+		cache := newBoardCache(boardCacheTTL)
+		key := boardKey{gameID: "pacman"}
+		_, generation, _ := cache.get(key)
+		cache.invalidate(key)
+		cache.store(key, &models.Leaderboard{GameID: "pacman"}, generation)
+		if _, _, ok := cache.get(key); ok {
+			t.Error("Expected a board read before a write not to be cached")
+		}
+	})
+}
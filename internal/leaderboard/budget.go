@@ -0,0 +1,56 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// submissionBudget returns the player's budget for the UTC day containing now as it
+// stands after one more submission, or nil if the game has no daily budget
+func (s *Service) submissionBudget(ctx context.Context, gameID, initials string, now time.Time) *models.SubmissionBudget {
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil || game.Settings.DailySubmissions <= 0 {
+		return nil
+	}
+	limit := game.Settings.DailySubmissions
+
+	now = now.UTC()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	used := 0
+	if allScores, err := s.getAllScores(ctx, gameID); err == nil {
+		for _, entry := range allScores.Scores {
+			if entry.Initials == initials && !entry.NonCounting && !entry.Timestamp.Before(dayStart) {
+				used++
+			}
+		}
+	}
+
+	budget := &models.SubmissionBudget{
+		Limit:    limit,
+		Used:     used,
+		Counted:  used < limit,
+		ResetsAt: dayStart.Add(24 * time.Hour),
+	}
+	if budget.Counted {
+		budget.Used++
+	}
+	budget.Remaining = limit - budget.Used
+	return budget
+}
+
+// SetDailySubmissions limits how many of each player's submissions count per UTC day,
+// or removes the limit when limit is 0. Today's plays count against a new limit at once.
+func (s *Service) SetDailySubmissions(ctx context.Context, gameID string, limit int) (*models.GameInfo, error) {
+	if limit < 0 || limit > models.MaxDailySubmissions {
+		return nil, fmt.Errorf("daily submissions must be between 0 and %d", models.MaxDailySubmissions)
+	}
+
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.DailySubmissions = limit
+		return nil
+	})
+}
@@ -0,0 +1,87 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestSubmissionBudget(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("games without a budget count every play", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if result.Budget != nil {
+			t.Errorf("Expected no budget, got %+v", result.Budget)
+		}
+	})
+
+	t.Run("plays over budget are kept but not counted", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetDailySubmissions(ctx, "pacman", 2); err != nil {
+			t.Fatalf("SetDailySubmissions failed: %v", err)
+		}
+
+		for i, want := range []models.SubmissionBudget{
+			{Limit: 2, Used: 1, Remaining: 1, Counted: true},
+			{Limit: 2, Used: 2, Remaining: 0, Counted: true},
+			{Limit: 2, Used: 2, Remaining: 0, Counted: false},
+		} {
+			result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: int64(1000 * (i + 1))})
+			if err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+			budget := result.Budget
+			if budget == nil {
+				t.Fatal("Expected a budget")
+			}
+			want.ResetsAt = budget.ResetsAt
+			if *budget != want {
+				t.Errorf("Submission %d: expected %+v, got %+v", i+1, want, *budget)
+			}
+			if !budget.ResetsAt.After(time.Now()) || budget.ResetsAt.Sub(time.Now()) > 24*time.Hour {
+				t.Errorf("Expected the budget to reset at the next UTC midnight, got %v", budget.ResetsAt)
+			}
+		}
+
+		leaderboard, err := service.GetLeaderboard(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("GetLeaderboard failed: %v", err)
+		}
+		if len(leaderboard.Entries) != 1 || leaderboard.Entries[0].Score != 2000 {
+			t.Errorf("Expected the over-budget play to leave the board alone, got %+v", leaderboard.Entries)
+		}
+
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if len(history.Scores) != 3 || !history.Scores[2].NonCounting || history.Scores[1].NonCounting {
+			t.Errorf("Expected only the third play to be flagged non-counting, got %+v", history.Scores)
+		}
+
+		// Budgets are per player
+		if result, _ := service.Submit(ctx, "pacman", models.Submission{Initials: "BBB", Score: 500}); result.Budget == nil || !result.Budget.Counted || result.Budget.Remaining != 1 {
+			t.Errorf("Expected BBB to have a fresh budget, got %+v", result.Budget)
+		}
+
+		// And per UTC day
+		tomorrow := service.submissionBudget(ctx, "pacman", "AAA", time.Now().Add(24*time.Hour))
+		if tomorrow == nil || !tomorrow.Counted || tomorrow.Used != 1 {
+			t.Errorf("Expected AAA's budget to reset tomorrow, got %+v", tomorrow)
+		}
+	})
+
+	t.Run("rejects out of range budgets", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for _, limit := range []int{-1, models.MaxDailySubmissions + 1} {
+			if _, err := service.SetDailySubmissions(ctx, "pacman", limit); err == nil {
+				t.Errorf("Expected %d to be rejected", limit)
+			}
+		}
+	})
+}
@@ -0,0 +1,227 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// hourlySnapshotAge is how long every hourly snapshot is kept; older ones are thinned
+// to the first of each UTC day
+const hourlySnapshotAge = 48 * time.Hour
+
+// ErrNoSnapshot is returned when a game has no leaderboard snapshot to compare with yet
+var ErrNoSnapshot = errors.New("no leaderboard snapshot to compare with yet")
+
+// snapshotHistory holds a game's periodic board snapshots, oldest first
+type snapshotHistory struct {
+	GameID    string                `json:"game_id"`
+	Snapshots []leaderboardSnapshot `json:"snapshots"`
+}
+
+// leaderboardSnapshot is the board as it stood at Taken
+type leaderboardSnapshot struct {
+	Taken   time.Time       `json:"taken"`
+	Entries []snapshotEntry `json:"entries"` // In board order
+}
+
+// snapshotEntry is one player's place on a snapshot
+type snapshotEntry struct {
+	Initials string `json:"initials"`
+	DeviceID string `json:"device_id,omitempty"`
+	Score    int64  `json:"score"`
+}
+
+func snapshotsKey(gameID string) string {
+	return fmt.Sprintf("leaderboard_snapshots:%s", gameID)
+}
+
+// SnapshotLeaderboards records every game's board for LeaderboardChanges to compare
+// with, at most once per UTC hour per game however often it runs, and returns how many
+// snapshots were taken
+func (s *Service) SnapshotLeaderboards(ctx context.Context, now time.Time) (int, error) {
+	gameIDs, err := s.ListGames(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	taken := 0
+	var errs []error
+	for _, gameID := range gameIDs {
+		done, err := s.snapshotLeaderboard(ctx, gameID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", gameID, err))
+			continue
+		}
+		if done {
+			taken++
+		}
+	}
+	return taken, errors.Join(errs...)
+}
+
+// snapshotLeaderboard records gameID's board unless it was already recorded this hour.
+// Games without a board have nothing to record.
+func (s *Service) snapshotLeaderboard(ctx context.Context, gameID string, now time.Time) (bool, error) {
+	board, err := s.GetLeaderboard(ctx, gameID)
+	if err != nil {
+		return false, nil
+	}
+
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	history, err := s.getSnapshots(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	now = now.UTC()
+	if n := len(history.Snapshots); n > 0 && !history.Snapshots[n-1].Taken.Before(now.Truncate(time.Hour)) {
+		return false, nil
+	}
+
+	snapshot := leaderboardSnapshot{Taken: now, Entries: make([]snapshotEntry, 0, len(board.Entries))}
+	for _, entry := range board.Entries {
+		snapshot.Entries = append(snapshot.Entries, snapshotEntry{Initials: entry.Initials, DeviceID: entry.DeviceID, Score: entry.Score})
+	}
+	history.Snapshots = pruneSnapshots(append(history.Snapshots, snapshot), now)
+
+	if err := s.saveJSON(ctx, snapshotsKey(gameID), history); err != nil {
+		return false, fmt.Errorf("failed to save leaderboard snapshots: %w", err)
+	}
+	return true, nil
+}
+
+// pruneSnapshots keeps every snapshot from the last hourlySnapshotAge and the first of
+// each UTC day before that, back to a day beyond MaxChangesWindow
+func pruneSnapshots(snapshots []leaderboardSnapshot, now time.Time) []leaderboardSnapshot {
+	kept := snapshots[:0]
+	lastDay := ""
+	for _, snapshot := range snapshots {
+		age := now.Sub(snapshot.Taken)
+		day := snapshot.Taken.UTC().Format(dayBucketLayout)
+		switch {
+		case age > models.MaxChangesWindow+24*time.Hour:
+			continue
+		case age > hourlySnapshotAge && day == lastDay:
+			continue
+		}
+		kept = append(kept, snapshot)
+		lastDay = day
+	}
+	return kept
+}
+
+// LeaderboardChanges compares gameID's board with the latest snapshot taken at least
+// since ago, or the oldest snapshot when none is that old; Since in the result says
+// which. Returns ErrNoSnapshot before the game's first snapshot.
+func (s *Service) LeaderboardChanges(ctx context.Context, gameID string, since time.Duration, now time.Time) (*models.LeaderboardChanges, error) {
+	board, err := s.GetLeaderboard(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.getSnapshots(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if len(history.Snapshots) == 0 {
+		return nil, ErrNoSnapshot
+	}
+
+	baseline := history.Snapshots[0]
+	cutoff := now.Add(-since)
+	for _, snapshot := range history.Snapshots {
+		if snapshot.Taken.After(cutoff) {
+			break
+		}
+		baseline = snapshot
+	}
+
+	settings := s.gameSettings(ctx, gameID)
+	current := make([]snapshotEntry, 0, len(board.Entries))
+	for _, entry := range board.Entries {
+		current = append(current, snapshotEntry{Initials: entry.Initials, DeviceID: entry.DeviceID, Score: entry.Score})
+	}
+	changes := compareBoards(baseline.Entries, current, func(entry snapshotEntry) string {
+		return settings.PlayerKey(entry.Initials, entry.DeviceID)
+	})
+	changes.GameID = gameID
+	changes.Since = baseline.Taken
+
+	if scoring := s.scoring(ctx, gameID); scoring.Precision() > 0 {
+		for _, list := range [][]models.RankChange{changes.Movers, changes.NewEntrants, changes.DroppedOut} {
+			for i := range list {
+				list[i].DisplayScore = scoring.Format(list[i].Score)
+			}
+		}
+	}
+	return changes, nil
+}
+
+// compareBoards lists the players who moved between, joined or left two boards, with
+// players identified by key
+func compareBoards(previous, current []snapshotEntry, key func(snapshotEntry) string) *models.LeaderboardChanges {
+	previousRanks := make(map[string]int, len(previous))
+	for i, entry := range previous {
+		previousRanks[key(entry)] = i + 1
+	}
+
+	changes := &models.LeaderboardChanges{
+		Movers:      []models.RankChange{},
+		NewEntrants: []models.RankChange{},
+		DroppedOut:  []models.RankChange{},
+	}
+	onBoard := make(map[string]bool, len(current))
+	for i, entry := range current {
+		player := key(entry)
+		onBoard[player] = true
+		change := models.RankChange{Initials: entry.Initials, DeviceID: entry.DeviceID, Score: entry.Score, Rank: i + 1}
+
+		previousRank, ok := previousRanks[player]
+		switch {
+		case !ok:
+			changes.NewEntrants = append(changes.NewEntrants, change)
+		case previousRank != change.Rank:
+			change.PreviousRank = previousRank
+			change.Change = previousRank - change.Rank
+			changes.Movers = append(changes.Movers, change)
+		}
+	}
+	for i, entry := range previous {
+		if !onBoard[key(entry)] {
+			changes.DroppedOut = append(changes.DroppedOut, models.RankChange{
+				Initials:     entry.Initials,
+				DeviceID:     entry.DeviceID,
+				Score:        entry.Score,
+				PreviousRank: i + 1,
+			})
+		}
+	}
+
+	sort.SliceStable(changes.Movers, func(i, j int) bool { return changes.Movers[i].Change > changes.Movers[j].Change })
+	return changes
+}
+
+// getSnapshots retrieves a game's board snapshots, empty before the first
+func (s *Service) getSnapshots(ctx context.Context, gameID string) (*snapshotHistory, error) {
+	data, err := s.db.Get(ctx, snapshotsKey(gameID))
+	if errors.Is(err, redis.Nil) {
+		return &snapshotHistory{GameID: gameID, Snapshots: []leaderboardSnapshot{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard snapshots: %w", err)
+	}
+
+	var history snapshotHistory
+	if err := json.Unmarshal([]byte(data), &history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal leaderboard snapshots: %w", err)
+	}
+	return &history, nil
+}
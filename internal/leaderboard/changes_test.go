@@ -0,0 +1,101 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestLeaderboardChanges(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("compares the board with the snapshot from before since", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for initials, score := range map[string]int64{"AAA": 300, "BBB": 200, "CCC": 100} {
+			if err := service.SubmitScore(ctx, "pacman", initials, score); err != nil {
+				t.Fatalf("SubmitScore failed: %v", err)
+			}
+		}
+		if _, err := service.LeaderboardChanges(ctx, "pacman", 7*24*time.Hour, now); !errors.Is(err, ErrNoSnapshot) {
+			t.Errorf("Expected ErrNoSnapshot before the first snapshot, got %v", err)
+		}
+
+		taken, err := service.SnapshotLeaderboards(ctx, now.Add(-8*24*time.Hour))
+		if err != nil || taken != 1 {
+			t.Fatalf("Expected one snapshot, got %d, %v", taken, err)
+		}
+		if taken, _ := service.SnapshotLeaderboards(ctx, now.Add(-8*24*time.Hour)); taken != 0 {
+			t.Errorf("Expected one snapshot per hour, got another")
+		}
+
+		service.SubmitScore(ctx, "pacman", "CCC", 500)
+		service.SubmitScore(ctx, "pacman", "DDD", 250)
+		// A newer snapshot than since asks for isn't compared with
+		service.SnapshotLeaderboards(ctx, now.Add(-time.Hour))
+
+		changes, err := service.LeaderboardChanges(ctx, "pacman", 7*24*time.Hour, now)
+		if err != nil {
+			t.Fatalf("LeaderboardChanges failed: %v", err)
+		}
+		if !changes.Since.Equal(now.Add(-8 * 24 * time.Hour).UTC()) {
+			t.Errorf("Expected the 8-day-old snapshot, got %v", changes.Since)
+		}
+		want := []struct {
+			initials string
+			change   int
+		}{{"CCC", 2}, {"AAA", -1}, {"BBB", -2}}
+		if len(changes.Movers) != len(want) {
+			t.Fatalf("Expected %d movers, got %+v", len(want), changes.Movers)
+		}
+		for i, mover := range want {
+			if changes.Movers[i].Initials != mover.initials || changes.Movers[i].Change != mover.change {
+				t.Errorf("Mover %d: expected %s %+d, got %+v", i, mover.initials, mover.change, changes.Movers[i])
+			}
+		}
+		if len(changes.NewEntrants) != 1 || changes.NewEntrants[0].Initials != "DDD" || changes.NewEntrants[0].Rank != 3 {
+			t.Errorf("Expected DDD to enter at 3, got %+v", changes.NewEntrants)
+		}
+	})
+}
+
+func TestCompareBoards(t *testing.T) {
+	previous := []snapshotEntry{{Initials: "AAA", Score: 300}, {Initials: "BBB", Score: 200}}
+	current := []snapshotEntry{{Initials: "AAA", Score: 300}, {Initials: "CCC", Score: 250}}
+	changes := compareBoards(previous, current, func(entry snapshotEntry) string { return entry.Initials })
+
+	if len(changes.Movers) != 0 {
+		t.Errorf("Expected AAA to hold its place, got %+v", changes.Movers)
+	}
+	if len(changes.DroppedOut) != 1 || changes.DroppedOut[0].Initials != "BBB" || changes.DroppedOut[0].PreviousRank != 2 {
+		t.Errorf("Expected BBB to drop out from 2, got %+v", changes.DroppedOut)
+	}
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	now := time.Date(2025, 7, 16, 12, 0, 0, 0, time.UTC)
+	var snapshots []leaderboardSnapshot
+	for at := now.AddDate(0, 0, -40); !at.After(now); at = at.Add(time.Hour) {
+		snapshots = append(snapshots, leaderboardSnapshot{Taken: at})
+	}
+
+	kept := pruneSnapshots(snapshots, now)
+	hourly, daily := 0, 0
+	for _, snapshot := range kept {
+		if now.Sub(snapshot.Taken) <= hourlySnapshotAge {
+			hourly++
+		} else {
+			daily++
+		}
+		if now.Sub(snapshot.Taken) > models.MaxChangesWindow+24*time.Hour {
+			t.Errorf("Expected snapshots past the window to be dropped, kept %v", snapshot.Taken)
+		}
+	}
+	if hourly != 49 || daily < 29 || daily > 31 {
+		t.Errorf("Expected 49 hourly and about 30 daily snapshots, got %d and %d", hourly, daily)
+	}
+}
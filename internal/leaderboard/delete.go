@@ -0,0 +1,202 @@
+package leaderboard
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// WithGameKeys adds the keys another package keeps for each game, such as its webhooks,
+// to those DeleteGame removes
+func WithGameKeys(keys func(gameID string) []string) Option {
+	return func(s *Service) {
+		s.gameKeys = append(s.gameKeys, keys)
+	}
+}
+
+// PlanGameDeletion previews what deleting a game would remove, with the token
+// DeleteGame must be given to go ahead. Games with neither a registry record nor
+// stored scores fail with ErrGameNotFound.
+func (s *Service) PlanGameDeletion(ctx context.Context, gameID string) (*models.GameDeletion, error) {
+	plan, _, err := s.planGameDeletion(ctx, gameID)
+	return plan, err
+}
+
+// planGameDeletion previews a deletion, returning the game's registry record with it.
+// Games scored before the registry existed get a bare record.
+func (s *Service) planGameDeletion(ctx context.Context, gameID string) (*models.GameDeletion, *models.GameInfo, error) {
+	game, err := s.GetGame(ctx, gameID)
+	if errors.Is(err, ErrGameNotFound) {
+		stored, storedErr := s.hasGameData(ctx, gameID)
+		if storedErr != nil {
+			return nil, nil, storedErr
+		}
+		if !stored {
+			return nil, nil, err
+		}
+		game = &models.GameInfo{GameID: gameID}
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	plan := &models.GameDeletion{GameID: gameID}
+	history, err := s.getAllScores(ctx, gameID)
+	if errors.Is(err, ErrNoScoreHistory) {
+		history = &models.AllScoresRecord{}
+	} else if err != nil {
+		return nil, nil, err
+	}
+	plan.Scores = len(history.Scores)
+	if highScores, err := s.getPlayerHighScores(ctx, gameID); err == nil {
+		plan.Players = len(highScores.HighScores)
+	}
+	index, err := s.getArchives(ctx, gameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	plan.Archives = len(index.Archives)
+
+	// The token changes with every score, so a deletion confirmed against an older
+	// preview doesn't take scores the operator never saw
+	sum := sha256.Sum256(fmt.Appendf(nil, "%s\x00%d\x00%d\x00%d\x00%d",
+		gameID, game.CreatedAt.UnixNano(), plan.Scores, history.Updated.UnixNano(), plan.Archives))
+	plan.ConfirmToken = hex.EncodeToString(sum[:8])
+	return plan, game, nil
+}
+
+// DeleteGame removes a game and everything kept for it: its leaderboard,
+// score history, high scores and what is derived from them, achievements and their
+// badges, seasons, archives, settings and signing secret, and the keys other packages
+// added with WithGameKeys. The game leaves the registry and no longer counts against
+// the API key that created it. confirm must be the token of a current PlanGameDeletion;
+// otherwise nothing is removed and it fails with models.ErrDeletionNotConfirmed.
+// Games with neither a registry record nor stored scores fail with ErrGameNotFound.
+// Reset listeners are told, and a later score registers the game afresh.
+func (s *Service) DeleteGame(ctx context.Context, gameID, confirm string) (*models.GameDeletion, error) {
+	s.archiveMu.Lock()
+	defer s.archiveMu.Unlock()
+
+	plan, game, err := s.planGameDeletion(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if confirm == "" || confirm != plan.ConfirmToken {
+		return nil, fmt.Errorf("%w: %s", models.ErrDeletionNotConfirmed, gameID)
+	}
+	deleter, ok := s.db.(database.SortedSets)
+	if !ok {
+		return nil, fmt.Errorf("database can't delete keys")
+	}
+
+	// Emptying the board first shows live streams the game is gone
+	if err := s.RestoreGame(ctx, &models.AllScoresRecord{GameID: gameID, Scores: []models.ScoreEntry{}, Updated: time.Now()}, nil); err != nil {
+		return nil, fmt.Errorf("failed to clear game: %w", err)
+	}
+
+	keys, err := s.deletedGameKeys(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	// The registry record goes last, so a deletion that fails part way can be retried
+	keys = append(keys, gameKey(gameID))
+	for _, key := range keys {
+		if err := deleter.Del(ctx, key); err != nil {
+			return nil, fmt.Errorf("failed to delete %s: %w", key, err)
+		}
+	}
+
+	if err := s.unlistGame(ctx, gameID); err != nil {
+		s.log(ctx).Warn("failed to remove deleted game from the registry", "game_id", gameID, "error", err)
+	}
+	if game.CreatedBy != "" {
+		if err := s.unclaimGame(ctx, game.CreatedBy, gameID); err != nil {
+			s.log(ctx).Warn("failed to release deleted game from its API key", "game_id", gameID, "key_id", game.CreatedBy, "error", err)
+		}
+	}
+	s.invalidateGame(ctx, gameID)
+	s.notifyReset(ctx, gameID, models.ResetGameDeleted, "")
+
+	plan.Deleted = true
+	plan.Keys = len(keys)
+	plan.ConfirmToken = ""
+	s.log(ctx).Info("game deleted", "game_id", gameID, "scores", plan.Scores, "players", plan.Players, "keys", plan.Keys)
+	return plan, nil
+}
+
+// hasGameData reports whether scores are stored for a game, registered or not
+func (s *Service) hasGameData(ctx context.Context, gameID string) (bool, error) {
+	for _, key := range []string{
+		fmt.Sprintf("all_scores:%s", gameID),
+		fmt.Sprintf("leaderboard:%s", gameID),
+		fmt.Sprintf("player_high_scores:%s", gameID),
+	} {
+		_, err := s.db.Get(ctx, key)
+		if err == nil {
+			return true, nil
+		}
+		if !errors.Is(err, redis.Nil) {
+			return false, fmt.Errorf("failed to get %s: %w", key, err)
+		}
+	}
+	return false, nil
+}
+
+// deletedGameKeys lists the keys kept for a game other than its registry record
+func (s *Service) deletedGameKeys(ctx context.Context, gameID string) ([]string, error) {
+	// Records found through a list go before the list, so a retry still finds them
+	keys := []string{}
+	definitions, _, err := s.achievementDefinitions(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, definition := range definitions {
+		keys = append(keys, achievementBadgeKey(gameID, definition.ID))
+	}
+	seasons, err := s.getSeasons(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, season := range seasons.Seasons {
+		keys = append(keys, seasonLeaderboardKey(gameID, season.SeasonID))
+	}
+	index, err := s.getArchives(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, archive := range index.Archives {
+		keys = append(keys, archiveKey(gameID, archive.ArchiveID))
+	}
+	keys = append(keys,
+		fmt.Sprintf("leaderboard:%s", gameID),
+		fmt.Sprintf("all_scores:%s", gameID),
+		fmt.Sprintf("player_high_scores:%s", gameID),
+		rankingKey(gameID),
+		sequenceKey(gameID),
+		achievementDefinitionsKey(gameID),
+		achievementUnlocksKey(gameID),
+		playerActivityKey(gameID),
+		timeseriesKey(gameID),
+		snapshotsKey(gameID),
+		tombstonesKey(gameID),
+		disputesKey(gameID),
+		scoreIndexKey(gameID),
+		timeIndexKey(gameID),
+		scoreIndexReadyKey(gameID),
+		signingSecretKey(gameID),
+		signatureNoncesKey(gameID),
+		seasonsKey(gameID),
+		archivesKey(gameID),
+	)
+	for _, extra := range s.gameKeys {
+		keys = append(keys, extra(gameID)...)
+	}
+	return keys, nil
+}
@@ -0,0 +1,144 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestDeleteGame(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("removes every key kept for the game", func(t *testing.T) {
+		db := database.NewFake()
+		resets := &resetRecorder{}
+		service := NewService(db, WithResetListener(resets), WithGameKeys(func(gameID string) []string {
+			return []string{"extra:" + gameID}
+		}))
+		cabinet := apikeys.WithPrincipal(ctx, &apikeys.Principal{KeyID: "key-1", GameIDs: []string{models.AllGames}})
+		service.SubmitScore(cabinet, "pacman", "AAA", 1000)
+		service.SubmitScore(cabinet, "galaga", "AAA", 500)
+		service.ResetGame(ctx, "pacman", "")
+		service.StartSeason(ctx, "pacman", "summer", "Summer", nil)
+		service.SubmitScore(ctx, "pacman", "BBB", 2000)
+		service.QueryScores(ctx, "pacman", models.ScoreQuery{Limit: 10})
+		db.Set(ctx, "extra:pacman", "{}")
+
+		plan, err := service.PlanGameDeletion(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("PlanGameDeletion failed: %v", err)
+		}
+		if plan.Scores != 1 || plan.Players != 1 || plan.Archives != 1 || plan.Deleted || plan.ConfirmToken == "" {
+			t.Errorf("Expected a preview of 1 score, 1 player and 1 archive, got %+v", plan)
+		}
+
+		deletion, err := service.DeleteGame(ctx, "pacman", plan.ConfirmToken)
+		if err != nil {
+			t.Fatalf("DeleteGame failed: %v", err)
+		}
+		if !deletion.Deleted || deletion.ConfirmToken != "" || deletion.Keys == 0 {
+			t.Errorf("Expected the deletion done, got %+v", deletion)
+		}
+
+		for _, key := range db.Keys("") {
+			if strings.Contains(key, "pacman") {
+				t.Errorf("Expected %s deleted", key)
+			}
+		}
+		if count, _ := db.ZCard(ctx, scoreIndexKey("pacman")); count != 0 {
+			t.Errorf("Expected the score index deleted, got %d members", count)
+		}
+		if games, _ := service.ListGames(ctx); len(games) != 1 || games[0] != "galaga" {
+			t.Errorf("Expected only galaga registered, got %v", games)
+		}
+		if quota := service.GameQuota(ctx, "key-1", nil); quota.Used != 1 || quota.GameIDs[0] != "galaga" {
+			t.Errorf("Expected pacman released from key-1's quota, got %+v", quota)
+		}
+		if last := resets.events[len(resets.events)-1]; last.Reason != models.ResetGameDeleted {
+			t.Errorf("Expected a game.deleted reset, got %+v", last)
+		}
+		if board, _ := service.GetLeaderboard(ctx, "galaga"); len(board.Entries) != 1 {
+			t.Errorf("Expected galaga untouched, got %+v", board.Entries)
+		}
+	})
+
+	t.Run("needs the current confirm token", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		plan, _ := service.PlanGameDeletion(ctx, "pacman")
+
+		if _, err := service.DeleteGame(ctx, "pacman", ""); !errors.Is(err, models.ErrDeletionNotConfirmed) {
+			t.Errorf("Expected ErrDeletionNotConfirmed without a token, got %v", err)
+		}
+		service.SubmitScore(ctx, "pacman", "BBB", 500)
+		if _, err := service.DeleteGame(ctx, "pacman", plan.ConfirmToken); !errors.Is(err, models.ErrDeletionNotConfirmed) {
+			t.Errorf("Expected a token from before the last score to be refused, got %v", err)
+		}
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); len(board.Entries) != 2 {
+			t.Errorf("Expected nothing deleted, got %+v", board.Entries)
+		}
+		if _, err := service.DeleteGame(ctx, "galaga", "token"); !errors.Is(err, ErrGameNotFound) {
+			t.Errorf("Expected ErrGameNotFound for an unknown game, got %v", err)
+		}
+	})
+
+	t.Run("lets the game start afresh", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		plan, _ := service.PlanGameDeletion(ctx, "pacman")
+		service.DeleteGame(ctx, "pacman", plan.ConfirmToken)
+
+		if _, err := service.GetGame(ctx, "pacman"); err == nil {
+			t.Error("Expected the game unregistered")
+		}
+		service.SubmitScore(ctx, "pacman", "CCC", 10)
+		board, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 1 || board.Entries[0].Initials != "CCC" {
+			t.Errorf("Expected only the new score, got %+v", board.Entries)
+		}
+		scores, _ := service.QueryScores(ctx, "pacman", models.ScoreQuery{Limit: 10})
+		if scores == nil || len(scores.Scores) != 1 {
+			t.Errorf("Expected the rebuilt index to hold only the new score, got %+v", scores)
+		}
+	})
+
+	t.Run("deletes games scored before the registry", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		db.Del(ctx, gameKey("pacman"))
+		service.unlistGame(ctx, "pacman")
+
+		plan, err := service.PlanGameDeletion(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("Expected an unregistered game with scores to be deletable, got %v", err)
+		}
+		if plan.Scores != 1 {
+			t.Errorf("Expected a preview of 1 score, got %+v", plan)
+		}
+		if _, err := service.DeleteGame(ctx, "pacman", plan.ConfirmToken); err != nil {
+			t.Fatalf("DeleteGame failed: %v", err)
+		}
+		for _, key := range db.Keys("") {
+			if strings.Contains(key, "pacman") {
+				t.Errorf("Expected %s deleted", key)
+			}
+		}
+	})
+
+	t.Run("reports database errors apart from unknown games", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+
+		db.FailKey(database.OpGet, "game:pacman", errors.New("connection reset"))
+		if _, err := service.PlanGameDeletion(ctx, "pacman"); err == nil || errors.Is(err, ErrGameNotFound) {
+			t.Errorf("Expected the read error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+
+	"rawboard/internal/models"
+)
+
+// demoScores are the plays SeedDemo submits, in order, by game
+var demoScores = []struct {
+	gameID string
+	plays  []models.Submission
+}{
+	{"pacman", []models.Submission{
+		{Initials: "AAA", Score: 3333360}, {Initials: "BLN", Score: 1250000}, {Initials: "PKY", Score: 980500},
+		{Initials: "AAA", Score: 2100000}, {Initials: "INK", Score: 455300}, {Initials: "CLY", Score: 120900},
+		{Initials: "BLN", Score: 1410700}, {Initials: "SUE", Score: 61500},
+	}},
+	{"galaga", []models.Submission{
+		{Initials: "BEE", Score: 1220980}, {Initials: "BOS", Score: 860400}, {Initials: "AAA", Score: 702150},
+		{Initials: "BEE", Score: 1048760}, {Initials: "MTH", Score: 355020}, {Initials: "ZZZ", Score: 98300},
+		{Initials: "BOS", Score: 912030}, {Initials: "ACE", Score: 50200},
+	}},
+	{"donkey-kong", []models.Submission{
+		{Initials: "KNG", Score: 1272800}, {Initials: "JMP", Score: 1064500}, {Initials: "BRL", Score: 874300},
+		{Initials: "KNG", Score: 1218000}, {Initials: "HAM", Score: 512900}, {Initials: "PLN", Score: 226100},
+		{Initials: "JMP", Score: 1110100}, {Initials: "AAA", Score: 45800},
+	}},
+}
+
+// ClearCaches drops everything this replica remembers of the database, for databases
+// that were wiped underneath it
+func (s *Service) ClearCaches() {
+	s.analytics.clear()
+	s.boards.clear()
+	s.playerIDSalts.Clear()
+}
+
+// SeedDemo fills a few classic games with demo scores for local development
+func (s *Service) SeedDemo(ctx context.Context) (*models.DevReset, error) {
+	seeded := &models.DevReset{Seeded: true}
+	for _, game := range demoScores {
+		for _, play := range game.plays {
+			if _, err := s.Submit(ctx, game.gameID, play); err != nil {
+				return nil, fmt.Errorf("failed to seed %s: %w", game.gameID, err)
+			}
+			seeded.Scores++
+		}
+		seeded.Games = append(seeded.Games, game.gameID)
+	}
+	return seeded, nil
+}
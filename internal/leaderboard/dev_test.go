@@ -0,0 +1,38 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"rawboard/internal/database"
+)
+
+func TestDevReset(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+	service := NewService(db)
+
+	seeded, err := service.SeedDemo(ctx)
+	if err != nil {
+		t.Fatalf("SeedDemo failed: %v", err)
+	}
+	if len(seeded.Games) != len(demoScores) || seeded.Scores == 0 {
+		t.Fatalf("Expected every demo game seeded, got %+v", seeded)
+	}
+	board, err := service.GetLeaderboard(ctx, "pacman")
+	if err != nil || len(board.Entries) == 0 || board.Entries[0].Initials != "AAA" {
+		t.Fatalf("Expected AAA to lead the demo pacman board, got %+v, %v", board, err)
+	}
+
+	salt, _ := service.PlayerIDSalt(ctx)
+	db.Reset()
+	service.ClearCaches()
+
+	if board, err := service.GetLeaderboard(ctx, "pacman"); err == nil && len(board.Entries) > 0 {
+		t.Errorf("Expected an empty board after the reset, got %+v", board.Entries)
+	}
+	if again, _ := service.PlayerIDSalt(ctx); bytes.Equal(salt, again) {
+		t.Error("Expected a new player ID salt after the reset")
+	}
+}
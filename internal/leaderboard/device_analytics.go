@@ -0,0 +1,123 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// dateLayout formats the UTC days of daily breakdowns
+const dateLayout = "2006-01-02"
+
+// DeviceAnalytics compares the devices that submitted gameID's scores over the last
+// days whole UTC days up to now, from the score history. A device's uptime is inferred
+// from the days it submitted anything, so a cabinet nobody played looks the same as one
+// that was down; compare it with its neighbours.
+func (s *Service) DeviceAnalytics(ctx context.Context, gameID string, days int, now time.Time) (*models.DeviceAnalytics, error) {
+	if days < 1 || days > models.MaxDeviceAnalyticsDays {
+		return nil, fmt.Errorf("days must be between 1 and %d", models.MaxDeviceAnalyticsDays)
+	}
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	analytics := &models.DeviceAnalytics{
+		GameID:  gameID,
+		From:    today.AddDate(0, 0, 1-days),
+		To:      now,
+		Days:    days,
+		Devices: []models.DeviceStats{},
+	}
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return analytics, nil // No history yet
+	}
+
+	// History is oldest first, so each device's plays are too
+	byDevice := make(map[string][]models.ScoreEntry)
+	var total float64
+	for _, entry := range allScores.Scores {
+		if entry.Timestamp.Before(analytics.From) || entry.Timestamp.After(now) {
+			continue
+		}
+		analytics.Plays++
+		total += float64(entry.Score)
+		if entry.DeviceID == "" {
+			analytics.Unattributed++
+			continue
+		}
+		byDevice[entry.DeviceID] = append(byDevice[entry.DeviceID], entry)
+	}
+	if analytics.Plays > 0 {
+		analytics.AverageScore = total / float64(analytics.Plays)
+	}
+
+	scoring := s.scoring(ctx, gameID)
+	for deviceID, plays := range byDevice {
+		stats := deviceStats(deviceID, plays, analytics)
+		if scoring.Precision() > 0 {
+			stats.DisplayAverageScore = scoring.FormatMean(stats.AverageScore)
+			stats.DisplayHighScore = scoring.Format(stats.HighScore)
+		}
+		analytics.Devices = append(analytics.Devices, stats)
+	}
+	if scoring.Precision() > 0 && analytics.Plays > 0 {
+		analytics.DisplayAverageScore = scoring.FormatMean(analytics.AverageScore)
+	}
+	sort.Slice(analytics.Devices, func(i, j int) bool {
+		a, b := analytics.Devices[i], analytics.Devices[j]
+		if a.Plays != b.Plays {
+			return a.Plays > b.Plays
+		}
+		return a.DeviceID < b.DeviceID
+	})
+	return analytics, nil
+}
+
+// deviceStats summarizes one device's plays, oldest first, within the analytics window
+func deviceStats(deviceID string, plays []models.ScoreEntry, analytics *models.DeviceAnalytics) models.DeviceStats {
+	stats := models.DeviceStats{
+		DeviceID:    deviceID,
+		Plays:       len(plays),
+		PlaysPerDay: float64(len(plays)) / float64(analytics.Days),
+		HighScore:   plays[0].Score,
+		FirstPlay:   plays[0].Timestamp.UTC(),
+		LastPlay:    plays[len(plays)-1].Timestamp.UTC(),
+		Daily:       make([]models.DevicePlays, analytics.Days),
+	}
+	for i := range stats.Daily {
+		stats.Daily[i].Date = analytics.From.AddDate(0, 0, i).Format(dateLayout)
+	}
+
+	var total float64
+	var longestGap time.Duration
+	for i, entry := range plays {
+		total += float64(entry.Score)
+		if entry.Score > stats.HighScore {
+			stats.HighScore = entry.Score
+		}
+		if i > 0 {
+			longestGap = max(longestGap, entry.Timestamp.Sub(plays[i-1].Timestamp))
+		}
+		day := int(entry.Timestamp.Sub(analytics.From) / (24 * time.Hour))
+		if stats.Daily[day].Plays == 0 {
+			stats.ActiveDays++
+		}
+		stats.Daily[day].Plays++
+	}
+	longestGap = max(longestGap, analytics.To.Sub(stats.LastPlay))
+
+	stats.AverageScore = total / float64(len(plays))
+	if analytics.AverageScore != 0 {
+		// Relative to the average's magnitude, so a higher average reads as above it
+		// even when scores are negative
+		stats.ScoreDeviation = (stats.AverageScore - analytics.AverageScore) / math.Abs(analytics.AverageScore)
+	}
+	stats.Uptime = float64(stats.ActiveDays) / float64(analytics.Days)
+	stats.LongestGap = longestGap.Hours()
+	return stats
+}
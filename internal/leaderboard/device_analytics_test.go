@@ -0,0 +1,112 @@
+package leaderboard
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestDeviceAnalytics(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 7, 16, 12, 0, 0, 0, time.UTC)
+
+	t.Run("attributes submissions to the device holding the key", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		cabinet := apikeys.WithPrincipal(ctx, &apikeys.Principal{Name: "device:cabinet-1", Device: "cabinet-1"})
+
+		result, err := service.Submit(cabinet, "pacman", models.Submission{Initials: "AAA", Score: 1000})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if result.Entry.DeviceID != "cabinet-1" {
+			t.Errorf("Expected the entry to be attributed to cabinet-1, got %q", result.Entry.DeviceID)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "BBB", 2000); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+
+		analytics, err := service.DeviceAnalytics(ctx, "pacman", 1, time.Now())
+		if err != nil {
+			t.Fatalf("DeviceAnalytics failed: %v", err)
+		}
+		if analytics.Plays != 2 || analytics.Unattributed != 1 || len(analytics.Devices) != 1 || analytics.Devices[0].DeviceID != "cabinet-1" {
+			t.Errorf("Unexpected analytics: %+v", analytics)
+		}
+	})
+
+	t.Run("compares plays, scores and uptime", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		at := func(daysAgo, hour int) time.Time {
+			return time.Date(2025, 7, 16-daysAgo, hour, 0, 0, 0, time.UTC)
+		}
+		scores := []models.ScoreEntry{
+			{Initials: "AAA", Score: 1000, Timestamp: at(2, 10), DeviceID: "steady"},
+			{Initials: "BBB", Score: 1000, Timestamp: at(1, 10), DeviceID: "steady"},
+			{Initials: "CCC", Score: 1000, Timestamp: at(0, 10), DeviceID: "steady"},
+			{Initials: "DDD", Score: 3000, Timestamp: at(2, 9), DeviceID: "generous"},
+			{Initials: "EEE", Score: 4000, Timestamp: at(5, 9), DeviceID: "steady"}, // Before the window
+		}
+		if _, err := service.ImportScores(ctx, "pacman", models.ImportReplace, scores, false); err != nil {
+			t.Fatalf("ImportScores failed: %v", err)
+		}
+
+		analytics, err := service.DeviceAnalytics(ctx, "pacman", 3, now)
+		if err != nil {
+			t.Fatalf("DeviceAnalytics failed: %v", err)
+		}
+		if analytics.Plays != 4 || analytics.AverageScore != 1500 || len(analytics.Devices) != 2 {
+			t.Fatalf("Unexpected analytics: %+v", analytics)
+		}
+
+		steady, generous := analytics.Devices[0], analytics.Devices[1]
+		if steady.DeviceID != "steady" || steady.Plays != 3 || steady.PlaysPerDay != 1 || steady.ActiveDays != 3 || steady.Uptime != 1 {
+			t.Errorf("Unexpected steady device: %+v", steady)
+		}
+		if math.Abs(steady.ScoreDeviation-(-1.0/3)) > 1e-9 || generous.ScoreDeviation != 1 {
+			t.Errorf("Expected deviations of -1/3 and 1, got %v and %v", steady.ScoreDeviation, generous.ScoreDeviation)
+		}
+		if generous.Uptime != 1.0/3 || generous.LongestGap != 51 {
+			t.Errorf("Expected the silent device's uptime and gap to show it, got %+v", generous)
+		}
+		if len(steady.Daily) != 3 || steady.Daily[0].Date != "2025-07-14" || generous.Daily[0].Plays != 1 || generous.Daily[2].Plays != 0 {
+			t.Errorf("Unexpected daily plays: %+v, %+v", steady.Daily, generous.Daily)
+		}
+	})
+
+	t.Run("compares negative decimal scores", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SetScoring(ctx, "golf", models.ScoringSettings{Decimals: 1, AllowNegative: true})
+		scores := []models.ScoreEntry{
+			{Initials: "AAA", Score: -20, Timestamp: now.Add(-time.Hour), DeviceID: "tough"},
+			{Initials: "BBB", Score: -40, Timestamp: now.Add(-time.Hour), DeviceID: "tough"},
+			{Initials: "CCC", Score: 0, Timestamp: now.Add(-time.Hour), DeviceID: "easy"},
+		}
+		if _, err := service.ImportScores(ctx, "golf", models.ImportReplace, scores, false); err != nil {
+			t.Fatalf("ImportScores failed: %v", err)
+		}
+
+		analytics, _ := service.DeviceAnalytics(ctx, "golf", 1, now)
+		if analytics.DisplayAverageScore != "-2.0" || len(analytics.Devices) != 2 {
+			t.Fatalf("Expected a display average of -2.0, got %+v", analytics)
+		}
+		tough, easy := analytics.Devices[0], analytics.Devices[1]
+		if tough.ScoreDeviation != -0.5 || easy.ScoreDeviation != 1 {
+			t.Errorf("Expected the lower-scoring device below the average, got %v and %v", tough.ScoreDeviation, easy.ScoreDeviation)
+		}
+		if tough.DisplayHighScore != "-2.0" || tough.DisplayAverageScore != "-3.0" {
+			t.Errorf("Expected display scores with a decimal, got %+v", tough)
+		}
+	})
+
+	t.Run("bounds the window", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.DeviceAnalytics(ctx, "pacman", models.MaxDeviceAnalyticsDays+1, now); err == nil {
+			t.Error("Expected an oversized window to be refused")
+		}
+	})
+}
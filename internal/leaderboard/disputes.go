@@ -0,0 +1,189 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// maxDisputes caps a game's open disputes; flags on new scores are refused beyond it
+const maxDisputes = 1000
+
+// maxDisputeReports caps the reports kept per dispute; older ones are only counted
+const maxDisputeReports = 20
+
+// maxKeptScores caps the reviewed scores remembered per game, so they can't be flagged
+// again; the oldest are forgotten first
+const maxKeptScores = 1000
+
+func disputesKey(gameID string) string {
+	return fmt.Sprintf("disputes:%s", gameID)
+}
+
+// disputeRecord is a game's open disputes, oldest first, and the scores reviewed and kept
+type disputeRecord struct {
+	Disputes []models.ScoreDispute `json:"disputes"`
+	Kept     []string              `json:"kept,omitempty"`
+}
+
+// FlagScore reports a score in the game's history as suspicious, opening a dispute for
+// an operator to review or adding to its open one. Unknown scores fail with
+// models.ErrScoreNotFound, and scores an operator has already kept with
+// models.ErrDisputeClosed.
+func (s *Service) FlagScore(ctx context.Context, gameID, scoreID, reason string) (*models.ScoreDispute, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("reason cannot be empty")
+	}
+	if len(reason) > models.MaxDisputeReason {
+		return nil, fmt.Errorf("reason cannot exceed %d characters", models.MaxDisputeReason)
+	}
+
+	s.disputeMu.Lock()
+	defer s.disputeMu.Unlock()
+
+	record, err := s.getDisputes(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for _, kept := range record.Kept {
+		if kept == scoreID {
+			return nil, fmt.Errorf("%w: %s", models.ErrDisputeClosed, scoreID)
+		}
+	}
+
+	now := time.Now().UTC()
+	report := models.DisputeReport{Reason: reason, FlaggedAt: now}
+	i := findDispute(record, scoreID)
+	if i < 0 {
+		entry, ok := s.findScore(ctx, gameID, scoreID)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", models.ErrScoreNotFound, scoreID)
+		}
+		if len(record.Disputes) >= maxDisputes {
+			return nil, fmt.Errorf("%w: %d", models.ErrTooManyDisputes, len(record.Disputes))
+		}
+		entry.ID = scoreID
+		record.Disputes = append(record.Disputes, models.ScoreDispute{ScoreID: scoreID, GameID: gameID, Entry: entry, Opened: now})
+		i = len(record.Disputes) - 1
+	}
+
+	dispute := &record.Disputes[i]
+	dispute.Flags++
+	dispute.Reports = append(dispute.Reports, report)
+	if excess := len(dispute.Reports) - maxDisputeReports; excess > 0 {
+		dispute.Reports = dispute.Reports[excess:]
+	}
+	if err := s.saveJSON(ctx, disputesKey(gameID), record); err != nil {
+		return nil, fmt.Errorf("failed to save disputes: %w", err)
+	}
+
+	s.log(ctx).Info("score flagged", "game_id", gameID, "score_id", scoreID, "initials", dispute.Entry.Initials, "flags", dispute.Flags)
+	flagged := *dispute
+	return &flagged, nil
+}
+
+// Disputes lists a game's open disputes, most flagged first
+func (s *Service) Disputes(ctx context.Context, gameID string) (*models.DisputesResponse, error) {
+	record, err := s.getDisputes(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	disputes := append([]models.ScoreDispute{}, record.Disputes...)
+	sort.SliceStable(disputes, func(i, j int) bool {
+		return disputes[i].Flags > disputes[j].Flags
+	})
+	return &models.DisputesResponse{GameID: gameID, Disputes: disputes, Count: len(disputes)}, nil
+}
+
+// ResolveDispute closes a dispute with an operator's decision. Kept scores stand and
+// can't be flagged again; removed scores are deleted like DeleteScore, so the deletion
+// can be undone within the undo window. Unknown disputes fail with
+// models.ErrDisputeNotFound.
+func (s *Service) ResolveDispute(ctx context.Context, gameID, scoreID, action string) (*models.DisputeResolution, error) {
+	if action != models.DisputeKeep && action != models.DisputeRemove {
+		return nil, fmt.Errorf("action must be %s or %s", models.DisputeKeep, models.DisputeRemove)
+	}
+
+	s.disputeMu.Lock()
+	defer s.disputeMu.Unlock()
+
+	record, err := s.getDisputes(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	i := findDispute(record, scoreID)
+	if i < 0 {
+		return nil, fmt.Errorf("%w: %s", models.ErrDisputeNotFound, scoreID)
+	}
+	dispute := record.Disputes[i]
+	result := &models.DisputeResolution{GameID: gameID, ScoreID: scoreID, Action: action, Flags: dispute.Flags}
+
+	if action == models.DisputeRemove {
+		result.Deletion, err = s.DeleteScore(ctx, gameID, dispute.Entry.Initials, dispute.Entry.Timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", models.ErrScoreNotFound, scoreID)
+		}
+	} else {
+		record.Kept = append(record.Kept, scoreID)
+		if excess := len(record.Kept) - maxKeptScores; excess > 0 {
+			record.Kept = record.Kept[excess:]
+		}
+	}
+
+	record.Disputes = append(record.Disputes[:i], record.Disputes[i+1:]...)
+	if err := s.saveJSON(ctx, disputesKey(gameID), record); err != nil {
+		return nil, fmt.Errorf("failed to save disputes: %w", err)
+	}
+
+	s.log(ctx).Info("dispute resolved", "game_id", gameID, "score_id", scoreID, "action", action, "flags", dispute.Flags)
+	return result, nil
+}
+
+// findScore finds the history entry a score ID names
+func (s *Service) findScore(ctx context.Context, gameID, scoreID string) (models.ScoreEntry, bool) {
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return models.ScoreEntry{}, false // No history yet
+	}
+	for _, entry := range allScores.Scores {
+		if entry.ID == scoreID || (entry.ID == "" && models.ScoreID(entry.Initials, entry.Timestamp) == scoreID) {
+			return entry, true
+		}
+	}
+	return models.ScoreEntry{}, false
+}
+
+func findDispute(record *disputeRecord, scoreID string) int {
+	for i, dispute := range record.Disputes {
+		if dispute.ScoreID == scoreID {
+			return i
+		}
+	}
+	return -1
+}
+
+// getDisputes reads a game's disputes, empty when none were ever opened
+func (s *Service) getDisputes(ctx context.Context, gameID string) (*disputeRecord, error) {
+	record := &disputeRecord{}
+	data, err := s.db.Get(ctx, disputesKey(gameID))
+	if errors.Is(err, redis.Nil) {
+		return record, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disputes: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal disputes: %w", err)
+	}
+	return record, nil
+}
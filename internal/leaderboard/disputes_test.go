@@ -0,0 +1,92 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestScoreDisputes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("flags gather into one dispute until removed", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		service.SubmitScore(ctx, "pacman", "BBB", 999999)
+
+		board, _ := service.GetLeaderboard(ctx, "pacman")
+		suspect := board.Entries[0]
+		if suspect.Initials != "BBB" || suspect.ID == "" {
+			t.Fatalf("Expected BBB's entry to carry an ID, got %+v", suspect)
+		}
+
+		service.FlagScore(ctx, "pacman", suspect.ID, "impossible")
+		dispute, err := service.FlagScore(ctx, "pacman", suspect.ID, "  seen it done with a bot  ")
+		if err != nil {
+			t.Fatalf("FlagScore failed: %v", err)
+		}
+		if dispute.Flags != 2 || len(dispute.Reports) != 2 || dispute.Reports[1].Reason != "seen it done with a bot" {
+			t.Errorf("Expected two reports on one dispute, got %+v", dispute)
+		}
+		if dispute.Entry.Initials != "BBB" || dispute.Entry.Score != 999999 {
+			t.Errorf("Expected the disputed entry from history, got %+v", dispute.Entry)
+		}
+
+		disputes, err := service.Disputes(ctx, "pacman")
+		if err != nil || disputes.Count != 1 {
+			t.Fatalf("Expected one open dispute, got %+v (%v)", disputes, err)
+		}
+
+		result, err := service.ResolveDispute(ctx, "pacman", suspect.ID, models.DisputeRemove)
+		if err != nil {
+			t.Fatalf("ResolveDispute failed: %v", err)
+		}
+		if result.Deletion == nil || result.Deletion.Removed != 1 || result.Deletion.TombstoneID == "" {
+			t.Errorf("Expected an undoable deletion of the score, got %+v", result.Deletion)
+		}
+		board, _ = service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 1 || board.Entries[0].Initials != "AAA" {
+			t.Errorf("Expected only AAA left on the board, got %+v", board.Entries)
+		}
+		if disputes, _ := service.Disputes(ctx, "pacman"); disputes.Count != 0 {
+			t.Errorf("Expected the dispute closed, got %d open", disputes.Count)
+		}
+	})
+
+	t.Run("kept scores can't be flagged again", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		id := history.Scores[0].ID
+
+		service.FlagScore(ctx, "pacman", id, "too good")
+		if _, err := service.ResolveDispute(ctx, "pacman", id, models.DisputeKeep); err != nil {
+			t.Fatalf("ResolveDispute failed: %v", err)
+		}
+		if _, err := service.FlagScore(ctx, "pacman", id, "still too good"); !errors.Is(err, models.ErrDisputeClosed) {
+			t.Errorf("Expected ErrDisputeClosed, got %v", err)
+		}
+		if _, err := service.ResolveDispute(ctx, "pacman", id, models.DisputeKeep); !errors.Is(err, models.ErrDisputeNotFound) {
+			t.Errorf("Expected ErrDisputeNotFound once resolved, got %v", err)
+		}
+	})
+
+	t.Run("scores without a stored ID", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		entry := history.Scores[0]
+		history.Scores[0].ID = ""
+		service.saveJSON(ctx, "all_scores:pacman", history)
+
+		if _, err := service.FlagScore(ctx, "pacman", models.ScoreID(entry.Initials, entry.Timestamp), "old score"); err != nil {
+			t.Errorf("Expected scores stored before IDs to be found by their derived ID, got %v", err)
+		}
+		if _, err := service.FlagScore(ctx, "pacman", "nope", "missing"); !errors.Is(err, models.ErrScoreNotFound) {
+			t.Errorf("Expected ErrScoreNotFound, got %v", err)
+		}
+	})
+}
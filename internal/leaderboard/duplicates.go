@@ -0,0 +1,275 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/models"
+)
+
+// Registry and merge errors
+var (
+	ErrGameNotFound        = errors.New("game not found")
+	ErrMergeSameGame       = errors.New("a game can't be merged into itself")
+	ErrMergeScoringDiffers = errors.New("games with different decimals can't be merged")
+)
+
+// minTypoLength is the shortest game ID compared for typos; shorter IDs are too often
+// one character apart by chance
+const minTypoLength = 4
+
+// maxMergeHops bounds how many merges a submission's game ID is followed through
+const maxMergeHops = 5
+
+// ResolveGameID returns the game a submission for gameID belongs in, so variants of an
+// existing game don't register new ones. Surrounding whitespace is trimmed, merged games
+// lead to the game they were merged into, and an unknown ID that matches a registered
+// game but for letter case becomes that game. Games the caller's API key can't access
+// are never substituted.
+func (s *Service) ResolveGameID(ctx context.Context, gameID string) string {
+	resolved := strings.TrimSpace(gameID)
+	if resolved == "" {
+		return gameID
+	}
+
+	if _, err := s.GetGame(ctx, resolved); err != nil {
+		gameIDs, _ := s.ListGames(ctx)
+		for _, existing := range gameIDs {
+			if strings.EqualFold(existing, resolved) {
+				resolved = existing
+				break
+			}
+		}
+	}
+
+	for hops := 0; hops < maxMergeHops; hops++ {
+		game, err := s.GetGame(ctx, resolved)
+		if err != nil || game.MergedInto == "" {
+			break
+		}
+		resolved = game.MergedInto
+	}
+
+	if p := apikeys.PrincipalFromContext(ctx); p != nil && !p.CanAccessGame(resolved) {
+		return gameID
+	}
+	if resolved != gameID {
+		s.log(ctx).Info("game ID resolved to an existing game", "requested", gameID, "game_id", resolved)
+	}
+	return resolved
+}
+
+// FindDuplicateGames groups registered games whose IDs differ only in case, surrounding
+// whitespace or a single character, largest game first within each group
+func (s *Service) FindDuplicateGames(ctx context.Context) (*models.DuplicateGameReport, error) {
+	gameIDs, err := s.ListGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Union the games of every alike pair into groups
+	parent := make([]int, len(gameIDs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	reasons := map[int]map[string]bool{}
+	for i := range gameIDs {
+		for j := i + 1; j < len(gameIDs); j++ {
+			reason := duplicateReason(gameIDs[i], gameIDs[j])
+			if reason == "" {
+				continue
+			}
+			a, b := find(i), find(j)
+			parent[b] = a
+			if reasons[a] == nil {
+				reasons[a] = map[string]bool{}
+			}
+			for r := range reasons[b] {
+				reasons[a][r] = true
+			}
+			reasons[a][reason] = true
+		}
+	}
+
+	members := map[int][]int{}
+	for i := range gameIDs {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	report := &models.DuplicateGameReport{Checked: len(gameIDs), Groups: []models.DuplicateGameGroup{}}
+	for root, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+
+		group := models.DuplicateGameGroup{}
+		for _, i := range indexes {
+			group.Games = append(group.Games, s.duplicateGame(ctx, gameIDs[i]))
+		}
+		sort.SliceStable(group.Games, func(a, b int) bool {
+			return group.Games[a].Scores > group.Games[b].Scores
+		})
+		group.SuggestedTarget = group.Games[0].GameID
+
+		for _, reason := range []string{models.DuplicateCase, models.DuplicateWhitespace, models.DuplicateTypo} {
+			if reasons[root][reason] {
+				group.Reasons = append(group.Reasons, reason)
+			}
+		}
+		report.Groups = append(report.Groups, group)
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		return report.Groups[i].SuggestedTarget < report.Groups[j].SuggestedTarget
+	})
+	return report, nil
+}
+
+// duplicateGame describes a game for a duplicate report
+func (s *Service) duplicateGame(ctx context.Context, gameID string) models.DuplicateGame {
+	game := models.DuplicateGame{GameID: gameID}
+	if info, err := s.GetGame(ctx, gameID); err == nil {
+		game.CreatedAt = info.CreatedAt
+	}
+
+	history, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return game
+	}
+	game.Scores = len(history.Scores)
+	game.Players = len(deriveHighScores(gameID, s.gameSettings(ctx, gameID), history.Scores).HighScores)
+	for _, entry := range history.Scores {
+		if game.LastScoreAt == nil || entry.Timestamp.After(*game.LastScoreAt) {
+			last := entry.Timestamp
+			game.LastScoreAt = &last
+		}
+	}
+	return game
+}
+
+// duplicateReason explains why two game IDs look like the same game, or returns ""
+func duplicateReason(a, b string) string {
+	trimmedA, trimmedB := strings.TrimSpace(a), strings.TrimSpace(b)
+	switch {
+	case trimmedA == trimmedB:
+		return models.DuplicateWhitespace
+	case strings.EqualFold(trimmedA, trimmedB):
+		return models.DuplicateCase
+	}
+
+	foldedA, foldedB := strings.ToLower(trimmedA), strings.ToLower(trimmedB)
+	if len(foldedA) >= minTypoLength && len(foldedB) >= minTypoLength && withinOneEdit(foldedA, foldedB) {
+		return models.DuplicateTypo
+	}
+	return ""
+}
+
+// withinOneEdit reports whether a and b differ by at most one inserted, deleted or
+// substituted byte
+func withinOneEdit(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	if len(b)-len(a) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(a) && a[i] == b[i] {
+		i++
+	}
+	if len(a) == len(b) {
+		return a[i+min(1, len(a)-i):] == b[i+min(1, len(b)-i):]
+	}
+	return a[i:] == b[i+1:]
+}
+
+// MergeGames moves source's scores into target, skipping scores target already has, and
+// rebuilds target's high scores and leaderboard. source is then emptied, dropped from
+// the game list and kept as a pointer to target, so later submissions for it land in
+// target. A dry run reports the merge without making it.
+func (s *Service) MergeGames(ctx context.Context, source, target string, dryRun bool) (*models.GameMerge, error) {
+	if source == target {
+		return nil, ErrMergeSameGame
+	}
+	sourceGame, err := s.GetGame(ctx, source)
+	if err != nil || sourceGame.MergedInto != "" {
+		return nil, fmt.Errorf("%w: %s", ErrGameNotFound, source)
+	}
+	if targetGame, err := s.GetGame(ctx, target); err == nil && targetGame.MergedInto != "" {
+		return nil, fmt.Errorf("%w: %s was merged into %s", ErrGameNotFound, target, targetGame.MergedInto)
+	}
+	if s.scoring(ctx, source).Precision() != s.scoring(ctx, target).Precision() {
+		return nil, ErrMergeScoringDiffers
+	}
+
+	moving := []models.ScoreEntry{}
+	if history, err := s.getAllScores(ctx, source); err == nil {
+		moving = history.Scores
+	}
+
+	now := time.Now()
+	merged := &models.AllScoresRecord{GameID: target, Updated: now}
+	if history, err := s.getAllScores(ctx, target); err == nil {
+		merged.Scores = history.Scores
+	}
+	moved, duplicates := withoutDuplicates(merged.Scores, append([]models.ScoreEntry(nil), moving...))
+	merged.Scores = append(merged.Scores, moved...)
+	sort.SliceStable(merged.Scores, func(i, j int) bool {
+		return merged.Scores[i].Timestamp.Before(merged.Scores[j].Timestamp)
+	})
+
+	highScores := deriveHighScores(target, s.gameSettings(ctx, target), s.boardScores(ctx, target, merged.Scores))
+	result := &models.GameMerge{
+		Source:     source,
+		Target:     target,
+		DryRun:     dryRun,
+		Moved:      len(moved),
+		Duplicates: duplicates,
+		Scores:     len(merged.Scores),
+		Players:    len(highScores.HighScores),
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	if err := s.RestoreGame(ctx, merged, highScores); err != nil {
+		return nil, fmt.Errorf("failed to merge into %s: %w", target, err)
+	}
+	if err := s.retireGame(ctx, sourceGame, target); err != nil {
+		return nil, fmt.Errorf("merged into %s but failed to retire %s: %w", target, source, err)
+	}
+	result.Merged = true
+
+	s.log(ctx).Info("games merged", "source", source, "target", target, "moved", result.Moved, "duplicates", duplicates)
+	return result, nil
+}
+
+// retireGame empties a merged game, drops it from the game list and points it at target
+func (s *Service) retireGame(ctx context.Context, game *models.GameInfo, target string) error {
+	if err := s.RestoreGame(ctx, &models.AllScoresRecord{GameID: game.GameID, Scores: []models.ScoreEntry{}, Updated: time.Now()}, nil); err != nil {
+		return err
+	}
+
+	game.MergedInto = target
+	game.Updated = time.Now()
+	if err := s.saveJSON(ctx, gameKey(game.GameID), game); err != nil {
+		return fmt.Errorf("failed to save game: %w", err)
+	}
+
+	return s.unlistGame(ctx, game.GameID)
+}
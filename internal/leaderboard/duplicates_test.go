@@ -0,0 +1,109 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestDuplicateReason(t *testing.T) {
+	for _, tc := range []struct {
+		a, b, want string
+	}{
+		{"pacman", "pacman ", models.DuplicateWhitespace},
+		{"pacman", "PacMan", models.DuplicateCase},
+		{"pacman", " PACMAN", models.DuplicateCase},
+		{"pacman", "pacmn", models.DuplicateTypo},
+		{"pacman", "pacmen", models.DuplicateTypo},
+		{"pacman", "pacman2", models.DuplicateTypo},
+		{"pacman", "galaga", ""},
+		{"pacman", "pacman-ce", ""},
+		{"dig", "dug", ""}, // Too short to call a typo
+	} {
+		if got := duplicateReason(tc.a, tc.b); got != tc.want {
+			t.Errorf("duplicateReason(%q, %q) = %q, want %q", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestDuplicateGames(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake())
+	for gameID, scores := range map[string]int{"pacman": 3, "PacMan": 1, "pacmn": 2, "galaga": 1} {
+		for i := 0; i < scores; i++ {
+			service.SubmitScore(ctx, gameID, "AAA", int64(100*(i+1)))
+		}
+	}
+	service.SubmitScore(ctx, "PacMan", "BBB", 5000)
+
+	report, err := service.FindDuplicateGames(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Checked != 4 || len(report.Groups) != 1 {
+		t.Fatalf("Expected one group among 4 games, got %+v", report)
+	}
+	group := report.Groups[0]
+	if group.SuggestedTarget != "pacman" || len(group.Games) != 3 || len(group.Reasons) != 2 {
+		t.Errorf("Expected pacman, PacMan and pacmn alike by case and typo, got %+v", group)
+	}
+
+	t.Run("merges a duplicate into the suggested target", func(t *testing.T) {
+		result, err := service.MergeGames(ctx, "PacMan", "pacman", true)
+		if err != nil || result.Merged || result.Moved != 2 {
+			t.Fatalf("Expected a dry run moving 2 scores, got %+v (%v)", result, err)
+		}
+
+		result, err = service.MergeGames(ctx, "PacMan", "pacman", false)
+		if err != nil || !result.Merged || result.Scores != 5 || result.Players != 2 {
+			t.Fatalf("Expected 5 scores from 2 players after the merge, got %+v (%v)", result, err)
+		}
+		board, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 2 || board.Entries[0].Initials != "BBB" {
+			t.Errorf("Expected BBB's merged score to lead, got %+v", board.Entries)
+		}
+
+		games, _ := service.ListGames(ctx)
+		for _, gameID := range games {
+			if gameID == "PacMan" {
+				t.Error("Expected the merged game to leave the game list")
+			}
+		}
+		if _, err := service.MergeGames(ctx, "PacMan", "pacman", false); !errors.Is(err, ErrGameNotFound) {
+			t.Errorf("Expected merging a merged game again to fail, got %v", err)
+		}
+	})
+
+	t.Run("resolves variants of a game's ID at submit time", func(t *testing.T) {
+		for requested, want := range map[string]string{
+			"pacman":   "pacman",
+			"PacMan":   "pacman", // Merged
+			" PACMAN ": "pacman", // Merged, after trimming
+			"GALAGA":   "galaga",
+			"tetris":   "tetris",
+		} {
+			if got := service.ResolveGameID(ctx, requested); got != want {
+				t.Errorf("ResolveGameID(%q) = %q, want %q", requested, got, want)
+			}
+		}
+
+		scoped := apikeys.WithPrincipal(ctx, &apikeys.Principal{KeyID: "key-1", GameIDs: []string{"GALAGA"}})
+		if got := service.ResolveGameID(scoped, "GALAGA"); got != "GALAGA" {
+			t.Errorf("Expected a key not scoped to galaga to keep its own game ID, got %q", got)
+		}
+	})
+
+	t.Run("refuses merges that would mix decimals", func(t *testing.T) {
+		service.SetScoring(ctx, "speedrun", models.ScoringSettings{Decimals: 2})
+		if _, err := service.MergeGames(ctx, "galaga", "speedrun", false); !errors.Is(err, ErrMergeScoringDiffers) {
+			t.Errorf("Expected ErrMergeScoringDiffers, got %v", err)
+		}
+		if _, err := service.MergeGames(ctx, "galaga", "galaga", false); !errors.Is(err, ErrMergeSameGame) {
+			t.Errorf("Expected ErrMergeSameGame, got %v", err)
+		}
+	})
+}
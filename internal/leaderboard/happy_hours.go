@@ -0,0 +1,38 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// SetHappyHours replaces a game's scheduled happy hours, or removes them when hours is
+// empty. Scores already submitted keep the multiplier they were given.
+func (s *Service) SetHappyHours(ctx context.Context, gameID string, hours []models.HappyHour) (*models.GameInfo, error) {
+	if err := models.ValidateHappyHours(hours); err != nil {
+		return nil, err
+	}
+
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.HappyHours = nil
+		if len(hours) > 0 {
+			settings.HappyHours = hours
+		}
+		return nil
+	})
+}
+
+// applyHappyHour multiplies an entry's score by the game's happy hour running at now,
+// keeping the score as played alongside it
+func (s *Service) applyHappyHour(ctx context.Context, gameID string, entry *models.ScoreEntry, scoring *models.ScoringSettings, now time.Time) {
+	happyHour := models.ActiveHappyHour(s.gameSettings(ctx, gameID).HappyHours, now)
+	if happyHour == nil {
+		return
+	}
+
+	entry.RawScore = entry.Score
+	entry.Multiplier = happyHour.Multiplier
+	entry.HappyHour = happyHour.ID
+	entry.Score = scoring.Clamp(happyHour.Apply(entry.Score))
+}
@@ -0,0 +1,157 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestHappyHours(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("multiplies scores submitted during a window", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 5000); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+
+		startsAt, endsAt := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+		if _, err := service.SetHappyHours(ctx, "pacman", []models.HappyHour{
+			{ID: "launch", Multiplier: 1.5, StartsAt: &startsAt, EndsAt: &endsAt},
+			{ID: "double", Multiplier: 2, StartsAt: &startsAt, EndsAt: &endsAt},
+		}); err != nil {
+			t.Fatalf("SetHappyHours failed: %v", err)
+		}
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "BBB", Score: 3000})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if entry := result.Entry; entry.Score != 6000 || entry.RawScore != 3000 || entry.Multiplier != 2 || entry.HappyHour != "double" {
+			t.Errorf("Expected 3000 doubled by the highest multiplier, got %+v", entry)
+		}
+
+		board, err := service.GetLeaderboard(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("GetLeaderboard failed: %v", err)
+		}
+		if first := board.Entries[0]; first.Initials != "BBB" || first.Score != 6000 || first.RawScore != 3000 {
+			t.Errorf("Expected the multiplied score to lead the board annotated, got %+v", board.Entries)
+		}
+		if second := board.Entries[1]; second.Initials != "AAA" || second.Multiplier != 0 {
+			t.Errorf("Expected the earlier score to stand unmultiplied, got %+v", second)
+		}
+	})
+
+	t.Run("anti-cheat judges the score as played", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetAntiCheatRules(ctx, "pacman", models.AntiCheatRules{MaxScore: 5000}); err != nil {
+			t.Fatalf("SetAntiCheatRules failed: %v", err)
+		}
+		startsAt, endsAt := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+		if _, err := service.SetHappyHours(ctx, "pacman", []models.HappyHour{
+			{ID: "triple", Multiplier: 3, StartsAt: &startsAt, EndsAt: &endsAt},
+		}); err != nil {
+			t.Fatalf("SetHappyHours failed: %v", err)
+		}
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 4000})
+		if err != nil {
+			t.Fatalf("Expected a plausible raw score to be accepted, got %v", err)
+		}
+		if result.Entry.Score != 12000 || len(result.Entry.Flags) != 0 {
+			t.Errorf("Expected 12000 without flags, got %+v", result.Entry)
+		}
+	})
+
+	t.Run("leaves scores outside windows alone", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		startsAt, endsAt := time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)
+		if _, err := service.SetHappyHours(ctx, "pacman", []models.HappyHour{
+			{ID: "over", Multiplier: 2, StartsAt: &startsAt, EndsAt: &endsAt},
+		}); err != nil {
+			t.Fatalf("SetHappyHours failed: %v", err)
+		}
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 4000})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if result.Entry.Score != 4000 || result.Entry.RawScore != 0 || result.Entry.HappyHour != "" {
+			t.Errorf("Expected an unmultiplied score, got %+v", result.Entry)
+		}
+	})
+
+	t.Run("rejects invalid schedules", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for name, hours := range map[string][]models.HappyHour{
+			"multiplier of one":   {{ID: "same", Multiplier: 1, Days: []string{"fri"}, Start: "18:00", End: "21:00"}},
+			"unknown day":         {{ID: "bad-day", Multiplier: 2, Days: []string{"friday"}, Start: "18:00", End: "21:00"}},
+			"bad time":            {{ID: "bad-time", Multiplier: 2, Days: []string{"fri"}, Start: "6pm", End: "21:00"}},
+			"unknown timezone":    {{ID: "bad-zone", Multiplier: 2, Days: []string{"fri"}, Start: "18:00", End: "21:00", Timezone: "Mars/Olympus"}},
+			"no window":           {{ID: "empty", Multiplier: 2}},
+			"duplicate ids":       {{ID: "dup", Multiplier: 2, Days: []string{"fri"}, Start: "18:00", End: "21:00"}, {ID: "dup", Multiplier: 3, Days: []string{"sat"}, Start: "18:00", End: "21:00"}},
+			"uppercase id":        {{ID: "Friday", Multiplier: 2, Days: []string{"fri"}, Start: "18:00", End: "21:00"}},
+			"empty one-off range": {{ID: "instant", Multiplier: 2, StartsAt: &time.Time{}, EndsAt: &time.Time{}}},
+		} {
+			if _, err := service.SetHappyHours(ctx, "pacman", hours); err == nil {
+				t.Errorf("Expected %s to be rejected", name)
+			}
+		}
+	})
+
+	t.Run("clearing removes the schedule", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetHappyHours(ctx, "pacman", []models.HappyHour{
+			{ID: "friday", Multiplier: 2, Days: []string{"Fri"}, Start: "18:00", End: "21:00"},
+		}); err != nil {
+			t.Fatalf("SetHappyHours failed: %v", err)
+		}
+		game, err := service.SetHappyHours(ctx, "pacman", []models.HappyHour{})
+		if err != nil {
+			t.Fatalf("SetHappyHours failed: %v", err)
+		}
+		if game.Settings.HappyHours != nil {
+			t.Errorf("Expected no happy hours, got %+v", game.Settings.HappyHours)
+		}
+	})
+}
+
+func TestHappyHourWeeklyWindows(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation failed: %v", err)
+	}
+	// Friday 18:00 to 21:00 New York time, and a late show from Saturday 22:00 to 02:00
+	friday := models.HappyHour{ID: "friday", Multiplier: 2, Days: []string{"fri"}, Start: "18:00", End: "21:00", Timezone: "America/New_York"}
+	lateShow := models.HappyHour{ID: "late-show", Multiplier: 3, Days: []string{"sat"}, Start: "22:00", End: "02:00"}
+	for _, hours := range []*models.HappyHour{&friday, &lateShow} {
+		if err := hours.Validate(); err != nil {
+			t.Fatalf("Validate failed: %v", err)
+		}
+	}
+
+	for _, tc := range []struct {
+		name      string
+		happyHour models.HappyHour
+		at        time.Time
+		active    bool
+	}{
+		{"friday evening", friday, time.Date(2025, 7, 18, 19, 30, 0, 0, newYork), true},
+		{"friday opening minute", friday, time.Date(2025, 7, 18, 18, 0, 0, 0, newYork), true},
+		{"friday closing minute", friday, time.Date(2025, 7, 18, 21, 0, 0, 0, newYork), false},
+		{"friday evening in UTC", friday, time.Date(2025, 7, 18, 23, 30, 0, 0, time.UTC), true},
+		{"thursday evening", friday, time.Date(2025, 7, 17, 19, 30, 0, 0, newYork), false},
+		{"saturday late", lateShow, time.Date(2025, 7, 19, 23, 0, 0, 0, time.UTC), true},
+		{"sunday early hours", lateShow, time.Date(2025, 7, 20, 1, 30, 0, 0, time.UTC), true},
+		{"saturday early hours", lateShow, time.Date(2025, 7, 19, 1, 30, 0, 0, time.UTC), false},
+		{"sunday late", lateShow, time.Date(2025, 7, 20, 23, 0, 0, 0, time.UTC), false},
+	} {
+		if got := tc.happyHour.Active(tc.at); got != tc.active {
+			t.Errorf("%s: expected active %v, got %v", tc.name, tc.active, got)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"rawboard/internal/models"
+)
+
+// SubmissionHook runs custom logic on each submission before the service checks and
+// stores it. BeforeSubmit may change the submission, such as to normalize initials or
+// rescale the score, and refuses it by returning an error; a
+// *models.SubmissionRejectedError tells the client why. The changed submission is
+// validated like any other, so hooks can't store what the service wouldn't accept.
+// Side effects after a score is stored belong in a ScoreListener.
+type SubmissionHook interface {
+	BeforeSubmit(ctx context.Context, gameID string, submission *models.Submission) error
+}
+
+// SubmissionHookFunc adapts a function to a SubmissionHook
+type SubmissionHookFunc func(ctx context.Context, gameID string, submission *models.Submission) error
+
+func (f SubmissionHookFunc) BeforeSubmit(ctx context.Context, gameID string, submission *models.Submission) error {
+	return f(ctx, gameID, submission)
+}
+
+// WithSubmissionHook runs hook on every submission, after any hooks added before it
+func WithSubmissionHook(hook SubmissionHook) Option {
+	return func(s *Service) {
+		s.hooks = append(s.hooks, hook)
+	}
+}
+
+// runSubmissionHooks passes a submission through each hook in turn, stopping at the
+// first to refuse it
+func (s *Service) runSubmissionHooks(ctx context.Context, gameID string, submission *models.Submission) error {
+	for _, hook := range s.hooks {
+		err := hook.BeforeSubmit(ctx, gameID, submission)
+		if errors.Is(err, models.ErrSubmissionRejected) {
+			s.log(ctx).Info("score rejected by submission hook", "game_id", gameID, "initials", submission.Initials, "error", err)
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("submission hook failed: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestSubmissionHooks(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("transform what is stored", func(t *testing.T) {
+		double := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			submission.Score *= 2
+			return nil
+		})
+		rename := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			submission.Initials = "zzz"
+			return nil
+		})
+		service := NewService(database.NewFake(), WithSubmissionHook(double), WithSubmissionHook(rename))
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if result.Entry.Initials != "ZZZ" || result.Entry.Score != 200 {
+			t.Errorf("Expected ZZZ with 200 stored, got %s with %d", result.Entry.Initials, result.Entry.Score)
+		}
+	})
+
+	t.Run("reject submissions", func(t *testing.T) {
+		calls := 0
+		reject := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			return &models.SubmissionRejectedError{Reason: "no scores on " + gameID}
+		})
+		count := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			calls++
+			return nil
+		})
+		service := NewService(database.NewFake(), WithSubmissionHook(reject), WithSubmissionHook(count))
+
+		_, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		var rejected *models.SubmissionRejectedError
+		if !errors.As(err, &rejected) || rejected.Reason != "no scores on pacman" {
+			t.Fatalf("Expected the hook's rejection, got %v", err)
+		}
+		if calls != 0 {
+			t.Error("Expected hooks after a rejection not to run")
+		}
+		if record, err := service.GetAllScoresForGame(ctx, "pacman"); err == nil && len(record.Scores) != 0 {
+			t.Errorf("Expected nothing stored, got %d scores", len(record.Scores))
+		}
+	})
+
+	t.Run("changes are validated", func(t *testing.T) {
+		blank := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			submission.Initials = ""
+			return nil
+		})
+		service := NewService(database.NewFake(), WithSubmissionHook(blank))
+
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100}); err == nil {
+			t.Error("Expected blank initials from a hook to be refused")
+		}
+	})
+
+	t.Run("failures fail the submission", func(t *testing.T) {
+		broken := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			return errors.New("hook crashed")
+		})
+		service := NewService(database.NewFake(), WithSubmissionHook(broken))
+
+		_, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		if err == nil || errors.Is(err, models.ErrSubmissionRejected) {
+			t.Errorf("Expected a failed submission that isn't a rejection, got %v", err)
+		}
+	})
+
+	t.Run("signatures cover the submission as sent", func(t *testing.T) {
+		double := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *models.Submission) error {
+			submission.Score *= 2
+			return nil
+		})
+		service := NewService(database.NewFake(), WithSubmissionHook(double))
+		secret, err := service.CreateSigningSecret(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+
+		signed := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, time.Now(), "nonce-0001")
+		result, err := service.Submit(ctx, "pacman", signed)
+		if err != nil {
+			t.Fatalf("Expected the signature over the sent score to hold, got %v", err)
+		}
+		if result.Entry.Score != 200 {
+			t.Errorf("Expected the hook's 200 stored, got %d", result.Entry.Score)
+		}
+	})
+}
@@ -0,0 +1,140 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// ErrInvalidImport is returned with a report listing the problems when any imported
+// score fails validation; nothing is written
+var ErrInvalidImport = errors.New("import contains invalid scores")
+
+// ImportScores validates downloaded scores and makes them gameID's history, or adds them
+// to it in append mode, then rebuilds the game's high scores and leaderboard. Imported
+// scores are checked like submissions, except that anti-cheat rules and daily budgets
+// aren't applied again; an import with any invalid score is refused whole. A dry run
+// reports what the import would do without writing anything.
+func (s *Service) ImportScores(ctx context.Context, gameID, mode string, scores []models.ScoreEntry, dryRun bool) (*models.ImportReport, error) {
+	if mode == "" {
+		mode = models.ImportReplace
+	}
+	if mode != models.ImportReplace && mode != models.ImportAppend {
+		return nil, fmt.Errorf("import mode must be %s or %s", models.ImportReplace, models.ImportAppend)
+	}
+
+	report := &models.ImportReport{GameID: gameID, Mode: mode, DryRun: dryRun, Received: len(scores)}
+	scoring := s.scoring(ctx, gameID)
+	now := time.Now()
+
+	imported := make([]models.ScoreEntry, 0, len(scores))
+	for i, entry := range scores {
+		entry, err := s.checkImported(ctx, entry, scoring, now)
+		if err != nil {
+			report.Invalid++
+			if len(report.Problems) < models.MaxImportProblems {
+				report.Problems = append(report.Problems, models.ImportProblem{Index: i + 1, Initials: entry.Initials, Error: err.Error()})
+			}
+			continue
+		}
+		imported = append(imported, entry)
+	}
+	if report.Invalid > 0 {
+		return report, ErrInvalidImport
+	}
+
+	history := &models.AllScoresRecord{GameID: gameID, Updated: now}
+	if mode == models.ImportAppend {
+		if existing, err := s.getAllScores(ctx, gameID); err == nil {
+			history.Scores = existing.Scores
+		}
+		imported, report.Duplicates = withoutDuplicates(history.Scores, imported)
+	}
+	history.Scores = append(history.Scores, imported...)
+	sort.SliceStable(history.Scores, func(i, j int) bool {
+		return history.Scores[i].Timestamp.Before(history.Scores[j].Timestamp)
+	})
+
+	highScores := deriveHighScores(gameID, s.gameSettings(ctx, gameID), s.boardScores(ctx, gameID, history.Scores))
+	report.Scores = len(history.Scores)
+	report.Players = len(highScores.HighScores)
+	if dryRun {
+		return report, nil
+	}
+
+	if err := s.RestoreGame(ctx, history, highScores); err != nil {
+		return report, err
+	}
+	report.Imported = true
+
+	s.log(ctx).Info("scores imported", "game_id", gameID, "mode", mode, "received", report.Received, "duplicates", report.Duplicates, "scores", report.Scores)
+	return report, nil
+}
+
+// checkImported validates an imported score and normalizes it as Submit would
+func (s *Service) checkImported(ctx context.Context, entry models.ScoreEntry, scoring *models.ScoringSettings, now time.Time) (models.ScoreEntry, error) {
+	entry.Initials = strings.ToUpper(strings.TrimSpace(entry.Initials))
+	if len(entry.Initials) != 3 || strings.Contains(entry.Initials, " ") {
+		return entry, fmt.Errorf("initials must be exactly 3 characters with no spaces")
+	}
+	if s.IsBlocked(ctx, entry.Initials) {
+		return entry, fmt.Errorf("%w: %s", models.ErrBlockedInitials, entry.Initials)
+	}
+	if err := scoring.Check(entry.Score); err != nil {
+		return entry, err
+	}
+	if err := entry.Metadata.Validate(); err != nil {
+		return entry, err
+	}
+	if entry.Sequence < 0 || entry.ClientSequence < 0 {
+		return entry, fmt.Errorf("sequence cannot be negative")
+	}
+	if len(entry.DeviceID) > 50 {
+		return entry, fmt.Errorf("device_id cannot exceed 50 characters")
+	}
+	if entry.Timestamp.IsZero() {
+		return entry, fmt.Errorf("timestamp is required")
+	}
+	if entry.Timestamp.After(now) {
+		return entry, fmt.Errorf("timestamp cannot be in the future")
+	}
+
+	entry.DisplayScore = ""
+	if scoring.Precision() > 0 {
+		entry.DisplayScore = scoring.Format(entry.Score)
+	}
+	return entry, nil
+}
+
+// withoutDuplicates drops imported scores that are already in history, or repeated in
+// the import, matching on initials, score and time
+func withoutDuplicates(history, imported []models.ScoreEntry) ([]models.ScoreEntry, int) {
+	type scoreKey struct {
+		initials  string
+		score     int64
+		timestamp int64
+	}
+	keyOf := func(entry models.ScoreEntry) scoreKey {
+		return scoreKey{entry.Initials, entry.Score, entry.Timestamp.UnixNano()}
+	}
+
+	seen := make(map[scoreKey]bool, len(history)+len(imported))
+	for _, entry := range history {
+		seen[keyOf(entry)] = true
+	}
+
+	unique := imported[:0]
+	for _, entry := range imported {
+		if seen[keyOf(entry)] {
+			continue
+		}
+		seen[keyOf(entry)] = true
+		unique = append(unique, entry)
+	}
+	return unique, len(imported) - len(unique)
+}
@@ -0,0 +1,89 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestImportScores(t *testing.T) {
+	ctx := context.Background()
+	at := time.Now().Add(-time.Hour).Truncate(time.Second)
+	download := []models.ScoreEntry{
+		{Initials: "aaa", Score: 500, Timestamp: at},
+		{Initials: "BBB", Score: 900, Timestamp: at.Add(time.Minute)},
+		{Initials: "AAA", Score: 1200, Timestamp: at.Add(2 * time.Minute), NonCounting: true},
+	}
+
+	t.Run("replaces the history and rebuilds the leaderboard", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "ZZZ", 99999)
+
+		report, err := service.ImportScores(ctx, "pacman", models.ImportReplace, download, false)
+		if err != nil || !report.Imported || report.Scores != 3 || report.Players != 2 {
+			t.Fatalf("Expected 3 scores from 2 players to be imported, got %+v (%v)", report, err)
+		}
+
+		board, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 2 || board.Entries[0].Initials != "BBB" || board.Entries[1].Score != 500 {
+			t.Errorf("Expected BBB then AAA's counted 500, without ZZZ, got %+v", board.Entries)
+		}
+	})
+
+	t.Run("dry runs write nothing", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		report, err := service.ImportScores(ctx, "pacman", "", download, true)
+		if err != nil || report.Imported || report.Mode != models.ImportReplace || report.Scores != 3 {
+			t.Fatalf("Expected a dry run report of 3 scores, got %+v (%v)", report, err)
+		}
+		if _, err := service.GetAllScoresForGame(ctx, "pacman"); err == nil {
+			t.Error("Expected a dry run to leave the game without history")
+		}
+	})
+
+	t.Run("appends without duplicating scores", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "CCC", 700)
+		if _, err := service.ImportScores(ctx, "pacman", models.ImportAppend, download[:2], false); err != nil {
+			t.Fatal(err)
+		}
+
+		report, err := service.ImportScores(ctx, "pacman", models.ImportAppend, download, false)
+		if err != nil || report.Duplicates != 2 || report.Scores != 4 || report.Players != 3 {
+			t.Fatalf("Expected 2 duplicates and 4 scores from 3 players, got %+v (%v)", report, err)
+		}
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if history.Scores[0].Initials != "AAA" || history.Scores[len(history.Scores)-1].Initials != "CCC" {
+			t.Errorf("Expected the history in time order, got %+v", history.Scores)
+		}
+	})
+
+	t.Run("refuses imports with invalid scores", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		invalid := append([]models.ScoreEntry{
+			{Initials: "A B", Score: 1, Timestamp: at},
+			{Initials: "DDD", Score: -5, Timestamp: at},
+			{Initials: "EEE", Score: 5},
+			{Initials: "FFF", Score: 5, Timestamp: time.Now().Add(time.Hour)},
+		}, download...)
+
+		report, err := service.ImportScores(ctx, "pacman", models.ImportReplace, invalid, false)
+		if !errors.Is(err, ErrInvalidImport) || report.Invalid != 4 || report.Imported {
+			t.Fatalf("Expected 4 invalid scores and nothing imported, got %+v (%v)", report, err)
+		}
+		if report.Problems[0].Index != 1 || report.Problems[3].Index != 4 {
+			t.Errorf("Expected problems by position in the import, got %+v", report.Problems)
+		}
+		if _, err := service.GetAllScoresForGame(ctx, "pacman"); err == nil {
+			t.Error("Expected an invalid import to write nothing")
+		}
+
+		if _, err := service.ImportScores(ctx, "pacman", "merge", download, false); err == nil {
+			t.Error("Expected an unknown mode to be rejected")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/models"
+)
+
+// ErrClaimRequired is returned when a game only accepts claimed initials and the
+// submitted ones aren't
+var ErrClaimRequired = errors.New("this game only accepts claimed initials")
+
+// deviceContextKey carries the device a player lookup is scoped to
+type deviceContextKey struct{}
+
+// WithDevice scopes player lookups made with ctx, such as stats and ranks, to a device
+// in games whose initials are device-scoped. Other games ignore it.
+func WithDevice(ctx context.Context, deviceID string) context.Context {
+	return context.WithValue(ctx, deviceContextKey{}, deviceID)
+}
+
+// lookupDevice returns the device a player lookup in gameID is scoped to: the one
+// given with WithDevice, else the enrolled device making the request. Games that don't
+// scope initials to devices, and requests with neither, look up the initials across
+// every device.
+func (s *Service) lookupDevice(ctx context.Context, gameID string) string {
+	if s.gameSettings(ctx, gameID).InitialsPolicy != models.InitialsDevice {
+		return ""
+	}
+	if deviceID, ok := ctx.Value(deviceContextKey{}).(string); ok && deviceID != "" {
+		return deviceID
+	}
+	if p := apikeys.PrincipalFromContext(ctx); p != nil {
+		return p.Device
+	}
+	return ""
+}
+
+// gameSettings returns a game's settings, or the defaults for a game not registered yet
+func (s *Service) gameSettings(ctx context.Context, gameID string) models.GameSettings {
+	if game, err := s.GetGame(ctx, gameID); err == nil {
+		return game.Settings
+	}
+	return models.GameSettings{}
+}
+
+// playerScore reports whether entry was played by initials, on deviceID unless it's empty
+func playerScore(entry models.ScoreEntry, initials, deviceID string) bool {
+	return entry.Initials == initials && (deviceID == "" || entry.DeviceID == deviceID)
+}
+
+// SetInitialsPolicy changes who a game's initials stand for and rebuilds its high
+// scores from history under the new policy, so a device-scoped game splits shared
+// initials by the device that submitted each score and a shared game joins them again
+func (s *Service) SetInitialsPolicy(ctx context.Context, gameID, policy string) (*models.GameInfo, error) {
+	if !models.ValidInitialsPolicy(policy) {
+		return nil, fmt.Errorf("initials policy must be %s, %s or %s", models.InitialsShared, models.InitialsDevice, models.InitialsClaimed)
+	}
+	if policy == models.InitialsShared {
+		policy = ""
+	}
+
+	previous := s.gameSettings(ctx, gameID)
+	game, err := s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.InitialsPolicy = policy
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if (previous.InitialsPolicy == models.InitialsDevice) == (policy == models.InitialsDevice) {
+		return game, nil // High scores are keyed the same way
+	}
+
+	if err := s.rekeyHighScores(ctx, gameID, game.Settings); err != nil {
+		return nil, err
+	}
+	return game, nil
+}
+
+// rekeyHighScores rebuilds a game's high scores from history under settings' player
+// keys, keeping stored high scores history no longer has, such as ones pruned by retention
+func (s *Service) rekeyHighScores(ctx context.Context, gameID string, settings models.GameSettings) error {
+	stored, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		return nil // No scores yet
+	}
+
+	var history []models.ScoreEntry
+	if allScores, err := s.getAllScores(ctx, gameID); err == nil {
+		history = allScores.Scores
+	}
+	highScores := deriveHighScores(gameID, settings, s.boardScores(ctx, gameID, history))
+	inHistory := make(map[string]bool, len(highScores.HighScores))
+	for _, entry := range highScores.HighScores {
+		inHistory[entry.Initials] = true
+	}
+	for _, entry := range stored.HighScores {
+		if inHistory[entry.Initials] {
+			continue
+		}
+		key := settings.PlayerKey(entry.Initials, entry.DeviceID)
+		if existing, ok := highScores.HighScores[key]; !ok || entry.Score > existing.Score {
+			highScores.HighScores[key] = entry
+		}
+	}
+	highScores.Updated = time.Now()
+
+	if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), highScores); err != nil {
+		return fmt.Errorf("failed to save player high scores: %w", err)
+	}
+	return s.rebuildLeaderboard(ctx, gameID)
+}
@@ -0,0 +1,114 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestInitialsPolicy(t *testing.T) {
+	ctx := context.Background()
+	cabinet := func(deviceID string) context.Context {
+		return apikeys.WithPrincipal(ctx, &apikeys.Principal{Name: "device:" + deviceID, Device: deviceID})
+	}
+
+	t.Run("device-scoped initials are separate players on boards and in stats", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetInitialsPolicy(ctx, "pacman", models.InitialsDevice); err != nil {
+			t.Fatalf("SetInitialsPolicy failed: %v", err)
+		}
+
+		for _, play := range []struct {
+			deviceID string
+			score    int64
+		}{{"cabinet-1", 500}, {"cabinet-2", 300}, {"cabinet-1", 200}, {"cabinet-2", 400}} {
+			if err := service.SubmitScore(cabinet(play.deviceID), "pacman", "AAA", play.score); err != nil {
+				t.Fatalf("SubmitScore failed: %v", err)
+			}
+		}
+
+		board, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 2 || board.Entries[0].DeviceID != "cabinet-1" || board.Entries[1].Score != 400 {
+			t.Fatalf("Expected AAA on each cabinet, got %+v", board.Entries)
+		}
+
+		stats, err := service.GetPlayerStats(WithDevice(ctx, "cabinet-2"), "pacman", "AAA")
+		if err != nil {
+			t.Fatalf("GetPlayerStats failed: %v", err)
+		}
+		if stats.HighScore != 400 || stats.TotalScores != 2 {
+			t.Errorf("Expected cabinet-2's 2 scores, got %+v", stats)
+		}
+		// A cabinet looks up its own player without naming itself
+		rank, err := service.GetPlayerRank(cabinet("cabinet-2"), "pacman", "AAA")
+		if err != nil || rank.Rank != 2 || rank.TotalPlayers != 2 {
+			t.Errorf("Expected cabinet-2's AAA second of 2, got %+v, %v", rank, err)
+		}
+		// Without a device, the initials' best on any device answers
+		if rank, _ := service.GetPlayerRank(ctx, "pacman", "AAA"); rank.Score != 500 {
+			t.Errorf("Expected the best AAA, got %+v", rank)
+		}
+
+		result, err := service.DeletePlayer(ctx, "pacman", "AAA")
+		if err != nil || result.Removed != 4 || result.Remaining != 0 {
+			t.Errorf("Expected every device's AAA removed, got %+v, %v", result, err)
+		}
+	})
+
+	t.Run("switching policies rebuilds high scores from history", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for _, play := range []struct {
+			deviceID string
+			score    int64
+		}{{"cabinet-1", 500}, {"cabinet-2", 300}} {
+			if err := service.SubmitScore(cabinet(play.deviceID), "pacman", "AAA", play.score); err != nil {
+				t.Fatalf("SubmitScore failed: %v", err)
+			}
+		}
+
+		service.SetInitialsPolicy(ctx, "pacman", models.InitialsDevice)
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); len(board.Entries) != 2 {
+			t.Errorf("Expected AAA split by cabinet, got %+v", board.Entries)
+		}
+		if _, err := service.RecomputePlayer(ctx, "pacman", "AAA"); err != nil {
+			t.Fatalf("RecomputePlayer failed: %v", err)
+		}
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); len(board.Entries) != 2 {
+			t.Errorf("Expected a recompute to keep both cabinets, got %+v", board.Entries)
+		}
+
+		service.SetInitialsPolicy(ctx, "pacman", models.InitialsShared)
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); len(board.Entries) != 1 || board.Entries[0].Score != 500 {
+			t.Errorf("Expected AAA joined again, got %+v", board.Entries)
+		}
+	})
+
+	t.Run("claimed games only accept claimed initials with their PIN", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SetInitialsPolicy(ctx, "pacman", models.InitialsClaimed)
+		if _, err := service.ClaimInitials(ctx, "AAA", "4821"); err != nil {
+			t.Fatalf("ClaimInitials failed: %v", err)
+		}
+
+		if err := service.SubmitScore(ctx, "pacman", "BBB", 100); !errors.Is(err, ErrClaimRequired) {
+			t.Errorf("Expected ErrClaimRequired, got %v", err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); !errors.Is(err, ErrPINRequired) {
+			t.Errorf("Expected ErrPINRequired, got %v", err)
+		}
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100, PIN: "4821"}); err != nil {
+			t.Errorf("Expected the claimed initials to submit, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown policies", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetInitialsPolicy(ctx, "pacman", "household"); err == nil {
+			t.Error("Expected an unknown policy to be refused")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"rawboard/internal/database"
+	"rawboard/internal/tenants"
+)
+
+// invalidationChannel carries the games whose cached reads went stale on some replica
+const invalidationChannel = "rawboard:invalidate"
+
+// invalidation tells other replicas that a game's data changed
+type invalidation struct {
+	GameID string `json:"game_id"`
+	Tenant string `json:"tenant,omitempty"`
+	Origin string `json:"origin"` // Instance that made the change, which has already invalidated
+}
+
+// invalidateGame drops this replica's cached reads for a game and tells every other
+// replica to do the same. Databases without pub/sub only invalidate locally.
+func (s *Service) invalidateGame(ctx context.Context, gameID string) {
+	tenant := tenants.FromContext(ctx)
+	s.invalidateLocal(tenant, gameID)
+
+	pubsub, ok := s.db.(database.PubSub)
+	if !ok {
+		return
+	}
+	data, err := json.Marshal(invalidation{GameID: gameID, Tenant: tenant, Origin: s.instanceID})
+	if err == nil {
+		err = pubsub.Publish(ctx, invalidationChannel, string(data))
+	}
+	if err != nil {
+		s.log(ctx).Warn("failed to publish cache invalidation, other replicas may serve stale reads", "game_id", gameID, "error", err)
+	}
+}
+
+// invalidateLocal drops this replica's cached reads for the tenant's game
+func (s *Service) invalidateLocal(tenant, gameID string) {
+	s.analytics.invalidateGame(tenant, gameID)
+	s.boards.invalidate(boardKey{tenant: tenant, gameID: gameID})
+}
+
+// WatchInvalidations applies other replicas' invalidations to this replica's caches
+// until ctx is cancelled. It returns nil at once for databases without pub/sub.
+func (s *Service) WatchInvalidations(ctx context.Context) error {
+	pubsub, ok := s.db.(database.PubSub)
+	if !ok {
+		return nil
+	}
+
+	messages, err := pubsub.Subscribe(ctx, invalidationChannel)
+	if err != nil {
+		return err
+	}
+	for message := range messages {
+		var inv invalidation
+		if err := json.Unmarshal([]byte(message), &inv); err != nil {
+			s.logger.Warn("ignoring malformed cache invalidation", "error", err)
+			continue
+		}
+		if inv.Origin != s.instanceID {
+			s.invalidateLocal(inv.Tenant, inv.GameID)
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return errors.New("cache invalidation subscription closed")
+}
@@ -0,0 +1,68 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+)
+
+func TestInvalidation(t *testing.T) {
+	ctx := context.Background()
+
+	// waitFor polls condition until it holds, failing the test after a second
+	waitFor := func(t *testing.T, what string, condition func() bool) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatalf("Timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	t.Run("a submission on one replica refreshes cached analytics on another", func(t *testing.T) {
+		db := database.NewFake()
+		replicaA, replicaB := NewService(db), NewService(db)
+
+		if err := replicaA.SubmitScore(ctx, "pacman", "AAA", 1000); err != nil {
+			t.Fatal(err)
+		}
+		if analysis, err := replicaB.GetScoreAnalysis(ctx, "pacman", 5); err != nil || analysis.TotalScores != 1 {
+			t.Fatalf("Expected one score in B's cached analysis, got %+v (%v)", analysis, err)
+		}
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		watching := make(chan error, 1)
+		go func() { watching <- replicaB.WatchInvalidations(watchCtx) }()
+		waitFor(t, "the subscription", func() bool { return db.Subscribers(invalidationChannel) == 1 })
+
+		if err := replicaA.SubmitScore(ctx, "pacman", "BBB", 2000); err != nil {
+			t.Fatal(err)
+		}
+		waitFor(t, "B to see the new score", func() bool {
+			analysis, err := replicaB.GetScoreAnalysis(ctx, "pacman", 5)
+			return err == nil && analysis.TotalScores == 2
+		})
+
+		cancel()
+		if err := <-watching; err != nil {
+			t.Errorf("Expected nil after cancelling, got %v", err)
+		}
+		if db.Subscribers(invalidationChannel) != 0 {
+			t.Error("Expected the subscription to be closed")
+		}
+	})
+
+	t.Run("databases without pub/sub have nothing to watch", func(t *testing.T) {
+		service := NewService(plainDB{database.NewFake()})
+		if err := service.WatchInvalidations(ctx); err != nil {
+			t.Errorf("Expected nil, got %v", err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 1000); err != nil {
+			t.Errorf("Expected submissions to work without pub/sub, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// MergeHighScores folds player high scores from another instance into a game's, for
+// edge instances mirroring a central one. Each player keeps the better of the two
+// entries, so merges can arrive in any order, or more than once, and every instance
+// that has seen the same scores ends up with the same board. It returns how many
+// players' high scores changed; the leaderboard is rebuilt if any did.
+func (s *Service) MergeHighScores(ctx context.Context, gameID string, entries []models.ScoreEntry) (int, error) {
+	if len(entries) == 0 {
+		return 0, nil
+	}
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return 0, fmt.Errorf("failed to register game: %w", err)
+	}
+
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		highScores = &models.PlayerHighScores{GameID: gameID, HighScores: make(map[string]models.ScoreEntry)}
+	}
+	settings := s.gameSettings(ctx, gameID)
+
+	changed := 0
+	for _, entry := range entries {
+		initials := strings.ToUpper(strings.TrimSpace(entry.Initials))
+		if len(initials) != 3 {
+			continue
+		}
+		player := settings.PlayerKey(initials, entry.DeviceID)
+		merged := models.ScoreEntry{
+			ID:             entry.ID,
+			Initials:       initials,
+			Score:          entry.Score,
+			Timestamp:      entry.Timestamp,
+			Metadata:       entry.Metadata,
+			Sequence:       entry.Sequence,
+			ClientSequence: entry.ClientSequence,
+			RawScore:       entry.RawScore,
+			Multiplier:     entry.Multiplier,
+			HappyHour:      entry.HappyHour,
+		}
+		if player != initials {
+			merged.DeviceID = entry.DeviceID
+		}
+		if existing, ok := highScores.HighScores[player]; ok && !mergesAbove(merged, existing) {
+			continue
+		}
+		highScores.HighScores[player] = merged
+		changed++
+	}
+	if changed == 0 {
+		return 0, nil
+	}
+
+	highScores.Updated = time.Now()
+	if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), highScores); err != nil {
+		return 0, fmt.Errorf("failed to save player high scores: %w", err)
+	}
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return 0, err
+	}
+	s.invalidateGame(ctx, gameID)
+
+	s.log(ctx).Info("high scores merged", "game_id", gameID, "changed", changed)
+	return changed, nil
+}
+
+// mergesAbove reports whether a replaces b as a player's high score in a merge: the
+// higher score wins and, as for live submissions, the earlier of equal scores. The
+// score ID settles the rest, so the choice never depends on which side is local.
+func mergesAbove(a, b models.ScoreEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.Before(b.Timestamp)
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,102 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestMergeHighScores(t *testing.T) {
+	ctx := context.Background()
+	earlier := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Hour)
+
+	t.Run("keeps each player's better entry", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 5000)
+		service.SubmitScore(ctx, "pacman", "BBB", 9000)
+
+		changed, err := service.MergeHighScores(ctx, "pacman", []models.ScoreEntry{
+			{Initials: "AAA", Score: 7000, Timestamp: later},
+			{Initials: "BBB", Score: 3000, Timestamp: later},
+			{Initials: "CCC", Score: 4000, Timestamp: later},
+		})
+		if err != nil {
+			t.Fatalf("MergeHighScores failed: %v", err)
+		}
+		if changed != 2 {
+			t.Errorf("Expected AAA and CCC to change, got %d", changed)
+		}
+
+		leaderboard, _ := service.GetLeaderboard(ctx, "pacman")
+		want := map[string]int64{"BBB": 9000, "AAA": 7000, "CCC": 4000}
+		if len(leaderboard.Entries) != len(want) {
+			t.Fatalf("Expected 3 entries, got %+v", leaderboard.Entries)
+		}
+		for _, entry := range leaderboard.Entries {
+			if want[entry.Initials] != entry.Score {
+				t.Errorf("Expected %s at %d, got %d", entry.Initials, want[entry.Initials], entry.Score)
+			}
+		}
+	})
+
+	t.Run("ends the same whichever order merges arrive in", func(t *testing.T) {
+		first := []models.ScoreEntry{{ID: "a", Initials: "AAA", Score: 5000, Timestamp: later}}
+		second := []models.ScoreEntry{{ID: "b", Initials: "AAA", Score: 5000, Timestamp: earlier}}
+
+		forward := NewService(database.NewFake())
+		forward.MergeHighScores(ctx, "pacman", first)
+		forward.MergeHighScores(ctx, "pacman", second)
+		backward := NewService(database.NewFake())
+		backward.MergeHighScores(ctx, "pacman", second)
+		backward.MergeHighScores(ctx, "pacman", first)
+
+		for name, service := range map[string]*Service{"forward": forward, "backward": backward} {
+			leaderboard, _ := service.GetLeaderboard(ctx, "pacman")
+			if len(leaderboard.Entries) != 1 || leaderboard.Entries[0].ID != "b" {
+				t.Errorf("Expected the earlier 5000 to win %s, got %+v", name, leaderboard.Entries)
+			}
+		}
+
+		if changed, err := forward.MergeHighScores(ctx, "pacman", second); err != nil || changed != 0 {
+			t.Errorf("Expected repeating a merge to change nothing, got %d (%v)", changed, err)
+		}
+	})
+}
+
+func TestForwardedSubmissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("stores the score as of when it was played", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "BBB", 500)
+		playedAt := time.Now().Add(-2 * time.Hour).UTC()
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 1000, PlayedAt: playedAt, DeviceID: "cab-1"})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if !result.Entry.Timestamp.Equal(playedAt) || result.Entry.DeviceID != "cab-1" {
+			t.Errorf("Expected the entry played at %v on cab-1, got %+v", playedAt, result.Entry)
+		}
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if len(history.Scores) != 2 || history.Scores[0].Initials != "AAA" {
+			t.Errorf("Expected AAA's earlier play first in history, got %+v", history.Scores)
+		}
+	})
+
+	t.Run("reads later play times as now", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		before := time.Now()
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 1000, PlayedAt: before.Add(time.Hour)})
+		if err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if result.Entry.Timestamp.Before(before) || result.Entry.Timestamp.After(time.Now()) {
+			t.Errorf("Expected the entry stored now, got %v", result.Entry.Timestamp)
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// DeleteScore removes a player's score submitted at timestamp from the history,
+// recomputes their high score if it was affected, and regenerates the leaderboard
+func (s *Service) DeleteScore(ctx context.Context, gameID, initials string, timestamp time.Time) (*models.ModerationResult, error) {
+	initials = strings.ToUpper(strings.TrimSpace(initials))
+	return s.moderate(ctx, gameID, initials, func(entry models.ScoreEntry) bool {
+		return entry.Initials == initials && entry.Timestamp.Equal(timestamp)
+	}, false)
+}
+
+// DeletePlayer removes every score, the high score and unlocked achievements for
+// initials, then regenerates the leaderboard
+func (s *Service) DeletePlayer(ctx context.Context, gameID, initials string) (*models.ModerationResult, error) {
+	initials = strings.ToUpper(strings.TrimSpace(initials))
+	return s.moderate(ctx, gameID, initials, func(entry models.ScoreEntry) bool {
+		return entry.Initials == initials
+	}, true)
+}
+
+// moderate removes history entries matching remove and rebuilds the player's aggregates.
+// Without removeAll, a high score only changes when the removed scores included it, so
+// a high score whose history was pruned by retention isn't lost to an unrelated deletion.
+func (s *Service) moderate(ctx context.Context, gameID, initials string, remove func(models.ScoreEntry) bool, removeAll bool) (*models.ModerationResult, error) {
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil && !removeAll {
+		return nil, err
+	}
+
+	result := &models.ModerationResult{GameID: gameID, Initials: initials}
+	settings := s.gameSettings(ctx, gameID)
+	kind := models.TombstoneScore
+	if removeAll {
+		kind = models.TombstonePlayer
+	}
+	tombstone := &models.Tombstone{GameID: gameID, Kind: kind, Initials: initials, HighScores: map[string]models.ScoreEntry{}}
+
+	// The best removed score under each of the player's keys, of which device-scoped
+	// games have one per device
+	removedBest := map[string]int64{}
+	if allScores != nil {
+		kept := make([]models.ScoreEntry, 0, len(allScores.Scores))
+		for _, entry := range allScores.Scores {
+			if remove(entry) {
+				result.Removed++
+				tombstone.Scores = append(tombstone.Scores, entry)
+				key := settings.PlayerKey(entry.Initials, entry.DeviceID)
+				if best, ok := removedBest[key]; !ok || entry.Score > best {
+					removedBest[key] = entry.Score
+				}
+				continue
+			}
+			kept = append(kept, entry)
+		}
+
+		if result.Removed > 0 {
+			allScores.Scores = kept
+			allScores.Updated = time.Now()
+			if err := s.saveJSON(ctx, fmt.Sprintf("all_scores:%s", gameID), allScores); err != nil {
+				return nil, fmt.Errorf("failed to save score history: %w", err)
+			}
+			s.invalidateScoreIndex(ctx, gameID)
+			s.recountTimeseries(ctx, gameID, kept)
+		}
+	}
+
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		if result.Removed == 0 {
+			return nil, fmt.Errorf("no matching scores found")
+		}
+		highScores = &models.PlayerHighScores{GameID: gameID, HighScores: make(map[string]models.ScoreEntry)}
+	}
+
+	keys := playerKeys(highScores, initials)
+	if result.Removed == 0 && !(removeAll && len(keys) > 0) {
+		return nil, fmt.Errorf("no matching scores found")
+	}
+
+	changed := false
+	for _, key := range keys {
+		removed, ok := removedBest[key]
+		if !removeAll && !(ok && removed >= highScores.HighScores[key].Score) {
+			continue
+		}
+		tombstone.HighScores[key] = highScores.HighScores[key]
+		delete(highScores.HighScores, key)
+		if !removeAll && allScores != nil {
+			if best, ok := bestScore(keyScores(settings, s.boardScores(ctx, gameID, allScores.Scores), key), initials); ok {
+				highScores.HighScores[key] = best
+			}
+		}
+		changed = true
+	}
+	if changed {
+		highScores.Updated = time.Now()
+		if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), highScores); err != nil {
+			return nil, fmt.Errorf("failed to save player high scores: %w", err)
+		}
+	}
+
+	for _, key := range playerKeys(highScores, initials) {
+		if best := highScores.HighScores[key]; result.HighScore == nil || best.Score > result.HighScore.Score {
+			result.HighScore = &best
+		}
+	}
+	result.Remaining = len(highScores.HighScores)
+
+	if removeAll {
+		if tombstone.Achievements, err = s.forgetAchievements(ctx, gameID, initials); err != nil {
+			return nil, fmt.Errorf("failed to remove achievements: %w", err)
+		}
+	}
+	// Tallies can't take plays back out, so what is left is tallied again when needed
+	if err := s.forgetActivity(ctx, gameID, initials); err != nil {
+		return nil, fmt.Errorf("failed to reset player activity: %w", err)
+	}
+
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return nil, err
+	}
+	s.invalidateGame(ctx, gameID)
+
+	// The deletion is done either way; it just can't be undone
+	if err := s.saveTombstone(ctx, tombstone); err != nil {
+		s.log(ctx).Warn("failed to save tombstone", "game_id", gameID, "initials", initials, "error", err)
+	} else if tombstone.ID != "" {
+		result.TombstoneID = tombstone.ID
+		result.RestorableUntil = &tombstone.ExpiresAt
+	}
+
+	s.log(ctx).Info("scores removed by moderation", "game_id", gameID, "initials", initials, "removed", result.Removed)
+	return result, nil
+}
+
+// playerKeys returns the keys of initials' high scores: the initials, or in
+// device-scoped games one per device they played on
+func playerKeys(highScores *models.PlayerHighScores, initials string) []string {
+	var keys []string
+	for key, entry := range highScores.HighScores {
+		if entry.Initials == initials {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keyScores returns the scores settings' initials policy files under key
+func keyScores(settings models.GameSettings, scores []models.ScoreEntry, key string) []models.ScoreEntry {
+	matched := make([]models.ScoreEntry, 0)
+	for _, entry := range scores {
+		if settings.PlayerKey(entry.Initials, entry.DeviceID) == key {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
+
+// bestScore returns the highest (earliest on ties) score in history for initials
+func bestScore(scores []models.ScoreEntry, initials string) (models.ScoreEntry, bool) {
+	var best models.ScoreEntry
+	found := false
+	for _, entry := range scores {
+		if entry.Initials != initials {
+			continue
+		}
+		if !found || entry.Score > best.Score || (entry.Score == best.Score && entry.Timestamp.Before(best.Timestamp)) {
+			best = entry
+			found = true
+		}
+	}
+	return best, found
+}
@@ -0,0 +1,148 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestBestScore(t *testing.T) {
+	base := time.Date(2025, 7, 16, 15, 0, 0, 0, time.UTC)
+	scores := []models.ScoreEntry{
+		{Initials: "AAA", Score: 500, Timestamp: base},
+		{Initials: "BBB", Score: 900, Timestamp: base},
+		{Initials: "AAA", Score: 700, Timestamp: base.Add(2 * time.Minute)},
+		{Initials: "AAA", Score: 700, Timestamp: base.Add(time.Minute)},
+	}
+
+	best, ok := bestScore(scores, "AAA")
+	if !ok || best.Score != 700 || !best.Timestamp.Equal(base.Add(time.Minute)) {
+		t.Errorf("Expected the earliest 700, got %+v", best)
+	}
+
+	if _, ok := bestScore(scores, "CCC"); ok {
+		t.Error("Expected no score for a player without history")
+	}
+}
+
+func TestModeration(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("deleting a high score falls back to the next best", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_moderation_score_" + generateTestID()
+		service.SubmitScore(ctx, gameID, "AAA", 1000)
+		service.SubmitScore(ctx, gameID, "AAA", 9000)
+		service.SubmitScore(ctx, gameID, "BBB", 5000)
+
+		history, _ := service.GetAllScoresForGame(ctx, gameID)
+		cheated := history.Scores[1]
+
+		result, err := service.DeleteScore(ctx, gameID, "aaa", cheated.Timestamp)
+		if err != nil {
+			t.Fatalf("Failed to delete score: %v", err)
+		}
+		if result.Removed != 1 || result.HighScore == nil || result.HighScore.Score != 1000 {
+			t.Errorf("Expected AAA's high score to fall back to 1000, got %+v", result)
+		}
+
+		leaderboard, _ := service.GetLeaderboard(ctx, gameID)
+		if len(leaderboard.Entries) != 2 || leaderboard.Entries[0].Initials != "BBB" {
+			t.Errorf("Expected BBB to lead after the deletion, got %+v", leaderboard.Entries)
+		}
+	})
+
+	t.Run("deleting a player removes them everywhere", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_moderation_player_" + generateTestID()
+		service.SubmitScore(ctx, gameID, "BAD", 9000)
+		service.SubmitScore(ctx, gameID, "BAD", 8000)
+		service.SubmitScore(ctx, gameID, "GUD", 5000)
+
+		result, err := service.DeletePlayer(ctx, gameID, "BAD")
+		if err != nil {
+			t.Fatalf("Failed to delete player: %v", err)
+		}
+		if result.Removed != 2 || result.HighScore != nil || result.Remaining != 1 {
+			t.Errorf("Unexpected moderation result: %+v", result)
+		}
+
+		history, _ := service.GetAllScoresForGame(ctx, gameID)
+		if len(history.Scores) != 1 {
+			t.Errorf("Expected one score left in history, got %d", len(history.Scores))
+		}
+		if _, err := service.GetPlayerRank(ctx, gameID, "BAD"); err == nil {
+			t.Error("Expected the deleted player to have no rank")
+		}
+	})
+
+	t.Run("reports scores that don't exist", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_moderation_missing_" + generateTestID()
+		service.SubmitScore(ctx, gameID, "AAA", 1000)
+
+		if _, err := service.DeleteScore(ctx, gameID, "AAA", time.Now().Add(time.Hour)); err == nil {
+			t.Error("Expected an error for a timestamp with no score")
+		}
+		if _, err := service.DeletePlayer(ctx, gameID, "ZZZ"); err == nil {
+			t.Error("Expected an error for an unknown player")
+		}
+	})
+	t.Run("recomputing a player repairs a corrupted high score", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_moderation_recompute_" + generateTestID()
+		service.SubmitScore(ctx, gameID, "AAA", 1000)
+		service.SubmitScore(ctx, gameID, "AAA", 3000)
+		service.SubmitScore(ctx, gameID, "BBB", 5000)
+
+		// Corrupt AAA's high score as a bug might have
+		highScores, _ := service.GetPlayerHighScores(ctx, gameID)
+		highScores.HighScores["AAA"] = models.ScoreEntry{Initials: "AAA", Score: 99999, Timestamp: time.Now()}
+		if err := service.saveJSON(ctx, "player_high_scores:"+gameID, highScores); err != nil {
+			t.Fatal(err)
+		}
+		service.regenerateFilteredLeaderboard(ctx, gameID)
+
+		result, err := service.RecomputePlayer(ctx, gameID, "aaa")
+		if err != nil {
+			t.Fatalf("Failed to recompute player: %v", err)
+		}
+		if !result.Changed || result.PreviousHighScore.Score != 99999 || result.HighScore.Score != 3000 {
+			t.Errorf("Expected the high score to be corrected to 3000, got %+v", result)
+		}
+		if result.Scores != 2 || result.Rank != 2 || len(result.Achievements) == 0 {
+			t.Errorf("Expected two scores, rank 2 and achievements, got %+v", result)
+		}
+
+		leaderboard, _ := service.GetLeaderboard(ctx, gameID)
+		if len(leaderboard.Entries) != 2 || leaderboard.Entries[0].Initials != "BBB" || leaderboard.Entries[1].Score != 3000 {
+			t.Errorf("Expected the rebuilt leaderboard to rank BBB first, got %+v", leaderboard.Entries)
+		}
+		if other, _ := service.GetPlayerHighScores(ctx, gameID); other.HighScores["BBB"].Score != 5000 {
+			t.Errorf("Expected other players to be untouched, got %+v", other.HighScores["BBB"])
+		}
+
+		again, err := service.RecomputePlayer(ctx, gameID, "AAA")
+		if err != nil || again.Changed {
+			t.Errorf("Expected a second recompute to change nothing, got %+v (%v)", again, err)
+		}
+		if _, err := service.RecomputePlayer(ctx, gameID, "ZZZ"); !errors.Is(err, ErrNoHistory) {
+			t.Errorf("Expected ErrNoHistory for a player without scores, got %v", err)
+		}
+	})
+}
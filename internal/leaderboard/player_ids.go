@@ -0,0 +1,125 @@
+package leaderboard
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// playerIDSaltKey holds the random salt public player IDs are derived with. Each
+	// tenant's namespace has its own, so IDs can't be linked across tenants.
+	playerIDSaltKey = "player_id_salt"
+	// playerIDLength is the number of hex characters kept from a player's HMAC
+	playerIDLength = 16
+	// PublicHistoryPrecision is the granularity of timestamps in public history, coarse
+	// enough that a score can't be matched to a single visit to a machine
+	PublicHistoryPrecision = time.Hour
+)
+
+// PlayerID derives the public ID of initials in a game from salt. Initials are scoped
+// to their game, so the same player has unrelated IDs in different games.
+func PlayerID(salt []byte, gameID, initials string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(gameID + "\x00" + initials))
+	return hex.EncodeToString(mac.Sum(nil))[:playerIDLength]
+}
+
+// PlayerIDSalt returns the salt of the tenant ctx acts for, creating it on first use
+func (s *Service) PlayerIDSalt(ctx context.Context) ([]byte, error) {
+	tenant := tenants.FromContext(ctx)
+	if salt, ok := s.playerIDSalts.Load(tenant); ok {
+		return salt.([]byte), nil
+	}
+
+	s.saltMu.Lock()
+	defer s.saltMu.Unlock()
+
+	value, err := s.db.Get(ctx, playerIDSaltKey)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get player ID salt: %w", err)
+	}
+	if err == nil {
+		salt, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode player ID salt: %w", err)
+		}
+		s.playerIDSalts.Store(tenant, salt)
+		return salt, nil
+	}
+
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate player ID salt: %w", err)
+	}
+	created, err := s.db.SetNX(ctx, playerIDSaltKey, hex.EncodeToString(salt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save player ID salt: %w", err)
+	}
+	if !created {
+		// Another replica created one first, and everyone keeps that
+		value, err := s.db.Get(ctx, playerIDSaltKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get player ID salt: %w", err)
+		}
+		if salt, err = hex.DecodeString(value); err != nil {
+			return nil, fmt.Errorf("failed to decode player ID salt: %w", err)
+		}
+	}
+	s.playerIDSalts.Store(tenant, salt)
+	return salt, nil
+}
+
+// PublicHistory returns a game's most recent counted scores, newest first, with each
+// player's initials replaced by their public ID and timestamps coarsened to
+// PublicHistoryPrecision
+func (s *Service) PublicHistory(ctx context.Context, gameID string, limit int) (*models.PublicHistory, error) {
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	salt, err := s.PlayerIDSalt(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counted := make([]models.ScoreEntry, 0, len(allScores.Scores))
+	for _, entry := range allScores.Scores {
+		if !entry.NonCounting {
+			counted = append(counted, entry)
+		}
+	}
+	sort.SliceStable(counted, func(i, j int) bool { return counted[i].Timestamp.After(counted[j].Timestamp) })
+
+	history := &models.PublicHistory{GameID: gameID, Scores: make([]models.PublicScore, 0, min(limit, len(counted)))}
+	players := map[string]string{}
+	for _, entry := range counted {
+		playerID, ok := players[entry.Initials]
+		if !ok {
+			playerID = PlayerID(salt, gameID, entry.Initials)
+			players[entry.Initials] = playerID
+		}
+		if len(history.Scores) < limit {
+			history.Scores = append(history.Scores, models.PublicScore{
+				PlayerID:     playerID,
+				Score:        entry.Score,
+				DisplayScore: entry.DisplayScore,
+				Timestamp:    entry.Timestamp.UTC().Truncate(PublicHistoryPrecision),
+			})
+		}
+	}
+	history.TotalScores = len(counted)
+	history.Players = len(players)
+	return history, nil
+}
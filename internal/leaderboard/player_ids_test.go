@@ -0,0 +1,90 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/tenants"
+)
+
+func TestPlayerIDs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("each tenant keeps one salt", func(t *testing.T) {
+		db := tenants.NewDB(database.NewFake())
+		service := NewService(db)
+		acme := tenants.WithTenant(ctx, "acme")
+
+		first, err := service.PlayerIDSalt(acme)
+		if err != nil {
+			t.Fatalf("PlayerIDSalt failed: %v", err)
+		}
+		// A fresh service, like another replica, reads the same salt
+		again, _ := NewService(db).PlayerIDSalt(acme)
+		if !bytes.Equal(first, again) {
+			t.Error("Expected the tenant's salt to be stored")
+		}
+		other, _ := service.PlayerIDSalt(tenants.WithTenant(ctx, "globex"))
+		if bytes.Equal(first, other) {
+			t.Error("Expected tenants to have their own salts")
+		}
+	})
+
+	t.Run("replicas creating the salt at once agree on it", func(t *testing.T) {
+		db := database.NewFake()
+		salts := make([][]byte, 8)
+		var wg sync.WaitGroup
+		for i := range salts {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				salts[i], _ = NewService(db).PlayerIDSalt(ctx)
+			}()
+		}
+		wg.Wait()
+		for _, salt := range salts[1:] {
+			if salt == nil || !bytes.Equal(salt, salts[0]) {
+				t.Fatal("Expected every replica to use the same salt")
+			}
+		}
+	})
+
+	t.Run("public history identifies players only by their IDs", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for _, play := range []struct {
+			initials string
+			score    int64
+		}{{"AAA", 100}, {"BBB", 300}, {"AAA", 200}} {
+			if err := service.SubmitScore(ctx, "pacman", play.initials, play.score); err != nil {
+				t.Fatalf("SubmitScore failed: %v", err)
+			}
+		}
+
+		history, err := service.PublicHistory(ctx, "pacman", 2)
+		if err != nil {
+			t.Fatalf("PublicHistory failed: %v", err)
+		}
+		if history.TotalScores != 3 || history.Players != 2 || len(history.Scores) != 2 {
+			t.Fatalf("Expected 2 of 3 scores from 2 players, got %+v", history)
+		}
+		if history.Scores[0].Score != 200 {
+			t.Errorf("Expected the newest score first, got %+v", history.Scores)
+		}
+
+		salt, _ := service.PlayerIDSalt(ctx)
+		aaa := PlayerID(salt, "pacman", "AAA")
+		if history.Scores[0].PlayerID != aaa || history.Scores[1].PlayerID == aaa || len(aaa) != playerIDLength {
+			t.Errorf("Expected AAA's stable ID %s on their score only, got %+v", aaa, history.Scores)
+		}
+		if PlayerID(salt, "galaga", "AAA") == aaa {
+			t.Error("Expected IDs to be scoped to their game")
+		}
+		if ts := history.Scores[0].Timestamp; !ts.Equal(ts.Truncate(time.Hour)) {
+			t.Errorf("Expected the timestamp truncated to the hour, got %v", ts)
+		}
+	})
+}
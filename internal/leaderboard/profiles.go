@@ -0,0 +1,308 @@
+package leaderboard
+
+import (
+	"context"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// profilesKey is the database key holding every profile by initials; there are few
+	// enough possible initials for one key
+	profilesKey = "player_profiles"
+	// pinIterations slows guessing PINs from a leaked database; short PINs still fall
+	// quickly offline, so lockouts are what protect them online
+	pinIterations = 100000
+)
+
+// Profile errors
+var (
+	ErrProfileNotFound = errors.New("no profile for these initials")
+	ErrProfileExists   = errors.New("these initials already have a profile")
+	ErrWrongPIN        = errors.New("wrong PIN")
+	ErrProfileLocked   = errors.New("too many wrong PINs; try again later")
+	ErrPINRequired     = errors.New("these initials are claimed; this game needs their PIN")
+)
+
+// profileRecord is a profile as stored, with its PIN's hash and recent wrong guesses
+type profileRecord struct {
+	models.PlayerProfile
+	PINSalt     string     `json:"pin_salt"`
+	PINHash     string     `json:"pin_hash"`
+	WrongPINs   int        `json:"wrong_pins,omitempty"`
+	LockedUntil *time.Time `json:"locked_until,omitempty"`
+}
+
+// CreateProfile registers a profile for its initials, which only the PIN can change
+func (s *Service) CreateProfile(ctx context.Context, profile models.PlayerProfile, pin string) (*models.PlayerProfile, error) {
+	profile.Initials = models.NormalizeInitials(profile.Initials)
+	if len(profile.Initials) != 3 || strings.Contains(profile.Initials, " ") {
+		return nil, fmt.Errorf("initials must be exactly 3 characters with no spaces")
+	}
+	if s.IsBlocked(ctx, profile.Initials) {
+		return nil, fmt.Errorf("%w: %s", models.ErrBlockedInitials, profile.Initials)
+	}
+	if err := profile.Validate(); err != nil {
+		return nil, err
+	}
+	if err := models.ValidatePIN(pin); err != nil {
+		return nil, err
+	}
+
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	profiles, err := s.getProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := profiles[profile.Initials]; ok {
+		return nil, ErrProfileExists
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate PIN salt: %w", err)
+	}
+	hash, err := hashPIN(pin, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	profile.CreatedAt = time.Now().UTC()
+	profile.Updated = profile.CreatedAt
+	profiles[profile.Initials] = &profileRecord{PlayerProfile: profile, PINSalt: base64.StdEncoding.EncodeToString(salt), PINHash: hash}
+	if err := s.saveJSON(ctx, profilesKey, profiles); err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// ClaimInitials claims initials with nothing but a PIN, the arcade way; the profile's
+// display name is the initials until the player changes it
+func (s *Service) ClaimInitials(ctx context.Context, initials, pin string) (*models.PlayerProfile, error) {
+	initials = models.NormalizeInitials(initials)
+	return s.CreateProfile(ctx, models.PlayerProfile{Initials: initials, DisplayName: initials}, pin)
+}
+
+// VerifyPIN checks pin against the profile for initials, so a cabinet can confirm a
+// player before they play. Wrong PINs count towards the profile's lockout.
+func (s *Service) VerifyPIN(ctx context.Context, initials, pin string) (*models.PlayerProfile, error) {
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	profiles, err := s.getProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.verifyPIN(ctx, profiles, models.NormalizeInitials(initials), pin)
+	if err != nil {
+		return nil, err
+	}
+	return &record.PlayerProfile, nil
+}
+
+// SetRequirePIN makes submissions under claimed initials in a game carry their PIN, or
+// stops checking them. Unclaimed initials never need one.
+func (s *Service) SetRequirePIN(ctx context.Context, gameID string, require bool) (*models.GameInfo, error) {
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.RequirePIN = require
+		return nil
+	})
+}
+
+// checkClaim refuses a submission under claimed initials without their PIN when the
+// game requires PINs, and under unclaimed initials when the game's initials policy
+// requires claims
+func (s *Service) checkClaim(ctx context.Context, gameID, initials, pin string) error {
+	game, err := s.GetGame(ctx, gameID)
+	if errors.Is(err, ErrGameNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err // Unread settings might require a PIN
+	}
+	claimRequired := game.Settings.InitialsPolicy == models.InitialsClaimed
+	if !game.Settings.RequirePIN && !claimRequired {
+		return nil
+	}
+
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	profiles, err := s.getProfiles(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles[initials]; !ok {
+		if claimRequired {
+			return ErrClaimRequired
+		}
+		return nil
+	}
+	if pin == "" {
+		return ErrPINRequired
+	}
+	_, err = s.verifyPIN(ctx, profiles, initials, pin)
+	return err
+}
+
+// Profile returns the profile registered for initials
+func (s *Service) Profile(ctx context.Context, initials string) (*models.PlayerProfile, error) {
+	profiles, err := s.getProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := profiles[models.NormalizeInitials(initials)]
+	if !ok {
+		return nil, ErrProfileNotFound
+	}
+	return &record.PlayerProfile, nil
+}
+
+// UpdateProfile replaces a profile's display name, avatar and country once pin is
+// verified
+func (s *Service) UpdateProfile(ctx context.Context, initials, pin string, update models.PlayerProfile) (*models.PlayerProfile, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	profiles, err := s.getProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.verifyPIN(ctx, profiles, models.NormalizeInitials(initials), pin)
+	if err != nil {
+		return nil, err
+	}
+
+	record.DisplayName = update.DisplayName
+	record.Avatar = update.Avatar
+	record.Country = update.Country
+	record.Updated = time.Now().UTC()
+	if err := s.saveJSON(ctx, profilesKey, profiles); err != nil {
+		return nil, err
+	}
+	return &record.PlayerProfile, nil
+}
+
+// DeleteProfile removes a profile, such as one with an offensive name, freeing its
+// initials to be registered again. Scores are kept.
+func (s *Service) DeleteProfile(ctx context.Context, initials string) (*models.PlayerProfile, error) {
+	initials = models.NormalizeInitials(initials)
+
+	s.profileMu.Lock()
+	defer s.profileMu.Unlock()
+
+	profiles, err := s.getProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record, ok := profiles[initials]
+	if !ok {
+		return nil, ErrProfileNotFound
+	}
+	delete(profiles, initials)
+	if err := s.saveJSON(ctx, profilesKey, profiles); err != nil {
+		return nil, err
+	}
+	return &record.PlayerProfile, nil
+}
+
+// verifyPIN returns the profile for initials when pin is its PIN, counting wrong PINs
+// in profiles towards a lockout; s.profileMu must be held
+func (s *Service) verifyPIN(ctx context.Context, profiles map[string]*profileRecord, initials, pin string) (*profileRecord, error) {
+	record, ok := profiles[initials]
+	if !ok {
+		return nil, ErrProfileNotFound
+	}
+
+	now := time.Now().UTC()
+	if record.LockedUntil != nil && now.Before(*record.LockedUntil) {
+		return nil, ErrProfileLocked
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(record.PINSalt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PIN salt: %w", err)
+	}
+	hash, err := hashPIN(pin, salt)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(hash), []byte(record.PINHash)) == 1 {
+		if record.WrongPINs > 0 || record.LockedUntil != nil {
+			record.WrongPINs, record.LockedUntil = 0, nil
+			if err := s.saveJSON(ctx, profilesKey, profiles); err != nil {
+				return nil, err
+			}
+		}
+		return record, nil
+	}
+
+	record.WrongPINs++
+	if record.WrongPINs >= models.MaxPINAttempts {
+		lockedUntil := now.Add(models.PINLockout)
+		record.WrongPINs, record.LockedUntil = 0, &lockedUntil
+		s.log(ctx).Warn("profile locked after wrong PINs", "initials", initials, "locked_until", lockedUntil)
+	}
+	if err := s.saveJSON(ctx, profilesKey, profiles); err != nil {
+		return nil, err
+	}
+	return nil, ErrWrongPIN
+}
+
+// profileOf returns the profile for initials to show with their stats, or nil
+func (s *Service) profileOf(ctx context.Context, initials string) *models.PlayerProfile {
+	profile, err := s.Profile(ctx, initials)
+	if err != nil {
+		if !errors.Is(err, ErrProfileNotFound) {
+			s.log(ctx).Warn("failed to get profile", "initials", initials, "error", err)
+		}
+		return nil
+	}
+	return profile
+}
+
+// getProfiles reads every stored profile, keyed by initials
+func (s *Service) getProfiles(ctx context.Context) (map[string]*profileRecord, error) {
+	profiles := map[string]*profileRecord{}
+	data, err := s.db.Get(ctx, profilesKey)
+	if errors.Is(err, redis.Nil) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profiles: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), &profiles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal profiles: %w", err)
+	}
+	if profiles == nil {
+		profiles = map[string]*profileRecord{}
+	}
+	return profiles, nil
+}
+
+// hashPIN derives the stored hash of pin with salt
+func hashPIN(pin string, salt []byte) (string, error) {
+	key, err := pbkdf2.Key(sha256.New, pin, salt, pinIterations, 32)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash PIN: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
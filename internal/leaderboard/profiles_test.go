@@ -0,0 +1,163 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestProfiles(t *testing.T) {
+	ctx := context.Background()
+	ada := models.PlayerProfile{Initials: "ada", DisplayName: "Ada", Avatar: "👾", Country: "GB"}
+
+	t.Run("registers initials once and shows the profile with stats", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		profile, err := service.CreateProfile(ctx, ada, "1234")
+		if err != nil {
+			t.Fatalf("CreateProfile failed: %v", err)
+		}
+		if profile.Initials != "ADA" || profile.CreatedAt.IsZero() {
+			t.Errorf("Unexpected profile: %+v", profile)
+		}
+		if _, err := service.CreateProfile(ctx, ada, "9999"); !errors.Is(err, ErrProfileExists) {
+			t.Errorf("Expected ErrProfileExists, got %v", err)
+		}
+
+		if err := service.SubmitScore(ctx, "pacman", "ADA", 1000); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		stats, err := service.GetPlayerStats(ctx, "pacman", "ada")
+		if err != nil || stats.Profile == nil || stats.Profile.DisplayName != "Ada" {
+			t.Errorf("Expected stats with the profile, got %+v, %v", stats, err)
+		}
+		enhanced, err := service.GetEnhancedPlayerStats(ctx, "pacman", "ADA", false)
+		if err != nil || enhanced.Profile == nil || enhanced.Profile.Avatar != "👾" {
+			t.Errorf("Expected enhanced stats with the profile, got %+v, %v", enhanced, err)
+		}
+	})
+
+	t.Run("validates profiles and PINs", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		invalid := []struct {
+			name    string
+			profile models.PlayerProfile
+			pin     string
+		}{
+			{"short PIN", ada, "123"},
+			{"letters in PIN", ada, "12a4"},
+			{"no display name", models.PlayerProfile{Initials: "ADA"}, "1234"},
+			{"text avatar", models.PlayerProfile{Initials: "ADA", DisplayName: "Ada", Avatar: "A"}, "1234"},
+			{"country name", models.PlayerProfile{Initials: "ADA", DisplayName: "Ada", Country: "Britain"}, "1234"},
+			{"blocked initials", models.PlayerProfile{Initials: "KKK", DisplayName: "K"}, "1234"},
+		}
+		for _, tt := range invalid {
+			if _, err := service.CreateProfile(ctx, tt.profile, tt.pin); err == nil {
+				t.Errorf("%s: expected the profile to be refused", tt.name)
+			}
+		}
+		if _, err := service.CreateProfile(ctx, models.PlayerProfile{Initials: "BOB", DisplayName: "Bob", Avatar: "👍🏽"}, "12345678"); err != nil {
+			t.Errorf("Expected a skin-toned emoji avatar to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("updates only with the PIN and locks out guessing", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.CreateProfile(ctx, ada, "1234"); err != nil {
+			t.Fatalf("CreateProfile failed: %v", err)
+		}
+
+		renamed := models.PlayerProfile{DisplayName: "Countess"}
+		updated, err := service.UpdateProfile(ctx, "ADA", "1234", renamed)
+		if err != nil || updated.DisplayName != "Countess" || updated.Avatar != "" {
+			t.Fatalf("Expected the profile to be replaced, got %+v, %v", updated, err)
+		}
+
+		for i := 0; i < models.MaxPINAttempts; i++ {
+			if _, err := service.UpdateProfile(ctx, "ADA", "0000", renamed); !errors.Is(err, ErrWrongPIN) {
+				t.Fatalf("Expected ErrWrongPIN, got %v", err)
+			}
+		}
+		if _, err := service.UpdateProfile(ctx, "ADA", "1234", renamed); !errors.Is(err, ErrProfileLocked) {
+			t.Errorf("Expected the right PIN to be refused while locked, got %v", err)
+		}
+	})
+
+	t.Run("deleting frees the initials", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.CreateProfile(ctx, ada, "1234"); err != nil {
+			t.Fatalf("CreateProfile failed: %v", err)
+		}
+		if _, err := service.DeleteProfile(ctx, "ada"); err != nil {
+			t.Fatalf("DeleteProfile failed: %v", err)
+		}
+		if _, err := service.Profile(ctx, "ADA"); !errors.Is(err, ErrProfileNotFound) {
+			t.Errorf("Expected ErrProfileNotFound, got %v", err)
+		}
+		if _, err := service.CreateProfile(ctx, ada, "4321"); err != nil {
+			t.Errorf("Expected the initials to be registered again, got %v", err)
+		}
+	})
+
+	t.Run("games requiring PINs refuse claimed initials without theirs", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		claimed, err := service.ClaimInitials(ctx, "ada", "4821")
+		if err != nil {
+			t.Fatalf("ClaimInitials failed: %v", err)
+		}
+		if claimed.DisplayName != "ADA" {
+			t.Errorf("Expected the claim to be displayed as its initials, got %q", claimed.DisplayName)
+		}
+		if _, err := service.VerifyPIN(ctx, "ADA", "4821"); err != nil {
+			t.Errorf("Expected the PIN to verify, got %v", err)
+		}
+		if _, err := service.VerifyPIN(ctx, "BOB", "4821"); !errors.Is(err, ErrProfileNotFound) {
+			t.Errorf("Expected ErrProfileNotFound, got %v", err)
+		}
+
+		// Games that don't require PINs take claimed initials without one
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "ADA", Score: 100}); err != nil {
+			t.Fatalf("Submit failed: %v", err)
+		}
+		if _, err := service.SetRequirePIN(ctx, "pacman", true); err != nil {
+			t.Fatalf("SetRequirePIN failed: %v", err)
+		}
+
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "ADA", Score: 200}); !errors.Is(err, ErrPINRequired) {
+			t.Errorf("Expected ErrPINRequired, got %v", err)
+		}
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "ADA", Score: 200, PIN: "0000"}); !errors.Is(err, ErrWrongPIN) {
+			t.Errorf("Expected ErrWrongPIN, got %v", err)
+		}
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "ADA", Score: 300, PIN: "4821"}); err != nil {
+			t.Errorf("Expected the PIN to be accepted, got %v", err)
+		}
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "BOB", Score: 50}); err != nil {
+			t.Errorf("Expected unclaimed initials to need no PIN, got %v", err)
+		}
+
+		stats, err := service.GetPlayerStats(ctx, "pacman", "ADA")
+		if err != nil || stats.HighScore != 300 {
+			t.Errorf("Expected refused submissions to be left out, got %+v, %v", stats, err)
+		}
+	})
+
+	t.Run("refuses scores while the game's settings can't be read", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		if _, err := service.SetRequirePIN(ctx, "pacman", true); err != nil {
+			t.Fatalf("SetRequirePIN failed: %v", err)
+		}
+
+		db.FailKey(database.OpGet, "game:pacman", errors.New("connection reset"))
+		if err := service.checkClaim(ctx, "pacman", "AAA", ""); err == nil {
+			t.Error("Expected the PIN check to fail while the game can't be read")
+		}
+		db.Heal()
+		if err := service.checkClaim(ctx, "galaga", "AAA", ""); err != nil {
+			t.Errorf("Expected unregistered games to take scores without a PIN, got %v", err)
+		}
+	})
+}
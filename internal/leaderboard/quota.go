@@ -0,0 +1,96 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/models"
+)
+
+// WithGameLimit caps how many games each API key may create, unless the key has its
+// own limit. 0, the default, is unlimited. The master key is never limited.
+func WithGameLimit(limit int) Option {
+	return func(s *Service) {
+		s.gameLimit = limit
+	}
+}
+
+// keyGamesKey returns the database key listing the games an API key created
+func keyGamesKey(keyID string) string {
+	return fmt.Sprintf("key_games:%s", keyID)
+}
+
+// GameQuota reports the games an API key has created against its limit; maxGames is
+// the key's own limit, nil for the default
+func (s *Service) GameQuota(ctx context.Context, keyID string, maxGames *int) *models.GameQuota {
+	created := s.keyGames(ctx, keyID)
+	return &models.GameQuota{
+		KeyID:   keyID,
+		Limit:   s.gameLimitFor(maxGames),
+		Default: maxGames == nil,
+		Used:    len(created.GameIDs),
+		GameIDs: created.GameIDs,
+	}
+}
+
+// claimGame counts a new game against the API key creating it, failing with
+// models.ErrGameLimitExceeded once the key has created its limit
+func (s *Service) claimGame(ctx context.Context, p *apikeys.Principal, gameID string) error {
+	created := s.keyGames(ctx, p.KeyID)
+	for _, existing := range created.GameIDs {
+		if existing == gameID {
+			return nil
+		}
+	}
+
+	if limit := s.gameLimitFor(p.MaxGames); limit > 0 && len(created.GameIDs) >= limit {
+		s.log(ctx).Warn("game limit exceeded", "key_id", p.KeyID, "game_id", gameID, "limit", limit)
+		return fmt.Errorf("%w: API key %s has created %d of %d games", models.ErrGameLimitExceeded, p.Name, len(created.GameIDs), limit)
+	}
+
+	created.GameIDs = append(created.GameIDs, gameID)
+	created.Updated = time.Now()
+	return s.saveJSON(ctx, keyGamesKey(p.KeyID), created)
+}
+
+// unclaimGame stops counting a deleted game against the API key that created it
+func (s *Service) unclaimGame(ctx context.Context, keyID, gameID string) error {
+	created := s.keyGames(ctx, keyID)
+	kept := created.GameIDs[:0]
+	for _, existing := range created.GameIDs {
+		if existing != gameID {
+			kept = append(kept, existing)
+		}
+	}
+	if len(kept) == len(created.GameIDs) {
+		return nil
+	}
+	created.GameIDs = kept
+	created.Updated = time.Now()
+	return s.saveJSON(ctx, keyGamesKey(keyID), created)
+}
+
+// gameLimitFor returns a key's game limit given its own, nil for the default
+func (s *Service) gameLimitFor(maxGames *int) int {
+	if maxGames != nil {
+		return *maxGames
+	}
+	return s.gameLimit
+}
+
+// keyGames retrieves the games an API key created
+func (s *Service) keyGames(ctx context.Context, keyID string) *models.GameIndex {
+	created := &models.GameIndex{GameIDs: []string{}}
+	data, err := s.db.Get(ctx, keyGamesKey(keyID))
+	if err != nil {
+		return created
+	}
+	if err := json.Unmarshal([]byte(data), created); err != nil || created.GameIDs == nil {
+		s.log(ctx).Warn("failed to read an API key's games", "key_id", keyID, "error", err)
+		created.GameIDs = []string{}
+	}
+	return created
+}
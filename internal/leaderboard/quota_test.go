@@ -0,0 +1,60 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestGameLimit(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake(), WithGameLimit(2))
+	cabinet := apikeys.WithPrincipal(ctx, &apikeys.Principal{KeyID: "key-1", Name: "cabinet", GameIDs: []string{models.AllGames}})
+
+	for _, gameID := range []string{"pacman", "galaga", "pacman"} {
+		if err := service.SubmitScore(cabinet, gameID, "AAA", 100); err != nil {
+			t.Fatalf("Expected %s to be within the limit, got %v", gameID, err)
+		}
+	}
+	if err := service.SubmitScore(cabinet, "pacman-typo", "AAA", 100); !errors.Is(err, models.ErrGameLimitExceeded) {
+		t.Fatalf("Expected ErrGameLimitExceeded for a third game, got %v", err)
+	}
+	if _, err := service.GetGame(ctx, "pacman-typo"); err == nil {
+		t.Error("Expected the refused game not to be registered")
+	}
+	if game, _ := service.GetGame(ctx, "galaga"); game.CreatedBy != "key-1" {
+		t.Errorf("Expected galaga to record the key that created it, got %+v", game)
+	}
+
+	quota := service.GameQuota(ctx, "key-1", nil)
+	if quota.Limit != 2 || !quota.Default || quota.Used != 2 || len(quota.GameIDs) != 2 {
+		t.Errorf("Expected 2 of 2 games used, got %+v", quota)
+	}
+
+	t.Run("a key's own limit overrides the default", func(t *testing.T) {
+		unlimited := 0
+		override := apikeys.WithPrincipal(ctx, &apikeys.Principal{KeyID: "key-1", Name: "cabinet", MaxGames: &unlimited})
+		if err := service.SubmitScore(override, "pacman-typo", "AAA", 100); err != nil {
+			t.Errorf("Expected an unlimited key to create games, got %v", err)
+		}
+		if quota := service.GameQuota(ctx, "key-1", &unlimited); quota.Limit != 0 || quota.Default || quota.Used != 3 {
+			t.Errorf("Expected 3 games used with no limit, got %+v", quota)
+		}
+	})
+
+	t.Run("the master key and unauthenticated calls are never limited", func(t *testing.T) {
+		master := apikeys.WithPrincipal(ctx, apikeys.MasterPrincipal())
+		for _, gameID := range []string{"a", "b", "c"} {
+			if err := service.SubmitScore(master, gameID, "AAA", 100); err != nil {
+				t.Fatalf("Expected the master key to create %s, got %v", gameID, err)
+			}
+			if err := service.SubmitScore(ctx, gameID+"2", "AAA", 100); err != nil {
+				t.Fatalf("Expected an unauthenticated call to create %s2, got %v", gameID, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// MaxAroundWindow is the most entries above and below a player an around-me view returns
+const MaxAroundWindow = 25
+
+// rankingKey returns the database key holding a game's untruncated ranking
+func rankingKey(gameID string) string {
+	return fmt.Sprintf("ranking:%s", gameID)
+}
+
+// GetPlayerRank returns a player's absolute rank among every player of a game,
+// computed from the full high score table rather than the truncated leaderboard. In
+// device-scoped games it's the rank on the device the lookup is scoped to, or the
+// initials' best rank on any device.
+func (s *Service) GetPlayerRank(ctx context.Context, gameID, initials string) (*models.PlayerRank, error) {
+	initials = strings.ToUpper(strings.TrimSpace(initials))
+
+	ranking, err := s.getRanking(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	rank, entry, found := findPlayerRank(ranking.Entries, initials, s.lookupDevice(ctx, gameID))
+	if !found {
+		return nil, fmt.Errorf("no scores found for player %s", initials)
+	}
+
+	return &models.PlayerRank{
+		GameID:       gameID,
+		Initials:     initials,
+		Rank:         rank,
+		Score:        entry.Score,
+		TotalPlayers: len(ranking.Entries),
+		AchievedAt:   entry.Timestamp,
+	}, nil
+}
+
+// GetLeaderboardAround returns the window entries above and below a player with absolute ranks
+func (s *Service) GetLeaderboardAround(ctx context.Context, gameID, initials string, window int) (*models.AroundMeResponse, error) {
+	initials = strings.ToUpper(strings.TrimSpace(initials))
+	if window < 0 {
+		window = 0
+	}
+	if window > MaxAroundWindow {
+		window = MaxAroundWindow
+	}
+
+	ranking, err := s.getRanking(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	rank, _, found := findPlayerRank(ranking.Entries, initials, s.lookupDevice(ctx, gameID))
+	if !found {
+		return nil, fmt.Errorf("no scores found for player %s", initials)
+	}
+
+	return &models.AroundMeResponse{
+		GameID:       gameID,
+		Initials:     initials,
+		Rank:         rank,
+		TotalPlayers: len(ranking.Entries),
+		Window:       window,
+		Entries:      neighborhood(ranking.Entries, rank, window),
+	}, nil
+}
+
+// neighborhood returns up to window ranked entries either side of the 1-based rank
+func neighborhood(ranked []models.ScoreEntry, rank, window int) []models.RankedEntry {
+	start := rank - 1 - window
+	if start < 0 {
+		start = 0
+	}
+	end := rank + window
+	if end > len(ranked) {
+		end = len(ranked)
+	}
+
+	entries := make([]models.RankedEntry, 0, end-start)
+	for i := start; i < end; i++ {
+		entries = append(entries, models.RankedEntry{
+			Rank:      i + 1,
+			Initials:  ranked[i].Initials,
+			Score:     ranked[i].Score,
+			Timestamp: ranked[i].Timestamp,
+		})
+	}
+	return entries
+}
+
+// findRank returns the 1-based position of initials in a ranked high score list
+func findRank(ranked []models.ScoreEntry, initials string) (int, models.ScoreEntry, bool) {
+	return findPlayerRank(ranked, initials, "")
+}
+
+// findPlayerRank is findRank for initials on deviceID, or their best entry on any
+// device when deviceID is empty
+func findPlayerRank(ranked []models.ScoreEntry, initials, deviceID string) (int, models.ScoreEntry, bool) {
+	for i, entry := range ranked {
+		if playerScore(entry, initials, deviceID) {
+			return i + 1, entry, true
+		}
+	}
+	return 0, models.ScoreEntry{}, false
+}
+
+// GetRanking returns every player's high score in leaderboard order, including players
+// below the leaderboard's size
+func (s *Service) GetRanking(ctx context.Context, gameID string) (*models.Ranking, error) {
+	return s.getRanking(ctx, gameID)
+}
+
+// getRanking retrieves a game's untruncated ranking, building it from the high score
+// table for games whose board hasn't been regenerated since rankings were introduced
+func (s *Service) getRanking(ctx context.Context, gameID string) (*models.Ranking, error) {
+	if data, err := s.db.Get(ctx, rankingKey(gameID)); err == nil {
+		var ranking models.Ranking
+		decoder := json.NewDecoder(strings.NewReader(data))
+		if err := decoder.Decode(&ranking); err == nil {
+			return &ranking, nil
+		}
+	}
+
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := rankHighScores(highScores)
+	if err := s.saveRanking(ctx, gameID, entries); err != nil {
+		return nil, err
+	}
+
+	return &models.Ranking{GameID: gameID, Entries: entries, Updated: time.Now()}, nil
+}
+
+// saveRanking stores a game's untruncated ranking
+func (s *Service) saveRanking(ctx context.Context, gameID string, entries []models.ScoreEntry) error {
+	ranking := &models.Ranking{
+		GameID:  gameID,
+		Entries: entries,
+		Updated: time.Now(),
+	}
+	if err := s.saveJSON(ctx, rankingKey(gameID), ranking); err != nil {
+		return fmt.Errorf("failed to save ranking: %w", err)
+	}
+	return nil
+}
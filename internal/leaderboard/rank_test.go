@@ -0,0 +1,103 @@
+package leaderboard
+
+import (
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestFindRank(t *testing.T) {
+	now := time.Now()
+	highScores := &models.PlayerHighScores{
+		GameID: "pacman",
+		HighScores: map[string]models.ScoreEntry{
+			"AAA": {Initials: "AAA", Score: 500, Timestamp: now},
+			"BBB": {Initials: "BBB", Score: 9000, Timestamp: now},
+			"CCC": {Initials: "CCC", Score: 500, Timestamp: now.Add(time.Minute)},
+		},
+	}
+	for i := 0; i < 20; i++ {
+		initials := string(rune('D'+i)) + "ZZ"
+		highScores.HighScores[initials] = models.ScoreEntry{Initials: initials, Score: 1000 + int64(i), Timestamp: now}
+	}
+	ranked := rankHighScores(highScores)
+
+	t.Run("ranks players beyond the top 10", func(t *testing.T) {
+		rank, entry, found := findRank(ranked, "AAA")
+		if !found {
+			t.Fatal("Expected AAA to be ranked")
+		}
+		if rank != 23 || entry.Score != 500 {
+			t.Errorf("Expected AAA at rank 23 with 500, got rank %d with %d", rank, entry.Score)
+		}
+	})
+
+	t.Run("breaks ties the same way as the leaderboard", func(t *testing.T) {
+		cccRank, _, _ := findRank(ranked, "CCC")
+		aaaRank, _, _ := findRank(ranked, "AAA")
+		if cccRank != aaaRank-1 {
+			t.Errorf("Expected newer tied score CCC (%d) directly ahead of AAA (%d)", cccRank, aaaRank)
+		}
+	})
+
+	t.Run("reports unknown players", func(t *testing.T) {
+		if _, _, found := findRank(ranked, "XYZ"); found {
+			t.Error("Expected XYZ not to be found")
+		}
+	})
+}
+
+func TestNeighborhood(t *testing.T) {
+	ranked := make([]models.ScoreEntry, 10)
+	for i := range ranked {
+		ranked[i] = models.ScoreEntry{Initials: string(rune('A'+i)) + "AA", Score: int64(1000 - i*10)}
+	}
+
+	t.Run("returns window entries either side with absolute ranks", func(t *testing.T) {
+		entries := neighborhood(ranked, 5, 2)
+		if len(entries) != 5 || entries[0].Rank != 3 || entries[4].Rank != 7 {
+			t.Errorf("Expected ranks 3-7, got %+v", entries)
+		}
+		if entries[2].Initials != "EAA" {
+			t.Errorf("Expected player in the middle, got %s", entries[2].Initials)
+		}
+	})
+
+	t.Run("clamps at the top and bottom of the board", func(t *testing.T) {
+		if top := neighborhood(ranked, 1, 3); len(top) != 4 || top[0].Rank != 1 {
+			t.Errorf("Expected ranks 1-4 at the top, got %+v", top)
+		}
+		if bottom := neighborhood(ranked, 10, 3); len(bottom) != 4 || bottom[3].Rank != 10 {
+			t.Errorf("Expected ranks 7-10 at the bottom, got %+v", bottom)
+		}
+	})
+}
+
+func TestReceiptStanding(t *testing.T) {
+	ranked := []models.ScoreEntry{
+		{Initials: "BBB", Score: 9000},
+		{Initials: "AAA", Score: 5000},
+		{Initials: "CCC", Score: 3000},
+	}
+
+	t.Run("a player's high score reports its rank", func(t *testing.T) {
+		status, rank := receiptStanding(ranked, "AAA", 5000)
+		if status != models.ReceiptStatusHighScore || rank != 2 {
+			t.Errorf("Expected high_score at rank 2, got %s at %d", status, rank)
+		}
+	})
+
+	t.Run("a beaten score reports where it would rank", func(t *testing.T) {
+		status, rank := receiptStanding(ranked, "AAA", 4000)
+		if status != models.ReceiptStatusSuperseded || rank != 2 {
+			t.Errorf("Expected superseded at rank 2, got %s at %d", status, rank)
+		}
+	})
+
+	t.Run("a score no longer on record is removed", func(t *testing.T) {
+		if status, _ := receiptStanding(ranked, "ZZZ", 100); status != models.ReceiptStatusRemoved {
+			t.Errorf("Expected removed, got %s", status)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package leaderboard
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// receiptTokenBytes is the entropy in a receipt token; tokens are unguessable
+// so holding one is the only proof needed to look up its score
+const receiptTokenBytes = 18
+
+// receiptKey returns the database key holding the receipt for a token's hash
+func receiptKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return fmt.Sprintf("receipt:%s", hex.EncodeToString(sum[:]))
+}
+
+// IssueReceipt records a submission and returns a token that can later be used
+// to look up that single score. Only the token's hash is stored.
+func (s *Service) IssueReceipt(ctx context.Context, gameID, initials string, score int64) (string, error) {
+	buf := make([]byte, receiptTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate receipt token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	receipt := &models.ScoreReceipt{
+		GameID:      gameID,
+		Initials:    strings.ToUpper(strings.TrimSpace(initials)),
+		Score:       score,
+		SubmittedAt: time.Now().UTC(),
+	}
+	if err := s.saveJSON(ctx, receiptKey(token), receipt); err != nil {
+		return "", fmt.Errorf("failed to save receipt: %w", err)
+	}
+
+	return token, nil
+}
+
+// LookupReceipt returns the current standing of the score a receipt was issued for
+func (s *Service) LookupReceipt(ctx context.Context, token string) (*models.ReceiptStatus, error) {
+	data, err := s.db.Get(ctx, receiptKey(token))
+	if err != nil {
+		return nil, fmt.Errorf("receipt not found")
+	}
+
+	var receipt models.ScoreReceipt
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&receipt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal receipt: %w", err)
+	}
+
+	status := &models.ReceiptStatus{ScoreReceipt: receipt, Status: models.ReceiptStatusRemoved}
+
+	ranking, err := s.getRanking(ctx, receipt.GameID)
+	if err != nil {
+		return status, nil // The game's scores are gone
+	}
+
+	status.TotalPlayers = len(ranking.Entries)
+	status.Status, status.Rank = receiptStanding(ranking.Entries, receipt.Initials, receipt.Score)
+	status.OnLeaderboard = status.Status == models.ReceiptStatusHighScore && status.Rank <= s.LeaderboardSize(ctx, receipt.GameID)
+	return status, nil
+}
+
+// receiptStanding classifies a submitted score against the current ranking.
+// A player's high score reports its actual rank; a superseded score reports the
+// rank it would hold among the other players' high scores.
+func receiptStanding(ranked []models.ScoreEntry, initials string, score int64) (string, int) {
+	rank, best, found := findRank(ranked, initials)
+	if !found || best.Score < score {
+		return models.ReceiptStatusRemoved, 0
+	}
+	if best.Score == score {
+		return models.ReceiptStatusHighScore, rank
+	}
+
+	wouldRank := 1
+	for _, entry := range ranked {
+		if entry.Initials != initials && entry.Score > score {
+			wouldRank++
+		}
+	}
+	return models.ReceiptStatusSuperseded, wouldRank
+}
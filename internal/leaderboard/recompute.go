@@ -0,0 +1,115 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// ErrNoHistory is returned when a player has no counted scores in a game's history to
+// rebuild from
+var ErrNoHistory = errors.New("player has no scores in the game's history")
+
+// RecomputePlayer rebuilds one player's derived data from the game's history: their
+// high score, the ranking and leaderboard built from it, the score index, their activity
+// tally, and any achievements their history meets that they haven't unlocked. A high score older than the game's retention window is replaced by the
+// best score still in history, and only scores from the current season count. In
+// device-scoped games each of the initials' devices gets its own high score; the result
+// reports the best.
+func (s *Service) RecomputePlayer(ctx context.Context, gameID, initials string) (*models.RecomputeResult, error) {
+	initials = strings.ToUpper(strings.TrimSpace(initials))
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return nil, ErrNoHistory
+	}
+	var playerScores, counted []models.ScoreEntry
+	for _, entry := range allScores.Scores {
+		if entry.Initials != initials {
+			continue
+		}
+		playerScores = append(playerScores, entry)
+		if !entry.NonCounting {
+			counted = append(counted, entry)
+		}
+	}
+	settings := s.gameSettings(ctx, gameID)
+	counted = s.boardScores(ctx, gameID, counted)
+	bests := make(map[string]models.ScoreEntry)
+	for _, entry := range counted {
+		key := settings.PlayerKey(entry.Initials, entry.DeviceID)
+		if _, ok := bests[key]; ok {
+			continue
+		}
+		best, _ := bestScore(keyScores(settings, counted, key), initials)
+		highScore := models.ScoreEntry{ID: best.ID, Initials: initials, Score: best.Score, Timestamp: best.Timestamp, Metadata: best.Metadata, Sequence: best.Sequence, ClientSequence: best.ClientSequence}
+		if key != initials {
+			highScore.DeviceID = best.DeviceID
+		}
+		bests[key] = highScore
+	}
+	if len(bests) == 0 {
+		return nil, ErrNoHistory
+	}
+
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		highScores = &models.PlayerHighScores{GameID: gameID, HighScores: make(map[string]models.ScoreEntry)}
+	}
+
+	result := &models.RecomputeResult{GameID: gameID, Initials: initials, Scores: len(playerScores)}
+	changed := false
+	for key, best := range bests {
+		previous, ok := highScores.HighScores[key]
+		// The stored timestamp trails the history entry's slightly, so only the score is compared
+		if !ok || previous.Score != best.Score {
+			highScores.HighScores[key] = best
+			changed = true
+		}
+		if result.HighScore.Initials == "" || best.Score > result.HighScore.Score {
+			result.HighScore = best
+			result.PreviousHighScore = nil
+			if ok {
+				result.PreviousHighScore = &previous
+			}
+		}
+	}
+	result.Changed = changed
+
+	if result.Changed {
+		highScores.Updated = time.Now()
+		if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), highScores); err != nil {
+			return nil, fmt.Errorf("failed to save player high scores: %w", err)
+		}
+	}
+
+	// Rebuild the board, ranking and index even when the high score was right, in case
+	// those were what drifted
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return nil, err
+	}
+	s.invalidateScoreIndex(ctx, gameID)
+	s.invalidateGame(ctx, gameID)
+
+	if ranking, err := s.getRanking(ctx, gameID); err == nil {
+		if rank, _, ok := findRank(ranking.Entries, initials); ok {
+			result.Rank = rank
+		}
+	}
+	definitions, unlocks, _, err := s.recordAchievements(ctx, gameID, map[string][]models.ScoreEntry{initials: playerScores}, false, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to record achievements: %w", err)
+	}
+	result.Achievements = achievementsOf(definitions, unlocks.Players[initials])
+	if err := s.forgetActivity(ctx, gameID, initials); err != nil {
+		return nil, fmt.Errorf("failed to reset player activity: %w", err)
+	}
+	s.playerActivity(ctx, gameID, initials, playerScores)
+
+	s.log(ctx).Info("player recomputed from history", "game_id", gameID, "initials", initials, "changed", result.Changed)
+	return result, nil
+}
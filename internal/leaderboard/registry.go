@@ -0,0 +1,280 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// gameIndexKey is the database key holding the list of registered games
+const gameIndexKey = "games"
+
+// registryBackfilledKey marks a namespace whose games stored before the registry
+// existed have been registered
+const registryBackfilledKey = "games:backfilled"
+
+// gameKey returns the database key holding a game's registry record
+func gameKey(gameID string) string {
+	return fmt.Sprintf("game:%s", gameID)
+}
+
+// GetGame returns the registry record for a game. Games not registered fail with
+// ErrGameNotFound; other errors are the database's.
+func (s *Service) GetGame(ctx context.Context, gameID string) (*models.GameInfo, error) {
+	data, err := s.db.Get(ctx, gameKey(gameID))
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", ErrGameNotFound, gameID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game: %w", err)
+	}
+
+	var game models.GameInfo
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&game); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal game: %w", err)
+	}
+
+	return &game, nil
+}
+
+// ListGames returns the IDs of every registered game in sorted order
+// Games are registered on their first score submission or migration, and games stored
+// before the registry existed by BackfillRegistry
+func (s *Service) ListGames(ctx context.Context) ([]string, error) {
+	index, err := s.getGameIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gameIDs := make([]string, len(index.GameIDs))
+	copy(gameIDs, index.GameIDs)
+	sort.Strings(gameIDs)
+	return gameIDs, nil
+}
+
+// UpdateGameSettings applies update to a game's settings and saves the result,
+// registering the game first if it isn't known yet. Changing the leaderboard size
+// regenerates the board from players' high scores, so a larger board is backfilled
+// at once rather than as new scores arrive.
+func (s *Service) UpdateGameSettings(ctx context.Context, gameID string, update func(settings *models.GameSettings) error) (*models.GameInfo, error) {
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("failed to register game: %w", err)
+	}
+
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	maxEntries := game.Settings.MaxEntries
+	if err := update(&game.Settings); err != nil {
+		return nil, err
+	}
+	game.Updated = time.Now()
+
+	if err := s.saveJSON(ctx, gameKey(gameID), game); err != nil {
+		return nil, fmt.Errorf("failed to save game: %w", err)
+	}
+
+	if game.Settings.MaxEntries != maxEntries {
+		if err := s.rebuildLeaderboard(ctx, gameID); err != nil {
+			return nil, err
+		}
+	}
+
+	return game, nil
+}
+
+// registerGame records a game in the registry if it isn't already known. Only a game
+// whose record is missing is registered: a record that can't be read is left alone, so
+// its settings are never replaced by the defaults.
+func (s *Service) registerGame(ctx context.Context, gameID string) error {
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+
+	_, err := s.GetGame(ctx, gameID)
+	if err == nil {
+		return nil // Already registered
+	}
+	if !errors.Is(err, ErrGameNotFound) {
+		return err
+	}
+
+	// Games created with stored API keys count against the key's game limit
+	createdBy := ""
+	if p := apikeys.PrincipalFromContext(ctx); p != nil && !p.Master {
+		if err := s.claimGame(ctx, p, gameID); err != nil {
+			return err
+		}
+		createdBy = p.KeyID
+	}
+
+	now := time.Now()
+	game := models.GameInfo{
+		GameID:    gameID,
+		CreatedAt: now,
+		CreatedBy: createdBy,
+		Updated:   now,
+	}
+	if err := s.saveJSON(ctx, gameKey(gameID), &game); err != nil {
+		return fmt.Errorf("failed to save game: %w", err)
+	}
+
+	index, err := s.getGameIndex(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range index.GameIDs {
+		if existing == gameID {
+			return nil
+		}
+	}
+
+	index.GameIDs = append(index.GameIDs, gameID)
+	index.Updated = time.Now()
+	if err := s.saveJSON(ctx, gameIndexKey, index); err != nil {
+		return fmt.Errorf("failed to save game index: %w", err)
+	}
+
+	return nil
+}
+
+// BackfillRegistry registers the games stored before the registry existed, found by
+// their score history or leaderboard, so jobs driven by ListGames such as exports and
+// retention see them. It runs once per namespace, returning how many games it
+// registered; later calls return 0 at once. Databases that can't list their keys fail
+// with errors.ErrUnsupported.
+func (s *Service) BackfillRegistry(ctx context.Context) (int, error) {
+	if _, err := s.db.Get(ctx, registryBackfilledKey); err == nil {
+		return 0, nil
+	} else if !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("failed to read registry backfill marker: %w", err)
+	}
+	scanner, ok := s.db.(database.Scanner)
+	if !ok {
+		return 0, fmt.Errorf("database can't list keys: %w", errors.ErrUnsupported)
+	}
+
+	found := map[string]bool{}
+	for _, prefix := range []string{"all_scores:", "leaderboard:"} {
+		keys, err := scanner.ScanKeys(ctx, prefix)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list %s keys: %w", prefix, err)
+		}
+		for _, key := range keys {
+			// Leaderboard members of the legacy sorted set format aren't games
+			if gameID := strings.TrimPrefix(key, prefix); gameID != "" && !strings.Contains(gameID, ":member:") {
+				found[gameID] = true
+			}
+		}
+	}
+	gameIDs := make([]string, 0, len(found))
+	for gameID := range found {
+		gameIDs = append(gameIDs, gameID)
+	}
+	sort.Strings(gameIDs)
+
+	registered := 0
+	for _, gameID := range gameIDs {
+		if _, err := s.GetGame(ctx, gameID); err == nil {
+			continue
+		} else if !errors.Is(err, ErrGameNotFound) {
+			return registered, err
+		}
+		if err := s.registerGame(ctx, gameID); err != nil {
+			return registered, fmt.Errorf("failed to register %s: %w", gameID, err)
+		}
+		registered++
+	}
+
+	if err := s.db.Set(ctx, registryBackfilledKey, time.Now().UTC().Format(time.RFC3339)); err != nil {
+		return registered, fmt.Errorf("failed to save registry backfill marker: %w", err)
+	}
+	if registered > 0 {
+		s.log(ctx).Info("registered games stored before the registry", "games", registered)
+	}
+	return registered, nil
+}
+
+// getGameIndex retrieves the list of registered games, empty if none has been
+func (s *Service) getGameIndex(ctx context.Context) (*models.GameIndex, error) {
+	data, err := s.db.Get(ctx, gameIndexKey)
+	if errors.Is(err, redis.Nil) {
+		return &models.GameIndex{GameIDs: []string{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get game index: %w", err)
+	}
+
+	var index models.GameIndex
+	decoder := json.NewDecoder(strings.NewReader(data))
+	if err := decoder.Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal game index: %w", err)
+	}
+
+	return &index, nil
+}
+
+// unlistGame removes a game from the registry's list of games
+func (s *Service) unlistGame(ctx context.Context, gameID string) error {
+	s.registryMu.Lock()
+	defer s.registryMu.Unlock()
+
+	index, err := s.getGameIndex(ctx)
+	if err != nil {
+		return err
+	}
+	kept := index.GameIDs[:0]
+	for _, existing := range index.GameIDs {
+		if existing != gameID {
+			kept = append(kept, existing)
+		}
+	}
+	index.GameIDs = kept
+	index.Updated = time.Now()
+	return s.saveJSON(ctx, gameIndexKey, index)
+}
+
+// saveJSON encodes a value as JSON and stores it under key
+func (s *Service) saveJSON(ctx context.Context, key string, value interface{}) error {
+	var buf strings.Builder
+	encoder := json.NewEncoder(&buf)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+
+	jsonData := strings.TrimSuffix(buf.String(), "\n")
+	return s.db.Set(ctx, key, jsonData)
+}
+
+// PendingMigrations returns registered games whose leaderboard still uses the legacy,
+// history-less format. Legacy games unknown to the registry migrate on first read.
+func (s *Service) PendingMigrations(ctx context.Context) ([]string, error) {
+	gameIDs, err := s.ListGames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, gameID := range gameIDs {
+		if _, err := s.getRawLeaderboard(ctx, gameID); err != nil {
+			continue // No leaderboard yet
+		}
+		if _, err := s.getAllScores(ctx, gameID); err != nil {
+			pending = append(pending, gameID)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,78 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("lists games registered at once", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				service.SubmitScore(ctx, fmt.Sprintf("game-%02d", i), "AAA", 100)
+			}(i)
+		}
+		wg.Wait()
+
+		if games, _ := service.ListGames(ctx); len(games) != 20 {
+			t.Errorf("Expected 20 games listed, got %d: %v", len(games), games)
+		}
+	})
+
+	t.Run("keeps a record it can't read", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		if _, err := service.UpdateGameSettings(ctx, "pacman", func(settings *models.GameSettings) error {
+			settings.RequirePIN = true
+			return nil
+		}); err != nil {
+			t.Fatalf("UpdateGameSettings failed: %v", err)
+		}
+
+		db.FailKey(database.OpGet, "game:pacman", errors.New("connection reset"))
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err == nil {
+			t.Error("Expected the submission to fail while the game can't be read")
+		}
+		db.Heal()
+		if game, err := service.GetGame(ctx, "pacman"); err != nil || !game.Settings.RequirePIN {
+			t.Errorf("Expected pacman's settings kept, got %+v (%v)", game, err)
+		}
+		if _, err := service.GetGame(ctx, "galaga"); !errors.Is(err, ErrGameNotFound) {
+			t.Errorf("Expected ErrGameNotFound for an unknown game, got %v", err)
+		}
+	})
+
+	t.Run("backfills games stored before the registry", func(t *testing.T) {
+		db := database.NewFake()
+		db.Set(ctx, "all_scores:pacman", `{"game_id":"pacman","scores":[]}`)
+		db.Set(ctx, "leaderboard:galaga", `{"game_id":"galaga","entries":[]}`)
+		db.Set(ctx, "leaderboard:galaga:member:AAA:1", `{}`)
+		service := NewService(db)
+		service.SubmitScore(ctx, "digdug", "AAA", 100)
+
+		registered, err := service.BackfillRegistry(ctx)
+		if err != nil || registered != 2 {
+			t.Fatalf("Expected 2 games registered, got %d (%v)", registered, err)
+		}
+		if games, _ := service.ListGames(ctx); len(games) != 3 || games[0] != "digdug" || games[1] != "galaga" || games[2] != "pacman" {
+			t.Errorf("Expected digdug, galaga and pacman listed, got %v", games)
+		}
+
+		db.Set(ctx, "all_scores:frogger", `{"game_id":"frogger","scores":[]}`)
+		if registered, _ := service.BackfillRegistry(ctx); registered != 0 {
+			t.Errorf("Expected the backfill to run once, got %d more", registered)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// archiveIDLayout names archives by when they were taken
+const archiveIDLayout = "20060102T150405.000Z"
+
+// archiveKey stores a models.GameArchive
+func archiveKey(gameID, archiveID string) string {
+	return fmt.Sprintf("archive:%s:%s", gameID, archiveID)
+}
+
+// archivesKey stores a game's archive list, oldest first
+func archivesKey(gameID string) string {
+	return fmt.Sprintf("archives:%s", gameID)
+}
+
+// archiveIndex is a game's archives, oldest first
+type archiveIndex struct {
+	Archives []models.GameArchiveInfo `json:"archives"`
+}
+
+// ResetGame starts a registered game afresh. Its leaderboard, high scores and score
+// history are archived first under a key named by the time, then cleared along with
+// what is derived from them: activity tallies, time series, top mover snapshots,
+// undoable deletions and open disputes. Settings, seasons and achievement unlocks are
+// kept. Reset listeners are told, as for a season boundary. Unknown games fail with
+// ErrGameNotFound.
+func (s *Service) ResetGame(ctx context.Context, gameID, reason string) (*models.GameArchiveInfo, error) {
+	if _, err := s.GetGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrGameNotFound, gameID)
+	}
+
+	s.archiveMu.Lock()
+	defer s.archiveMu.Unlock()
+
+	index, err := s.getArchives(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	archive := &models.GameArchive{
+		GameArchiveInfo: models.GameArchiveInfo{GameID: gameID, ArchivedAt: now, Reason: strings.TrimSpace(reason)},
+		Leaderboard:     models.Leaderboard{GameID: gameID, Entries: []models.ScoreEntry{}},
+		HighScores:      models.PlayerHighScores{GameID: gameID, HighScores: map[string]models.ScoreEntry{}},
+		History:         []models.ScoreEntry{},
+	}
+	// Resets in the same millisecond get the next free ID
+	for taken := true; taken; {
+		archive.ArchiveID = now.Format(archiveIDLayout)
+		taken = false
+		for _, existing := range index.Archives {
+			if existing.ArchiveID == archive.ArchiveID {
+				now, taken = now.Add(time.Millisecond), true
+				break
+			}
+		}
+	}
+
+	if board, err := s.GetLeaderboard(ctx, gameID); err == nil {
+		archive.Leaderboard = *board
+	}
+	if highScores, err := s.getPlayerHighScores(ctx, gameID); err == nil {
+		archive.HighScores = *highScores
+	}
+	if history, err := s.getAllScores(ctx, gameID); err == nil {
+		archive.History = history.Scores
+	}
+	archive.Scores = len(archive.History)
+	archive.Players = len(archive.HighScores.HighScores)
+
+	if err := s.saveJSON(ctx, archiveKey(gameID, archive.ArchiveID), archive); err != nil {
+		return nil, fmt.Errorf("failed to save archive: %w", err)
+	}
+	index.Archives = append(index.Archives, archive.GameArchiveInfo)
+	if err := s.saveJSON(ctx, archivesKey(gameID), index); err != nil {
+		return nil, fmt.Errorf("failed to save archive list: %w", err)
+	}
+
+	// Nothing is cleared until the archive is safe
+	if err := s.RestoreGame(ctx, &models.AllScoresRecord{GameID: gameID, Scores: []models.ScoreEntry{}, Updated: now}, nil); err != nil {
+		return nil, fmt.Errorf("archived as %s but failed to clear: %w", archive.ArchiveID, err)
+	}
+	if err := s.clearDerived(ctx, gameID, now); err != nil {
+		return nil, fmt.Errorf("archived as %s but failed to clear: %w", archive.ArchiveID, err)
+	}
+	s.notifyReset(ctx, gameID, models.ResetGame, "")
+
+	s.log(ctx).Info("game reset", "game_id", gameID, "archive_id", archive.ArchiveID, "scores", archive.Scores, "players", archive.Players)
+	info := archive.GameArchiveInfo
+	return &info, nil
+}
+
+// Archives lists a game's archives, newest first
+func (s *Service) Archives(ctx context.Context, gameID string) (*models.GameArchivesResponse, error) {
+	index, err := s.getArchives(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	archives := make([]models.GameArchiveInfo, 0, len(index.Archives))
+	for i := len(index.Archives) - 1; i >= 0; i-- {
+		archives = append(archives, index.Archives[i])
+	}
+	return &models.GameArchivesResponse{GameID: gameID, Archives: archives, Count: len(archives)}, nil
+}
+
+// Archive returns one of a game's archives whole. Unknown archives fail with
+// models.ErrArchiveNotFound.
+func (s *Service) Archive(ctx context.Context, gameID, archiveID string) (*models.GameArchive, error) {
+	data, err := s.db.Get(ctx, archiveKey(gameID, archiveID))
+	if errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("%w: %s", models.ErrArchiveNotFound, archiveID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archive: %w", err)
+	}
+
+	var archive models.GameArchive
+	if err := json.Unmarshal([]byte(data), &archive); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal archive: %w", err)
+	}
+	return &archive, nil
+}
+
+// clearDerived empties what a game's records derive from its score history, beyond
+// what RestoreGame rebuilds
+func (s *Service) clearDerived(ctx context.Context, gameID string, now time.Time) error {
+	s.activityMu.Lock()
+	err := s.saveJSON(ctx, playerActivityKey(gameID), &playerActivityRecord{Players: map[string]*activityTally{}, Updated: now})
+	s.activityMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to clear player activity: %w", err)
+	}
+
+	s.snapshotMu.Lock()
+	err = s.saveJSON(ctx, snapshotsKey(gameID), &snapshotHistory{GameID: gameID, Snapshots: []leaderboardSnapshot{}})
+	s.snapshotMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to clear leaderboard snapshots: %w", err)
+	}
+
+	// Undoing an older deletion or removing a disputed score would reach into the archive
+	s.tombstoneMu.Lock()
+	err = s.saveJSON(ctx, tombstonesKey(gameID), &tombstoneRecord{})
+	s.tombstoneMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to clear tombstones: %w", err)
+	}
+
+	s.disputeMu.Lock()
+	err = s.saveJSON(ctx, disputesKey(gameID), &disputeRecord{})
+	s.disputeMu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to clear disputes: %w", err)
+	}
+	return nil
+}
+
+// getArchives reads a game's archive list, empty before its first reset
+func (s *Service) getArchives(ctx context.Context, gameID string) (*archiveIndex, error) {
+	index := &archiveIndex{Archives: []models.GameArchiveInfo{}}
+	data, err := s.db.Get(ctx, archivesKey(gameID))
+	if errors.Is(err, redis.Nil) {
+		return index, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archive list: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), index); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal archive list: %w", err)
+	}
+	return index, nil
+}
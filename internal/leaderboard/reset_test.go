@@ -0,0 +1,95 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+type resetRecorder struct{ events []models.ResetEvent }
+
+func (r *resetRecorder) BoardReset(event models.ResetEvent) { r.events = append(r.events, event) }
+
+func TestResetGame(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("archives then clears", func(t *testing.T) {
+		resets := &resetRecorder{}
+		service := NewService(database.NewFake(), WithResetListener(resets))
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		service.SubmitScore(ctx, "pacman", "AAA", 2000)
+		service.SubmitScore(ctx, "pacman", "BBB", 1500)
+		deleted, _ := service.DeletePlayer(ctx, "pacman", "BBB")
+
+		info, err := service.ResetGame(ctx, "pacman", " new venue ")
+		if err != nil {
+			t.Fatalf("ResetGame failed: %v", err)
+		}
+		if info.ArchiveID == "" || info.Scores != 2 || info.Players != 1 || info.Reason != "new venue" {
+			t.Errorf("Expected 2 scores of 1 player archived, got %+v", info)
+		}
+
+		board, err := service.GetLeaderboard(ctx, "pacman")
+		if err != nil || len(board.Entries) != 0 {
+			t.Errorf("Expected an empty leaderboard, got %+v (%v)", board, err)
+		}
+		if history, err := service.GetAllScoresForGame(ctx, "pacman"); err == nil && len(history.Scores) != 0 {
+			t.Errorf("Expected an empty history, got %d scores", len(history.Scores))
+		}
+		if _, err := service.RestoreDeletion(ctx, "pacman", deleted.TombstoneID); !errors.Is(err, models.ErrTombstoneNotFound) {
+			t.Errorf("Expected deletions from before the reset not to be undoable, got %v", err)
+		}
+		if len(resets.events) != 1 || resets.events[0].Reason != models.ResetGame {
+			t.Errorf("Expected one game.reset event, got %+v", resets.events)
+		}
+
+		archive, err := service.Archive(ctx, "pacman", info.ArchiveID)
+		if err != nil {
+			t.Fatalf("Archive failed: %v", err)
+		}
+		if len(archive.History) != 2 || len(archive.Leaderboard.Entries) != 1 || archive.Leaderboard.Entries[0].Score != 2000 {
+			t.Errorf("Expected the board and history as they were, got %+v", archive)
+		}
+
+		service.SubmitScore(ctx, "pacman", "CCC", 10)
+		board, _ = service.GetLeaderboard(ctx, "pacman")
+		if len(board.Entries) != 1 || board.Entries[0].Initials != "CCC" {
+			t.Errorf("Expected the game to carry on afresh, got %+v", board.Entries)
+		}
+	})
+
+	t.Run("archives are listed newest first", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+
+		first, _ := service.ResetGame(ctx, "pacman", "")
+		second, err := service.ResetGame(ctx, "pacman", "")
+		if err != nil {
+			t.Fatalf("ResetGame failed: %v", err)
+		}
+		if first.ArchiveID == second.ArchiveID {
+			t.Fatalf("Expected distinct archive IDs, both %s", first.ArchiveID)
+		}
+
+		archives, err := service.Archives(ctx, "pacman")
+		if err != nil || archives.Count != 2 || archives.Archives[0].ArchiveID != second.ArchiveID {
+			t.Errorf("Expected both archives newest first, got %+v (%v)", archives, err)
+		}
+		if second.Scores != 0 {
+			t.Errorf("Expected the second reset to archive nothing, got %d scores", second.Scores)
+		}
+	})
+
+	t.Run("unknown games and archives", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.ResetGame(ctx, "nope", ""); !errors.Is(err, ErrGameNotFound) {
+			t.Errorf("Expected ErrGameNotFound, got %v", err)
+		}
+		if _, err := service.Archive(ctx, "nope", "20250101T000000.000Z"); !errors.Is(err, models.ErrArchiveNotFound) {
+			t.Errorf("Expected ErrArchiveNotFound, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,76 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// RestoreGame replaces a game's stored history and player high scores, then rebuilds
+// its leaderboard. If highScores is nil they are derived from the history played since
+// the live board was last reset by a season.
+func (s *Service) RestoreGame(ctx context.Context, history *models.AllScoresRecord, highScores *models.PlayerHighScores) error {
+	if history == nil || history.GameID == "" {
+		return fmt.Errorf("restore requires a score history with a game ID")
+	}
+	gameID := history.GameID
+
+	if highScores == nil {
+		highScores = deriveHighScores(gameID, s.gameSettings(ctx, gameID), s.boardScores(ctx, gameID, history.Scores))
+	}
+	if highScores.GameID == "" {
+		highScores.GameID = gameID
+	}
+	if highScores.GameID != gameID {
+		return fmt.Errorf("high scores belong to game %s, not %s", highScores.GameID, gameID)
+	}
+
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return fmt.Errorf("failed to register game: %w", err)
+	}
+
+	if err := s.saveJSON(ctx, fmt.Sprintf("all_scores:%s", gameID), history); err != nil {
+		return fmt.Errorf("failed to restore score history: %w", err)
+	}
+	s.invalidateScoreIndex(ctx, gameID)
+	s.recountTimeseries(ctx, gameID, history.Scores)
+
+	if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), highScores); err != nil {
+		return fmt.Errorf("failed to restore player high scores: %w", err)
+	}
+
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return fmt.Errorf("failed to rebuild leaderboard: %w", err)
+	}
+
+	s.invalidateGame(ctx, gameID)
+	return nil
+}
+
+// deriveHighScores computes each player's best score from a score history, keyed as
+// settings' initials policy keys players
+// Only counted scores are considered, and the earliest submission of a player's best score
+// wins, matching live submission behavior
+func deriveHighScores(gameID string, settings models.GameSettings, scores []models.ScoreEntry) *models.PlayerHighScores {
+	highScores := &models.PlayerHighScores{
+		GameID:     gameID,
+		HighScores: make(map[string]models.ScoreEntry),
+		Updated:    time.Now(),
+	}
+
+	for _, entry := range scores {
+		if entry.NonCounting {
+			continue
+		}
+		key := settings.PlayerKey(entry.Initials, entry.DeviceID)
+		existing, exists := highScores.HighScores[key]
+		if !exists || entry.Score > existing.Score ||
+			(entry.Score == existing.Score && entry.Timestamp.Before(existing.Timestamp)) {
+			highScores.HighScores[key] = entry
+		}
+	}
+
+	return highScores
+}
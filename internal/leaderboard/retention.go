@@ -0,0 +1,79 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// WithDefaultRetention sets the retention policy for games without their own. The zero
+// policy, the default, keeps everything.
+func WithDefaultRetention(policy models.RetentionPolicy) Option {
+	return func(s *Service) {
+		s.retention = policy
+	}
+}
+
+// RetentionPolicy returns the policy that applies to a game: its own, or the default
+func (s *Service) RetentionPolicy(game *models.GameInfo) models.RetentionPolicy {
+	if game.Settings.Retention != nil {
+		return *game.Settings.Retention
+	}
+	return s.retention
+}
+
+// PruneHistory removes raw score history older than the game's retention policy, then
+// the oldest scores beyond its score count. Player high scores and the leaderboard are
+// aggregates and are never pruned.
+// Returns nil without changes if no policy applies to the game.
+func (s *Service) PruneHistory(ctx context.Context, gameID string, now time.Time) (*models.RetentionResult, error) {
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.RetentionPolicy(game)
+	if !policy.Enabled() {
+		return nil, nil
+	}
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return nil, nil // Nothing stored yet
+	}
+
+	result := &models.RetentionResult{GameID: gameID}
+	kept := allScores.Scores
+	if policy.HistoryDays > 0 {
+		cutoff := now.Add(-time.Duration(policy.HistoryDays) * 24 * time.Hour)
+		result.Cutoff = &cutoff
+		kept = make([]models.ScoreEntry, 0, len(allScores.Scores))
+		for _, entry := range allScores.Scores {
+			if !entry.Timestamp.Before(cutoff) {
+				kept = append(kept, entry)
+			}
+		}
+	}
+	if policy.MaxScores > 0 && len(kept) > policy.MaxScores {
+		kept = kept[len(kept)-policy.MaxScores:] // History is kept oldest first
+	}
+
+	result.Removed = len(allScores.Scores) - len(kept)
+	result.Remaining = len(kept)
+	if result.Removed == 0 {
+		return result, nil
+	}
+
+	allScores.Scores = kept
+	allScores.Updated = now
+	if err := s.saveJSON(ctx, fmt.Sprintf("all_scores:%s", gameID), allScores); err != nil {
+		return nil, fmt.Errorf("failed to save pruned history: %w", err)
+	}
+	s.invalidateScoreIndex(ctx, gameID)
+	s.recountTimeseries(ctx, gameID, kept)
+
+	s.invalidateGame(ctx, gameID)
+	return result, nil
+}
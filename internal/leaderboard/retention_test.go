@@ -0,0 +1,137 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestRetentionPolicies(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("games without a policy keep all history", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_retention_none_" + generateTestID()
+		if err := service.SubmitScore(ctx, gameID, "AAA", 1000); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+
+		result, err := service.PruneHistory(ctx, gameID, time.Now().Add(365*24*time.Hour))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result != nil {
+			t.Errorf("Expected no pruning without a policy, got %+v", result)
+		}
+	})
+
+	t.Run("prunes raw history but keeps aggregates", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_retention_prune_" + generateTestID()
+		if err := service.SubmitScore(ctx, gameID, "AAA", 5000); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+		if err := service.SubmitScore(ctx, gameID, "BBB", 3000); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+
+		if _, err := service.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+			settings.Retention = &models.RetentionPolicy{HistoryDays: 30}
+			return nil
+		}); err != nil {
+			t.Fatalf("Failed to set retention policy: %v", err)
+		}
+
+		// When pruning runs long after the scores were submitted
+		result, err := service.PruneHistory(ctx, gameID, time.Now().Add(31*24*time.Hour))
+		if err != nil {
+			t.Fatalf("Failed to prune history: %v", err)
+		}
+		if result == nil || result.Removed != 2 || result.Remaining != 0 {
+			t.Fatalf("Expected 2 scores pruned, got %+v", result)
+		}
+
+		// Then the leaderboard is unaffected
+		board, err := service.GetLeaderboard(ctx, gameID)
+		if err != nil {
+			t.Fatalf("Failed to get leaderboard: %v", err)
+		}
+		if len(board.Entries) != 2 || board.Entries[0].Score != 5000 {
+			t.Errorf("Expected leaderboard to be kept, got %+v", board.Entries)
+		}
+	})
+
+	t.Run("keeps the newest scores beyond a score count", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_retention_count_" + generateTestID()
+		for _, initials := range []string{"AAA", "BBB", "CCC"} {
+			if err := service.SubmitScore(ctx, gameID, initials, 1000); err != nil {
+				t.Fatalf("Failed to submit score: %v", err)
+			}
+		}
+		if _, err := service.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+			settings.Retention = &models.RetentionPolicy{MaxScores: 2}
+			return nil
+		}); err != nil {
+			t.Fatalf("Failed to set retention policy: %v", err)
+		}
+
+		result, err := service.PruneHistory(ctx, gameID, time.Now())
+		if err != nil {
+			t.Fatalf("Failed to prune history: %v", err)
+		}
+		if result == nil || result.Removed != 1 || result.Remaining != 2 || result.Cutoff != nil {
+			t.Fatalf("Expected the oldest score pruned without a cutoff, got %+v", result)
+		}
+
+		history, err := service.getAllScores(ctx, gameID)
+		if err != nil {
+			t.Fatalf("Failed to get history: %v", err)
+		}
+		if len(history.Scores) != 2 || history.Scores[0].Initials != "BBB" {
+			t.Errorf("Expected BBB and CCC to be kept, got %+v", history.Scores)
+		}
+	})
+
+	t.Run("games without a policy use the default", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db, WithDefaultRetention(models.RetentionPolicy{HistoryDays: 30}))
+
+		gameID := "test_retention_default_" + generateTestID()
+		exemptID := "test_retention_exempt_" + generateTestID()
+		for _, id := range []string{gameID, exemptID} {
+			if err := service.SubmitScore(ctx, id, "AAA", 1000); err != nil {
+				t.Fatalf("Failed to submit score: %v", err)
+			}
+		}
+		// A policy without limits keeps everything despite the default
+		if _, err := service.UpdateGameSettings(ctx, exemptID, func(settings *models.GameSettings) error {
+			settings.Retention = &models.RetentionPolicy{}
+			return nil
+		}); err != nil {
+			t.Fatalf("Failed to set retention policy: %v", err)
+		}
+
+		later := time.Now().Add(31 * 24 * time.Hour)
+		result, err := service.PruneHistory(ctx, gameID, later)
+		if err != nil || result == nil || result.Removed != 1 {
+			t.Errorf("Expected the default policy to prune, got %+v (%v)", result, err)
+		}
+		result, err = service.PruneHistory(ctx, exemptID, later)
+		if err != nil || result != nil {
+			t.Errorf("Expected the game's own policy to keep everything, got %+v (%v)", result, err)
+		}
+	})
+}
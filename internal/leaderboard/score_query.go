@@ -0,0 +1,284 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+const (
+	// DefaultScoreQueryLimit is the page size of a score query that doesn't set one
+	DefaultScoreQueryLimit = 50
+	// MaxScoreQueryLimit is the largest page a score query can return
+	MaxScoreQueryLimit = 500
+
+	// scoreIndexBatch is how many index members a query reads per round trip
+	scoreIndexBatch = 500
+)
+
+// scoreIndexKey returns the sorted set indexing a game's history by score
+func scoreIndexKey(gameID string) string {
+	return fmt.Sprintf("score_index:%s", gameID)
+}
+
+// timeIndexKey returns the sorted set indexing a game's history by submission time
+func timeIndexKey(gameID string) string {
+	return fmt.Sprintf("time_index:%s", gameID)
+}
+
+// scoreIndexReadyKey marks a game's indexes as complete; it's removed whenever
+// history is rewritten so the next query rebuilds them
+func scoreIndexReadyKey(gameID string) string {
+	return fmt.Sprintf("score_index_ready:%s", gameID)
+}
+
+// QueryScores returns a page of a game's score history within a score range and
+// time window. Results are ordered by score (highest first) when a score bound is
+// given, and by time (newest first) otherwise. Databases with sorted sets answer
+// from indexes; others fall back to scanning the history.
+func (s *Service) QueryScores(ctx context.Context, gameID string, query models.ScoreQuery) (*models.ScoreQueryResponse, error) {
+	if query.Limit <= 0 || query.Limit > MaxScoreQueryLimit {
+		query.Limit = DefaultScoreQueryLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	order := models.ScoreQueryOrderTime
+	if query.MinScore != nil || query.MaxScore != nil {
+		order = models.ScoreQueryOrderScore
+	}
+
+	var page []models.ScoreEntry
+	var err error
+	if sets, ok := s.db.(database.SortedSets); ok {
+		page, err = s.queryScoreIndex(ctx, sets, gameID, query, order)
+	} else {
+		page, err = s.scanScores(ctx, gameID, query, order)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.ScoreQueryResponse{
+		GameID: gameID,
+		Order:  order,
+		Scores: page,
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+	// Pages are read one entry long to tell whether another follows
+	if len(page) > query.Limit {
+		response.Scores = page[:query.Limit]
+		response.HasMore = true
+	}
+	return response, nil
+}
+
+// queryScoreIndex reads up to Limit+1 matching entries from the index for order
+func (s *Service) queryScoreIndex(ctx context.Context, sets database.SortedSets, gameID string, query models.ScoreQuery, order string) ([]models.ScoreEntry, error) {
+	if err := s.ensureScoreIndex(ctx, sets, gameID); err != nil {
+		return nil, err
+	}
+
+	key := scoreIndexKey(gameID)
+	bounds := database.ZRange{Min: math.Inf(-1), Max: math.Inf(1), Reverse: true}
+	if query.MinScore != nil {
+		bounds.Min = float64(*query.MinScore)
+	}
+	if query.MaxScore != nil {
+		bounds.Max = float64(*query.MaxScore)
+	}
+	// Only the other dimension needs filtering, which rules out offsetting in the database
+	filtered := !query.From.IsZero() || !query.To.IsZero()
+
+	if order == models.ScoreQueryOrderTime {
+		key = timeIndexKey(gameID)
+		bounds = database.ZRange{Min: math.Inf(-1), Max: math.Inf(1), Reverse: true}
+		if !query.From.IsZero() {
+			bounds.Min = float64(query.From.UnixMilli())
+		}
+		if !query.To.IsZero() {
+			bounds.Max = float64(query.To.UnixMilli())
+		}
+		filtered = false
+	}
+
+	skip := query.Offset
+	if !filtered {
+		bounds.Offset = int64(query.Offset)
+		skip = 0
+	}
+
+	page := []models.ScoreEntry{}
+	for {
+		bounds.Count = scoreIndexBatch
+		members, err := sets.ZRangeByScore(ctx, key, bounds)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read score index: %w", err)
+		}
+
+		for _, member := range members {
+			var entry models.ScoreEntry
+			if err := json.Unmarshal([]byte(member), &entry); err != nil {
+				return nil, fmt.Errorf("failed to decode score index entry: %w", err)
+			}
+			// Index scores are rounded to milliseconds and float64, so check exactly
+			if !matchesScoreQuery(entry, query) {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			page = append(page, entry)
+			if len(page) > query.Limit {
+				return page, nil
+			}
+		}
+
+		if len(members) < scoreIndexBatch {
+			return page, nil
+		}
+		bounds.Offset += int64(len(members))
+	}
+}
+
+// scanScores answers a query from the full history, for databases without sorted sets
+func (s *Service) scanScores(ctx context.Context, gameID string, query models.ScoreQuery, order string) ([]models.ScoreEntry, error) {
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return []models.ScoreEntry{}, nil // No history yet
+	}
+
+	matches := []models.ScoreEntry{}
+	for _, entry := range allScores.Scores {
+		if matchesScoreQuery(entry, query) {
+			matches = append(matches, entry)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if order == models.ScoreQueryOrderScore && matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if !matches[i].Timestamp.Equal(matches[j].Timestamp) {
+			return matches[i].Timestamp.After(matches[j].Timestamp)
+		}
+		return sequencedAbove(matches[i], matches[j])
+	})
+
+	if query.Offset >= len(matches) {
+		return []models.ScoreEntry{}, nil
+	}
+	matches = matches[query.Offset:]
+	if len(matches) > query.Limit+1 {
+		matches = matches[:query.Limit+1]
+	}
+	return matches, nil
+}
+
+// matchesScoreQuery reports whether entry falls within the query's bounds
+func matchesScoreQuery(entry models.ScoreEntry, query models.ScoreQuery) bool {
+	if query.MinScore != nil && entry.Score < *query.MinScore {
+		return false
+	}
+	if query.MaxScore != nil && entry.Score > *query.MaxScore {
+		return false
+	}
+	if !query.From.IsZero() && entry.Timestamp.Before(query.From) {
+		return false
+	}
+	if !query.To.IsZero() && entry.Timestamp.After(query.To) {
+		return false
+	}
+	return true
+}
+
+// indexMembers returns the score and time index members for entries
+func indexMembers(entries []models.ScoreEntry) ([]database.ZMember, []database.ZMember, error) {
+	byScore := make([]database.ZMember, 0, len(entries))
+	byTime := make([]database.ZMember, 0, len(entries))
+	for _, entry := range entries {
+		member, err := json.Marshal(entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal score index entry: %w", err)
+		}
+		byScore = append(byScore, database.ZMember{Score: float64(entry.Score), Member: string(member)})
+		byTime = append(byTime, database.ZMember{Score: float64(entry.Timestamp.UnixMilli()), Member: string(member)})
+	}
+	return byScore, byTime, nil
+}
+
+// indexScore adds a new submission to the game's indexes. A failure is logged and the
+// indexes are marked for rebuilding rather than failing the submission.
+func (s *Service) indexScore(ctx context.Context, gameID string, entry models.ScoreEntry) {
+	sets, ok := s.db.(database.SortedSets)
+	if !ok {
+		return
+	}
+
+	byScore, byTime, err := indexMembers([]models.ScoreEntry{entry})
+	if err == nil {
+		err = sets.ZAdd(ctx, scoreIndexKey(gameID), byScore...)
+	}
+	if err == nil {
+		err = sets.ZAdd(ctx, timeIndexKey(gameID), byTime...)
+	}
+	if err != nil {
+		s.log(ctx).Warn("failed to index score, rebuilding on next query", "game_id", gameID, "error", err)
+		s.invalidateScoreIndex(ctx, gameID)
+	}
+}
+
+// invalidateScoreIndex marks a game's indexes for rebuilding after its history was rewritten
+func (s *Service) invalidateScoreIndex(ctx context.Context, gameID string) {
+	sets, ok := s.db.(database.SortedSets)
+	if !ok {
+		return
+	}
+	if err := sets.Del(ctx, scoreIndexReadyKey(gameID)); err != nil {
+		s.log(ctx).Error("failed to invalidate score index", "game_id", gameID, "error", err)
+	}
+}
+
+// ensureScoreIndex builds a game's indexes from its history unless they're complete
+func (s *Service) ensureScoreIndex(ctx context.Context, sets database.SortedSets, gameID string) error {
+	if _, err := s.db.Get(ctx, scoreIndexReadyKey(gameID)); err == nil {
+		return nil
+	}
+
+	for _, key := range []string{scoreIndexKey(gameID), timeIndexKey(gameID)} {
+		if err := sets.Del(ctx, key); err != nil {
+			return fmt.Errorf("failed to clear score index: %w", err)
+		}
+	}
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return nil // No history to index; new submissions index themselves
+	}
+	for start := 0; start < len(allScores.Scores); start += scoreIndexBatch {
+		end := min(start+scoreIndexBatch, len(allScores.Scores))
+		byScore, byTime, err := indexMembers(allScores.Scores[start:end])
+		if err != nil {
+			return err
+		}
+		if err := sets.ZAdd(ctx, scoreIndexKey(gameID), byScore...); err != nil {
+			return fmt.Errorf("failed to build score index: %w", err)
+		}
+		if err := sets.ZAdd(ctx, timeIndexKey(gameID), byTime...); err != nil {
+			return fmt.Errorf("failed to build score index: %w", err)
+		}
+	}
+
+	if err := s.db.Set(ctx, scoreIndexReadyKey(gameID), "1"); err != nil {
+		return fmt.Errorf("failed to mark score index ready: %w", err)
+	}
+	s.log(ctx).Info("built score index", "game_id", gameID, "entries", len(allScores.Scores))
+	return nil
+}
@@ -0,0 +1,162 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+// plainDB hides a database's optional capabilities, like a store without sorted sets
+type plainDB struct {
+	database.DB
+}
+
+// seedHistory restores a history of one score per hour, oldest first, ending at base
+func seedHistory(t *testing.T, service *Service, gameID string, base time.Time, scores []int64) {
+	t.Helper()
+	history := &models.AllScoresRecord{GameID: gameID}
+	highScores := &models.PlayerHighScores{GameID: gameID, HighScores: map[string]models.ScoreEntry{}}
+	for i, score := range scores {
+		entry := models.ScoreEntry{
+			Initials:  string(rune('A'+i)) + "AA",
+			Score:     score,
+			Timestamp: base.Add(time.Duration(i-len(scores)+1) * time.Hour),
+		}
+		history.Scores = append(history.Scores, entry)
+		highScores.HighScores[entry.Initials] = entry
+	}
+	if err := service.RestoreGame(context.Background(), history, highScores); err != nil {
+		t.Fatalf("Failed to seed history: %v", err)
+	}
+}
+
+// entryScores returns the scores of entries in order
+func entryScores(entries []models.ScoreEntry) []int64 {
+	scores := make([]int64, len(entries))
+	for i, entry := range entries {
+		scores[i] = entry.Score
+	}
+	return scores
+}
+
+func equalScores(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQueryScores(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 7, 16, 12, 0, 0, 0, time.UTC)
+	history := []int64{500, 12000, 30000, 8000, 45000, 60000, 20000} // 06:00 to 12:00
+	int64p := func(v int64) *int64 { return &v }
+
+	for name, newDB := range map[string]func() database.DB{
+		"indexed":  func() database.DB { return database.NewFake() },
+		"scanning": func() database.DB { return plainDB{database.NewFake()} },
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Run("filters by score range, highest first, with pages", func(t *testing.T) {
+				service := NewService(newDB())
+				seedHistory(t, service, "pacman", base, history)
+
+				query := models.ScoreQuery{MinScore: int64p(10000), MaxScore: int64p(50000), Limit: 2}
+				first, err := service.QueryScores(ctx, "pacman", query)
+				if err != nil {
+					t.Fatalf("QueryScores failed: %v", err)
+				}
+				if first.Order != models.ScoreQueryOrderScore || !equalScores(entryScores(first.Scores), []int64{45000, 30000}) || !first.HasMore {
+					t.Errorf("Unexpected first page: %+v", first)
+				}
+
+				query.Offset = 2
+				second, _ := service.QueryScores(ctx, "pacman", query)
+				if !equalScores(entryScores(second.Scores), []int64{20000, 12000}) || second.HasMore {
+					t.Errorf("Unexpected second page: %+v", second)
+				}
+			})
+
+			t.Run("filters by time window, newest first", func(t *testing.T) {
+				service := NewService(newDB())
+				seedHistory(t, service, "pacman", base, history)
+
+				response, err := service.QueryScores(ctx, "pacman", models.ScoreQuery{From: base.Add(-3 * time.Hour), To: base.Add(-time.Hour)})
+				if err != nil {
+					t.Fatalf("QueryScores failed: %v", err)
+				}
+				if response.Order != models.ScoreQueryOrderTime || !equalScores(entryScores(response.Scores), []int64{60000, 45000, 8000}) {
+					t.Errorf("Unexpected results: %+v", response)
+				}
+			})
+
+			t.Run("combines score and time bounds across pages", func(t *testing.T) {
+				service := NewService(newDB())
+				seedHistory(t, service, "pacman", base, history)
+
+				query := models.ScoreQuery{MinScore: int64p(1000), From: base.Add(-4 * time.Hour), Limit: 1, Offset: 1}
+				response, _ := service.QueryScores(ctx, "pacman", query)
+				if !equalScores(entryScores(response.Scores), []int64{45000}) || !response.HasMore {
+					t.Errorf("Unexpected results: %+v", response)
+				}
+			})
+
+			t.Run("returns an empty page for unknown games", func(t *testing.T) {
+				service := NewService(newDB())
+				response, err := service.QueryScores(ctx, "unknown", models.ScoreQuery{})
+				if err != nil || len(response.Scores) != 0 || response.HasMore {
+					t.Errorf("Expected an empty page, got %+v (%v)", response, err)
+				}
+			})
+		})
+	}
+
+	t.Run("keeps the index in step with submissions and moderation", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		seedHistory(t, service, "pacman", base, history)
+
+		if _, err := service.QueryScores(ctx, "pacman", models.ScoreQuery{}); err != nil {
+			t.Fatalf("QueryScores failed: %v", err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "NEW", 99999); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		response, _ := service.QueryScores(ctx, "pacman", models.ScoreQuery{MinScore: int64p(50000)})
+		if !equalScores(entryScores(response.Scores), []int64{99999, 60000}) {
+			t.Errorf("Expected the new submission to be indexed, got %v", entryScores(response.Scores))
+		}
+
+		if _, err := service.DeletePlayer(ctx, "pacman", "NEW"); err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		response, _ = service.QueryScores(ctx, "pacman", models.ScoreQuery{MinScore: int64p(50000)})
+		if !equalScores(entryScores(response.Scores), []int64{60000}) {
+			t.Errorf("Expected the deleted player to be gone, got %v", entryScores(response.Scores))
+		}
+	})
+
+	t.Run("rebuilds the index after a failed index write", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		seedHistory(t, service, "pacman", base, history)
+		service.QueryScores(ctx, "pacman", models.ScoreQuery{})
+
+		db.FailNext(database.OpZAdd, context.DeadlineExceeded)
+		if err := service.SubmitScore(ctx, "pacman", "NEW", 99999); err != nil {
+			t.Fatalf("Expected the submission to succeed without its index, got %v", err)
+		}
+		response, _ := service.QueryScores(ctx, "pacman", models.ScoreQuery{MinScore: int64p(99999)})
+		if len(response.Scores) != 1 {
+			t.Errorf("Expected the rebuilt index to include the submission, got %+v", response.Scores)
+		}
+	})
+}
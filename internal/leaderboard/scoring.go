@@ -0,0 +1,106 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+
+	"rawboard/internal/models"
+)
+
+// ErrScoringLocked is returned when changing the decimals of a game that already has
+// scores, whose stored values would change meaning
+var ErrScoringLocked = errors.New("decimals can't change once a game has scores")
+
+// scoring returns a game's scoring settings, nil for whole, non-negative scores
+func (s *Service) scoring(ctx context.Context, gameID string) *models.ScoringSettings {
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil {
+		return nil
+	}
+	return game.Settings.Scoring
+}
+
+// ParseScore converts a submitted score, as written in the request, to the value stored
+// for the game: 12.5 is stored as 1250 in a game with 2 decimals
+func (s *Service) ParseScore(ctx context.Context, gameID, value string) (int64, error) {
+	return s.scoring(ctx, gameID).Parse(value)
+}
+
+// SetScoring replaces a game's scoring settings, or removes them when they allow
+// nothing beyond whole, non-negative scores. The decimals are locked once the game
+// has scores; allowing or refusing negative scores only affects new submissions.
+func (s *Service) SetScoring(ctx context.Context, gameID string, scoring models.ScoringSettings) (*models.GameInfo, error) {
+	if err := scoring.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		if settings.Scoring.Precision() != scoring.Decimals {
+			if history, err := s.getAllScores(ctx, gameID); err == nil && len(history.Scores) > 0 {
+				return ErrScoringLocked
+			}
+		}
+
+		settings.Scoring = nil
+		if scoring.Enabled() {
+			settings.Scoring = &scoring
+		}
+		return nil
+	})
+}
+
+// withDisplayScores sets the display score of entries in a game that keeps decimals
+func withDisplayScores(scoring *models.ScoringSettings, entries []models.ScoreEntry) {
+	if scoring.Precision() == 0 {
+		return
+	}
+	for i := range entries {
+		entries[i].DisplayScore = scoring.Format(entries[i].Score)
+	}
+}
+
+// scoreRanges are the score distribution's ranges of whole points, each from its bound
+// up to the next; scores below zero are counted together
+var scoreRanges = []struct {
+	from  int64
+	label string
+}{
+	{0, "0-999"},
+	{1000, "1K-5K"},
+	{5000, "5K-10K"},
+	{10000, "10K-25K"},
+	{25000, "25K-50K"},
+	{50000, "50K+"},
+}
+
+// scoreDistribution counts scores per range of whole points, reading stored values with
+// the game's decimals
+func scoreDistribution(scoring *models.ScoringSettings, scores []models.ScoreEntry) map[string]int {
+	distribution := make(map[string]int)
+	unit := scoring.Unit()
+	for _, score := range scores {
+		if score.Score < 0 {
+			distribution["Below 0"]++
+			continue
+		}
+		label := scoreRanges[0].label
+		for _, r := range scoreRanges[1:] {
+			if score.Score < r.from*unit {
+				break
+			}
+			label = r.label
+		}
+		distribution[label]++
+	}
+	return distribution
+}
+
+// withDisplayStats sets the display scores of a player's stats in a game that keeps
+// decimals
+func withDisplayStats(scoring *models.ScoringSettings, stats *models.EnhancedPlayerStats) {
+	if scoring.Precision() == 0 {
+		return
+	}
+	stats.DisplayHighScore = scoring.Format(stats.HighScore)
+	stats.DisplayAverageScore = scoring.FormatMean(stats.AverageScore)
+}
@@ -0,0 +1,205 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestScoringSettings(t *testing.T) {
+	golf := &models.ScoringSettings{AllowNegative: true}
+	times := &models.ScoringSettings{Decimals: 2}
+	deltas := &models.ScoringSettings{Decimals: 3, AllowNegative: true}
+
+	for _, tc := range []struct {
+		scoring *models.ScoringSettings
+		value   string
+		want    int64
+	}{
+		{nil, "12500", 12500},
+		{nil, "0", 0},
+		{golf, "-4", -4},
+		{times, "12.5", 1250},
+		{times, "12.50", 1250},
+		{times, "0.07", 7},
+		{times, "42", 4200},
+		{deltas, "-0.125", -125},
+		{deltas, "999999999", 999999999000},
+	} {
+		got, err := tc.scoring.Parse(tc.value)
+		if err != nil || got != tc.want {
+			t.Errorf("Parse(%q) with %+v = %d, %v; want %d", tc.value, tc.scoring, got, err, tc.want)
+		}
+	}
+
+	for _, tc := range []struct {
+		scoring *models.ScoringSettings
+		value   string
+	}{
+		{nil, "-1"},
+		{nil, "1.5"},
+		{times, "-1"},
+		{times, "1.234"},
+		{times, "1e3"},
+		{times, "abc"},
+		{golf, "1000000000"},
+		{golf, "-1000000000"},
+		{deltas, "99999999999999999999"},
+	} {
+		if _, err := tc.scoring.Parse(tc.value); !errors.Is(err, models.ErrInvalidScore) {
+			t.Errorf("Expected Parse(%q) with %+v to fail with ErrInvalidScore, got %v", tc.value, tc.scoring, err)
+		}
+	}
+
+	for stored, want := range map[int64]string{1250: "12.50", 7: "0.07", -5: "-0.05", 0: "0.00"} {
+		if got := times.Format(stored); got != want {
+			t.Errorf("Format(%d) = %q, want %q", stored, got, want)
+		}
+	}
+	if got := deltas.Format(-125); got != "-0.125" {
+		t.Errorf("Format(-125) = %q, want -0.125", got)
+	}
+	if got := times.FormatMean(1237.5); got != "12.38" {
+		t.Errorf("FormatMean(1237.5) = %q, want 12.38", got)
+	}
+}
+
+func TestScoringModes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("ranks negative scores below positive ones", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetScoring(ctx, "golf", models.ScoringSettings{AllowNegative: true}); err != nil {
+			t.Fatalf("SetScoring failed: %v", err)
+		}
+
+		for initials, score := range map[string]int64{"AAA": -4, "BBB": 2, "CCC": -7} {
+			if err := service.SubmitScore(ctx, "golf", initials, score); err != nil {
+				t.Fatalf("Expected a negative score to be accepted, got %v", err)
+			}
+		}
+		if err := service.SubmitScore(ctx, "golf", "CCC", -9); err != nil {
+			t.Fatal(err)
+		}
+
+		board, _ := service.GetLeaderboard(ctx, "golf")
+		if len(board.Entries) != 3 || board.Entries[0].Initials != "BBB" || board.Entries[2].Score != -7 {
+			t.Errorf("Expected BBB, AAA, CCC with CCC's best of -7, got %+v", board.Entries)
+		}
+		if stats, _ := service.GetPlayerStats(ctx, "golf", "CCC"); stats.HighScore != -7 {
+			t.Errorf("Expected an all-negative player's high score to be -7, got %d", stats.HighScore)
+		}
+
+		if err := service.SubmitScore(ctx, "pacman", "AAA", -1); !errors.Is(err, models.ErrInvalidScore) {
+			t.Errorf("Expected negative scores to be refused by default, got %v", err)
+		}
+	})
+
+	t.Run("stores decimals as fixed-point and formats them", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetScoring(ctx, "speedrun", models.ScoringSettings{Decimals: 2}); err != nil {
+			t.Fatalf("SetScoring failed: %v", err)
+		}
+
+		score, err := service.ParseScore(ctx, "speedrun", "12.5")
+		if err != nil || score != 1250 {
+			t.Fatalf("Expected 12.5 to be stored as 1250, got %d (%v)", score, err)
+		}
+		result, err := service.Submit(ctx, "speedrun", models.Submission{Initials: "AAA", Score: score})
+		if err != nil || result.Entry.DisplayScore != "12.50" {
+			t.Fatalf("Expected the entry to display 12.50, got %+v (%v)", result, err)
+		}
+		service.SubmitScore(ctx, "speedrun", "BBB", 1249)
+
+		board, _ := service.GetLeaderboard(ctx, "speedrun")
+		if len(board.Entries) != 2 || board.Entries[0].DisplayScore != "12.50" || board.Entries[1].DisplayScore != "12.49" {
+			t.Errorf("Expected display scores on the board, got %+v", board.Entries)
+		}
+		if _, err := service.ParseScore(ctx, "speedrun", "1.234"); !errors.Is(err, models.ErrInvalidScore) {
+			t.Errorf("Expected extra decimals to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("locks decimals once a game has scores", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 100)
+
+		if _, err := service.SetScoring(ctx, "pacman", models.ScoringSettings{Decimals: 1}); !errors.Is(err, ErrScoringLocked) {
+			t.Errorf("Expected ErrScoringLocked, got %v", err)
+		}
+		game, err := service.SetScoring(ctx, "pacman", models.ScoringSettings{AllowNegative: true})
+		if err != nil || !game.Settings.Scoring.AllowNegative {
+			t.Errorf("Expected negatives to be allowed on a game with scores, got %+v (%v)", game, err)
+		}
+		if _, err := service.SetScoring(ctx, "pacman", models.ScoringSettings{Decimals: 7}); err == nil {
+			t.Error("Expected more than 6 decimals to be rejected")
+		}
+
+		game, err = service.SetScoring(ctx, "pacman", models.ScoringSettings{})
+		if err != nil || game.Settings.Scoring != nil {
+			t.Errorf("Expected empty settings to clear the scoring mode, got %+v (%v)", game, err)
+		}
+	})
+
+	t.Run("analyzes negative scores", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SetScoring(ctx, "golf", models.ScoringSettings{AllowNegative: true})
+		for initials, score := range map[string]int64{"AAA": -4, "BBB": -8, "CCC": 1500} {
+			service.SubmitScore(ctx, "golf", initials, score)
+		}
+
+		analysis, err := service.GetScoreAnalysis(ctx, "golf", 5)
+		if err != nil {
+			t.Fatalf("GetScoreAnalysis failed: %v", err)
+		}
+		if analysis.ScoreDistribution["Below 0"] != 2 || analysis.ScoreDistribution["1K-5K"] != 1 {
+			t.Errorf("Expected negative scores counted in the distribution, got %v", analysis.ScoreDistribution)
+		}
+		if analysis.DisplayAverageScore != "" {
+			t.Errorf("Expected no display average for whole scores, got %q", analysis.DisplayAverageScore)
+		}
+
+		service = NewService(database.NewFake())
+		service.SetScoring(ctx, "golf", models.ScoringSettings{AllowNegative: true})
+		service.SubmitScore(ctx, "golf", "AAA", -4)
+		service.SubmitScore(ctx, "golf", "BBB", -8)
+		if analysis, _ := service.GetScoreAnalysis(ctx, "golf", 5); analysis.HighestScore != -4 {
+			t.Errorf("Expected the highest of all-negative scores to be -4, got %d", analysis.HighestScore)
+		}
+	})
+
+	t.Run("analyzes decimal scores in whole points", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SetScoring(ctx, "speedrun", models.ScoringSettings{Decimals: 2})
+		service.SubmitScore(ctx, "speedrun", "AAA", 1250)   // 12.50
+		service.SubmitScore(ctx, "speedrun", "AAA", 1276)   // 12.76
+		service.SubmitScore(ctx, "speedrun", "BBB", 150000) // 1500.00
+
+		analysis, err := service.GetScoreAnalysis(ctx, "speedrun", 5)
+		if err != nil {
+			t.Fatalf("GetScoreAnalysis failed: %v", err)
+		}
+		if analysis.ScoreDistribution["0-999"] != 2 || analysis.ScoreDistribution["1K-5K"] != 1 {
+			t.Errorf("Expected decimal scores bucketed by whole points, got %v", analysis.ScoreDistribution)
+		}
+		if analysis.DisplayHighestScore != "1500.00" || analysis.DisplayAverageScore != "508.42" {
+			t.Errorf("Expected display highest 1500.00 and average 508.42, got %q and %q", analysis.DisplayHighestScore, analysis.DisplayAverageScore)
+		}
+		if len(analysis.TopPlayers) != 2 || analysis.TopPlayers[1].DisplayAverageScore != "12.63" {
+			t.Errorf("Expected AAA's display average of 12.63, got %+v", analysis.TopPlayers)
+		}
+
+		stats, _ := service.GetPlayerStats(ctx, "speedrun", "AAA")
+		if stats.DisplayHighScore != "12.76" || stats.DisplayAverageScore != "12.63" {
+			t.Errorf("Expected player stats to display 12.76 and 12.63, got %q and %q", stats.DisplayHighScore, stats.DisplayAverageScore)
+		}
+		series, _ := service.ScoreTimeseries(ctx, "speedrun", models.TimeseriesDay, 1, time.Now())
+		if last := series.Buckets[len(series.Buckets)-1]; last.DisplayAverageScore != "508.42" {
+			t.Errorf("Expected today's display average of 508.42, got %+v", last)
+		}
+	})
+}
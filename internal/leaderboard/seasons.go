@@ -0,0 +1,283 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+// Season errors
+var (
+	ErrSeasonExists    = errors.New("season already exists")
+	ErrSeasonNotFound  = errors.New("season not found")
+	ErrSeasonNotActive = errors.New("season has already ended")
+	ErrInvalidSeason   = errors.New("invalid season")
+)
+
+// seasonsKey stores a game's models.GameSeasons
+func seasonsKey(gameID string) string {
+	return fmt.Sprintf("seasons:%s", gameID)
+}
+
+// seasonLeaderboardKey stores an ended season's final board as a models.Ranking
+func seasonLeaderboardKey(gameID, seasonID string) string {
+	return fmt.Sprintf("season_leaderboard:%s:%s", gameID, seasonID)
+}
+
+// StartSeason starts a season of gameID, ending any active one first, and resets the
+// live board so it only ranks scores from the new season. seasonID defaults to
+// "season-N"; a season without endsAt runs until it is ended or replaced. Score history
+// is kept whole across seasons.
+func (s *Service) StartSeason(ctx context.Context, gameID, seasonID, name string, endsAt *time.Time) (*models.Season, error) {
+	now := time.Now()
+	if endsAt != nil && !endsAt.After(now) {
+		return nil, fmt.Errorf("%w: ends_at must be in the future", ErrInvalidSeason)
+	}
+
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("failed to register game: %w", err)
+	}
+
+	seasons, err := s.getSeasons(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	seasonID = strings.TrimSpace(seasonID)
+	if seasonID == "" {
+		seasonID = fmt.Sprintf("season-%d", len(seasons.Seasons)+1)
+	}
+	if seasons.Find(seasonID) != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSeasonExists, seasonID)
+	}
+
+	if active := seasons.Active(); active != nil {
+		if err := s.archiveSeason(ctx, gameID, active, now); err != nil {
+			return nil, err
+		}
+	}
+
+	seasons.Seasons = append(seasons.Seasons, models.Season{
+		SeasonID:  seasonID,
+		GameID:    gameID,
+		Name:      strings.TrimSpace(name),
+		Status:    models.SeasonActive,
+		StartedAt: now,
+		EndsAt:    endsAt,
+	})
+	seasons.BoardSince = &now
+	if err := s.saveJSON(ctx, seasonsKey(gameID), seasons); err != nil {
+		return nil, fmt.Errorf("failed to save seasons: %w", err)
+	}
+	if err := s.resetBoard(ctx, gameID, models.ResetSeasonStarted, seasonID); err != nil {
+		return nil, err
+	}
+
+	s.log(ctx).Info("season started", "game_id", gameID, "season_id", seasonID)
+	season := seasons.Seasons[len(seasons.Seasons)-1]
+	return &season, nil
+}
+
+// EndSeason ends gameID's active season now, archiving its board and resetting the live
+// board for the scores played until the next season starts
+func (s *Service) EndSeason(ctx context.Context, gameID, seasonID string) (*models.Season, error) {
+	seasons, err := s.getSeasons(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	season := seasons.Find(seasonID)
+	if season == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSeasonNotFound, seasonID)
+	}
+	if season.Status != models.SeasonActive {
+		return nil, fmt.Errorf("%w: %s", ErrSeasonNotActive, seasonID)
+	}
+
+	if err := s.endSeason(ctx, seasons, season, time.Now()); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// EndDueSeasons ends every active season whose end time has passed, returning how many
+// were ended. Submissions end a game's due season themselves, so this only matters for
+// games nobody is playing.
+func (s *Service) EndDueSeasons(ctx context.Context, now time.Time) (int, error) {
+	gameIDs, err := s.ListGames(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ended := 0
+	var errs []error
+	for _, gameID := range gameIDs {
+		done, err := s.endDueSeason(ctx, gameID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", gameID, err))
+			continue
+		}
+		if done {
+			ended++
+		}
+	}
+	return ended, errors.Join(errs...)
+}
+
+// endDueSeason ends gameID's active season if its end time has passed. The season is
+// recorded as ending at its scheduled time, however late this runs.
+func (s *Service) endDueSeason(ctx context.Context, gameID string, now time.Time) (bool, error) {
+	seasons, err := s.getSeasons(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+	season := seasons.Active()
+	if season == nil || season.EndsAt == nil || season.EndsAt.After(now) {
+		return false, nil
+	}
+	return true, s.endSeason(ctx, seasons, season, *season.EndsAt)
+}
+
+// endSeason archives season, which must belong to seasons, and resets the live board
+func (s *Service) endSeason(ctx context.Context, seasons *models.GameSeasons, season *models.Season, endedAt time.Time) error {
+	gameID := seasons.GameID
+	if err := s.archiveSeason(ctx, gameID, season, endedAt); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	seasons.BoardSince = &now
+	if err := s.saveJSON(ctx, seasonsKey(gameID), seasons); err != nil {
+		return fmt.Errorf("failed to save seasons: %w", err)
+	}
+	return s.resetBoard(ctx, gameID, models.ResetSeasonEnded, season.SeasonID)
+}
+
+// archiveSeason stores the live board as season's final board and marks it ended. The
+// caller saves the seasons record.
+func (s *Service) archiveSeason(ctx context.Context, gameID string, season *models.Season, endedAt time.Time) error {
+	entries := []models.ScoreEntry{}
+	if highScores, err := s.getPlayerHighScores(ctx, gameID); err == nil {
+		entries = rankHighScores(highScores)
+		withDisplayScores(s.scoring(ctx, gameID), entries)
+	}
+
+	archive := &models.Ranking{GameID: gameID, Entries: entries, Updated: endedAt}
+	if err := s.saveJSON(ctx, seasonLeaderboardKey(gameID, season.SeasonID), archive); err != nil {
+		return fmt.Errorf("failed to archive season %s: %w", season.SeasonID, err)
+	}
+
+	season.Status = models.SeasonEnded
+	season.EndedAt = &endedAt
+	season.Players = len(entries)
+	season.Champion = nil
+	if len(entries) > 0 {
+		champion := entries[0]
+		season.Champion = &champion
+	}
+
+	s.log(ctx).Info("season ended", "game_id", gameID, "season_id", season.SeasonID, "players", season.Players)
+	return nil
+}
+
+// resetBoard clears the player high scores behind the live board, rebuilds it and tells
+// reset listeners why
+func (s *Service) resetBoard(ctx context.Context, gameID, reason, seasonID string) error {
+	empty := &models.PlayerHighScores{GameID: gameID, HighScores: make(map[string]models.ScoreEntry), Updated: time.Now()}
+	if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), empty); err != nil {
+		return fmt.Errorf("failed to reset player high scores: %w", err)
+	}
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return fmt.Errorf("failed to rebuild leaderboard: %w", err)
+	}
+	s.invalidateGame(ctx, gameID)
+	s.notifyReset(ctx, gameID, reason, seasonID)
+	return nil
+}
+
+// notifyReset tells reset listeners a game's live board was emptied, and why
+func (s *Service) notifyReset(ctx context.Context, gameID, reason, seasonID string) {
+	event := models.ResetEvent{GameID: gameID, Tenant: tenants.FromContext(ctx), Reason: reason, SeasonID: seasonID}
+	for _, listener := range s.resetListeners {
+		listener.BoardReset(event)
+	}
+}
+
+// Seasons returns gameID's seasons, oldest first
+func (s *Service) Seasons(ctx context.Context, gameID string) (*models.GameSeasons, error) {
+	return s.getSeasons(ctx, gameID)
+}
+
+// SeasonLeaderboard returns a season's board: the live ranking while it's active, or its
+// archived final board. limit caps the entries returned; 0 returns them all.
+func (s *Service) SeasonLeaderboard(ctx context.Context, gameID, seasonID string, limit int) (*models.SeasonLeaderboard, error) {
+	seasons, err := s.getSeasons(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	season := seasons.Find(seasonID)
+	if season == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSeasonNotFound, seasonID)
+	}
+
+	var entries []models.ScoreEntry
+	if season.Status == models.SeasonActive {
+		if ranking, err := s.getRanking(ctx, gameID); err == nil {
+			entries = ranking.Entries
+		}
+	} else {
+		data, err := s.db.Get(ctx, seasonLeaderboardKey(gameID, seasonID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load season %s: %w", seasonID, err)
+		}
+		var archive models.Ranking
+		if err := json.NewDecoder(strings.NewReader(data)).Decode(&archive); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal season %s: %w", seasonID, err)
+		}
+		entries = archive.Entries
+	}
+
+	board := &models.SeasonLeaderboard{Season: *season, Entries: entries, Total: len(entries)}
+	if board.Entries == nil {
+		board.Entries = []models.ScoreEntry{}
+	}
+	if limit > 0 && len(board.Entries) > limit {
+		board.Entries = board.Entries[:limit]
+	}
+	return board, nil
+}
+
+// getSeasons loads gameID's seasons record, empty for a game that never had a season
+func (s *Service) getSeasons(ctx context.Context, gameID string) (*models.GameSeasons, error) {
+	data, err := s.db.Get(ctx, seasonsKey(gameID))
+	if err != nil {
+		return &models.GameSeasons{GameID: gameID, Seasons: []models.Season{}}, nil
+	}
+
+	var seasons models.GameSeasons
+	if err := json.NewDecoder(strings.NewReader(data)).Decode(&seasons); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal seasons: %w", err)
+	}
+	return &seasons, nil
+}
+
+// boardScores returns the scores of history that belong on the live board: those played
+// since a season last started or ended
+func (s *Service) boardScores(ctx context.Context, gameID string, scores []models.ScoreEntry) []models.ScoreEntry {
+	seasons, err := s.getSeasons(ctx, gameID)
+	if err != nil || seasons.BoardSince == nil {
+		return scores
+	}
+
+	kept := make([]models.ScoreEntry, 0, len(scores))
+	for _, entry := range scores {
+		if !entry.Timestamp.Before(*seasons.BoardSince) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,169 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestSeasons(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("starting a season resets the live board", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_season_start_" + generateTestID()
+		if err := service.SubmitScore(ctx, gameID, "AAA", 5000); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+
+		season, err := service.StartSeason(ctx, gameID, "", "Opening", nil)
+		if err != nil {
+			t.Fatalf("Failed to start season: %v", err)
+		}
+		if season.SeasonID != "season-1" || season.Status != models.SeasonActive {
+			t.Fatalf("Expected active season-1, got %+v", season)
+		}
+
+		if err := service.SubmitScore(ctx, gameID, "BBB", 1000); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+		board, err := service.GetLeaderboard(ctx, gameID)
+		if err != nil {
+			t.Fatalf("Failed to get leaderboard: %v", err)
+		}
+		if len(board.Entries) != 1 || board.Entries[0].Initials != "BBB" {
+			t.Errorf("Expected only the season's score on the board, got %+v", board.Entries)
+		}
+
+		history, err := service.GetAllScoresForGame(ctx, gameID)
+		if err != nil || len(history.Scores) != 2 {
+			t.Errorf("Expected history kept whole across seasons, got %+v (%v)", history, err)
+		}
+
+		if _, err := service.StartSeason(ctx, gameID, "season-1", "", nil); !errors.Is(err, ErrSeasonExists) {
+			t.Errorf("Expected ErrSeasonExists, got %v", err)
+		}
+		past := time.Now().Add(-time.Hour)
+		if _, err := service.StartSeason(ctx, gameID, "late", "", &past); !errors.Is(err, ErrInvalidSeason) {
+			t.Errorf("Expected ErrInvalidSeason for a past end time, got %v", err)
+		}
+	})
+
+	t.Run("ending a season archives its board", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_season_end_" + generateTestID()
+		if _, err := service.StartSeason(ctx, gameID, "spring", "Spring", nil); err != nil {
+			t.Fatalf("Failed to start season: %v", err)
+		}
+		for initials, score := range map[string]int64{"AAA": 3000, "BBB": 7000} {
+			if err := service.SubmitScore(ctx, gameID, initials, score); err != nil {
+				t.Fatalf("Failed to submit score: %v", err)
+			}
+		}
+
+		live, err := service.SeasonLeaderboard(ctx, gameID, "spring", 0)
+		if err != nil || live.Total != 2 {
+			t.Fatalf("Expected the active season to serve the live board, got %+v (%v)", live, err)
+		}
+
+		season, err := service.EndSeason(ctx, gameID, "spring")
+		if err != nil {
+			t.Fatalf("Failed to end season: %v", err)
+		}
+		if season.Status != models.SeasonEnded || season.Players != 2 || season.Champion == nil || season.Champion.Initials != "BBB" {
+			t.Errorf("Expected an ended season won by BBB, got %+v", season)
+		}
+		if _, err := service.EndSeason(ctx, gameID, "spring"); !errors.Is(err, ErrSeasonNotActive) {
+			t.Errorf("Expected ErrSeasonNotActive, got %v", err)
+		}
+
+		if err := service.SubmitScore(ctx, gameID, "CCC", 100); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+		archived, err := service.SeasonLeaderboard(ctx, gameID, "spring", 1)
+		if err != nil {
+			t.Fatalf("Failed to get season leaderboard: %v", err)
+		}
+		if archived.Total != 2 || len(archived.Entries) != 1 || archived.Entries[0].Initials != "BBB" {
+			t.Errorf("Expected the archived board limited to BBB, got %+v", archived)
+		}
+
+		board, err := service.GetLeaderboard(ctx, gameID)
+		if err != nil || len(board.Entries) != 1 || board.Entries[0].Initials != "CCC" {
+			t.Errorf("Expected only scores after the season on the live board, got %+v (%v)", board, err)
+		}
+
+		if _, err := service.SeasonLeaderboard(ctx, gameID, "winter", 0); !errors.Is(err, ErrSeasonNotFound) {
+			t.Errorf("Expected ErrSeasonNotFound, got %v", err)
+		}
+	})
+
+	t.Run("seasons past their end time are archived", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_season_due_" + generateTestID()
+		endsAt := time.Now().Add(time.Hour)
+		if _, err := service.StartSeason(ctx, gameID, "weekly", "", &endsAt); err != nil {
+			t.Fatalf("Failed to start season: %v", err)
+		}
+		if err := service.SubmitScore(ctx, gameID, "AAA", 4200); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+
+		ended, err := service.EndDueSeasons(ctx, time.Now())
+		if err != nil || ended != 0 {
+			t.Fatalf("Expected no seasons due yet, got %d (%v)", ended, err)
+		}
+		ended, err = service.EndDueSeasons(ctx, endsAt.Add(time.Minute))
+		if err != nil || ended != 1 {
+			t.Fatalf("Expected the season to end, got %d (%v)", ended, err)
+		}
+
+		seasons, err := service.Seasons(ctx, gameID)
+		if err != nil {
+			t.Fatalf("Failed to list seasons: %v", err)
+		}
+		if seasons.Active() != nil || len(seasons.Seasons) != 1 {
+			t.Fatalf("Expected one ended season, got %+v", seasons)
+		}
+		if season := seasons.Seasons[0]; season.EndedAt == nil || !season.EndedAt.Equal(endsAt) || season.Players != 1 {
+			t.Errorf("Expected the season ended at its scheduled time with one player, got %+v", season)
+		}
+	})
+
+	t.Run("recompute only counts the current season", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_season_recompute_" + generateTestID()
+		if err := service.SubmitScore(ctx, gameID, "AAA", 9000); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+		if _, err := service.StartSeason(ctx, gameID, "", "", nil); err != nil {
+			t.Fatalf("Failed to start season: %v", err)
+		}
+		if err := service.SubmitScore(ctx, gameID, "AAA", 100); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+
+		result, err := service.RecomputePlayer(ctx, gameID, "AAA")
+		if err != nil {
+			t.Fatalf("Failed to recompute player: %v", err)
+		}
+		if result.HighScore.Score != 100 {
+			t.Errorf("Expected the season's best of 100, got %d", result.HighScore.Score)
+		}
+	})
+}
@@ -3,46 +3,303 @@ package leaderboard
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"rawboard/internal/apikeys"
 	"rawboard/internal/database"
+	"rawboard/internal/logging"
 	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
 )
 
+// ErrNoScoreHistory is returned for games with no score history stored
+var ErrNoScoreHistory = errors.New("no score history found for game")
+
 // Service handles leaderboard operations
 type Service struct {
-	db database.DB
+	db             database.DB
+	analytics      *analyticsCache
+	boards         *boardCache
+	logger         *slog.Logger
+	maxEntries     int
+	gameLimit      int                    // Games each API key may create, 0 is unlimited
+	retention      models.RetentionPolicy // Applies to games without their own policy
+	publishers     []Publisher
+	listeners      []ScoreListener
+	resetListeners []ResetListener
+	hooks          []SubmissionHook
+	gameKeys       []func(gameID string) []string // Keys other packages keep per game, for DeleteGame
+	blocked        map[string]bool                // The deployment's configured blocked initials
+	undoWindow     time.Duration                  // How long moderation deletions can be undone, 0 for not at all
+	instanceID     string                         // Identifies this replica in cache invalidations
+	profileMu      sync.Mutex
+	// Guards the read-modify-write of achievement definitions and unlocks
+	achievementMu sync.Mutex
+	activityMu    sync.Mutex // Guards the read-modify-write of player activity tallies
+	timeseriesMu  sync.Mutex // Guards the read-modify-write of score time series counters
+	snapshotMu    sync.Mutex // Guards the read-modify-write of leaderboard snapshots
+	signingMu     sync.Mutex // Guards the read-modify-write of spent signature nonces
+	tombstoneMu   sync.Mutex // Guards the read-modify-write of moderation tombstones
+	disputeMu     sync.Mutex // Guards the read-modify-write of score disputes
+	archiveMu     sync.Mutex // Guards the read-modify-write of game archive lists
+	registryMu    sync.Mutex // Guards registering games and the read-modify-write of the game index
+	playerIDSalts sync.Map   // Tenant -> salt, once read or created
+	saltMu        sync.Mutex
+}
+
+// Publisher receives every regenerated leaderboard for live fan-out
+// Publish must not block; slow consumers are the publisher's problem
+type Publisher interface {
+	Publish(event models.BoardEvent)
+}
+
+// ScoreListener receives what each counted submission changed for its player
+// ScoreSubmitted must not block, like Publish
+type ScoreListener interface {
+	ScoreSubmitted(event models.ScoreEvent)
+}
+
+// ResetListener receives every live board reset, such as at season boundaries
+// BoardReset must not block, like Publish
+type ResetListener interface {
+	BoardReset(event models.ResetEvent)
+}
+
+// Option configures optional Service behavior
+type Option func(*Service)
+
+// WithLogger sets the logger used when a request carries no scoped logger
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+// WithMaxEntries sets the leaderboard size for games without their own override
+func WithMaxEntries(maxEntries int) Option {
+	return func(s *Service) {
+		s.maxEntries = maxEntries
+	}
+}
+
+// WithPublisher sends leaderboard changes to publisher, in addition to any others
+func WithPublisher(publisher Publisher) Option {
+	return func(s *Service) {
+		s.publishers = append(s.publishers, publisher)
+	}
+}
+
+// WithScoreListener sends the outcome of counted submissions to listener, in addition
+// to any others
+func WithScoreListener(listener ScoreListener) Option {
+	return func(s *Service) {
+		s.listeners = append(s.listeners, listener)
+	}
+}
+
+// WithResetListener sends live board resets to listener, in addition to any others
+func WithResetListener(listener ResetListener) Option {
+	return func(s *Service) {
+		s.resetListeners = append(s.resetListeners, listener)
+	}
 }
 
 // NewService creates a new leaderboard service
-func NewService(db database.DB) *Service {
-	return &Service{db: db}
+func NewService(db database.DB, opts ...Option) *Service {
+	s := &Service{
+		db:         db,
+		analytics:  newAnalyticsCache(analyticsFreshTTL, analyticsStaleTTL),
+		boards:     newBoardCache(boardCacheTTL),
+		logger:     slog.Default(),
+		maxEntries: models.DefaultLeaderboardEntries,
+		undoWindow: DefaultUndoWindow,
+		instanceID: uuid.New().String(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.analytics.logger = s.logger
+	return s
+}
+
+// log returns the request-scoped logger for ctx, or the service logger
+func (s *Service) log(ctx context.Context) *slog.Logger {
+	return logging.FromContext(ctx, s.logger)
 }
 
 // SubmitScore submits a new score entry (traditional arcade style)
 // Now stores all scores and maintains per-player high scores
 func (s *Service) SubmitScore(ctx context.Context, gameID, initials string, score int64) error {
+	_, err := s.Submit(ctx, gameID, models.Submission{Initials: initials, Score: score})
+	return err
+}
+
+// Submit submits a score like SubmitScore, returning the stored entry and, for games
+// with a daily submission budget, what is left of the player's budget, along with any
+// achievements the score newly unlocked. Plays over budget
+// are stored in history flagged non-counting and leave high scores and the leaderboard
+// alone. Scores breaking the game's anti-cheat rules fail with a
+// *models.SuspiciousScoreError, or are stored with their violations when the rules flag
+// rather than reject. Anti-cheat rules judge the score as played; during a happy hour
+// the stored score is multiplied, with the score as played kept beside it. Games past
+// their sunset refuse submissions with a *models.GameSunsetError. Games requiring signed
+// submissions refuse scores without a valid signature with models.ErrSignatureRequired
+// or models.ErrInvalidScoreSignature. Submission hooks see the submission first; the
+// signature covers the submission as sent, and everything else what the hooks made of it.
+// Scores forwarded with the time they were played, which can't be later than now, are
+// stored, judged and checked against the game's sunset and signature window as of then.
+func (s *Service) Submit(ctx context.Context, gameID string, submission models.Submission) (*models.SubmissionResult, error) {
+	sent := submission
+	if err := s.runSubmissionHooks(ctx, gameID, &submission); err != nil {
+		return nil, err
+	}
+
 	// Validate initials (should be 3 characters, no spaces allowed)
-	initials = strings.ToUpper(strings.TrimSpace(initials))
+	initials := strings.ToUpper(strings.TrimSpace(submission.Initials))
 	if len(initials) != 3 || strings.Contains(initials, " ") {
-		return fmt.Errorf("initials must be exactly 3 characters with no spaces")
+		return nil, fmt.Errorf("initials must be exactly 3 characters with no spaces")
+	}
+	score := submission.Score
+	if err := submission.Metadata.Validate(); err != nil {
+		return nil, err
+	}
+	if err := models.ValidateSessionID(submission.SessionID); err != nil {
+		return nil, err
+	}
+	if submission.Sequence < 0 {
+		return nil, fmt.Errorf("sequence cannot be negative")
+	}
+
+	if s.IsBlocked(ctx, initials) {
+		return nil, fmt.Errorf("%w: %s", models.ErrBlockedInitials, initials)
+	}
+
+	now := time.Now()
+	playedAt := now
+	if !submission.PlayedAt.IsZero() && submission.PlayedAt.Before(now) {
+		playedAt = submission.PlayedAt
+	}
+	sunset, err := s.checkSunset(ctx, gameID, playedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the game is known to the registry
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("failed to register game: %w", err)
+	}
+
+	scoring := s.scoring(ctx, gameID)
+	if err := scoring.Check(score); err != nil {
+		return nil, err
+	}
+
+	signedInitials := strings.ToUpper(strings.TrimSpace(sent.Initials))
+	if err := s.checkSignature(ctx, gameID, signedInitials, sent.Score, scoring, submission.Signature, playedAt, now); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkClaim(ctx, gameID, initials, submission.PIN); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.endDueSeason(ctx, gameID, now); err != nil {
+		s.log(ctx).Warn("failed to end due season", "game_id", gameID, "error", err)
+	}
+
+	rules, violations := s.checkAntiCheat(ctx, gameID, initials, score, playedAt)
+	if len(violations) > 0 && !rules.Flags() {
+		s.log(ctx).Info("score rejected by anti-cheat rules", "game_id", gameID, "initials", initials, "score", score, "violations", len(violations))
+		return nil, &models.SuspiciousScoreError{Violations: violations}
 	}
 
+	budget := s.submissionBudget(ctx, gameID, initials, playedAt)
+	counted := budget == nil || budget.Counted
+
 	// Store the score in all scores history
-	if err := s.addToAllScores(ctx, gameID, initials, score); err != nil {
-		return fmt.Errorf("failed to store score in history: %w", err)
+	entry := models.ScoreEntry{
+		ID:             models.ScoreID(initials, playedAt),
+		Initials:       initials,
+		Score:          score,
+		Timestamp:      playedAt,
+		NonCounting:    !counted,
+		Flags:          violations,
+		Metadata:       submission.Metadata,
+		Sequence:       s.nextSequence(ctx, gameID),
+		ClientSequence: submission.Sequence,
+		SessionID:      submission.SessionID,
+	}
+	if p := apikeys.PrincipalFromContext(ctx); p != nil {
+		entry.DeviceID = p.Device
+	}
+	if submission.DeviceID != "" {
+		entry.DeviceID = submission.DeviceID
+	}
+	s.applyHappyHour(ctx, gameID, &entry, scoring, playedAt)
+	if scoring.Precision() > 0 {
+		entry.DisplayScore = scoring.Format(entry.Score)
+	}
+	history, err := s.addToAllScores(ctx, gameID, entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store score in history: %w", err)
 	}
+	s.tallyActivity(ctx, gameID, entry, history)
+	s.tallyTimeseries(ctx, gameID, entry, history)
 
-	// Update player's high score if necessary
-	if err := s.updatePlayerHighScore(ctx, gameID, initials, score); err != nil {
-		return fmt.Errorf("failed to update player high score: %w", err)
+	var achievements []models.Achievement
+	if counted {
+		// Update player's high score if necessary
+		previous, err := s.updatePlayerHighScore(ctx, gameID, entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update player high score: %w", err)
+		}
+
+		// Regenerate the filtered leaderboard
+		if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+			return nil, err
+		}
+
+		achievements = s.unlockAchievements(ctx, gameID, entry, history)
+		s.notifyListeners(ctx, gameID, entry, previous, achievements, submission)
 	}
 
-	// Regenerate the filtered leaderboard
-	return s.regenerateFilteredLeaderboard(ctx, gameID)
+	// Let cached analytics refresh in the background on the next read, on every replica
+	s.invalidateGame(ctx, gameID)
+
+	s.log(ctx).Debug("score submitted", "game_id", gameID, "initials", initials, "score", entry.Score, "counted", counted, "flagged", len(violations) > 0)
+	return &models.SubmissionResult{Entry: entry, Budget: budget, Achievements: achievements, Sunset: sunset}, nil
+}
+
+// notifyListeners tells score listeners whether a counted entry beat the player's high
+// score and which achievements it unlocked, with the PIN and signature it was sent with
+func (s *Service) notifyListeners(ctx context.Context, gameID string, entry models.ScoreEntry, previous *models.ScoreEntry, achievements []models.Achievement, submission models.Submission) {
+	if len(s.listeners) == 0 {
+		return
+	}
+
+	event := models.ScoreEvent{
+		GameID:            gameID,
+		Tenant:            tenants.FromContext(ctx),
+		Entry:             entry,
+		PreviousHighScore: previous,
+		NewHighScore:      previous == nil || entry.Score > previous.Score,
+		Achievements:      achievements,
+		PIN:               submission.PIN,
+		Signature:         submission.Signature,
+	}
+	for _, listener := range s.listeners {
+		listener.ScoreSubmitted(event)
+	}
 }
 
 // submitScoreAtomic uses Redis sorted sets for efficient score management
@@ -93,16 +350,12 @@ func (s *Service) submitScoreLegacy(ctx context.Context, gameID, initials string
 
 	// Sort by score (highest first) - use stable sort for consistent ordering
 	sort.SliceStable(leaderboard.Entries, func(i, j int) bool {
-		if leaderboard.Entries[i].Score == leaderboard.Entries[j].Score {
-			// If scores are equal, newer entries come first (traditional arcade behavior)
-			return leaderboard.Entries[i].Timestamp.After(leaderboard.Entries[j].Timestamp)
-		}
-		return leaderboard.Entries[i].Score > leaderboard.Entries[j].Score
+		return ranksAbove(leaderboard.Entries[i], leaderboard.Entries[j])
 	})
 
-	// Keep only top 10 scores (traditional arcade limit)
-	if len(leaderboard.Entries) > 10 {
-		leaderboard.Entries = leaderboard.Entries[:10]
+	// Keep only the top scores (traditional arcade limit)
+	if size := s.LeaderboardSize(ctx, gameID); len(leaderboard.Entries) > size {
+		leaderboard.Entries = leaderboard.Entries[:size]
 	}
 
 	// Save back to database
@@ -110,8 +363,15 @@ func (s *Service) submitScoreLegacy(ctx context.Context, gameID, initials string
 }
 
 // GetLeaderboard returns the current leaderboard for a game
-// This now returns the filtered leaderboard (highest score per player)
+// This now returns the filtered leaderboard (highest score per player), from memory
+// when it hasn't changed since it was last read. The caller owns the returned board.
 func (s *Service) GetLeaderboard(ctx context.Context, gameID string) (*models.Leaderboard, error) {
+	cacheKey := boardKey{tenant: tenants.FromContext(ctx), gameID: gameID}
+	cached, generation, ok := s.boards.get(cacheKey)
+	if ok {
+		return cached, nil
+	}
+
 	key := fmt.Sprintf("leaderboard:%s", gameID)
 
 	data, err := s.db.Get(ctx, key)
@@ -135,11 +395,14 @@ func (s *Service) GetLeaderboard(ctx context.Context, gameID string) (*models.Le
 		return nil, fmt.Errorf("failed to unmarshal leaderboard: %w", err)
 	}
 
+	s.boards.store(cacheKey, &leaderboard, generation)
 	return &leaderboard, nil
 }
 
 // saveLeaderboard saves a leaderboard to the database with optimized encoding
 func (s *Service) saveLeaderboard(ctx context.Context, leaderboard *models.Leaderboard) error {
+	leaderboard.Updated = time.Now()
+
 	// Use buffer pool to reduce allocations
 	var buf strings.Builder
 	buf.Grow(1024) // Pre-allocate reasonable size for typical leaderboard JSON
@@ -154,20 +417,14 @@ func (s *Service) saveLeaderboard(ctx context.Context, leaderboard *models.Leade
 	key := fmt.Sprintf("leaderboard:%s", leaderboard.GameID)
 	// Remove trailing newline that encoder.Encode adds
 	jsonData := strings.TrimSuffix(buf.String(), "\n")
+	defer s.boards.invalidate(boardKey{tenant: tenants.FromContext(ctx), gameID: leaderboard.GameID})
 	return s.db.Set(ctx, key, jsonData)
 }
 
 // addToAllScores adds a score entry to the complete score history
-func (s *Service) addToAllScores(ctx context.Context, gameID, initials string, score int64) error {
+func (s *Service) addToAllScores(ctx context.Context, gameID string, entry models.ScoreEntry) (*models.AllScoresRecord, error) {
 	key := fmt.Sprintf("all_scores:%s", gameID)
 
-	// Create the score entry
-	entry := models.ScoreEntry{
-		Initials:  initials,
-		Score:     score,
-		Timestamp: time.Now(),
-	}
-
 	// Get existing all scores record
 	allScores, err := s.getAllScores(ctx, gameID)
 	if err != nil {
@@ -179,24 +436,39 @@ func (s *Service) addToAllScores(ctx context.Context, gameID, initials string, s
 		}
 	}
 
-	// Add new entry
-	allScores.Scores = append(allScores.Scores, entry)
+	// Add new entry, in time order: forwarded scores may have been played before the last
+	at := len(allScores.Scores)
+	for at > 0 && allScores.Scores[at-1].Timestamp.After(entry.Timestamp) {
+		at--
+	}
+	allScores.Scores = slices.Insert(allScores.Scores, at, entry)
 	allScores.Updated = time.Now()
 
 	// Save back to database
 	var buf strings.Builder
 	encoder := json.NewEncoder(&buf)
 	if err := encoder.Encode(allScores); err != nil {
-		return fmt.Errorf("failed to marshal all scores: %w", err)
+		return nil, fmt.Errorf("failed to marshal all scores: %w", err)
 	}
 
 	jsonData := strings.TrimSuffix(buf.String(), "\n")
-	return s.db.Set(ctx, key, jsonData)
+	if err := s.db.Set(ctx, key, jsonData); err != nil {
+		return nil, err
+	}
+
+	s.indexScore(ctx, gameID, entry)
+	return allScores, nil
 }
 
-// updatePlayerHighScore updates a player's high score if the new score is higher
-func (s *Service) updatePlayerHighScore(ctx context.Context, gameID, initials string, score int64) error {
+// updatePlayerHighScore makes a counted entry the player's high score, with its metadata,
+// sequence and any happy hour multiplier, if it beats their current one. It returns the high score the player had
+// before, nil for their first score. In device-scoped games the player is the initials
+// on the entry's device.
+func (s *Service) updatePlayerHighScore(ctx context.Context, gameID string, entry models.ScoreEntry) (*models.ScoreEntry, error) {
+	initials, score := entry.Initials, entry.Score
 	key := fmt.Sprintf("player_high_scores:%s", gameID)
+	settings := s.gameSettings(ctx, gameID)
+	player := settings.PlayerKey(initials, entry.DeviceID)
 
 	// Get existing high scores
 	highScores, err := s.getPlayerHighScores(ctx, gameID)
@@ -210,28 +482,44 @@ func (s *Service) updatePlayerHighScore(ctx context.Context, gameID, initials st
 	}
 
 	// Check if this is a new high score for the player
-	existingEntry, exists := highScores.HighScores[initials]
+	existingEntry, exists := highScores.HighScores[player]
+	var previous *models.ScoreEntry
+	if exists {
+		previous = &existingEntry
+	}
 	if !exists || score > existingEntry.Score {
 		// Update or create the high score entry
-		highScores.HighScores[initials] = models.ScoreEntry{
-			Initials:  initials,
-			Score:     score,
-			Timestamp: time.Now(),
+		highScore := models.ScoreEntry{
+			ID:             entry.ID,
+			Initials:       initials,
+			Score:          score,
+			Timestamp:      time.Now(),
+			Metadata:       entry.Metadata,
+			Sequence:       entry.Sequence,
+			ClientSequence: entry.ClientSequence,
+			RawScore:       entry.RawScore,
+			Multiplier:     entry.Multiplier,
+			HappyHour:      entry.HappyHour,
 		}
+		// Boards show which device each of the initials' entries belongs to
+		if player != initials {
+			highScore.DeviceID = entry.DeviceID
+		}
+		highScores.HighScores[player] = highScore
 		highScores.Updated = time.Now()
 
 		// Save back to database
 		var buf strings.Builder
 		encoder := json.NewEncoder(&buf)
 		if err := encoder.Encode(highScores); err != nil {
-			return fmt.Errorf("failed to marshal high scores: %w", err)
+			return nil, fmt.Errorf("failed to marshal high scores: %w", err)
 		}
 
 		jsonData := strings.TrimSuffix(buf.String(), "\n")
-		return s.db.Set(ctx, key, jsonData)
+		return previous, s.db.Set(ctx, key, jsonData)
 	}
 
-	return nil // No update needed
+	return previous, nil // No update needed
 }
 
 // regenerateFilteredLeaderboard creates a leaderboard showing only the highest score per initials
@@ -242,6 +530,50 @@ func (s *Service) regenerateFilteredLeaderboard(ctx context.Context, gameID stri
 		return fmt.Errorf("failed to get player high scores: %w", err)
 	}
 
+	entries := rankHighScores(highScores)
+	withDisplayScores(s.scoring(ctx, gameID), entries)
+
+	// Keep the untruncated ranking for rank lookups beyond the board
+	if err := s.saveRanking(ctx, gameID, entries); err != nil {
+		return err
+	}
+
+	// Keep only the top scores
+	if size := s.LeaderboardSize(ctx, gameID); len(entries) > size {
+		entries = entries[:size]
+	}
+
+	// Create the filtered leaderboard, one version past the board it replaces
+	leaderboard := &models.Leaderboard{
+		GameID:  gameID,
+		Entries: entries,
+		Version: 1,
+	}
+	previous, err := s.getRawLeaderboard(ctx, gameID)
+	if err == nil {
+		leaderboard.Version = previous.Version + 1
+	}
+
+	// Save the filtered leaderboard
+	if err := s.saveLeaderboard(ctx, leaderboard); err != nil {
+		return err
+	}
+
+	for _, publisher := range s.publishers {
+		publisher.Publish(models.BoardEvent{
+			Type:        models.BoardEventUpdated,
+			GameID:      gameID,
+			Version:     leaderboard.Version,
+			Leaderboard: leaderboard,
+			Previous:    previous,
+			Tenant:      tenants.FromContext(ctx),
+		})
+	}
+	return nil
+}
+
+// rankHighScores returns every player's high score sorted into leaderboard order
+func rankHighScores(highScores *models.PlayerHighScores) []models.ScoreEntry {
 	// Convert map to slice for sorting
 	entries := make([]models.ScoreEntry, 0, len(highScores.HighScores))
 	for _, entry := range highScores.HighScores {
@@ -250,26 +582,62 @@ func (s *Service) regenerateFilteredLeaderboard(ctx context.Context, gameID stri
 
 	// Sort by score (highest first) - use stable sort for consistent ordering
 	sort.SliceStable(entries, func(i, j int) bool {
-		if entries[i].Score == entries[j].Score {
-			// If scores are equal, newer entries come first (traditional arcade behavior)
-			return entries[i].Timestamp.After(entries[j].Timestamp)
-		}
-		return entries[i].Score > entries[j].Score
+		return ranksAbove(entries[i], entries[j])
 	})
 
-	// Keep only top 10 scores
-	if len(entries) > 10 {
-		entries = entries[:10]
+	return entries
+}
+
+// ranksAbove reports whether a ranks above b: higher scores first, then newer entries
+// (traditional arcade behavior), then by sequence, so entries stored in the same instant
+// still have one order
+func ranksAbove(a, b models.ScoreEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if !a.Timestamp.Equal(b.Timestamp) {
+		return a.Timestamp.After(b.Timestamp)
 	}
+	return sequencedAbove(a, b)
+}
 
-	// Create the filtered leaderboard
-	leaderboard := &models.Leaderboard{
-		GameID:  gameID,
-		Entries: entries,
+// sequencedAbove orders entries stored in the same instant. Client sequences are only
+// compared with each other, so entries the server sequenced come first, in arrival
+// order, then those a client synced, higher sequences first.
+func sequencedAbove(a, b models.ScoreEntry) bool {
+	if (a.ClientSequence == 0) != (b.ClientSequence == 0) {
+		return a.ClientSequence == 0
+	}
+	if a.ClientSequence != b.ClientSequence {
+		return a.ClientSequence > b.ClientSequence
 	}
+	return a.Sequence > b.Sequence
+}
 
-	// Save the filtered leaderboard
-	return s.saveLeaderboard(ctx, leaderboard)
+// nextSequence returns the next server-assigned sequence for a game's entries. Databases
+// without counters, or a failing counter, fall back to one past the highest sequence in
+// the game's history.
+func (s *Service) nextSequence(ctx context.Context, gameID string) int64 {
+	if counters, ok := s.db.(database.Counters); ok {
+		sequence, err := counters.Incr(ctx, sequenceKey(gameID))
+		if err == nil {
+			return sequence
+		}
+		s.log(ctx).Warn("failed to assign sequence from counter", "game_id", gameID, "error", err)
+	}
+
+	var highest int64
+	if history, err := s.getAllScores(ctx, gameID); err == nil {
+		for _, entry := range history.Scores {
+			highest = max(highest, entry.Sequence)
+		}
+	}
+	return highest + 1
+}
+
+// sequenceKey returns the database key of a game's sequence counter
+func sequenceKey(gameID string) string {
+	return fmt.Sprintf("score_sequence:%s", gameID)
 }
 
 // getAllScores retrieves the complete score history for a game
@@ -277,8 +645,11 @@ func (s *Service) getAllScores(ctx context.Context, gameID string) (*models.AllS
 	key := fmt.Sprintf("all_scores:%s", gameID)
 
 	data, err := s.db.Get(ctx, key)
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNoScoreHistory
+	}
 	if err != nil {
-		return nil, fmt.Errorf("no score history found for game")
+		return nil, fmt.Errorf("failed to get score history: %w", err)
 	}
 
 	var allScores models.AllScoresRecord
@@ -328,7 +699,9 @@ func (s *Service) getRawLeaderboard(ctx context.Context, gameID string) (*models
 	return &leaderboard, nil
 }
 
-// GetPlayerStats returns comprehensive statistics for a specific player
+// GetPlayerStats returns comprehensive statistics for a specific player. In
+// device-scoped games they cover the device the lookup is scoped to, or the initials on
+// every device.
 func (s *Service) GetPlayerStats(ctx context.Context, gameID, initials string) (*models.PlayerStats, error) {
 	initials = strings.ToUpper(strings.TrimSpace(initials))
 	if len(initials) != 3 {
@@ -341,10 +714,11 @@ func (s *Service) GetPlayerStats(ctx context.Context, gameID, initials string) (
 		return nil, fmt.Errorf("failed to get score history: %w", err)
 	}
 
-	// Filter scores for this player
+	// Filter scores for this player, on the device the lookup is scoped to if any
+	deviceID := s.lookupDevice(ctx, gameID)
 	playerScores := make([]models.ScoreEntry, 0)
 	for _, entry := range allScores.Scores {
-		if entry.Initials == initials {
+		if playerScore(entry, initials, deviceID) {
 			playerScores = append(playerScores, entry)
 		}
 	}
@@ -355,12 +729,14 @@ func (s *Service) GetPlayerStats(ctx context.Context, gameID, initials string) (
 
 	// Calculate statistics
 	var highScore int64
+	var highScoreMetadata models.ScoreMetadata
 	var totalScore int64
 	var firstPlayed, lastPlayed time.Time
 
 	for i, entry := range playerScores {
-		if entry.Score > highScore {
+		if i == 0 || entry.Score > highScore {
 			highScore = entry.Score
+			highScoreMetadata = entry.Metadata
 		}
 		totalScore += entry.Score
 
@@ -379,14 +755,24 @@ func (s *Service) GetPlayerStats(ctx context.Context, gameID, initials string) (
 
 	averageScore := float64(totalScore) / float64(len(playerScores))
 
-	return &models.PlayerStats{
+	stats := &models.PlayerStats{
 		Initials:     initials,
 		HighScore:    highScore,
 		TotalScores:  len(playerScores),
 		LastPlayed:   lastPlayed,
 		AverageScore: averageScore,
 		FirstPlayed:  firstPlayed,
-	}, nil
+		Profile:      s.profileOf(ctx, initials),
+		Activity:     s.playerActivity(ctx, gameID, initials, playerScores),
+		Standing:     s.standingOf(ctx, gameID, initials),
+
+		HighScoreMetadata: highScoreMetadata,
+	}
+	if scoring := s.scoring(ctx, gameID); scoring.Precision() > 0 {
+		stats.DisplayHighScore = scoring.Format(highScore)
+		stats.DisplayAverageScore = scoring.FormatMean(averageScore)
+	}
+	return stats, nil
 }
 
 // GetAllScoresForGame returns all scores submitted for a game (for admin/analytics)
@@ -394,87 +780,9 @@ func (s *Service) GetAllScoresForGame(ctx context.Context, gameID string) (*mode
 	return s.getAllScores(ctx, gameID)
 }
 
-// calculateAchievements determines which achievements a player has unlocked
-func (s *Service) calculateAchievements(playerScores []models.ScoreEntry, highScore int64) []models.Achievement {
-	achievements := make([]models.Achievement, 0)
-
-	if len(playerScores) == 0 {
-		return achievements
-	}
-
-	// Sort scores by timestamp for achievement calculation
-	sort.Slice(playerScores, func(i, j int) bool {
-		return playerScores[i].Timestamp.Before(playerScores[j].Timestamp)
-	})
-
-	firstScore := playerScores[0]
-
-	// First Score Achievement
-	achievements = append(achievements, models.Achievement{
-		ID:          "first_score",
-		Name:        "First Score",
-		Description: "Submit your first score",
-		UnlockedAt:  firstScore.Timestamp,
-		Icon:        "🎯",
-	})
-
-	// Score milestone achievements
-	milestones := []struct {
-		score int64
-		id    string
-		name  string
-		icon  string
-	}{
-		{1000, "score_1k", "Getting Started", "⭐"},
-		{5000, "score_5k", "Rising Star", "🌟"},
-		{10000, "score_10k", "High Achiever", "💫"},
-		{25000, "score_25k", "Score Master", "🏆"},
-		{50000, "score_50k", "Legend", "👑"},
-	}
-
-	for _, milestone := range milestones {
-		if highScore >= milestone.score {
-			// Find when this milestone was first achieved
-			var unlockedAt time.Time
-			for _, score := range playerScores {
-				if score.Score >= milestone.score {
-					unlockedAt = score.Timestamp
-					break
-				}
-			}
-
-			achievements = append(achievements, models.Achievement{
-				ID:          milestone.id,
-				Name:        milestone.name,
-				Description: fmt.Sprintf("Reach %d points", milestone.score),
-				UnlockedAt:  unlockedAt,
-				Icon:        milestone.icon,
-			})
-		}
-	}
-
-	// Dedication achievements
-	if len(playerScores) >= 5 {
-		achievements = append(achievements, models.Achievement{
-			ID:          "dedicated_player",
-			Name:        "Dedicated Player",
-			Description: "Submit 5 or more scores",
-			UnlockedAt:  playerScores[4].Timestamp, // 5th score
-			Icon:        "🎮",
-		})
-	}
-
-	if len(playerScores) >= 10 {
-		achievements = append(achievements, models.Achievement{
-			ID:          "score_hunter",
-			Name:        "Score Hunter",
-			Description: "Submit 10 or more scores",
-			UnlockedAt:  playerScores[9].Timestamp, // 10th score
-			Icon:        "🏹",
-		})
-	}
-
-	return achievements
+// GetPlayerHighScores returns every player's high score for a game (for admin/export)
+func (s *Service) GetPlayerHighScores(ctx context.Context, gameID string) (*models.PlayerHighScores, error) {
+	return s.getPlayerHighScores(ctx, gameID)
 }
 
 // GetEnhancedPlayerStats returns comprehensive statistics with achievements
@@ -490,10 +798,11 @@ func (s *Service) GetEnhancedPlayerStats(ctx context.Context, gameID, initials s
 		return nil, fmt.Errorf("failed to get score history: %w", err)
 	}
 
-	// Filter scores for this player
+	// Filter scores for this player, on the device the lookup is scoped to if any
+	deviceID := s.lookupDevice(ctx, gameID)
 	playerScores := make([]models.ScoreEntry, 0)
 	for _, entry := range allScores.Scores {
-		if entry.Initials == initials {
+		if playerScore(entry, initials, deviceID) {
 			playerScores = append(playerScores, entry)
 		}
 	}
@@ -502,13 +811,39 @@ func (s *Service) GetEnhancedPlayerStats(ctx context.Context, gameID, initials s
 		return nil, fmt.Errorf("no scores found for player %s", initials)
 	}
 
+	// Get current rank from leaderboard
+	var currentRank *int
+	leaderboard, err := s.GetLeaderboard(ctx, gameID)
+	if err == nil {
+		for i, entry := range leaderboard.Entries {
+			if playerScore(entry, initials, deviceID) {
+				rank := i + 1
+				currentRank = &rank
+				break
+			}
+		}
+	}
+
+	achievements := s.playerAchievements(ctx, gameID, map[string][]models.ScoreEntry{initials: playerScores})
+	stats := s.buildEnhancedPlayerStats(initials, playerScores, achievements[initials], currentRank, includeHistory)
+	stats.Profile = s.profileOf(ctx, initials)
+	stats.Activity = s.playerActivity(ctx, gameID, initials, playerScores)
+	stats.Standing = s.standingOf(ctx, gameID, initials)
+	stats.Sessions = playerSessions(playerScores)
+	withDisplayStats(s.scoring(ctx, gameID), stats)
+	return stats, nil
+}
+
+// buildEnhancedPlayerStats derives enhanced statistics from a player's score history and
+// unlocked achievements
+func (s *Service) buildEnhancedPlayerStats(initials string, playerScores []models.ScoreEntry, achievements []models.Achievement, currentRank *int, includeHistory bool) *models.EnhancedPlayerStats {
 	// Calculate basic statistics
 	var highScore int64
 	var totalScore int64
 	var firstPlayed, lastPlayed time.Time
 
 	for i, entry := range playerScores {
-		if entry.Score > highScore {
+		if i == 0 || entry.Score > highScore {
 			highScore = entry.Score
 		}
 		totalScore += entry.Score
@@ -528,22 +863,10 @@ func (s *Service) GetEnhancedPlayerStats(ctx context.Context, gameID, initials s
 
 	averageScore := float64(totalScore) / float64(len(playerScores))
 
-	// Get current rank from leaderboard
-	var currentRank *int
-	leaderboard, err := s.GetLeaderboard(ctx, gameID)
-	if err == nil {
-		for i, entry := range leaderboard.Entries {
-			if entry.Initials == initials {
-				rank := i + 1
-				currentRank = &rank
-				break
-			}
-		}
+	if achievements == nil {
+		achievements = []models.Achievement{}
 	}
 
-	// Calculate achievements
-	achievements := s.calculateAchievements(playerScores, highScore)
-
 	// Prepare score history if requested
 	var scoreHistory []models.ScoreEntry
 	if includeHistory {
@@ -560,11 +883,38 @@ func (s *Service) GetEnhancedPlayerStats(ctx context.Context, gameID, initials s
 		CurrentRank:  currentRank,
 		Achievements: achievements,
 		ScoreHistory: scoreHistory,
-	}, nil
+	}
 }
 
 // GetScoreAnalysis returns comprehensive analysis for a game
+// Results are cached per game with stale-while-revalidate semantics, so the
+// returned analysis may be up to a few minutes old and must not be modified
 func (s *Service) GetScoreAnalysis(ctx context.Context, gameID string, topPlayersLimit int) (*models.ScoreAnalysisResponse, error) {
+	return s.GetScoreAnalysisPage(ctx, gameID, topPlayersLimit, 0)
+}
+
+// GetScoreAnalysisPage returns comprehensive analysis for a game with a page of
+// top players starting at the given offset into the full player ranking
+func (s *Service) GetScoreAnalysisPage(ctx context.Context, gameID string, topPlayersLimit, offset int) (*models.ScoreAnalysisResponse, error) {
+	limit := topPlayersLimit
+	if limit <= 0 || limit > MaxTopPlayersLimit {
+		limit = MaxTopPlayersLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > MaxAnalysisOffset {
+		offset = MaxAnalysisOffset
+	}
+
+	key := analyticsKey{tenant: tenants.FromContext(ctx), gameID: gameID, topPlayersLimit: limit, offset: offset}
+	return s.analytics.get(ctx, key, func(ctx context.Context) (*models.ScoreAnalysisResponse, error) {
+		return s.computeScoreAnalysis(ctx, gameID, limit, offset)
+	})
+}
+
+// computeScoreAnalysis builds a score analysis directly from storage
+func (s *Service) computeScoreAnalysis(ctx context.Context, gameID string, limit, offset int) (*models.ScoreAnalysisResponse, error) {
 	// Get all scores
 	allScores, err := s.getAllScores(ctx, gameID)
 	if err != nil {
@@ -583,8 +933,8 @@ func (s *Service) GetScoreAnalysis(ctx context.Context, gameID string, topPlayer
 	playerMap := make(map[string][]models.ScoreEntry)
 
 	// Group scores by player and calculate totals
-	for _, score := range allScores.Scores {
-		if score.Score > highestScore {
+	for i, score := range allScores.Scores {
+		if i == 0 || score.Score > highestScore {
 			highestScore = score.Score
 		}
 		totalScore += score.Score
@@ -599,71 +949,52 @@ func (s *Service) GetScoreAnalysis(ctx context.Context, gameID string, topPlayer
 	totalPlayers := len(playerMap)
 	averageScore := float64(totalScore) / float64(totalScores)
 
-	// Get top players with enhanced stats
-	topPlayers := make([]models.EnhancedPlayerStats, 0)
-	leaderboard, _ := s.GetLeaderboard(ctx, gameID)
-
-	limit := topPlayersLimit
-	if limit <= 0 || limit > 10 {
-		limit = 10
+	// Rank every player so pages beyond the displayed leaderboard are available
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get player high scores: %w", err)
 	}
+	ranked := rankHighScores(highScores)
 
-	for i, entry := range leaderboard.Entries {
-		if i >= limit {
-			break
-		}
-
-		enhancedStats, err := s.GetEnhancedPlayerStats(ctx, gameID, entry.Initials, false)
-		if err == nil {
-			topPlayers = append(topPlayers, *enhancedStats)
-		}
+	// Get the requested page of top players with enhanced stats
+	start := offset
+	if start > len(ranked) {
+		start = len(ranked)
+	}
+	end := start + limit
+	if end > len(ranked) {
+		end = len(ranked)
 	}
 
-	// Calculate score distribution
-	scoreDistribution := make(map[string]int)
-	ranges := []struct {
-		min, max int64
-		label    string
-	}{
-		{0, 999, "0-999"},
-		{1000, 4999, "1K-5K"},
-		{5000, 9999, "5K-10K"},
-		{10000, 24999, "10K-25K"},
-		{25000, 49999, "25K-50K"},
-		{50000, 999999999, "50K+"},
-	}
-
-	for _, score := range allScores.Scores {
-		for _, r := range ranges {
-			if score.Score >= r.min && score.Score <= r.max {
-				scoreDistribution[r.label]++
-				break
-			}
-		}
+	achievements := s.playerAchievements(ctx, gameID, playerMap)
+	topPlayers, err := s.computeTopPlayers(ctx, ranked[start:end], start, playerMap, achievements)
+	if err != nil {
+		return nil, err
+	}
+	scoring := s.scoring(ctx, gameID)
+	for i := range topPlayers {
+		withDisplayStats(scoring, &topPlayers[i])
+	}
+	topPlayersPage := &models.Pagination{
+		Offset:  start,
+		Limit:   limit,
+		Total:   len(ranked),
+		HasMore: end < len(ranked),
 	}
 
 	// Get recent achievements (last 24 hours)
 	recentAchievements := make([]models.Achievement, 0)
 	cutoff := time.Now().Add(-24 * time.Hour)
 
-	for _, playerScores := range playerMap {
-		// Get player's highest score
-		var highScore int64
-		for _, score := range playerScores {
-			if score.Score > highScore {
-				highScore = score.Score
-			}
-		}
-
-		achievements := s.calculateAchievements(playerScores, highScore)
-		for _, achievement := range achievements {
+	for _, unlocked := range achievements {
+		for _, achievement := range unlocked {
 			if achievement.UnlockedAt.After(cutoff) {
 				recentAchievements = append(recentAchievements, achievement)
 			}
 		}
 	}
 
-	return &models.ScoreAnalysisResponse{
+	analysis := &models.ScoreAnalysisResponse{
 		GameID:             gameID,
 		TotalPlayers:       totalPlayers,
 		TotalScores:        totalScores,
@@ -671,10 +1002,17 @@ func (s *Service) GetScoreAnalysis(ctx context.Context, gameID string, topPlayer
 		AverageScore:       averageScore,
 		LastActivity:       lastActivity,
 		TopPlayers:         topPlayers,
-		ScoreDistribution:  scoreDistribution,
+		TopPlayersPage:     topPlayersPage,
+		ScoreDistribution:  scoreDistribution(scoring, allScores.Scores),
+		HighScores:         scoreSpread(ranked),
 		RecentAchievements: recentAchievements,
 		Updated:            time.Now(),
-	}, nil
+	}
+	if scoring.Precision() > 0 {
+		analysis.DisplayHighestScore = scoring.Format(highestScore)
+		analysis.DisplayAverageScore = scoring.FormatMean(averageScore)
+	}
+	return analysis, nil
 }
 
 // MigrateExistingLeaderboard migrates an existing leaderboard to the new storage format
@@ -694,6 +1032,10 @@ func (s *Service) MigrateExistingLeaderboard(ctx context.Context, gameID string)
 		return nil
 	}
 
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return fmt.Errorf("failed to register game during migration: %w", err)
+	}
+
 	// Create all scores record from existing leaderboard entries
 	allScores := &models.AllScoresRecord{
 		GameID:  gameID,
@@ -711,6 +1053,7 @@ func (s *Service) MigrateExistingLeaderboard(ctx context.Context, gameID string)
 	if err := s.db.Set(ctx, fmt.Sprintf("all_scores:%s", gameID), jsonData); err != nil {
 		return fmt.Errorf("failed to save all scores during migration: %w", err)
 	}
+	s.recountTimeseries(ctx, gameID, allScores.Scores)
 
 	// Create player high scores from existing entries
 	highScores := &models.PlayerHighScores{
@@ -738,5 +1081,11 @@ func (s *Service) MigrateExistingLeaderboard(ctx context.Context, gameID string)
 	}
 
 	// Regenerate the filtered leaderboard to ensure consistency
-	return s.regenerateFilteredLeaderboard(ctx, gameID)
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return err
+	}
+
+	s.log(ctx).Info("migrated leaderboard to per-player format",
+		"game_id", gameID, "scores", len(allScores.Scores), "players", len(highScores.HighScores))
+	return nil
 }
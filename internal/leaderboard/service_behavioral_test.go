@@ -3,7 +3,6 @@ package leaderboard
 import (
 	"context"
 	"fmt"
-	"os"
 	"sort"
 	"sync"
 	"testing"
@@ -12,23 +11,18 @@ import (
 	"rawboard/internal/database"
 )
 
-// TestLeaderboardBehaviors focuses on key leaderboard service behaviors
+// TestLeaderboardBehaviors runs the behavior suite against the in-memory fake
 func TestLeaderboardBehaviors(t *testing.T) {
-	if os.Getenv("SKIP_DB_TESTS") != "" {
-		t.Skip("Skipping leaderboard behavioral tests - database tests disabled")
-	}
-
-	// Setup test environment
-	db, err := database.NewValkeyDB()
-	if err != nil {
-		t.Skip("Skipping leaderboard behavioral tests - no database available")
-	}
-	defer db.Close()
+	testLeaderboardBehaviors(t, database.NewFake())
+}
 
-	if err := db.Ping(context.Background()); err != nil {
-		t.Skip("Skipping leaderboard behavioral tests - database connection failed")
-	}
+// TestLeaderboardBehaviorsValkey runs the behavior suite against Valkey as an integration test
+func TestLeaderboardBehaviorsValkey(t *testing.T) {
+	testLeaderboardBehaviors(t, setupValkeyDatabase(t))
+}
 
+// testLeaderboardBehaviors focuses on key leaderboard service behaviors
+func testLeaderboardBehaviors(t *testing.T, db database.DB) {
 	service := NewService(db)
 	ctx := context.Background()
 
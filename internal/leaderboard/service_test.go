@@ -2,21 +2,19 @@ package leaderboard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"rawboard/internal/database"
+	"rawboard/internal/models"
 )
 
 func TestLeaderboardService(t *testing.T) {
-	// Skip if no database available
-	if os.Getenv("SKIP_DB_TESTS") != "" {
-		t.Skip("Skipping leaderboard tests - database tests disabled")
-	}
-
 	ctx := context.Background()
 	t.Run("stores and retrieves player scores correctly", func(t *testing.T) {
 		db := setupTestDatabase(t)
@@ -204,18 +202,108 @@ func TestLeaderboardService(t *testing.T) {
 			t.Errorf("Expected top snake score to be 2000, got %d", snakeBoard.Entries[0].Score)
 		}
 	})
+	t.Run("keeps submitted metadata with the score", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_metadata_" + generateTestID()
+		best := models.ScoreMetadata{"level": float64(12), "character": "ms_pacman", "perfect": true}
+		if _, err := service.Submit(ctx, gameID, models.Submission{Initials: "AAA", Score: 9000, Metadata: best}); err != nil {
+			t.Fatalf("Failed to submit score with metadata: %v", err)
+		}
+		if _, err := service.Submit(ctx, gameID, models.Submission{Initials: "AAA", Score: 500, Metadata: models.ScoreMetadata{"level": float64(2)}}); err != nil {
+			t.Fatalf("Failed to submit score with metadata: %v", err)
+		}
+
+		// The board shows the metadata of each player's high score, and history keeps every play's
+		leaderboard, _ := service.GetLeaderboard(ctx, gameID)
+		if len(leaderboard.Entries) != 1 || leaderboard.Entries[0].Metadata["character"] != "ms_pacman" {
+			t.Errorf("Expected the high score's metadata on the board, got %+v", leaderboard.Entries)
+		}
+		history, _ := service.GetAllScoresForGame(ctx, gameID)
+		if len(history.Scores) != 2 || history.Scores[1].Metadata["level"] != float64(2) {
+			t.Errorf("Expected every play's metadata in history, got %+v", history.Scores)
+		}
+		stats, err := service.GetPlayerStats(ctx, gameID, "AAA")
+		if err != nil || stats.HighScoreMetadata["perfect"] != true {
+			t.Errorf("Expected the high score's metadata in player stats, got %+v (%v)", stats, err)
+		}
+
+		tooMany := models.ScoreMetadata{}
+		for i := 0; i <= models.MaxMetadataKeys; i++ {
+			tooMany[fmt.Sprintf("key_%d", i)] = true
+		}
+		for name, metadata := range map[string]models.ScoreMetadata{
+			"nested value":  {"loadout": map[string]interface{}{"weapon": "laser"}},
+			"invalid key":   {"Level Reached": float64(3)},
+			"too many keys": tooMany,
+			"long value":    {"note": strings.Repeat("x", models.MaxMetadataValueLength+1)},
+		} {
+			if _, err := service.Submit(ctx, gameID, models.Submission{Initials: "BBB", Score: 100, Metadata: metadata}); err == nil {
+				t.Errorf("Expected metadata with a %s to be rejected", name)
+			}
+		}
+	})
+	t.Run("reports storage failures instead of dropping scores", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		service := NewService(db)
+		gameID := "test_write_failure_" + generateTestID()
+
+		// When the database rejects writes, the submission fails
+		db.Fail(database.OpSet, errors.New("connection reset"))
+		if err := service.SubmitScore(ctx, gameID, "AAA", 1000); err == nil {
+			t.Fatal("Expected the submission to fail while writes fail")
+		}
+
+		// And once it recovers, submissions succeed again
+		db.Heal()
+		if err := service.SubmitScore(ctx, gameID, "AAA", 1000); err != nil {
+			t.Fatalf("Expected the submission to succeed after recovery: %v", err)
+		}
+		leaderboard, err := service.GetLeaderboard(ctx, gameID)
+		if err != nil || len(leaderboard.Entries) != 1 {
+			t.Fatalf("Expected one entry after recovery, got %+v (%v)", leaderboard, err)
+		}
+	})
+	t.Run("reports read failures for a game's leaderboard", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		service := NewService(db)
+		gameID := "test_read_failure_" + generateTestID()
+		service.SubmitScore(ctx, gameID, "AAA", 1000)
+
+		db.FailKey(database.OpGet, "leaderboard:", errors.New("i/o timeout"))
+		if _, err := service.GetLeaderboard(ctx, gameID); err == nil {
+			t.Error("Expected the leaderboard read to fail")
+		}
+
+		db.Heal()
+		if _, err := service.GetLeaderboard(ctx, gameID); err != nil {
+			t.Errorf("Expected reads to succeed after recovery: %v", err)
+		}
+	})
+}
+
+// setupTestDatabase returns an empty in-memory database
+func setupTestDatabase(t *testing.T) *database.Fake {
+	return database.NewFake()
 }
 
-func setupTestDatabase(t *testing.T) database.DB {
+// setupValkeyDatabase connects to Valkey for integration tests, skipping when none is available
+func setupValkeyDatabase(t *testing.T) database.DB {
+	if os.Getenv("SKIP_DB_TESTS") != "" {
+		t.Skip("Skipping Valkey integration test - database tests disabled")
+	}
+
 	db, err := database.NewValkeyDB()
 	if err != nil {
-		t.Skip("Skipping test - failed to connect to database")
+		t.Skip("Skipping Valkey integration test - failed to connect to database")
 	}
+	t.Cleanup(func() { db.Close() })
 
 	// Test the connection
-	ctx := context.Background()
-	if err := db.Ping(ctx); err != nil {
-		t.Skip("Skipping test - database connection failed")
+	if err := db.Ping(context.Background()); err != nil {
+		t.Skip("Skipping Valkey integration test - database connection failed")
 	}
 
 	return db
@@ -224,3 +312,75 @@ func setupTestDatabase(t *testing.T) database.DB {
 func generateTestID() string {
 	return fmt.Sprintf("%d_%d", time.Now().Unix(), rand.Intn(10000))
 }
+
+func TestTieOrdering(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("orders equal scores stored in the same instant by sequence", func(t *testing.T) {
+		at := time.Date(2025, 7, 16, 15, 30, 0, 0, time.UTC)
+		entries := rankHighScores(&models.PlayerHighScores{HighScores: map[string]models.ScoreEntry{
+			"AAA": {Initials: "AAA", Score: 500, Timestamp: at, Sequence: 2},
+			"BBB": {Initials: "BBB", Score: 500, Timestamp: at, Sequence: 7},
+			"CCC": {Initials: "CCC", Score: 500, Timestamp: at.Add(-time.Second), Sequence: 9},
+			"DDD": {Initials: "DDD", Score: 600, Timestamp: at.Add(-time.Hour), Sequence: 1},
+		}})
+
+		var order []string
+		for _, entry := range entries {
+			order = append(order, entry.Initials)
+		}
+		if strings.Join(order, ",") != "DDD,BBB,AAA,CCC" {
+			t.Errorf("Expected DDD,BBB,AAA,CCC, got %v", order)
+		}
+	})
+
+	t.Run("compares client sequences only with each other", func(t *testing.T) {
+		at := time.Date(2025, 7, 16, 15, 30, 0, 0, time.UTC)
+		entries := rankHighScores(&models.PlayerHighScores{HighScores: map[string]models.ScoreEntry{
+			"AAA": {Initials: "AAA", Score: 500, Timestamp: at, Sequence: 1, ClientSequence: 1000000},
+			"BBB": {Initials: "BBB", Score: 500, Timestamp: at, Sequence: 3},
+			"CCC": {Initials: "CCC", Score: 500, Timestamp: at, Sequence: 2, ClientSequence: 5},
+			"DDD": {Initials: "DDD", Score: 500, Timestamp: at, Sequence: 4},
+		}})
+
+		var order []string
+		for _, entry := range entries {
+			order = append(order, entry.Initials)
+		}
+		if strings.Join(order, ",") != "DDD,BBB,AAA,CCC" {
+			t.Errorf("Expected DDD,BBB,AAA,CCC, got %v", order)
+		}
+	})
+
+	t.Run("assigns increasing sequences and keeps the client's apart", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for want := int64(1); want <= 3; want++ {
+			result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+			if err != nil || result.Entry.Sequence != want {
+				t.Fatalf("Expected sequence %d, got %+v (%v)", want, result, err)
+			}
+		}
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "BBB", Score: 200, Sequence: 42})
+		if err != nil || result.Entry.Sequence != 4 || result.Entry.ClientSequence != 42 {
+			t.Fatalf("Expected sequence 4 with the client's 42 kept apart, got %+v (%v)", result, err)
+		}
+		if board, _ := service.GetLeaderboard(ctx, "pacman"); board.Entries[0].Sequence != 4 || board.Entries[0].ClientSequence != 42 {
+			t.Errorf("Expected the high score to keep its sequences, got %+v", board.Entries[0])
+		}
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "BBB", Score: 200, Sequence: -1}); err == nil {
+			t.Error("Expected a negative sequence to be rejected")
+		}
+	})
+
+	t.Run("falls back to the history without counters", func(t *testing.T) {
+		service := NewService(struct{ database.DB }{database.NewFake()})
+		service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100, Sequence: 10})
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "BBB", Score: 100})
+		if err != nil || result.Entry.Sequence != 3 {
+			t.Errorf("Expected one past the history's highest sequence, got %+v (%v)", result, err)
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"sort"
+
+	"rawboard/internal/models"
+)
+
+// playerSessions groups a player's plays into visits: plays submitted with a session ID
+// by that ID, and the rest by pauses of at most models.SessionGap. It returns nil for a
+// player without plays.
+func playerSessions(playerScores []models.ScoreEntry) *models.PlayerSessions {
+	if len(playerScores) == 0 {
+		return nil
+	}
+
+	plays := append([]models.ScoreEntry(nil), playerScores...)
+	sort.SliceStable(plays, func(i, j int) bool {
+		return plays[i].Timestamp.Before(plays[j].Timestamp)
+	})
+
+	var sessions []*models.SessionStats
+	named := make(map[string]*models.SessionStats)
+	var inferred *models.SessionStats
+	for _, play := range plays {
+		var session *models.SessionStats
+		switch {
+		case play.SessionID != "":
+			session = named[play.SessionID]
+			if session == nil {
+				session = &models.SessionStats{SessionID: play.SessionID, Start: play.Timestamp}
+				named[play.SessionID] = session
+				sessions = append(sessions, session)
+			}
+		case inferred != nil && play.Timestamp.Sub(inferred.End) <= models.SessionGap:
+			session = inferred
+		default:
+			inferred = &models.SessionStats{Start: play.Timestamp}
+			session = inferred
+			sessions = append(sessions, session)
+		}
+		addPlay(session, play)
+	}
+
+	result := &models.PlayerSessions{
+		Count:        len(sessions),
+		AveragePlays: float64(len(plays)) / float64(len(sessions)),
+		Recent:       make([]models.SessionStats, 0, min(len(sessions), models.RecentSessions)),
+	}
+	for i, session := range sessions {
+		// Sessions were opened in order, so later ones win ties
+		if i == 0 || session.BestScore >= result.Best.BestScore {
+			result.Best = *session
+		}
+	}
+	for i := len(sessions) - 1; i >= 0 && len(result.Recent) < models.RecentSessions; i-- {
+		result.Recent = append(result.Recent, *sessions[i])
+	}
+	return result
+}
+
+// addPlay adds a play, later than the session's others, to its stats
+func addPlay(session *models.SessionStats, play models.ScoreEntry) {
+	if session.Plays == 0 {
+		session.FirstScore = play.Score
+		session.BestScore = play.Score
+	}
+	session.AverageScore = (session.AverageScore*float64(session.Plays) + float64(play.Score)) / float64(session.Plays+1)
+	session.Plays++
+	session.End = play.Timestamp
+	session.BestScore = max(session.BestScore, play.Score)
+	session.LastScore = play.Score
+	session.Improvement = session.LastScore - session.FirstScore
+}
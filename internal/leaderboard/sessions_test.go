@@ -0,0 +1,84 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestPlayerSessions(t *testing.T) {
+	start := time.Date(2025, 7, 16, 19, 0, 0, 0, time.UTC)
+	play := func(minutes int, score int64, sessionID string) models.ScoreEntry {
+		return models.ScoreEntry{Initials: "AAA", Score: score, Timestamp: start.Add(time.Duration(minutes) * time.Minute), SessionID: sessionID}
+	}
+
+	t.Run("infers sessions from pauses", func(t *testing.T) {
+		sessions := playerSessions([]models.ScoreEntry{
+			play(0, 1000, ""), play(20, 1500, ""), play(45, 1200, ""), // One visit, pauses of 30 minutes or less
+			play(120, 3000, ""), play(125, 2500, ""), // A second visit after a long break
+		})
+		if sessions.Count != 2 || sessions.AveragePlays != 2.5 {
+			t.Fatalf("Expected 2 sessions of 2.5 plays on average, got %+v", sessions)
+		}
+		latest := sessions.Recent[0]
+		if latest.Plays != 2 || latest.FirstScore != 3000 || latest.LastScore != 2500 || latest.Improvement != -500 {
+			t.Errorf("Expected the latest session first, got %+v", latest)
+		}
+		first := sessions.Recent[1]
+		if first.Plays != 3 || first.BestScore != 1500 || first.Improvement != 200 || first.AverageScore != 1233.3333333333333 {
+			t.Errorf("Expected the first session's stats, got %+v", first)
+		}
+		if sessions.Best.BestScore != 3000 || !sessions.Best.Start.Equal(start.Add(120*time.Minute)) {
+			t.Errorf("Expected the second session to be best, got %+v", sessions.Best)
+		}
+	})
+
+	t.Run("groups plays by session ID whatever the pauses", func(t *testing.T) {
+		sessions := playerSessions([]models.ScoreEntry{
+			play(0, 1000, "visit-1"), play(90, 2000, "visit-1"),
+			play(10, 500, "visit-2"),
+			play(15, 700, ""),
+		})
+		if sessions.Count != 3 {
+			t.Fatalf("Expected 3 sessions, got %+v", sessions)
+		}
+		if best := sessions.Best; best.SessionID != "visit-1" || best.Plays != 2 || best.Improvement != 1000 || !best.End.Equal(start.Add(90*time.Minute)) {
+			t.Errorf("Expected visit-1 to span both its plays, got %+v", best)
+		}
+	})
+
+	t.Run("keeps the last ten sessions", func(t *testing.T) {
+		var plays []models.ScoreEntry
+		for i := 0; i < 15; i++ {
+			plays = append(plays, play(i*60, int64(i), ""))
+		}
+		sessions := playerSessions(plays)
+		if sessions.Count != 15 || len(sessions.Recent) != models.RecentSessions || sessions.Recent[0].BestScore != 14 {
+			t.Errorf("Expected the 10 newest of 15 sessions, got %+v", sessions)
+		}
+	})
+
+	t.Run("submissions carry their session into enhanced stats", func(t *testing.T) {
+		ctx := context.Background()
+		service := NewService(database.NewFake())
+		for _, score := range []int64{1000, 1800} {
+			if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: score, SessionID: "cab-1:evening"}); err != nil {
+				t.Fatalf("Submit failed: %v", err)
+			}
+		}
+		if _, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 10, SessionID: "not a session"}); err == nil {
+			t.Error("Expected an invalid session ID to be rejected")
+		}
+
+		stats, err := service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+		if err != nil {
+			t.Fatalf("GetEnhancedPlayerStats failed: %v", err)
+		}
+		if stats.Sessions == nil || stats.Sessions.Count != 1 || stats.Sessions.Best.SessionID != "cab-1:evening" || stats.Sessions.Best.Improvement != 800 {
+			t.Errorf("Expected one named session improving by 800, got %+v", stats.Sessions)
+		}
+	})
+}
@@ -0,0 +1,171 @@
+package leaderboard
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// signingSecretBytes is the entropy in a game's signing secret
+const signingSecretBytes = 32
+
+func signingSecretKey(gameID string) string {
+	return fmt.Sprintf("signing_secret:%s", gameID)
+}
+
+func signatureNoncesKey(gameID string) string {
+	return fmt.Sprintf("signature_nonces:%s", gameID)
+}
+
+// signatureNonces are the nonces a game's signed submissions used recently, with the
+// Unix time each was seen
+type signatureNonces struct {
+	Nonces map[string]int64 `json:"nonces"`
+}
+
+// CreateSigningSecret generates a new signing secret for a game and requires its
+// submissions to be signed with it. It replaces any secret before it at once, so
+// clients signing with the old one are refused until they're given the new one.
+func (s *Service) CreateSigningSecret(ctx context.Context, gameID string) (*models.SigningSecret, error) {
+	buf := make([]byte, signingSecretBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, fmt.Errorf("failed to generate signing secret: %w", err)
+	}
+	secret := &models.SigningSecret{GameID: gameID, Secret: hex.EncodeToString(buf), CreatedAt: time.Now().UTC()}
+
+	if err := s.registerGame(ctx, gameID); err != nil {
+		return nil, fmt.Errorf("failed to register game: %w", err)
+	}
+	if err := s.saveJSON(ctx, signingSecretKey(gameID), secret); err != nil {
+		return nil, fmt.Errorf("failed to save signing secret: %w", err)
+	}
+	if _, err := s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.SignedSubmissions = true
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// DeleteSigningSecret stops requiring a game's submissions to be signed and discards
+// its secret
+func (s *Service) DeleteSigningSecret(ctx context.Context, gameID string) (*models.GameInfo, error) {
+	game, err := s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.SignedSubmissions = false
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// An empty secret reads as none
+	if err := s.saveJSON(ctx, signingSecretKey(gameID), models.SigningSecret{}); err != nil {
+		return nil, fmt.Errorf("failed to delete signing secret: %w", err)
+	}
+	return game, nil
+}
+
+// checkSignature refuses submissions to games requiring signatures unless they carry a
+// fresh signature, made with the game's secret, over the initials and the score as the
+// game formats it, whose nonce hasn't been seen. A nonce is spent once it's verified.
+// The signature must have been made around playedAt, which is now unless the score was
+// forwarded, and no longer ago than nonces are remembered.
+func (s *Service) checkSignature(ctx context.Context, gameID, initials string, score int64, scoring *models.ScoringSettings, signature *models.ScoreSignature, playedAt, now time.Time) error {
+	game, err := s.GetGame(ctx, gameID)
+	if errors.Is(err, ErrGameNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err // Unread settings might require a signature
+	}
+	if !game.Settings.SignedSubmissions {
+		return nil
+	}
+	if signature == nil {
+		return models.ErrSignatureRequired
+	}
+
+	signedAt := time.Unix(signature.Timestamp, 0)
+	if playedAt.Sub(signedAt).Abs() > models.SignatureWindow {
+		return fmt.Errorf("%w: timestamp is more than %s from the server's clock", models.ErrInvalidScoreSignature, models.SignatureWindow)
+	}
+	if now.Sub(signedAt) > models.SignedForwardMaxAge+models.SignatureWindow {
+		return fmt.Errorf("%w: signed more than %s ago", models.ErrInvalidScoreSignature, models.SignedForwardMaxAge)
+	}
+	if len(signature.Nonce) < models.MinNonceLength || len(signature.Nonce) > models.MaxNonceLength {
+		return fmt.Errorf("%w: nonce must be %d to %d characters", models.ErrInvalidScoreSignature, models.MinNonceLength, models.MaxNonceLength)
+	}
+
+	secret, err := s.signingSecret(ctx, gameID)
+	if err != nil {
+		return err
+	}
+	payload := models.ScoreSigningPayload(gameID, initials, scoring.Format(score), signature.Timestamp, signature.Nonce)
+	if !hmac.Equal([]byte(signature.Signature), []byte(models.SignScore(secret, payload))) {
+		return fmt.Errorf("%w: signature doesn't match", models.ErrInvalidScoreSignature)
+	}
+
+	return s.spendNonce(ctx, gameID, signature.Nonce, signedAt, now)
+}
+
+// signingSecret returns the secret a game's submissions are signed with
+func (s *Service) signingSecret(ctx context.Context, gameID string) (string, error) {
+	data, err := s.db.Get(ctx, signingSecretKey(gameID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return "", fmt.Errorf("failed to get signing secret: %w", err)
+	}
+	var secret models.SigningSecret
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), &secret); err != nil {
+			return "", fmt.Errorf("failed to unmarshal signing secret: %w", err)
+		}
+	}
+	if secret.Secret == "" {
+		// Signatures are required but there's nothing they could be checked against
+		return "", fmt.Errorf("%w: the game has no signing secret", models.ErrInvalidScoreSignature)
+	}
+	return secret.Secret, nil
+}
+
+// spendNonce records a nonce as used, refusing one already seen. Nonces are kept by
+// the time they were signed and forgotten once that's a signature window past the
+// oldest timestamp accepted, since their signatures are refused anyway.
+func (s *Service) spendNonce(ctx context.Context, gameID, nonce string, signedAt, now time.Time) error {
+	s.signingMu.Lock()
+	defer s.signingMu.Unlock()
+
+	record := signatureNonces{Nonces: map[string]int64{}}
+	data, err := s.db.Get(ctx, signatureNoncesKey(gameID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to get signature nonces: %w", err) // Forgetting them would allow replays
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), &record); err != nil || record.Nonces == nil {
+			record.Nonces = map[string]int64{}
+		}
+	}
+	if _, seen := record.Nonces[nonce]; seen {
+		return fmt.Errorf("%w: nonce already used", models.ErrInvalidScoreSignature)
+	}
+
+	cutoff := now.Add(-models.SignedForwardMaxAge - 2*models.SignatureWindow).Unix()
+	for used, at := range record.Nonces {
+		if at < cutoff {
+			delete(record.Nonces, used)
+		}
+	}
+	record.Nonces[nonce] = signedAt.Unix()
+	if err := s.saveJSON(ctx, signatureNoncesKey(gameID), record); err != nil {
+		return fmt.Errorf("failed to save signature nonce: %w", err)
+	}
+	return nil
+}
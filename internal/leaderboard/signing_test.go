@@ -0,0 +1,178 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func signedSubmission(secret, gameID, initials, score string, stored int64, at time.Time, nonce string) models.Submission {
+	payload := models.ScoreSigningPayload(gameID, initials, score, at.Unix(), nonce)
+	return models.Submission{
+		Initials:  initials,
+		Score:     stored,
+		Signature: &models.ScoreSignature{Timestamp: at.Unix(), Nonce: nonce, Signature: models.SignScore(secret, payload)},
+	}
+}
+
+func TestSignedSubmissions(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("games without a secret take unsigned scores", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+			t.Fatalf("Expected an unsigned score to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("requires a valid signature once a secret is created", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		secret, err := service.CreateSigningSecret(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+		if len(secret.Secret) != 2*signingSecretBytes {
+			t.Errorf("Expected a %d character hex secret, got %q", 2*signingSecretBytes, secret.Secret)
+		}
+
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); !errors.Is(err, models.ErrSignatureRequired) {
+			t.Errorf("Expected ErrSignatureRequired for an unsigned score, got %v", err)
+		}
+
+		now := time.Now()
+		if _, err := service.Submit(ctx, "pacman", signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, now, "nonce-0001")); err != nil {
+			t.Fatalf("Expected a signed score to be accepted, got %v", err)
+		}
+
+		forged := signedSubmission(secret.Secret, "pacman", "AAA", "100", 999, now, "nonce-0002")
+		if _, err := service.Submit(ctx, "pacman", forged); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a signature over a different score to be refused, got %v", err)
+		}
+
+		wrongKey := signedSubmission("not-the-secret", "pacman", "AAA", "100", 100, now, "nonce-0003")
+		if _, err := service.Submit(ctx, "pacman", wrongKey); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a signature under another secret to be refused, got %v", err)
+		}
+	})
+
+	t.Run("refuses replays and stale timestamps", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		secret, err := service.CreateSigningSecret(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+
+		submission := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, time.Now(), "nonce-0001")
+		if _, err := service.Submit(ctx, "pacman", submission); err != nil {
+			t.Fatalf("Expected the first submission to be accepted, got %v", err)
+		}
+		if _, err := service.Submit(ctx, "pacman", submission); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a replayed nonce to be refused, got %v", err)
+		}
+
+		stale := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, time.Now().Add(-2*models.SignatureWindow), "nonce-0002")
+		if _, err := service.Submit(ctx, "pacman", stale); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a stale timestamp to be refused, got %v", err)
+		}
+
+		short := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, time.Now(), "short")
+		if _, err := service.Submit(ctx, "pacman", short); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a short nonce to be refused, got %v", err)
+		}
+	})
+
+	t.Run("checks forwarded scores against when they were played", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		secret, err := service.CreateSigningSecret(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+
+		playedAt := time.Now().Add(-time.Hour)
+		forwarded := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, playedAt, "nonce-0001")
+		forwarded.PlayedAt = playedAt
+		if _, err := service.Submit(ctx, "pacman", forwarded); err != nil {
+			t.Errorf("Expected a score signed when it was played to be accepted, got %v", err)
+		}
+
+		late := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, playedAt, "nonce-0002")
+		if _, err := service.Submit(ctx, "pacman", late); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected the same signature without played_at to be stale, got %v", err)
+		}
+
+		// Replayed with its played_at long after it was first accepted
+		later := time.Now().Add(30 * time.Minute)
+		if err := service.checkSignature(ctx, "pacman", "AAA", 100, nil, forwarded.Signature, playedAt, later); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a forwarded score replayed later to be refused, got %v", err)
+		}
+
+		old := time.Now().Add(-models.SignedForwardMaxAge - time.Hour)
+		expired := signedSubmission(secret.Secret, "pacman", "AAA", "100", 100, old, "nonce-0003")
+		expired.PlayedAt = old
+		if _, err := service.Submit(ctx, "pacman", expired); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected a score signed before nonces are remembered to be refused, got %v", err)
+		}
+	})
+
+	t.Run("signs the score as the game formats it", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetScoring(ctx, "racer", models.ScoringSettings{Decimals: 2}); err != nil {
+			t.Fatalf("SetScoring failed: %v", err)
+		}
+		secret, err := service.CreateSigningSecret(ctx, "racer")
+		if err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+
+		submission := signedSubmission(secret.Secret, "racer", "AAA", "12.50", 1250, time.Now(), "nonce-0001")
+		if _, err := service.Submit(ctx, "racer", submission); err != nil {
+			t.Errorf("Expected a signature over the formatted score to be accepted, got %v", err)
+		}
+	})
+
+	t.Run("rotating and deleting the secret", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		old, err := service.CreateSigningSecret(ctx, "pacman")
+		if err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+		rotated, err := service.CreateSigningSecret(ctx, "pacman")
+		if err != nil || rotated.Secret == old.Secret {
+			t.Fatalf("Expected a new secret, got %+v, %v", rotated, err)
+		}
+		stale := signedSubmission(old.Secret, "pacman", "AAA", "100", 100, time.Now(), "nonce-0001")
+		if _, err := service.Submit(ctx, "pacman", stale); !errors.Is(err, models.ErrInvalidScoreSignature) {
+			t.Errorf("Expected the old secret to be refused after rotation, got %v", err)
+		}
+
+		game, err := service.DeleteSigningSecret(ctx, "pacman")
+		if err != nil || game.Settings.SignedSubmissions {
+			t.Fatalf("Expected signing to be turned off, got %+v, %v", game, err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+			t.Errorf("Expected unsigned scores once signing is off, got %v", err)
+		}
+	})
+
+	t.Run("refuses scores while the game's settings can't be read", func(t *testing.T) {
+		db := database.NewFake()
+		service := NewService(db)
+		if _, err := service.CreateSigningSecret(ctx, "pacman"); err != nil {
+			t.Fatalf("CreateSigningSecret failed: %v", err)
+		}
+
+		db.FailKey(database.OpGet, "game:pacman", errors.New("connection reset"))
+		now := time.Now()
+		if err := service.checkSignature(ctx, "pacman", "AAA", 100, nil, nil, now, now); err == nil {
+			t.Error("Expected an unsigned score refused while the game can't be read")
+		}
+		db.Heal()
+		if err := service.checkSignature(ctx, "galaga", "AAA", 100, nil, nil, now, now); err != nil {
+			t.Errorf("Expected unregistered games to take unsigned scores, got %v", err)
+		}
+	})
+}
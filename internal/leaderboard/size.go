@@ -0,0 +1,62 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+
+	"rawboard/internal/models"
+)
+
+// LeaderboardSize returns how many entries a game's leaderboard keeps: the game's
+// own override if it has one, otherwise the service default
+func (s *Service) LeaderboardSize(ctx context.Context, gameID string) int {
+	if game, err := s.GetGame(ctx, gameID); err == nil && game.Settings.MaxEntries > 0 {
+		return game.Settings.MaxEntries
+	}
+	return s.maxEntries
+}
+
+// SetLeaderboardSize overrides a game's leaderboard size, or clears the override when
+// maxEntries is 0. The leaderboard is regenerated so the new size applies immediately,
+// with a larger board filled from players' existing high scores.
+func (s *Service) SetLeaderboardSize(ctx context.Context, gameID string, maxEntries int) (*models.LeaderboardResize, error) {
+	if maxEntries < 0 || maxEntries > models.MaxLeaderboardEntries {
+		return nil, fmt.Errorf("leaderboard size must be between 0 and %d", models.MaxLeaderboardEntries)
+	}
+
+	resize := &models.LeaderboardResize{PreviousSize: s.LeaderboardSize(ctx, gameID)}
+	before := 0
+	if board, err := s.getRawLeaderboard(ctx, gameID); err == nil {
+		before = len(board.Entries)
+	}
+
+	game, err := s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.MaxEntries = maxEntries
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resize.GameInfo = *game
+	resize.Size = s.LeaderboardSize(ctx, gameID)
+	if board, err := s.getRawLeaderboard(ctx, gameID); err == nil {
+		resize.Entries = len(board.Entries)
+	}
+	resize.Backfilled = max(resize.Entries-before, 0)
+	return resize, nil
+}
+
+// rebuildLeaderboard regenerates a game's leaderboard from its high scores at its
+// current size
+func (s *Service) rebuildLeaderboard(ctx context.Context, gameID string) error {
+	// Games without scores have no leaderboard to resize yet
+	if _, err := s.getPlayerHighScores(ctx, gameID); err != nil {
+		return nil
+	}
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return fmt.Errorf("failed to resize leaderboard: %w", err)
+	}
+	s.invalidateGame(ctx, gameID)
+	return nil
+}
@@ -0,0 +1,93 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestLeaderboardSize(t *testing.T) {
+	ctx := context.Background()
+	submitPlayers := func(t *testing.T, service *Service, gameID string, count int) {
+		t.Helper()
+		for i := 0; i < count; i++ {
+			if err := service.SubmitScore(ctx, gameID, fmt.Sprintf("P%02d", i), int64(i*100)); err != nil {
+				t.Fatalf("Failed to submit score: %v", err)
+			}
+		}
+	}
+
+	t.Run("uses the configured default size", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db, WithMaxEntries(25))
+
+		gameID := "test_size_default_" + generateTestID()
+		submitPlayers(t, service, gameID, 30)
+
+		leaderboard, err := service.GetLeaderboard(ctx, gameID)
+		if err != nil {
+			t.Fatalf("Failed to get leaderboard: %v", err)
+		}
+		if len(leaderboard.Entries) != 25 {
+			t.Errorf("Expected 25 entries, got %d", len(leaderboard.Entries))
+		}
+	})
+
+	t.Run("per-game overrides resize the leaderboard immediately", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_size_override_" + generateTestID()
+		submitPlayers(t, service, gameID, 15)
+
+		if _, err := service.SetLeaderboardSize(ctx, gameID, 5); err != nil {
+			t.Fatalf("Failed to set leaderboard size: %v", err)
+		}
+		leaderboard, _ := service.GetLeaderboard(ctx, gameID)
+		if len(leaderboard.Entries) != 5 {
+			t.Errorf("Expected 5 entries after shrinking, got %d", len(leaderboard.Entries))
+		}
+
+		if _, err := service.SetLeaderboardSize(ctx, gameID, 0); err != nil {
+			t.Fatalf("Failed to clear leaderboard size: %v", err)
+		}
+		leaderboard, _ = service.GetLeaderboard(ctx, gameID)
+		if len(leaderboard.Entries) != 10 {
+			t.Errorf("Expected the default 10 entries after clearing the override, got %d", len(leaderboard.Entries))
+		}
+	})
+
+	t.Run("raising the size backfills the board from existing high scores", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		gameID := "test_size_backfill_" + generateTestID()
+		submitPlayers(t, service, gameID, 30)
+
+		resize, err := service.SetLeaderboardSize(ctx, gameID, 25)
+		if err != nil {
+			t.Fatalf("Failed to set leaderboard size: %v", err)
+		}
+		if resize.PreviousSize != 10 || resize.Size != 25 || resize.Entries != 25 || resize.Backfilled != 15 {
+			t.Errorf("Expected 15 entries backfilled onto a board of 25, got %+v", resize)
+		}
+		leaderboard, _ := service.GetLeaderboard(ctx, gameID)
+		if len(leaderboard.Entries) != 25 || leaderboard.Entries[24].Score != 500 {
+			t.Errorf("Expected the top 25 high scores without new submissions, got %d entries", len(leaderboard.Entries))
+		}
+
+	})
+
+	t.Run("rejects sizes over the maximum", func(t *testing.T) {
+		db := setupTestDatabase(t)
+		defer db.Close()
+		service := NewService(db)
+
+		if _, err := service.SetLeaderboardSize(ctx, "test_size_max_"+generateTestID(), 101); err == nil {
+			t.Error("Expected an error for a size over 100")
+		}
+	})
+}
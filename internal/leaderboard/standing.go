@@ -0,0 +1,69 @@
+package leaderboard
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"rawboard/internal/models"
+)
+
+// scoreSpread summarizes ranked high scores, one per player, so players who submit
+// often don't weigh more than those who don't
+func scoreSpread(ranked []models.ScoreEntry) models.ScoreSpread {
+	spread := models.ScoreSpread{Players: len(ranked)}
+	if len(ranked) == 0 {
+		return spread
+	}
+
+	var total float64
+	for _, entry := range ranked {
+		total += float64(entry.Score)
+	}
+	spread.Mean = total / float64(len(ranked))
+
+	var squares float64
+	for _, entry := range ranked {
+		diff := float64(entry.Score) - spread.Mean
+		squares += diff * diff
+	}
+	spread.StandardDeviation = math.Sqrt(squares / float64(len(ranked)))
+
+	// Ranked high scores are in descending order
+	middle := len(ranked) / 2
+	if len(ranked)%2 == 1 {
+		spread.Median = float64(ranked[middle].Score)
+	} else {
+		spread.Median = float64(ranked[middle-1].Score+ranked[middle].Score) / 2
+	}
+	return spread
+}
+
+// playerStanding places the high score of initials on deviceID, or on any device if
+// it's empty, within ranked high scores, or returns nil if the player isn't ranked
+func playerStanding(ranked []models.ScoreEntry, initials, deviceID string) *models.PlayerStanding {
+	_, entry, found := findPlayerRank(ranked, initials, deviceID)
+	if !found {
+		return nil
+	}
+
+	standing := &models.PlayerStanding{Percentile: 100, ScoreSpread: scoreSpread(ranked)}
+	if others := len(ranked) - 1; others > 0 {
+		below := len(ranked) - sort.Search(len(ranked), func(i int) bool { return ranked[i].Score < entry.Score })
+		standing.Percentile = math.Round(float64(below)/float64(others)*1000) / 10
+	}
+	if standing.StandardDeviation > 0 {
+		standing.ZScore = (float64(entry.Score) - standing.Mean) / standing.StandardDeviation
+	}
+	return standing
+}
+
+// standingOf returns a player's standing among every ranked player of a game, nil if
+// the game has no ranking or the player isn't in it
+func (s *Service) standingOf(ctx context.Context, gameID, initials string) *models.PlayerStanding {
+	ranking, err := s.getRanking(ctx, gameID)
+	if err != nil {
+		return nil
+	}
+	return playerStanding(ranking.Entries, initials, s.lookupDevice(ctx, gameID))
+}
@@ -0,0 +1,53 @@
+package leaderboard
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"rawboard/internal/database"
+)
+
+func TestStanding(t *testing.T) {
+	ctx := context.Background()
+	service := NewService(database.NewFake())
+
+	// AAA's many low plays count once, through their high score
+	for _, play := range []struct {
+		initials string
+		score    int64
+	}{{"AAA", 100}, {"AAA", 50}, {"AAA", 50}, {"AAA", 400}, {"BBB", 200}, {"CCC", 300}, {"DDD", 400}} {
+		if err := service.SubmitScore(ctx, "pacman", play.initials, play.score); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+	}
+
+	analysis, err := service.GetScoreAnalysis(ctx, "pacman", 10)
+	if err != nil {
+		t.Fatalf("GetScoreAnalysis failed: %v", err)
+	}
+	spread := analysis.HighScores
+	if spread.Players != 4 || spread.Mean != 325 || spread.Median != 350 {
+		t.Errorf("Expected 4 high scores with mean 325 and median 350, got %+v", spread)
+	}
+	if math.Abs(spread.StandardDeviation-82.92) > 0.01 {
+		t.Errorf("Expected a standard deviation of about 82.92, got %v", spread.StandardDeviation)
+	}
+
+	stats, err := service.GetPlayerStats(ctx, "pacman", "CCC")
+	if err != nil {
+		t.Fatalf("GetPlayerStats failed: %v", err)
+	}
+	if stats.Standing == nil || stats.Standing.Percentile != 33.3 || stats.Standing.ZScore >= 0 {
+		t.Errorf("Expected CCC better than 33.3%% and below the mean, got %+v", stats.Standing)
+	}
+
+	enhanced, err := service.GetEnhancedPlayerStats(ctx, "pacman", "AAA", false)
+	if err != nil {
+		t.Fatalf("GetEnhancedPlayerStats failed: %v", err)
+	}
+	// Tied with DDD, AAA is better than BBB and CCC only
+	if enhanced.Standing == nil || enhanced.Standing.Percentile != 66.7 || enhanced.Standing.ZScore <= 0 {
+		t.Errorf("Expected AAA better than 66.7%% and above the mean, got %+v", enhanced.Standing)
+	}
+}
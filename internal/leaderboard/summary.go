@@ -0,0 +1,42 @@
+package leaderboard
+
+import (
+	"context"
+
+	"rawboard/internal/models"
+)
+
+// GetGameSummary returns aggregate, non-identifying numbers for a game
+func (s *Service) GetGameSummary(ctx context.Context, gameID string) (*models.GameSummary, error) {
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &models.GameSummary{
+		GameID:      gameID,
+		PlayerCount: len(highScores.HighScores),
+	}
+
+	for _, entry := range highScores.HighScores {
+		if entry.Score > summary.TopScore {
+			summary.TopScore = entry.Score
+		}
+	}
+
+	// Last activity comes from the full history, since most submissions don't set a high score
+	lastActivity := highScores.Updated
+	if allScores, err := s.getAllScores(ctx, gameID); err == nil {
+		for _, entry := range allScores.Scores {
+			if entry.Timestamp.After(lastActivity) {
+				lastActivity = entry.Timestamp
+			}
+		}
+	}
+	if !lastActivity.IsZero() {
+		lastActivity = lastActivity.UTC()
+		summary.LastActivity = &lastActivity
+	}
+
+	return summary, nil
+}
@@ -0,0 +1,44 @@
+package leaderboard
+
+import (
+	"context"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// SetSunset schedules a game to stop taking submissions at sunset.At, or stops them at
+// once when that has passed. Its leaderboard, history and players stay readable.
+func (s *Service) SetSunset(ctx context.Context, gameID string, sunset models.GameSunset) (*models.GameInfo, error) {
+	if err := sunset.Validate(); err != nil {
+		return nil, err
+	}
+	sunset.At = sunset.At.UTC()
+
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.Sunset = &sunset
+		return nil
+	})
+}
+
+// ClearSunset cancels a game's sunset, taking submissions again if it had passed
+func (s *Service) ClearSunset(ctx context.Context, gameID string) (*models.GameInfo, error) {
+	return s.UpdateGameSettings(ctx, gameID, func(settings *models.GameSettings) error {
+		settings.Sunset = nil
+		return nil
+	})
+}
+
+// checkSunset refuses submissions to a game past its sunset, and otherwise returns the
+// sunset still to come, if any, so submitters can be warned of it
+func (s *Service) checkSunset(ctx context.Context, gameID string, now time.Time) (*models.GameSunset, error) {
+	game, err := s.GetGame(ctx, gameID)
+	if err != nil {
+		return nil, nil // New games have no sunset
+	}
+	sunset := game.Settings.Sunset
+	if sunset.Reached(now) {
+		return nil, &models.GameSunsetError{GameID: gameID, Sunset: *sunset}
+	}
+	return sunset, nil
+}
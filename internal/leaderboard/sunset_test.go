@@ -0,0 +1,73 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestSunset(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("warns of an upcoming sunset", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		at := time.Now().Add(time.Hour)
+		if _, err := service.SetSunset(ctx, "pacman", models.GameSunset{At: at, Message: "moving to pacman-ce"}); err != nil {
+			t.Fatalf("SetSunset failed: %v", err)
+		}
+
+		result, err := service.Submit(ctx, "pacman", models.Submission{Initials: "AAA", Score: 100})
+		if err != nil {
+			t.Fatalf("Expected submissions before the sunset to be accepted, got %v", err)
+		}
+		if result.Sunset == nil || !result.Sunset.At.Equal(at) || result.Sunset.Message != "moving to pacman-ce" {
+			t.Errorf("Expected the result to carry the upcoming sunset, got %+v", result.Sunset)
+		}
+	})
+
+	t.Run("refuses submissions after the sunset and keeps reads", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+			t.Fatalf("SubmitScore failed: %v", err)
+		}
+		if _, err := service.SetSunset(ctx, "pacman", models.GameSunset{At: time.Now().Add(-time.Minute)}); err != nil {
+			t.Fatalf("SetSunset failed: %v", err)
+		}
+
+		err := service.SubmitScore(ctx, "pacman", "BBB", 200)
+		var sunset *models.GameSunsetError
+		if !errors.As(err, &sunset) || !errors.Is(err, models.ErrGameSunset) || sunset.GameID != "pacman" {
+			t.Fatalf("Expected a GameSunsetError, got %v", err)
+		}
+
+		board, err := service.GetLeaderboard(ctx, "pacman")
+		if err != nil || len(board.Entries) != 1 || board.Entries[0].Initials != "AAA" {
+			t.Errorf("Expected the board to stay readable and unchanged, got %+v, %v", board, err)
+		}
+	})
+
+	t.Run("clearing the sunset reopens the game", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetSunset(ctx, "pacman", models.GameSunset{At: time.Now().Add(-time.Minute)}); err != nil {
+			t.Fatalf("SetSunset failed: %v", err)
+		}
+		game, err := service.ClearSunset(ctx, "pacman")
+		if err != nil || game.Settings.Sunset != nil {
+			t.Fatalf("Expected the sunset to be cleared, got %+v, %v", game, err)
+		}
+		if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+			t.Errorf("Expected submissions to be accepted again, got %v", err)
+		}
+	})
+
+	t.Run("rejects sunsets without a time", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.SetSunset(ctx, "pacman", models.GameSunset{}); err == nil {
+			t.Error("Expected a sunset without a time to be rejected")
+		}
+	})
+}
@@ -0,0 +1,225 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Layouts of the UTC hours and days time series buckets are keyed by
+const (
+	hourBucketLayout = "2006-01-02T15"
+	dayBucketLayout  = "2006-01-02"
+)
+
+// timeseriesRecord holds a game's submission counts by UTC hour and day, updated on
+// every submission so charts don't rescan history
+type timeseriesRecord struct {
+	Hours   map[string]*timeseriesTally `json:"hours"`
+	Days    map[string]*timeseriesTally `json:"days"`
+	Updated time.Time                   `json:"updated"`
+}
+
+// timeseriesTally counts the submissions in one bucket
+type timeseriesTally struct {
+	Scores  int      `json:"scores"`
+	Sum     float64  `json:"sum"`
+	Players []string `json:"players"` // Sorted player keys
+}
+
+func timeseriesKey(gameID string) string {
+	return fmt.Sprintf("score_timeseries:%s", gameID)
+}
+
+// add counts a submission by player
+func (t *timeseriesTally) add(player string, score int64) {
+	t.Scores++
+	t.Sum += float64(score)
+	if i := sort.SearchStrings(t.Players, player); i == len(t.Players) || t.Players[i] != player {
+		t.Players = append(t.Players, "")
+		copy(t.Players[i+1:], t.Players[i:])
+		t.Players[i] = player
+	}
+}
+
+// add counts a submission in its hour and day, dropping buckets too old to keep
+func (r *timeseriesRecord) add(settings models.GameSettings, entry models.ScoreEntry) {
+	at := entry.Timestamp.UTC()
+	player := settings.PlayerKey(entry.Initials, entry.DeviceID)
+	for _, series := range []struct {
+		buckets map[string]*timeseriesTally
+		layout  string
+		oldest  time.Time
+	}{
+		{r.Hours, hourBucketLayout, at.Add(-(models.MaxTimeseriesHours - 1) * time.Hour)},
+		{r.Days, dayBucketLayout, at.AddDate(0, 0, -(models.MaxTimeseriesDays - 1))},
+	} {
+		bucket := at.Format(series.layout)
+		tally, ok := series.buckets[bucket]
+		if !ok {
+			tally = &timeseriesTally{}
+			series.buckets[bucket] = tally
+		}
+		tally.add(player, entry.Score)
+
+		// Keys sort chronologically, so older buckets compare lower
+		oldest := series.oldest.Format(series.layout)
+		for start := range series.buckets {
+			if start < oldest {
+				delete(series.buckets, start)
+			}
+		}
+	}
+}
+
+// tallyTimeseries counts a stored submission. Games counted for the first time are
+// counted from history, which already holds the submission.
+func (s *Service) tallyTimeseries(ctx context.Context, gameID string, entry models.ScoreEntry, history *models.AllScoresRecord) {
+	s.timeseriesMu.Lock()
+	defer s.timeseriesMu.Unlock()
+
+	settings := s.gameSettings(ctx, gameID)
+	record, found, err := s.getTimeseries(ctx, gameID)
+	if err == nil {
+		if found {
+			record.add(settings, entry)
+		} else {
+			record = tallyTimeseriesHistory(settings, history.Scores)
+		}
+		record.Updated = time.Now()
+		err = s.saveJSON(ctx, timeseriesKey(gameID), record)
+	}
+	if err != nil {
+		s.log(ctx).Warn("failed to record score time series", "game_id", gameID, "error", err)
+	}
+}
+
+// recountTimeseries replaces a game's counters with a count of scores, for history
+// that was replaced, pruned or moderated rather than submitted
+func (s *Service) recountTimeseries(ctx context.Context, gameID string, scores []models.ScoreEntry) {
+	s.timeseriesMu.Lock()
+	defer s.timeseriesMu.Unlock()
+
+	record := tallyTimeseriesHistory(s.gameSettings(ctx, gameID), scores)
+	record.Updated = time.Now()
+	if err := s.saveJSON(ctx, timeseriesKey(gameID), record); err != nil {
+		s.log(ctx).Warn("failed to recount score time series", "game_id", gameID, "error", err)
+	}
+}
+
+// ScoreTimeseries returns the submissions in each of a game's last count UTC hours or
+// days up to now, from counters kept as scores are submitted. Games with history from
+// before the counters existed are counted from it once, on first use.
+func (s *Service) ScoreTimeseries(ctx context.Context, gameID, bucket string, count int, now time.Time) (*models.ScoreTimeseries, error) {
+	var step func(time.Time, int) time.Time
+	var layout string
+	var start time.Time
+	now = now.UTC()
+	switch bucket {
+	case models.TimeseriesHour:
+		if count < 1 || count > models.MaxTimeseriesHours {
+			return nil, fmt.Errorf("count must be between 1 and %d hours", models.MaxTimeseriesHours)
+		}
+		layout = hourBucketLayout
+		step = func(t time.Time, n int) time.Time { return t.Add(time.Duration(n) * time.Hour) }
+		start = now.Truncate(time.Hour)
+	case models.TimeseriesDay:
+		if count < 1 || count > models.MaxTimeseriesDays {
+			return nil, fmt.Errorf("count must be between 1 and %d days", models.MaxTimeseriesDays)
+		}
+		layout = dayBucketLayout
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) }
+		start = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	default:
+		return nil, fmt.Errorf("bucket must be %s or %s", models.TimeseriesHour, models.TimeseriesDay)
+	}
+
+	record, err := s.timeseries(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	buckets := record.Days
+	if bucket == models.TimeseriesHour {
+		buckets = record.Hours
+	}
+
+	scoring := s.scoring(ctx, gameID)
+	series := &models.ScoreTimeseries{GameID: gameID, Bucket: bucket, Buckets: make([]models.TimeseriesBucket, 0, count)}
+	for at := step(start, 1-count); !at.After(start); at = step(at, 1) {
+		point := models.TimeseriesBucket{Start: at}
+		if tally, ok := buckets[at.Format(layout)]; ok {
+			point.Scores = tally.Scores
+			point.Players = len(tally.Players)
+			point.AverageScore = tally.Sum / float64(tally.Scores)
+			if scoring.Precision() > 0 {
+				point.DisplayAverageScore = scoring.FormatMean(point.AverageScore)
+			}
+		}
+		series.Buckets = append(series.Buckets, point)
+	}
+	return series, nil
+}
+
+// timeseries returns a game's counters, counting its history first if it hasn't been
+func (s *Service) timeseries(ctx context.Context, gameID string) (*timeseriesRecord, error) {
+	s.timeseriesMu.Lock()
+	defer s.timeseriesMu.Unlock()
+
+	record, found, err := s.getTimeseries(ctx, gameID)
+	if err != nil || found {
+		return record, err
+	}
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		return record, nil // No scores yet
+	}
+	record = tallyTimeseriesHistory(s.gameSettings(ctx, gameID), allScores.Scores)
+	record.Updated = time.Now()
+	if err := s.saveJSON(ctx, timeseriesKey(gameID), record); err != nil {
+		s.log(ctx).Warn("failed to save score time series", "game_id", gameID, "error", err)
+	}
+	return record, nil
+}
+
+// tallyTimeseriesHistory counts the submissions in scores, oldest first
+func tallyTimeseriesHistory(settings models.GameSettings, scores []models.ScoreEntry) *timeseriesRecord {
+	sorted := make([]models.ScoreEntry, len(scores))
+	copy(sorted, scores)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Timestamp.Before(sorted[j].Timestamp) })
+
+	record := &timeseriesRecord{Hours: map[string]*timeseriesTally{}, Days: map[string]*timeseriesTally{}}
+	for _, entry := range sorted {
+		record.add(settings, entry)
+	}
+	return record
+}
+
+// getTimeseries reads a game's counters, reporting whether it has any yet
+func (s *Service) getTimeseries(ctx context.Context, gameID string) (*timeseriesRecord, bool, error) {
+	record := &timeseriesRecord{}
+	data, err := s.db.Get(ctx, timeseriesKey(gameID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, false, fmt.Errorf("failed to get score time series: %w", err)
+	}
+	found := err == nil
+	if found {
+		if err := json.Unmarshal([]byte(data), record); err != nil {
+			return nil, false, fmt.Errorf("failed to unmarshal score time series: %w", err)
+		}
+	}
+	if record.Hours == nil {
+		record.Hours = map[string]*timeseriesTally{}
+	}
+	if record.Days == nil {
+		record.Days = map[string]*timeseriesTally{}
+	}
+	return record, found, nil
+}
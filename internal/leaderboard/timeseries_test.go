@@ -0,0 +1,112 @@
+package leaderboard
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestScoreTimeseries(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("counts submissions as they arrive", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		for _, play := range []struct {
+			initials string
+			score    int64
+		}{{"AAA", 100}, {"AAA", 300}, {"BBB", 200}} {
+			if err := service.SubmitScore(ctx, "pacman", play.initials, play.score); err != nil {
+				t.Fatalf("SubmitScore failed: %v", err)
+			}
+		}
+
+		series, err := service.ScoreTimeseries(ctx, "pacman", models.TimeseriesHour, 3, time.Now())
+		if err != nil {
+			t.Fatalf("ScoreTimeseries failed: %v", err)
+		}
+		if len(series.Buckets) != 3 || series.Buckets[0].Scores != 0 {
+			t.Fatalf("Expected 3 hours with the earlier ones empty, got %+v", series.Buckets)
+		}
+		current := series.Buckets[2]
+		if current.Scores != 3 || current.Players != 2 || current.AverageScore != 200 {
+			t.Errorf("Expected 3 scores from 2 players averaging 200, got %+v", current)
+		}
+		if !current.Start.Equal(time.Now().UTC().Truncate(time.Hour)) {
+			t.Errorf("Expected the current hour last, got %v", current.Start)
+		}
+	})
+
+	t.Run("takes moderated scores back out", func(t *testing.T) {
+		service := NewService(database.NewFake(), WithUndoWindow(time.Hour))
+		service.SubmitScore(ctx, "pacman", "AAA", 100)
+		service.SubmitScore(ctx, "pacman", "AAA", 300)
+		service.SubmitScore(ctx, "pacman", "BBB", 200)
+
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if _, err := service.DeleteScore(ctx, "pacman", "AAA", history.Scores[0].Timestamp); err != nil {
+			t.Fatalf("DeleteScore failed: %v", err)
+		}
+		series, _ := service.ScoreTimeseries(ctx, "pacman", models.TimeseriesDay, 1, time.Now())
+		if bucket := series.Buckets[0]; bucket.Scores != 2 || bucket.Players != 2 || bucket.AverageScore != 250 {
+			t.Errorf("Expected 2 scores from 2 players averaging 250 after the deletion, got %+v", bucket)
+		}
+
+		deleted, err := service.DeletePlayer(ctx, "pacman", "BBB")
+		if err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		series, _ = service.ScoreTimeseries(ctx, "pacman", models.TimeseriesDay, 1, time.Now())
+		if bucket := series.Buckets[0]; bucket.Scores != 1 || bucket.Players != 1 {
+			t.Errorf("Expected only AAA's 300 left, got %+v", bucket)
+		}
+
+		if _, err := service.RestoreDeletion(ctx, "pacman", deleted.TombstoneID); err != nil {
+			t.Fatalf("RestoreDeletion failed: %v", err)
+		}
+		series, _ = service.ScoreTimeseries(ctx, "pacman", models.TimeseriesDay, 1, time.Now())
+		if bucket := series.Buckets[0]; bucket.Scores != 2 || bucket.Players != 2 {
+			t.Errorf("Expected BBB's score counted again once restored, got %+v", bucket)
+		}
+	})
+
+	t.Run("counts history from before the counters once", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		day := time.Date(2025, 7, 14, 20, 0, 0, 0, time.UTC)
+		history := &models.AllScoresRecord{GameID: "pacman", Scores: []models.ScoreEntry{
+			{Initials: "AAA", Score: 100, Timestamp: day},
+			{Initials: "BBB", Score: 300, Timestamp: day.Add(time.Hour)},
+			{Initials: "AAA", Score: 500, Timestamp: day.AddDate(0, 0, 2)},
+		}}
+		if err := service.RestoreGame(ctx, history, nil); err != nil {
+			t.Fatalf("RestoreGame failed: %v", err)
+		}
+
+		series, err := service.ScoreTimeseries(ctx, "pacman", models.TimeseriesDay, 3, day.AddDate(0, 0, 2))
+		if err != nil {
+			t.Fatalf("ScoreTimeseries failed: %v", err)
+		}
+		want := []models.TimeseriesBucket{
+			{Start: day.Truncate(24 * time.Hour), Scores: 2, Players: 2, AverageScore: 200},
+			{Start: day.Truncate(24*time.Hour).AddDate(0, 0, 1)},
+			{Start: day.Truncate(24*time.Hour).AddDate(0, 0, 2), Scores: 1, Players: 1, AverageScore: 500},
+		}
+		for i, bucket := range series.Buckets {
+			if !bucket.Start.Equal(want[i].Start) || bucket.Scores != want[i].Scores || bucket.Players != want[i].Players || bucket.AverageScore != want[i].AverageScore {
+				t.Errorf("Bucket %d: expected %+v, got %+v", i, want[i], bucket)
+			}
+		}
+	})
+
+	t.Run("rejects unknown buckets and counts", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		if _, err := service.ScoreTimeseries(ctx, "pacman", "week", 1, time.Now()); err == nil {
+			t.Error("Expected an unknown bucket to be refused")
+		}
+		if _, err := service.ScoreTimeseries(ctx, "pacman", models.TimeseriesHour, models.MaxTimeseriesHours+1, time.Now()); err == nil {
+			t.Error("Expected too many hours to be refused")
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package leaderboard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultUndoWindow is how long moderation deletions can be undone, unless
+// WithUndoWindow says otherwise
+const DefaultUndoWindow = 7 * 24 * time.Hour
+
+// maxTombstones caps the deletions kept per game; the oldest go first
+const maxTombstones = 1000
+
+func tombstonesKey(gameID string) string {
+	return fmt.Sprintf("tombstones:%s", gameID)
+}
+
+// tombstoneRecord is a game's undoable deletions, oldest first
+type tombstoneRecord struct {
+	Tombstones []models.Tombstone `json:"tombstones"`
+}
+
+// WithUndoWindow sets how long moderation deletions keep a tombstone they can be
+// undone with. 0 keeps none.
+func WithUndoWindow(window time.Duration) Option {
+	return func(s *Service) {
+		s.undoWindow = window
+	}
+}
+
+// Tombstones lists a game's deletions that can still be undone, newest first
+func (s *Service) Tombstones(ctx context.Context, gameID string) (*models.TombstonesResponse, error) {
+	record, err := s.getTombstones(ctx, gameID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	tombstones := make([]models.Tombstone, 0, len(record.Tombstones))
+	for i := len(record.Tombstones) - 1; i >= 0; i-- {
+		tombstones = append(tombstones, record.Tombstones[i])
+	}
+	return &models.TombstonesResponse{GameID: gameID, Tombstones: tombstones, Count: len(tombstones)}, nil
+}
+
+// RestoreDeletion undoes a moderation deletion within its undo window: the removed
+// scores go back into history, high scores the deletion replaced come back unless the
+// player has since beaten them, a deleted player's achievements are unlocked again, and
+// the leaderboard is regenerated. The tombstone is used up. Unknown and expired
+// tombstones fail with models.ErrTombstoneNotFound.
+func (s *Service) RestoreDeletion(ctx context.Context, gameID, tombstoneID string) (*models.RestoreResult, error) {
+	tombstone, err := s.takeTombstone(ctx, gameID, tombstoneID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	result := &models.RestoreResult{GameID: gameID, Initials: tombstone.Initials, TombstoneID: tombstone.ID}
+
+	allScores, err := s.getAllScores(ctx, gameID)
+	if err != nil {
+		allScores = &models.AllScoresRecord{GameID: gameID}
+	}
+	stored := make(map[string]bool, len(allScores.Scores))
+	for _, entry := range allScores.Scores {
+		stored[scoreIdentity(entry)] = true
+	}
+	for _, entry := range tombstone.Scores {
+		if !stored[scoreIdentity(entry)] {
+			allScores.Scores = append(allScores.Scores, entry)
+			result.Restored++
+		}
+	}
+	if result.Restored > 0 {
+		sort.SliceStable(allScores.Scores, func(i, j int) bool {
+			return allScores.Scores[i].Timestamp.Before(allScores.Scores[j].Timestamp)
+		})
+		allScores.Updated = time.Now()
+		if err := s.saveJSON(ctx, fmt.Sprintf("all_scores:%s", gameID), allScores); err != nil {
+			return nil, fmt.Errorf("failed to save score history: %w", err)
+		}
+		s.invalidateScoreIndex(ctx, gameID)
+		s.recountTimeseries(ctx, gameID, allScores.Scores)
+	}
+
+	highScores, err := s.getPlayerHighScores(ctx, gameID)
+	if err != nil {
+		highScores = &models.PlayerHighScores{GameID: gameID, HighScores: make(map[string]models.ScoreEntry)}
+	}
+	changed := false
+	for key, entry := range tombstone.HighScores {
+		if current, ok := highScores.HighScores[key]; !ok || entry.Score > current.Score {
+			highScores.HighScores[key] = entry
+			changed = true
+		}
+	}
+	if changed {
+		highScores.Updated = time.Now()
+		if err := s.saveJSON(ctx, fmt.Sprintf("player_high_scores:%s", gameID), highScores); err != nil {
+			return nil, fmt.Errorf("failed to save player high scores: %w", err)
+		}
+	}
+	for _, key := range playerKeys(highScores, tombstone.Initials) {
+		if best := highScores.HighScores[key]; result.HighScore == nil || best.Score > result.HighScore.Score {
+			result.HighScore = &best
+		}
+	}
+
+	if err := s.restoreAchievements(ctx, gameID, tombstone.Initials, tombstone.Achievements); err != nil {
+		return nil, fmt.Errorf("failed to restore achievements: %w", err)
+	}
+	// Tallies are rebuilt from the history, now with the restored plays
+	if err := s.forgetActivity(ctx, gameID, tombstone.Initials); err != nil {
+		return nil, fmt.Errorf("failed to reset player activity: %w", err)
+	}
+
+	if err := s.regenerateFilteredLeaderboard(ctx, gameID); err != nil {
+		return nil, err
+	}
+	s.invalidateGame(ctx, gameID)
+
+	s.log(ctx).Info("moderation deletion undone", "game_id", gameID, "initials", tombstone.Initials, "tombstone_id", tombstone.ID, "restored", result.Restored)
+	return result, nil
+}
+
+// scoreIdentity tells history entries apart, as moderation deletes them
+func scoreIdentity(entry models.ScoreEntry) string {
+	return entry.Initials + "|" + entry.Timestamp.UTC().Format(time.RFC3339Nano)
+}
+
+// saveTombstone keeps what a deletion removed for the undo window, giving the tombstone
+// its ID and expiry. Nothing is kept when undo is off.
+func (s *Service) saveTombstone(ctx context.Context, tombstone *models.Tombstone) error {
+	if s.undoWindow <= 0 {
+		return nil
+	}
+	s.tombstoneMu.Lock()
+	defer s.tombstoneMu.Unlock()
+
+	now := time.Now()
+	record, err := s.getTombstones(ctx, tombstone.GameID, now)
+	if err != nil {
+		return err
+	}
+	tombstone.ID = uuid.New().String()
+	tombstone.DeletedAt = now.UTC()
+	tombstone.ExpiresAt = now.Add(s.undoWindow).UTC()
+	record.Tombstones = append(record.Tombstones, *tombstone)
+	if excess := len(record.Tombstones) - maxTombstones; excess > 0 {
+		record.Tombstones = record.Tombstones[excess:]
+	}
+	return s.saveJSON(ctx, tombstonesKey(tombstone.GameID), record)
+}
+
+// takeTombstone removes and returns an unexpired tombstone
+func (s *Service) takeTombstone(ctx context.Context, gameID, id string, now time.Time) (*models.Tombstone, error) {
+	s.tombstoneMu.Lock()
+	defer s.tombstoneMu.Unlock()
+
+	record, err := s.getTombstones(ctx, gameID, now)
+	if err != nil {
+		return nil, err
+	}
+	for i, tombstone := range record.Tombstones {
+		if tombstone.ID != id {
+			continue
+		}
+		record.Tombstones = append(record.Tombstones[:i], record.Tombstones[i+1:]...)
+		if err := s.saveJSON(ctx, tombstonesKey(gameID), record); err != nil {
+			return nil, fmt.Errorf("failed to save tombstones: %w", err)
+		}
+		return &tombstone, nil
+	}
+	return nil, fmt.Errorf("%w: %s", models.ErrTombstoneNotFound, id)
+}
+
+// getTombstones reads a game's tombstones, leaving out those expired by now
+func (s *Service) getTombstones(ctx context.Context, gameID string, now time.Time) (*tombstoneRecord, error) {
+	record := &tombstoneRecord{}
+	data, err := s.db.Get(ctx, tombstonesKey(gameID))
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, fmt.Errorf("failed to get tombstones: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(data), record); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal tombstones: %w", err)
+		}
+	}
+
+	live := record.Tombstones[:0]
+	for _, tombstone := range record.Tombstones {
+		if now.Before(tombstone.ExpiresAt) {
+			live = append(live, tombstone)
+		}
+	}
+	record.Tombstones = live
+	return record, nil
+}
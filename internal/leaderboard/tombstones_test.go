@@ -0,0 +1,115 @@
+package leaderboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestRestoreDeletion(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("undoing a score deletion brings back the high score", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		service.SubmitScore(ctx, "pacman", "AAA", 9000)
+		service.SubmitScore(ctx, "pacman", "BBB", 5000)
+
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		deleted, err := service.DeleteScore(ctx, "pacman", "AAA", history.Scores[1].Timestamp)
+		if err != nil {
+			t.Fatalf("DeleteScore failed: %v", err)
+		}
+		if deleted.TombstoneID == "" || deleted.RestorableUntil == nil {
+			t.Fatalf("Expected the deletion to report a tombstone, got %+v", deleted)
+		}
+
+		tombstones, err := service.Tombstones(ctx, "pacman")
+		if err != nil || tombstones.Count != 1 || tombstones.Tombstones[0].Kind != models.TombstoneScore {
+			t.Fatalf("Expected one score tombstone, got %+v (%v)", tombstones, err)
+		}
+
+		result, err := service.RestoreDeletion(ctx, "pacman", deleted.TombstoneID)
+		if err != nil {
+			t.Fatalf("RestoreDeletion failed: %v", err)
+		}
+		if result.Restored != 1 || result.HighScore == nil || result.HighScore.Score != 9000 {
+			t.Errorf("Expected the 9000 restored as AAA's high score, got %+v", result)
+		}
+
+		history, _ = service.GetAllScoresForGame(ctx, "pacman")
+		if len(history.Scores) != 3 || history.Scores[1].Score != 9000 {
+			t.Errorf("Expected the score back in its place in history, got %+v", history.Scores)
+		}
+		leaderboard, _ := service.GetLeaderboard(ctx, "pacman")
+		if len(leaderboard.Entries) != 2 || leaderboard.Entries[0].Initials != "AAA" {
+			t.Errorf("Expected AAA to lead again, got %+v", leaderboard.Entries)
+		}
+
+		if _, err := service.RestoreDeletion(ctx, "pacman", deleted.TombstoneID); !errors.Is(err, models.ErrTombstoneNotFound) {
+			t.Errorf("Expected a deletion to be undone only once, got %v", err)
+		}
+	})
+
+	t.Run("undoing a player deletion keeps scores they have beaten since", func(t *testing.T) {
+		service := NewService(database.NewFake())
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+		service.SubmitScore(ctx, "pacman", "AAA", 2000)
+
+		deleted, err := service.DeletePlayer(ctx, "pacman", "AAA")
+		if err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		service.SubmitScore(ctx, "pacman", "AAA", 3000)
+
+		result, err := service.RestoreDeletion(ctx, "pacman", deleted.TombstoneID)
+		if err != nil {
+			t.Fatalf("RestoreDeletion failed: %v", err)
+		}
+		if result.Restored != 2 || result.HighScore == nil || result.HighScore.Score != 3000 {
+			t.Errorf("Expected 2 scores restored under the newer 3000, got %+v", result)
+		}
+		history, _ := service.GetAllScoresForGame(ctx, "pacman")
+		if len(history.Scores) != 3 {
+			t.Errorf("Expected 3 scores in history, got %d", len(history.Scores))
+		}
+	})
+
+	t.Run("unknown and expired tombstones", func(t *testing.T) {
+		service := NewService(database.NewFake(), WithUndoWindow(time.Millisecond))
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+
+		if _, err := service.RestoreDeletion(ctx, "pacman", "nope"); !errors.Is(err, models.ErrTombstoneNotFound) {
+			t.Errorf("Expected ErrTombstoneNotFound for an unknown tombstone, got %v", err)
+		}
+
+		deleted, err := service.DeletePlayer(ctx, "pacman", "AAA")
+		if err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if _, err := service.RestoreDeletion(ctx, "pacman", deleted.TombstoneID); !errors.Is(err, models.ErrTombstoneNotFound) {
+			t.Errorf("Expected ErrTombstoneNotFound past the undo window, got %v", err)
+		}
+		if tombstones, _ := service.Tombstones(ctx, "pacman"); tombstones.Count != 0 {
+			t.Errorf("Expected expired tombstones left out, got %d", tombstones.Count)
+		}
+	})
+
+	t.Run("no tombstones without an undo window", func(t *testing.T) {
+		service := NewService(database.NewFake(), WithUndoWindow(0))
+		service.SubmitScore(ctx, "pacman", "AAA", 1000)
+
+		deleted, err := service.DeletePlayer(ctx, "pacman", "AAA")
+		if err != nil {
+			t.Fatalf("DeletePlayer failed: %v", err)
+		}
+		if deleted.TombstoneID != "" || deleted.RestorableUntil != nil {
+			t.Errorf("Expected no tombstone, got %+v", deleted)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"rawboard/internal/models"
+)
+
+const (
+	// MaxTopPlayersLimit is the largest page of top players a score analysis can return
+	MaxTopPlayersLimit = 100
+	// MaxAnalysisOffset is the deepest top players page a score analysis can start at
+	MaxAnalysisOffset = 10000
+	// topPlayersWorkers bounds how many players' stats are derived concurrently
+	topPlayersWorkers = 8
+)
+
+// computeTopPlayers derives enhanced stats for a page of ranked players in parallel.
+// Stats are computed from the already loaded score history, so no additional database
+// reads are made per player. Results keep the ranking order of the input page.
+func (s *Service) computeTopPlayers(ctx context.Context, page []models.ScoreEntry, rankOffset int, playerMap map[string][]models.ScoreEntry, achievements map[string][]models.Achievement) ([]models.EnhancedPlayerStats, error) {
+	results := make([]*models.EnhancedPlayerStats, len(page))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+
+	workers := topPlayersWorkers
+	if workers > len(page) {
+		workers = len(page)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				entry := page[i]
+				playerScores := playerMap[entry.Initials]
+				if len(playerScores) == 0 {
+					continue
+				}
+
+				rank := rankOffset + i + 1
+				results[i] = s.buildEnhancedPlayerStats(entry.Initials, playerScores, achievements[entry.Initials], &rank, false)
+			}
+		}()
+	}
+
+	// Stream indexes to the workers, stopping early if the request is cancelled
+	var cancelled error
+	for i := range page {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			cancelled = ctx.Err()
+		}
+		if cancelled != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if cancelled != nil {
+		return nil, fmt.Errorf("top player computation cancelled: %w", cancelled)
+	}
+
+	topPlayers := make([]models.EnhancedPlayerStats, 0, len(page))
+	for _, stats := range results {
+		if stats != nil {
+			topPlayers = append(topPlayers, *stats)
+		}
+	}
+
+	return topPlayers, nil
+}
@@ -0,0 +1,65 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+func TestComputeTopPlayers(t *testing.T) {
+	service := &Service{}
+	base := time.Now().Add(-time.Hour)
+
+	// Build 60 players with two scores each, ranked by their best score
+	playerMap := make(map[string][]models.ScoreEntry)
+	highScores := &models.PlayerHighScores{HighScores: make(map[string]models.ScoreEntry)}
+	for i := 0; i < 60; i++ {
+		initials := fmt.Sprintf("P%02d", i)
+		best := models.ScoreEntry{Initials: initials, Score: int64((i + 1) * 1000), Timestamp: base.Add(time.Duration(i) * time.Second)}
+		playerMap[initials] = []models.ScoreEntry{
+			{Initials: initials, Score: best.Score / 2, Timestamp: best.Timestamp.Add(-time.Minute)},
+			best,
+		}
+		highScores.HighScores[initials] = best
+	}
+	ranked := rankHighScores(highScores)
+
+	t.Run("keeps ranking order and absolute ranks for a page", func(t *testing.T) {
+		page := ranked[20:45]
+		topPlayers, err := service.computeTopPlayers(context.Background(), page, 20, playerMap, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(topPlayers) != len(page) {
+			t.Fatalf("Expected %d players, got %d", len(page), len(topPlayers))
+		}
+
+		for i, stats := range topPlayers {
+			if stats.Initials != page[i].Initials {
+				t.Errorf("Position %d: expected %s, got %s", i, page[i].Initials, stats.Initials)
+			}
+			if stats.CurrentRank == nil || *stats.CurrentRank != 21+i {
+				t.Errorf("Position %d: expected rank %d, got %v", i, 21+i, stats.CurrentRank)
+			}
+			if stats.HighScore != page[i].Score {
+				t.Errorf("Position %d: expected high score %d, got %d", i, page[i].Score, stats.HighScore)
+			}
+			if stats.TotalScores != 2 {
+				t.Errorf("Position %d: expected 2 scores, got %d", i, stats.TotalScores)
+			}
+		}
+	})
+
+	t.Run("stops when the request is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := service.computeTopPlayers(ctx, ranked, 0, playerMap, nil); err == nil {
+			t.Error("Expected an error for a cancelled request")
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Output formats
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// contextKey is the type for values this package stores in a context
+type contextKey struct{}
+
+// requestIDKey is the context key for a request's correlation ID
+type requestIDKey struct{}
+
+// MaxRequestIDLength bounds accepted client-supplied request IDs
+const MaxRequestIDLength = 128
+
+// New creates a logger writing to w at the given level ("debug", "info", "warn", "error")
+// in the given format ("json" or "text")
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, opts)), nil
+	case FormatText:
+		return slog.New(slog.NewTextHandler(w, opts)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (expected json or text)", format)
+	}
+}
+
+// ParseLevel converts a level name into a slog level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (expected debug, info, warn or error)", level)
+	}
+}
+
+// Discard returns a logger that drops every record
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// WithContext returns a copy of ctx carrying a request-scoped logger
+func WithContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger carried by ctx, falling back to
+// fallback (or the default logger when fallback is nil)
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok && logger != nil {
+			return logger
+		}
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return slog.Default()
+}
+
+// WithRequestID returns a copy of ctx carrying the request's correlation ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID returns the correlation ID carried by ctx, or "" if there is none
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// ValidRequestID reports whether a client-supplied ID is safe to log and echo back:
+// non-empty, bounded, and limited to characters common in trace and UUID formats
+func ValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > MaxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/', r == '+', r == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
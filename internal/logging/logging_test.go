@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestLogging(t *testing.T) {
+	t.Run("writes JSON records at or above the configured level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, err := New(&buf, "warn", FormatJSON)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		logger.Info("ignored")
+		logger.Warn("kept", "game_id", "pacman")
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("Expected a single JSON record, got %q: %v", buf.String(), err)
+		}
+		if record["msg"] != "kept" || record["game_id"] != "pacman" {
+			t.Errorf("Unexpected record: %v", record)
+		}
+	})
+
+	t.Run("rejects unknown levels and formats", func(t *testing.T) {
+		if _, err := New(&bytes.Buffer{}, "verbose", FormatJSON); err == nil {
+			t.Error("Expected an error for an unknown level")
+		}
+		if _, err := New(&bytes.Buffer{}, "info", "xml"); err == nil {
+			t.Error("Expected an error for an unknown format")
+		}
+	})
+
+	t.Run("prefers the request-scoped logger over the fallback", func(t *testing.T) {
+		fallback := Discard()
+		scoped := Discard().With("request_id", "abc")
+
+		if FromContext(context.Background(), fallback) != fallback {
+			t.Error("Expected fallback logger without a scoped logger")
+		}
+		if FromContext(WithContext(context.Background(), scoped), fallback) != scoped {
+			t.Error("Expected request-scoped logger from context")
+		}
+	})
+	t.Run("carries the request ID", func(t *testing.T) {
+		if RequestID(context.Background()) != "" {
+			t.Error("Expected no request ID in a bare context")
+		}
+		if got := RequestID(WithRequestID(context.Background(), "req-1")); got != "req-1" {
+			t.Errorf("Expected req-1, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,75 @@
+// Package manifest reads YAML deployment manifests for rawboardctl and renders the
+// bootstrap plans computed from them
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"rawboard/internal/models"
+)
+
+// Load parses a manifest: the bootstrap document written as YAML. Unknown fields are
+// rejected so a typo can't silently drop part of the desired state.
+func Load(r io.Reader) (*models.BootstrapDocument, error) {
+	var raw interface{}
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("manifest is empty")
+		}
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+	if _, ok := raw.(map[string]interface{}); !ok {
+		return nil, fmt.Errorf("manifest must be a mapping of games, api_keys and blocked_initials")
+	}
+
+	// Round-trip through JSON so the manifest uses the API's field names
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	var doc models.BootstrapDocument
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &doc, nil
+}
+
+// changeSymbols prefixes each change in a plan, diff-style
+var changeSymbols = map[string]string{
+	models.BootstrapActionCreate:  "+",
+	models.BootstrapActionBlock:   "+",
+	models.BootstrapActionUpdate:  "~",
+	models.BootstrapActionRevoke:  "-",
+	models.BootstrapActionUnblock: "-",
+}
+
+// WritePlan prints each change on its own line followed by a summary
+func WritePlan(w io.Writer, result *models.BootstrapResult) {
+	if len(result.Changes) == 0 {
+		fmt.Fprintln(w, "No changes. The server matches the manifest.")
+		return
+	}
+
+	var added, changed, removed int
+	for _, change := range result.Changes {
+		symbol := changeSymbols[change.Action]
+		switch symbol {
+		case "+":
+			added++
+		case "~":
+			changed++
+		default:
+			removed++
+		}
+		fmt.Fprintf(w, "  %s %s %s (%s)\n", symbol, change.Resource, change.ID, change.Action)
+	}
+	fmt.Fprintf(w, "\n%d to add, %d to change, %d to remove.\n", added, changed, removed)
+}
@@ -0,0 +1,78 @@
+package manifest
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"rawboard/internal/models"
+)
+
+func TestLoad(t *testing.T) {
+	t.Run("reads the bootstrap document from YAML", func(t *testing.T) {
+		doc, err := Load(strings.NewReader(`
+games:
+  - game_id: pacman
+    settings:
+      max_entries: 25
+      retention:
+        history_days: 90
+api_keys:
+  - name: pacman-cabinet-1
+    game_ids: [pacman]
+    scopes: [submit]
+blocked_initials: [ZZZ]
+`))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if len(doc.Games) != 1 || doc.Games[0].Settings.MaxEntries != 25 || doc.Games[0].Settings.Retention.HistoryDays != 90 {
+			t.Errorf("Unexpected games: %+v", doc.Games)
+		}
+		if len(doc.APIKeys) != 1 || doc.APIKeys[0].Scopes[0] != models.ScopeSubmit {
+			t.Errorf("Unexpected keys: %+v", doc.APIKeys)
+		}
+		if len(doc.BlockedInitials) != 1 {
+			t.Errorf("Unexpected blocklist: %v", doc.BlockedInitials)
+		}
+	})
+
+	t.Run("keeps omitted and empty sections distinct", func(t *testing.T) {
+		doc, err := Load(strings.NewReader("api_keys: []\n"))
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		if doc.APIKeys == nil || doc.Games != nil || doc.BlockedInitials != nil {
+			t.Errorf("Expected only api_keys to be declared, got %+v", doc)
+		}
+	})
+
+	t.Run("rejects unknown fields and malformed manifests", func(t *testing.T) {
+		for name, input := range map[string]string{
+			"unknown section": "achievements: []\n",
+			"misspelled":      "games:\n  - gameid: pacman\n",
+			"not a mapping":   "- pacman\n",
+			"empty":           "",
+			"invalid YAML":    "games: [\n",
+		} {
+			if _, err := Load(strings.NewReader(input)); err == nil {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
+
+func TestWritePlan(t *testing.T) {
+	var out bytes.Buffer
+	WritePlan(&out, &models.BootstrapResult{Changes: []models.BootstrapChange{
+		{Resource: models.BootstrapResourceGame, ID: "pacman", Action: models.BootstrapActionCreate},
+		{Resource: models.BootstrapResourceAPIKey, ID: "ci", Action: models.BootstrapActionUpdate},
+		{Resource: models.BootstrapResourceBlockedInitials, ID: "ZZZ", Action: models.BootstrapActionUnblock},
+	}})
+
+	for _, want := range []string{"+ game pacman (create)", "~ api_key ci (update)", "- blocked_initials ZZZ (unblock)", "1 to add, 1 to change, 1 to remove."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected %q in plan:\n%s", want, out.String())
+		}
+	}
+}
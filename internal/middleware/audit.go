@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxAuditedBody is how much of a request body is kept to summarize it; larger
+	// bodies are summarized by size alone
+	maxAuditedBody = 64 << 10
+	// maxAuditedValue is how much of a string field a payload summary keeps
+	maxAuditedValue = 64
+)
+
+// redactedFields are payload fields whose values never reach the audit log
+var redactedFields = []string{"pin", "secret", "password", "token", "signature", "key"}
+
+// AuditWrites records every authenticated write (POST, PUT, PATCH and DELETE) in the
+// audit log with its caller, route, status and a summary of its payload. Writes whose
+// handler recorded its own entry aren't recorded again; that entry names the route too.
+// Unauthenticated and rate-limited requests are not recorded.
+func AuditWrites(log *audit.Log, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method != http.MethodPost && method != http.MethodPut && method != http.MethodPatch && method != http.MethodDelete {
+			c.Next()
+			return
+		}
+
+		body := &auditedBody{ReadCloser: c.Request.Body}
+		if c.Request.Body != nil {
+			c.Request.Body = body
+		}
+		ctx := audit.WithRequest(c.Request.Context(), method, c.FullPath())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		value, exists := c.Get(apikeys.PrincipalContextKey)
+		if !exists || audit.Recorded(ctx) || c.Writer.Status() == http.StatusTooManyRequests {
+			return
+		}
+		principal, ok := value.(*apikeys.Principal)
+		if !ok {
+			return
+		}
+
+		actor := "api_key:" + principal.KeyID
+		if principal.Master {
+			actor = audit.AdminActor
+		}
+		entry := models.AuditEntry{
+			Action: audit.ActionAPIWrite,
+			Actor:  actor,
+			GameID: c.Param("gameId"),
+			Details: map[string]interface{}{
+				"status":  c.Writer.Status(),
+				"payload": body.summary(c.ContentType()),
+			},
+		}
+		// The handler may have moved the request to its tenant's context
+		if err := log.Record(c.Request.Context(), entry); err != nil {
+			logging.FromContext(c.Request.Context(), logger).Error("failed to record audit entry", "action", entry.Action, "error", err)
+		}
+	}
+}
+
+// auditedBody keeps the start of a request body as the handler reads it
+type auditedBody struct {
+	io.ReadCloser
+	kept bytes.Buffer
+	size int
+}
+
+func (b *auditedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := maxAuditedBody - b.kept.Len(); room > 0 {
+		b.kept.Write(p[:min(n, room)])
+	}
+	b.size += n
+	return n, err
+}
+
+// summary describes the payload the handler read: a JSON object's top-level fields,
+// with long strings cut short, secrets redacted and nested values reduced to their
+// size, or else its content type and size
+func (b *auditedBody) summary(contentType string) map[string]interface{} {
+	summary := map[string]interface{}{"bytes": b.size}
+	var fields map[string]interface{}
+	if b.size > maxAuditedBody || !strings.Contains(contentType, "json") || json.Unmarshal(b.kept.Bytes(), &fields) != nil {
+		if b.size > 0 {
+			summary["content_type"] = contentType
+		}
+		return summary
+	}
+
+	for name, value := range fields {
+		summary[name] = summarizeValue(name, value)
+	}
+	summary["bytes"] = b.size
+	return summary
+}
+
+// summarizeValue shortens one payload field for the audit log
+func summarizeValue(name string, value interface{}) interface{} {
+	lower := strings.ToLower(name)
+	for _, redacted := range redactedFields {
+		if strings.Contains(lower, redacted) {
+			return "[redacted]"
+		}
+	}
+
+	switch v := value.(type) {
+	case string:
+		if utf8.RuneCountInString(v) > maxAuditedValue {
+			return string([]rune(v)[:maxAuditedValue]) + "…"
+		}
+		return v
+	case []interface{}:
+		return map[string]interface{}{"items": len(v)}
+	case map[string]interface{}:
+		return map[string]interface{}{"fields": len(v)}
+	default:
+		return v
+	}
+}
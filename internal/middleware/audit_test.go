@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+func TestAuditWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	db := database.NewFake()
+	log := audit.NewLog(db)
+	keys := apikeys.NewStore(db)
+	cabinet, err := keys.Create(ctx, "cabinet", []string{"pacman"}, []string{models.ScopeSubmit})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.Use(AuditWrites(log, nil))
+	auth := APIKeyAuth("master-key", keys)
+	router.POST("/games/:gameId/scores", auth, func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	})
+	router.DELETE("/games/:gameId/scores", auth, func(c *gin.Context) {
+		log.Record(c.Request.Context(), models.AuditEntry{Action: audit.ActionScoreDeleted, Actor: audit.AdminActor})
+		c.Status(http.StatusOK)
+	})
+	router.PUT("/public", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(method, path, key, body string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send("POST", "/games/pacman/scores", cabinet.Key, `{"initials": "AAA", "score": 5000, "signature": "abc", "history": [1, 2]}`)
+	send("DELETE", "/games/pacman/scores", "master-key", "")
+	send("PUT", "/public", "", `{}`)
+
+	entries, err := log.Query(ctx, audit.Filter{})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("Expected the submission and the deletion only, got %+v, %v", entries, err)
+	}
+
+	deleted := entries[0]
+	if deleted.Action != audit.ActionScoreDeleted || deleted.Method != "DELETE" || deleted.Route != "/games/:gameId/scores" {
+		t.Errorf("Expected the handler's own entry to name its route, got %+v", deleted)
+	}
+
+	submitted := entries[1]
+	if submitted.Action != audit.ActionAPIWrite || submitted.Actor != "api_key:"+cabinet.ID || submitted.GameID != "pacman" || submitted.Method != "POST" {
+		t.Errorf("Unexpected submission entry: %+v", submitted)
+	}
+	payload, _ := submitted.Details["payload"].(map[string]interface{})
+	if payload["initials"] != "AAA" || payload["score"] != float64(5000) || payload["signature"] != "[redacted]" {
+		t.Errorf("Expected the payload summarized with its signature redacted, got %v", payload)
+	}
+	if history, _ := payload["history"].(map[string]interface{}); history["items"] != float64(2) {
+		t.Errorf("Expected the history reduced to its size, got %v", payload["history"])
+	}
+	if submitted.Details["status"] != float64(http.StatusCreated) {
+		t.Errorf("Expected the response status, got %v", submitted.Details["status"])
+	}
+}
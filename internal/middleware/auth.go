@@ -1,10 +1,14 @@
 package middleware
 
 import (
+	"crypto/subtle"
 	"net/http"
 	"strings"
+	"time"
 
+	"rawboard/internal/apikeys"
 	"rawboard/internal/handlers"
+	"rawboard/internal/tenants"
 
 	"github.com/gin-gonic/gin"
 )
@@ -48,3 +52,227 @@ func APIKeyMiddleware(validAPIKey string) gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// AuthOption configures APIKeyAuth
+type AuthOption func(*authOptions)
+
+type authOptions struct {
+	limiter  *KeyRateLimiter
+	tenants  *tenants.Registry
+	sessions *apikeys.Sessions
+	jwt      *apikeys.JWTVerifier
+}
+
+// WithRateLimit throttles each authenticated key, or each game when authentication is
+// disabled, with limiter
+func WithRateLimit(limiter *KeyRateLimiter) AuthOption {
+	return func(o *authOptions) {
+		o.limiter = limiter
+	}
+}
+
+// WithSessions also accepts the admin UI's session cookie in place of an API key
+func WithSessions(sessions *apikeys.Sessions) AuthOption {
+	return func(o *authOptions) {
+		o.sessions = sessions
+	}
+}
+
+// WithJWT also accepts identity provider JWTs as bearer tokens, verified by verifier
+func WithJWT(verifier *apikeys.JWTVerifier) AuthOption {
+	return func(o *authOptions) {
+		o.jwt = verifier
+	}
+}
+
+// APIKeyAuth authenticates requests with either the deployment-wide master key or a
+// per-game key from keys, storing the resolved apikeys.Principal in the gin context and
+// the request's context, which then acts for the key's tenant.
+// Scope and game checks are left to the routes, which know what they require.
+// Authentication is disabled when no master key is configured (development).
+func APIKeyAuth(masterKey string, keys *apikeys.Store, opts ...AuthOption) gin.HandlerFunc {
+	var o authOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(c *gin.Context) {
+		if masterKey == "" {
+			o.actAs(c, tenants.FromContext(c.Request.Context()))
+			if o.limiter.allow(c, nil) {
+				c.Next()
+			}
+			return
+		}
+
+		p, ok := o.authenticate(c, masterKey, keys)
+		if !ok {
+			return
+		}
+		tenant, ok := actingTenant(c, p)
+		if !ok {
+			return
+		}
+		c.Set(apikeys.PrincipalContextKey, p)
+		c.Request = c.Request.WithContext(apikeys.WithPrincipal(c.Request.Context(), p))
+		o.actAs(c, tenant)
+		if o.limiter.allow(c, p) {
+			c.Next()
+		}
+	}
+}
+
+// StreamAuth authenticates live stream connections for the route's game. Browsers
+// can't set headers on EventSource or WebSocket requests, so besides an API key
+// scoped to the game it accepts a stream token for that game in ?token=.
+// Authentication is disabled when no master key is configured (development).
+func StreamAuth(masterKey string, keys *apikeys.Store, tokens *apikeys.StreamTokens) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if masterKey == "" {
+			c.Next()
+			return
+		}
+
+		gameID := c.Param("gameId")
+		if token := c.Query("token"); token != "" {
+			claims, err := tokens.Verify(token)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, handlers.NewStandardErrorResponse(c,
+					handlers.ErrorCodeInvalidStreamToken, "Invalid or expired stream token"))
+				c.Abort()
+				return
+			}
+			if claims.GameID != gameID || claims.Tenant != tenants.FromContext(c.Request.Context()) {
+				c.JSON(http.StatusForbidden, handlers.NewStandardErrorResponse(c,
+					handlers.ErrorCodeInsufficientScope, "Stream token is not for this game",
+					map[string]interface{}{"game_id": gameID}))
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		p, ok := authenticate(c, masterKey, keys)
+		if !ok {
+			return
+		}
+		tenant, ok := actingTenant(c, p)
+		if !ok {
+			return
+		}
+		if !p.CanAccessGame(gameID) {
+			c.JSON(http.StatusForbidden, handlers.NewStandardErrorResponse(c,
+				handlers.ErrorCodeInsufficientScope, "API key is not scoped to this game",
+				map[string]interface{}{"game_id": gameID}))
+			c.Abort()
+			return
+		}
+		c.Set(apikeys.PrincipalContextKey, p)
+		c.Request = c.Request.WithContext(tenants.WithTenant(c.Request.Context(), tenant))
+		c.Next()
+	}
+}
+
+// authenticate resolves the request's API key to a principal, or its bearer JWT when
+// they're accepted, or without either its admin session cookie when sessions are
+// accepted
+func (o *authOptions) authenticate(c *gin.Context, masterKey string, keys *apikeys.Store) (*apikeys.Principal, bool) {
+	if o.jwt != nil && c.GetHeader("X-API-Key") == "" {
+		if token := extractAPIKey(c); apikeys.LooksLikeJWT(token) && token != masterKey {
+			return authenticateJWT(c, o.jwt, token)
+		}
+	}
+	if o.sessions != nil && extractAPIKey(c) == "" {
+		if token, err := c.Cookie(apikeys.SessionCookie); err == nil && token != "" {
+			return authenticateSession(c, o.sessions, keys, token)
+		}
+	}
+	return authenticate(c, masterKey, keys)
+}
+
+// authenticateSession resolves an admin session to the principal of the key it was
+// signed in with, writing the error response and aborting when the session has expired
+// or the key has since been revoked
+func authenticateSession(c *gin.Context, sessions *apikeys.Sessions, keys *apikeys.Store, token string) (*apikeys.Principal, bool) {
+	claims, err := sessions.Verify(token)
+	if err == nil {
+		var p *apikeys.Principal
+		if claims.KeyID == "" {
+			p = apikeys.MasterPrincipal()
+		} else if keys != nil {
+			key, err := keys.Get(tenants.WithTenant(c.Request.Context(), claims.Tenant), claims.KeyID)
+			if err == nil && !key.Revoked() {
+				p = apikeys.PrincipalFor(key)
+			}
+		}
+		if p != nil {
+			expiresAt := time.Unix(claims.ExpiresAt, 0).UTC()
+			p.Session = &expiresAt
+			return p, true
+		}
+	}
+
+	c.JSON(http.StatusUnauthorized, handlers.NewStandardErrorResponse(c,
+		handlers.ErrorCodeInvalidSession, "Session expired, sign in again"))
+	c.Abort()
+	return nil, false
+}
+
+// authenticateJWT resolves a bearer JWT to the principal its claims map to, writing the
+// error response and aborting when it's forged, expired or meant for someone else
+func authenticateJWT(c *gin.Context, verifier *apikeys.JWTVerifier, token string) (*apikeys.Principal, bool) {
+	p, err := verifier.Verify(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, handlers.NewStandardErrorResponse(c,
+			handlers.ErrorCodeInvalidToken, "Invalid or expired token",
+			map[string]interface{}{"error": err.Error()}))
+		c.Abort()
+		return nil, false
+	}
+	return p, true
+}
+
+// authenticate resolves the request's API key to a principal, writing the error
+// response and aborting when it's missing or unknown
+func authenticate(c *gin.Context, masterKey string, keys *apikeys.Store) (*apikeys.Principal, bool) {
+	apiKey := extractAPIKey(c)
+	if apiKey == "" {
+		c.JSON(http.StatusUnauthorized, handlers.NewStandardErrorResponse(c,
+			handlers.ErrorCodeAuthenticationRequired, "API key required",
+			map[string]interface{}{
+				"message": "Please provide API key in X-API-Key header or Authorization: Bearer <key>",
+			}))
+		c.Abort()
+		return nil, false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(masterKey)) == 1 {
+		return apikeys.MasterPrincipal(), true
+	}
+
+	if keys != nil {
+		key, err := keys.Resolve(c.Request.Context(), apiKey)
+		if err == nil {
+			return apikeys.PrincipalFor(key), true
+		}
+	}
+
+	c.JSON(http.StatusUnauthorized, handlers.NewStandardErrorResponse(c,
+		handlers.ErrorCodeInvalidAPIKey, "Invalid API key"))
+	c.Abort()
+	return nil, false
+}
+
+// extractAPIKey reads the API key from X-API-Key or an Authorization bearer token
+func extractAPIKey(c *gin.Context) string {
+	if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
+		return apiKey
+	}
+
+	authHeader := c.GetHeader("Authorization")
+	if strings.HasPrefix(authHeader, "Bearer ") {
+		return strings.TrimPrefix(authHeader, "Bearer ")
+	}
+	return ""
+}
@@ -1,12 +1,22 @@
 package middleware
 
 import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
 )
 
 func TestAPIKeyMiddleware(t *testing.T) {
@@ -102,3 +112,226 @@ func TestAPIKeyMiddleware(t *testing.T) {
 		}
 	})
 }
+
+func TestStreamAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	masterKey := "test-master-key"
+	tokens := apikeys.NewStreamTokens(masterKey, time.Minute)
+
+	router := gin.New()
+	router.GET("/games/:gameId/events", StreamAuth(masterKey, nil, tokens), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	pacman, err := tokens.Issue(nil, "", "pacman")
+	if err != nil {
+		t.Fatalf("Issue failed: %v", err)
+	}
+	rotated, _ := apikeys.NewStreamTokens("old-master-key", time.Minute).Issue(nil, "", "pacman")
+
+	tests := []struct {
+		name   string
+		url    string
+		apiKey string
+		want   int
+	}{
+		{"token for the game", "/games/pacman/events?token=" + pacman.Token, "", http.StatusOK},
+		{"token for another game", "/games/tetris/events?token=" + pacman.Token, "", http.StatusForbidden},
+		{"token signed by another master key", "/games/pacman/events?token=" + rotated.Token, "", http.StatusUnauthorized},
+		{"api key header", "/games/pacman/events", masterKey, http.StatusOK},
+		{"invalid api key", "/games/pacman/events", "wrong", http.StatusUnauthorized},
+		{"no credentials", "/games/pacman/events", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.apiKey != "" {
+				req.Header.Set("X-API-Key", tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("open when no master key is configured", func(t *testing.T) {
+		open := gin.New()
+		open.GET("/games/:gameId/events", StreamAuth("", nil, tokens), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		w := httptest.NewRecorder()
+		open.ServeHTTP(w, httptest.NewRequest("GET", "/games/pacman/events", nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200 in development mode, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIKeyAuthSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	masterKey := "test-master-key"
+	keys := apikeys.NewStore(database.NewFake())
+	sessions := apikeys.NewSessions(masterKey, time.Hour)
+	ctx := context.Background()
+
+	created, err := keys.Create(ctx, "ops", []string{models.AllGames}, []string{models.ScopeAdminRead})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	revoked, _ := keys.Create(ctx, "old", []string{models.AllGames}, []string{models.ScopeAdminRead})
+	if _, err := keys.Revoke(ctx, revoked.ID); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/admin", APIKeyAuth(masterKey, keys, WithSessions(sessions)), func(c *gin.Context) {
+		p := c.MustGet(apikeys.PrincipalContextKey).(*apikeys.Principal)
+		if p.Session == nil {
+			t.Errorf("Expected the principal to carry its session's expiry")
+		}
+		c.String(http.StatusOK, p.Name)
+	})
+
+	session := func(p *apikeys.Principal, issuer *apikeys.Sessions) string {
+		token, _, err := issuer.Issue(p)
+		if err != nil {
+			t.Fatalf("Issue failed: %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name   string
+		cookie string
+		want   int
+		body   string
+	}{
+		{"scoped key session", session(apikeys.PrincipalFor(&created.APIKey), sessions), http.StatusOK, "ops"},
+		{"master session", session(apikeys.MasterPrincipal(), sessions), http.StatusOK, "master"},
+		{"revoked key session", session(apikeys.PrincipalFor(&revoked.APIKey), sessions), http.StatusUnauthorized, ""},
+		{"session from another master key", session(nil, apikeys.NewSessions("old-master-key", time.Hour)), http.StatusUnauthorized, ""},
+		{"api key in the cookie", created.Key, http.StatusUnauthorized, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/admin", nil)
+			req.AddCookie(&http.Cookie{Name: apikeys.SessionCookie, Value: tt.cookie})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("Expected to act as %q, got %q", tt.body, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("ignores session cookies unless enabled", func(t *testing.T) {
+		plain := gin.New()
+		plain.GET("/admin", APIKeyAuth(masterKey, keys), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.AddCookie(&http.Cookie{Name: apikeys.SessionCookie, Value: session(nil, sessions)})
+		w := httptest.NewRecorder()
+		plain.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
+
+func TestAPIKeyAuthJWT(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	masterKey := "test-master-key"
+	secret := "0123456789abcdef0123456789abcdef"
+	keys := apikeys.NewStore(database.NewFake())
+	created, err := keys.Create(context.Background(), "cabinet", []string{"pacman"}, []string{models.ScopeSubmit})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	verifier, err := apikeys.NewJWTVerifier(apikeys.JWTConfig{
+		Algorithm: apikeys.JWTAlgorithmHS256, Secret: []byte(secret), Issuer: "https://id.example.com", Audience: "rawboard",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTVerifier failed: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/games/:gameId", APIKeyAuth(masterKey, keys, WithJWT(verifier)), func(c *gin.Context) {
+		p := c.MustGet(apikeys.PrincipalContextKey).(*apikeys.Principal)
+		c.String(http.StatusOK, p.Name)
+	})
+
+	sign := func(claims map[string]interface{}) string {
+		header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+		payload, _ := json.Marshal(claims)
+		signed := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(signed))
+		return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+	}
+	claims := func(exp time.Duration) map[string]interface{} {
+		return map[string]interface{}{
+			"iss": "https://id.example.com", "aud": "rawboard", "sub": "alice",
+			"exp": time.Now().Add(exp).Unix(), "role": "submitter", "games": []string{"pacman"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		bearer string
+		want   int
+		body   string
+	}{
+		{"valid JWT", sign(claims(time.Hour)), http.StatusOK, "alice"},
+		{"expired JWT", sign(claims(-time.Hour)), http.StatusUnauthorized, ""},
+		{"API key alongside JWTs", created.Key, http.StatusOK, "cabinet"},
+		{"master key alongside JWTs", masterKey, http.StatusOK, "master"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/games/pacman", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.bearer)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if tt.body != "" && w.Body.String() != tt.body {
+				t.Errorf("Expected to act as %q, got %q", tt.body, w.Body.String())
+			}
+			if tt.want == http.StatusUnauthorized && !strings.Contains(w.Body.String(), "INVALID_TOKEN") {
+				t.Errorf("Expected INVALID_TOKEN, got %s", w.Body.String())
+			}
+		})
+	}
+
+	t.Run("JWTs aren't accepted unless enabled", func(t *testing.T) {
+		plain := gin.New()
+		plain.GET("/games/:gameId", APIKeyAuth(masterKey, keys), func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/games/pacman", nil)
+		req.Header.Set("Authorization", "Bearer "+sign(claims(time.Hour)))
+		w := httptest.NewRecorder()
+		plain.ServeHTTP(w, req)
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected status 401, got %d", w.Code)
+		}
+	})
+}
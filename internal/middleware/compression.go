@@ -0,0 +1,235 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compression content codings, in order of preference
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// minCompressedSize is the smallest body worth compressing; below it the encoding's
+// framing outweighs the savings
+const minCompressedSize = 1024
+
+var (
+	gzipWriters = sync.Pool{New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(io.Discard, gzip.DefaultCompression)
+		return w
+	}}
+	deflateWriters = sync.Pool{New: func() interface{} {
+		w, _ := zlib.NewWriterLevel(io.Discard, zlib.DefaultCompression)
+		return w
+	}}
+)
+
+// pooledEncoder is a compressor that can be reused for another response
+type pooledEncoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(io.Writer)
+}
+
+// Compression gzips or deflates response bodies of a kilobyte or more for clients that
+// accept it, so cabinets on metered connections download less. Streams are compressed
+// as they're flushed. Bodies that are already encoded, or aren't text, JSON or CSV, are
+// sent as they are, as are WebSocket upgrades.
+func Compression() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		encoding := negotiateEncoding(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{ResponseWriter: c.Writer, encoding: encoding}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// negotiateEncoding picks gzip or deflate from an Accept-Encoding header by quality,
+// preferring gzip, or returns "" when the client accepts neither
+func negotiateEncoding(header string) string {
+	best, bestQuality := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != encodingGzip && name != encodingDeflate {
+			continue
+		}
+
+		quality := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		if quality > bestQuality || (quality == bestQuality && name == encodingGzip) {
+			best, bestQuality = name, quality
+		}
+	}
+	if bestQuality <= 0 {
+		return ""
+	}
+	return best
+}
+
+// compressible reports whether a response with the given headers and status is worth
+// compressing
+func compressible(header http.Header, status int) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified || status == http.StatusPartialContent {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	switch {
+	case strings.HasPrefix(contentType, "text/event-stream"):
+		return false // Browsers' EventSource handles it, but proxies buffer compressed streams
+	case strings.HasPrefix(contentType, "text/"),
+		strings.HasPrefix(contentType, "application/json"),
+		strings.HasPrefix(contentType, "application/problem+json"),
+		strings.HasPrefix(contentType, "application/x-ndjson"),
+		strings.HasPrefix(contentType, "application/javascript"),
+		strings.HasPrefix(contentType, "application/xml"),
+		strings.HasPrefix(contentType, "image/svg+xml"):
+		return true
+	}
+	return false
+}
+
+// compressWriter holds back the start of a body until it's large enough to compress,
+// then compresses the rest as it's written
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+
+	decided bool // Whether the body is compressed is settled
+	buffer  []byte
+	encoder pooledEncoder // Set once compressing
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		// Headers already flushed without an encoding can't gain one
+		if w.ResponseWriter.Written() || !compressible(w.Header(), w.Status()) {
+			w.decided = true
+		} else {
+			w.buffer = append(w.buffer, data...)
+			if len(w.buffer) < minCompressedSize {
+				return len(data), nil
+			}
+			if err := w.startEncoding(); err != nil {
+				return 0, err
+			}
+			return len(data), nil
+		}
+	}
+
+	if w.encoder != nil {
+		return w.encoder.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeaderNow sends the headers without a body, which is never compressed
+func (w *compressWriter) WriteHeaderNow() {
+	if !w.decided {
+		w.decided = true
+		w.flushBuffer()
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush compresses what's held back, however small, so streamed responses arrive as
+// they're written
+func (w *compressWriter) Flush() {
+	if !w.decided && len(w.buffer) > 0 {
+		if err := w.startEncoding(); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	w.ResponseWriter.Flush()
+}
+
+// startEncoding switches the response to the negotiated encoding and compresses what
+// was held back
+func (w *compressWriter) startEncoding() error {
+	w.decided = true
+	header := w.Header()
+	header.Set("Content-Encoding", w.encoding)
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+
+	if w.encoding == encodingGzip {
+		w.encoder = gzipWriters.Get().(*gzip.Writer)
+	} else {
+		w.encoder = deflateWriters.Get().(*zlib.Writer)
+	}
+	w.encoder.Reset(w.ResponseWriter)
+
+	buffered := w.buffer
+	w.buffer = nil
+	_, err := w.encoder.Write(buffered)
+	return err
+}
+
+// flushBuffer writes what was held back uncompressed
+func (w *compressWriter) flushBuffer() {
+	if len(w.buffer) > 0 {
+		buffered := w.buffer
+		w.buffer = nil
+		_, _ = w.ResponseWriter.Write(buffered)
+	}
+}
+
+// finish ends the body: a small one is sent as it is, a compressed one is completed
+// and its encoder returned to its pool
+func (w *compressWriter) finish() {
+	if w.encoder == nil {
+		if len(w.buffer) > 0 {
+			// Only bodies that could have been compressed are held back, so say they vary
+			w.Header().Add("Vary", "Accept-Encoding")
+		}
+		w.flushBuffer()
+		return
+	}
+
+	_ = w.encoder.Close()
+	w.encoder.Reset(io.Discard)
+	switch encoder := w.encoder.(type) {
+	case *gzip.Writer:
+		gzipWriters.Put(encoder)
+	case *zlib.Writer:
+		deflateWriters.Put(encoder)
+	}
+	w.encoder = nil
+}
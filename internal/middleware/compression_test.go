@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCompression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	large := strings.Repeat(`{"initials":"AAA","score":12500},`, 100)
+	router := gin.New()
+	router.Use(Compression())
+	router.GET("/large", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", []byte(large))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/binary", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", []byte(large))
+	})
+	router.GET("/events", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("data: " + large + "\n\n")
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/x-ndjson")
+		_, _ = c.Writer.WriteString("{\"line\":1}\n")
+		c.Writer.Flush()
+		_, _ = c.Writer.WriteString("{\"line\":2}\n")
+	})
+
+	get := func(path, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(t *testing.T, w *httptest.ResponseRecorder) string {
+		var reader io.Reader
+		var err error
+		switch w.Header().Get("Content-Encoding") {
+		case "gzip":
+			reader, err = gzip.NewReader(w.Body)
+		case "deflate":
+			reader, err = zlib.NewReader(w.Body)
+		default:
+			t.Fatalf("Expected a compressed body, got headers %v", w.Header())
+		}
+		if err != nil {
+			t.Fatalf("Failed to open compressed body: %v", err)
+		}
+		body, err := io.ReadAll(reader)
+		if err != nil {
+			t.Fatalf("Failed to decompress body: %v", err)
+		}
+		return string(body)
+	}
+
+	t.Run("gzips large JSON bodies", func(t *testing.T) {
+		w := get("/large", "gzip, deflate, br")
+		if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Fatalf("Expected gzip with Vary, got %v", w.Header())
+		}
+		if w.Body.Len() >= len(large) {
+			t.Errorf("Expected a smaller body, got %d bytes for %d", w.Body.Len(), len(large))
+		}
+		if body := decode(t, w); body != large {
+			t.Errorf("Expected the body to round-trip, got %q", body)
+		}
+	})
+
+	t.Run("deflates when gzip isn't accepted", func(t *testing.T) {
+		w := get("/large", "gzip;q=0, deflate")
+		if body := decode(t, w); w.Header().Get("Content-Encoding") != "deflate" || body != large {
+			t.Errorf("Expected a deflated body, got %v", w.Header())
+		}
+	})
+
+	t.Run("leaves responses alone", func(t *testing.T) {
+		for name, w := range map[string]*httptest.ResponseRecorder{
+			"without Accept-Encoding": get("/large", ""),
+			"with other encodings":    get("/large", "br"),
+			"small bodies":            get("/small", "gzip"),
+			"binary bodies":           get("/binary", "gzip"),
+			"event streams":           get("/events", "gzip"),
+		} {
+			if w.Header().Get("Content-Encoding") != "" {
+				t.Errorf("%s: expected no compression, got %v", name, w.Header())
+			}
+		}
+		if w := get("/small", "gzip"); w.Body.String() != `{"status":"ok"}` {
+			t.Errorf("Expected the small body as written, got %q", w.Body.String())
+		}
+	})
+
+	t.Run("compresses flushed streams however small", func(t *testing.T) {
+		w := get("/stream", "gzip")
+		if body := decode(t, w); body != "{\"line\":1}\n{\"line\":2}\n" {
+			t.Errorf("Expected both lines, got %q", body)
+		}
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                          "",
+		"gzip":                      "gzip",
+		"deflate, gzip":             "gzip",
+		"deflate":                   "deflate",
+		"gzip;q=0.5, deflate":       "deflate",
+		"GZIP; q=1":                 "gzip",
+		"gzip;q=0, deflate;q=0":     "",
+		"br, identity":              "",
+		"gzip;q=bogus, deflate":     "deflate",
+		"deflate;q=0.8, gzip;q=0.8": "gzip",
+	} {
+		if got := negotiateEncoding(header); got != want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
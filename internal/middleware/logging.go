@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log/slog"
+	"time"
+
+	"rawboard/internal/logging"
+	"rawboard/internal/tenants"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestLogger attaches a request-scoped logger carrying request_id, route, game_id
+// and any tenant the path names to the request context and logs each completed request
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		// Prefer the ID chosen by RequestID so logs match the response header
+		requestID := logging.RequestID(c.Request.Context())
+		if requestID == "" {
+			requestID = c.GetHeader(RequestIDHeader)
+			if !logging.ValidRequestID(requestID) {
+				requestID = uuid.New().String()
+			}
+			// Keep the ID for the rest of the request so errors and audit records match
+			c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		fields := []any{
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"route", route,
+		}
+		if gameID := c.Param("gameId"); gameID != "" {
+			fields = append(fields, "game_id", gameID)
+		}
+		if tenant := tenants.FromContext(c.Request.Context()); tenant != "" {
+			fields = append(fields, "tenant", tenant)
+		}
+
+		requestLogger := logger.With(fields...)
+		c.Request = c.Request.WithContext(logging.WithContext(c.Request.Context(), requestLogger))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+			"client_ip", c.ClientIP(),
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+		requestLogger.Log(c.Request.Context(), level, "request completed", attrs...)
+	}
+}
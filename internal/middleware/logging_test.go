@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rawboard/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("logs request-scoped fields for handler and completion records", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _ := logging.New(&buf, "info", logging.FormatJSON)
+
+		router := gin.New()
+		router.Use(RequestLogger(logger))
+		router.GET("/games/:gameId/leaderboard", func(c *gin.Context) {
+			logging.FromContext(c.Request.Context(), nil).Info("handler record")
+			c.Status(http.StatusOK)
+		})
+
+		req := httptest.NewRequest("GET", "/games/pacman/leaderboard", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("Expected 2 log records, got %d: %s", len(lines), buf.String())
+		}
+
+		for _, line := range lines {
+			var record map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("Expected JSON log record, got %q", line)
+			}
+			if record["request_id"] != "req-123" || record["game_id"] != "pacman" ||
+				record["route"] != "/games/:gameId/leaderboard" {
+				t.Errorf("Missing request-scoped fields: %v", record)
+			}
+		}
+	})
+
+	t.Run("generates a request ID and logs server errors at error level", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger, _ := logging.New(&buf, "info", logging.FormatJSON)
+
+		router := gin.New()
+		router.Use(RequestLogger(logger))
+		router.GET("/fail", func(c *gin.Context) {
+			c.Status(http.StatusInternalServerError)
+		})
+
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+			t.Fatalf("Expected JSON log record, got %q", buf.String())
+		}
+		if record["level"] != "ERROR" || record["request_id"] == "" || record["request_id"] == nil {
+			t.Errorf("Unexpected completion record: %v", record)
+		}
+	})
+}
@@ -28,29 +28,39 @@ type RateLimitConfig struct {
 	BurstSize         int
 }
 
-// RateLimitMiddleware implements simple in-memory rate limiting
-// For production, consider using Redis-based rate limiting
+// IPRateLimiter limits each client IP with its own in-memory token bucket
+// Limits are per process; KeyRateLimiter shares them across replicas
+type IPRateLimiter struct {
+	config RateLimitConfig
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewIPRateLimiter creates a per-IP limiter; run Cleanup periodically to forget idle IPs
+func NewIPRateLimiter(config RateLimitConfig) *IPRateLimiter {
+	return &IPRateLimiter{config: config, limiters: make(map[string]*rate.Limiter)}
+}
+
+// RateLimitMiddleware implements simple in-memory rate limiting per client IP
+// Its buckets are never cleaned up; servers use an IPRateLimiter instead
 func RateLimitMiddleware(config RateLimitConfig) gin.HandlerFunc {
-	limiters := make(map[string]*rate.Limiter)
-	mu := sync.RWMutex{}
+	return NewIPRateLimiter(config).Handler()
+}
 
-	return gin.HandlerFunc(func(c *gin.Context) {
+// Handler rejects requests from IPs whose bucket is empty
+func (l *IPRateLimiter) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
 		// Use client IP as the key for rate limiting
 		key := c.ClientIP()
 
-		mu.RLock()
-		limiter, exists := limiters[key]
-		mu.RUnlock()
-
+		l.mu.Lock()
+		limiter, exists := l.limiters[key]
 		if !exists {
-			mu.Lock()
-			// Double-check pattern to avoid race conditions
-			if limiter, exists = limiters[key]; !exists {
-				limiter = rate.NewLimiter(rate.Limit(config.RequestsPerSecond), config.BurstSize)
-				limiters[key] = limiter
-			}
-			mu.Unlock()
+			limiter = rate.NewLimiter(rate.Limit(l.config.RequestsPerSecond), l.config.BurstSize)
+			l.limiters[key] = limiter
 		}
+		l.mu.Unlock()
 
 		if !limiter.Allow() {
 			c.JSON(429, handlers.NewErrorResponse("Rate limit exceeded", map[string]interface{}{
@@ -61,18 +71,27 @@ func RateLimitMiddleware(config RateLimitConfig) gin.HandlerFunc {
 		}
 
 		c.Next()
-	})
+	}
 }
 
-// CleanupOldLimiters removes old rate limiters to prevent memory leaks
-// This should be called periodically in a background goroutine
-func CleanupOldLimiters(limiters map[string]*rate.Limiter, mu *sync.RWMutex) {
-	mu.Lock()
-	defer mu.Unlock()
+// Cleanup forgets IPs whose bucket has refilled, so the limiter doesn't grow with every
+// client ever seen. A full bucket is the same as a new one, so no limit is lost.
+func (l *IPRateLimiter) Cleanup(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	// Simple cleanup: clear all limiters every hour
-	// In production, implement more sophisticated cleanup based on last access time
-	for key := range limiters {
-		delete(limiters, key)
+	pruneLimiters(l.limiters, time.Now())
+	return nil
+}
+
+// pruneLimiters removes the limiters whose bucket is full at now, returning how many
+func pruneLimiters(limiters map[string]*rate.Limiter, now time.Time) int {
+	var removed int
+	for key, limiter := range limiters {
+		if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+			delete(limiters, key)
+			removed++
+		}
 	}
+	return removed
 }
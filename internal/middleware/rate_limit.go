@@ -0,0 +1,158 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/handlers"
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitKeyPrefix namespaces rate limit buckets in the database
+const rateLimitKeyPrefix = "ratelimit:"
+
+// KeyRateLimiter throttles API callers with one token bucket per API key, or per game
+// when authentication is disabled. Buckets live in the database when it supports them,
+// so every replica behind a load balancer draws from the same bucket.
+type KeyRateLimiter struct {
+	buckets   database.RateLimiter // nil when the database can't keep buckets
+	defaults  models.RateLimit
+	overrides map[string]models.RateLimit // By key ID, key name or game ID
+	logger    *slog.Logger
+
+	mu    sync.Mutex
+	local map[string]*rate.Limiter // Per-process buckets when buckets is nil
+}
+
+// NewKeyRateLimiter creates a limiter applying defaults to every caller without an
+// override. Databases that can't keep buckets fall back to per-process limits.
+func NewKeyRateLimiter(db database.DB, defaults models.RateLimit, overrides map[string]models.RateLimit, logger *slog.Logger) *KeyRateLimiter {
+	buckets, _ := db.(database.RateLimiter)
+	return &KeyRateLimiter{
+		buckets:   buckets,
+		defaults:  defaults,
+		overrides: overrides,
+		logger:    logger,
+		local:     make(map[string]*rate.Limiter),
+	}
+}
+
+// Distributed reports whether limits are shared across replicas
+func (l *KeyRateLimiter) Distributed() bool {
+	return l.buckets != nil
+}
+
+// allow takes a token from the caller's bucket, writing a 429 and aborting when it's
+// empty. A nil limiter allows everything.
+func (l *KeyRateLimiter) allow(c *gin.Context, p *apikeys.Principal) bool {
+	if l == nil {
+		return true
+	}
+	bucket, limit := l.limitFor(c, p)
+	if limit.RequestsPerSecond <= 0 {
+		return true
+	}
+
+	allowed, retryAfter := l.take(c, bucket, limit)
+	if allowed {
+		return true
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.JSON(http.StatusTooManyRequests, handlers.NewStandardErrorResponse(c,
+		handlers.ErrorCodeRateLimitExceeded, "Rate limit exceeded",
+		map[string]interface{}{
+			"retry_after_seconds": seconds,
+			"requests_per_second": limit.RequestsPerSecond,
+			"burst":               limit.Burst,
+		}))
+	c.Abort()
+	return false
+}
+
+// limitFor returns the caller's bucket and its limit
+func (l *KeyRateLimiter) limitFor(c *gin.Context, p *apikeys.Principal) (string, models.RateLimit) {
+	switch {
+	case p != nil && p.Master:
+		return l.limit("key:master", "master")
+	case p != nil && p.RateLimit != nil:
+		return "key:" + p.KeyID, *p.RateLimit
+	case p != nil:
+		return l.limit("key:"+p.KeyID, p.KeyID, p.Name)
+	case c.Param("gameId") != "":
+		gameID := c.Param("gameId")
+		return l.limit("game:"+gameID, gameID)
+	default:
+		return l.limit("ip:" + c.ClientIP())
+	}
+}
+
+// limit returns bucket with the first override matching names, or the default limit
+func (l *KeyRateLimiter) limit(bucket string, names ...string) (string, models.RateLimit) {
+	for _, name := range names {
+		if limit, ok := l.overrides[name]; ok {
+			return bucket, limit
+		}
+	}
+	return bucket, l.defaults
+}
+
+// take takes a token from bucket. If the database fails the request is allowed, since
+// refusing traffic over a limiter outage would turn it into a full outage.
+func (l *KeyRateLimiter) take(c *gin.Context, bucket string, limit models.RateLimit) (bool, time.Duration) {
+	if l.buckets == nil {
+		return l.takeLocal(bucket, limit)
+	}
+
+	ctx := c.Request.Context()
+	allowed, retryAfter, err := l.buckets.TakeToken(ctx, rateLimitKeyPrefix+bucket, limit.RequestsPerSecond, limit.Burst)
+	if err != nil {
+		logging.FromContext(ctx, l.logger).Warn("rate limit check failed, allowing request", "bucket", bucket, "error", err)
+		return true, 0
+	}
+	return allowed, retryAfter
+}
+
+// Cleanup forgets this process's buckets once they have refilled. Buckets kept in the
+// database expire there on their own.
+func (l *KeyRateLimiter) Cleanup(context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	pruneLimiters(l.local, time.Now())
+	return nil
+}
+
+// takeLocal takes a token from this process's bucket
+func (l *KeyRateLimiter) takeLocal(bucket string, limit models.RateLimit) (bool, time.Duration) {
+	l.mu.Lock()
+	limiter, ok := l.local[bucket]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), limit.Burst)
+		l.local[bucket] = limiter
+	} else if limiter.Limit() != rate.Limit(limit.RequestsPerSecond) || limiter.Burst() != limit.Burst {
+		// The key's limit was changed since the bucket was made
+		limiter.SetLimit(rate.Limit(limit.RequestsPerSecond))
+		limiter.SetBurst(limit.Burst)
+	}
+	l.mu.Unlock()
+
+	reservation := limiter.Reserve()
+	if delay := reservation.Delay(); delay > 0 {
+		reservation.Cancel()
+		return false, delay
+	}
+	return true, 0
+}
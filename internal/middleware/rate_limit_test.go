@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+func TestKeyRateLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const masterKey = "test-master-key"
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	slow := models.RateLimit{RequestsPerSecond: 0.001, Burst: 2} // No refills during a test
+
+	// newRouter returns a replica authenticating with masterKey (or none) and limiting with db
+	newRouter := func(db database.DB, keys *apikeys.Store, masterKey string, overrides map[string]models.RateLimit) *gin.Engine {
+		limiter := NewKeyRateLimiter(db, slow, overrides, logger)
+		router := gin.New()
+		router.Use(APIKeyAuth(masterKey, keys, WithRateLimit(limiter)))
+		router.GET("/games/:gameId/scores", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+		})
+		return router
+	}
+	// get requests the route with key, returning the response
+	get := func(router *gin.Engine, gameID, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/games/"+gameID+"/scores", nil)
+		if key != "" {
+			req.Header.Set("X-API-Key", key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("limits each key separately, across replicas", func(t *testing.T) {
+		db := database.NewFake()
+		keys := apikeys.NewStore(db)
+		cabinet, err := keys.Create(context.Background(), "cabinet", []string{"pacman"}, []string{models.ScopeSubmit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, second := newRouter(db, keys, masterKey, nil), newRouter(db, keys, masterKey, nil)
+
+		for i, router := range []*gin.Engine{first, second} {
+			if w := get(router, "pacman", cabinet.Key); w.Code != http.StatusOK {
+				t.Fatalf("request %d: expected 200 within the burst, got %d", i+1, w.Code)
+			}
+		}
+		w := get(first, "pacman", cabinet.Key)
+		if w.Code != http.StatusTooManyRequests {
+			t.Fatalf("Expected the shared bucket to be empty, got %d", w.Code)
+		}
+		if w.Header().Get("Retry-After") == "" {
+			t.Error("Expected a Retry-After header")
+		}
+
+		if w := get(second, "pacman", masterKey); w.Code != http.StatusOK {
+			t.Errorf("Expected the master key to have its own bucket, got %d", w.Code)
+		}
+		if db.Calls(database.OpTakeToken) != 4 {
+			t.Errorf("Expected every request to use the database bucket, got %d calls", db.Calls(database.OpTakeToken))
+		}
+	})
+
+	t.Run("applies overrides by key name", func(t *testing.T) {
+		db := database.NewFake()
+		keys := apikeys.NewStore(db)
+		desk, err := keys.Create(context.Background(), "tournament-desk", []string{models.AllGames}, []string{models.ScopeSubmit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		router := newRouter(db, keys, masterKey, map[string]models.RateLimit{
+			"tournament-desk": {RequestsPerSecond: 0.001, Burst: 5},
+		})
+
+		for i := 0; i < 5; i++ {
+			if w := get(router, "pacman", desk.Key); w.Code != http.StatusOK {
+				t.Fatalf("request %d: expected the raised burst to allow it, got %d", i+1, w.Code)
+			}
+		}
+		if w := get(router, "pacman", desk.Key); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected the raised burst to run out, got %d", w.Code)
+		}
+	})
+
+	t.Run("prefers a key's own limit, where 0 exempts it", func(t *testing.T) {
+		ctx := context.Background()
+		db := database.NewFake()
+		keys := apikeys.NewStore(db)
+		desk, err := keys.Create(ctx, "tournament-desk", []string{models.AllGames}, []string{models.ScopeSubmit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := keys.SetRateLimit(ctx, desk.ID, &models.RateLimit{}); err != nil {
+			t.Fatal(err)
+		}
+		router := newRouter(db, keys, masterKey, map[string]models.RateLimit{
+			"tournament-desk": {RequestsPerSecond: 0.001, Burst: 1},
+		})
+
+		for i := 0; i < 10; i++ {
+			if w := get(router, "pacman", desk.Key); w.Code != http.StatusOK {
+				t.Fatalf("request %d: expected the exempt key to be allowed, got %d", i+1, w.Code)
+			}
+		}
+
+		if _, err := keys.SetRateLimit(ctx, desk.ID, &models.RateLimit{RequestsPerSecond: 0.001, Burst: 3}); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 3; i++ {
+			if w := get(router, "pacman", desk.Key); w.Code != http.StatusOK {
+				t.Fatalf("request %d: expected the key's burst to allow it, got %d", i+1, w.Code)
+			}
+		}
+		if w := get(router, "pacman", desk.Key); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected the key's burst to run out, got %d", w.Code)
+		}
+	})
+
+	t.Run("limits each game when authentication is disabled", func(t *testing.T) {
+		router := newRouter(database.NewFake(), nil, "", map[string]models.RateLimit{
+			"galaga": {RequestsPerSecond: 0.001, Burst: 1},
+		})
+
+		get(router, "pacman", "")
+		get(router, "pacman", "")
+		if w := get(router, "pacman", ""); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected pacman's bucket to be empty, got %d", w.Code)
+		}
+		if w := get(router, "galaga", ""); w.Code != http.StatusOK {
+			t.Errorf("Expected galaga to have its own bucket, got %d", w.Code)
+		}
+		if w := get(router, "galaga", ""); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected galaga's override to apply, got %d", w.Code)
+		}
+	})
+
+	t.Run("allows requests when the database fails", func(t *testing.T) {
+		db := database.NewFake()
+		db.Fail(database.OpTakeToken, errors.New("connection refused"))
+		router := newRouter(db, nil, "", nil)
+
+		for i := 0; i < 3; i++ {
+			if w := get(router, "pacman", ""); w.Code != http.StatusOK {
+				t.Fatalf("request %d: expected the limiter to fail open, got %d", i+1, w.Code)
+			}
+		}
+	})
+
+	t.Run("falls back to per-process buckets", func(t *testing.T) {
+		db := struct{ database.DB }{database.NewFake()} // Only the DB interface
+		limiter := NewKeyRateLimiter(db, slow, nil, logger)
+		if limiter.Distributed() {
+			t.Fatal("Expected a database without buckets to be limited locally")
+		}
+		router := newRouter(db, nil, "", nil)
+
+		get(router, "pacman", "")
+		get(router, "pacman", "")
+		if w := get(router, "pacman", ""); w.Code != http.StatusTooManyRequests {
+			t.Errorf("Expected the local bucket to be empty, got %d", w.Code)
+		}
+	})
+}
+
+func TestPruneLimiters(t *testing.T) {
+	now := time.Now()
+	used := rate.NewLimiter(1, 2)
+	used.AllowN(now, 2)
+	limiters := map[string]*rate.Limiter{
+		"idle": rate.NewLimiter(1, 2),
+		"used": used,
+	}
+
+	if removed := pruneLimiters(limiters, now); removed != 1 || limiters["used"] == nil {
+		t.Fatalf("Expected only the full bucket to be pruned, removed %d leaving %v", removed, limiters)
+	}
+	if removed := pruneLimiters(limiters, now.Add(2*time.Second)); removed != 1 || len(limiters) != 0 {
+		t.Errorf("Expected the refilled bucket to be pruned, removed %d leaving %v", removed, limiters)
+	}
+}
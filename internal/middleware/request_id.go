@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"rawboard/internal/logging"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries a request's correlation ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key holding the request ID
+const RequestIDKey = "request_id"
+
+// RequestID accepts the caller's X-Request-ID, or generates one when it is missing or
+// malformed, and returns it on the response. The ID is stored in the gin context and
+// the request context so logs and error responses carry the same value.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !logging.ValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"rawboard/internal/handlers"
+	"rawboard/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// newRouter returns a router that echoes the request ID on /ok and fails on /fail
+	newRouter := func(logger *slog.Logger) *gin.Engine {
+		router := gin.New()
+		router.Use(RequestID())
+		if logger != nil {
+			router.Use(RequestLogger(logger))
+		}
+		router.GET("/ok", func(c *gin.Context) {
+			c.String(http.StatusOK, c.GetString(RequestIDKey))
+		})
+		router.GET("/fail", func(c *gin.Context) {
+			c.JSON(http.StatusBadRequest, handlers.NewStandardErrorResponse(c, handlers.ErrorCodeInvalidRequest, "bad"))
+		})
+		return router
+	}
+
+	t.Run("echoes a valid caller ID on responses, errors and logs", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger, _ := logging.New(&logs, "info", logging.FormatJSON)
+		router := newRouter(logger)
+
+		req := httptest.NewRequest("GET", "/fail", nil)
+		req.Header.Set(RequestIDHeader, "trace-abc.123")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if got := w.Header().Get(RequestIDHeader); got != "trace-abc.123" {
+			t.Errorf("Expected the caller's ID in the response header, got %q", got)
+		}
+		var body handlers.StandardErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Meta.RequestID != "trace-abc.123" {
+			t.Errorf("Expected the error to carry the caller's ID, got %q", body.Meta.RequestID)
+		}
+		if !strings.Contains(logs.String(), `"request_id":"trace-abc.123"`) {
+			t.Errorf("Expected the log record to carry the caller's ID, got %s", logs.String())
+		}
+	})
+
+	t.Run("generates an ID for successful responses", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		newRouter(nil).ServeHTTP(w, httptest.NewRequest("GET", "/ok", nil))
+
+		got := w.Header().Get(RequestIDHeader)
+		if len(got) != 36 || w.Body.String() != got {
+			t.Errorf("Expected a generated UUID in the header and gin context, got %q and %q", got, w.Body.String())
+		}
+	})
+
+	t.Run("replaces malformed caller IDs", func(t *testing.T) {
+		for _, id := range []string{"has space", "line\nbreak", `{"json":1}`, strings.Repeat("a", logging.MaxRequestIDLength+1)} {
+			req := httptest.NewRequest("GET", "/ok", nil)
+			req.Header.Set(RequestIDHeader, id)
+			w := httptest.NewRecorder()
+			newRouter(nil).ServeHTTP(w, req)
+
+			if got := w.Header().Get(RequestIDHeader); got == id || len(got) != 36 {
+				t.Errorf("%q: expected a generated ID, got %q", id, got)
+			}
+		}
+	})
+
+	t.Run("errors on routes that bypass the middleware still get an ID", func(t *testing.T) {
+		router := gin.New()
+		router.GET("/fail", func(c *gin.Context) {
+			c.JSON(http.StatusBadRequest, handlers.NewStandardErrorResponse(c, handlers.ErrorCodeInvalidRequest, "bad"))
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/fail", nil))
+		var body handlers.StandardErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || len(body.Meta.RequestID) != 36 {
+			t.Errorf("Expected a generated request ID, got %s", w.Body.String())
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/handlers"
+	"rawboard/internal/logging"
+	"rawboard/internal/tenants"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tenantPathPrefix starts the paths that name the tenant a request acts for
+const tenantPathPrefix = "/api/v1/tenants/"
+
+// TenantPaths serves /api/v1/tenants/:tenantId/... as the /api/v1/... route it wraps,
+// acting for that tenant. It sits in front of the router so every route is reachable
+// per tenant without being registered twice; APIKeyAuth then checks the caller's key
+// may act there. Public routes act for whichever tenant the path names.
+func TenantPaths(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rest, ok := strings.CutPrefix(r.URL.Path, tenantPathPrefix)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tenant, route, ok := strings.Cut(rest, "/")
+		if !ok {
+			next.ServeHTTP(w, r) // No route beneath the tenant
+			return
+		}
+		if err := tenants.Validate(tenant); err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(handlers.NewValidationErrorResponse(nil,
+				"tenantId", tenant, "1 to 50 lowercase letters, digits, hyphens or underscores"))
+			return
+		}
+
+		scoped := r.Clone(tenants.WithTenant(r.Context(), tenant))
+		scoped.URL.Path = "/api/v1/" + route
+		if r.URL.RawPath != "" {
+			scoped.URL.RawPath = "/api/v1/" + strings.TrimPrefix(r.URL.RawPath, tenantPathPrefix+tenant+"/")
+		}
+		next.ServeHTTP(w, scoped)
+	})
+}
+
+// WithTenants registers the tenants authenticated requests act for in registry, so
+// background jobs visit them
+func WithTenants(registry *tenants.Registry) AuthOption {
+	return func(o *authOptions) {
+		o.tenants = registry
+	}
+}
+
+// actingTenant returns the tenant an authenticated request acts for: the key's own, or
+// for the master key whichever the path names. A key used under another tenant's path
+// is refused with 403.
+func actingTenant(c *gin.Context, p *apikeys.Principal) (string, bool) {
+	requested := tenants.FromContext(c.Request.Context())
+	if p.Master || requested == p.Tenant {
+		return requested, true
+	}
+	if requested == "" {
+		return p.Tenant, true
+	}
+
+	c.JSON(http.StatusForbidden, handlers.NewStandardErrorResponse(c,
+		handlers.ErrorCodeTenantMismatch, "API key belongs to another tenant",
+		map[string]interface{}{"tenant": requested}))
+	c.Abort()
+	return "", false
+}
+
+// actAs makes the request act for tenant, registering it with the registry if any
+func (o *authOptions) actAs(c *gin.Context, tenant string) {
+	ctx := tenants.WithTenant(c.Request.Context(), tenant)
+	c.Request = c.Request.WithContext(ctx)
+	if o.tenants == nil {
+		return
+	}
+	if err := o.tenants.Register(ctx, tenant); err != nil {
+		logging.FromContext(ctx, slog.Default()).Warn("failed to register tenant, background jobs may skip it", "tenant", tenant, "error", err)
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+	"rawboard/internal/tenants"
+)
+
+func TestTenants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	ctx := context.Background()
+	masterKey := "test-master-key"
+	db := database.NewFake()
+	keys := apikeys.NewStore(db)
+	registry := tenants.NewRegistry(db)
+
+	acmeKey, err := keys.Create(tenants.WithTenant(ctx, "acme"), "acme", []string{models.AllGames}, apikeys.ValidScopes())
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+	defaultKey, err := keys.Create(ctx, "default", []string{models.AllGames}, apikeys.ValidScopes())
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	router := gin.New()
+	router.GET("/api/v1/whoami", APIKeyAuth(masterKey, keys, WithTenants(registry)), func(c *gin.Context) {
+		c.String(http.StatusOK, tenants.FromContext(c.Request.Context()))
+	})
+	handler := TenantPaths(router)
+
+	tests := []struct {
+		name   string
+		path   string
+		apiKey string
+		want   int
+		tenant string
+	}{
+		{"master key in a tenant path", "/api/v1/tenants/acme/whoami", masterKey, http.StatusOK, "acme"},
+		{"master key without a tenant", "/api/v1/whoami", masterKey, http.StatusOK, ""},
+		{"tenant key without a tenant path", "/api/v1/whoami", acmeKey.Key, http.StatusOK, "acme"},
+		{"tenant key in its own path", "/api/v1/tenants/acme/whoami", acmeKey.Key, http.StatusOK, "acme"},
+		{"tenant key in another tenant's path", "/api/v1/tenants/globex/whoami", acmeKey.Key, http.StatusForbidden, ""},
+		{"default key in a tenant path", "/api/v1/tenants/acme/whoami", defaultKey.Key, http.StatusForbidden, ""},
+		{"invalid tenant ID", "/api/v1/tenants/Acme/whoami", masterKey, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.path, nil)
+			req.Header.Set("X-API-Key", tt.apiKey)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("Expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			if w.Code == http.StatusOK && w.Body.String() != tt.tenant {
+				t.Errorf("Expected to act for %q, got %q", tt.tenant, w.Body.String())
+			}
+		})
+	}
+
+	list, err := registry.List(ctx)
+	if err != nil || len(list) != 1 || list[0] != "acme" {
+		t.Errorf("Expected acme to be registered, got %v (%v)", list, err)
+	}
+}
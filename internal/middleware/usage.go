@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"time"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/tenants"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageTracking counts each authenticated request against its API key, route and game.
+// Unauthenticated requests (public routes, development mode) are not tracked.
+func UsageTracking(tracker *audit.UsageTracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		value, exists := c.Get(apikeys.PrincipalContextKey)
+		if !exists {
+			return
+		}
+		principal, ok := value.(*apikeys.Principal)
+		if !ok {
+			return
+		}
+
+		keyID := principal.KeyID
+		if principal.Master {
+			keyID = audit.MasterKeyID
+		}
+
+		tracker.Track(audit.UsageRequest{
+			KeyID:   keyID,
+			KeyName: principal.Name,
+			Method:  c.Request.Method,
+			Route:   c.FullPath(),
+			GameID:  c.Param("gameId"),
+			Tenant:  tenants.FromContext(c.Request.Context()),
+			Status:  c.Writer.Status(),
+			At:      time.Now(),
+		})
+	}
+}
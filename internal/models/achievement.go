@@ -0,0 +1,173 @@
+package models
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"unicode/utf8"
+)
+
+// Achievement types
+const (
+	AchievementThreshold = "threshold" // A score of at least the threshold
+	AchievementPlays     = "plays"     // The threshold's number of submissions
+	AchievementStreak    = "streak"    // Submissions on the threshold's number of consecutive UTC days
+	AchievementRank      = "rank"      // A place on the leaderboard at or above the threshold
+)
+
+// MaxAchievements bounds the achievements defined for one game
+const MaxAchievements = 100
+
+// AchievementDefinition describes an achievement players of a game can unlock
+type AchievementDefinition struct {
+	ID          string `json:"id" example:"score_10k"`
+	Name        string `json:"name" example:"High Achiever"`
+	Description string `json:"description,omitempty" example:"Reach 10000 points"`
+	Icon        string `json:"icon,omitempty" example:"💫"`                                                             // A single emoji
+	Type        string `json:"type" example:"threshold"`                                                               // threshold, plays, streak or rank
+	Threshold   int64  `json:"threshold" example:"10000"`                                                              // The score, submissions, days or rank to reach
+	Badge       string `json:"badge,omitempty" example:"/api/v1/games/pacman/achievements/score_10k/badge?v=9f86d081"` // Uploaded badge image, set by uploading one
+}
+
+// Validate checks a definition's ID, name, type and threshold
+func (d *AchievementDefinition) Validate() error {
+	if len(d.ID) < 1 || len(d.ID) > 50 {
+		return fmt.Errorf("id must be between 1 and 50 characters")
+	}
+	for _, r := range d.ID {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			return fmt.Errorf("id can only contain lowercase letters, digits, underscores and hyphens")
+		}
+	}
+	if n := utf8.RuneCountInString(d.Name); n < 1 || n > 50 {
+		return fmt.Errorf("name must be between 1 and 50 characters")
+	}
+	if utf8.RuneCountInString(d.Description) > 200 {
+		return fmt.Errorf("description cannot exceed 200 characters")
+	}
+	if d.Icon != "" && !isEmoji(d.Icon) {
+		return fmt.Errorf("icon must be a single emoji")
+	}
+	switch d.Type {
+	case AchievementThreshold:
+	case AchievementPlays, AchievementStreak, AchievementRank:
+		if d.Threshold < 1 {
+			return fmt.Errorf("a %s achievement needs a threshold of at least 1", d.Type)
+		}
+	default:
+		return fmt.Errorf("type must be %s, %s, %s or %s", AchievementThreshold, AchievementPlays, AchievementStreak, AchievementRank)
+	}
+	return nil
+}
+
+// Unlocked returns the achievement as unlocked at a time
+func (d *AchievementDefinition) Unlocked(at time.Time) Achievement {
+	return Achievement{ID: d.ID, Name: d.Name, Description: d.Description, UnlockedAt: at, Icon: d.Icon, Badge: d.Badge}
+}
+
+// DefaultAchievements are the achievements of games that haven't defined their own
+var DefaultAchievements = []AchievementDefinition{
+	{ID: "first_score", Name: "First Score", Description: "Submit your first score", Icon: "🎯", Type: AchievementPlays, Threshold: 1},
+	{ID: "score_1k", Name: "Getting Started", Description: "Reach 1000 points", Icon: "⭐", Type: AchievementThreshold, Threshold: 1000},
+	{ID: "score_5k", Name: "Rising Star", Description: "Reach 5000 points", Icon: "🌟", Type: AchievementThreshold, Threshold: 5000},
+	{ID: "score_10k", Name: "High Achiever", Description: "Reach 10000 points", Icon: "💫", Type: AchievementThreshold, Threshold: 10000},
+	{ID: "score_25k", Name: "Score Master", Description: "Reach 25000 points", Icon: "🏆", Type: AchievementThreshold, Threshold: 25000},
+	{ID: "score_50k", Name: "Legend", Description: "Reach 50000 points", Icon: "👑", Type: AchievementThreshold, Threshold: 50000},
+	{ID: "dedicated_player", Name: "Dedicated Player", Description: "Submit 5 or more scores", Icon: "🎮", Type: AchievementPlays, Threshold: 5},
+	{ID: "score_hunter", Name: "Score Hunter", Description: "Submit 10 or more scores", Icon: "🏹", Type: AchievementPlays, Threshold: 10},
+}
+
+// AchievementDefinitionList lists a game's achievement definitions
+type AchievementDefinitionList struct {
+	GameID       string                  `json:"game_id" example:"pacman"`
+	Default      bool                    `json:"default" example:"false"`          // The game uses DefaultAchievements, having defined none of its own
+	Theme        string                  `json:"theme,omitempty" example:"medals"` // Icon theme the achievements are shown with
+	Achievements []AchievementDefinition `json:"achievements"`
+}
+
+// MaxBadgeSize bounds an uploaded achievement badge image, in bytes
+const MaxBadgeSize = 64 << 10
+
+// BadgeContentTypes are the image types a badge can be uploaded as. SVG isn't one: it
+// can carry script.
+var BadgeContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/gif":  true,
+	"image/jpeg": true,
+	"image/webp": true,
+}
+
+// AchievementTheme is a built-in set of achievement icons, chosen by achievement ID and
+// then by type. Achievements it has no icon for keep their own.
+type AchievementTheme struct {
+	ByID   map[string]string
+	ByType map[string]string
+}
+
+// Achievement icon themes
+const (
+	AchievementThemeClassic = "classic" // Each achievement's own icon
+	AchievementThemeMedals  = "medals"
+	AchievementThemeRetro   = "retro"
+)
+
+// AchievementThemes are the icon themes a game can show its achievements with
+var AchievementThemes = map[string]AchievementTheme{
+	AchievementThemeClassic: {},
+	AchievementThemeMedals: {
+		ByID: map[string]string{
+			"score_1k": "🥉", "score_5k": "🥈", "score_10k": "🥇", "score_25k": "🏅", "score_50k": "🏆",
+		},
+		ByType: map[string]string{
+			AchievementThreshold: "🏅", AchievementPlays: "🎖️", AchievementStreak: "📅", AchievementRank: "🏆",
+		},
+	},
+	AchievementThemeRetro: {
+		ByID: map[string]string{
+			"first_score": "🪙", "score_50k": "👑",
+		},
+		ByType: map[string]string{
+			AchievementThreshold: "👾", AchievementPlays: "🕹️", AchievementStreak: "⏱️", AchievementRank: "🏁",
+		},
+	},
+}
+
+// ValidAchievementTheme reports whether theme is a built-in icon theme or empty
+func ValidAchievementTheme(theme string) bool {
+	_, ok := AchievementThemes[theme]
+	return ok || theme == ""
+}
+
+// Apply returns the definition with its icon from the theme
+func (t AchievementTheme) Apply(definition AchievementDefinition) AchievementDefinition {
+	if icon, ok := t.ByID[definition.ID]; ok {
+		definition.Icon = icon
+	} else if icon, ok := t.ByType[definition.Type]; ok {
+		definition.Icon = icon
+	}
+	return definition
+}
+
+// AchievementBadge is an image uploaded to show for an achievement instead of its icon
+type AchievementBadge struct {
+	ContentType string    `json:"content_type"`
+	Data        []byte    `json:"data"`
+	Checksum    string    `json:"checksum"` // Hex SHA-256 of the data
+	Updated     time.Time `json:"updated"`
+}
+
+// BadgeContentType returns the image type of badge data, or an error when it's too
+// large or not an accepted image
+func BadgeContentType(data []byte) (string, error) {
+	if len(data) == 0 {
+		return "", fmt.Errorf("badge image is empty")
+	}
+	if len(data) > MaxBadgeSize {
+		return "", fmt.Errorf("badge image cannot exceed %d KB", MaxBadgeSize>>10)
+	}
+	contentType := http.DetectContentType(data)
+	if !BadgeContentTypes[contentType] {
+		return "", fmt.Errorf("badge image must be PNG, GIF, JPEG or WebP, got %s", contentType)
+	}
+	return contentType, nil
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrSuspiciousScore is returned when a submission breaks its game's anti-cheat rules
+var ErrSuspiciousScore = errors.New("score failed anti-cheat validation")
+
+// What happens to a submission that breaks a game's anti-cheat rules
+const (
+	AntiCheatReject = "reject" // Refuse it
+	AntiCheatFlag   = "flag"   // Accept it and list it for review
+)
+
+// Anti-cheat rule names, reported with each violation
+const (
+	RuleMaxScore    = "max_score"
+	RuleMaxDelta    = "max_delta"
+	RuleMinInterval = "min_interval"
+)
+
+// AntiCheatRules bound what a plausible submission looks like for a game. A zero
+// value disables that rule.
+type AntiCheatRules struct {
+	MaxScore           int64  `json:"max_score,omitempty" example:"3333360"`       // Highest plausible score
+	MaxDelta           int64  `json:"max_delta,omitempty" example:"500000"`        // Largest jump over the player's previous high score
+	MinIntervalSeconds int    `json:"min_interval_seconds,omitempty" example:"60"` // Shortest gap between submissions from the same initials
+	Action             string `json:"action,omitempty" example:"reject"`           // reject (the default) or flag
+}
+
+// Enabled reports whether any rule is set
+func (r *AntiCheatRules) Enabled() bool {
+	return r != nil && (r.MaxScore > 0 || r.MaxDelta > 0 || r.MinIntervalSeconds > 0)
+}
+
+// Flags reports whether violations are accepted and flagged rather than rejected
+func (r *AntiCheatRules) Flags() bool {
+	return r.Action == AntiCheatFlag
+}
+
+// Validate checks the rules, defaulting the action to reject
+func (r *AntiCheatRules) Validate() error {
+	if r.MaxScore < 0 || r.MaxDelta < 0 || r.MinIntervalSeconds < 0 {
+		return fmt.Errorf("anti-cheat limits cannot be negative")
+	}
+	if r.MinIntervalSeconds > 86400 {
+		return fmt.Errorf("min_interval_seconds cannot exceed a day")
+	}
+
+	switch r.Action {
+	case "":
+		r.Action = AntiCheatReject
+	case AntiCheatReject, AntiCheatFlag:
+	default:
+		return fmt.Errorf("action must be %s or %s", AntiCheatReject, AntiCheatFlag)
+	}
+	return nil
+}
+
+// ScoreViolation is one anti-cheat rule a submission broke
+type ScoreViolation struct {
+	Rule    string `json:"rule" example:"max_delta"`
+	Message string `json:"message" example:"score is 600000 above the player's high score, more than 500000"`
+}
+
+// SuspiciousScoreError reports the rules a rejected submission broke. It matches
+// ErrSuspiciousScore with errors.Is.
+type SuspiciousScoreError struct {
+	Violations []ScoreViolation
+}
+
+func (e *SuspiciousScoreError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return fmt.Sprintf("%s: %s", ErrSuspiciousScore, strings.Join(messages, "; "))
+}
+
+func (e *SuspiciousScoreError) Is(target error) bool {
+	return target == ErrSuspiciousScore
+}
+
+// FlaggedSubmissionsResponse lists a game's submissions flagged for review, newest first
+type FlaggedSubmissionsResponse struct {
+	GameID      string       `json:"game_id" example:"pacman"`
+	Submissions []ScoreEntry `json:"submissions"`
+	Count       int          `json:"count" example:"2"`
+}
+
+// Anti-cheat simulation outcomes
+const (
+	SimulationAccepted = "accepted" // Broke no rule
+	SimulationFlagged  = "flagged"  // Broke a rule and would be stored for review
+	SimulationRejected = "rejected" // Broke a rule and would be refused
+	SimulationInvalid  = "invalid"  // Would be refused before the rules, e.g. for malformed initials
+)
+
+// MaxSimulatedSubmissions bounds an anti-cheat simulation's batch
+const MaxSimulatedSubmissions = 1000
+
+// SimulatedSubmission is a hypothetical submission run through a game's anti-cheat rules
+type SimulatedSubmission struct {
+	Initials  string      `json:"initials" binding:"required" example:"AAA"`
+	Score     json.Number `json:"score" binding:"required" swaggertype:"number" example:"12500"` // As a cabinet would submit it
+	DeviceID  string      `json:"device_id,omitempty" example:"pacman-cabinet-1"`                // The submitting device, for games keeping players per device
+	Timestamp time.Time   `json:"timestamp,omitempty" example:"2025-07-16T15:30:00Z"`            // When it's submitted, default now
+}
+
+// SimulatedResult is what a game's anti-cheat rules would do with one submission
+type SimulatedResult struct {
+	Index      int              `json:"index" example:"0"` // Position in the batch
+	Initials   string           `json:"initials" example:"AAA"`
+	Score      int64            `json:"score" example:"12500"`
+	Outcome    string           `json:"outcome" example:"flagged"` // accepted, flagged, rejected or invalid
+	Violations []ScoreViolation `json:"violations,omitempty"`
+	Error      string           `json:"error,omitempty" example:"initials must be exactly 3 characters with no spaces"` // Why an invalid submission would be refused
+}
+
+// AntiCheatSimulation reports what anti-cheat rules would do with a batch of submissions.
+// Each submission is judged against the game's real history plus the batch's earlier
+// submissions that would have been stored.
+type AntiCheatSimulation struct {
+	GameID   string            `json:"game_id" example:"pacman"`
+	Rules    *AntiCheatRules   `json:"rules,omitempty"` // The rules simulated, absent when the game has none
+	Results  []SimulatedResult `json:"results"`
+	Accepted int               `json:"accepted" example:"97"`
+	Flagged  int               `json:"flagged" example:"0"`
+	Rejected int               `json:"rejected" example:"3"`
+	Invalid  int               `json:"invalid" example:"0"`
+	ByRule   map[string]int    `json:"by_rule"` // How many submissions broke each rule
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// API key scopes
+const (
+	ScopeSubmit     = "submit"      // Submit scores
+	ScopeAdminRead  = "admin:read"  // Read score history and admin data
+	ScopeAdminWrite = "admin:write" // Change settings, run exports and restores
+	ScopeEdge       = "edge"        // Submit scores for other devices, with the time they were played
+
+	// AllGames grants a key access to every game
+	AllGames = "*"
+)
+
+// Credential roles. Submitters, such as cabinets, only post scores; admin credentials
+// are needed to read every score and to delete anything.
+const (
+	RoleSubmitter = "submitter"
+	RoleAdmin     = "admin"
+)
+
+// RoleForScopes returns the role a key with scopes holds: admin if it has any admin
+// scope, submitter otherwise
+func RoleForScopes(scopes []string) string {
+	for _, scope := range scopes {
+		if scope == ScopeAdminRead || scope == ScopeAdminWrite {
+			return RoleAdmin
+		}
+	}
+	return RoleSubmitter
+}
+
+// APIKey is a stored API key; the secret itself is never stored, only its hash
+type APIKey struct {
+	ID        string     `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Name      string     `json:"name" example:"pacman-cabinet-1"`
+	Prefix    string     `json:"prefix" example:"rbk_3f2a9c1b"` // First characters of the secret, for identification
+	GameIDs   []string   `json:"game_ids" example:"pacman"`
+	Scopes    []string   `json:"scopes" example:"submit"`
+	Role      string     `json:"role" example:"submitter"`                       // submitter or admin
+	MaxGames  *int       `json:"max_games,omitempty" example:"500"`              // Overrides MAX_GAMES_PER_KEY; 0 is unlimited
+	Tenant    string     `json:"tenant,omitempty" example:"acme"`                // The tenant the key acts for, empty for the default namespace
+	DeviceID  string     `json:"device_id,omitempty" example:"pacman-cabinet-1"` // The enrolled device the key was issued to
+	RateLimit *RateLimit `json:"rate_limit,omitempty"`                           // Overrides API_RATE_LIMIT and its overrides; a rate of 0 is unlimited
+	CreatedAt time.Time  `json:"created_at" example:"2025-07-16T15:30:00Z"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty" example:"2025-07-20T10:00:00Z"`
+}
+
+// EffectiveRole returns the key's role, derived from its scopes for keys stored before
+// keys had roles
+func (k *APIKey) EffectiveRole() string {
+	if k.Role != "" {
+		return k.Role
+	}
+	return RoleForScopes(k.Scopes)
+}
+
+// Revoked reports whether the key has been revoked
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// RateLimit is a sustained request rate and how many requests may arrive at once
+type RateLimit struct {
+	RequestsPerSecond float64 `json:"requests_per_second" example:"20"`
+	Burst             int     `json:"burst" example:"40"`
+}
+
+// MaxRateLimitBurst bounds the burst of a key's own rate limit
+const MaxRateLimitBurst = 10000
+
+// Validate checks a key's own rate limit: a rate of 0 is unlimited, any other needs a
+// burst of at least one request
+func (l *RateLimit) Validate() error {
+	if l.RequestsPerSecond < 0 {
+		return fmt.Errorf("requests_per_second cannot be negative")
+	}
+	if l.RequestsPerSecond > 0 && (l.Burst < 1 || l.Burst > MaxRateLimitBurst) {
+		return fmt.Errorf("burst must be between 1 and %d", MaxRateLimitBurst)
+	}
+	return nil
+}
+
+// CreatedAPIKey is returned once when a key is created and includes its secret
+type CreatedAPIKey struct {
+	APIKey
+	Key string `json:"key" example:"rbk_3f2a9c1b..."` // Only shown at creation
+}
+
+// APIKeyIndex maps key IDs to the hashes their records are stored under
+type APIKeyIndex struct {
+	Keys    map[string]string `json:"keys"`
+	Updated time.Time         `json:"updated"`
+}
+
+// StreamToken is a short-lived credential for opening one game's live streams
+// from clients that can't send headers, such as browser EventSource and WebSocket
+type StreamToken struct {
+	Token     string    `json:"token" example:"rbs_eyJnYW1lX2lkIjoicGFjbWFuIn0.3q2-7w"`
+	GameID    string    `json:"game_id" example:"pacman"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-07-16T15:35:00Z"` // Connections must be opened before this
+}
+
+// StreamTokenClaims are the facts a stream token vouches for
+type StreamTokenClaims struct {
+	GameID    string `json:"game_id"`
+	KeyID     string `json:"key_id,omitempty"` // The key that minted it, empty for the master key
+	Tenant    string `json:"tenant,omitempty"`
+	ExpiresAt int64  `json:"exp"` // Unix seconds
+}
+
+// AdminSession describes who an admin UI session is signed in as
+type AdminSession struct {
+	Name      string     `json:"name" example:"ops-laptop"`
+	KeyID     string     `json:"key_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // Empty for the master key
+	Master    bool       `json:"master"`
+	Tenant    string     `json:"tenant,omitempty" example:"acme"`
+	GameIDs   []string   `json:"game_ids" example:"*"`
+	Scopes    []string   `json:"scopes" example:"admin:read,admin:write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-07-17T03:30:00Z"` // Absent when signed in with an API key rather than a session
+}
+
+// AdminSessionClaims are the facts an admin session cookie vouches for
+type AdminSessionClaims struct {
+	KeyID     string `json:"key_id,omitempty"` // The key signed in with, empty for the master key
+	Tenant    string `json:"tenant,omitempty"` // The key's tenant
+	ExpiresAt int64  `json:"exp"`              // Unix seconds
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrArchiveNotFound is returned for an archive a game doesn't have
+var ErrArchiveNotFound = errors.New("archive not found")
+
+// GameArchive is a game's leaderboard, high scores and score history as they were when
+// an operator reset it
+type GameArchive struct {
+	GameArchiveInfo
+	Leaderboard Leaderboard      `json:"leaderboard"`
+	HighScores  PlayerHighScores `json:"high_scores"`
+	History     []ScoreEntry     `json:"history"`
+}
+
+// GameArchiveInfo describes an archive without its contents
+type GameArchiveInfo struct {
+	ArchiveID  string    `json:"archive_id" example:"20250716T153000.000Z"` // When it was taken, in UTC
+	GameID     string    `json:"game_id" example:"pacman"`
+	ArchivedAt time.Time `json:"archived_at"`
+	Reason     string    `json:"reason,omitempty" example:"Cabinet moved to a new venue"`
+	Scores     int       `json:"scores" example:"1520"` // History entries archived
+	Players    int       `json:"players" example:"87"`  // High scores archived
+}
+
+// ErrDeletionNotConfirmed is returned for a game deletion without the game's current
+// confirm token
+var ErrDeletionNotConfirmed = errors.New("game deletion not confirmed")
+
+// GameDeletion is what deleting a game removes. Before the deletion it carries the
+// token that confirms it, which changes whenever the game takes a score.
+type GameDeletion struct {
+	GameID       string `json:"game_id" example:"pacman"`
+	Scores       int    `json:"scores" example:"1520"`                              // History entries
+	Players      int    `json:"players" example:"87"`                               // High scores
+	Archives     int    `json:"archives" example:"2"`                               // Archives of earlier resets
+	Keys         int    `json:"keys,omitempty" example:"24"`                        // Database keys removed
+	Deleted      bool   `json:"deleted" example:"true"`                             // False for a preview
+	ConfirmToken string `json:"confirm_token,omitempty" example:"9f86d081884c7d65"` // Pass as ?confirm= to delete
+}
+
+// GameArchivesResponse lists a game's archives, newest first
+type GameArchivesResponse struct {
+	GameID   string            `json:"game_id" example:"pacman"`
+	Archives []GameArchiveInfo `json:"archives"`
+	Count    int               `json:"count" example:"2"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// AuditEntry records a single protected or automated operation
+type AuditEntry struct {
+	ID        string                 `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Timestamp time.Time              `json:"timestamp" example:"2025-07-16T15:30:00Z"`
+	Action    string                 `json:"action" example:"retention.prune"`
+	Actor     string                 `json:"actor" example:"system:retention"`
+	GameID    string                 `json:"game_id,omitempty" example:"pacman"`
+	RequestID string                 `json:"request_id,omitempty" example:"5f3c9a1e-7b2d-4e8a-9c61-0d2f4b8e7a13"` // The API request that made the change
+	Method    string                 `json:"method,omitempty" example:"DELETE"`                                   // The API request's method
+	Route     string                 `json:"route,omitempty" example:"/api/v1/games/:gameId/scores"`              // The API request's route pattern
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// AuditLogRecord is the stored, bounded audit log
+type AuditLogRecord struct {
+	Entries []AuditEntry `json:"entries"`
+	Updated time.Time    `json:"updated"`
+}
+
+// Usage report granularities
+const (
+	UsageGranularityHour = "hour"
+	UsageGranularityDay  = "day"
+)
+
+// UsageRow counts one API key's requests to one route for one game
+type UsageRow struct {
+	KeyID     string    `json:"key_id" example:"123e4567-e89b-12d3-a456-426614174000"` // "master" for RAWBOARD_API_KEY
+	KeyName   string    `json:"key_name" example:"pacman-cabinet-1"`
+	Method    string    `json:"method" example:"POST"`
+	Route     string    `json:"route" example:"/api/v1/games/:gameId/scores"`
+	GameID    string    `json:"game_id,omitempty" example:"pacman"`
+	Tenant    string    `json:"tenant,omitempty" example:"acme"`
+	Requests  int       `json:"requests" example:"42"`
+	Errors    int       `json:"errors" example:"1"` // Responses with status 400 or above
+	FirstSeen time.Time `json:"first_seen" example:"2025-07-16T02:03:11Z"`
+	LastSeen  time.Time `json:"last_seen" example:"2025-07-16T02:58:40Z"`
+}
+
+// UsageBucket is the stored usage for one hour
+type UsageBucket struct {
+	Hour time.Time  `json:"hour" example:"2025-07-16T02:00:00Z"`
+	Rows []UsageRow `json:"rows"`
+}
+
+// UsagePeriod is the usage within one reporting period
+type UsagePeriod struct {
+	Start time.Time  `json:"start" example:"2025-07-16T02:00:00Z"`
+	Rows  []UsageRow `json:"rows"`
+}
+
+// UsageReport breaks down API key usage by route over a time window
+type UsageReport struct {
+	From        time.Time     `json:"from" example:"2025-07-16T00:00:00Z"`
+	To          time.Time     `json:"to" example:"2025-07-17T00:00:00Z"`
+	Granularity string        `json:"granularity" example:"hour"`
+	Periods     []UsagePeriod `json:"periods"`
+	Totals      []UsageRow    `json:"totals"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrBlockedInitials is returned when submitted initials are on the blocklist
+var ErrBlockedInitials = errors.New("initials are not allowed")
+
+// DefaultBlockedInitials are rejected on every deployment
+var DefaultBlockedInitials = []string{
+	"ASS", "COK", "CUM", "DIK", "FAG", "FCK", "FUC", "FUK",
+	"JIZ", "KKK", "NIG", "SHT", "TIT", "VAG",
+}
+
+// IsStaticallyBlocked reports whether initials are on the built-in blocklist. The
+// deployment's configured and operator-managed entries are checked by the leaderboard
+// service.
+func IsStaticallyBlocked(initials string) bool {
+	initials = NormalizeInitials(initials)
+	for _, blocked := range DefaultBlockedInitials {
+		if initials == blocked {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeInitials upper-cases and trims initials the way submissions are stored
+func NormalizeInitials(initials string) string {
+	return strings.ToUpper(strings.TrimSpace(initials))
+}
+
+// BlockedInitialsRecord is the operator-managed blocklist stored in the database
+type BlockedInitialsRecord struct {
+	Initials []string  `json:"initials"`
+	Updated  time.Time `json:"updated"`
+}
+
+// BlocklistResponse shows every source of blocked initials
+type BlocklistResponse struct {
+	BuiltIn    []string `json:"built_in"`   // Always blocked
+	Configured []string `json:"configured"` // From RAWBOARD_BLOCKED_INITIALS
+	Managed    []string `json:"managed"`    // Added through the admin API
+}
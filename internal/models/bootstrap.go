@@ -0,0 +1,53 @@
+package models
+
+// Bootstrap change actions
+const (
+	BootstrapActionCreate  = "create"
+	BootstrapActionUpdate  = "update"
+	BootstrapActionRevoke  = "revoke"
+	BootstrapActionBlock   = "block"
+	BootstrapActionUnblock = "unblock"
+)
+
+// Bootstrap resource kinds
+const (
+	BootstrapResourceGame            = "game"
+	BootstrapResourceAPIKey          = "api_key"
+	BootstrapResourceBlockedInitials = "blocked_initials"
+)
+
+// BootstrapDocument declares the desired state of a deployment. Each section that is
+// present is authoritative for what it covers; omitted sections are left alone.
+type BootstrapDocument struct {
+	Games           []BootstrapGame   `json:"games"`            // Games to register with exactly these settings; unlisted games are kept
+	APIKeys         []BootstrapAPIKey `json:"api_keys"`         // Every active scoped key; unlisted keys are revoked
+	BlockedInitials []string          `json:"blocked_initials"` // The whole managed blocklist
+}
+
+// BootstrapGame declares a game and its settings
+type BootstrapGame struct {
+	GameID   string       `json:"game_id" example:"pacman"`
+	Settings GameSettings `json:"settings"`
+}
+
+// BootstrapAPIKey declares a scoped API key, identified by name
+type BootstrapAPIKey struct {
+	Name    string   `json:"name" example:"pacman-cabinet-1"`
+	GameIDs []string `json:"game_ids" example:"pacman"`
+	Scopes  []string `json:"scopes" example:"submit"`
+	Secret  string   `json:"secret,omitempty" example:"rbk_3f2a9c1b..."` // Optional; generated and returned once when omitted
+}
+
+// BootstrapChange is one change reconciling the stored state with the document
+type BootstrapChange struct {
+	Resource string `json:"resource" example:"api_key"`
+	ID       string `json:"id" example:"pacman-cabinet-1"` // Game ID, key name or initials
+	Action   string `json:"action" example:"create"`
+}
+
+// BootstrapResult reports what a bootstrap changed, or would change on a dry run
+type BootstrapResult struct {
+	DryRun      bool              `json:"dry_run"`
+	Changes     []BootstrapChange `json:"changes"`
+	CreatedKeys []CreatedAPIKey   `json:"created_keys"` // Keys with generated secrets, which are only returned here
+}
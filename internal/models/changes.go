@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Leaderboard snapshots are kept hourly for two days and daily for this long, so
+// changes can be compared over at most MaxChangesWindow
+const MaxChangesWindow = 30 * 24 * time.Hour
+
+// LeaderboardChanges compares a game's board with a snapshot of it taken earlier
+type LeaderboardChanges struct {
+	GameID      string       `json:"game_id" example:"pacman"`
+	Since       time.Time    `json:"since" example:"2025-07-09T15:00:00Z"` // When the compared snapshot was taken
+	Movers      []RankChange `json:"movers"`                               // Players on both boards whose rank changed, biggest gains first
+	NewEntrants []RankChange `json:"new_entrants"`                         // Players on the board now who weren't on it then, best first
+	DroppedOut  []RankChange `json:"dropped_out"`                          // Players on the board then who aren't now, best first
+}
+
+// RankChange is one player's move on the board
+type RankChange struct {
+	Initials     string `json:"initials" example:"AAA"`
+	DeviceID     string `json:"device_id,omitempty" example:"pacman-cabinet-1"` // In device-scoped games
+	Score        int64  `json:"score" example:"15000"`                          // Current high score, or the one held then for players who dropped out
+	DisplayScore string `json:"display_score,omitempty" example:"12.50"`
+	Rank         int    `json:"rank,omitempty" example:"3"`          // 0 once off the board
+	PreviousRank int    `json:"previous_rank,omitempty" example:"7"` // 0 when not on the board then
+	Change       int    `json:"change,omitempty" example:"4"`        // Places gained, negative for places lost; only for movers
+}
@@ -0,0 +1,8 @@
+package models
+
+// DevReset reports what a development reset left behind
+type DevReset struct {
+	Seeded bool     `json:"seeded"`                                  // Whether demo data was loaded after wiping
+	Games  []string `json:"games,omitempty" example:"pacman,galaga"` // Games given demo scores
+	Scores int      `json:"scores,omitempty" example:"24"`           // Demo scores submitted
+}
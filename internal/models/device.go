@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Device statuses
+const (
+	DeviceStatusPending = "pending" // Waiting for the cabinet to present its enrollment code
+	DeviceStatusActive  = "active"  // Enrolled and holding a key
+	DeviceStatusRevoked = "revoked" // Its key no longer works
+)
+
+// Device limits
+const (
+	MaxDeviceGames           = 50             // Games mapped on one device
+	DefaultEnrollmentCodeTTL = 24 * time.Hour // How long an enrollment code may be redeemed
+)
+
+// Device is a cabinet enrolled, or waiting to enroll, for its own submission key
+type Device struct {
+	DeviceID   string            `json:"device_id" example:"pacman-cabinet-1"`
+	Name       string            `json:"name,omitempty" example:"Pac-Man by the bar"`
+	Games      map[string]string `json:"games"` // Names the cabinet uses for its games, such as ROM names, mapped to game IDs
+	Status     string            `json:"status" example:"active"`
+	KeyID      string            `json:"key_id,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // The API key issued at enrollment
+	CreatedAt  time.Time         `json:"created_at" example:"2025-07-16T15:30:00Z"`
+	CodeExpiry *time.Time        `json:"code_expires_at,omitempty" example:"2025-07-17T15:30:00Z"` // While pending
+	EnrolledAt *time.Time        `json:"enrolled_at,omitempty" example:"2025-07-16T16:00:00Z"`
+	RevokedAt  *time.Time        `json:"revoked_at,omitempty" example:"2025-08-01T09:00:00Z"`
+}
+
+// Validate checks a device's name and game mapping
+func (d *Device) Validate() error {
+	if len(d.Name) > 100 {
+		return fmt.Errorf("name cannot exceed 100 characters")
+	}
+	if len(d.Games) == 0 {
+		return fmt.Errorf("a device needs at least one game")
+	}
+	if len(d.Games) > MaxDeviceGames {
+		return fmt.Errorf("a device can map at most %d games", MaxDeviceGames)
+	}
+	for name, gameID := range d.Games {
+		if len(name) < 1 || len(name) > 50 {
+			return fmt.Errorf("game names must be between 1 and 50 characters")
+		}
+		if len(gameID) < 1 || len(gameID) > 50 || gameID == AllGames {
+			return fmt.Errorf("game %s must map to a game ID between 1 and 50 characters", name)
+		}
+	}
+	return nil
+}
+
+// GameIDs returns the distinct game IDs the device's games map to
+func (d *Device) GameIDs() []string {
+	seen := make(map[string]bool, len(d.Games))
+	gameIDs := make([]string, 0, len(d.Games))
+	for _, gameID := range d.Games {
+		if !seen[gameID] {
+			seen[gameID] = true
+			gameIDs = append(gameIDs, gameID)
+		}
+	}
+	return gameIDs
+}
+
+// DeviceEnrollmentCode is returned once when a device is created, for typing into the cabinet
+type DeviceEnrollmentCode struct {
+	Device    Device    `json:"device"`
+	Code      string    `json:"code" example:"K7QM-2XRD-9HPA"` // Only shown at creation; redeemable once
+	ExpiresAt time.Time `json:"expires_at" example:"2025-07-17T15:30:00Z"`
+}
+
+// DeviceEndpoints are the API paths a device uses, relative to the server and already
+// within the device's tenant
+type DeviceEndpoints struct {
+	SubmitScore string `json:"submit_score" example:"/api/v1/games/{gameId}/scores"`
+	Leaderboard string `json:"leaderboard" example:"/api/v1/games/{gameId}/leaderboard"`
+	Events      string `json:"events" example:"/api/v1/games/{gameId}/events"`
+}
+
+// DeviceConfig is the bundle a device receives at enrollment
+type DeviceConfig struct {
+	DeviceID  string            `json:"device_id" example:"pacman-cabinet-1"`
+	Games     map[string]string `json:"games"`
+	Endpoints DeviceEndpoints   `json:"endpoints"`
+}
+
+// EnrolledDevice is returned once to a device that redeemed its enrollment code
+type EnrolledDevice struct {
+	Key    string       `json:"key" example:"rbk_3f2a9c1b..."` // The device's own API key, scoped to submitting its games; only shown here
+	Config DeviceConfig `json:"config"`
+}
+
+// Device analytics limits
+const (
+	DefaultDeviceAnalyticsDays = 30
+	MaxDeviceAnalyticsDays     = 90
+)
+
+// DeviceAnalytics compares the devices that submitted a game's scores over a window of
+// whole UTC days ending today
+type DeviceAnalytics struct {
+	GameID       string        `json:"game_id" example:"pacman"`
+	From         time.Time     `json:"from" example:"2025-06-17T00:00:00Z"`
+	To           time.Time     `json:"to" example:"2025-07-16T15:30:00Z"`
+	Days         int           `json:"days" example:"30"`
+	Plays        int           `json:"plays" example:"1240"`            // Every submission in the window
+	AverageScore float64       `json:"average_score" example:"15230.5"` // Over every submission in the window
+	Unattributed int           `json:"unattributed" example:"12"`       // Submissions made without a device key
+	Devices      []DeviceStats `json:"devices"`                         // Most plays first
+
+	DisplayAverageScore string `json:"display_average_score,omitempty" example:"152.31"` // With the game's decimals, set in games that keep decimals
+}
+
+// DeviceStats is one device's play in a game over a DeviceAnalytics window
+type DeviceStats struct {
+	DeviceID     string  `json:"device_id" example:"pacman-cabinet-1"`
+	Plays        int     `json:"plays" example:"620"`
+	PlaysPerDay  float64 `json:"plays_per_day" example:"20.7"`
+	AverageScore float64 `json:"average_score" example:"14900"`
+	// How far the device's average score is from the game's, as a fraction: 0.25 is 25%
+	// higher. Large deviations can mean a miscalibrated cabinet.
+	ScoreDeviation float64       `json:"score_deviation" example:"-0.02"`
+	HighScore      int64         `json:"high_score" example:"98000"`
+	ActiveDays     int           `json:"active_days" example:"29"`
+	Uptime         float64       `json:"uptime" example:"0.97"`            // Share of the window's days with a submission
+	LongestGap     float64       `json:"longest_gap_hours" example:"31.5"` // Longest stretch between submissions, or since the last, in hours
+	FirstPlay      time.Time     `json:"first_play" example:"2025-06-17T18:02:00Z"`
+	LastPlay       time.Time     `json:"last_play" example:"2025-07-16T14:55:00Z"`
+	Daily          []DevicePlays `json:"daily"` // Every day of the window, oldest first
+
+	// Average and high score with the game's decimals, set in games that keep decimals
+	DisplayAverageScore string `json:"display_average_score,omitempty" example:"149.00"`
+	DisplayHighScore    string `json:"display_high_score,omitempty" example:"980.00"`
+}
+
+// DevicePlays counts a device's submissions on one UTC day
+type DevicePlays struct {
+	Date  string `json:"date" example:"2025-07-16"`
+	Plays int    `json:"plays" example:"21"`
+}
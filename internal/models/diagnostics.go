@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// Self-check statuses, from best to worst
+const (
+	CheckStatusOK       = "ok"
+	CheckStatusSkipped  = "skipped"
+	CheckStatusWarn     = "warn"
+	CheckStatusCritical = "critical"
+)
+
+// SelfCheck is the outcome of one startup self-check
+type SelfCheck struct {
+	Name       string                 `json:"name" example:"database"`
+	Status     string                 `json:"status" example:"ok"`
+	Message    string                 `json:"message" example:"connected"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	DurationMs int64                  `json:"duration_ms" example:"2"`
+}
+
+// SelfCheckReport collects every self-check; Status is the worst individual status
+type SelfCheckReport struct {
+	Status    string      `json:"status" example:"ok"`
+	CheckedAt time.Time   `json:"checked_at" example:"2025-07-16T15:30:00Z"`
+	Checks    []SelfCheck `json:"checks"`
+}
+
+// ClockSkewReading is one comparison of the server clock against the database clock
+type ClockSkewReading struct {
+	SkewMs      int64     `json:"skew_ms" example:"-12"` // Positive when the server is ahead of the database
+	ThresholdMs int64     `json:"threshold_ms" example:"2000"`
+	Exceeded    bool      `json:"exceeded" example:"false"`
+	MeasuredAt  time.Time `json:"measured_at" example:"2025-07-16T15:30:00Z"`
+}
+
+// DatabaseStats reports the database connection pool, retry and failover counters since startup
+type DatabaseStats struct {
+	Mode       string `json:"mode" example:"standalone"`
+	Hits       uint32 `json:"hits" example:"1520"`      // Connections reused from the pool
+	Misses     uint32 `json:"misses" example:"12"`      // Connections that had to be dialed
+	Timeouts   uint32 `json:"timeouts" example:"0"`     // Waits for a free connection that timed out
+	TotalConns uint32 `json:"total_conns" example:"10"` // Open connections
+	IdleConns  uint32 `json:"idle_conns" example:"8"`   // Open connections not in use
+	StaleConns uint32 `json:"stale_conns" example:"1"`  // Connections closed for being idle too long
+	Retries    uint64 `json:"retries" example:"3"`      // Commands retried after a transient error
+	RetryFails uint64 `json:"retry_fails" example:"0"`  // Commands that still failed after every retry
+
+	Failovers      uint64          `json:"failovers" example:"1"`     // Primaries replaced, for cluster and Sentinel
+	Reshards       uint64          `json:"reshards" example:"0"`      // Cluster slot layouts changed
+	TopologyEvents []TopologyEvent `json:"topology_events,omitempty"` // Latest failovers and reshards, oldest first
+}
+
+// TopologyEvent is a failover or reshard seen by the database topology watcher
+type TopologyEvent struct {
+	Kind   string    `json:"kind" example:"master_change"` // master_change or reshard
+	Detail string    `json:"detail" example:"mymaster: 10.0.0.5:6379 -> 10.0.0.6:6379"`
+	At     time.Time `json:"at" example:"2024-01-15T10:30:00Z"`
+}
+
+// Readiness and dependency statuses
+const (
+	ReadinessReady    = "ready"
+	ReadinessNotReady = "not_ready"
+	ReadinessDraining = "draining"
+	DependencyUp      = "up"
+	DependencyDown    = "down"
+)
+
+// DependencyStatus is the outcome of probing one dependency for readiness
+type DependencyStatus struct {
+	Name      string  `json:"name" example:"database"`
+	Status    string  `json:"status" example:"up"`
+	LatencyMs float64 `json:"latency_ms" example:"0.84"`
+	Error     string  `json:"error,omitempty" example:"context deadline exceeded"`
+}
+
+// ReadinessReport says whether the server can serve traffic; it is ready only when every
+// dependency is up and it isn't draining
+type ReadinessReport struct {
+	Status       string             `json:"status" example:"ready"` // ready, not_ready or draining
+	CheckedAt    time.Time          `json:"checked_at" example:"2025-07-16T15:30:00Z"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	Drain        *DrainStatus       `json:"drain,omitempty"` // Set while draining
+}
+
+// DrainStatus says whether an instance is draining: failing readiness so load balancers
+// stop sending it new requests, while it keeps serving those it gets
+type DrainStatus struct {
+	Draining bool       `json:"draining" example:"true"`
+	Since    *time.Time `json:"since,omitempty" example:"2025-07-16T15:30:00Z"`
+	Reason   string     `json:"reason,omitempty" example:"kernel upgrade"` // shutdown when draining to exit
+}
@@ -0,0 +1,184 @@
+package models
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Display slide views
+const (
+	DisplayViewLeaderboard = "leaderboard" // The game's leaderboard
+	DisplayViewSummary     = "summary"     // The game's public summary card
+)
+
+// DisplayViews lists the views a slide can show
+var DisplayViews = []string{DisplayViewLeaderboard, DisplayViewSummary}
+
+// Display limits
+const (
+	MaxDisplaySlides       = 50   // Slides in one display's rotation
+	MinDisplaySlideSeconds = 3    // Shortest time a slide is shown
+	MaxDisplaySlideSeconds = 3600 // Longest time a slide is shown
+)
+
+// Display is a venue screen and the games it rotates through in attract mode
+type Display struct {
+	DisplayID string         `json:"display_id" example:"lobby-tv"`
+	Name      string         `json:"name,omitempty" example:"Lobby TV"`
+	Slides    []DisplaySlide `json:"slides"`
+	Updated   time.Time      `json:"updated" example:"2025-07-16T15:30:00Z"`
+}
+
+// DisplaySlide is one step of a display's rotation
+type DisplaySlide struct {
+	GameID  string `json:"game_id" example:"pacman"`
+	View    string `json:"view" example:"leaderboard"`        // leaderboard (the default) or summary
+	Seconds int    `json:"seconds" example:"15"`              // How long the slide is shown
+	Limit   int    `json:"limit,omitempty" example:"10"`      // Leaderboard entries to show, 0 for the default
+	Title   string `json:"title,omitempty" example:"Pac-Man"` // Heading to show instead of the game ID
+}
+
+// DisplayRotation is the playlist a display loops through in order
+type DisplayRotation struct {
+	DisplayID    string          `json:"display_id" example:"lobby-tv"`
+	Name         string          `json:"name,omitempty" example:"Lobby TV"`
+	Slides       []RotationSlide `json:"slides"`
+	CycleSeconds int             `json:"cycle_seconds" example:"45"`             // Length of one pass through the slides
+	Updated      time.Time       `json:"updated" example:"2025-07-16T15:30:00Z"` // Changes whenever the rotation is reconfigured
+}
+
+// RotationSlide is a slide with the path its content is fetched from
+type RotationSlide struct {
+	DisplaySlide
+	Path string `json:"path" example:"/api/v1/games/pacman/leaderboard?limit=10"`
+}
+
+// Validate checks a display's rotation and defaults each slide's view
+func (d *Display) Validate() error {
+	if len(d.Name) > 100 {
+		return fmt.Errorf("name cannot exceed 100 characters")
+	}
+	if len(d.Slides) == 0 {
+		return fmt.Errorf("a display needs at least one slide")
+	}
+	if len(d.Slides) > MaxDisplaySlides {
+		return fmt.Errorf("a display can have at most %d slides", MaxDisplaySlides)
+	}
+
+	for i := range d.Slides {
+		slide := &d.Slides[i]
+		if len(slide.GameID) < 1 || len(slide.GameID) > 50 {
+			return fmt.Errorf("slide %d: game_id must be between 1 and 50 characters", i+1)
+		}
+		if slide.Seconds < MinDisplaySlideSeconds || slide.Seconds > MaxDisplaySlideSeconds {
+			return fmt.Errorf("slide %d: seconds must be between %d and %d", i+1, MinDisplaySlideSeconds, MaxDisplaySlideSeconds)
+		}
+		if slide.Limit < 0 || slide.Limit > MaxLeaderboardEntries {
+			return fmt.Errorf("slide %d: limit must be between 0 and %d", i+1, MaxLeaderboardEntries)
+		}
+		if len(slide.Title) > 100 {
+			return fmt.Errorf("slide %d: title cannot exceed 100 characters", i+1)
+		}
+
+		switch slide.View {
+		case "":
+			slide.View = DisplayViewLeaderboard
+		case DisplayViewLeaderboard, DisplayViewSummary:
+		default:
+			return fmt.Errorf("slide %d: view must be one of %s", i+1, strings.Join(DisplayViews, ", "))
+		}
+	}
+	return nil
+}
+
+// Rotation returns the display's playlist with the path each slide's content is
+// fetched from
+func (d *Display) Rotation() *DisplayRotation {
+	rotation := &DisplayRotation{DisplayID: d.DisplayID, Name: d.Name, Slides: make([]RotationSlide, 0, len(d.Slides)), Updated: d.Updated}
+	for _, slide := range d.Slides {
+		path := fmt.Sprintf("/api/v1/games/%s/leaderboard", url.PathEscape(slide.GameID))
+		if slide.View == DisplayViewSummary {
+			path = fmt.Sprintf("/public/games/%s/summary", url.PathEscape(slide.GameID))
+		} else if slide.Limit > 0 {
+			path += fmt.Sprintf("?limit=%d", slide.Limit)
+		}
+		rotation.Slides = append(rotation.Slides, RotationSlide{DisplaySlide: slide, Path: path})
+		rotation.CycleSeconds += slide.Seconds
+	}
+	return rotation
+}
+
+// Display device limits
+const (
+	MaxDisplayDevices = 20 // Devices registered to one display
+)
+
+// Display alert events
+const (
+	DisplayEventOffline = "display.offline" // A device stopped checking in
+	DisplayEventOnline  = "display.online"  // A device that went dark checked in again
+)
+
+// DisplayDevice is a screen showing a display's rotation, as it last reported itself
+type DisplayDevice struct {
+	DeviceID      string          `json:"device_id" example:"lobby-tv-1"`
+	Name          string          `json:"name,omitempty" example:"Lobby TV by the door"`
+	Version       string          `json:"version,omitempty" example:"1.4.0"` // Display client version
+	Address       string          `json:"address,omitempty" example:"203.0.113.7"`
+	RegisteredAt  time.Time       `json:"registered_at" example:"2025-07-16T15:30:00Z"`
+	LastHeartbeat *time.Time      `json:"last_heartbeat,omitempty" example:"2025-07-16T15:30:00Z"`
+	LastFetch     *time.Time      `json:"last_fetch,omitempty" example:"2025-07-16T15:30:00Z"` // Last rotation fetch
+	Showing       *DisplayShowing `json:"showing,omitempty"`
+	Online        bool            `json:"online" example:"true"`
+	OfflineSince  *time.Time      `json:"offline_since,omitempty" example:"2025-07-16T15:30:00Z"` // Set once the device is reported dark
+}
+
+// LastSeen returns when the device last checked in, by heartbeat or rotation fetch
+func (d *DisplayDevice) LastSeen() time.Time {
+	seen := d.RegisteredAt
+	if d.LastHeartbeat != nil && d.LastHeartbeat.After(seen) {
+		seen = *d.LastHeartbeat
+	}
+	if d.LastFetch != nil && d.LastFetch.After(seen) {
+		seen = *d.LastFetch
+	}
+	return seen
+}
+
+// DisplayShowing is the slide a device reported it was showing
+type DisplayShowing struct {
+	Slide  int    `json:"slide" example:"2"` // 1-based position in the rotation
+	GameID string `json:"game_id,omitempty" example:"pacman"`
+	View   string `json:"view,omitempty" example:"leaderboard"`
+}
+
+// DisplayStatus lists the devices showing one display and whether each is online
+type DisplayStatus struct {
+	DisplayID string          `json:"display_id" example:"lobby-tv"`
+	Name      string          `json:"name,omitempty" example:"Lobby TV"`
+	Online    int             `json:"online" example:"1"`
+	Offline   int             `json:"offline" example:"0"`
+	Devices   []DisplayDevice `json:"devices"`
+}
+
+// DisplayHeartbeat acknowledges a heartbeat; a device refetches its rotation when
+// Updated changes
+type DisplayHeartbeat struct {
+	DisplayID        string    `json:"display_id" example:"lobby-tv"`
+	DeviceID         string    `json:"device_id" example:"lobby-tv-1"`
+	Updated          time.Time `json:"updated" example:"2025-07-16T15:30:00Z"` // When the rotation last changed
+	HeartbeatSeconds int       `json:"heartbeat_seconds" example:"30"`         // How often to send heartbeats
+}
+
+// DisplayAlert is posted to the alert URL when a device goes dark or comes back
+type DisplayAlert struct {
+	Event     string    `json:"event" example:"display.offline"`
+	DisplayID string    `json:"display_id" example:"lobby-tv"`
+	DeviceID  string    `json:"device_id" example:"lobby-tv-1"`
+	Name      string    `json:"name,omitempty" example:"Lobby TV by the door"`
+	Tenant    string    `json:"tenant,omitempty" example:"acme"` // The display's tenant, empty for the default namespace
+	LastSeen  time.Time `json:"last_seen" example:"2025-07-16T15:30:00Z"`
+	Text      string    `json:"text" example:"Display lobby-tv device lobby-tv-1 went dark; last seen 2025-07-16T15:30:00Z"` // For chat webhooks
+}
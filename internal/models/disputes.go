@@ -0,0 +1,64 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ScoreID identifies a score in a game's history by the initials and the exact time it
+// was submitted. Scores stored before IDs were recorded get the same ID when looked up.
+func ScoreID(initials string, timestamp time.Time) string {
+	sum := sha256.Sum256([]byte(initials + "|" + timestamp.UTC().Format(time.RFC3339Nano)))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Errors returned by score disputes
+var (
+	ErrScoreNotFound   = errors.New("score not found")
+	ErrDisputeNotFound = errors.New("dispute not found")
+	ErrDisputeClosed   = errors.New("score already reviewed and kept")
+	ErrTooManyDisputes = errors.New("too many open disputes")
+)
+
+// What an operator decides about a disputed score
+const (
+	DisputeKeep   = "keep"   // The score stands, and can't be flagged again
+	DisputeRemove = "remove" // The score is deleted, undoably, like DELETE /scores
+)
+
+// MaxDisputeReason bounds the reason given with a flag
+const MaxDisputeReason = 500
+
+// DisputeReport is one flag raised against a score
+type DisputeReport struct {
+	Reason    string    `json:"reason" example:"Impossible score for level 3"`
+	FlaggedAt time.Time `json:"flagged_at"`
+}
+
+// ScoreDispute is a score flagged as suspicious, awaiting an operator's review
+type ScoreDispute struct {
+	ScoreID string          `json:"score_id" example:"3f2a9c1e7b4d8a60"`
+	GameID  string          `json:"game_id" example:"pacman"`
+	Entry   ScoreEntry      `json:"entry"`             // The score as it is in history
+	Flags   int             `json:"flags" example:"3"` // Times it has been flagged, including reports no longer kept
+	Reports []DisputeReport `json:"reports"`           // The most recent reports, oldest first
+	Opened  time.Time       `json:"opened_at"`
+}
+
+// DisputesResponse lists a game's open disputes, most flagged first
+type DisputesResponse struct {
+	GameID   string         `json:"game_id" example:"pacman"`
+	Disputes []ScoreDispute `json:"disputes"`
+	Count    int            `json:"count" example:"1"`
+}
+
+// DisputeResolution reports how an operator resolved a dispute
+type DisputeResolution struct {
+	GameID   string            `json:"game_id" example:"pacman"`
+	ScoreID  string            `json:"score_id" example:"3f2a9c1e7b4d8a60"`
+	Action   string            `json:"action" example:"remove" enums:"keep,remove"`
+	Flags    int               `json:"flags" example:"3"`
+	Deletion *ModerationResult `json:"deletion,omitempty"` // What removing the score deleted, with its tombstone for undoing it
+}
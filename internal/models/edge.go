@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// EdgeDeadLetter is a score an edge instance forwarded that the central instance
+// refused outright, kept until an operator replays it
+type EdgeDeadLetter struct {
+	ScoreID   string        `json:"score_id" example:"3f2a9c1d4e5b6a70"` // The edge's entry
+	GameID    string        `json:"game_id" example:"pacman"`
+	Tenant    string        `json:"tenant,omitempty" example:"venue-12"`
+	Initials  string        `json:"initials" example:"AAA"`
+	Score     string        `json:"score" example:"12500"` // As played, written with the game's decimals
+	Metadata  ScoreMetadata `json:"metadata,omitempty" swaggertype:"object"`
+	SessionID string        `json:"session_id,omitempty" example:"cabinet-1:2025-07-16T19"`
+	DeviceID  string        `json:"device_id,omitempty" example:"pacman-cabinet-1"`
+	PlayedAt  time.Time     `json:"played_at" example:"2025-07-16T19:04:05Z"`
+	Status    int           `json:"status" example:"422"` // The central instance's answer; 0 if it was never sent
+	Error     string        `json:"error" example:"status 422: score rejected by the game's anti-cheat rules"`
+	RefusedAt time.Time     `json:"refused_at" example:"2025-07-16T19:05:00Z"`
+}
+
+// EdgeDeadLetters lists the refused forwards an edge instance keeps, oldest first
+type EdgeDeadLetters struct {
+	DeadLetters []EdgeDeadLetter `json:"dead_letters"`
+	Total       int              `json:"total" example:"1"`
+}
+
+// EdgeReplay reports refused forwards put back in an edge instance's outbox
+type EdgeReplay struct {
+	Replayed int `json:"replayed" example:"1"`
+	Pending  int `json:"pending" example:"3"` // Submissions now waiting for the central instance
+}
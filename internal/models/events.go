@@ -0,0 +1,49 @@
+package models
+
+// Board event types sent to live display clients
+const (
+	BoardEventSnapshot = "snapshot"            // The current board, sent when a client connects
+	BoardEventUpdated  = "leaderboard.updated" // The board changed
+	BoardEventResync   = "resync"              // Events were dropped; carries the latest board known to the server
+)
+
+// BoardEvent is one message on a game's live leaderboard stream
+// Clients should ignore events whose version isn't newer than the board they show
+type BoardEvent struct {
+	Type        string       `json:"type" example:"leaderboard.updated"`
+	GameID      string       `json:"game_id" example:"pacman"`
+	Version     int64        `json:"version" example:"42"`
+	Leaderboard *Leaderboard `json:"leaderboard,omitempty"`
+	Previous    *Leaderboard `json:"-"` // The board this one replaced, for server-side consumers; nil for a new game
+	Tenant      string       `json:"-"` // The game's tenant, empty for the default namespace
+}
+
+// ScoreEvent is what a counted submission changed for its player, sent to server-side
+// consumers such as webhooks
+type ScoreEvent struct {
+	GameID            string
+	Tenant            string // The game's tenant, empty for the default namespace
+	Entry             ScoreEntry
+	PreviousHighScore *ScoreEntry     // Nil for the player's first score
+	NewHighScore      bool            // The entry beat the player's previous high score
+	Achievements      []Achievement   // Unlocked by this submission
+	PIN               string          `json:"-"` // The PIN sent with the submission, for forwarding it
+	Signature         *ScoreSignature `json:"-"` // The signature sent with the submission, for forwarding it
+}
+
+// Reasons a game's live board is reset
+const (
+	ResetSeasonStarted = "season.started"
+	ResetSeasonEnded   = "season.ended"
+	ResetGame          = "game.reset"   // An operator started the game afresh
+	ResetGameDeleted   = "game.deleted" // An operator deleted the game
+)
+
+// ResetEvent is a game's live board being emptied, sent to server-side consumers such as
+// the event bus
+type ResetEvent struct {
+	GameID   string
+	Tenant   string // The game's tenant, empty for the default namespace
+	Reason   string // One of the Reset reasons
+	SeasonID string // The season that started or ended
+}
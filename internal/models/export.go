@@ -0,0 +1,125 @@
+package models
+
+import "time"
+
+// Export record types written to NDJSON game exports
+const (
+	ExportRecordLeaderboard = "leaderboard"
+	ExportRecordHighScores  = "high_scores"
+	ExportRecordScore       = "score"
+)
+
+// ExportRecord is a single line in a game's NDJSON export
+type ExportRecord struct {
+	Type        string            `json:"type" example:"score"`
+	GameID      string            `json:"game_id" example:"pacman"`
+	Leaderboard *Leaderboard      `json:"leaderboard,omitempty"`
+	HighScores  *PlayerHighScores `json:"high_scores,omitempty"`
+	Score       *ScoreEntry       `json:"score,omitempty"`
+}
+
+// ExportManifest describes a complete export run stored alongside its game objects
+type ExportManifest struct {
+	ExportID  string         `json:"export_id" example:"20250716T153000Z"`
+	CreatedAt time.Time      `json:"created_at" example:"2025-07-16T15:30:00Z"`
+	Games     []ExportedGame `json:"games"`
+}
+
+// ExportedGame records what was written for one game in an export
+type ExportedGame struct {
+	GameID             string `json:"game_id" example:"pacman"`
+	Object             string `json:"object" example:"exports/20250716T153000Z/games/pacman.ndjson.gz"`
+	Scores             int    `json:"scores" example:"150"`
+	Players            int    `json:"players" example:"25"`
+	LeaderboardEntries int    `json:"leaderboard_entries" example:"10"`
+	Bytes              int    `json:"bytes" example:"4096"`
+	SHA256             string `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
+
+// RestoreReport summarizes a restore from an export run
+type RestoreReport struct {
+	ExportID    string         `json:"export_id" example:"20250716T153000Z"`
+	DryRun      bool           `json:"dry_run" example:"false"`
+	Games       []RestoredGame `json:"games"`
+	CompletedAt time.Time      `json:"completed_at" example:"2025-07-16T15:35:00Z"`
+}
+
+// RestoredGame reports the verified contents of one restored game
+type RestoredGame struct {
+	GameID             string `json:"game_id" example:"pacman"`
+	Scores             int    `json:"scores" example:"150"`
+	Players            int    `json:"players" example:"25"`
+	LeaderboardEntries int    `json:"leaderboard_entries" example:"10"`
+	SHA256             string `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Restored           bool   `json:"restored" example:"true"` // False for dry runs
+}
+
+// Import modes
+const (
+	ImportReplace = "replace" // The import becomes the game's whole history
+	ImportAppend  = "append"  // The import is added to the game's history, skipping scores it already has
+)
+
+// MaxImportProblems caps the invalid scores listed in an import report
+const MaxImportProblems = 100
+
+// ImportReport summarizes an import of downloaded scores into a game
+type ImportReport struct {
+	GameID     string          `json:"game_id" example:"pacman"`
+	Mode       string          `json:"mode" example:"replace"`
+	DryRun     bool            `json:"dry_run" example:"false"`
+	Received   int             `json:"received" example:"150"`  // Scores in the import
+	Duplicates int             `json:"duplicates" example:"0"`  // Scores already in the history, in append mode
+	Scores     int             `json:"scores" example:"150"`    // Scores in the history after the import
+	Players    int             `json:"players" example:"25"`    // Players with a counted score after the import
+	Invalid    int             `json:"invalid" example:"0"`     // Scores that failed validation
+	Problems   []ImportProblem `json:"problems,omitempty"`      // The first MaxImportProblems invalid scores
+	Imported   bool            `json:"imported" example:"true"` // False for dry runs and invalid imports
+}
+
+// ImportProblem is one imported score that failed validation
+type ImportProblem struct {
+	Index    int    `json:"index" example:"3"` // Position in the import, from 1
+	Initials string `json:"initials" example:"A B"`
+	Error    string `json:"error" example:"initials must be exactly 3 characters with no spaces"`
+}
+
+// Anonymization modes for public dataset exports
+const (
+	AnonymizeHash  = "hash"  // Replace initials with a pseudonym that is stable within one dataset
+	AnonymizeStrip = "strip" // Drop player identity entirely
+	// Replace initials with their public player IDs, stable across datasets and matching
+	// the game's public history
+	AnonymizePlayerID = "player_id"
+)
+
+// AnonymizedScore is a single score in a public dataset, with no identifiable player data
+type AnonymizedScore struct {
+	GameID    string    `json:"game_id" example:"pacman"`
+	Player    string    `json:"player,omitempty" example:"3f2a9c1b7e4d8a60"` // Pseudonym, except in strip mode
+	Score     int64     `json:"score" example:"12500"`
+	Timestamp time.Time `json:"timestamp" example:"2025-07-13T15:00:00Z"` // Truncated to DatasetTimestampPrecision
+}
+
+// DatasetManifest describes an anonymized public dataset export run
+type DatasetManifest struct {
+	DatasetID          string        `json:"dataset_id" example:"20250716T153000Z"`
+	CreatedAt          time.Time     `json:"created_at" example:"2025-07-16T15:30:00Z"`
+	Mode               string        `json:"mode" example:"hash"`
+	TimestampPrecision string        `json:"timestamp_precision" example:"1h0m0s"`
+	Games              []DatasetGame `json:"games"`
+}
+
+// DatasetGame summarizes one game's scores in a public dataset
+type DatasetGame struct {
+	GameID       string    `json:"game_id" example:"pacman"`
+	Object       string    `json:"object" example:"exports/datasets/20250716T153000Z/games/pacman.ndjson.gz"`
+	Scores       int       `json:"scores" example:"150"`
+	Players      int       `json:"players,omitempty" example:"25"` // Distinct pseudonyms, only in hash mode
+	MinScore     int64     `json:"min_score" example:"100"`
+	MaxScore     int64     `json:"max_score" example:"98000"`
+	FirstScoreAt time.Time `json:"first_score_at" example:"2025-01-01T00:00:00Z"`
+	LastScoreAt  time.Time `json:"last_score_at" example:"2025-07-16T15:00:00Z"`
+	Bytes        int       `json:"bytes" example:"2048"`
+	SHA256       string    `json:"sha256" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+}
@@ -0,0 +1,257 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrGameLimitExceeded is returned when an API key that has created its limit of games
+// tries to create another
+var ErrGameLimitExceeded = errors.New("game limit exceeded")
+
+// GameInfo represents a game known to the registry
+type GameInfo struct {
+	GameID     string       `json:"game_id" example:"pacman"`
+	CreatedAt  time.Time    `json:"created_at" example:"2025-07-16T15:30:00Z"`                           // When the first score was submitted
+	CreatedBy  string       `json:"created_by,omitempty" example:"123e4567-e89b-12d3-a456-426614174000"` // ID of the API key that created it, empty for the master key
+	MergedInto string       `json:"merged_into,omitempty" example:"pacman"`                              // Set once the game was merged into another; its submissions go there
+	Settings   GameSettings `json:"settings"`
+	Updated    time.Time    `json:"updated" example:"2025-07-16T15:30:00Z"`
+}
+
+// GameSettings holds operator-configured, per-game behavior
+type GameSettings struct {
+	Retention         *RetentionPolicy `json:"retention,omitempty"`
+	MaxEntries        int              `json:"max_entries,omitempty" example:"25"`      // Leaderboard size, 0 uses MAX_SCORE_ENTRIES
+	DailySubmissions  int              `json:"daily_submissions,omitempty" example:"5"` // Counted submissions per initials per UTC day, 0 is unlimited
+	AntiCheat         *AntiCheatRules  `json:"anti_cheat,omitempty"`
+	Scoring           *ScoringSettings `json:"scoring,omitempty"`                            // Decimal and negative scores; whole, non-negative scores if nil
+	RequirePIN        bool             `json:"require_pin,omitempty" example:"true"`         // Submissions under claimed initials must carry their PIN
+	InitialsPolicy    string           `json:"initials_policy,omitempty" example:"device"`   // Who the game's initials stand for; empty is shared
+	HappyHours        []HappyHour      `json:"happy_hours,omitempty"`                        // Scheduled score multiplier windows
+	AchievementTheme  string           `json:"achievement_theme,omitempty" example:"medals"` // Icon theme of the game's achievements; empty is classic
+	Sunset            *GameSunset      `json:"sunset,omitempty"`                             // When the game stops taking submissions
+	SignedSubmissions bool             `json:"signed_submissions,omitempty" example:"true"`  // Submissions must be signed with the game's signing secret
+}
+
+// ErrGameSunset is matched by a GameSunsetError with errors.Is
+var ErrGameSunset = errors.New("game sunset")
+
+// MaxSunsetMessageLength bounds the note integrators are shown about a game's sunset
+const MaxSunsetMessageLength = 200
+
+// GameSunset winds a game down: from At it refuses submissions, while its leaderboard,
+// history and players stay readable. A sunset at or before now makes the game read-only.
+type GameSunset struct {
+	At      time.Time `json:"at" example:"2025-09-01T00:00:00Z"`
+	Message string    `json:"message,omitempty" example:"Pac-Man has moved to pacman-ce"` // For integrators, e.g. where the game went
+}
+
+// Reached reports whether the game has stopped taking submissions at now
+func (s *GameSunset) Reached(now time.Time) bool {
+	return s != nil && !now.Before(s.At)
+}
+
+// Validate checks the sunset has a time and a message integrators can be shown
+func (s GameSunset) Validate() error {
+	if s.At.IsZero() {
+		return errors.New("sunset needs a time")
+	}
+	if len(s.Message) > MaxSunsetMessageLength {
+		return fmt.Errorf("sunset message must be at most %d characters", MaxSunsetMessageLength)
+	}
+	return nil
+}
+
+// GameSunsetError reports a submission to a game past its sunset
+type GameSunsetError struct {
+	GameID string
+	Sunset GameSunset
+}
+
+func (e *GameSunsetError) Error() string {
+	return fmt.Sprintf("game %s stopped taking submissions at %s", e.GameID, e.Sunset.At.Format(time.RFC3339))
+}
+
+func (e *GameSunsetError) Is(target error) bool {
+	return target == ErrGameSunset
+}
+
+// Initials policies decide whether players entering the same initials are the same player
+const (
+	InitialsShared  = "shared"  // Everyone entering the initials is one player, the classic arcade way
+	InitialsDevice  = "device"  // Initials are scoped to the enrolled device that submitted them
+	InitialsClaimed = "claimed" // Only initials claimed with a PIN can submit, and must give the PIN
+)
+
+// ValidInitialsPolicy reports whether policy is a known initials policy or empty
+func ValidInitialsPolicy(policy string) bool {
+	switch policy {
+	case "", InitialsShared, InitialsDevice, InitialsClaimed:
+		return true
+	}
+	return false
+}
+
+// PlayerKey returns the key a game's high scores hold a player's best score under:
+// their initials, or for device-scoped games their initials and device. Scores without
+// a device share the initials' key.
+func (s GameSettings) PlayerKey(initials, deviceID string) string {
+	if s.InitialsPolicy == InitialsDevice && deviceID != "" {
+		return initials + "@" + deviceID
+	}
+	return initials
+}
+
+// LeaderboardResize is a game after its leaderboard size changed, with how the board
+// was refilled from players' existing high scores
+type LeaderboardResize struct {
+	GameInfo
+	PreviousSize int `json:"previous_size" example:"10"`
+	Size         int `json:"size" example:"25"`       // The board size now in effect
+	Entries      int `json:"entries" example:"25"`    // Entries on the regenerated board
+	Backfilled   int `json:"backfilled" example:"15"` // Entries added from existing high scores, 0 when the board shrank
+}
+
+// GameQuota reports the games an API key has created against its game limit
+type GameQuota struct {
+	KeyID   string   `json:"key_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	Limit   int      `json:"limit" example:"100"`    // 0 is unlimited
+	Default bool     `json:"default" example:"true"` // The limit is MAX_GAMES_PER_KEY rather than the key's own
+	Used    int      `json:"used" example:"3"`
+	GameIDs []string `json:"game_ids" example:"pacman"`
+}
+
+// MaxDailySubmissions bounds a game's daily submission budget
+const MaxDailySubmissions = 1000
+
+// SubmissionBudget reports a player's counted submissions for the current UTC day in a
+// game with a daily budget. Plays beyond the budget are kept in history but never reach
+// the high scores or leaderboard.
+type SubmissionBudget struct {
+	Limit     int       `json:"limit" example:"5"`
+	Used      int       `json:"used" example:"3"` // Counted submissions today, including this one
+	Remaining int       `json:"remaining" example:"2"`
+	Counted   bool      `json:"counted" example:"true"` // False when this submission was over budget
+	ResetsAt  time.Time `json:"resets_at" example:"2025-07-17T00:00:00Z"`
+}
+
+// Submission is a score to submit, with any optional detail about the play
+type Submission struct {
+	Initials  string
+	Score     int64
+	Metadata  ScoreMetadata
+	Sequence  int64           // The syncing client's order of the play, for keys with the edge scope; 0 for none
+	PIN       string          // The initials' PIN, for games requiring PINs of claimed initials
+	SessionID string          // The client's play session, grouping plays into visits
+	Signature *ScoreSignature // Proof the client holds the game's signing secret, for games requiring one
+	PlayedAt  time.Time       // When the score was played, for scores forwarded later; zero means now
+	DeviceID  string          // The device the score was played on, overriding the key's own
+}
+
+// SubmissionResult is what a score submission stored
+type SubmissionResult struct {
+	Entry        ScoreEntry
+	Budget       *SubmissionBudget // Nil when the game has no daily budget
+	Achievements []Achievement     // Achievements the score unlocked for its player
+	Sunset       *GameSunset       // The game's upcoming sunset, if one is scheduled
+}
+
+// RetentionPolicy controls how much raw score history is kept for a game
+// Aggregates (player high scores and the leaderboard) are always kept
+type RetentionPolicy struct {
+	HistoryDays int `json:"history_days" example:"180"`           // Days of raw history to keep, 0 keeps everything
+	MaxScores   int `json:"max_scores,omitempty" example:"50000"` // Newest scores to keep, 0 keeps everything
+}
+
+// Enabled reports whether the policy prunes anything
+func (p *RetentionPolicy) Enabled() bool {
+	return p != nil && (p.HistoryDays > 0 || p.MaxScores > 0)
+}
+
+// RetentionResult reports the outcome of pruning one game's history
+type RetentionResult struct {
+	GameID    string     `json:"game_id" example:"pacman"`
+	Cutoff    *time.Time `json:"cutoff,omitempty" example:"2025-01-17T15:30:00Z"` // Absent when only a score count applies
+	Removed   int        `json:"removed" example:"42"`
+	Remaining int        `json:"remaining" example:"108"`
+}
+
+// GameIndex lists every game ID known to the registry
+type GameIndex struct {
+	GameIDs []string  `json:"game_ids"`
+	Updated time.Time `json:"updated"`
+}
+
+// Reasons two game IDs look like duplicates
+const (
+	DuplicateCase       = "case"       // They differ only in letter case
+	DuplicateWhitespace = "whitespace" // They differ only in leading or trailing whitespace
+	DuplicateTypo       = "typo"       // One character was added, removed or changed
+)
+
+// DuplicateGameReport lists groups of registered games that likely are one game
+type DuplicateGameReport struct {
+	Checked int                  `json:"checked" example:"42"` // Registered games compared
+	Groups  []DuplicateGameGroup `json:"groups"`
+}
+
+// DuplicateGameGroup is a set of games whose IDs likely name the same game
+type DuplicateGameGroup struct {
+	Games           []DuplicateGame `json:"games"`                             // Most scores first
+	Reasons         []string        `json:"reasons" example:"case"`            // Why the IDs look alike
+	SuggestedTarget string          `json:"suggested_target" example:"pacman"` // The game with the most scores, to merge the others into
+}
+
+// DuplicateGame is one game of a duplicate group
+type DuplicateGame struct {
+	GameID      string     `json:"game_id" example:"PacMan"`
+	Scores      int        `json:"scores" example:"12"`
+	Players     int        `json:"players" example:"3"`
+	CreatedAt   time.Time  `json:"created_at" example:"2025-07-16T15:30:00Z"`
+	LastScoreAt *time.Time `json:"last_score_at,omitempty" example:"2025-07-16T18:00:00Z"`
+}
+
+// GameMerge reports a merge of one game's scores into another
+type GameMerge struct {
+	Source     string `json:"source" example:"PacMan"`
+	Target     string `json:"target" example:"pacman"`
+	DryRun     bool   `json:"dry_run" example:"false"`
+	Moved      int    `json:"moved" example:"12"`     // Source scores added to the target
+	Duplicates int    `json:"duplicates" example:"0"` // Source scores the target already had
+	Scores     int    `json:"scores" example:"162"`   // Scores in the target after the merge
+	Players    int    `json:"players" example:"27"`   // Players with a counted score in the target after the merge
+	Merged     bool   `json:"merged" example:"true"`  // False for dry runs
+}
+
+// GameSummary is the public, aggregate-only view of a game for embedding on other sites
+type GameSummary struct {
+	GameID       string     `json:"game_id" example:"pacman"`
+	TopScore     int64      `json:"top_score" example:"98000"`
+	PlayerCount  int        `json:"player_count" example:"25"`
+	LastActivity *time.Time `json:"last_activity,omitempty" example:"2025-07-16T15:30:00Z"`
+}
+
+// Public history limits
+const (
+	DefaultPublicHistoryLimit = 50
+	MaxPublicHistoryLimit     = 500
+)
+
+// PublicHistory is a game's recent scores with players identified only by their
+// public IDs, safe to publish
+type PublicHistory struct {
+	GameID      string        `json:"game_id" example:"pacman"`
+	TotalScores int           `json:"total_scores" example:"1240"` // Counted scores in the game's history
+	Players     int           `json:"players" example:"87"`        // Distinct players among them
+	Scores      []PublicScore `json:"scores"`                      // Newest first
+}
+
+// PublicScore is one score in a game's public history
+type PublicScore struct {
+	PlayerID     string    `json:"player_id" example:"3f2a9c1b7e4d8a60"` // Stable for the same initials in the same game, but not reversible
+	Score        int64     `json:"score" example:"12500"`
+	DisplayScore string    `json:"display_score,omitempty" example:"125.00"`
+	Timestamp    time.Time `json:"timestamp" example:"2025-07-16T15:00:00Z"` // Truncated to the hour
+}
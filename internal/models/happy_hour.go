@@ -0,0 +1,182 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+	_ "time/tzdata" // Happy hour time zones resolve on images without zoneinfo
+)
+
+// Happy hour limits
+const (
+	MaxHappyHours          = 20
+	MaxHappyHourMultiplier = 10
+)
+
+var happyHourIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// happyHourDays are the weekday abbreviations a weekly happy hour runs on
+var happyHourDays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// HappyHour multiplies a game's scores while it runs: every week on its days between
+// start and end in its time zone, or once between starts_at and ends_at. Windows whose
+// end is before their start run past midnight into the next day.
+type HappyHour struct {
+	ID         string     `json:"id" example:"friday-night"` // Lowercase letters, digits, _ and -
+	Name       string     `json:"name,omitempty" example:"Friday night double points"`
+	Multiplier float64    `json:"multiplier" example:"2"`                             // Above 1, up to 10
+	Days       []string   `json:"days,omitempty" example:"fri"`                       // Weekly windows: sun to sat
+	Start      string     `json:"start,omitempty" example:"18:00"`                    // Weekly windows: local time it opens
+	End        string     `json:"end,omitempty" example:"21:00"`                      // Weekly windows: local time it closes
+	Timezone   string     `json:"timezone,omitempty" example:"America/New_York"`      // IANA time zone of weekly windows, UTC if empty
+	StartsAt   *time.Time `json:"starts_at,omitempty" example:"2025-12-31T20:00:00Z"` // One-off windows: when it opens
+	EndsAt     *time.Time `json:"ends_at,omitempty" example:"2026-01-01T02:00:00Z"`   // One-off windows: when it closes
+}
+
+// Validate checks the happy hour's ID, multiplier and window, normalizing its days
+func (h *HappyHour) Validate() error {
+	if !happyHourIDPattern.MatchString(h.ID) {
+		return fmt.Errorf("happy hour id %q must be lowercase letters, digits, _ and -, at most 50 characters", h.ID)
+	}
+	if len(h.Name) > 100 {
+		return fmt.Errorf("happy hour %s: name cannot exceed 100 characters", h.ID)
+	}
+	if h.Multiplier <= 1 || h.Multiplier > MaxHappyHourMultiplier || math.IsNaN(h.Multiplier) {
+		return fmt.Errorf("happy hour %s: multiplier must be above 1 and at most %d", h.ID, MaxHappyHourMultiplier)
+	}
+
+	weekly := len(h.Days) > 0 || h.Start != "" || h.End != "" || h.Timezone != ""
+	oneOff := h.StartsAt != nil || h.EndsAt != nil
+	switch {
+	case weekly && oneOff:
+		return fmt.Errorf("happy hour %s: set either days, start and end, or starts_at and ends_at", h.ID)
+	case oneOff:
+		if h.StartsAt == nil || h.EndsAt == nil || !h.EndsAt.After(*h.StartsAt) {
+			return fmt.Errorf("happy hour %s: ends_at must be after starts_at", h.ID)
+		}
+		return nil
+	case !weekly:
+		return fmt.Errorf("happy hour %s: set days, start and end, or starts_at and ends_at", h.ID)
+	}
+
+	if len(h.Days) == 0 {
+		return fmt.Errorf("happy hour %s: days are required", h.ID)
+	}
+	for i, day := range h.Days {
+		day = strings.ToLower(strings.TrimSpace(day))
+		if _, ok := happyHourDays[day]; !ok {
+			return fmt.Errorf("happy hour %s: day %q must be one of sun, mon, tue, wed, thu, fri, sat", h.ID, h.Days[i])
+		}
+		h.Days[i] = day
+	}
+	start, err := clockMinutes(h.Start)
+	if err != nil {
+		return fmt.Errorf("happy hour %s: start %w", h.ID, err)
+	}
+	end, err := clockMinutes(h.End)
+	if err != nil {
+		return fmt.Errorf("happy hour %s: end %w", h.ID, err)
+	}
+	if start == end {
+		return fmt.Errorf("happy hour %s: start and end cannot be the same time", h.ID)
+	}
+	if _, err := time.LoadLocation(h.Timezone); err != nil {
+		return fmt.Errorf("happy hour %s: unknown timezone %q", h.ID, h.Timezone)
+	}
+	return nil
+}
+
+// Active reports whether the happy hour is running at the given time. A happy hour that
+// fails validation is never active.
+func (h *HappyHour) Active(at time.Time) bool {
+	if h.StartsAt != nil && h.EndsAt != nil {
+		return !at.Before(*h.StartsAt) && at.Before(*h.EndsAt)
+	}
+
+	start, err := clockMinutes(h.Start)
+	if err != nil {
+		return false
+	}
+	end, err := clockMinutes(h.End)
+	if err != nil {
+		return false
+	}
+	location, err := time.LoadLocation(h.Timezone)
+	if err != nil {
+		return false
+	}
+
+	local := at.In(location)
+	minute := local.Hour()*60 + local.Minute()
+	if start < end {
+		return h.runsOn(local.Weekday()) && minute >= start && minute < end
+	}
+	// Past midnight: the evening part belongs to today, the early hours to yesterday
+	if minute >= start {
+		return h.runsOn(local.Weekday())
+	}
+	return minute < end && h.runsOn((local.Weekday()+6)%7)
+}
+
+// Apply returns a score multiplied by the happy hour, rounded to the nearest stored
+// unit. Scores below zero are left alone, so a multiplier never deepens a penalty.
+func (h *HappyHour) Apply(score int64) int64 {
+	if score <= 0 {
+		return score
+	}
+	return int64(math.Round(float64(score) * h.Multiplier))
+}
+
+// runsOn reports whether a weekly happy hour opens on the given weekday
+func (h *HappyHour) runsOn(weekday time.Weekday) bool {
+	for _, day := range h.Days {
+		if happyHourDays[strings.ToLower(day)] == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateHappyHours checks a game's happy hours, which must have unique IDs
+func ValidateHappyHours(hours []HappyHour) error {
+	if len(hours) > MaxHappyHours {
+		return fmt.Errorf("a game cannot have more than %d happy hours", MaxHappyHours)
+	}
+	seen := make(map[string]bool, len(hours))
+	for i := range hours {
+		if err := hours[i].Validate(); err != nil {
+			return err
+		}
+		if seen[hours[i].ID] {
+			return fmt.Errorf("happy hour id %q is used more than once", hours[i].ID)
+		}
+		seen[hours[i].ID] = true
+	}
+	return nil
+}
+
+// ActiveHappyHour returns the happy hour with the highest multiplier running at the
+// given time, or nil if none is. Overlapping happy hours don't stack.
+func ActiveHappyHour(hours []HappyHour, at time.Time) *HappyHour {
+	var active *HappyHour
+	for i := range hours {
+		if hours[i].Active(at) && (active == nil || hours[i].Multiplier > active.Multiplier) {
+			active = &hours[i]
+		}
+	}
+	return active
+}
+
+// clockMinutes parses a 24-hour HH:MM time of day into minutes after midnight
+func clockMinutes(clock string) (int, error) {
+	parsed, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("must be a 24-hour time like 18:00")
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
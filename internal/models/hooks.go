@@ -0,0 +1,26 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSubmissionRejected is returned when a submission hook refuses a submission
+var ErrSubmissionRejected = errors.New("submission rejected by hook")
+
+// SubmissionRejectedError reports why a submission hook refused a submission. It
+// matches ErrSubmissionRejected with errors.Is.
+type SubmissionRejectedError struct {
+	Reason string
+}
+
+func (e *SubmissionRejectedError) Error() string {
+	if e.Reason == "" {
+		return ErrSubmissionRejected.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrSubmissionRejected, e.Reason)
+}
+
+func (e *SubmissionRejectedError) Is(target error) bool {
+	return target == ErrSubmissionRejected
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+// Score metadata limits, so history stays small enough to load whole
+const (
+	MaxMetadataKeys        = 16
+	MaxMetadataKeyLength   = 32
+	MaxMetadataValueLength = 256
+	MaxMetadataBytes       = 1024 // Encoded as JSON
+)
+
+var metadataKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ScoreMetadata is optional game-specific detail about a play, such as the level
+// reached or the character used. Values are strings, numbers or booleans.
+type ScoreMetadata map[string]interface{}
+
+// Validate checks the metadata's keys, values and size
+func (m ScoreMetadata) Validate() error {
+	if len(m) > MaxMetadataKeys {
+		return fmt.Errorf("metadata cannot have more than %d keys", MaxMetadataKeys)
+	}
+
+	for key, value := range m {
+		if len(key) > MaxMetadataKeyLength || !metadataKeyPattern.MatchString(key) {
+			return fmt.Errorf("metadata key %q must be lowercase letters, digits and underscores, starting with a letter, at most %d characters", key, MaxMetadataKeyLength)
+		}
+		switch v := value.(type) {
+		case string:
+			if len(v) > MaxMetadataValueLength {
+				return fmt.Errorf("metadata value for %q cannot exceed %d characters", key, MaxMetadataValueLength)
+			}
+		case float64, int, int64, bool:
+		default:
+			return fmt.Errorf("metadata value for %q must be a string, number or boolean", key)
+		}
+	}
+
+	encoded, err := json.Marshal(m)
+	if err != nil {
+		return fmt.Errorf("metadata cannot be encoded: %w", err)
+	}
+	if len(encoded) > MaxMetadataBytes {
+		return fmt.Errorf("metadata cannot exceed %d bytes, got %d", MaxMetadataBytes, len(encoded))
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,13 +10,47 @@ import (
 
 // ScoreEntry represents a simple arcade-style score entry
 type ScoreEntry struct {
-	Initials  string    `json:"initials" example:"AAA"`                       // Three letter initials (e.g., "AAA")
-	Score     int64     `json:"score" example:"12500"`                        // Player's score
-	Timestamp time.Time `json:"timestamp" example:"2025-07-13T15:30:00.000Z"` // When this score was achieved
+	ID             string           `json:"id,omitempty" example:"3f2a9c1e7b4d8a60"`                // Identifies the score for flagging; see ScoreID
+	Initials       string           `json:"initials" example:"AAA"`                                 // Three letter initials (e.g., "AAA")
+	Score          int64            `json:"score" example:"12500"`                                  // Player's score
+	Timestamp      time.Time        `json:"timestamp" example:"2025-07-13T15:30:00.000Z"`           // When this score was achieved
+	NonCounting    bool             `json:"non_counting,omitempty"`                                 // Played over the game's daily budget; kept in history only
+	Flags          []ScoreViolation `json:"flags,omitempty"`                                        // Anti-cheat rules it broke; it counts, but awaits review
+	Metadata       ScoreMetadata    `json:"metadata,omitempty" swaggertype:"object"`                // Game-specific detail submitted with the score
+	Sequence       int64            `json:"sequence,omitempty" example:"1042"`                      // Server-assigned arrival order, breaking ties between equal scores with equal timestamps
+	ClientSequence int64            `json:"client_sequence,omitempty" example:"17"`                 // Order the syncing client gave the play; breaks ties among entries that have one
+	DeviceID       string           `json:"device_id,omitempty" example:"pacman-cabinet-1"`         // The enrolled device that submitted it
+	SessionID      string           `json:"session_id,omitempty" example:"cabinet-1:2025-07-16T19"` // The play session it was submitted in, if the client named one
+
+	// Set for scores submitted during a happy hour: the score as played, the multiplier
+	// applied to it, and the happy hour's ID. Score is the multiplied score.
+	RawScore   int64   `json:"raw_score,omitempty" example:"6250"`
+	Multiplier float64 `json:"multiplier,omitempty" example:"2"`
+	HappyHour  string  `json:"happy_hour,omitempty" example:"friday-night"`
+
+	// The score with the game's decimals, e.g. "12.50" for a stored 1250. Only set for
+	// games that keep decimals.
+	DisplayScore string `json:"display_score,omitempty" example:"12.50"`
 }
 
-// Validate ensures the ScoreEntry meets arcade standards
+// Validate ensures a submitted ScoreEntry meets arcade standards, including the
+// built-in initials blocklist
 func (se *ScoreEntry) Validate() error {
+	if err := se.validateFormat(); err != nil {
+		return err
+	}
+
+	if IsStaticallyBlocked(se.Initials) {
+		return ErrBlockedInitials
+	}
+
+	return nil
+}
+
+// validateFormat checks the entry's shape without the blocklist, so entries stored
+// before initials were blocked can still be served until they're moderated. The score's
+// range depends on the game's scoring settings, so it is checked on submission.
+func (se *ScoreEntry) validateFormat() error {
 	// Normalize initials
 	se.Initials = strings.ToUpper(strings.TrimSpace(se.Initials))
 
@@ -27,12 +62,8 @@ func (se *ScoreEntry) Validate() error {
 		return fmt.Errorf("initials cannot contain spaces")
 	}
 
-	if se.Score < 0 {
-		return fmt.Errorf("score cannot be negative")
-	}
-
-	if se.Score > 999999999 { // Traditional arcade max
-		return fmt.Errorf("score too high - maximum allowed is 999,999,999")
+	if err := se.Metadata.Validate(); err != nil {
+		return err
 	}
 
 	if se.Timestamp.IsZero() {
@@ -42,10 +73,18 @@ func (se *ScoreEntry) Validate() error {
 	return nil
 }
 
+// Leaderboard size limits; the size is configured per deployment and per game
+const (
+	DefaultLeaderboardEntries = 10
+	MaxLeaderboardEntries     = 100
+)
+
 // Leaderboard represents a simple arcade leaderboard
 type Leaderboard struct {
 	GameID  string       `json:"game_id" example:"pacman"` // Unique identifier for the game
-	Entries []ScoreEntry `json:"entries"`                  // Top scores (max 10, sorted by score desc)
+	Entries []ScoreEntry `json:"entries"`                  // Top scores (10 by default, sorted by score desc)
+	Version int64        `json:"version" example:"42"`     // Incremented every time the board is regenerated
+	Updated time.Time    `json:"updated"`                  // When the board was last saved; zero for boards saved before it was recorded
 }
 
 // Validate ensures the Leaderboard meets arcade standards
@@ -58,13 +97,13 @@ func (lb *Leaderboard) Validate() error {
 		return fmt.Errorf("game_id too long - maximum 50 characters")
 	}
 
-	if len(lb.Entries) > 10 {
-		return fmt.Errorf("leaderboard cannot have more than 10 entries")
+	if len(lb.Entries) > MaxLeaderboardEntries {
+		return fmt.Errorf("leaderboard cannot have more than %d entries", MaxLeaderboardEntries)
 	}
 
 	// Validate each entry
 	for i, entry := range lb.Entries {
-		if err := entry.Validate(); err != nil {
+		if err := entry.validateFormat(); err != nil {
 			return fmt.Errorf("entry %d invalid: %w", i, err)
 		}
 	}
@@ -91,6 +130,16 @@ type PlayerStats struct {
 	LastPlayed   time.Time `json:"last_played" example:"2025-07-16T15:30:00Z"`  // Last time this player submitted a score
 	AverageScore float64   `json:"average_score" example:"12000.5"`             // Average of all scores
 	FirstPlayed  time.Time `json:"first_played" example:"2025-07-15T10:15:00Z"` // First time this player submitted a score
+
+	// High and average score with the game's decimals, set in games that keep decimals
+	DisplayHighScore    string `json:"display_high_score,omitempty" example:"150.00"`
+	DisplayAverageScore string `json:"display_average_score,omitempty" example:"120.01"`
+
+	Profile  *PlayerProfile  `json:"profile,omitempty"`  // The profile registered for the initials, if any
+	Activity *PlayerActivity `json:"activity,omitempty"` // When and how often the player plays
+	Standing *PlayerStanding `json:"standing,omitempty"` // Where the high score sits among every player's
+
+	HighScoreMetadata ScoreMetadata `json:"high_score_metadata,omitempty" swaggertype:"object"` // Metadata submitted with the high score
 }
 
 // AllScoresRecord represents the complete score history for a game
@@ -100,6 +149,32 @@ type AllScoresRecord struct {
 	Updated time.Time    `json:"updated"` // Last update timestamp
 }
 
+// Score query orders
+const (
+	ScoreQueryOrderScore = "score" // Highest score first
+	ScoreQueryOrderTime  = "time"  // Newest first
+)
+
+// ScoreQuery selects submissions from a game's score history
+type ScoreQuery struct {
+	MinScore *int64    // Inclusive; nil for no lower bound
+	MaxScore *int64    // Inclusive; nil for no upper bound
+	From     time.Time // Inclusive; zero for no lower bound
+	To       time.Time // Inclusive; zero for no upper bound
+	Limit    int
+	Offset   int
+}
+
+// ScoreQueryResponse is one page of submissions matching a score query
+type ScoreQueryResponse struct {
+	GameID  string       `json:"game_id" example:"pacman"`
+	Order   string       `json:"order" example:"score"` // score (highest first) when a score bound is given, otherwise time (newest first)
+	Scores  []ScoreEntry `json:"scores"`
+	Limit   int          `json:"limit" example:"50"`
+	Offset  int          `json:"offset" example:"0"`
+	HasMore bool         `json:"has_more" example:"true"` // Whether the next offset has more results
+}
+
 // PlayerHighScores represents a mapping of initials to their highest scores
 type PlayerHighScores struct {
 	GameID     string                `json:"game_id" example:"pacman"`
@@ -114,31 +189,209 @@ type Achievement struct {
 	Description string    `json:"description" example:"Submit your first score"`
 	UnlockedAt  time.Time `json:"unlocked_at" example:"2025-07-16T15:30:00Z"`
 	Icon        string    `json:"icon,omitempty" example:"🎯"`
+	Badge       string    `json:"badge,omitempty" example:"/api/v1/games/pacman/achievements/first_score/badge?v=9f86d081"` // Uploaded badge image, shown instead of the icon
 }
 
 // EnhancedPlayerStats represents comprehensive statistics with achievements
 type EnhancedPlayerStats struct {
-	Initials     string        `json:"initials" example:"AAA"`
-	HighScore    int64         `json:"high_score" example:"15000"`
-	TotalScores  int           `json:"total_scores" example:"5"`
-	LastPlayed   time.Time     `json:"last_played" example:"2025-07-16T15:30:00Z"`
-	AverageScore float64       `json:"average_score" example:"12000.5"`
-	FirstPlayed  time.Time     `json:"first_played" example:"2025-07-15T10:15:00Z"`
-	CurrentRank  *int          `json:"current_rank,omitempty" example:"3"`
-	Achievements []Achievement `json:"achievements"`
-	ScoreHistory []ScoreEntry  `json:"score_history,omitempty"` // Optional, only if requested
+	Initials     string    `json:"initials" example:"AAA"`
+	HighScore    int64     `json:"high_score" example:"15000"`
+	TotalScores  int       `json:"total_scores" example:"5"`
+	LastPlayed   time.Time `json:"last_played" example:"2025-07-16T15:30:00Z"`
+	AverageScore float64   `json:"average_score" example:"12000.5"`
+	FirstPlayed  time.Time `json:"first_played" example:"2025-07-15T10:15:00Z"`
+	CurrentRank  *int      `json:"current_rank,omitempty" example:"3"`
+
+	// High and average score with the game's decimals, set in games that keep decimals
+	DisplayHighScore    string `json:"display_high_score,omitempty" example:"150.00"`
+	DisplayAverageScore string `json:"display_average_score,omitempty" example:"120.01"`
+
+	Achievements []Achievement   `json:"achievements"`
+	ScoreHistory []ScoreEntry    `json:"score_history,omitempty"` // Optional, only if requested
+	Profile      *PlayerProfile  `json:"profile,omitempty"`       // The profile registered for the initials, if any
+	Activity     *PlayerActivity `json:"activity,omitempty"`      // When and how often the player plays
+	Standing     *PlayerStanding `json:"standing,omitempty"`      // Where the high score sits among every player's
+	Sessions     *PlayerSessions `json:"sessions,omitempty"`      // Plays grouped into visits
+}
+
+// PlayerActivity describes when and how often a player plays a game. Days and hours
+// are UTC.
+type PlayerActivity struct {
+	CurrentStreak   int           `json:"current_streak" example:"3"` // Consecutive days played, up to today or yesterday
+	LongestStreak   int           `json:"longest_streak" example:"7"` // Most consecutive days ever played
+	MostActiveDay   string        `json:"most_active_day" example:"Saturday"`
+	MostActiveHour  int           `json:"most_active_hour" example:"20"`    // 0-23
+	WeeklyScores    []WeeklyPlays `json:"weekly_scores"`                    // Plays in each of the last 8 weeks, oldest first
+	ImprovementRate float64       `json:"improvement_rate" example:"125.5"` // Points gained per play, from the trend line through every score
+}
+
+// ScoreSpread summarizes a game's high scores, one per player, so repeat plays don't
+// skew it
+type ScoreSpread struct {
+	Players           int     `json:"players" example:"350"`
+	Mean              float64 `json:"mean" example:"12500.5"`
+	Median            float64 `json:"median" example:"11000"`
+	StandardDeviation float64 `json:"standard_deviation" example:"4200.25"` // Population standard deviation
+}
+
+// PlayerStanding places a player's high score within their game's high scores
+type PlayerStanding struct {
+	Percentile float64 `json:"percentile" example:"87.5"` // Share of the other players with a lower high score, 0-100
+	ZScore     float64 `json:"z_score" example:"1.25"`    // Standard deviations above the mean high score, negative below it
+	ScoreSpread
+}
+
+// WeeklyPlays counts a player's plays in the week starting on a Monday
+type WeeklyPlays struct {
+	WeekStart time.Time `json:"week_start" example:"2025-07-14T00:00:00Z"`
+	Scores    int       `json:"scores" example:"12"`
 }
 
 // ScoreAnalysisResponse represents bulk analysis for a game
 type ScoreAnalysisResponse struct {
-	GameID             string                `json:"game_id" example:"pacman"`
-	TotalPlayers       int                   `json:"total_players" example:"25"`
-	TotalScores        int                   `json:"total_scores" example:"150"`
-	HighestScore       int64                 `json:"highest_score" example:"50000"`
-	AverageScore       float64               `json:"average_score" example:"12500.5"`
-	LastActivity       time.Time             `json:"last_activity" example:"2025-07-16T15:30:00Z"`
-	TopPlayers         []EnhancedPlayerStats `json:"top_players"`
-	ScoreDistribution  map[string]int        `json:"score_distribution"` // e.g., "0-1000": 5, "1000-5000": 10
-	RecentAchievements []Achievement         `json:"recent_achievements"`
-	Updated            time.Time             `json:"updated"`
+	GameID              string                `json:"game_id" example:"pacman"`
+	TotalPlayers        int                   `json:"total_players" example:"25"`
+	TotalScores         int                   `json:"total_scores" example:"150"`
+	HighestScore        int64                 `json:"highest_score" example:"50000"`
+	AverageScore        float64               `json:"average_score" example:"12500.5"`
+	DisplayHighestScore string                `json:"display_highest_score,omitempty" example:"500.00"` // With the game's decimals, set in games that keep decimals
+	DisplayAverageScore string                `json:"display_average_score,omitempty" example:"125.01"`
+	LastActivity        time.Time             `json:"last_activity" example:"2025-07-16T15:30:00Z"`
+	TopPlayers          []EnhancedPlayerStats `json:"top_players"`
+	TopPlayersPage      *Pagination           `json:"top_players_pagination,omitempty"`
+	ScoreDistribution   map[string]int        `json:"score_distribution"` // Scores per range of whole points, e.g. "0-999": 5, "1K-5K": 10, "Below 0": 2
+	HighScores          ScoreSpread           `json:"high_scores"`        // Median and spread of player high scores
+	RecentAchievements  []Achievement         `json:"recent_achievements"`
+	Updated             time.Time             `json:"updated"`
+}
+
+// Pagination describes a page within a larger ranked or ordered collection
+type Pagination struct {
+	Offset  int  `json:"offset" example:"0"`      // Index of the first item in this page
+	Limit   int  `json:"limit" example:"25"`      // Maximum number of items requested
+	Total   int  `json:"total" example:"140"`     // Total number of items available
+	HasMore bool `json:"has_more" example:"true"` // Whether more items exist after this page
+}
+
+// PlayerRank represents a player's absolute position among every player of a game
+type PlayerRank struct {
+	GameID       string    `json:"game_id" example:"pacman"`
+	Initials     string    `json:"initials" example:"AAA"`
+	Rank         int       `json:"rank" example:"42"`
+	Score        int64     `json:"score" example:"12500"` // The player's high score
+	TotalPlayers int       `json:"total_players" example:"350"`
+	AchievedAt   time.Time `json:"achieved_at" example:"2025-07-13T15:30:00.000Z"` // When the high score was set
+}
+
+// Ranking is every player's high score in leaderboard order, without the board's truncation
+type Ranking struct {
+	GameID  string       `json:"game_id" example:"pacman"`
+	Entries []ScoreEntry `json:"entries"`
+	Updated time.Time    `json:"updated" example:"2025-07-16T15:30:00Z"`
+}
+
+// RankedEntry is a score with its absolute position among all players
+type RankedEntry struct {
+	Rank      int       `json:"rank" example:"41"`
+	Initials  string    `json:"initials" example:"AAA"`
+	Score     int64     `json:"score" example:"12500"`
+	Timestamp time.Time `json:"timestamp" example:"2025-07-13T15:30:00.000Z"`
+}
+
+// AroundMeResponse is the neighborhood of the board around one player
+type AroundMeResponse struct {
+	GameID       string        `json:"game_id" example:"pacman"`
+	Initials     string        `json:"initials" example:"AAA"`
+	Rank         int           `json:"rank" example:"42"`
+	TotalPlayers int           `json:"total_players" example:"350"`
+	Window       int           `json:"window" example:"3"` // Entries requested above and below the player
+	Entries      []RankedEntry `json:"entries"`
+}
+
+// Receipt statuses reported by public score lookups
+const (
+	ReceiptStatusHighScore  = "high_score" // Still the player's best score
+	ReceiptStatusSuperseded = "superseded" // The player has since scored higher
+	ReceiptStatusRemoved    = "removed"    // No longer on record
+)
+
+// ScoreReceipt is the stored record behind a submission receipt token
+type ScoreReceipt struct {
+	GameID      string    `json:"game_id" example:"pacman"`
+	Initials    string    `json:"initials" example:"AAA"`
+	Score       int64     `json:"score" example:"12500"`
+	SubmittedAt time.Time `json:"submitted_at" example:"2025-07-13T15:30:00.000Z"`
+}
+
+// ReceiptStatus is the current standing of a single receipted score
+type ReceiptStatus struct {
+	ScoreReceipt
+	Status        string `json:"status" example:"high_score"`
+	Rank          int    `json:"rank,omitempty" example:"42"` // Actual rank for high scores, would-be rank for superseded scores
+	TotalPlayers  int    `json:"total_players" example:"350"`
+	OnLeaderboard bool   `json:"on_leaderboard" example:"false"`
+}
+
+// ModerationResult reports what an operator deletion removed from a game
+type ModerationResult struct {
+	GameID    string      `json:"game_id" example:"pacman"`
+	Initials  string      `json:"initials" example:"AAA"`
+	Removed   int         `json:"removed" example:"1"`            // Score history entries removed
+	HighScore *ScoreEntry `json:"high_score,omitempty"`           // The player's high score afterwards, if any remain
+	Remaining int         `json:"remaining_players" example:"24"` // Players left on the game's ranking
+	// The deletion's tombstone and how long it can be undone with, when undo is enabled
+	TombstoneID     string     `json:"tombstone_id,omitempty" example:"8f14e45f-ceea-4c7a-9a0b-2f1c6b1d6e0f"`
+	RestorableUntil *time.Time `json:"restorable_until,omitempty"`
+}
+
+// Kinds of moderation deletion a tombstone undoes
+const (
+	TombstoneScore  = "score"
+	TombstonePlayer = "player"
+)
+
+// ErrTombstoneNotFound is returned when a deletion can't be undone, because its
+// tombstone is unknown or its undo window has passed
+var ErrTombstoneNotFound = errors.New("tombstone not found")
+
+// Tombstone keeps what a moderation deletion removed from a game, so it can be undone
+// until ExpiresAt
+type Tombstone struct {
+	ID           string                `json:"id" example:"8f14e45f-ceea-4c7a-9a0b-2f1c6b1d6e0f"`
+	GameID       string                `json:"game_id" example:"pacman"`
+	Kind         string                `json:"kind" example:"score" enums:"score,player"`
+	Initials     string                `json:"initials" example:"AAA"`
+	Scores       []ScoreEntry          `json:"scores"`                 // History entries removed
+	HighScores   map[string]ScoreEntry `json:"high_scores,omitempty"`  // High scores the deletion replaced, by player key
+	Achievements map[string]time.Time  `json:"achievements,omitempty"` // Unlocks removed with a player, by achievement ID
+	DeletedAt    time.Time             `json:"deleted_at"`
+	ExpiresAt    time.Time             `json:"expires_at"`
+}
+
+// TombstonesResponse lists a game's deletions that can still be undone, newest first
+type TombstonesResponse struct {
+	GameID     string      `json:"game_id" example:"pacman"`
+	Tombstones []Tombstone `json:"tombstones"`
+	Count      int         `json:"count" example:"1"`
+}
+
+// RestoreResult reports what undoing a moderation deletion put back
+type RestoreResult struct {
+	GameID      string      `json:"game_id" example:"pacman"`
+	Initials    string      `json:"initials" example:"AAA"`
+	TombstoneID string      `json:"tombstone_id" example:"8f14e45f-ceea-4c7a-9a0b-2f1c6b1d6e0f"`
+	Restored    int         `json:"restored" example:"1"` // Score history entries put back
+	HighScore   *ScoreEntry `json:"high_score,omitempty"` // The player's high score afterwards
+}
+
+// RecomputeResult reports a player's derived data as rebuilt from a game's history
+type RecomputeResult struct {
+	GameID            string        `json:"game_id" example:"pacman"`
+	Initials          string        `json:"initials" example:"AAA"`
+	Scores            int           `json:"scores" example:"12"`           // History entries found for the player
+	PreviousHighScore *ScoreEntry   `json:"previous_high_score,omitempty"` // The stored high score before the rebuild, if any
+	HighScore         ScoreEntry    `json:"high_score"`                    // The best counted score in history
+	Changed           bool          `json:"changed" example:"true"`        // Whether the stored high score was corrected
+	Rank              int           `json:"rank,omitempty" example:"3"`    // Position on the rebuilt ranking
+	Achievements      []Achievement `json:"achievements"`                  // Unlocked achievements, derived from history
 }
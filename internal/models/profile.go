@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Profile limits
+const (
+	MaxDisplayNameLength = 30               // Characters in a profile's display name
+	MaxAvatarRunes       = 10               // Code points in an avatar, enough for flags and joined emoji
+	MinPINLength         = 4                // Digits in a profile's PIN, at least
+	MaxPINLength         = 8                // and at most
+	MaxPINAttempts       = 5                // Wrong PINs in a row before a profile is locked
+	PINLockout           = 15 * time.Minute // How long a locked profile refuses its PIN
+)
+
+// PlayerProfile is the identity a player registered for their initials, shown with
+// their stats in every game
+type PlayerProfile struct {
+	Initials    string    `json:"initials" example:"AAA"`
+	DisplayName string    `json:"display_name" example:"Ada"`
+	Avatar      string    `json:"avatar,omitempty" example:"👾"`   // A single emoji
+	Country     string    `json:"country,omitempty" example:"GB"` // ISO 3166-1 alpha-2 code
+	CreatedAt   time.Time `json:"created_at" example:"2025-07-16T15:30:00Z"`
+	Updated     time.Time `json:"updated" example:"2025-07-16T15:30:00Z"`
+}
+
+// Validate checks a profile's display name, avatar and country
+func (p *PlayerProfile) Validate() error {
+	if n := utf8.RuneCountInString(p.DisplayName); n < 1 || n > MaxDisplayNameLength {
+		return fmt.Errorf("display name must be between 1 and %d characters", MaxDisplayNameLength)
+	}
+	for _, r := range p.DisplayName {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("display name can only contain printable characters")
+		}
+	}
+	if p.Avatar != "" && !isEmoji(p.Avatar) {
+		return fmt.Errorf("avatar must be a single emoji")
+	}
+	if p.Country != "" && !isCountryCode(p.Country) {
+		return fmt.Errorf("country must be a two-letter ISO 3166-1 code, such as GB")
+	}
+	return nil
+}
+
+// ValidatePIN checks a PIN is MinPINLength to MaxPINLength digits
+func ValidatePIN(pin string) error {
+	if len(pin) < MinPINLength || len(pin) > MaxPINLength {
+		return fmt.Errorf("PIN must be %d to %d digits", MinPINLength, MaxPINLength)
+	}
+	for _, r := range pin {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("PIN must be %d to %d digits", MinPINLength, MaxPINLength)
+		}
+	}
+	return nil
+}
+
+// isEmoji reports whether s looks like one emoji: symbols, optionally joined, with any
+// skin tone modifiers and variation selectors
+func isEmoji(s string) bool {
+	if !utf8.ValidString(s) || utf8.RuneCountInString(s) > MaxAvatarRunes {
+		return false
+	}
+	symbols := 0
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.So, r):
+			symbols++
+		case unicode.Is(unicode.Sk, r), unicode.Is(unicode.Mn, r), r == '\u200d': // Zero-width joiner
+		default:
+			return false
+		}
+	}
+	return symbols > 0
+}
+
+// isCountryCode reports whether s is two upper-case ASCII letters
+func isCountryCode(s string) bool {
+	return len(s) == 2 && s[0] >= 'A' && s[0] <= 'Z' && s[1] >= 'A' && s[1] <= 'Z'
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidScore is returned when a score doesn't fit its game's scoring mode
+var ErrInvalidScore = errors.New("invalid score")
+
+// Score limits. Scores are at most 999,999,999 in magnitude whatever the precision, so
+// a stored score stays exact as a float64 in sorted-set indexes.
+const (
+	MaxScoreDecimals  = 6
+	MaxScoreMagnitude = 999999999
+)
+
+// ScoringSettings set how a game's scores are read, stored and shown. Scores with
+// decimals are stored as fixed-point integers: with 2 decimals, 12.5 is stored as 1250.
+// Nil means whole, non-negative scores.
+type ScoringSettings struct {
+	Decimals      int  `json:"decimals,omitempty" example:"2"`          // Digits after the decimal point, up to 6
+	AllowNegative bool `json:"allow_negative,omitempty" example:"true"` // Accept scores below zero, e.g. golf-style or deltas
+}
+
+// Enabled reports whether the settings differ from whole, non-negative scores
+func (s *ScoringSettings) Enabled() bool {
+	return s != nil && (s.Decimals > 0 || s.AllowNegative)
+}
+
+// Validate checks the precision
+func (s *ScoringSettings) Validate() error {
+	if s.Decimals < 0 || s.Decimals > MaxScoreDecimals {
+		return fmt.Errorf("decimals must be between 0 and %d", MaxScoreDecimals)
+	}
+	return nil
+}
+
+// Precision returns the digits kept after the decimal point, 0 for nil settings
+func (s *ScoringSettings) Precision() int {
+	if s == nil {
+		return 0
+	}
+	return s.Decimals
+}
+
+// scale returns the multiplier from a score to its stored value
+func (s *ScoringSettings) scale() int64 {
+	scale := int64(1)
+	for i := 0; i < s.Precision(); i++ {
+		scale *= 10
+	}
+	return scale
+}
+
+// Unit returns the stored value of one whole point: 100 with 2 decimals
+func (s *ScoringSettings) Unit() int64 {
+	return s.scale()
+}
+
+// Parse converts a submitted decimal score, such as "-3" or "12.50", to its stored
+// fixed-point value, rejecting more decimals than the game keeps
+func (s *ScoringSettings) Parse(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	whole, fraction, _ := strings.Cut(strings.TrimPrefix(value, "-"), ".")
+
+	if whole == "" || strings.ContainsAny(whole+fraction, "+-eE") {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidScore, value)
+	}
+	if fraction = strings.TrimRight(fraction, "0"); len(fraction) > s.Precision() {
+		if s.Precision() == 0 {
+			return 0, fmt.Errorf("%w: this game only accepts whole scores", ErrInvalidScore)
+		}
+		return 0, fmt.Errorf("%w: this game keeps at most %d decimal places", ErrInvalidScore, s.Precision())
+	}
+
+	digits := whole + fraction + strings.Repeat("0", s.Precision()-len(fraction))
+	if len(strings.TrimLeft(digits, "0")) > 18 {
+		return 0, fmt.Errorf("%w: %s is too large", ErrInvalidScore, value)
+	}
+	stored, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidScore, value)
+	}
+	if negative {
+		stored = -stored
+	}
+	return stored, s.Check(stored)
+}
+
+// Check reports whether a stored score is allowed: in range, and not negative unless
+// the game allows it
+func (s *ScoringSettings) Check(stored int64) error {
+	if stored < 0 && (s == nil || !s.AllowNegative) {
+		return fmt.Errorf("%w: score cannot be negative", ErrInvalidScore)
+	}
+	limit := MaxScoreMagnitude * s.scale()
+	if stored > limit {
+		return fmt.Errorf("%w: score too high - maximum allowed is 999,999,999", ErrInvalidScore)
+	}
+	if stored < -limit {
+		return fmt.Errorf("%w: score too low - minimum allowed is -999,999,999", ErrInvalidScore)
+	}
+	return nil
+}
+
+// Clamp limits a stored score to the allowed range
+func (s *ScoringSettings) Clamp(stored int64) int64 {
+	limit := MaxScoreMagnitude * s.scale()
+	return max(-limit, min(stored, limit))
+}
+
+// Format shows a stored score with the game's decimals, e.g. 1250 as "12.50"
+func (s *ScoringSettings) Format(stored int64) string {
+	if s.Precision() == 0 {
+		return strconv.FormatInt(stored, 10)
+	}
+
+	sign := ""
+	magnitude := uint64(stored)
+	if stored < 0 {
+		sign = "-"
+		magnitude = uint64(-stored)
+	}
+	scale := uint64(s.scale())
+	return fmt.Sprintf("%s%d.%0*d", sign, magnitude/scale, s.Precision(), magnitude%scale)
+}
+
+// FormatMean shows an average of stored scores with the game's decimals, e.g. 1237.5 as
+// "12.38"
+func (s *ScoringSettings) FormatMean(mean float64) string {
+	return strconv.FormatFloat(mean/float64(s.scale()), 'f', s.Precision(), 64)
+}
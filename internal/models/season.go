@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+// Season statuses
+const (
+	SeasonActive = "active"
+	SeasonEnded  = "ended"
+)
+
+// Season is a competition period; the live leaderboard only holds the active season's
+// scores, and each season's final board is archived when it ends
+type Season struct {
+	SeasonID  string      `json:"season_id" example:"summer-2025"`
+	GameID    string      `json:"game_id" example:"pacman"`
+	Name      string      `json:"name,omitempty" example:"Summer 2025"`
+	Status    string      `json:"status" example:"active"`
+	StartedAt time.Time   `json:"started_at" example:"2025-06-01T00:00:00Z"`
+	EndsAt    *time.Time  `json:"ends_at,omitempty" example:"2025-09-01T00:00:00Z"` // Scheduled end; open-ended seasons run until ended or replaced
+	EndedAt   *time.Time  `json:"ended_at,omitempty" example:"2025-09-01T00:00:00Z"`
+	Players   int         `json:"players" example:"42"` // Players on the archived board, once ended
+	Champion  *ScoreEntry `json:"champion,omitempty"`   // Top of the archived board, once ended
+}
+
+// GameSeasons lists a game's seasons, oldest first
+type GameSeasons struct {
+	GameID     string     `json:"game_id" example:"pacman"`
+	BoardSince *time.Time `json:"board_since,omitempty" example:"2025-06-01T00:00:00Z"` // When the live board was last reset by a season starting or ending
+	Seasons    []Season   `json:"seasons"`
+}
+
+// Active returns the active season, or nil between seasons
+func (g *GameSeasons) Active() *Season {
+	for i := range g.Seasons {
+		if g.Seasons[i].Status == SeasonActive {
+			return &g.Seasons[i]
+		}
+	}
+	return nil
+}
+
+// Find returns the season with seasonID, or nil
+func (g *GameSeasons) Find(seasonID string) *Season {
+	for i := range g.Seasons {
+		if g.Seasons[i].SeasonID == seasonID {
+			return &g.Seasons[i]
+		}
+	}
+	return nil
+}
+
+// SeasonLeaderboard is a season's board: the live board while it's active, and the
+// archived final board once it has ended
+type SeasonLeaderboard struct {
+	Season  Season       `json:"season"`
+	Entries []ScoreEntry `json:"entries"`
+	Total   int          `json:"total" example:"42"` // Players on the full board, beyond the entries returned
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// Session grouping limits
+const (
+	MaxSessionIDLength = 64
+	SessionGap         = 30 * time.Minute // Longest pause between plays of an inferred session
+	RecentSessions     = 10
+)
+
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+// ValidateSessionID checks a submitted session ID, which may be empty
+func ValidateSessionID(id string) error {
+	if id == "" {
+		return nil
+	}
+	if len(id) > MaxSessionIDLength || !sessionIDPattern.MatchString(id) {
+		return fmt.Errorf("session_id must be letters, digits, '.', '_', ':' and '-', at most %d characters", MaxSessionIDLength)
+	}
+	return nil
+}
+
+// PlayerSessions groups a player's plays into visits. Plays submitted with a session ID
+// belong to that session; the rest are grouped by pauses of at most 30 minutes.
+type PlayerSessions struct {
+	Count        int            `json:"count" example:"12"`
+	AveragePlays float64        `json:"average_plays" example:"4.5"` // Plays per session
+	Best         SessionStats   `json:"best"`                        // The session with the highest score, the most recent on ties
+	Recent       []SessionStats `json:"recent"`                      // The last 10 sessions, newest first
+}
+
+// SessionStats summarizes one visit's plays
+type SessionStats struct {
+	SessionID    string    `json:"session_id,omitempty" example:"cabinet-1:2025-07-16T19"` // As submitted; empty for sessions inferred from pauses
+	Start        time.Time `json:"start" example:"2025-07-16T19:02:00Z"`
+	End          time.Time `json:"end" example:"2025-07-16T19:41:00Z"`
+	Plays        int       `json:"plays" example:"6"`
+	BestScore    int64     `json:"best_score" example:"15000"`
+	AverageScore float64   `json:"average_score" example:"11250.5"`
+	FirstScore   int64     `json:"first_score" example:"8000"`
+	LastScore    int64     `json:"last_score" example:"15000"`
+	Improvement  int64     `json:"improvement" example:"7000"` // Last score minus first
+}
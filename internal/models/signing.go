@@ -0,0 +1,61 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureWindow bounds how far a signed submission's timestamp may be from the
+// time it was played, which is the server's clock unless the score was forwarded
+const SignatureWindow = 5 * time.Minute
+
+// SignedForwardMaxAge bounds how long after it was signed a forwarded signed score is
+// still accepted. Nonces are remembered for longer, so none can be replayed while its
+// timestamp is accepted.
+const SignedForwardMaxAge = 24 * time.Hour
+
+// Nonce length bounds for signed submissions
+const (
+	MinNonceLength = 8
+	MaxNonceLength = 64
+)
+
+var (
+	// ErrSignatureRequired is returned for unsigned submissions to games requiring signatures
+	ErrSignatureRequired = errors.New("score signature required")
+	// ErrInvalidScoreSignature is returned for forged, stale or replayed score signatures
+	ErrInvalidScoreSignature = errors.New("invalid score signature")
+)
+
+// ScoreSignature proves a submission was made by a holder of the game's signing secret
+type ScoreSignature struct {
+	Timestamp int64  `json:"timestamp" example:"1752680000"`                                                       // Unix seconds when the score was signed
+	Nonce     string `json:"nonce" example:"6f1c2a9e04b7"`                                                         // Unique per submission, 8 to 64 characters
+	Signature string `json:"signature" example:"3c5d8f0e6a1b2c4d7e9f0a1b2c3d4e5f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d"` // Hex HMAC-SHA256 of the signing payload
+}
+
+// SigningSecret is the secret a game's submissions are signed with, shown only when
+// it's created
+type SigningSecret struct {
+	GameID    string    `json:"game_id" example:"pacman"`
+	Secret    string    `json:"secret" example:"9b1f0c4e2d7a..."`
+	CreatedAt time.Time `json:"created_at" example:"2025-07-16T15:30:00Z"`
+}
+
+// ScoreSigningPayload is the message a submission's signature covers: the game ID,
+// upper-case initials, score as the game formats it, timestamp and nonce, one per line
+func ScoreSigningPayload(gameID, initials, score string, timestamp int64, nonce string) string {
+	return strings.Join([]string{gameID, initials, score, strconv.FormatInt(timestamp, 10), nonce}, "\n")
+}
+
+// SignScore returns the hex HMAC-SHA256 of payload under secret, as clients send it
+func SignScore(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Time series bucket sizes
+const (
+	TimeseriesHour = "hour"
+	TimeseriesDay  = "day"
+)
+
+// How many buckets of each size a game's time series keeps
+const (
+	MaxTimeseriesHours = 168 // A week of hours
+	MaxTimeseriesDays  = 366
+)
+
+// ScoreTimeseries charts a game's submissions over recent UTC hours or days
+type ScoreTimeseries struct {
+	GameID  string             `json:"game_id" example:"pacman"`
+	Bucket  string             `json:"bucket" example:"hour"`
+	Buckets []TimeseriesBucket `json:"buckets"` // Oldest first, including buckets without submissions
+}
+
+// TimeseriesBucket summarizes the submissions in one hour or day
+type TimeseriesBucket struct {
+	Start        time.Time `json:"start" example:"2025-07-16T15:00:00Z"`
+	Scores       int       `json:"scores" example:"42"`             // Submissions, including non-counting ones
+	Players      int       `json:"players" example:"9"`             // Distinct players who submitted
+	AverageScore float64   `json:"average_score" example:"12500.5"` // 0 without submissions
+
+	DisplayAverageScore string `json:"display_average_score,omitempty" example:"125.01"` // With the game's decimals, set in games that keep decimals
+}
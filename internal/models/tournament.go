@@ -0,0 +1,110 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tournament scoring modes
+const (
+	TournamentBest  = "best"  // A player's best score in any member game
+	TournamentTotal = "total" // The sum of a player's best score in each member game
+)
+
+// Tournament statuses, derived from the time window
+const (
+	TournamentUpcoming = "upcoming"
+	TournamentLive     = "live"
+	TournamentFinished = "finished"
+)
+
+// Tournament limits
+const (
+	MaxTournamentGames = 20 // Member games of one tournament
+)
+
+// Tournament is a competition across several games within a time window. Standings are
+// derived from the member games' score histories, so games need no setup to join one.
+type Tournament struct {
+	TournamentID string    `json:"tournament_id" example:"spring-cup"`
+	Name         string    `json:"name,omitempty" example:"Spring Cup"`
+	GameIDs      []string  `json:"game_ids" example:"pacman,galaga"`
+	Scoring      string    `json:"scoring" example:"total"` // best or total (the default)
+	StartsAt     time.Time `json:"starts_at" example:"2025-04-01T00:00:00Z"`
+	EndsAt       time.Time `json:"ends_at" example:"2025-04-30T00:00:00Z"` // Scores at or after this time don't count
+	Updated      time.Time `json:"updated" example:"2025-03-20T15:30:00Z"`
+}
+
+// Validate checks a tournament and defaults its scoring mode
+func (t *Tournament) Validate() error {
+	if len(t.Name) > 100 {
+		return fmt.Errorf("name cannot exceed 100 characters")
+	}
+	if len(t.GameIDs) == 0 {
+		return fmt.Errorf("a tournament needs at least one game")
+	}
+	if len(t.GameIDs) > MaxTournamentGames {
+		return fmt.Errorf("a tournament can have at most %d games", MaxTournamentGames)
+	}
+	seen := make(map[string]bool, len(t.GameIDs))
+	for _, gameID := range t.GameIDs {
+		if len(gameID) < 1 || len(gameID) > 50 {
+			return fmt.Errorf("game IDs must be between 1 and 50 characters")
+		}
+		if seen[gameID] {
+			return fmt.Errorf("game %s is listed twice", gameID)
+		}
+		seen[gameID] = true
+	}
+
+	switch t.Scoring {
+	case "":
+		t.Scoring = TournamentTotal
+	case TournamentBest, TournamentTotal:
+	default:
+		return fmt.Errorf("scoring must be %s or %s", TournamentBest, TournamentTotal)
+	}
+
+	if t.StartsAt.IsZero() || t.EndsAt.IsZero() {
+		return fmt.Errorf("starts_at and ends_at are required")
+	}
+	if !t.EndsAt.After(t.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	return nil
+}
+
+// Status reports whether the tournament is upcoming, live or finished at now
+func (t *Tournament) Status(now time.Time) string {
+	switch {
+	case now.Before(t.StartsAt):
+		return TournamentUpcoming
+	case now.Before(t.EndsAt):
+		return TournamentLive
+	default:
+		return TournamentFinished
+	}
+}
+
+// Counts reports whether a score played at timestamp falls in the tournament's window
+func (t *Tournament) Counts(timestamp time.Time) bool {
+	return !timestamp.Before(t.StartsAt) && timestamp.Before(t.EndsAt)
+}
+
+// TournamentStanding is one player's place in a tournament
+type TournamentStanding struct {
+	Rank         int              `json:"rank" example:"1"` // Players with equal scores share a rank
+	Initials     string           `json:"initials" example:"AAA"`
+	Score        int64            `json:"score" example:"42000"`
+	DisplayScore string           `json:"display_score,omitempty" example:"420.00"` // Score formatted in the games' decimals
+	Games        map[string]int64 `json:"games"`                                    // The player's best score in each game they played
+}
+
+// TournamentStandings ranks a tournament's players
+type TournamentStandings struct {
+	Tournament Tournament           `json:"tournament"`
+	Status     string               `json:"status" example:"live"`
+	Standings  []TournamentStanding `json:"standings"`
+	Players    int                  `json:"players" example:"42"` // Players in the full standings, beyond those returned
+	Updated    time.Time            `json:"updated" example:"2025-04-10T15:30:00Z"`
+}
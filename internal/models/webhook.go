@@ -0,0 +1,125 @@
+package models
+
+import "time"
+
+// Webhook event types
+const (
+	WebhookEventPosition    = "leaderboard.position" // A player crossed the rank threshold in a webhook's condition
+	WebhookEventHighScore   = "score.high_score"     // A player beat their own high score
+	WebhookEventTopScore    = "leaderboard.top_1"    // A new score took first place
+	WebhookEventAchievement = "achievement.unlocked" // A submission unlocked achievements
+	WebhookEventTest        = "webhook.test"         // Sent on request to check a receiver; never subscribed to
+)
+
+// WebhookEventTypes lists the events a webhook can subscribe to
+var WebhookEventTypes = []string{WebhookEventPosition, WebhookEventHighScore, WebhookEventTopScore, WebhookEventAchievement}
+
+// Webhook delivery formats
+const (
+	WebhookFormatJSON     = "json"     // WebhookEvent as JSON, the default
+	WebhookFormatProtobuf = "protobuf" // The rawboard.v1.Event protobuf message, for high score and achievement events only
+)
+
+// Webhook limits
+const (
+	MaxWebhooksPerGame    = 20  // Webhooks registered for one game
+	MaxWebhookDeadLetters = 100 // Failed deliveries kept per game; the oldest are dropped first
+)
+
+// Webhook is a URL notified of a game's leaderboard events
+type Webhook struct {
+	ID        string    `json:"id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	GameID    string    `json:"game_id" example:"pacman"`
+	URL       string    `json:"url" example:"https://hooks.example.com/rawboard"`
+	Events    []string  `json:"events" example:"leaderboard.position,score.high_score"`
+	Condition string    `json:"condition,omitempty" example:"any enters top 3"` // For leaderboard.position; see the README for the syntax
+	Format    string    `json:"format,omitempty" example:"protobuf"`            // protobuf, or omitted for json
+	Signed    bool      `json:"signed" example:"true"`                          // Deliveries carry an X-Rawboard-Signature
+	CreatedAt time.Time `json:"created_at" example:"2025-07-16T15:30:00Z"`
+}
+
+// Subscribes reports whether the webhook receives events of the given type. Webhooks
+// registered before event subscriptions only receive position events.
+func (w Webhook) Subscribes(eventType string) bool {
+	if len(w.Events) == 0 {
+		return eventType == WebhookEventPosition
+	}
+	for _, event := range w.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// CreatedWebhook is returned once when a webhook is created and includes its signing secret
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret" example:"whsec_3f2a9c1b..."` // Only shown at creation
+}
+
+// PositionChange is one player crossing a webhook's rank threshold
+type PositionChange struct {
+	Initials     string `json:"initials" example:"AAA"`
+	Score        int64  `json:"score" example:"15000"`
+	PreviousRank int    `json:"previous_rank" example:"5"` // 0 when the player wasn't on the board
+	Rank         int    `json:"rank" example:"2"`          // 0 when the player is no longer on the board
+}
+
+// WebhookEvent is the payload POSTed to a webhook. Which fields are set depends on the type.
+type WebhookEvent struct {
+	ID                string           `json:"id" example:"8f14e45f-ceea-467f-a8f5-123456789abc"` // Unique per delivery
+	Type              string           `json:"type" example:"leaderboard.position"`
+	WebhookID         string           `json:"webhook_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	GameID            string           `json:"game_id" example:"pacman"`
+	Condition         string           `json:"condition,omitempty" example:"any enters top 3"`
+	Changes           []PositionChange `json:"changes,omitempty"`
+	Entry             *ScoreEntry      `json:"entry,omitempty"`               // The submitted score, or the new first place
+	PreviousHighScore *ScoreEntry      `json:"previous_high_score,omitempty"` // The score beaten; nil for a player's or board's first
+	Achievements      []Achievement    `json:"achievements,omitempty"`
+	Leaderboard       *Leaderboard     `json:"leaderboard,omitempty"`
+	Test              bool             `json:"test,omitempty"` // Synthetic, sent by the test endpoint
+	Timestamp         time.Time        `json:"timestamp" example:"2025-07-16T15:30:00Z"`
+}
+
+// WebhookTestResult reports a synthetic event's delivery to a webhook
+type WebhookTestResult struct {
+	Delivered  bool         `json:"delivered" example:"true"`
+	StatusCode int          `json:"status_code,omitempty" example:"200"` // 0 when no response arrived
+	Error      string       `json:"error,omitempty" example:"receiver returned 404 Not Found"`
+	DurationMS int64        `json:"duration_ms" example:"84"`
+	Event      WebhookEvent `json:"event"` // What was sent
+}
+
+// WebhookDeadLetter records a delivery that failed every attempt
+type WebhookDeadLetter struct {
+	Event     WebhookEvent `json:"event"`
+	URL       string       `json:"url" example:"https://hooks.example.com/rawboard"`
+	Attempts  int          `json:"attempts" example:"4"`
+	LastError string       `json:"last_error" example:"receiver returned 503 Service Unavailable"`
+	FailedAt  time.Time    `json:"failed_at" example:"2025-07-16T15:30:15Z"`
+}
+
+// WebhookDelivery is one attempt to deliver a webhook event, as kept by the development
+// webhook log
+type WebhookDelivery struct {
+	ID                string            `json:"id" example:"8f14e45f-ceea-467f-a8f5-123456789abc"` // The event's delivery ID, shared by its retries
+	WebhookID         string            `json:"webhook_id" example:"123e4567-e89b-12d3-a456-426614174000"`
+	GameID            string            `json:"game_id" example:"pacman"`
+	Tenant            string            `json:"-"`
+	EventType         string            `json:"event_type" example:"score.high_score"`
+	URL               string            `json:"url" example:"https://hooks.example.com/rawboard"`
+	Attempt           int               `json:"attempt" example:"1"` // 1 for the first try, counting up through retries
+	Test              bool              `json:"test,omitempty"`      // Sent by the test endpoint
+	RequestHeaders    map[string]string `json:"request_headers"`
+	Body              string            `json:"body"`
+	BodyEncoding      string            `json:"body_encoding,omitempty" example:"base64"` // base64 for protobuf bodies, empty for JSON
+	Delivered         bool              `json:"delivered" example:"true"`
+	StatusCode        int               `json:"status_code,omitempty" example:"200"` // 0 when no response arrived
+	ResponseHeaders   map[string]string `json:"response_headers,omitempty"`
+	ResponseBody      string            `json:"response_body,omitempty" example:"ok"`
+	ResponseTruncated bool              `json:"response_truncated,omitempty"` // The response body was longer than the log keeps
+	Error             string            `json:"error,omitempty" example:"receiver returned 404 Not Found"`
+	DurationMS        int64             `json:"duration_ms" example:"84"`
+	SentAt            time.Time         `json:"sent_at" example:"2025-07-16T15:30:00Z"`
+}
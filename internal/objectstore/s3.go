@@ -0,0 +1,309 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Config holds connection settings for an S3-compatible object store
+type S3Config struct {
+	Endpoint        string // e.g. https://s3.us-east-1.amazonaws.com or http://minio:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	UsePathStyle    bool // Address the bucket in the path instead of the host (MinIO, Ceph)
+}
+
+// S3Store stores objects in an S3-compatible bucket using Signature Version 4
+type S3Store struct {
+	config   S3Config
+	endpoint *url.URL
+	client   *http.Client
+	now      func() time.Time
+}
+
+// NewS3Store creates a new S3-compatible object store client
+func NewS3Store(config S3Config) (*S3Store, error) {
+	if config.Bucket == "" {
+		return nil, fmt.Errorf("bucket is required")
+	}
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q", config.Endpoint)
+	}
+
+	return &S3Store{
+		config:   config,
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 60 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+// Put uploads an object
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	headers := map[string]string{}
+	if contentType != "" {
+		headers["Content-Type"] = contentType
+	}
+
+	resp, err := s.do(ctx, http.MethodPut, key, nil, data, headers)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s.responseError("put", key, resp)
+	}
+	return nil
+}
+
+// Get downloads an object
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, s.responseError("get", key, resp)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// listBucketResult is the subset of the ListObjectsV2 response we use
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// List returns the keys of every object under prefix in lexical order
+func (s *S3Store) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := make([]string, 0)
+	token := ""
+
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", prefix)
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := s.do(ctx, http.MethodGet, "", query, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			err := s.responseError("list", prefix, resp)
+			resp.Body.Close()
+			return nil, err
+		}
+
+		var result listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode list response: %w", err)
+		}
+
+		for _, object := range result.Contents {
+			keys = append(keys, object.Key)
+		}
+
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// do builds, signs, and sends a request for an object key (or the bucket if key is empty)
+func (s *S3Store) do(ctx context.Context, method, key string, query url.Values, body []byte, headers map[string]string) (*http.Response, error) {
+	host := s.endpoint.Host
+	path := "/"
+	if s.config.UsePathStyle {
+		path += s.config.Bucket + "/"
+	} else {
+		host = s.config.Bucket + "." + host
+	}
+	path += key
+
+	canonicalPath := encodePath(path)
+	canonicalQuery := encodeQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint.Scheme+"://"+host+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	// Send the path exactly as signed rather than letting net/url re-escape it
+	req.URL.Opaque = "//" + host + canonicalPath
+	req.URL.RawQuery = canonicalQuery
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	s.sign(req, host, canonicalPath, canonicalQuery, body)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("object store request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers to a request
+func (s *S3Store) sign(req *http.Request, host, canonicalPath, canonicalQuery string, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	dateStamp := now.Format("20060102")
+
+	payloadHash := sha256.Sum256(body)
+	payloadHex := hex.EncodeToString(payloadHash[:])
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHex)
+
+	// Canonical headers must be lowercase and sorted
+	signed := map[string]string{
+		"host":                 host,
+		"x-amz-content-sha256": payloadHex,
+		"x-amz-date":           amzDate,
+	}
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		signed["content-type"] = contentType
+	}
+
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(signed[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath,
+		canonicalQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHex,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", dateStamp, s.config.Region)
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(requestHash[:]),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+s.config.SecretAccessKey), dateStamp)
+	signingKey = hmacSHA256(signingKey, s.config.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// responseError builds an error from a failed object store response
+func (s *S3Store) responseError(operation, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("object store %s %s failed with status %d: %s",
+		operation, key, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// encodePath URI-encodes each path segment as required by Signature Version 4
+func encodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = uriEncode(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// encodeQuery builds a canonical, sorted query string
+func encodeQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved characters
+func uriEncode(value string) string {
+	var buf strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' {
+			buf.WriteByte(b)
+		} else {
+			fmt.Fprintf(&buf, "%%%02X", b)
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,155 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeS3 is a tiny path-style S3 server supporting PUT, GET and ListObjectsV2
+type fakeS3 struct {
+	mu      sync.Mutex
+	bucket  string
+	objects map[string][]byte
+	pageLen int
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test-key/") || !strings.Contains(auth, "Signature=") {
+		http.Error(w, "missing signature", http.StatusForbidden)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	hash := sha256.Sum256(body)
+	if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+		http.Error(w, "payload hash mismatch", http.StatusBadRequest)
+		return
+	}
+
+	prefix := "/" + f.bucket + "/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+	key := strings.TrimPrefix(r.URL.Path, prefix)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	switch {
+	case r.Method == http.MethodPut:
+		f.objects[key] = body
+	case r.Method == http.MethodGet && key == "":
+		f.list(w, r)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			http.Error(w, "no such key", http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, r *http.Request) {
+	keys := make([]string, 0)
+	for key := range f.objects {
+		if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	start := 0
+	if token := r.URL.Query().Get("continuation-token"); token != "" {
+		fmt.Sscanf(token, "%d", &start)
+	}
+	end := start + f.pageLen
+	if end > len(keys) {
+		end = len(keys)
+	}
+
+	fmt.Fprint(w, "<ListBucketResult>")
+	for _, key := range keys[start:end] {
+		fmt.Fprintf(w, "<Contents><Key>%s</Key></Contents>", key)
+	}
+	if end < len(keys) {
+		fmt.Fprintf(w, "<IsTruncated>true</IsTruncated><NextContinuationToken>%d</NextContinuationToken>", end)
+	}
+	fmt.Fprint(w, "</ListBucketResult>")
+}
+
+func TestS3Store(t *testing.T) {
+	fake := &fakeS3{bucket: "backups", objects: make(map[string][]byte), pageLen: 2}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	store, err := NewS3Store(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          "backups",
+		AccessKeyID:     "test-key",
+		SecretAccessKey: "test-secret",
+		UsePathStyle:    true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	ctx := context.Background()
+
+	t.Run("round trips objects", func(t *testing.T) {
+		if err := store.Put(ctx, "exports/run1/games/pac man.ndjson.gz", []byte("hello"), "application/gzip"); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+
+		data, err := store.Get(ctx, "exports/run1/games/pac man.ndjson.gz")
+		if err != nil {
+			t.Fatalf("Get failed: %v", err)
+		}
+		if string(data) != "hello" {
+			t.Errorf("Expected object contents 'hello', got %q", data)
+		}
+	})
+
+	t.Run("reports missing objects", func(t *testing.T) {
+		_, err := store.Get(ctx, "exports/missing")
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("lists every page under a prefix", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			if err := store.Put(ctx, fmt.Sprintf("exports/run2/games/g%d.ndjson.gz", i), []byte("x"), ""); err != nil {
+				t.Fatalf("Put failed: %v", err)
+			}
+		}
+
+		keys, err := store.List(ctx, "exports/run2/")
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		if len(keys) != 5 {
+			t.Errorf("Expected 5 keys across pages, got %d: %v", len(keys), keys)
+		}
+	})
+
+	t.Run("requires a bucket", func(t *testing.T) {
+		if _, err := NewS3Store(S3Config{Endpoint: server.URL}); err == nil {
+			t.Error("Expected an error when no bucket is configured")
+		}
+	})
+}
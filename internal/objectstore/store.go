@@ -0,0 +1,16 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Store is a minimal object storage interface used for exports and restores
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	List(ctx context.Context, prefix string) ([]string, error)
+}
@@ -0,0 +1,251 @@
+package openapi
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Annotation holds the swag-style comment annotations of one handler function
+type Annotation struct {
+	Handler     string // Function name, used as the operation ID
+	Summary     string
+	Description string
+	Tags        []string
+	Params      []ParamAnnotation
+	Responses   []ResponseAnnotation
+	Security    []string
+	Path        string
+	Method      string
+}
+
+// ParamAnnotation is one @Param line: name in type required "description" [attributes],
+// where swag-style attributes such as minlength(1) maxlength(50) constrain primitives
+type ParamAnnotation struct {
+	Name        string
+	In          string // path, query, header or body
+	Type        string // A primitive for path, query and header; a type name for body
+	Required    bool
+	Description string
+	MinLength   *int
+	MaxLength   *int
+	Minimum     *float64
+	Maximum     *float64
+}
+
+// ResponseAnnotation is one @Success or @Failure line: code {object|array} Type "description"
+type ResponseAnnotation struct {
+	Code        int
+	Kind        string // object or array; empty when there is no body
+	Type        string
+	Description string
+}
+
+// ParseDir reads the annotations of every function in dir that has an @Router line
+func ParseDir(dir string) ([]Annotation, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	fset := token.NewFileSet()
+	annotations := []Annotation{}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range parsed.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Doc == nil {
+				continue
+			}
+
+			annotation, found, err := parseAnnotation(fn.Name.Name, fn.Doc)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", filepath.Base(file), fn.Name.Name, err)
+			}
+			if found {
+				annotations = append(annotations, annotation)
+			}
+		}
+	}
+
+	return annotations, nil
+}
+
+// parseAnnotation reads one function's doc comment
+func parseAnnotation(handler string, doc *ast.CommentGroup) (Annotation, bool, error) {
+	annotation := Annotation{Handler: handler}
+	found := false
+
+	for _, comment := range doc.List {
+		line := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(line, "@") {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "@Summary":
+			annotation.Summary = value
+		case "@Description":
+			annotation.Description = strings.TrimSpace(annotation.Description + " " + value)
+		case "@Tags":
+			for _, tag := range strings.Split(value, ",") {
+				annotation.Tags = append(annotation.Tags, strings.TrimSpace(tag))
+			}
+		case "@Param":
+			param, err := parseParam(value)
+			if err != nil {
+				return annotation, false, err
+			}
+			annotation.Params = append(annotation.Params, param)
+		case "@Success", "@Failure":
+			response, err := parseResponse(value)
+			if err != nil {
+				return annotation, false, err
+			}
+			annotation.Responses = append(annotation.Responses, response)
+		case "@Security":
+			annotation.Security = append(annotation.Security, value)
+		case "@Router":
+			fields := strings.Fields(value)
+			if len(fields) != 2 || !strings.HasPrefix(fields[1], "[") || !strings.HasSuffix(fields[1], "]") {
+				return annotation, false, fmt.Errorf("malformed @Router %q", value)
+			}
+			annotation.Path = fields[0]
+			annotation.Method = strings.ToLower(strings.Trim(fields[1], "[]"))
+			found = true
+		case "@Accept", "@Produce":
+			// Every endpoint speaks JSON
+		default:
+			return annotation, false, fmt.Errorf("unknown annotation %s", key)
+		}
+	}
+
+	return annotation, found, nil
+}
+
+// parseParam parses: name in type required "description" [attributes]
+func parseParam(value string) (ParamAnnotation, error) {
+	var attributes string
+	if end := strings.LastIndex(value, `"`); end > strings.Index(value, `"`) {
+		value, attributes = value[:end+1], value[end+1:]
+	}
+	fields, description := splitDescription(value)
+	if len(fields) != 4 {
+		return ParamAnnotation{}, fmt.Errorf("malformed @Param %q", value)
+	}
+
+	required, err := strconv.ParseBool(fields[3])
+	if err != nil {
+		return ParamAnnotation{}, fmt.Errorf("malformed @Param %q: %w", value, err)
+	}
+
+	switch fields[1] {
+	case "path", "query", "header", "body":
+	default:
+		return ParamAnnotation{}, fmt.Errorf("unknown parameter location %q", fields[1])
+	}
+
+	param := ParamAnnotation{
+		Name:        fields[0],
+		In:          fields[1],
+		Type:        fields[2],
+		Required:    required,
+		Description: description,
+	}
+	if err := parseAttributes(&param, attributes); err != nil {
+		return ParamAnnotation{}, fmt.Errorf("malformed @Param %q: %w", value+attributes, err)
+	}
+	return param, nil
+}
+
+// parseAttributes reads the minlength, maxlength, minimum and maximum attributes
+// following a parameter's description
+func parseAttributes(param *ParamAnnotation, attributes string) error {
+	for _, attribute := range strings.Fields(attributes) {
+		name, arg, ok := strings.Cut(strings.TrimSuffix(attribute, ")"), "(")
+		if !ok {
+			return fmt.Errorf("malformed attribute %q", attribute)
+		}
+
+		switch strings.ToLower(name) {
+		case "minlength", "maxlength":
+			length, err := strconv.Atoi(arg)
+			if err != nil {
+				return fmt.Errorf("malformed attribute %q", attribute)
+			}
+			if strings.EqualFold(name, "minlength") {
+				param.MinLength = &length
+			} else {
+				param.MaxLength = &length
+			}
+		case "minimum", "maximum":
+			bound, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return fmt.Errorf("malformed attribute %q", attribute)
+			}
+			if strings.EqualFold(name, "minimum") {
+				param.Minimum = &bound
+			} else {
+				param.Maximum = &bound
+			}
+		default:
+			return fmt.Errorf("unknown attribute %q", attribute)
+		}
+	}
+	return nil
+}
+
+// parseResponse parses: code [{object|array} Type] "description"
+func parseResponse(value string) (ResponseAnnotation, error) {
+	fields, description := splitDescription(value)
+	if len(fields) != 1 && len(fields) != 3 {
+		return ResponseAnnotation{}, fmt.Errorf("malformed response %q", value)
+	}
+
+	code, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return ResponseAnnotation{}, fmt.Errorf("malformed response code %q", fields[0])
+	}
+
+	response := ResponseAnnotation{Code: code, Description: description}
+	if len(fields) == 3 {
+		response.Kind = strings.Trim(fields[1], "{}")
+		response.Type = fields[2]
+		if response.Kind != "object" && response.Kind != "array" {
+			return ResponseAnnotation{}, fmt.Errorf("unknown response kind %q", fields[1])
+		}
+	}
+	return response, nil
+}
+
+// splitDescription separates the whitespace-separated fields from a trailing quoted description
+func splitDescription(value string) ([]string, string) {
+	description := ""
+	if start := strings.Index(value, `"`); start >= 0 {
+		description = strings.Trim(value[start:], `"`)
+		value = value[:start]
+	}
+	return strings.Fields(value), description
+}
@@ -0,0 +1,158 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SecurityAPIKey is the security scheme name protected handlers reference with @Security
+const SecurityAPIKey = "ApiKeyAuth"
+
+// Generate builds an OpenAPI document from handler annotations. types maps every
+// type name used in @Param body and response lines to its Go type.
+func Generate(info Info, annotations []Annotation, types map[string]reflect.Type) (*Document, error) {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				SecurityAPIKey: {
+					Type:        "apiKey",
+					In:          "header",
+					Name:        "X-API-Key",
+					Description: "The master RAWBOARD_API_KEY or a scoped per-game key. Authorization: Bearer <key> is also accepted.",
+				},
+			},
+		},
+	}
+
+	builder := newSchemaBuilder()
+	resolve := func(name string) (*Schema, error) {
+		t, ok := types[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown type %s", name)
+		}
+		return builder.schemaFor(t)
+	}
+
+	for _, annotation := range annotations {
+		operation, err := buildOperation(annotation, resolve)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", annotation.Handler, err)
+		}
+
+		item, ok := doc.Paths[annotation.Path]
+		if !ok {
+			item = &PathItem{}
+			doc.Paths[annotation.Path] = item
+		}
+		if _, exists := (*item)[annotation.Method]; exists {
+			return nil, fmt.Errorf("%s: duplicate operation %s %s", annotation.Handler, annotation.Method, annotation.Path)
+		}
+		(*item)[annotation.Method] = operation
+	}
+
+	doc.Components.Schemas = builder.components
+	return doc, nil
+}
+
+// buildOperation converts one handler's annotations into an operation
+func buildOperation(annotation Annotation, resolve func(string) (*Schema, error)) (*Operation, error) {
+	operation := &Operation{
+		Summary:     annotation.Summary,
+		Description: annotation.Description,
+		OperationID: annotation.Handler,
+		Tags:        annotation.Tags,
+		Responses:   make(map[string]*Response),
+	}
+
+	pathParams := map[string]bool{}
+	for _, param := range annotation.Params {
+		if param.In == "body" {
+			schema, err := resolve(param.Type)
+			if err != nil {
+				return nil, err
+			}
+			operation.RequestBody = &RequestBody{
+				Description: param.Description,
+				Required:    param.Required,
+				Content:     map[string]MediaType{"application/json": {Schema: schema}},
+			}
+			continue
+		}
+
+		schema, err := primitiveSchema(param.Type)
+		if err != nil {
+			return nil, fmt.Errorf("parameter %s: %w", param.Name, err)
+		}
+		schema.MinLength, schema.MaxLength = param.MinLength, param.MaxLength
+		schema.Minimum, schema.Maximum = param.Minimum, param.Maximum
+		if param.In == "path" {
+			pathParams[param.Name] = true
+		}
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        param.Name,
+			In:          param.In,
+			Description: param.Description,
+			Required:    param.Required || param.In == "path",
+			Schema:      schema,
+		})
+	}
+
+	// Every {param} in the path must be documented
+	for _, segment := range strings.Split(annotation.Path, "/") {
+		if strings.HasPrefix(segment, "{") && !pathParams[strings.Trim(segment, "{}")] {
+			return nil, fmt.Errorf("path parameter %s is not documented", segment)
+		}
+	}
+
+	for _, response := range annotation.Responses {
+		description := response.Description
+		if description == "" {
+			description = http.StatusText(response.Code)
+		}
+
+		entry := &Response{Description: description}
+		if response.Type != "" {
+			schema, err := resolve(response.Type)
+			if err != nil {
+				return nil, err
+			}
+			if response.Kind == "array" {
+				schema = &Schema{Type: "array", Items: schema}
+			}
+			entry.Content = map[string]MediaType{"application/json": {Schema: schema}}
+		}
+		operation.Responses[strconv.Itoa(response.Code)] = entry
+	}
+	if len(operation.Responses) == 0 {
+		return nil, fmt.Errorf("no @Success or @Failure responses")
+	}
+
+	sort.Strings(annotation.Security)
+	for _, scheme := range annotation.Security {
+		operation.Security = append(operation.Security, map[string][]string{scheme: {}})
+	}
+
+	return operation, nil
+}
+
+// primitiveSchema returns the schema for a path, query or header parameter type
+func primitiveSchema(name string) (*Schema, error) {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}, nil
+	case "integer", "int":
+		return &Schema{Type: "integer"}, nil
+	case "number":
+		return &Schema{Type: "number"}, nil
+	case "boolean", "bool":
+		return &Schema{Type: "boolean"}, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter type %s", name)
+}
@@ -0,0 +1,125 @@
+package openapi_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"rawboard/internal/handlers"
+	"rawboard/internal/openapi"
+)
+
+func TestSpecUpToDate(t *testing.T) {
+	spec, err := handlers.GenerateOpenAPI("../handlers")
+	if err != nil {
+		t.Fatalf("Failed to generate OpenAPI document: %v", err)
+	}
+
+	if !bytes.Equal(spec, openapi.Spec) {
+		t.Error("openapi.json is out of date with the handler annotations, run: go generate ./internal/openapi")
+	}
+}
+
+type widget struct {
+	Name    string    `json:"name" binding:"required" example:"spinner"`
+	Tags    []string  `json:"tags,omitempty" example:"red,round"`
+	Size    int       `json:"size" minimum:"1" maximum:"10"`
+	Created time.Time `json:"created"`
+	secret  string
+}
+
+// writeHandlers writes one annotated handler source file into a temporary directory
+func writeHandlers(t *testing.T, annotations string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := "package sample\n\n" + annotations + "\nfunc GetWidget() {}\n"
+	if err := os.WriteFile(filepath.Join(dir, "sample.go"), []byte(src), 0o644); err != nil {
+		t.Fatalf("Failed to write sample handler: %v", err)
+	}
+	return dir
+}
+
+func TestGenerate(t *testing.T) {
+	types := map[string]reflect.Type{"sample.Widget": reflect.TypeOf(widget{})}
+
+	t.Run("builds operations and schemas from annotations", func(t *testing.T) {
+		dir := writeHandlers(t, `// GetWidget returns a widget
+// @Summary Get a widget
+// @Tags widgets
+// @Param widgetId path string true "Widget ID" minlength(1) maxlength(20)
+// @Param verbose query boolean false "Include everything"
+// @Success 200 {object} sample.Widget
+// @Failure 404 "Widget not found"
+// @Security ApiKeyAuth
+// @Router /widgets/{widgetId} [get]`)
+
+		annotations, err := openapi.ParseDir(dir)
+		if err != nil {
+			t.Fatalf("Failed to parse annotations: %v", err)
+		}
+		doc, err := openapi.Generate(openapi.Info{Title: "Sample", Version: "1.0.0"}, annotations, types)
+		if err != nil {
+			t.Fatalf("Failed to generate document: %v", err)
+		}
+
+		operation := (*doc.Paths["/widgets/{widgetId}"])["get"]
+		if operation == nil {
+			t.Fatal("Expected GET /widgets/{widgetId} to be documented")
+		}
+		if len(operation.Parameters) != 2 || !operation.Parameters[0].Required || operation.Parameters[1].Required {
+			t.Errorf("Unexpected parameters: %+v", operation.Parameters)
+		}
+		if id := operation.Parameters[0].Schema; id.MinLength == nil || *id.MinLength != 1 || id.MaxLength == nil || *id.MaxLength != 20 {
+			t.Errorf("Expected the path parameter's length attributes, got %+v", id)
+		}
+		if operation.Responses["404"].Description != "Widget not found" || len(operation.Security) != 1 {
+			t.Errorf("Unexpected responses or security: %+v %+v", operation.Responses, operation.Security)
+		}
+
+		schema := doc.Components.Schemas["widget"]
+		if schema == nil {
+			t.Fatalf("Expected a widget component, got %v", doc.Components.Schemas)
+		}
+		if _, ok := schema.Properties["secret"]; ok {
+			t.Error("Unexported fields must not be documented")
+		}
+		if !reflect.DeepEqual(schema.Required, []string{"name"}) {
+			t.Errorf("Expected name to be required, got %v", schema.Required)
+		}
+		if schema.Properties["created"].Format != "date-time" || schema.Properties["size"].Minimum == nil {
+			t.Errorf("Unexpected property schemas: %+v", schema.Properties)
+		}
+		if !reflect.DeepEqual(schema.Properties["tags"].Example, []interface{}{"red", "round"}) {
+			t.Errorf("Unexpected array example: %v", schema.Properties["tags"].Example)
+		}
+	})
+
+	t.Run("rejects invalid annotations", func(t *testing.T) {
+		cases := map[string]string{
+			"unknown annotation":     "// @Sumary typo\n// @Router /widgets [get]",
+			"malformed router":       "// @Success 200\n// @Router /widgets",
+			"undocumented path":      "// @Success 200\n// @Router /widgets/{widgetId} [get]",
+			"unknown type":           "// @Success 200 {object} sample.Gadget\n// @Router /widgets [get]",
+			"missing responses":      "// @Router /widgets [get]",
+			"bad parameter location": "// @Param id cookie string true \"ID\"\n// @Success 200\n// @Router /widgets [get]",
+			"unknown attribute":      "// @Param id query string true \"ID\" pattern(x)\n// @Success 200\n// @Router /widgets [get]",
+		}
+
+		for name, annotations := range cases {
+			annotationList, err := openapi.ParseDir(writeHandlers(t, annotations))
+			if err == nil {
+				_, err = openapi.Generate(openapi.Info{}, annotationList, types)
+			}
+			if err == nil {
+				t.Errorf("%s: expected an error", name)
+			} else if strings.TrimSpace(err.Error()) == "" {
+				t.Errorf("%s: expected a descriptive error", name)
+			}
+		}
+	})
+}
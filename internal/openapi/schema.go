@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemaBuilder turns Go types into schemas, collecting named structs as components
+type schemaBuilder struct {
+	components map[string]*Schema
+	owners     map[string]reflect.Type // Detects two packages' types sharing a name
+}
+
+func newSchemaBuilder() *schemaBuilder {
+	return &schemaBuilder{
+		components: make(map[string]*Schema),
+		owners:     make(map[string]reflect.Type),
+	}
+}
+
+// schemaFor returns the schema for t, referencing named structs by component
+func (b *schemaBuilder) schemaFor(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}, nil
+	case t == rawMessageType:
+		return &Schema{}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Interface:
+		return &Schema{}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}, nil
+		}
+		items, err := b.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := b.schemaFor(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.component(t)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// component registers a named struct and returns a reference to it
+func (b *schemaBuilder) component(t reflect.Type) (*Schema, error) {
+	name := t.Name()
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+
+	if owner, ok := b.owners[name]; ok {
+		if owner != t {
+			return nil, fmt.Errorf("schema name %s is used by both %s and %s", name, owner, t)
+		}
+		return ref, nil
+	}
+	b.owners[name] = t
+
+	schema, err := b.structSchema(t)
+	if err != nil {
+		return nil, err
+	}
+	b.components[name] = schema
+	return ref, nil
+}
+
+// structSchema describes a struct's JSON fields, flattening embedded structs
+func (b *schemaBuilder) structSchema(t reflect.Type) (*Schema, error) {
+	schema := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			inner, err := b.structSchema(embedded)
+			if err != nil {
+				return nil, err
+			}
+			for key, value := range inner.Properties {
+				schema.Properties[key] = value
+			}
+			schema.Required = append(schema.Required, inner.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		property, err := b.schemaFor(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+		}
+		// swaggertype documents what a field accepts when its Go type says otherwise,
+		// e.g. a json.Number score
+		if swaggerType := field.Tag.Get("swaggertype"); swaggerType != "" && swaggerType != property.Type {
+			if property, err = primitiveSchema(swaggerType); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", t.Name(), field.Name, err)
+			}
+		}
+		if property.Ref == "" {
+			applyFieldTags(property, field.Tag)
+		}
+		schema.Properties[name] = property
+
+		if binding := field.Tag.Get("binding"); strings.Contains(binding, "required") {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+
+	return schema, nil
+}
+
+// applyFieldTags copies binding rules and swag-style example and constraint tags onto
+// a schema, so the document states what the handlers enforce
+func applyFieldTags(schema *Schema, tag reflect.StructTag) {
+	applyBinding(schema, tag.Get("binding"))
+
+	if example, ok := tag.Lookup("example"); ok {
+		schema.Example = parseExample(schema, example)
+	}
+
+	if value, err := strconv.ParseFloat(tag.Get("minimum"), 64); err == nil {
+		schema.Minimum = &value
+	}
+	if value, err := strconv.ParseFloat(tag.Get("maximum"), 64); err == nil {
+		schema.Maximum = &value
+	}
+	if value, err := strconv.Atoi(tag.Get("minLength")); err == nil {
+		schema.MinLength = &value
+	}
+	if value, err := strconv.Atoi(tag.Get("maxLength")); err == nil {
+		schema.MaxLength = &value
+	}
+}
+
+// applyBinding documents the min, max and len rules of a gin binding tag, which
+// bound a string's length, a number's value and an array's items
+func applyBinding(schema *Schema, binding string) {
+	for _, rule := range strings.Split(binding, ",") {
+		name, arg, ok := strings.Cut(rule, "=")
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			continue
+		}
+
+		lower := name == "min" || name == "len"
+		upper := name == "max" || name == "len"
+		if !lower && !upper {
+			continue
+		}
+		switch schema.Type {
+		case "string":
+			setBound(&schema.MinLength, &schema.MaxLength, int(value), lower, upper)
+		case "array":
+			setBound(&schema.MinItems, &schema.MaxItems, int(value), lower, upper)
+		case "integer", "number":
+			if lower {
+				schema.Minimum = &value
+			}
+			if upper {
+				maximum := value
+				schema.Maximum = &maximum
+			}
+		}
+	}
+}
+
+// setBound sets the lower and/or upper of a pair of integer bounds to value
+func setBound(lowerBound, upperBound **int, value int, lower, upper bool) {
+	if lower {
+		minimum := value
+		*lowerBound = &minimum
+	}
+	if upper {
+		maximum := value
+		*upperBound = &maximum
+	}
+}
+
+// parseExample converts an example tag to the schema's JSON type
+// Array examples are comma-separated, following swag
+func parseExample(schema *Schema, example string) interface{} {
+	switch schema.Type {
+	case "integer":
+		if value, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return value
+		}
+	case "number":
+		if value, err := strconv.ParseFloat(example, 64); err == nil {
+			return value
+		}
+	case "boolean":
+		if value, err := strconv.ParseBool(example); err == nil {
+			return value
+		}
+	case "array":
+		if schema.Items == nil || schema.Items.Ref != "" {
+			return nil
+		}
+		values := []interface{}{}
+		for _, item := range strings.Split(example, ",") {
+			if value := parseExample(schema.Items, strings.TrimSpace(item)); value != nil {
+				values = append(values, value)
+			}
+		}
+		return values
+	case "string":
+		return example
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+package openapi
+
+import _ "embed"
+
+//go:generate go run ../../cmd/openapi -handlers ../handlers -o openapi.json
+
+// Spec is the generated OpenAPI document served at /api/v1/openapi.json
+//
+//go:embed openapi.json
+var Spec []byte
@@ -0,0 +1,305 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError describes how a request breaks its operation's documented constraints
+type ValidationError struct {
+	Field      string // Parameter name, or the body property's path, e.g. slides[0].duration
+	Value      string
+	Constraint string // What the field must be, e.g. "length between 1 and 50 characters"
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: must be %s", e.Field, e.Constraint)
+}
+
+// Request is what a Validator checks of an incoming request
+type Request struct {
+	Method string
+	Path   string // Route pattern with {param} segments, as in the document's paths
+	Params map[string]string
+	Query  url.Values
+	Body   []byte // JSON body, or nil to skip checking it
+}
+
+// Validator checks requests against the operations of an OpenAPI document
+type Validator struct {
+	doc *Document
+}
+
+// NewValidator returns a validator for the JSON OpenAPI document spec
+func NewValidator(spec []byte) (*Validator, error) {
+	var doc Document
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI document: %w", err)
+	}
+	return &Validator{doc: &doc}, nil
+}
+
+// HasBody reports whether the operation at method and path documents a request body
+func (v *Validator) HasBody(method, path string) bool {
+	operation := v.operation(method, path)
+	return operation != nil && operation.RequestBody != nil
+}
+
+// Validate returns the first way req breaks its operation's parameters or body schema,
+// or nil when it doesn't. Requests to undocumented routes, and bodies that aren't JSON,
+// are left to their handlers.
+func (v *Validator) Validate(req Request) *ValidationError {
+	operation := v.operation(req.Method, req.Path)
+	if operation == nil {
+		return nil
+	}
+
+	for _, param := range operation.Parameters {
+		var value string
+		switch param.In {
+		case "path":
+			value = req.Params[param.Name]
+		case "query":
+			value = req.Query.Get(param.Name)
+			if value == "" {
+				if param.Required {
+					return &ValidationError{Field: param.Name, Constraint: "present"}
+				}
+				continue
+			}
+		default:
+			continue
+		}
+		if err := checkParam(param, value); err != nil {
+			return err
+		}
+	}
+
+	if operation.RequestBody == nil || len(bytes.TrimSpace(req.Body)) == 0 {
+		return nil
+	}
+	media, ok := operation.RequestBody.Content["application/json"]
+	if !ok || media.Schema == nil {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(req.Body))
+	decoder.UseNumber()
+	var body interface{}
+	if err := decoder.Decode(&body); err != nil {
+		return nil
+	}
+	return v.checkValue("", body, media.Schema)
+}
+
+// operation finds the operation at method and path
+func (v *Validator) operation(method, path string) *Operation {
+	item, ok := v.doc.Paths[path]
+	if !ok {
+		return nil
+	}
+	return (*item)[strings.ToLower(method)]
+}
+
+// checkParam checks a path or query parameter's value against its schema
+func checkParam(param Parameter, value string) *ValidationError {
+	schema := param.Schema
+	if schema == nil {
+		return nil
+	}
+	invalid := func() *ValidationError {
+		return &ValidationError{Field: param.Name, Value: value, Constraint: describe(schema)}
+	}
+
+	switch schema.Type {
+	case "integer":
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || !inRange(schema, float64(number)) {
+			return invalid()
+		}
+	case "number":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil || !inRange(schema, number) {
+			return invalid()
+		}
+	case "boolean":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return invalid()
+		}
+	case "string":
+		if !lengthInRange(schema.MinLength, schema.MaxLength, utf8.RuneCountInString(value)) {
+			return invalid()
+		}
+	}
+	return nil
+}
+
+// checkValue checks a decoded JSON value against schema, naming failures by field
+func (v *Validator) checkValue(field string, value interface{}, schema *Schema) *ValidationError {
+	schema = v.resolve(schema)
+	if schema == nil || value == nil {
+		return nil
+	}
+	invalid := func() *ValidationError {
+		return &ValidationError{Field: fieldName(field), Value: display(value), Constraint: describe(schema)}
+	}
+
+	switch schema.Type {
+	case "string":
+		text, ok := value.(string)
+		if !ok || !lengthInRange(schema.MinLength, schema.MaxLength, utf8.RuneCountInString(text)) {
+			return invalid()
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, text); err != nil {
+				return invalid()
+			}
+		}
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			return invalid()
+		}
+		parsed, err := number.Float64()
+		if err != nil || !inRange(schema, parsed) {
+			return invalid()
+		}
+		if schema.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				return invalid()
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return invalid()
+		}
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok || !lengthInRange(schema.MinItems, schema.MaxItems, len(items)) {
+			return invalid()
+		}
+		for i, item := range items {
+			if err := v.checkValue(fmt.Sprintf("%s[%d]", field, i), item, schema.Items); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return invalid()
+		}
+		for _, name := range schema.Required {
+			if object[name] == nil {
+				return &ValidationError{Field: join(field, name), Constraint: "present"}
+			}
+		}
+		for name, property := range object {
+			propertySchema := schema.Properties[name]
+			if propertySchema == nil {
+				propertySchema = schema.AdditionalProperties
+			}
+			if err := v.checkValue(join(field, name), property, propertySchema); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve follows a component reference
+func (v *Validator) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = v.doc.Components.Schemas[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+	return schema
+}
+
+// inRange reports whether number is within the schema's minimum and maximum
+func inRange(schema *Schema, number float64) bool {
+	return (schema.Minimum == nil || number >= *schema.Minimum) &&
+		(schema.Maximum == nil || number <= *schema.Maximum)
+}
+
+// lengthInRange reports whether length is within optional lower and upper bounds
+func lengthInRange(lower, upper *int, length int) bool {
+	return (lower == nil || length >= *lower) && (upper == nil || length <= *upper)
+}
+
+// describe words what a schema accepts, in the style of the handlers' own validation errors
+func describe(schema *Schema) string {
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" {
+			return "an RFC 3339 timestamp"
+		}
+		return describeBounds("length", schema.MinLength, schema.MaxLength, " characters", "string")
+	case "array":
+		return describeBounds("items", schema.MinItems, schema.MaxItems, "", "array")
+	case "integer", "number":
+		if schema.Minimum == nil && schema.Maximum == nil {
+			return schema.Type
+		}
+		return describeRange(schema.Type, schema.Minimum, schema.Maximum)
+	case "":
+		return "a value"
+	}
+	return schema.Type
+}
+
+// describeBounds words optional bounds on a length or count
+func describeBounds(what string, lower, upper *int, unit, fallback string) string {
+	switch {
+	case lower != nil && upper != nil && *lower == *upper:
+		return fmt.Sprintf("%s of exactly %d%s", what, *lower, unit)
+	case lower != nil && upper != nil:
+		return fmt.Sprintf("%s between %d and %d%s", what, *lower, *upper, unit)
+	case lower != nil:
+		return fmt.Sprintf("%s at least %d%s", what, *lower, unit)
+	case upper != nil:
+		return fmt.Sprintf("%s at most %d%s", what, *upper, unit)
+	}
+	return fallback
+}
+
+// describeRange words optional bounds on a number
+func describeRange(kind string, lower, upper *float64) string {
+	format := func(value float64) string { return strconv.FormatFloat(value, 'f', -1, 64) }
+	switch {
+	case lower != nil && upper != nil:
+		return fmt.Sprintf("%s between %s and %s", kind, format(*lower), format(*upper))
+	case lower != nil:
+		return fmt.Sprintf("%s of at least %s", kind, format(*lower))
+	default:
+		return fmt.Sprintf("%s of at most %s", kind, format(*upper))
+	}
+}
+
+// display renders a decoded JSON value for an error
+func display(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// join appends a property name to a field path
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
+
+// fieldName names the body itself when a failure isn't about one of its fields
+func fieldName(field string) string {
+	if field == "" {
+		return "body"
+	}
+	return field
+}
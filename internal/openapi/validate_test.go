@@ -0,0 +1,64 @@
+package openapi_test
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"rawboard/internal/openapi"
+)
+
+func TestValidator(t *testing.T) {
+	validator, err := openapi.NewValidator(openapi.Spec)
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+	submit := func(gameID, body string) openapi.Request {
+		return openapi.Request{
+			Method: "POST",
+			Path:   "/api/v1/games/{gameId}/scores",
+			Params: map[string]string{"gameId": gameID},
+			Body:   []byte(body),
+		}
+	}
+
+	cases := []struct {
+		name       string
+		req        openapi.Request
+		field      string
+		constraint string
+	}{
+		{"valid submission", submit("pacman", `{"initials": "AAA", "score": 12500}`), "", ""},
+		{"long game ID", submit(strings.Repeat("x", 51), `{"initials": "AAA", "score": 1}`), "gameId", "length between 1 and 50 characters"},
+		{"long initials", submit("pacman", `{"initials": "AAAA", "score": 1}`), "initials", "length of exactly 3 characters"},
+		{"score as text", submit("pacman", `{"initials": "AAA", "score": "lots"}`), "score", "number between -999999999 and 999999999"},
+		{"missing score", submit("pacman", `{"initials": "AAA"}`), "score", "present"},
+		{"negative sequence", submit("pacman", `{"initials": "AAA", "score": 1, "sequence": -1}`), "sequence", "integer of at least 0"},
+		{"malformed JSON left to the handler", submit("pacman", `{"initials": `), "", ""},
+		{"non-integer query", openapi.Request{
+			Method: "GET",
+			Path:   "/api/v1/games/{gameId}/scores/timeseries",
+			Params: map[string]string{"gameId": "pacman"},
+			Query:  url.Values{"count": {"ten"}},
+		}, "count", "integer"},
+		{"binding rules on nested fields", openapi.Request{
+			Method: "POST",
+			Path:   "/api/v1/admin/keys",
+			Body:   []byte(`{"name": "cabinet", "game_ids": [], "scopes": ["submit"]}`),
+		}, "game_ids", "items at least 1"},
+		{"undocumented route", openapi.Request{Method: "GET", Path: "/nowhere"}, "", ""},
+	}
+
+	for _, tc := range cases {
+		err := validator.Validate(tc.req)
+		if tc.field == "" {
+			if err != nil {
+				t.Errorf("%s: expected no error, got %v", tc.name, err)
+			}
+			continue
+		}
+		if err == nil || err.Field != tc.field || err.Constraint != tc.constraint {
+			t.Errorf("%s: expected %s to be %q, got %+v", tc.name, tc.field, tc.constraint, err)
+		}
+	}
+}
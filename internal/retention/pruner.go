@@ -0,0 +1,83 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"rawboard/internal/audit"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+// actor identifies the pruning job in the audit log
+const actor = "system:retention"
+
+// Pruner enforces each game's retention policy
+type Pruner struct {
+	service *leaderboard.Service
+	audit   *audit.Log
+	logger  *slog.Logger
+	now     func() time.Time
+}
+
+// NewPruner creates a new retention pruner
+func NewPruner(service *leaderboard.Service, auditLog *audit.Log, logger *slog.Logger) *Pruner {
+	return &Pruner{
+		service: service,
+		audit:   auditLog,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Run prunes raw score history for every registered game a retention policy applies to
+func (p *Pruner) Run(ctx context.Context) error {
+	gameIDs, err := p.service.ListGames(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list games: %w", err)
+	}
+
+	now := p.now()
+	var failed int
+	for _, gameID := range gameIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err := p.service.PruneHistory(ctx, gameID, now)
+		if err != nil {
+			p.logger.Warn("retention pruning failed", "game_id", gameID, "error", err)
+			failed++
+			continue
+		}
+		if result == nil || result.Removed == 0 {
+			continue
+		}
+
+		details := map[string]interface{}{
+			"removed":   result.Removed,
+			"remaining": result.Remaining,
+		}
+		if result.Cutoff != nil {
+			details["cutoff"] = *result.Cutoff
+		}
+		p.logger.Info("pruned score history",
+			"game_id", gameID, "removed", result.Removed, "remaining", result.Remaining, "cutoff", details["cutoff"])
+
+		if err := p.audit.Record(ctx, models.AuditEntry{
+			Action:  audit.ActionRetentionPrune,
+			Actor:   actor,
+			GameID:  gameID,
+			Details: details,
+		}); err != nil {
+			p.logger.Warn("failed to record retention audit entry", "game_id", gameID, "error", err)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("retention pruning failed for %d game(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package rpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+	"rawboard/internal/tenants"
+)
+
+// methodScopes lists the scope each protected method requires; other methods are public
+var methodScopes = map[string]string{
+	rawboardv1.LeaderboardService_SubmitScore_FullMethodName: models.ScopeSubmit,
+}
+
+// tenantMetadata names the tenant a call acts for, like the HTTP API's
+// /api/v1/tenants/:tenantId prefix
+const tenantMetadata = "x-tenant-id"
+
+// gameScoped is implemented by requests that act on a single game
+type gameScoped interface {
+	GetGameId() string
+}
+
+// APIKeyInterceptor authenticates protected methods with the master key or a per-game
+// key sent as x-api-key or authorization: Bearer metadata, then checks the key's scope
+// and game. Calls act for the tenant named in x-tenant-id metadata, or else the key's.
+// Authentication is disabled when no master key is configured (development).
+func APIKeyInterceptor(masterKey string, keys *apikeys.Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requested := firstMetadata(ctx, tenantMetadata)
+		if requested != "" {
+			if err := tenants.Validate(requested); err != nil {
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		}
+		ctx = tenants.WithTenant(ctx, requested)
+
+		scope, protected := methodScopes[info.FullMethod]
+		if !protected || masterKey == "" {
+			return handler(ctx, req)
+		}
+
+		p, err := authenticate(ctx, masterKey, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		tenant := requested
+		if !p.Master && requested != p.Tenant {
+			if requested != "" {
+				return nil, status.Errorf(codes.PermissionDenied, "API key belongs to another tenant than %s", requested)
+			}
+			tenant = p.Tenant
+		}
+
+		if !p.HasScope(scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "API key lacks the required scope %s", scope)
+		}
+
+		gameID := ""
+		if scoped, ok := req.(gameScoped); ok {
+			gameID = scoped.GetGameId()
+		}
+		if !p.CanAccessGame(gameID) {
+			return nil, status.Errorf(codes.PermissionDenied, "API key is not scoped to game %s", gameID)
+		}
+
+		return handler(tenants.WithTenant(apikeys.WithPrincipal(ctx, p), tenant), req)
+	}
+}
+
+// authenticate resolves the caller's API key to a principal
+func authenticate(ctx context.Context, masterKey string, keys *apikeys.Store) (*apikeys.Principal, error) {
+	apiKey := extractAPIKey(ctx)
+	if apiKey == "" {
+		return nil, status.Error(codes.Unauthenticated, "API key required in x-api-key or authorization: Bearer <key> metadata")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(apiKey), []byte(masterKey)) == 1 {
+		return apikeys.MasterPrincipal(), nil
+	}
+
+	if keys != nil {
+		if key, err := keys.Resolve(ctx, apiKey); err == nil {
+			return apikeys.PrincipalFor(key), nil
+		}
+	}
+
+	return nil, status.Error(codes.Unauthenticated, "invalid API key")
+}
+
+// extractAPIKey reads the API key from x-api-key or an authorization bearer token
+func extractAPIKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	if values := md.Get("x-api-key"); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	for _, value := range md.Get("authorization") {
+		if strings.HasPrefix(value, "Bearer ") {
+			return strings.TrimPrefix(value, "Bearer ")
+		}
+	}
+	return ""
+}
+
+// firstMetadata returns the first value of the incoming metadata key, or ""
+func firstMetadata(ctx context.Context, key string) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
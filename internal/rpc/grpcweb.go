@@ -0,0 +1,35 @@
+package rpc
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+
+	"rawboard/internal/logging"
+)
+
+// GRPCWebHandler translates gRPC-Web requests from browsers into calls on server,
+// in-process, so browser engines can use the protobuf contract without a proxy.
+// Any origin may call it; keys travel in x-api-key or authorization metadata.
+func GRPCWebHandler(server *grpc.Server) gin.HandlerFunc {
+	wrapped := grpcweb.WrapServer(server,
+		grpcweb.WithOriginFunc(func(origin string) bool { return true }),
+		grpcweb.WithAllowedRequestHeaders([]string{
+			"content-type", "x-grpc-web", "x-user-agent", "grpc-timeout", "x-api-key", "authorization", "x-request-id",
+		}),
+	)
+
+	return func(c *gin.Context) {
+		if !wrapped.IsGrpcWebRequest(c.Request) && !wrapped.IsAcceptableGrpcCorsRequest(c.Request) {
+			c.AbortWithStatus(http.StatusUnsupportedMediaType)
+			return
+		}
+		// Carry the ID the HTTP middleware settled on into the call's metadata
+		if requestID := logging.RequestID(c.Request.Context()); requestID != "" {
+			c.Request.Header.Set(requestIDMetadata, requestID)
+		}
+		wrapped.ServeHTTP(c.Writer, c.Request)
+	}
+}
@@ -0,0 +1,161 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+)
+
+// grpcWebCall sends one gRPC-Web request and returns the response message bytes and grpc-status
+func grpcWebCall(t *testing.T, router http.Handler, method string, req proto.Message, apiKey string) ([]byte, codes.Code) {
+	t.Helper()
+
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to marshal request: %v", err)
+	}
+	frame := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	frame = append(frame, payload...)
+
+	httpReq := httptest.NewRequest(http.MethodPost, "/rawboard.v1.LeaderboardService/"+method, bytes.NewReader(frame))
+	httpReq.Header.Set("Content-Type", "application/grpc-web+proto")
+	if apiKey != "" {
+		httpReq.Header.Set("X-API-Key", apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httpReq)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected HTTP 200 for a gRPC-Web call, got %d", w.Code)
+	}
+
+	// Errors may arrive as trailers-only headers; otherwise walk the data and trailer frames
+	status := w.Header().Get("Grpc-Status")
+	var message []byte
+	body, _ := io.ReadAll(w.Body)
+	for len(body) >= 5 {
+		flag, length := body[0], binary.BigEndian.Uint32(body[1:5])
+		data := body[5 : 5+length]
+		body = body[5+length:]
+		if flag&0x80 == 0 {
+			message = data
+			continue
+		}
+		for _, line := range strings.Split(string(data), "\r\n") {
+			if name, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(name, "grpc-status") {
+				status = strings.TrimSpace(value)
+			}
+		}
+	}
+
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(status)); err != nil {
+		t.Fatalf("Missing or invalid grpc-status %q", status)
+	}
+	return message, code
+}
+
+func TestGRPCWeb(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	db := database.NewFake()
+	keys := apikeys.NewStore(db)
+	server := NewGRPCServer(leaderboard.NewService(db), nil, "master-key", keys, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	router := gin.New()
+	router.POST("/rawboard.v1.LeaderboardService/:method", GRPCWebHandler(server))
+	router.OPTIONS("/rawboard.v1.LeaderboardService/:method", GRPCWebHandler(server))
+
+	cabinet, err := keys.Create(ctx, "cabinet", []string{"pacman"}, []string{models.ScopeSubmit})
+	if err != nil {
+		t.Fatalf("Failed to create key: %v", err)
+	}
+
+	t.Run("submits scores and reads the leaderboard", func(t *testing.T) {
+		data, code := grpcWebCall(t, router, "SubmitScore",
+			&rawboardv1.SubmitScoreRequest{GameId: "pacman", Initials: "aaa", Score: 1200}, cabinet.Key)
+		if code != codes.OK {
+			t.Fatalf("Expected OK, got %v", code)
+		}
+
+		var submitted rawboardv1.SubmitScoreResponse
+		if err := proto.Unmarshal(data, &submitted); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if submitted.GetEntry().GetInitials() != "AAA" || submitted.GetRank() != 1 || submitted.GetReceiptToken() == "" {
+			t.Errorf("Unexpected submission response: %v", &submitted)
+		}
+
+		grpcWebCall(t, router, "SubmitScore", &rawboardv1.SubmitScoreRequest{GameId: "pacman", Initials: "BBB", Score: 3400}, "master-key")
+
+		data, code = grpcWebCall(t, router, "GetLeaderboard", &rawboardv1.GetLeaderboardRequest{GameId: "pacman", Limit: 1}, "")
+		if code != codes.OK {
+			t.Fatalf("Expected the leaderboard to be public, got %v", code)
+		}
+		var board rawboardv1.Leaderboard
+		if err := proto.Unmarshal(data, &board); err != nil {
+			t.Fatalf("Failed to unmarshal leaderboard: %v", err)
+		}
+		if len(board.GetEntries()) != 1 || board.GetEntries()[0].GetInitials() != "BBB" {
+			t.Errorf("Expected only BBB on a limited leaderboard, got %v", board.GetEntries())
+		}
+	})
+
+	t.Run("rejects submissions without a valid key", func(t *testing.T) {
+		req := &rawboardv1.SubmitScoreRequest{GameId: "pacman", Initials: "CCC", Score: 100}
+		if _, code := grpcWebCall(t, router, "SubmitScore", req, ""); code != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated without a key, got %v", code)
+		}
+		if _, code := grpcWebCall(t, router, "SubmitScore", req, "wrong"); code != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated for an unknown key, got %v", code)
+		}
+	})
+
+	t.Run("enforces the key's games", func(t *testing.T) {
+		req := &rawboardv1.SubmitScoreRequest{GameId: "tetris", Initials: "CCC", Score: 100}
+		if _, code := grpcWebCall(t, router, "SubmitScore", req, cabinet.Key); code != codes.PermissionDenied {
+			t.Errorf("Expected PermissionDenied for another game, got %v", code)
+		}
+	})
+
+	t.Run("validates requests", func(t *testing.T) {
+		if _, code := grpcWebCall(t, router, "SubmitScore",
+			&rawboardv1.SubmitScoreRequest{GameId: "pacman", Initials: "TOOLONG", Score: 100}, "master-key"); code != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for bad initials, got %v", code)
+		}
+		if _, code := grpcWebCall(t, router, "GetLeaderboard", &rawboardv1.GetLeaderboardRequest{GameId: "galaga"}, ""); code != codes.NotFound {
+			t.Errorf("Expected NotFound for an unknown game, got %v", code)
+		}
+	})
+
+	t.Run("answers CORS preflight requests", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/rawboard.v1.LeaderboardService/SubmitScore", nil)
+		req.Header.Set("Origin", "https://games.example.com")
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web,x-api-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Header().Get("Access-Control-Allow-Origin") == "" {
+			t.Errorf("Expected CORS headers on preflight, got status %d headers %v", w.Code, w.Header())
+		}
+	})
+}
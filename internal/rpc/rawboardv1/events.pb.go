@@ -0,0 +1,714 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: rawboard/v1/events.proto
+
+package rawboardv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Event is one domain event, as carried on the Valkey event bus, streamed by
+// LeaderboardService.StreamEvents and delivered to protobuf webhooks.
+//
+// The schema only grows: fields and payloads are added, never renumbered or reused, so
+// consumers built against an older version keep working and should ignore what they
+// don't know. schema_version is bumped only for a change old consumers can't ignore.
+type Event struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique per event; a webhook delivery's ID for webhooks
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// score.submitted, highscore.new, board.reset or achievement.unlocked
+	Type          string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	SchemaVersion uint32 `protobuf:"varint,3,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	GameId        string `protobuf:"bytes,4,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// Empty for the default namespace
+	Tenant     string                 `protobuf:"bytes,5,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*Event_ScoreSubmitted
+	//	*Event_HighScoreNew
+	//	*Event_BoardReset
+	//	*Event_AchievementUnlocked
+	Payload       isEvent_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetSchemaVersion() uint32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *Event) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *Event) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *Event) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *Event) GetPayload() isEvent_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *Event) GetScoreSubmitted() *ScoreSubmitted {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_ScoreSubmitted); ok {
+			return x.ScoreSubmitted
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetHighScoreNew() *HighScoreNew {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_HighScoreNew); ok {
+			return x.HighScoreNew
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetBoardReset() *BoardReset {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_BoardReset); ok {
+			return x.BoardReset
+		}
+	}
+	return nil
+}
+
+func (x *Event) GetAchievementUnlocked() *AchievementUnlocked {
+	if x != nil {
+		if x, ok := x.Payload.(*Event_AchievementUnlocked); ok {
+			return x.AchievementUnlocked
+		}
+	}
+	return nil
+}
+
+type isEvent_Payload interface {
+	isEvent_Payload()
+}
+
+type Event_ScoreSubmitted struct {
+	ScoreSubmitted *ScoreSubmitted `protobuf:"bytes,10,opt,name=score_submitted,json=scoreSubmitted,proto3,oneof"`
+}
+
+type Event_HighScoreNew struct {
+	HighScoreNew *HighScoreNew `protobuf:"bytes,11,opt,name=high_score_new,json=highScoreNew,proto3,oneof"`
+}
+
+type Event_BoardReset struct {
+	BoardReset *BoardReset `protobuf:"bytes,12,opt,name=board_reset,json=boardReset,proto3,oneof"`
+}
+
+type Event_AchievementUnlocked struct {
+	AchievementUnlocked *AchievementUnlocked `protobuf:"bytes,13,opt,name=achievement_unlocked,json=achievementUnlocked,proto3,oneof"`
+}
+
+func (*Event_ScoreSubmitted) isEvent_Payload() {}
+
+func (*Event_HighScoreNew) isEvent_Payload() {}
+
+func (*Event_BoardReset) isEvent_Payload() {}
+
+func (*Event_AchievementUnlocked) isEvent_Payload() {}
+
+// ScoreSubmitted is a counted submission, whether or not it beat the player's best
+type ScoreSubmitted struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *ScoreEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreSubmitted) Reset() {
+	*x = ScoreSubmitted{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreSubmitted) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreSubmitted) ProtoMessage() {}
+
+func (x *ScoreSubmitted) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreSubmitted.ProtoReflect.Descriptor instead.
+func (*ScoreSubmitted) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ScoreSubmitted) GetEntry() *ScoreEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+// HighScoreNew is a submission that beat the player's own high score
+type HighScoreNew struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Entry *ScoreEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// The score beaten, absent for the player's first
+	PreviousHighScore *ScoreEntry `protobuf:"bytes,2,opt,name=previous_high_score,json=previousHighScore,proto3" json:"previous_high_score,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *HighScoreNew) Reset() {
+	*x = HighScoreNew{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HighScoreNew) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HighScoreNew) ProtoMessage() {}
+
+func (x *HighScoreNew) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HighScoreNew.ProtoReflect.Descriptor instead.
+func (*HighScoreNew) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HighScoreNew) GetEntry() *ScoreEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *HighScoreNew) GetPreviousHighScore() *ScoreEntry {
+	if x != nil {
+		return x.PreviousHighScore
+	}
+	return nil
+}
+
+// BoardReset is a game's live leaderboard being emptied
+type BoardReset struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Why the board was reset, e.g. season.started or season.ended
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// The season that started or ended, if any
+	SeasonId      string `protobuf:"bytes,2,opt,name=season_id,json=seasonId,proto3" json:"season_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoardReset) Reset() {
+	*x = BoardReset{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoardReset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoardReset) ProtoMessage() {}
+
+func (x *BoardReset) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoardReset.ProtoReflect.Descriptor instead.
+func (*BoardReset) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BoardReset) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BoardReset) GetSeasonId() string {
+	if x != nil {
+		return x.SeasonId
+	}
+	return ""
+}
+
+// AchievementUnlocked is a submission that unlocked achievements
+type AchievementUnlocked struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entry         *ScoreEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	Achievements  []*Achievement         `protobuf:"bytes,2,rep,name=achievements,proto3" json:"achievements,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AchievementUnlocked) Reset() {
+	*x = AchievementUnlocked{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AchievementUnlocked) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AchievementUnlocked) ProtoMessage() {}
+
+func (x *AchievementUnlocked) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AchievementUnlocked.ProtoReflect.Descriptor instead.
+func (*AchievementUnlocked) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AchievementUnlocked) GetEntry() *ScoreEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *AchievementUnlocked) GetAchievements() []*Achievement {
+	if x != nil {
+		return x.Achievements
+	}
+	return nil
+}
+
+// Achievement is an achievement a player unlocked
+type Achievement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Icon          string                 `protobuf:"bytes,4,opt,name=icon,proto3" json:"icon,omitempty"`
+	UnlockedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=unlocked_at,json=unlockedAt,proto3" json:"unlocked_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Achievement) Reset() {
+	*x = Achievement{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Achievement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Achievement) ProtoMessage() {}
+
+func (x *Achievement) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Achievement.ProtoReflect.Descriptor instead.
+func (*Achievement) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Achievement) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Achievement) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Achievement) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Achievement) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
+func (x *Achievement) GetUnlockedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UnlockedAt
+	}
+	return nil
+}
+
+// ScoreEntry is a single score submission
+type ScoreEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Initials      string                 `protobuf:"bytes,1,opt,name=initials,proto3" json:"initials,omitempty"`
+	Score         int64                  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScoreEntry) Reset() {
+	*x = ScoreEntry{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScoreEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScoreEntry) ProtoMessage() {}
+
+func (x *ScoreEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScoreEntry.ProtoReflect.Descriptor instead.
+func (*ScoreEntry) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ScoreEntry) GetInitials() string {
+	if x != nil {
+		return x.Initials
+	}
+	return ""
+}
+
+func (x *ScoreEntry) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ScoreEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type StreamEventsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	GameId string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// Event types to receive, all of them when empty
+	Types         []string `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamEventsRequest) Reset() {
+	*x = StreamEventsRequest{}
+	mi := &file_rawboard_v1_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamEventsRequest) ProtoMessage() {}
+
+func (x *StreamEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamEventsRequest.ProtoReflect.Descriptor instead.
+func (*StreamEventsRequest) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamEventsRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *StreamEventsRequest) GetTypes() []string {
+	if x != nil {
+		return x.Types
+	}
+	return nil
+}
+
+var File_rawboard_v1_events_proto protoreflect.FileDescriptor
+
+const file_rawboard_v1_events_proto_rawDesc = "" +
+	"\n" +
+	"\x18rawboard/v1/events.proto\x12\vrawboard.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe9\x03\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12%\n" +
+	"\x0eschema_version\x18\x03 \x01(\rR\rschemaVersion\x12\x17\n" +
+	"\agame_id\x18\x04 \x01(\tR\x06gameId\x12\x16\n" +
+	"\x06tenant\x18\x05 \x01(\tR\x06tenant\x12;\n" +
+	"\voccurred_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12F\n" +
+	"\x0fscore_submitted\x18\n" +
+	" \x01(\v2\x1b.rawboard.v1.ScoreSubmittedH\x00R\x0escoreSubmitted\x12A\n" +
+	"\x0ehigh_score_new\x18\v \x01(\v2\x19.rawboard.v1.HighScoreNewH\x00R\fhighScoreNew\x12:\n" +
+	"\vboard_reset\x18\f \x01(\v2\x17.rawboard.v1.BoardResetH\x00R\n" +
+	"boardReset\x12U\n" +
+	"\x14achievement_unlocked\x18\r \x01(\v2 .rawboard.v1.AchievementUnlockedH\x00R\x13achievementUnlockedB\t\n" +
+	"\apayload\"?\n" +
+	"\x0eScoreSubmitted\x12-\n" +
+	"\x05entry\x18\x01 \x01(\v2\x17.rawboard.v1.ScoreEntryR\x05entry\"\x86\x01\n" +
+	"\fHighScoreNew\x12-\n" +
+	"\x05entry\x18\x01 \x01(\v2\x17.rawboard.v1.ScoreEntryR\x05entry\x12G\n" +
+	"\x13previous_high_score\x18\x02 \x01(\v2\x17.rawboard.v1.ScoreEntryR\x11previousHighScore\"A\n" +
+	"\n" +
+	"BoardReset\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tseason_id\x18\x02 \x01(\tR\bseasonId\"\x82\x01\n" +
+	"\x13AchievementUnlocked\x12-\n" +
+	"\x05entry\x18\x01 \x01(\v2\x17.rawboard.v1.ScoreEntryR\x05entry\x12<\n" +
+	"\fachievements\x18\x02 \x03(\v2\x18.rawboard.v1.AchievementR\fachievements\"\xa4\x01\n" +
+	"\vAchievement\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x12\n" +
+	"\x04icon\x18\x04 \x01(\tR\x04icon\x12;\n" +
+	"\vunlocked_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"unlockedAt\"x\n" +
+	"\n" +
+	"ScoreEntry\x12\x1a\n" +
+	"\binitials\x18\x01 \x01(\tR\binitials\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x03R\x05score\x128\n" +
+	"\ttimestamp\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"D\n" +
+	"\x13StreamEventsRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x14\n" +
+	"\x05types\x18\x02 \x03(\tR\x05typesB-Z+rawboard/internal/rpc/rawboardv1;rawboardv1b\x06proto3"
+
+var (
+	file_rawboard_v1_events_proto_rawDescOnce sync.Once
+	file_rawboard_v1_events_proto_rawDescData []byte
+)
+
+func file_rawboard_v1_events_proto_rawDescGZIP() []byte {
+	file_rawboard_v1_events_proto_rawDescOnce.Do(func() {
+		file_rawboard_v1_events_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rawboard_v1_events_proto_rawDesc), len(file_rawboard_v1_events_proto_rawDesc)))
+	})
+	return file_rawboard_v1_events_proto_rawDescData
+}
+
+var file_rawboard_v1_events_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_rawboard_v1_events_proto_goTypes = []any{
+	(*Event)(nil),                 // 0: rawboard.v1.Event
+	(*ScoreSubmitted)(nil),        // 1: rawboard.v1.ScoreSubmitted
+	(*HighScoreNew)(nil),          // 2: rawboard.v1.HighScoreNew
+	(*BoardReset)(nil),            // 3: rawboard.v1.BoardReset
+	(*AchievementUnlocked)(nil),   // 4: rawboard.v1.AchievementUnlocked
+	(*Achievement)(nil),           // 5: rawboard.v1.Achievement
+	(*ScoreEntry)(nil),            // 6: rawboard.v1.ScoreEntry
+	(*StreamEventsRequest)(nil),   // 7: rawboard.v1.StreamEventsRequest
+	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+}
+var file_rawboard_v1_events_proto_depIdxs = []int32{
+	8,  // 0: rawboard.v1.Event.occurred_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: rawboard.v1.Event.score_submitted:type_name -> rawboard.v1.ScoreSubmitted
+	2,  // 2: rawboard.v1.Event.high_score_new:type_name -> rawboard.v1.HighScoreNew
+	3,  // 3: rawboard.v1.Event.board_reset:type_name -> rawboard.v1.BoardReset
+	4,  // 4: rawboard.v1.Event.achievement_unlocked:type_name -> rawboard.v1.AchievementUnlocked
+	6,  // 5: rawboard.v1.ScoreSubmitted.entry:type_name -> rawboard.v1.ScoreEntry
+	6,  // 6: rawboard.v1.HighScoreNew.entry:type_name -> rawboard.v1.ScoreEntry
+	6,  // 7: rawboard.v1.HighScoreNew.previous_high_score:type_name -> rawboard.v1.ScoreEntry
+	6,  // 8: rawboard.v1.AchievementUnlocked.entry:type_name -> rawboard.v1.ScoreEntry
+	5,  // 9: rawboard.v1.AchievementUnlocked.achievements:type_name -> rawboard.v1.Achievement
+	8,  // 10: rawboard.v1.Achievement.unlocked_at:type_name -> google.protobuf.Timestamp
+	8,  // 11: rawboard.v1.ScoreEntry.timestamp:type_name -> google.protobuf.Timestamp
+	12, // [12:12] is the sub-list for method output_type
+	12, // [12:12] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_rawboard_v1_events_proto_init() }
+func file_rawboard_v1_events_proto_init() {
+	if File_rawboard_v1_events_proto != nil {
+		return
+	}
+	file_rawboard_v1_events_proto_msgTypes[0].OneofWrappers = []any{
+		(*Event_ScoreSubmitted)(nil),
+		(*Event_HighScoreNew)(nil),
+		(*Event_BoardReset)(nil),
+		(*Event_AchievementUnlocked)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rawboard_v1_events_proto_rawDesc), len(file_rawboard_v1_events_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rawboard_v1_events_proto_goTypes,
+		DependencyIndexes: file_rawboard_v1_events_proto_depIdxs,
+		MessageInfos:      file_rawboard_v1_events_proto_msgTypes,
+	}.Build()
+	File_rawboard_v1_events_proto = out.File
+	file_rawboard_v1_events_proto_goTypes = nil
+	file_rawboard_v1_events_proto_depIdxs = nil
+}
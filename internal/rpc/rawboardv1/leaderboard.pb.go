@@ -0,0 +1,507 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: rawboard/v1/leaderboard.proto
+
+package rawboardv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Leaderboard is a game's top scores, one entry per player
+type Leaderboard struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	GameId  string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Entries []*ScoreEntry          `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Incremented every time the board changes
+	Version       int64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Leaderboard) Reset() {
+	*x = Leaderboard{}
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Leaderboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Leaderboard) ProtoMessage() {}
+
+func (x *Leaderboard) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Leaderboard.ProtoReflect.Descriptor instead.
+func (*Leaderboard) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_leaderboard_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Leaderboard) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *Leaderboard) GetEntries() []*ScoreEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *Leaderboard) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type SubmitScoreRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Initials      string                 `protobuf:"bytes,2,opt,name=initials,proto3" json:"initials,omitempty"`
+	Score         int64                  `protobuf:"varint,3,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitScoreRequest) Reset() {
+	*x = SubmitScoreRequest{}
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitScoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitScoreRequest) ProtoMessage() {}
+
+func (x *SubmitScoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitScoreRequest.ProtoReflect.Descriptor instead.
+func (*SubmitScoreRequest) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_leaderboard_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubmitScoreRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *SubmitScoreRequest) GetInitials() string {
+	if x != nil {
+		return x.Initials
+	}
+	return ""
+}
+
+func (x *SubmitScoreRequest) GetScore() int64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SubmitScoreResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Entry *ScoreEntry            `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// The player's leaderboard position, absent when they aren't on the leaderboard
+	Rank *int32 `protobuf:"varint,2,opt,name=rank,proto3,oneof" json:"rank,omitempty"`
+	// Token for looking the score up later at /public/receipts/{token}
+	ReceiptToken  string `protobuf:"bytes,3,opt,name=receipt_token,json=receiptToken,proto3" json:"receipt_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitScoreResponse) Reset() {
+	*x = SubmitScoreResponse{}
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitScoreResponse) ProtoMessage() {}
+
+func (x *SubmitScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitScoreResponse.ProtoReflect.Descriptor instead.
+func (*SubmitScoreResponse) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_leaderboard_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitScoreResponse) GetEntry() *ScoreEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *SubmitScoreResponse) GetRank() int32 {
+	if x != nil && x.Rank != nil {
+		return *x.Rank
+	}
+	return 0
+}
+
+func (x *SubmitScoreResponse) GetReceiptToken() string {
+	if x != nil {
+		return x.ReceiptToken
+	}
+	return ""
+}
+
+type GetLeaderboardRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	GameId string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	// Maximum entries to return, 0 returns the whole leaderboard
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLeaderboardRequest) Reset() {
+	*x = GetLeaderboardRequest{}
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLeaderboardRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLeaderboardRequest) ProtoMessage() {}
+
+func (x *GetLeaderboardRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLeaderboardRequest.ProtoReflect.Descriptor instead.
+func (*GetLeaderboardRequest) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_leaderboard_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetLeaderboardRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GetLeaderboardRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type GetPlayerStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameId        string                 `protobuf:"bytes,1,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Initials      string                 `protobuf:"bytes,2,opt,name=initials,proto3" json:"initials,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlayerStatsRequest) Reset() {
+	*x = GetPlayerStatsRequest{}
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlayerStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlayerStatsRequest) ProtoMessage() {}
+
+func (x *GetPlayerStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlayerStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetPlayerStatsRequest) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_leaderboard_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPlayerStatsRequest) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *GetPlayerStatsRequest) GetInitials() string {
+	if x != nil {
+		return x.Initials
+	}
+	return ""
+}
+
+// PlayerStats summarizes one player's scores in a game
+type PlayerStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Initials      string                 `protobuf:"bytes,1,opt,name=initials,proto3" json:"initials,omitempty"`
+	HighScore     int64                  `protobuf:"varint,2,opt,name=high_score,json=highScore,proto3" json:"high_score,omitempty"`
+	TotalScores   int32                  `protobuf:"varint,3,opt,name=total_scores,json=totalScores,proto3" json:"total_scores,omitempty"`
+	AverageScore  float64                `protobuf:"fixed64,4,opt,name=average_score,json=averageScore,proto3" json:"average_score,omitempty"`
+	FirstPlayed   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=first_played,json=firstPlayed,proto3" json:"first_played,omitempty"`
+	LastPlayed    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=last_played,json=lastPlayed,proto3" json:"last_played,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerStats) Reset() {
+	*x = PlayerStats{}
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerStats) ProtoMessage() {}
+
+func (x *PlayerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_rawboard_v1_leaderboard_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerStats.ProtoReflect.Descriptor instead.
+func (*PlayerStats) Descriptor() ([]byte, []int) {
+	return file_rawboard_v1_leaderboard_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlayerStats) GetInitials() string {
+	if x != nil {
+		return x.Initials
+	}
+	return ""
+}
+
+func (x *PlayerStats) GetHighScore() int64 {
+	if x != nil {
+		return x.HighScore
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetTotalScores() int32 {
+	if x != nil {
+		return x.TotalScores
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetAverageScore() float64 {
+	if x != nil {
+		return x.AverageScore
+	}
+	return 0
+}
+
+func (x *PlayerStats) GetFirstPlayed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FirstPlayed
+	}
+	return nil
+}
+
+func (x *PlayerStats) GetLastPlayed() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastPlayed
+	}
+	return nil
+}
+
+var File_rawboard_v1_leaderboard_proto protoreflect.FileDescriptor
+
+const file_rawboard_v1_leaderboard_proto_rawDesc = "" +
+	"\n" +
+	"\x1drawboard/v1/leaderboard.proto\x12\vrawboard.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x18rawboard/v1/events.proto\"s\n" +
+	"\vLeaderboard\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x121\n" +
+	"\aentries\x18\x02 \x03(\v2\x17.rawboard.v1.ScoreEntryR\aentries\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"_\n" +
+	"\x12SubmitScoreRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1a\n" +
+	"\binitials\x18\x02 \x01(\tR\binitials\x12\x14\n" +
+	"\x05score\x18\x03 \x01(\x03R\x05score\"\x8b\x01\n" +
+	"\x13SubmitScoreResponse\x12-\n" +
+	"\x05entry\x18\x01 \x01(\v2\x17.rawboard.v1.ScoreEntryR\x05entry\x12\x17\n" +
+	"\x04rank\x18\x02 \x01(\x05H\x00R\x04rank\x88\x01\x01\x12#\n" +
+	"\rreceipt_token\x18\x03 \x01(\tR\freceiptTokenB\a\n" +
+	"\x05_rank\"F\n" +
+	"\x15GetLeaderboardRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"L\n" +
+	"\x15GetPlayerStatsRequest\x12\x17\n" +
+	"\agame_id\x18\x01 \x01(\tR\x06gameId\x12\x1a\n" +
+	"\binitials\x18\x02 \x01(\tR\binitials\"\x8c\x02\n" +
+	"\vPlayerStats\x12\x1a\n" +
+	"\binitials\x18\x01 \x01(\tR\binitials\x12\x1d\n" +
+	"\n" +
+	"high_score\x18\x02 \x01(\x03R\thighScore\x12!\n" +
+	"\ftotal_scores\x18\x03 \x01(\x05R\vtotalScores\x12#\n" +
+	"\raverage_score\x18\x04 \x01(\x01R\faverageScore\x12=\n" +
+	"\ffirst_played\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vfirstPlayed\x12;\n" +
+	"\vlast_played\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"lastPlayed2\xce\x02\n" +
+	"\x12LeaderboardService\x12P\n" +
+	"\vSubmitScore\x12\x1f.rawboard.v1.SubmitScoreRequest\x1a .rawboard.v1.SubmitScoreResponse\x12N\n" +
+	"\x0eGetLeaderboard\x12\".rawboard.v1.GetLeaderboardRequest\x1a\x18.rawboard.v1.Leaderboard\x12N\n" +
+	"\x0eGetPlayerStats\x12\".rawboard.v1.GetPlayerStatsRequest\x1a\x18.rawboard.v1.PlayerStats\x12F\n" +
+	"\fStreamEvents\x12 .rawboard.v1.StreamEventsRequest\x1a\x12.rawboard.v1.Event0\x01B-Z+rawboard/internal/rpc/rawboardv1;rawboardv1b\x06proto3"
+
+var (
+	file_rawboard_v1_leaderboard_proto_rawDescOnce sync.Once
+	file_rawboard_v1_leaderboard_proto_rawDescData []byte
+)
+
+func file_rawboard_v1_leaderboard_proto_rawDescGZIP() []byte {
+	file_rawboard_v1_leaderboard_proto_rawDescOnce.Do(func() {
+		file_rawboard_v1_leaderboard_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rawboard_v1_leaderboard_proto_rawDesc), len(file_rawboard_v1_leaderboard_proto_rawDesc)))
+	})
+	return file_rawboard_v1_leaderboard_proto_rawDescData
+}
+
+var file_rawboard_v1_leaderboard_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_rawboard_v1_leaderboard_proto_goTypes = []any{
+	(*Leaderboard)(nil),           // 0: rawboard.v1.Leaderboard
+	(*SubmitScoreRequest)(nil),    // 1: rawboard.v1.SubmitScoreRequest
+	(*SubmitScoreResponse)(nil),   // 2: rawboard.v1.SubmitScoreResponse
+	(*GetLeaderboardRequest)(nil), // 3: rawboard.v1.GetLeaderboardRequest
+	(*GetPlayerStatsRequest)(nil), // 4: rawboard.v1.GetPlayerStatsRequest
+	(*PlayerStats)(nil),           // 5: rawboard.v1.PlayerStats
+	(*ScoreEntry)(nil),            // 6: rawboard.v1.ScoreEntry
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+	(*StreamEventsRequest)(nil),   // 8: rawboard.v1.StreamEventsRequest
+	(*Event)(nil),                 // 9: rawboard.v1.Event
+}
+var file_rawboard_v1_leaderboard_proto_depIdxs = []int32{
+	6, // 0: rawboard.v1.Leaderboard.entries:type_name -> rawboard.v1.ScoreEntry
+	6, // 1: rawboard.v1.SubmitScoreResponse.entry:type_name -> rawboard.v1.ScoreEntry
+	7, // 2: rawboard.v1.PlayerStats.first_played:type_name -> google.protobuf.Timestamp
+	7, // 3: rawboard.v1.PlayerStats.last_played:type_name -> google.protobuf.Timestamp
+	1, // 4: rawboard.v1.LeaderboardService.SubmitScore:input_type -> rawboard.v1.SubmitScoreRequest
+	3, // 5: rawboard.v1.LeaderboardService.GetLeaderboard:input_type -> rawboard.v1.GetLeaderboardRequest
+	4, // 6: rawboard.v1.LeaderboardService.GetPlayerStats:input_type -> rawboard.v1.GetPlayerStatsRequest
+	8, // 7: rawboard.v1.LeaderboardService.StreamEvents:input_type -> rawboard.v1.StreamEventsRequest
+	2, // 8: rawboard.v1.LeaderboardService.SubmitScore:output_type -> rawboard.v1.SubmitScoreResponse
+	0, // 9: rawboard.v1.LeaderboardService.GetLeaderboard:output_type -> rawboard.v1.Leaderboard
+	5, // 10: rawboard.v1.LeaderboardService.GetPlayerStats:output_type -> rawboard.v1.PlayerStats
+	9, // 11: rawboard.v1.LeaderboardService.StreamEvents:output_type -> rawboard.v1.Event
+	8, // [8:12] is the sub-list for method output_type
+	4, // [4:8] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_rawboard_v1_leaderboard_proto_init() }
+func file_rawboard_v1_leaderboard_proto_init() {
+	if File_rawboard_v1_leaderboard_proto != nil {
+		return
+	}
+	file_rawboard_v1_events_proto_init()
+	file_rawboard_v1_leaderboard_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rawboard_v1_leaderboard_proto_rawDesc), len(file_rawboard_v1_leaderboard_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rawboard_v1_leaderboard_proto_goTypes,
+		DependencyIndexes: file_rawboard_v1_leaderboard_proto_depIdxs,
+		MessageInfos:      file_rawboard_v1_leaderboard_proto_msgTypes,
+	}.Build()
+	File_rawboard_v1_leaderboard_proto = out.File
+	file_rawboard_v1_leaderboard_proto_goTypes = nil
+	file_rawboard_v1_leaderboard_proto_depIdxs = nil
+}
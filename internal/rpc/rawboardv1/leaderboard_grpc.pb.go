@@ -0,0 +1,251 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: rawboard/v1/leaderboard.proto
+
+package rawboardv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	LeaderboardService_SubmitScore_FullMethodName    = "/rawboard.v1.LeaderboardService/SubmitScore"
+	LeaderboardService_GetLeaderboard_FullMethodName = "/rawboard.v1.LeaderboardService/GetLeaderboard"
+	LeaderboardService_GetPlayerStats_FullMethodName = "/rawboard.v1.LeaderboardService/GetPlayerStats"
+	LeaderboardService_StreamEvents_FullMethodName   = "/rawboard.v1.LeaderboardService/StreamEvents"
+)
+
+// LeaderboardServiceClient is the client API for LeaderboardService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// LeaderboardService is the typed, protobuf counterpart of the REST API for game engines
+type LeaderboardServiceClient interface {
+	// SubmitScore records a score; requires an API key with the submit scope for the game
+	SubmitScore(ctx context.Context, in *SubmitScoreRequest, opts ...grpc.CallOption) (*SubmitScoreResponse, error)
+	// GetLeaderboard returns the highest score per player, best first
+	GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*Leaderboard, error)
+	// GetPlayerStats returns a player's statistics across their whole score history
+	GetPlayerStats(ctx context.Context, in *GetPlayerStatsRequest, opts ...grpc.CallOption) (*PlayerStats, error)
+	// StreamEvents streams a game's domain events as they happen, until the caller hangs up
+	StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type leaderboardServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewLeaderboardServiceClient(cc grpc.ClientConnInterface) LeaderboardServiceClient {
+	return &leaderboardServiceClient{cc}
+}
+
+func (c *leaderboardServiceClient) SubmitScore(ctx context.Context, in *SubmitScoreRequest, opts ...grpc.CallOption) (*SubmitScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SubmitScoreResponse)
+	err := c.cc.Invoke(ctx, LeaderboardService_SubmitScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetLeaderboard(ctx context.Context, in *GetLeaderboardRequest, opts ...grpc.CallOption) (*Leaderboard, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Leaderboard)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetLeaderboard_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) GetPlayerStats(ctx context.Context, in *GetPlayerStatsRequest, opts ...grpc.CallOption) (*PlayerStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PlayerStats)
+	err := c.cc.Invoke(ctx, LeaderboardService_GetPlayerStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *leaderboardServiceClient) StreamEvents(ctx context.Context, in *StreamEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LeaderboardService_ServiceDesc.Streams[0], LeaderboardService_StreamEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LeaderboardService_StreamEventsClient = grpc.ServerStreamingClient[Event]
+
+// LeaderboardServiceServer is the server API for LeaderboardService service.
+// All implementations must embed UnimplementedLeaderboardServiceServer
+// for forward compatibility.
+//
+// LeaderboardService is the typed, protobuf counterpart of the REST API for game engines
+type LeaderboardServiceServer interface {
+	// SubmitScore records a score; requires an API key with the submit scope for the game
+	SubmitScore(context.Context, *SubmitScoreRequest) (*SubmitScoreResponse, error)
+	// GetLeaderboard returns the highest score per player, best first
+	GetLeaderboard(context.Context, *GetLeaderboardRequest) (*Leaderboard, error)
+	// GetPlayerStats returns a player's statistics across their whole score history
+	GetPlayerStats(context.Context, *GetPlayerStatsRequest) (*PlayerStats, error)
+	// StreamEvents streams a game's domain events as they happen, until the caller hangs up
+	StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedLeaderboardServiceServer()
+}
+
+// UnimplementedLeaderboardServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedLeaderboardServiceServer struct{}
+
+func (UnimplementedLeaderboardServiceServer) SubmitScore(context.Context, *SubmitScoreRequest) (*SubmitScoreResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitScore not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetLeaderboard(context.Context, *GetLeaderboardRequest) (*Leaderboard, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLeaderboard not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) GetPlayerStats(context.Context, *GetPlayerStatsRequest) (*PlayerStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlayerStats not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) StreamEvents(*StreamEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamEvents not implemented")
+}
+func (UnimplementedLeaderboardServiceServer) mustEmbedUnimplementedLeaderboardServiceServer() {}
+func (UnimplementedLeaderboardServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeLeaderboardServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to LeaderboardServiceServer will
+// result in compilation errors.
+type UnsafeLeaderboardServiceServer interface {
+	mustEmbedUnimplementedLeaderboardServiceServer()
+}
+
+func RegisterLeaderboardServiceServer(s grpc.ServiceRegistrar, srv LeaderboardServiceServer) {
+	// If the following call pancis, it indicates UnimplementedLeaderboardServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&LeaderboardService_ServiceDesc, srv)
+}
+
+func _LeaderboardService_SubmitScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).SubmitScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_SubmitScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).SubmitScore(ctx, req.(*SubmitScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetLeaderboard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLeaderboardRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetLeaderboard(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetLeaderboard_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetLeaderboard(ctx, req.(*GetLeaderboardRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_GetPlayerStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlayerStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LeaderboardServiceServer).GetPlayerStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LeaderboardService_GetPlayerStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LeaderboardServiceServer).GetPlayerStats(ctx, req.(*GetPlayerStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LeaderboardService_StreamEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LeaderboardServiceServer).StreamEvents(m, &grpc.GenericServerStream[StreamEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LeaderboardService_StreamEventsServer = grpc.ServerStreamingServer[Event]
+
+// LeaderboardService_ServiceDesc is the grpc.ServiceDesc for LeaderboardService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var LeaderboardService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rawboard.v1.LeaderboardService",
+	HandlerType: (*LeaderboardServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitScore",
+			Handler:    _LeaderboardService_SubmitScore_Handler,
+		},
+		{
+			MethodName: "GetLeaderboard",
+			Handler:    _LeaderboardService_GetLeaderboard_Handler,
+		},
+		{
+			MethodName: "GetPlayerStats",
+			Handler:    _LeaderboardService_GetPlayerStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamEvents",
+			Handler:       _LeaderboardService_StreamEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rawboard/v1/leaderboard.proto",
+}
@@ -0,0 +1,30 @@
+package rpc
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+
+	"rawboard/internal/logging"
+)
+
+// requestIDMetadata carries a call's correlation ID, like the HTTP API's X-Request-ID
+const requestIDMetadata = "x-request-id"
+
+// RequestIDInterceptor accepts the caller's x-request-id metadata, or generates an ID
+// when it is missing or malformed, and scopes the call's context and logger to it so
+// service and database logs of the call carry the same ID
+func RequestIDInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestID := firstMetadata(ctx, requestIDMetadata)
+		if !logging.ValidRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		ctx = logging.WithRequestID(ctx, requestID)
+		ctx = logging.WithContext(ctx, logger.With("request_id", requestID, "method", info.FullMethod))
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,232 @@
+// Package rpc serves the protobuf LeaderboardService, the typed counterpart of the REST API,
+// natively over gRPC on its own port and to browsers over gRPC-Web
+package rpc
+
+//go:generate protoc -I ../../proto --go_out=../.. --go_opt=module=rawboard --go-grpc_out=../.. --go-grpc_opt=module=rawboard rawboard/v1/leaderboard.proto rawboard/v1/events.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/events"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+	"rawboard/internal/tenants"
+)
+
+// maxGameIDLength matches the REST API's game ID limit
+const maxGameIDLength = 50
+
+// Server implements rawboardv1.LeaderboardServiceServer on top of the leaderboard service
+type Server struct {
+	rawboardv1.UnimplementedLeaderboardServiceServer
+	service *leaderboard.Service
+	bus     *events.Bus // Nil leaves StreamEvents unimplemented
+	logger  *slog.Logger
+}
+
+// NewServer creates a LeaderboardService implementation streaming events from bus
+func NewServer(service *leaderboard.Service, bus *events.Bus, logger *slog.Logger) *Server {
+	return &Server{service: service, bus: bus, logger: logger}
+}
+
+// NewGRPCServer returns a gRPC server exposing the LeaderboardService behind API key
+// authentication. Reflection is enabled so tools like grpcurl can discover the API.
+func NewGRPCServer(service *leaderboard.Service, bus *events.Bus, masterKey string, keys *apikeys.Store, logger *slog.Logger) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(RequestIDInterceptor(logger), APIKeyInterceptor(masterKey, keys)))
+	rawboardv1.RegisterLeaderboardServiceServer(server, NewServer(service, bus, logger))
+	reflection.Register(server)
+	return server
+}
+
+// SubmitScore records a score and reports the player's leaderboard position
+func (s *Server) SubmitScore(ctx context.Context, req *rawboardv1.SubmitScoreRequest) (*rawboardv1.SubmitScoreResponse, error) {
+	gameID := req.GetGameId()
+	if err := validateGameID(gameID); err != nil {
+		return nil, err
+	}
+
+	entry := &models.ScoreEntry{Initials: req.GetInitials(), Score: req.GetScore()}
+	if err := entry.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	gameID = s.service.ResolveGameID(ctx, gameID)
+
+	err := s.service.SubmitScore(ctx, gameID, entry.Initials, entry.Score)
+	if errors.Is(err, models.ErrBlockedInitials) || errors.Is(err, models.ErrSuspiciousScore) || errors.Is(err, models.ErrInvalidScore) || errors.Is(err, models.ErrSubmissionRejected) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, models.ErrGameLimitExceeded) || errors.Is(err, leaderboard.ErrProfileLocked) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	}
+	if errors.Is(err, leaderboard.ErrPINRequired) || errors.Is(err, leaderboard.ErrClaimRequired) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, models.ErrGameSunset) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if errors.Is(err, models.ErrSignatureRequired) {
+		// SubmitScoreRequest has no signature, so games requiring one refuse gRPC submissions
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Error("score submission failed", "game_id", gameID, "error", err)
+		return nil, status.Error(codes.Internal, "score submission failed")
+	}
+
+	// Issue a receipt so the player can check on this score later
+	receipt, err := s.service.IssueReceipt(ctx, gameID, entry.Initials, entry.Score)
+	if err != nil {
+		logging.FromContext(ctx, s.logger).Warn("failed to issue score receipt", "game_id", gameID, "error", err)
+	}
+
+	response := &rawboardv1.SubmitScoreResponse{
+		Entry:        scoreEntry(*entry),
+		ReceiptToken: receipt,
+	}
+
+	// The submission stands even if the leaderboard can't be read back
+	if board, err := s.service.GetLeaderboard(ctx, gameID); err == nil {
+		for i, scoreEntry := range board.Entries {
+			if scoreEntry.Initials == entry.Initials {
+				rank := int32(i + 1)
+				response.Rank = &rank
+				break
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// GetLeaderboard returns a game's leaderboard, optionally truncated to limit entries
+func (s *Server) GetLeaderboard(ctx context.Context, req *rawboardv1.GetLeaderboardRequest) (*rawboardv1.Leaderboard, error) {
+	gameID := req.GetGameId()
+	if err := validateGameID(gameID); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.GetLimit())
+	if maxEntries := s.service.LeaderboardSize(ctx, gameID); limit < 0 || limit > maxEntries {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be between 0 and %d", maxEntries)
+	}
+
+	board, err := s.service.GetLeaderboard(ctx, gameID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "no leaderboard found for game %s", gameID)
+	}
+
+	entries := board.Entries
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	response := &rawboardv1.Leaderboard{
+		GameId:  board.GameID,
+		Entries: make([]*rawboardv1.ScoreEntry, 0, len(entries)),
+		Version: board.Version,
+	}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, scoreEntry(entry))
+	}
+	return response, nil
+}
+
+// GetPlayerStats returns a player's statistics across their whole score history
+func (s *Server) GetPlayerStats(ctx context.Context, req *rawboardv1.GetPlayerStatsRequest) (*rawboardv1.PlayerStats, error) {
+	gameID := req.GetGameId()
+	if err := validateGameID(gameID); err != nil {
+		return nil, err
+	}
+
+	initials := strings.ToUpper(strings.TrimSpace(req.GetInitials()))
+	if len(initials) != 3 {
+		return nil, status.Error(codes.InvalidArgument, "initials must be exactly 3 characters")
+	}
+
+	stats, err := s.service.GetPlayerStats(ctx, gameID, initials)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "no stats found for player %s in game %s", initials, gameID)
+	}
+
+	return &rawboardv1.PlayerStats{
+		Initials:     stats.Initials,
+		HighScore:    stats.HighScore,
+		TotalScores:  int32(stats.TotalScores),
+		AverageScore: stats.AverageScore,
+		FirstPlayed:  timestamppb.New(stats.FirstPlayed),
+		LastPlayed:   timestamppb.New(stats.LastPlayed),
+	}, nil
+}
+
+// StreamEvents streams a game's domain events until the caller hangs up. Like reads, it
+// needs no API key; the x-tenant-id metadata picks the tenant.
+func (s *Server) StreamEvents(req *rawboardv1.StreamEventsRequest, stream grpc.ServerStreamingServer[rawboardv1.Event]) error {
+	if s.bus == nil {
+		return status.Error(codes.Unimplemented, "event streaming is not enabled")
+	}
+	gameID := req.GetGameId()
+	if err := validateGameID(gameID); err != nil {
+		return err
+	}
+	ctx := stream.Context()
+	tenant := firstMetadata(ctx, tenantMetadata)
+	if tenant != "" {
+		if err := tenants.Validate(tenant); err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	sub, err := s.bus.Subscribe(tenant, gameID, req.GetTypes()...)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer s.bus.Unsubscribe(sub)
+	// Tell the caller the stream is live, so it knows later events will reach it
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-sub.Events():
+			if !ok {
+				if errors.Is(sub.Err(), events.ErrSlowSubscriber) {
+					return status.Error(codes.ResourceExhausted, sub.Err().Error())
+				}
+				return status.Error(codes.Unavailable, "event stream closed")
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// validateGameID applies the REST API's game ID rules
+func validateGameID(gameID string) error {
+	if len(gameID) < 1 || len(gameID) > maxGameIDLength {
+		return status.Error(codes.InvalidArgument, fmt.Sprintf("game_id must be between 1 and %d characters", maxGameIDLength))
+	}
+	return nil
+}
+
+// scoreEntry converts a model score to its protobuf form
+func scoreEntry(entry models.ScoreEntry) *rawboardv1.ScoreEntry {
+	return events.ScoreEntry(entry)
+}
@@ -0,0 +1,157 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/database"
+	"rawboard/internal/events"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/logging"
+	"rawboard/internal/rpc/rawboardv1"
+)
+
+// dialTestServer starts a gRPC server on an in-memory listener and returns a client for it
+func dialTestServer(t *testing.T, masterKey string) rawboardv1.LeaderboardServiceClient {
+	t.Helper()
+
+	db := database.NewFake()
+	return dial(t, NewGRPCServer(leaderboard.NewService(db), nil, masterKey, apikeys.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil))))
+}
+
+// dial serves server on an in-memory listener and returns a client for it
+func dial(t *testing.T, server *grpc.Server) rawboardv1.LeaderboardServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to dial test server: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return rawboardv1.NewLeaderboardServiceClient(conn)
+}
+
+func TestGRPCServer(t *testing.T) {
+	client := dialTestServer(t, "master-key")
+	ctx := context.Background()
+	authed := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer master-key")
+
+	t.Run("authenticates submissions from metadata", func(t *testing.T) {
+		req := &rawboardv1.SubmitScoreRequest{GameId: "pacman", Initials: "AAA", Score: 500}
+		if _, err := client.SubmitScore(ctx, req); status.Code(err) != codes.Unauthenticated {
+			t.Errorf("Expected Unauthenticated without metadata, got %v", err)
+		}
+
+		if _, err := client.SubmitScore(authed, req); err != nil {
+			t.Fatalf("Expected submission with a bearer token to succeed, got %v", err)
+		}
+		if _, err := client.SubmitScore(authed, &rawboardv1.SubmitScoreRequest{GameId: "pacman", Initials: "AAA", Score: 1500}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	})
+
+	t.Run("serves the leaderboard with its version", func(t *testing.T) {
+		board, err := client.GetLeaderboard(ctx, &rawboardv1.GetLeaderboardRequest{GameId: "pacman"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(board.GetEntries()) != 1 || board.GetEntries()[0].GetScore() != 1500 || board.GetVersion() != 2 {
+			t.Errorf("Unexpected leaderboard: %v", board)
+		}
+	})
+
+	t.Run("serves player stats", func(t *testing.T) {
+		stats, err := client.GetPlayerStats(ctx, &rawboardv1.GetPlayerStatsRequest{GameId: "pacman", Initials: "aaa"})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if stats.GetHighScore() != 1500 || stats.GetTotalScores() != 2 || stats.GetAverageScore() != 1000 {
+			t.Errorf("Unexpected stats: %v", stats)
+		}
+
+		if _, err := client.GetPlayerStats(ctx, &rawboardv1.GetPlayerStatsRequest{GameId: "pacman", Initials: "ZZZ"}); status.Code(err) != codes.NotFound {
+			t.Errorf("Expected NotFound for an unknown player, got %v", err)
+		}
+		if _, err := client.GetPlayerStats(ctx, &rawboardv1.GetPlayerStatsRequest{GameId: "pacman", Initials: "TOOLONG"}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for bad initials, got %v", err)
+		}
+	})
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	interceptor := RequestIDInterceptor(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	info := &grpc.UnaryServerInfo{FullMethod: rawboardv1.LeaderboardService_GetLeaderboard_FullMethodName}
+	requestID := func(ctx context.Context) string {
+		var seen string
+		interceptor(ctx, nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			seen = logging.RequestID(ctx)
+			return nil, nil
+		})
+		return seen
+	}
+
+	sent := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "trace-42"))
+	if got := requestID(sent); got != "trace-42" {
+		t.Errorf("Expected the caller's request ID, got %q", got)
+	}
+	malformed := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "bad id\n"))
+	if got := requestID(malformed); got == "" || got == "bad id\n" {
+		t.Errorf("Expected a generated request ID, got %q", got)
+	}
+}
+
+func TestStreamEvents(t *testing.T) {
+	db := database.NewFake()
+	bus := events.NewBus(struct{ database.DB }{db}) // In-process, without pub/sub
+	service := leaderboard.NewService(db, leaderboard.WithScoreListener(bus))
+	client := dial(t, NewGRPCServer(service, bus, "", apikeys.NewStore(db), slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if stream, err := client.StreamEvents(ctx, &rawboardv1.StreamEventsRequest{GameId: "pacman", Types: []string{"score.deleted"}}); err == nil {
+		if _, err := stream.Recv(); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Expected InvalidArgument for an unknown type, got %v", err)
+		}
+	}
+
+	stream, err := client.StreamEvents(ctx, &rawboardv1.StreamEventsRequest{GameId: "pacman", Types: []string{events.TypeHighScoreNew}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The stream has subscribed once its headers arrive
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := service.SubmitScore(ctx, "pacman", "AAA", 5000); err != nil {
+		t.Fatal(err)
+	}
+	event, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Recv failed: %v", err)
+	}
+	if event.GetType() != events.TypeHighScoreNew || event.GetHighScoreNew().GetEntry().GetInitials() != "AAA" {
+		t.Errorf("Expected AAA's highscore.new, got %v", event)
+	}
+}
@@ -0,0 +1,90 @@
+package selfcheck
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// DrainReasonShutdown is the reason given when the server drains itself to exit
+const DrainReasonShutdown = "shutdown"
+
+// ErrShuttingDown is returned for an instance draining to exit, which can't return to
+// service
+var ErrShuttingDown = errors.New("instance is shutting down")
+
+// Drain is whether this instance is draining ahead of maintenance or shutdown. It's
+// held in memory, so it belongs to the instance and is forgotten on restart.
+type Drain struct {
+	mu      sync.Mutex
+	since   time.Time
+	reason  string
+	exiting bool
+}
+
+// Start marks the instance as draining. An instance already draining keeps the time
+// it started, so waiting out a drain counts from the first request for one.
+func (d *Drain) Start(reason string) models.DrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.start(reason)
+	return d.status()
+}
+
+// Shutdown marks the instance as draining to exit
+func (d *Drain) Shutdown() models.DrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.start(DrainReasonShutdown)
+	d.exiting = true
+	return d.status()
+}
+
+// Stop returns the instance to service, unless it's shutting down
+func (d *Drain) Stop() (models.DrainStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.exiting {
+		return d.status(), ErrShuttingDown
+	}
+	d.since, d.reason = time.Time{}, ""
+	return d.status(), nil
+}
+
+// Status reports whether the instance is draining, and since when
+func (d *Drain) Status() models.DrainStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.status()
+}
+
+// Remaining returns how much of delay is left since draining started, so a shutdown
+// gives load balancers at least delay to notice, without waiting twice after a drain
+// requested ahead of it
+func (d *Drain) Remaining(delay time.Duration) time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.since.IsZero() {
+		return delay
+	}
+	return max(delay-time.Since(d.since), 0)
+}
+
+func (d *Drain) start(reason string) {
+	if d.since.IsZero() {
+		d.since = time.Now().UTC()
+	}
+	if reason != "" && !d.exiting {
+		d.reason = reason
+	}
+}
+
+func (d *Drain) status() models.DrainStatus {
+	if d.since.IsZero() {
+		return models.DrainStatus{}
+	}
+	since := d.since
+	return models.DrainStatus{Draining: true, Since: &since, Reason: d.reason}
+}
@@ -0,0 +1,64 @@
+package selfcheck
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// Dependency is something the server can't serve traffic without
+type Dependency struct {
+	Name string
+	Ping func(ctx context.Context) error
+}
+
+// Probe pings every dependency concurrently, giving each up to timeout, and reports
+// whether the server is ready for traffic
+func Probe(ctx context.Context, timeout time.Duration, dependencies []Dependency) *models.ReadinessReport {
+	report := &models.ReadinessReport{
+		Status:       models.ReadinessReady,
+		CheckedAt:    time.Now().UTC(),
+		Dependencies: make([]models.DependencyStatus, len(dependencies)),
+	}
+
+	var wg sync.WaitGroup
+	for i, dependency := range dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Dependencies[i] = probe(ctx, timeout, dependency)
+		}()
+	}
+	wg.Wait()
+
+	for _, status := range report.Dependencies {
+		if status.Status != models.DependencyUp {
+			report.Status = models.ReadinessNotReady
+		}
+	}
+	return report
+}
+
+// probe pings one dependency
+func probe(ctx context.Context, timeout time.Duration, dependency Dependency) models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := dependency.Ping(ctx)
+	status := models.DependencyStatus{
+		Name:      dependency.Name,
+		Status:    models.DependencyUp,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err() // Answered, but too slowly to count
+	}
+	if err != nil {
+		status.Status = models.DependencyDown
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,273 @@
+package selfcheck
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"rawboard/internal/config"
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+const (
+	// checkTimeout bounds each individual check
+	checkTimeout = 3 * time.Second
+
+	// SkewCriticalThreshold is the clock skew at which the self-check fails;
+	// smaller skew above CLOCK_SKEW_THRESHOLD only warns
+	SkewCriticalThreshold = 30 * time.Second
+
+	// CertWarnWindow is how close to expiry a TLS certificate starts warning
+	CertWarnWindow = 14 * 24 * time.Hour
+)
+
+// statusRank orders statuses so a report can take the worst one
+var statusRank = map[string]int{
+	models.CheckStatusOK:       0,
+	models.CheckStatusSkipped:  0,
+	models.CheckStatusWarn:     1,
+	models.CheckStatusCritical: 2,
+}
+
+// Checker runs the startup self-check and remembers the latest report
+type Checker struct {
+	cfg     *config.Config
+	db      database.DB
+	service *leaderboard.Service
+	skew    *SkewMonitor // nil when the database can't report its time
+	now     func() time.Time
+
+	mu   sync.Mutex
+	last *models.SelfCheckReport
+}
+
+// NewChecker creates a new self-checker
+func NewChecker(cfg *config.Config, db database.DB, service *leaderboard.Service, logger *slog.Logger) *Checker {
+	c := &Checker{
+		cfg:     cfg,
+		db:      db,
+		service: service,
+		now:     time.Now,
+	}
+	if clock, ok := db.(database.Clock); ok {
+		c.skew = NewSkewMonitor(clock, cfg.ClockSkewThreshold, logger)
+	}
+	return c
+}
+
+// SkewMonitor returns the clock skew monitor, or nil if the database can't report its time
+func (c *Checker) SkewMonitor() *SkewMonitor {
+	return c.skew
+}
+
+// Run performs every check and stores the report as the latest
+func (c *Checker) Run(ctx context.Context) *models.SelfCheckReport {
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) models.SelfCheck
+	}{
+		{"config", c.checkConfig},
+		{"database", c.checkDatabase},
+		{"migrations", c.checkMigrations},
+		{"clock_skew", c.checkClockSkew},
+		{"tls_certificate", c.checkTLSCertificate},
+	}
+
+	report := &models.SelfCheckReport{
+		Status:    models.CheckStatusOK,
+		CheckedAt: c.now().UTC(),
+		Checks:    make([]models.SelfCheck, 0, len(checks)),
+	}
+
+	for _, check := range checks {
+		checkCtx, cancel := context.WithTimeout(ctx, checkTimeout)
+		start := time.Now()
+		result := check.run(checkCtx)
+		cancel()
+
+		result.Name = check.name
+		result.DurationMs = time.Since(start).Milliseconds()
+		report.Checks = append(report.Checks, result)
+
+		if statusRank[result.Status] > statusRank[report.Status] {
+			report.Status = result.Status
+		}
+	}
+
+	c.mu.Lock()
+	c.last = report
+	c.mu.Unlock()
+
+	return report
+}
+
+// Last returns the most recent report, or nil if the check hasn't run
+func (c *Checker) Last() *models.SelfCheckReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// Log emits the report as a single structured record at a level matching its status
+func Log(logger *slog.Logger, report *models.SelfCheckReport) {
+	level := slog.LevelInfo
+	switch report.Status {
+	case models.CheckStatusWarn:
+		level = slog.LevelWarn
+	case models.CheckStatusCritical:
+		level = slog.LevelError
+	}
+
+	checks := make([]any, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		checks = append(checks, slog.Group(check.Name,
+			"status", check.Status,
+			"message", check.Message,
+			"duration_ms", check.DurationMs,
+		))
+	}
+
+	logger.Log(context.Background(), level, "startup self-check",
+		"status", report.Status,
+		slog.Group("checks", checks...),
+	)
+}
+
+// checkConfig re-validates configuration and flags risky settings
+func (c *Checker) checkConfig(ctx context.Context) models.SelfCheck {
+	if err := c.cfg.Validate(); err != nil {
+		return models.SelfCheck{Status: models.CheckStatusCritical, Message: err.Error()}
+	}
+
+	if !c.cfg.HasAPIKey() {
+		if c.cfg.IsProduction() {
+			return models.SelfCheck{Status: models.CheckStatusCritical, Message: "RAWBOARD_API_KEY is required in production"}
+		}
+		return models.SelfCheck{Status: models.CheckStatusWarn, Message: "authentication disabled, no RAWBOARD_API_KEY set"}
+	}
+
+	return models.SelfCheck{
+		Status:  models.CheckStatusOK,
+		Message: "valid",
+		Details: map[string]interface{}{"environment": c.cfg.Environment},
+	}
+}
+
+// checkDatabase pings the database
+func (c *Checker) checkDatabase(ctx context.Context) models.SelfCheck {
+	start := time.Now()
+	if err := c.db.Ping(ctx); err != nil {
+		return models.SelfCheck{Status: models.CheckStatusCritical, Message: fmt.Sprintf("ping failed: %v", err)}
+	}
+
+	return models.SelfCheck{
+		Status:  models.CheckStatusOK,
+		Message: "connected",
+		Details: map[string]interface{}{"ping_ms": time.Since(start).Milliseconds()},
+	}
+}
+
+// checkMigrations reports registered games still in the legacy storage format
+func (c *Checker) checkMigrations(ctx context.Context) models.SelfCheck {
+	pending, err := c.service.PendingMigrations(ctx)
+	if err != nil {
+		return models.SelfCheck{Status: models.CheckStatusWarn, Message: fmt.Sprintf("could not check migrations: %v", err)}
+	}
+
+	if len(pending) > 0 {
+		return models.SelfCheck{
+			Status:  models.CheckStatusWarn,
+			Message: fmt.Sprintf("%d game(s) pending migration, they migrate on first read", len(pending)),
+			Details: map[string]interface{}{"pending": pending},
+		}
+	}
+
+	return models.SelfCheck{Status: models.CheckStatusOK, Message: "all registered games migrated"}
+}
+
+// checkClockSkew compares the server clock with the database clock
+func (c *Checker) checkClockSkew(ctx context.Context) models.SelfCheck {
+	if c.skew == nil {
+		return models.SelfCheck{Status: models.CheckStatusSkipped, Message: "database does not report its time"}
+	}
+
+	reading, err := c.skew.Measure(ctx)
+	if err != nil {
+		return models.SelfCheck{Status: models.CheckStatusWarn, Message: fmt.Sprintf("could not read database time: %v", err)}
+	}
+
+	skew := time.Duration(reading.SkewMs) * time.Millisecond
+	check := models.SelfCheck{
+		Status:  models.CheckStatusOK,
+		Message: fmt.Sprintf("server is %v from database time", skew),
+		Details: map[string]interface{}{"skew_ms": reading.SkewMs, "threshold_ms": reading.ThresholdMs},
+	}
+
+	switch {
+	case skew.Abs() >= SkewCriticalThreshold:
+		check.Status = models.CheckStatusCritical
+	case reading.Exceeded:
+		check.Status = models.CheckStatusWarn
+	}
+	return check
+}
+
+// checkTLSCertificate reports how long the configured TLS certificate remains valid
+func (c *Checker) checkTLSCertificate(ctx context.Context) models.SelfCheck {
+	if !c.cfg.HasTLS() {
+		return models.SelfCheck{Status: models.CheckStatusSkipped, Message: "TLS not configured"}
+	}
+
+	notAfter, err := certificateExpiry(c.cfg.TLSCertFile)
+	if err != nil {
+		return models.SelfCheck{Status: models.CheckStatusCritical, Message: err.Error()}
+	}
+
+	remaining := notAfter.Sub(c.now())
+	check := models.SelfCheck{
+		Status:  models.CheckStatusOK,
+		Message: fmt.Sprintf("certificate valid for %d more day(s)", int(remaining.Hours()/24)),
+		Details: map[string]interface{}{"not_after": notAfter.UTC()},
+	}
+
+	switch {
+	case remaining <= 0:
+		check.Status = models.CheckStatusCritical
+		check.Message = "certificate expired"
+	case remaining < CertWarnWindow:
+		check.Status = models.CheckStatusWarn
+	}
+	return check
+}
+
+// certificateExpiry returns the expiry of the leaf certificate in a PEM file
+func certificateExpiry(path string) (time.Time, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read certificate: %w", err)
+	}
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return time.Time{}, fmt.Errorf("no certificate found in %s", path)
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to parse certificate: %w", err)
+		}
+		return cert.NotAfter, nil
+	}
+}
@@ -0,0 +1,270 @@
+package selfcheck
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"rawboard/internal/config"
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/logging"
+	"rawboard/internal/models"
+)
+
+// testConfig returns a valid development configuration
+func testConfig() *config.Config {
+	return &config.Config{
+		Port:                     "8080",
+		ShutdownTimeout:          30 * time.Second,
+		Environment:              "development",
+		LogLevel:                 "info",
+		LogFormat:                "text",
+		DatabaseTimeout:          time.Second,
+		DatabaseTopologyInterval: 5 * time.Second,
+		ReadinessTimeout:         time.Second,
+		APIKey:                   "test-key",
+		AdminSessionTTL:          12 * time.Hour,
+		MaxScoreEntries:          10,
+		MaxScoreValue:            999999999,
+		MaxGameIDLength:          50,
+		RetentionInterval:        time.Hour,
+		ReceiptLookupRate:        1,
+		ReceiptLookupBurst:       5,
+		ClockSkewThreshold:       2 * time.Second,
+		ClockSkewInterval:        5 * time.Minute,
+		StreamBufferSize:         16,
+		StreamSlowClientTimeout:  30 * time.Second,
+		StreamTokenTTL:           5 * time.Minute,
+		DisplayOfflineAfter:      2 * time.Minute,
+	}
+}
+
+// findCheck returns the named check from a report
+func findCheck(t *testing.T, report *models.SelfCheckReport, name string) models.SelfCheck {
+	t.Helper()
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("Report has no %s check", name)
+	return models.SelfCheck{}
+}
+
+// writeCertificate writes a self-signed PEM certificate expiring at notAfter
+func writeCertificate(t *testing.T, notAfter time.Time) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rawboard.test"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	return path
+}
+
+func TestSelfCheck(t *testing.T) {
+	ctx := context.Background()
+	newChecker := func(cfg *config.Config, db *database.Fake) *Checker {
+		return NewChecker(cfg, db, leaderboard.NewService(db, leaderboard.WithLogger(logging.Discard())), logging.Discard())
+	}
+
+	t.Run("healthy deployment reports ok", func(t *testing.T) {
+		checker := newChecker(testConfig(), database.NewFake())
+
+		report := checker.Run(ctx)
+		if report.Status != models.CheckStatusOK {
+			t.Errorf("Expected ok, got %+v", report.Checks)
+		}
+		if findCheck(t, report, "tls_certificate").Status != models.CheckStatusSkipped {
+			t.Error("Expected the TLS check to be skipped without a certificate")
+		}
+		if checker.Last() != report {
+			t.Error("Expected the report to be kept as the latest")
+		}
+	})
+
+	t.Run("database outage is critical", func(t *testing.T) {
+		db := database.NewFake()
+		db.Fail(database.OpPing, fmt.Errorf("connection refused"))
+
+		report := newChecker(testConfig(), db).Run(ctx)
+		if report.Status != models.CheckStatusCritical || findCheck(t, report, "database").Status != models.CheckStatusCritical {
+			t.Errorf("Expected a critical database check, got %+v", report.Checks)
+		}
+	})
+
+	t.Run("clock skew is graded by threshold", func(t *testing.T) {
+		tests := []struct {
+			offset time.Duration
+			status string
+		}{
+			{0, models.CheckStatusOK},
+			{-5 * time.Second, models.CheckStatusWarn},
+			{time.Minute, models.CheckStatusCritical},
+		}
+
+		for _, tt := range tests {
+			db := database.NewFake()
+			db.SetClockOffset(tt.offset)
+
+			check := findCheck(t, newChecker(testConfig(), db).Run(ctx), "clock_skew")
+			if check.Status != tt.status {
+				t.Errorf("Database offset %v: expected %s, got %s (%s)", tt.offset, tt.status, check.Status, check.Message)
+			}
+		}
+	})
+
+	t.Run("certificate expiry is graded by remaining validity", func(t *testing.T) {
+		tests := []struct {
+			notAfter time.Time
+			status   string
+		}{
+			{time.Now().Add(90 * 24 * time.Hour), models.CheckStatusOK},
+			{time.Now().Add(3 * 24 * time.Hour), models.CheckStatusWarn},
+			{time.Now().Add(-time.Hour), models.CheckStatusCritical},
+		}
+
+		for _, tt := range tests {
+			cfg := testConfig()
+			cfg.TLSCertFile = writeCertificate(t, tt.notAfter)
+			cfg.TLSKeyFile = cfg.TLSCertFile
+
+			check := findCheck(t, newChecker(cfg, database.NewFake()).Run(ctx), "tls_certificate")
+			if check.Status != tt.status {
+				t.Errorf("Expiry %v: expected %s, got %s (%s)", tt.notAfter, tt.status, check.Status, check.Message)
+			}
+		}
+	})
+
+	t.Run("missing API key warns in development and is critical in production", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.APIKey = ""
+		if status := findCheck(t, newChecker(cfg, database.NewFake()).Run(ctx), "config").Status; status != models.CheckStatusWarn {
+			t.Errorf("Expected warn in development, got %s", status)
+		}
+
+		cfg.Environment = "production"
+		if status := findCheck(t, newChecker(cfg, database.NewFake()).Run(ctx), "config").Status; status != models.CheckStatusCritical {
+			t.Errorf("Expected critical in production, got %s", status)
+		}
+	})
+}
+
+func TestSkewMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	db := database.NewFake()
+	monitor := NewSkewMonitor(db, 2*time.Second, logging.Discard())
+	if monitor.Last() != nil {
+		t.Fatal("Expected no reading before the first measurement")
+	}
+
+	if err := monitor.Run(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if reading := monitor.Last(); reading == nil || reading.Exceeded {
+		t.Errorf("Expected an in-threshold reading, got %+v", reading)
+	}
+
+	db.SetClockOffset(-10 * time.Second)
+	if err := monitor.Run(ctx); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	reading := monitor.Last()
+	if !reading.Exceeded || reading.SkewMs < 9000 {
+		t.Errorf("Expected the server to read about 10s ahead, got %+v", reading)
+	}
+}
+
+func TestProbe(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+	hung := Dependency{Name: "cache", Ping: func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+
+	report := Probe(ctx, time.Second, []Dependency{{Name: "database", Ping: db.Ping}})
+	if report.Status != models.ReadinessReady || report.Dependencies[0].Status != models.DependencyUp {
+		t.Errorf("Expected ready, got %+v", report)
+	}
+
+	db.Fail(database.OpPing, fmt.Errorf("connection refused"))
+	start := time.Now()
+	report = Probe(ctx, 50*time.Millisecond, []Dependency{{Name: "database", Ping: db.Ping}, hung})
+	if report.Status != models.ReadinessNotReady {
+		t.Errorf("Expected not ready, got %+v", report)
+	}
+	for _, dependency := range report.Dependencies {
+		if dependency.Status != models.DependencyDown || dependency.Error == "" {
+			t.Errorf("Expected %s to be down with an error, got %+v", dependency.Name, dependency)
+		}
+	}
+	if report.Dependencies[1].Name != "cache" || report.Dependencies[1].LatencyMs < 50 {
+		t.Errorf("Expected the hung dependency to time out, got %+v", report.Dependencies[1])
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the probe to be bounded by its timeout, took %v", elapsed)
+	}
+}
+
+func TestDrain(t *testing.T) {
+	var drain Drain
+	if status := drain.Status(); status.Draining || drain.Remaining(time.Second) != time.Second {
+		t.Errorf("Expected a new instance to be in service, got %+v", status)
+	}
+
+	started := drain.Start("kernel upgrade")
+	if !started.Draining || started.Since == nil || started.Reason != "kernel upgrade" {
+		t.Errorf("Expected draining with its reason, got %+v", started)
+	}
+	// Shutting down a drained instance keeps when draining started
+	again := drain.Start("reboot")
+	if !again.Since.Equal(*started.Since) || again.Reason != "reboot" {
+		t.Errorf("Expected the original start time, got %+v", again)
+	}
+	if remaining := drain.Remaining(time.Hour); remaining <= 0 || remaining > time.Hour {
+		t.Errorf("Expected part of the delay left, got %v", remaining)
+	}
+	if remaining := drain.Remaining(0); remaining != 0 {
+		t.Errorf("Expected no wait without a delay, got %v", remaining)
+	}
+
+	if status, err := drain.Stop(); err != nil || status.Draining || status.Since != nil {
+		t.Errorf("Expected the instance back in service, got %+v, %v", status, err)
+	}
+
+	if status := drain.Shutdown(); !status.Draining || status.Reason != DrainReasonShutdown {
+		t.Errorf("Expected draining to shut down, got %+v", status)
+	}
+	if _, err := drain.Stop(); !errors.Is(err, ErrShuttingDown) {
+		t.Errorf("Expected ErrShuttingDown, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package selfcheck
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+)
+
+// SkewMonitor periodically compares the server clock with the database clock.
+// Score timestamps drive tie-breaking, period bucketing and achievements, so a
+// drifting clock silently corrupts rankings.
+type SkewMonitor struct {
+	clock     database.Clock
+	threshold time.Duration
+	logger    *slog.Logger
+
+	mu   sync.Mutex
+	last *models.ClockSkewReading
+}
+
+// NewSkewMonitor creates a monitor that warns when skew reaches threshold
+func NewSkewMonitor(clock database.Clock, threshold time.Duration, logger *slog.Logger) *SkewMonitor {
+	return &SkewMonitor{
+		clock:     clock,
+		threshold: threshold,
+		logger:    logger,
+	}
+}
+
+// Measure reads the database clock and records the result as the latest reading
+func (m *SkewMonitor) Measure(ctx context.Context) (*models.ClockSkewReading, error) {
+	skew, err := database.MeasureSkew(ctx, m.clock)
+	if err != nil {
+		return nil, err
+	}
+
+	reading := &models.ClockSkewReading{
+		SkewMs:      skew.Milliseconds(),
+		ThresholdMs: m.threshold.Milliseconds(),
+		Exceeded:    skew.Abs() >= m.threshold,
+		MeasuredAt:  time.Now().UTC(),
+	}
+
+	m.mu.Lock()
+	m.last = reading
+	m.mu.Unlock()
+
+	return reading, nil
+}
+
+// Run measures skew once and warns if it exceeds the threshold; use it as a scheduled job
+func (m *SkewMonitor) Run(ctx context.Context) error {
+	reading, err := m.Measure(ctx)
+	if err != nil {
+		return err
+	}
+
+	if reading.Exceeded {
+		m.logger.Warn("clock skew between server and database exceeds threshold",
+			"skew_ms", reading.SkewMs, "threshold_ms", reading.ThresholdMs)
+	}
+	return nil
+}
+
+// Last returns the most recent reading, or nil if none has been taken
+func (m *SkewMonitor) Last() *models.ClockSkewReading {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
@@ -0,0 +1,114 @@
+// Package sharecard renders the preview image shown when a player shares a score in a
+// chat app: the game, initials and score drawn in an arcade pixel font
+package sharecard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"unicode"
+)
+
+// Card size, the 1.91:1 ratio OpenGraph and Twitter previews display uncropped
+const (
+	Width  = 1200
+	Height = 630
+)
+
+// margin keeps text clear of the card's border
+const margin = 80
+
+// Palette indexes
+const (
+	background uint8 = iota
+	border
+	titleColor
+	initialsColor
+	scoreColor
+	footerColor
+)
+
+var palette = color.Palette{
+	background:    color.RGBA{0x10, 0x10, 0x1a, 0xff},
+	border:        color.RGBA{0xff, 0x2e, 0x88, 0xff},
+	titleColor:    color.RGBA{0x3f, 0xd7, 0xff, 0xff},
+	initialsColor: color.RGBA{0xff, 0xd2, 0x3f, 0xff},
+	scoreColor:    color.RGBA{0xff, 0xff, 0xff, 0xff},
+	footerColor:   color.RGBA{0x9a, 0x9a, 0xb0, 0xff},
+}
+
+// Card is the text of a share card. Text is drawn in capitals; characters the font
+// lacks are drawn as question marks.
+type Card struct {
+	Title    string // The game
+	Initials string
+	Score    string // Formatted for display
+	Footer   string // The score's standing, e.g. RANK #3 OF 350
+}
+
+// Render draws a card as a PNG
+func Render(card Card) ([]byte, error) {
+	img := image.NewPaletted(image.Rect(0, 0, Width, Height), palette)
+	fill(img, image.Rect(0, 0, Width, Height), border)
+	fill(img, image.Rect(16, 16, Width-16, Height-16), background)
+
+	drawLine(img, card.Title, 60, 6, titleColor)
+	drawLine(img, card.Initials, 150, 22, initialsColor)
+	drawLine(img, card.Score, 340, 12, scoreColor)
+	drawLine(img, card.Footer, 480, 6, footerColor)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode share card: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine centers text horizontally with its top at y, at scale screen pixels per font
+// pixel or smaller if that's too wide for the card
+func drawLine(img *image.Paletted, text string, y, scale int, index uint8) {
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return
+	}
+	if fit := (Width - 2*margin) / (len(runes)*glyphAdvance - 1); fit < scale {
+		scale = max(fit, 1)
+	}
+
+	x := (Width - (len(runes)*glyphAdvance-1)*scale) / 2
+	for _, r := range runes {
+		drawGlyph(img, glyph(r), x, y, scale, index)
+		x += glyphAdvance * scale
+	}
+}
+
+// glyph returns the font's glyph for a rune
+func glyph(r rune) [glyphHeight]uint8 {
+	if g, ok := glyphs[unicode.ToUpper(r)]; ok {
+		return g
+	}
+	return glyphs['?']
+}
+
+func drawGlyph(img *image.Paletted, g [glyphHeight]uint8, x, y, scale int, index uint8) {
+	for row, bits := range g {
+		for col := 0; col < glyphWidth; col++ {
+			if bits&(1<<(glyphWidth-1-col)) == 0 {
+				continue
+			}
+			px, py := x+col*scale, y+row*scale
+			fill(img, image.Rect(px, py, px+scale, py+scale), index)
+		}
+	}
+}
+
+func fill(img *image.Paletted, r image.Rectangle, index uint8) {
+	r = r.Intersect(img.Rect)
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetColorIndex(x, y, index)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package sharecard
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	data, err := Render(Card{Title: "pacman", Initials: "AAA", Score: "12,500", Footer: "RANK #3 OF 350"})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a PNG, got %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() != Width || bounds.Dy() != Height {
+		t.Errorf("Expected a %dx%d card, got %v", Width, Height, bounds)
+	}
+
+	// The initials are drawn in their own color across the middle of the card
+	if !hasColor(img, image.Rect(0, 150, Width, 150+glyphHeight*22), palette[initialsColor]) {
+		t.Errorf("Expected the initials to be drawn")
+	}
+}
+
+func TestDrawLineFitsLongText(t *testing.T) {
+	img := image.NewPaletted(image.Rect(0, 0, Width, Height), palette)
+	drawLine(img, strings.Repeat("W", 100), 0, 12, scoreColor)
+
+	for y := 0; y < Height; y++ {
+		if img.ColorIndexAt(0, y) != background || img.ColorIndexAt(Width-1, y) != background {
+			t.Fatalf("Expected long text to be shrunk inside the margins")
+		}
+	}
+	if !hasColor(img, img.Rect, palette[scoreColor]) {
+		t.Errorf("Expected the text to be drawn")
+	}
+}
+
+func TestGlyph(t *testing.T) {
+	if glyph('a') != glyphs['A'] {
+		t.Errorf("Expected lowercase letters to be drawn as capitals")
+	}
+	if glyph('é') != glyphs['?'] {
+		t.Errorf("Expected missing characters to be drawn as question marks")
+	}
+}
+
+func hasColor(img image.Image, r image.Rectangle, want interface{ RGBA() (r, g, b, a uint32) }) bool {
+	wr, wg, wb, _ := want.RGBA()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			if r == wr && g == wg && b == wb {
+				return true
+			}
+		}
+	}
+	return false
+}
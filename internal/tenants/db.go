@@ -0,0 +1,158 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"rawboard/internal/database"
+)
+
+// DB stores each tenant's keys under its own prefix, leaving the default namespace
+// unprefixed so existing single-tenant data stays where it is. It offers the optional
+// capabilities tenant-scoped services rely on, failing with errors.ErrUnsupported when
+// the wrapped database lacks them.
+//
+// Pub/sub channels are shared by every tenant: messages must name their tenant.
+type DB struct {
+	db database.DB
+}
+
+// NewDB wraps db with per-tenant key prefixes
+func NewDB(db database.DB) *DB {
+	return &DB{db: db}
+}
+
+// Set stores value under the tenant's key
+func (d *DB) Set(ctx context.Context, key string, value interface{}) error {
+	return d.db.Set(ctx, Key(ctx, key), value)
+}
+
+// SetNX writes the tenant's key if it's unset
+func (d *DB) SetNX(ctx context.Context, key string, value interface{}) (bool, error) {
+	return d.db.SetNX(ctx, Key(ctx, key), value)
+}
+
+// Get reads the tenant's key
+func (d *DB) Get(ctx context.Context, key string) (string, error) {
+	return d.db.Get(ctx, Key(ctx, key))
+}
+
+// Ping checks the wrapped database
+func (d *DB) Ping(ctx context.Context) error {
+	return d.db.Ping(ctx)
+}
+
+// Close closes the wrapped database
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Incr increments the tenant's counter
+func (d *DB) Incr(ctx context.Context, key string) (int64, error) {
+	counters, ok := d.db.(database.Counters)
+	if !ok {
+		return 0, unsupported("counters")
+	}
+	return counters.Incr(ctx, Key(ctx, key))
+}
+
+// ZAdd adds members to the tenant's sorted set
+func (d *DB) ZAdd(ctx context.Context, key string, members ...database.ZMember) error {
+	sets, ok := d.db.(database.SortedSets)
+	if !ok {
+		return unsupported("sorted sets")
+	}
+	return sets.ZAdd(ctx, Key(ctx, key), members...)
+}
+
+// ZRangeByScore reads members of the tenant's sorted set
+func (d *DB) ZRangeByScore(ctx context.Context, key string, query database.ZRange) ([]string, error) {
+	sets, ok := d.db.(database.SortedSets)
+	if !ok {
+		return nil, unsupported("sorted sets")
+	}
+	return sets.ZRangeByScore(ctx, Key(ctx, key), query)
+}
+
+// ZCard counts the members of the tenant's sorted set
+func (d *DB) ZCard(ctx context.Context, key string) (int64, error) {
+	sets, ok := d.db.(database.SortedSets)
+	if !ok {
+		return 0, unsupported("sorted sets")
+	}
+	return sets.ZCard(ctx, Key(ctx, key))
+}
+
+// Del deletes the tenant's key
+func (d *DB) Del(ctx context.Context, key string) error {
+	sets, ok := d.db.(database.SortedSets)
+	if !ok {
+		return unsupported("sorted sets")
+	}
+	return sets.Del(ctx, Key(ctx, key))
+}
+
+// ScanKeys lists the tenant's keys starting with prefix, without the tenant's prefix.
+// In the default namespace, other tenants' keys are left out.
+func (d *DB) ScanKeys(ctx context.Context, prefix string) ([]string, error) {
+	scanner, ok := d.db.(database.Scanner)
+	if !ok {
+		return nil, unsupported("key scans")
+	}
+	keys, err := scanner.ScanKeys(ctx, Key(ctx, prefix))
+	if err != nil {
+		return nil, err
+	}
+	namespace := Key(ctx, "")
+	own := keys[:0]
+	for _, key := range keys {
+		if namespace == "" && strings.HasPrefix(key, keyPrefix) {
+			continue
+		}
+		own = append(own, strings.TrimPrefix(key, namespace))
+	}
+	return own, nil
+}
+
+// XAdd appends to the tenant's stream
+func (d *DB) XAdd(ctx context.Context, key string, maxLen int64, fields map[string]string) (string, error) {
+	streams, ok := d.db.(database.Streams)
+	if !ok {
+		return "", unsupported("streams")
+	}
+	return streams.XAdd(ctx, Key(ctx, key), maxLen, fields)
+}
+
+// XRevRange reads the tenant's stream
+func (d *DB) XRevRange(ctx context.Context, key, end string, count int64) ([]database.StreamEntry, error) {
+	streams, ok := d.db.(database.Streams)
+	if !ok {
+		return nil, unsupported("streams")
+	}
+	return streams.XRevRange(ctx, Key(ctx, key), end, count)
+}
+
+// Publish sends message on the deployment-wide channel
+func (d *DB) Publish(ctx context.Context, channel, message string) error {
+	pubsub, ok := d.db.(database.PubSub)
+	if !ok {
+		return unsupported("pub/sub")
+	}
+	return pubsub.Publish(ctx, channel, message)
+}
+
+// Subscribe listens on the deployment-wide channel
+func (d *DB) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	pubsub, ok := d.db.(database.PubSub)
+	if !ok {
+		return nil, unsupported("pub/sub")
+	}
+	return pubsub.Subscribe(ctx, channel)
+}
+
+// unsupported reports a capability the wrapped database lacks
+func unsupported(capability string) error {
+	return fmt.Errorf("database does not support %s: %w", capability, errors.ErrUnsupported)
+}
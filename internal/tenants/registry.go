@@ -0,0 +1,127 @@
+package tenants
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// registryKey is the database key listing every tenant, outside any tenant's namespace
+const registryKey = "tenants"
+
+// registry is the stored list of tenants
+type registry struct {
+	Tenants map[string]time.Time `json:"tenants"` // First seen
+}
+
+// Registry remembers which tenants exist so background jobs can visit each of them
+type Registry struct {
+	db    database.DB
+	mu    sync.Mutex
+	known map[string]bool // Tenants this instance has already registered
+}
+
+// NewRegistry creates a tenant registry stored in db, which must not be a tenant DB
+func NewRegistry(db database.DB) *Registry {
+	return &Registry{db: db, known: make(map[string]bool)}
+}
+
+// Register records tenant, doing nothing for the default namespace or a tenant this
+// instance has registered before
+func (r *Registry) Register(ctx context.Context, tenant string) error {
+	if tenant == "" {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.known[tenant] {
+		return nil
+	}
+
+	stored, err := r.load(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := stored.Tenants[tenant]; !ok {
+		stored.Tenants[tenant] = time.Now().UTC()
+		data, err := json.Marshal(stored)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", registryKey, err)
+		}
+		if err := r.db.Set(ctx, registryKey, string(data)); err != nil {
+			return fmt.Errorf("failed to save %s: %w", registryKey, err)
+		}
+	}
+	r.known[tenant] = true
+	return nil
+}
+
+// Forget makes this instance register tenants again, for databases that were wiped
+func (r *Registry) Forget() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.known = make(map[string]bool)
+}
+
+// List returns every registered tenant in ID order
+func (r *Registry) List(ctx context.Context) ([]string, error) {
+	stored, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]string, 0, len(stored.Tenants))
+	for tenant := range stored.Tenants {
+		list = append(list, tenant)
+	}
+	sort.Strings(list)
+	return list, nil
+}
+
+// Each wraps a background job to run for the default namespace and then every
+// registered tenant. A tenant's failure doesn't stop the others; their errors are joined.
+func (r *Registry) Each(run func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		errs := []error{run(WithTenant(ctx, ""))}
+
+		list, err := r.List(ctx)
+		if err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		for _, tenant := range list {
+			if ctx.Err() != nil {
+				break
+			}
+			if err := run(WithTenant(ctx, tenant)); err != nil {
+				errs = append(errs, fmt.Errorf("tenant %s: %w", tenant, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// load reads the stored registry
+func (r *Registry) load(ctx context.Context) (*registry, error) {
+	stored := &registry{}
+	value, err := r.db.Get(ctx, registryKey)
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	if err == nil {
+		if err := json.Unmarshal([]byte(value), stored); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", registryKey, err)
+		}
+	}
+	if stored.Tenants == nil {
+		stored.Tenants = map[string]time.Time{}
+	}
+	return stored, nil
+}
@@ -0,0 +1,53 @@
+// Package tenants lets one deployment host several studios. The tenant a request acts
+// for travels on its context, and DB keeps each tenant's data under its own key prefix,
+// so services written for a single namespace are isolated without knowing about tenants.
+package tenants
+
+import (
+	"context"
+	"errors"
+)
+
+// MaxIDLength bounds tenant IDs
+const MaxIDLength = 50
+
+// keyPrefix starts every database key belonging to a tenant
+const keyPrefix = "tenant:"
+
+// ErrInvalidID is returned for tenant IDs that can't be used in keys and paths
+var ErrInvalidID = errors.New("tenant IDs must be 1 to 50 lowercase letters, digits, hyphens or underscores")
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx acting for tenant; "" is the default namespace,
+// which holds the data of deployments that don't use tenants
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// FromContext returns the tenant ctx acts for, "" for the default namespace
+func FromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+// Validate checks a tenant ID
+func Validate(tenant string) error {
+	if len(tenant) < 1 || len(tenant) > MaxIDLength {
+		return ErrInvalidID
+	}
+	for _, r := range tenant {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return ErrInvalidID
+		}
+	}
+	return nil
+}
+
+// Key returns where key is stored for the tenant ctx acts for
+func Key(ctx context.Context, key string) string {
+	if tenant := FromContext(ctx); tenant != "" {
+		return keyPrefix + tenant + ":" + key
+	}
+	return key
+}
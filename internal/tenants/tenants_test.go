@@ -0,0 +1,97 @@
+package tenants
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"rawboard/internal/database"
+)
+
+func TestDBIsolatesTenants(t *testing.T) {
+	fake := database.NewFake()
+	db := NewDB(fake)
+	acme := WithTenant(context.Background(), "acme")
+	globex := WithTenant(context.Background(), "globex")
+
+	if err := db.Set(context.Background(), "games", "default"); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+	if err := db.Set(acme, "games", "acme"); err != nil {
+		t.Fatalf("Failed to set key: %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		want string
+	}{
+		"default": {context.Background(), "default"},
+		"acme":    {acme, "acme"},
+	} {
+		value, err := db.Get(tc.ctx, "games")
+		if err != nil || value != tc.want {
+			t.Errorf("%s: expected %q, got %q (%v)", name, tc.want, value, err)
+		}
+	}
+	if _, err := db.Get(globex, "games"); err == nil {
+		t.Error("Expected another tenant's key to be missing")
+	}
+	if keys := fake.Keys("tenant:acme:"); len(keys) != 1 || keys[0] != "tenant:acme:games" {
+		t.Errorf("Expected acme's key under its prefix, got %v", keys)
+	}
+
+	if _, err := db.Incr(acme, "counter"); err != nil {
+		t.Fatalf("Failed to increment: %v", err)
+	}
+	if n, _ := db.Incr(globex, "counter"); n != 1 {
+		t.Errorf("Expected globex's counter to start at 1, got %d", n)
+	}
+
+	if keys, err := db.ScanKeys(acme, "gam"); err != nil || len(keys) != 1 || keys[0] != "games" {
+		t.Errorf("Expected acme's games key without its prefix, got %v (%v)", keys, err)
+	}
+	if keys, _ := db.ScanKeys(context.Background(), ""); len(keys) != 1 || keys[0] != "games" {
+		t.Errorf("Expected only the default namespace's keys, got %v", keys)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tenant := range []string{"acme", "studio-9", "big_co"} {
+		if err := Validate(tenant); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", tenant, err)
+		}
+	}
+	for _, tenant := range []string{"", "Acme", "a:b", "a/b", string(make([]byte, MaxIDLength+1))} {
+		if err := Validate(tenant); !errors.Is(err, ErrInvalidID) {
+			t.Errorf("Expected %q to be invalid, got %v", tenant, err)
+		}
+	}
+}
+
+func TestRegistryEach(t *testing.T) {
+	ctx := context.Background()
+	registry := NewRegistry(database.NewFake())
+	for _, tenant := range []string{"globex", "acme", "acme", ""} {
+		if err := registry.Register(ctx, tenant); err != nil {
+			t.Fatalf("Failed to register %q: %v", tenant, err)
+		}
+	}
+
+	var visited []string
+	failing := errors.New("boom")
+	err := registry.Each(func(ctx context.Context) error {
+		tenant := FromContext(ctx)
+		visited = append(visited, tenant)
+		if tenant == "acme" {
+			return failing
+		}
+		return nil
+	})(ctx)
+
+	if len(visited) != 3 || visited[0] != "" || visited[1] != "acme" || visited[2] != "globex" {
+		t.Errorf("Expected the default namespace then acme and globex, got %q", visited)
+	}
+	if !errors.Is(err, failing) {
+		t.Errorf("Expected acme's error to be returned, got %v", err)
+	}
+}
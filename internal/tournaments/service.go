@@ -0,0 +1,172 @@
+// Package tournaments runs competitions across several games within a time window,
+// ranking players on their scores from the member games' histories
+package tournaments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tournamentsKey is the database key holding every tournament
+const tournamentsKey = "tournaments"
+
+// Service errors
+var (
+	ErrNotFound       = errors.New("tournament not found")
+	ErrScoringDiffers = errors.New("a tournament's games must use the same decimals")
+)
+
+// Games reads the member games of a tournament; *leaderboard.Service implements it
+type Games interface {
+	GetGame(ctx context.Context, gameID string) (*models.GameInfo, error)
+	GetAllScoresForGame(ctx context.Context, gameID string) (*models.AllScoresRecord, error)
+}
+
+// Service manages tournaments and computes their standings
+type Service struct {
+	db    database.DB
+	games Games
+	mu    sync.Mutex
+}
+
+// NewService creates a new tournament service
+func NewService(db database.DB, games Games) *Service {
+	return &Service{db: db, games: games}
+}
+
+// List returns every tournament, soonest start first
+func (s *Service) List(ctx context.Context) ([]models.Tournament, error) {
+	tournaments, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Tournament, 0, len(tournaments))
+	for _, tournament := range tournaments {
+		list = append(list, tournament)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].StartsAt.Equal(list[j].StartsAt) {
+			return list[i].StartsAt.Before(list[j].StartsAt)
+		}
+		return list[i].TournamentID < list[j].TournamentID
+	})
+	return list, nil
+}
+
+// Get returns a tournament
+func (s *Service) Get(ctx context.Context, tournamentID string) (*models.Tournament, error) {
+	tournaments, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tournament, ok := tournaments[tournamentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &tournament, nil
+}
+
+// Put validates and stores a tournament, replacing any with its ID. Registered member
+// games must share their decimals so their scores can be added up.
+func (s *Service) Put(ctx context.Context, tournament models.Tournament) (*models.Tournament, error) {
+	if err := tournament.Validate(); err != nil {
+		return nil, err
+	}
+	if _, err := s.scoring(ctx, &tournament); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tournaments, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tournament.Updated = time.Now().UTC()
+	tournaments[tournament.TournamentID] = tournament
+	if err := s.saveJSON(ctx, tournamentsKey, tournaments); err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// Delete removes a tournament, returning it. Scores are untouched.
+func (s *Service) Delete(ctx context.Context, tournamentID string) (*models.Tournament, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tournaments, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tournament, ok := tournaments[tournamentID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(tournaments, tournamentID)
+	if err := s.saveJSON(ctx, tournamentsKey, tournaments); err != nil {
+		return nil, err
+	}
+	return &tournament, nil
+}
+
+// scoring returns the scoring settings the tournament's registered games share, nil
+// when none are registered or they keep whole scores
+func (s *Service) scoring(ctx context.Context, tournament *models.Tournament) (*models.ScoringSettings, error) {
+	var shared *models.ScoringSettings
+	first := true
+	for _, gameID := range tournament.GameIDs {
+		game, err := s.games.GetGame(ctx, gameID)
+		if err != nil {
+			continue // Not registered yet; it will start with whole scores
+		}
+		if !first && game.Settings.Scoring.Precision() != shared.Precision() {
+			return nil, fmt.Errorf("%w: %s", ErrScoringDiffers, gameID)
+		}
+		shared, first = game.Settings.Scoring, false
+	}
+	return shared, nil
+}
+
+// load reads every tournament, keyed by ID
+func (s *Service) load(ctx context.Context) (map[string]models.Tournament, error) {
+	tournaments := map[string]models.Tournament{}
+	value, err := s.db.Get(ctx, tournamentsKey)
+	if errors.Is(err, redis.Nil) {
+		return tournaments, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(value), &tournaments); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", tournamentsKey, err)
+	}
+	if tournaments == nil {
+		tournaments = map[string]models.Tournament{}
+	}
+	return tournaments, nil
+}
+
+// saveJSON writes v to key
+func (s *Service) saveJSON(ctx context.Context, key string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", key, err)
+	}
+	if err := s.db.Set(ctx, key, string(data)); err != nil {
+		return fmt.Errorf("failed to save %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package tournaments
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+func TestStandings(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+	games := leaderboard.NewService(db)
+	service := NewService(db, games)
+
+	submit := func(gameID, initials string, score int64) {
+		t.Helper()
+		if err := games.SubmitScore(ctx, gameID, initials, score); err != nil {
+			t.Fatalf("Failed to submit score: %v", err)
+		}
+	}
+	submit("pacman", "AAA", 100)
+	submit("pacman", "AAA", 300)
+	submit("galaga", "AAA", 200)
+	submit("galaga", "BBB", 500)
+	submit("tetris", "CCC", 9000) // Not a member game
+
+	now := time.Now()
+	tournament, err := service.Put(ctx, models.Tournament{
+		TournamentID: "cup",
+		GameIDs:      []string{"pacman", "galaga"},
+		StartsAt:     now.Add(-time.Hour),
+		EndsAt:       now.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("Failed to store tournament: %v", err)
+	}
+	if tournament.Scoring != models.TournamentTotal {
+		t.Errorf("Expected total scoring by default, got %q", tournament.Scoring)
+	}
+
+	standings, err := service.Standings(ctx, "cup", 0, now)
+	if err != nil {
+		t.Fatalf("Failed to compute standings: %v", err)
+	}
+	if standings.Status != models.TournamentLive || standings.Players != 2 {
+		t.Fatalf("Expected a live tournament with 2 players, got %+v", standings)
+	}
+	first, second := standings.Standings[0], standings.Standings[1]
+	if first.Initials != "AAA" || first.Score != 500 || first.Games["pacman"] != 300 {
+		t.Errorf("Expected AAA first on 300 + 200, got %+v", first)
+	}
+	if second.Initials != "BBB" || second.Score != 500 || second.Rank != 1 {
+		t.Errorf("Expected BBB to share first place on 500, got %+v", second)
+	}
+
+	tournament.Scoring = models.TournamentBest
+	if _, err := service.Put(ctx, *tournament); err != nil {
+		t.Fatalf("Failed to update tournament: %v", err)
+	}
+	standings, err = service.Standings(ctx, "cup", 1, now)
+	if err != nil {
+		t.Fatalf("Failed to compute standings: %v", err)
+	}
+	if len(standings.Standings) != 1 || standings.Standings[0].Initials != "BBB" || standings.Players != 2 {
+		t.Errorf("Expected BBB alone on top by best score, got %+v", standings)
+	}
+
+	// Scores outside the window don't count
+	tournament.StartsAt, tournament.EndsAt = now.Add(time.Hour), now.Add(2*time.Hour)
+	if _, err := service.Put(ctx, *tournament); err != nil {
+		t.Fatalf("Failed to update tournament: %v", err)
+	}
+	standings, err = service.Standings(ctx, "cup", 0, now)
+	if err != nil {
+		t.Fatalf("Failed to compute standings: %v", err)
+	}
+	if standings.Status != models.TournamentUpcoming || len(standings.Standings) != 0 {
+		t.Errorf("Expected an upcoming tournament without standings, got %+v", standings)
+	}
+
+	if _, err := service.Delete(ctx, "cup"); err != nil {
+		t.Fatalf("Failed to delete tournament: %v", err)
+	}
+	if _, err := service.Standings(ctx, "cup", 0, now); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after deletion, got %v", err)
+	}
+}
+
+func TestPutRejectsMixedDecimals(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewFake()
+	games := leaderboard.NewService(db)
+	service := NewService(db, games)
+
+	if _, err := games.SetScoring(ctx, "speedrun", models.ScoringSettings{Decimals: 2}); err != nil {
+		t.Fatalf("Failed to set scoring: %v", err)
+	}
+	if err := games.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+		t.Fatalf("Failed to submit score: %v", err)
+	}
+
+	now := time.Now()
+	_, err := service.Put(ctx, models.Tournament{
+		TournamentID: "mixed",
+		GameIDs:      []string{"pacman", "speedrun"},
+		StartsAt:     now,
+		EndsAt:       now.Add(time.Hour),
+	})
+	if !errors.Is(err, ErrScoringDiffers) {
+		t.Errorf("Expected ErrScoringDiffers, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package tournaments
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// Standings ranks the tournament's players at now on their counted scores played in its
+// window. Each player's best score per member game is taken; the tournament's scoring
+// mode then keeps the best of those or adds them up. limit caps the standings returned;
+// 0 returns them all.
+func (s *Service) Standings(ctx context.Context, tournamentID string, limit int, now time.Time) (*models.TournamentStandings, error) {
+	tournament, err := s.Get(ctx, tournamentID)
+	if err != nil {
+		return nil, err
+	}
+	scoring, err := s.scoring(ctx, tournament)
+	if err != nil {
+		return nil, err
+	}
+
+	players := map[string]*models.TournamentStanding{}
+	for _, gameID := range tournament.GameIDs {
+		history, err := s.games.GetAllScoresForGame(ctx, gameID)
+		if err != nil {
+			continue // No scores yet
+		}
+		for _, entry := range history.Scores {
+			if entry.NonCounting || !tournament.Counts(entry.Timestamp) {
+				continue
+			}
+			player, ok := players[entry.Initials]
+			if !ok {
+				player = &models.TournamentStanding{Initials: entry.Initials, Games: map[string]int64{}}
+				players[entry.Initials] = player
+			}
+			if best, played := player.Games[gameID]; !played || entry.Score > best {
+				player.Games[gameID] = entry.Score
+			}
+		}
+	}
+
+	standings := make([]models.TournamentStanding, 0, len(players))
+	for _, player := range players {
+		player.Score = aggregate(tournament.Scoring, player.Games)
+		if scoring.Precision() > 0 {
+			player.DisplayScore = scoring.Format(player.Score)
+		}
+		standings = append(standings, *player)
+	}
+	rank(standings)
+
+	result := &models.TournamentStandings{
+		Tournament: *tournament,
+		Status:     tournament.Status(now),
+		Standings:  standings,
+		Players:    len(standings),
+		Updated:    now,
+	}
+	if limit > 0 && len(result.Standings) > limit {
+		result.Standings = result.Standings[:limit]
+	}
+	return result, nil
+}
+
+// aggregate combines a player's per-game bests by the tournament's scoring mode
+func aggregate(mode string, games map[string]int64) int64 {
+	var score int64
+	first := true
+	for _, best := range games {
+		switch {
+		case mode == models.TournamentBest && (first || best > score):
+			score = best
+		case mode != models.TournamentBest:
+			score += best
+		}
+		first = false
+	}
+	return score
+}
+
+// rank sorts standings highest score first, by initials among equals, and gives equal
+// scores the same rank
+func rank(standings []models.TournamentStanding) {
+	sort.Slice(standings, func(i, j int) bool {
+		if standings[i].Score != standings[j].Score {
+			return standings[i].Score > standings[j].Score
+		}
+		return standings[i].Initials < standings[j].Initials
+	})
+	for i := range standings {
+		standings[i].Rank = i + 1
+		if i > 0 && standings[i].Score == standings[i-1].Score {
+			standings[i].Rank = standings[i-1].Rank
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"rawboard/internal/models"
+)
+
+// ErrInvalidCondition is returned for conditions that don't parse
+var ErrInvalidCondition = errors.New("invalid webhook condition")
+
+// Condition decides which leaderboard changes a webhook hears about. Conditions read
+// like "any enters top 3" or "player XYZ leaves top 10":
+//
+//	condition = subject movement "top" N
+//	subject   = "any" ["score" | "player"] | "player" INITIALS
+//	movement  = "enters" | "leaves" | "drops out of"
+type Condition struct {
+	Initials string // Empty for any player
+	Leaves   bool   // Fire when players drop out of the top, rather than enter it
+	Top      int
+}
+
+// ParseCondition parses a condition, ignoring case and extra whitespace
+func ParseCondition(text string) (Condition, error) {
+	var cond Condition
+	words := strings.Fields(strings.ToLower(text))
+	invalid := func(reason string) (Condition, error) {
+		return Condition{}, fmt.Errorf("%w %q: %s", ErrInvalidCondition, text, reason)
+	}
+
+	switch {
+	case len(words) >= 1 && words[0] == "any":
+		words = words[1:]
+		if len(words) > 0 && (words[0] == "score" || words[0] == "player") {
+			words = words[1:]
+		}
+	case len(words) >= 2 && words[0] == "player":
+		cond.Initials = models.NormalizeInitials(words[1])
+		if len(cond.Initials) != 3 {
+			return invalid("player initials must be 3 characters")
+		}
+		words = words[2:]
+	default:
+		return invalid(`must start with "any" or "player <initials>"`)
+	}
+
+	switch {
+	case len(words) >= 1 && words[0] == "enters":
+		words = words[1:]
+	case len(words) >= 1 && words[0] == "leaves":
+		cond.Leaves = true
+		words = words[1:]
+	case len(words) >= 3 && words[0] == "drops" && words[1] == "out" && words[2] == "of":
+		cond.Leaves = true
+		words = words[3:]
+	default:
+		return invalid(`expected "enters", "leaves" or "drops out of"`)
+	}
+
+	if len(words) != 2 || words[0] != "top" {
+		return invalid(`expected "top <n>" at the end`)
+	}
+	top, err := strconv.Atoi(words[1])
+	if err != nil || top < 1 || top > models.MaxLeaderboardEntries {
+		return invalid(fmt.Sprintf("top must be between 1 and %d", models.MaxLeaderboardEntries))
+	}
+	cond.Top = top
+	return cond, nil
+}
+
+// String returns the condition in its canonical form
+func (c Condition) String() string {
+	subject := "any"
+	if c.Initials != "" {
+		subject = "player " + c.Initials
+	}
+	movement := "enters"
+	if c.Leaves {
+		movement = "leaves"
+	}
+	return fmt.Sprintf("%s %s top %d", subject, movement, c.Top)
+}
+
+// Match returns the players that crossed the condition's threshold between the previous
+// and current boards. A nil previous board is treated as empty.
+func (c Condition) Match(previous, current *models.Leaderboard) []models.PositionChange {
+	before, after := ranks(previous), ranks(current)
+
+	// Entering players are found on the current board, leaving ones on the previous
+	board, other := current, before
+	if c.Leaves {
+		board, other = previous, after
+	}
+	if board == nil {
+		return nil
+	}
+
+	var changes []models.PositionChange
+	for i, entry := range board.Entries {
+		if i >= c.Top {
+			break
+		}
+		if c.Initials != "" && entry.Initials != c.Initials {
+			continue
+		}
+		if rank := other[entry.Initials]; rank != 0 && rank <= c.Top {
+			continue // Inside the threshold on both boards
+		}
+
+		change := models.PositionChange{
+			Initials:     entry.Initials,
+			Score:        entry.Score,
+			PreviousRank: before[entry.Initials],
+			Rank:         after[entry.Initials],
+		}
+		if rank := after[entry.Initials]; c.Leaves && rank != 0 {
+			change.Score = current.Entries[rank-1].Score
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// ranks maps each player on board to their 1-based position
+func ranks(board *models.Leaderboard) map[string]int {
+	positions := map[string]int{}
+	if board == nil {
+		return positions
+	}
+	for i, entry := range board.Entries {
+		positions[entry.Initials] = i + 1
+	}
+	return positions
+}
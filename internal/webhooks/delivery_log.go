@@ -0,0 +1,107 @@
+package webhooks
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"rawboard/internal/models"
+)
+
+// maxLoggedResponseBody caps how much of a receiver's response the log keeps
+const maxLoggedResponseBody = 4096
+
+// DeliveryLog keeps the most recent delivery attempts in memory, with what was sent and
+// how the receiver answered, so integrators can see exactly what rawboard sent without a
+// public receiver. It is a development aid: entries are lost on restart and aren't
+// shared between replicas.
+type DeliveryLog struct {
+	mu      sync.Mutex
+	size    int
+	entries []models.WebhookDelivery // Oldest first
+}
+
+// NewDeliveryLog creates a log keeping the last size attempts
+func NewDeliveryLog(size int) *DeliveryLog {
+	return &DeliveryLog{size: size}
+}
+
+// Recent returns the tenant's logged attempts, newest first, for one game or every game
+// when gameID is empty. limit caps the attempts returned; 0 returns them all.
+func (l *DeliveryLog) Recent(tenant, gameID string, limit int) []models.WebhookDelivery {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	deliveries := []models.WebhookDelivery{}
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		entry := l.entries[i]
+		if entry.Tenant != tenant || (gameID != "" && entry.GameID != gameID) {
+			continue
+		}
+		deliveries = append(deliveries, entry)
+		if limit > 0 && len(deliveries) == limit {
+			break
+		}
+	}
+	return deliveries
+}
+
+// add logs an attempt, dropping the oldest once the log is full
+func (l *DeliveryLog) add(delivery models.WebhookDelivery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.size <= 0 {
+		return
+	}
+	if len(l.entries) >= l.size {
+		l.entries = append(l.entries[:0:0], l.entries[len(l.entries)-l.size+1:]...)
+	}
+	l.entries = append(l.entries, delivery)
+}
+
+// logHeaders flattens headers for the log, joining repeated values
+func logHeaders(header http.Header) map[string]string {
+	flat := make(map[string]string, len(header))
+	for name, values := range header {
+		flat[name] = strings.Join(values, ", ")
+	}
+	return flat
+}
+
+// logAttempt records a delivery attempt of payload to hook, sent at start, with the
+// response if one arrived
+func (l *DeliveryLog) logAttempt(tenant string, hook record, payload models.WebhookEvent, attempt int, req *http.Request, body []byte, resp *http.Response, respBody []byte, err error, start time.Time) {
+	delivery := models.WebhookDelivery{
+		ID:             payload.ID,
+		WebhookID:      hook.ID,
+		GameID:         hook.GameID,
+		Tenant:         tenant,
+		EventType:      payload.Type,
+		URL:            hook.URL,
+		Attempt:        attempt,
+		Test:           payload.Test,
+		RequestHeaders: logHeaders(req.Header),
+		Delivered:      err == nil,
+		DurationMS:     time.Since(start).Milliseconds(),
+		SentAt:         start.UTC(),
+	}
+	if hook.Format == models.WebhookFormatProtobuf {
+		delivery.Body, delivery.BodyEncoding = base64.StdEncoding.EncodeToString(body), "base64"
+	} else {
+		delivery.Body = string(body)
+	}
+	if resp != nil {
+		delivery.StatusCode = resp.StatusCode
+		delivery.ResponseHeaders = logHeaders(resp.Header)
+		if len(respBody) > maxLoggedResponseBody {
+			respBody, delivery.ResponseTruncated = respBody[:maxLoggedResponseBody], true
+		}
+		delivery.ResponseBody = strings.ToValidUTF8(string(respBody), "\uFFFD")
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+	l.add(delivery)
+}
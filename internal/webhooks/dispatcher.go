@@ -0,0 +1,537 @@
+// Package webhooks notifies integrators' URLs of leaderboard events, such as changes that
+// meet their conditions, new high scores and unlocked achievements
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"rawboard/internal/events"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+	"rawboard/internal/tenants"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Defaults for dispatcher options
+const (
+	DefaultQueueSize       = 256
+	DefaultDeliveryTimeout = 5 * time.Second
+	DefaultRetries         = 3
+	DefaultRetryBackoff    = 2 * time.Second
+)
+
+// userAgent identifies rawboard to webhook receivers
+const userAgent = "rawboard-webhooks/1.0"
+
+// Dispatcher turns leaderboard changes and submissions into webhook events and delivers
+// them. It is a leaderboard publisher and score listener: both only queue, and a separate
+// worker does the lookups and HTTP calls so submissions never wait on them. Failed
+// deliveries are retried with exponential backoff off the worker, then recorded as dead
+// letters.
+type Dispatcher struct {
+	store        *Store
+	client       *http.Client
+	logger       *slog.Logger
+	queueSize    int
+	retries      int
+	retryBackoff time.Duration
+	log          *DeliveryLog // Nil unless delivery attempts are logged
+
+	queue    chan job
+	mu       sync.Mutex
+	stopped  chan struct{}
+	done     chan struct{}
+	retrying sync.WaitGroup
+	ctx      context.Context // Cancelled when Close gives up waiting
+	cancel   context.CancelFunc
+}
+
+// job is one queued leaderboard change or submission
+type job struct {
+	board *models.BoardEvent
+	score *models.ScoreEvent
+}
+
+// Option configures optional Dispatcher behavior
+type Option func(*Dispatcher)
+
+// WithHTTPClient sets the client used for deliveries
+func WithHTTPClient(client *http.Client) Option {
+	return func(d *Dispatcher) {
+		d.client = client
+	}
+}
+
+// WithQueueSize sets how many leaderboard changes may wait for evaluation
+func WithQueueSize(size int) Option {
+	return func(d *Dispatcher) {
+		d.queueSize = size
+	}
+}
+
+// WithLogger sets the logger used to report failed deliveries
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *Dispatcher) {
+		d.logger = logger
+	}
+}
+
+// WithRetries sets how many times a failed delivery is retried before it becomes a dead
+// letter, waiting backoff before the first retry and doubling the wait each time
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(d *Dispatcher) {
+		d.retries = retries
+		d.retryBackoff = backoff
+	}
+}
+
+// WithDeliveryLog records every delivery attempt, including tests, in log
+func WithDeliveryLog(log *DeliveryLog) Option {
+	return func(d *Dispatcher) {
+		d.log = log
+	}
+}
+
+// NewDispatcher creates a dispatcher and starts its worker; call Close to stop it
+func NewDispatcher(store *Store, opts ...Option) *Dispatcher {
+	d := &Dispatcher{
+		store:        store,
+		client:       &http.Client{Timeout: DefaultDeliveryTimeout},
+		logger:       slog.Default(),
+		queueSize:    DefaultQueueSize,
+		retries:      DefaultRetries,
+		retryBackoff: DefaultRetryBackoff,
+		stopped:      make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	d.queue = make(chan job, d.queueSize)
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+
+	go d.run()
+	return d
+}
+
+// Publish queues a leaderboard change for evaluation, dropping it if the queue is full
+func (d *Dispatcher) Publish(event models.BoardEvent) {
+	if event.Type != models.BoardEventUpdated || event.Leaderboard == nil {
+		return
+	}
+	d.enqueue(job{board: &event}, "game_id", event.GameID, "version", event.Version)
+}
+
+// ScoreSubmitted queues a submission that beat the player's high score or unlocked
+// achievements, dropping it if the queue is full
+func (d *Dispatcher) ScoreSubmitted(event models.ScoreEvent) {
+	if !event.NewHighScore && len(event.Achievements) == 0 {
+		return
+	}
+	d.enqueue(job{score: &event}, "game_id", event.GameID, "initials", event.Entry.Initials)
+}
+
+// enqueue queues a job unless the dispatcher is closed or the queue is full
+func (d *Dispatcher) enqueue(j job, logAttrs ...any) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	select {
+	case <-d.stopped:
+		return
+	default:
+	}
+
+	select {
+	case d.queue <- j:
+	default:
+		d.logger.Warn("webhook queue full, dropping event", logAttrs...)
+	}
+}
+
+// Close stops accepting changes and waits for queued ones and pending retries to be
+// delivered, abandoning the rest once ctx expires
+func (d *Dispatcher) Close(ctx context.Context) {
+	d.mu.Lock()
+	select {
+	case <-d.stopped:
+	default:
+		close(d.stopped)
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		<-d.done
+		d.retrying.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		d.logger.Warn("webhook deliveries did not finish in time", "abandoned", len(d.queue))
+		d.cancel()
+		<-finished
+	}
+	d.cancel()
+}
+
+// run evaluates queued jobs until the queue is closed
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for j := range d.queue {
+		if d.ctx.Err() != nil {
+			continue // Abandoned by Close
+		}
+		if j.board != nil {
+			d.dispatchBoard(tenants.WithTenant(d.ctx, j.board.Tenant), *j.board)
+		} else {
+			d.dispatchScore(tenants.WithTenant(d.ctx, j.score.Tenant), *j.score)
+		}
+	}
+}
+
+// dispatchBoard delivers a leaderboard change to the game's webhooks whose condition it
+// meets, and to those watching first place when it changed hands or was beaten
+func (d *Dispatcher) dispatchBoard(ctx context.Context, event models.BoardEvent) {
+	hooks, err := d.store.load(ctx, event.GameID)
+	if err != nil {
+		d.logger.Error("failed to load webhooks", "game_id", event.GameID, "error", err)
+		return
+	}
+	leader, beaten := topScoreChange(event.Previous, event.Leaderboard)
+
+	for _, hook := range hooks {
+		if hook.Subscribes(models.WebhookEventPosition) {
+			d.dispatchPosition(ctx, hook, event)
+		}
+		if leader != nil && hook.Subscribes(models.WebhookEventTopScore) {
+			d.send(ctx, hook, models.WebhookEvent{
+				Type:              models.WebhookEventTopScore,
+				Entry:             leader,
+				PreviousHighScore: beaten,
+				Leaderboard:       event.Leaderboard,
+			})
+		}
+	}
+}
+
+// dispatchPosition delivers a leaderboard change to hook if it meets the condition
+func (d *Dispatcher) dispatchPosition(ctx context.Context, hook record, event models.BoardEvent) {
+	cond, err := ParseCondition(hook.Condition)
+	if err != nil {
+		d.logger.Error("skipping webhook with unparseable condition", "webhook_id", hook.ID, "error", err)
+		return
+	}
+	changes := cond.Match(event.Previous, event.Leaderboard)
+	if len(changes) == 0 {
+		return
+	}
+
+	d.send(ctx, hook, models.WebhookEvent{
+		Type:        models.WebhookEventPosition,
+		Condition:   hook.Condition,
+		Changes:     changes,
+		Leaderboard: event.Leaderboard,
+	})
+}
+
+// dispatchScore delivers a submission's new high score and unlocked achievements to the
+// game's webhooks subscribed to them
+func (d *Dispatcher) dispatchScore(ctx context.Context, event models.ScoreEvent) {
+	hooks, err := d.store.load(ctx, event.GameID)
+	if err != nil {
+		d.logger.Error("failed to load webhooks", "game_id", event.GameID, "error", err)
+		return
+	}
+
+	for _, hook := range hooks {
+		if event.NewHighScore && hook.Subscribes(models.WebhookEventHighScore) {
+			d.send(ctx, hook, models.WebhookEvent{
+				Type:              models.WebhookEventHighScore,
+				Entry:             &event.Entry,
+				PreviousHighScore: event.PreviousHighScore,
+			})
+		}
+		if len(event.Achievements) > 0 && hook.Subscribes(models.WebhookEventAchievement) {
+			d.send(ctx, hook, models.WebhookEvent{
+				Type:         models.WebhookEventAchievement,
+				Entry:        &event.Entry,
+				Achievements: event.Achievements,
+			})
+		}
+	}
+}
+
+// topScoreChange returns the new first place and the one it replaced when the current
+// board's top score differs from the previous board's
+func topScoreChange(previous, current *models.Leaderboard) (leader, beaten *models.ScoreEntry) {
+	if current == nil || len(current.Entries) == 0 {
+		return nil, nil
+	}
+	leader = &current.Entries[0]
+	if previous == nil || len(previous.Entries) == 0 {
+		return leader, nil
+	}
+
+	beaten = &previous.Entries[0]
+	if leader.Initials == beaten.Initials && leader.Score == beaten.Score {
+		return nil, nil
+	}
+	return leader, beaten
+}
+
+// send fills in the event's delivery fields and delivers it to hook, retrying in the
+// background when the first attempt fails
+func (d *Dispatcher) send(ctx context.Context, hook record, payload models.WebhookEvent) {
+	payload.ID = uuid.New().String()
+	payload.WebhookID = hook.ID
+	payload.GameID = hook.GameID
+	payload.Timestamp = time.Now().UTC()
+
+	status, err := d.deliver(ctx, hook, payload, 1)
+	if err == nil {
+		return
+	}
+	tenant := tenants.FromContext(ctx)
+	if !retryable(status) || d.retries == 0 {
+		d.deadLetter(tenant, hook, payload, 1, err)
+		return
+	}
+
+	d.logger.Info("webhook delivery failed, retrying", "webhook_id", hook.ID, "game_id", hook.GameID, "error", err)
+	d.retrying.Add(1)
+	go func() {
+		defer d.retrying.Done()
+		d.retry(tenant, hook, payload, err)
+	}()
+}
+
+// retry redelivers an event whose first attempt failed with err, backing off
+// exponentially, and records a dead letter once every attempt has failed or Close gives
+// up waiting
+func (d *Dispatcher) retry(tenant string, hook record, payload models.WebhookEvent, err error) {
+	backoff := d.retryBackoff
+	attempts := 1
+	for range d.retries {
+		select {
+		case <-time.After(backoff):
+		case <-d.ctx.Done():
+			d.deadLetter(tenant, hook, payload, attempts, fmt.Errorf("abandoned at shutdown: %w", err))
+			return
+		}
+		backoff *= 2
+		attempts++
+
+		var status int
+		if status, err = d.deliver(tenants.WithTenant(d.ctx, tenant), hook, payload, attempts); err == nil {
+			return
+		}
+		if !retryable(status) {
+			break
+		}
+	}
+	d.deadLetter(tenant, hook, payload, attempts, err)
+}
+
+// deadLetter records an event that couldn't be delivered with the tenant's dead letters
+func (d *Dispatcher) deadLetter(tenant string, hook record, payload models.WebhookEvent, attempts int, err error) {
+	d.logger.Warn("webhook delivery failed", "webhook_id", hook.ID, "game_id", hook.GameID, "attempts", attempts, "error", err)
+
+	// Record it even when Close has cancelled deliveries
+	ctx, cancel := context.WithTimeout(tenants.WithTenant(context.Background(), tenant), DefaultDeliveryTimeout)
+	defer cancel()
+	letter := models.WebhookDeadLetter{
+		Event:     payload,
+		URL:       hook.URL,
+		Attempts:  attempts,
+		LastError: err.Error(),
+		FailedAt:  time.Now().UTC(),
+	}
+	if err := d.store.AddDeadLetter(ctx, letter); err != nil {
+		d.logger.Error("failed to record webhook dead letter", "webhook_id", hook.ID, "error", err)
+	}
+}
+
+// deliver POSTs payload to the webhook's URL, signed with its secret, failing on any
+// non-2xx response, and logs the attempt when a delivery log is set. It returns the
+// response status: 0 when none arrived, or -1 when the request couldn't be built.
+func (d *Dispatcher) deliver(ctx context.Context, hook record, payload models.WebhookEvent, attempt int) (int, error) {
+	body, contentType, err := encode(hook, payload)
+	if err != nil {
+		return -1, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return -1, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("X-Rawboard-Event", payload.Type)
+	req.Header.Set("X-Rawboard-Delivery", payload.ID)
+	if hook.Secret != "" {
+		timestamp := time.Now().Unix()
+		req.Header.Set("X-Rawboard-Timestamp", strconv.FormatInt(timestamp, 10))
+		req.Header.Set("X-Rawboard-Signature", Sign(hook.Secret, timestamp, body))
+	}
+
+	start := time.Now()
+	resp, err := d.client.Do(req)
+	if err != nil {
+		if d.log != nil {
+			d.log.logAttempt(tenants.FromContext(ctx), hook, payload, attempt, req, body, nil, nil, err, start)
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("receiver returned %s", resp.Status)
+	}
+	if d.log != nil {
+		// Read one byte past the cap, so the log can tell a longer body was cut
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxLoggedResponseBody+1))
+		d.log.logAttempt(tenants.FromContext(ctx), hook, payload, attempt, req, body, resp, respBody, err, start)
+	}
+	return resp.StatusCode, err
+}
+
+// encode returns the delivery body for payload in the webhook's format, and its content
+// type
+func encode(hook record, payload models.WebhookEvent) ([]byte, string, error) {
+	if hook.Format != models.WebhookFormatProtobuf {
+		body, err := json.Marshal(payload)
+		return body, "application/json", err
+	}
+	event, err := ProtobufEvent(payload)
+	if err != nil {
+		return nil, "", err
+	}
+	body, err := proto.Marshal(event)
+	return body, "application/x-protobuf", err
+}
+
+// ProtobufEvent converts a webhook event to the rawboard.v1.Event protobuf webhooks
+// receive: score.high_score becomes highscore.new, achievement.unlocked keeps its type,
+// and webhook.test has no payload. The envelope's ID is the delivery's.
+func ProtobufEvent(payload models.WebhookEvent) (*rawboardv1.Event, error) {
+	event := &rawboardv1.Event{
+		Id:            payload.ID,
+		Type:          payload.Type,
+		SchemaVersion: events.SchemaVersion,
+		GameId:        payload.GameID,
+		OccurredAt:    timestamppb.New(payload.Timestamp),
+	}
+	switch {
+	case payload.Type == models.WebhookEventTest:
+	case payload.Type == models.WebhookEventHighScore && payload.Entry != nil:
+		event.Type = events.TypeHighScoreNew
+		event.Payload = &rawboardv1.Event_HighScoreNew{HighScoreNew: events.HighScoreNew(*payload.Entry, payload.PreviousHighScore)}
+	case payload.Type == models.WebhookEventAchievement && payload.Entry != nil:
+		event.Type = events.TypeAchievementUnlocked
+		event.Payload = &rawboardv1.Event_AchievementUnlocked{AchievementUnlocked: events.AchievementUnlocked(*payload.Entry, payload.Achievements)}
+	default:
+		return nil, fmt.Errorf("%s events have no protobuf form", payload.Type)
+	}
+	return event, nil
+}
+
+// retryable reports whether a delivery that failed with status is worth retrying:
+// network errors and timeouts (no status), 429s and 5xxs
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= 500
+}
+
+// Sign returns the X-Rawboard-Signature for a delivery body sent at timestamp (Unix
+// seconds): "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the
+// webhook's secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Test sends a synthetic event to one of a game's webhooks and reports how the receiver
+// answered. The event has the given type, or webhook.test when empty; other types must be
+// ones the webhook subscribes to. Test deliveries are signed like real ones but never
+// retried or dead-lettered.
+func (d *Dispatcher) Test(ctx context.Context, gameID, webhookID, eventType string) (*models.WebhookTestResult, error) {
+	hook, err := d.store.get(ctx, gameID, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	if eventType == "" {
+		eventType = models.WebhookEventTest
+	}
+	if eventType != models.WebhookEventTest && !hook.Subscribes(eventType) {
+		return nil, fmt.Errorf("%w: the webhook doesn't subscribe to %q", ErrInvalidEvents, eventType)
+	}
+
+	payload := sampleEvent(eventType, hook)
+	payload.ID = uuid.New().String()
+	payload.WebhookID = hook.ID
+	payload.GameID = hook.GameID
+	payload.Test = true
+	payload.Timestamp = time.Now().UTC()
+
+	start := time.Now()
+	status, err := d.deliver(ctx, hook, payload, 1)
+	result := &models.WebhookTestResult{
+		Delivered:  err == nil,
+		DurationMS: time.Since(start).Milliseconds(),
+		Event:      payload,
+	}
+	if status > 0 {
+		result.StatusCode = status
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// sampleEvent returns made-up content for an event of the given type, shaped like the
+// real thing
+func sampleEvent(eventType string, hook record) models.WebhookEvent {
+	now := time.Now().UTC()
+	entry := models.ScoreEntry{Initials: "AAA", Score: 15000, Timestamp: now}
+	beaten := models.ScoreEntry{Initials: "BBB", Score: 12000, Timestamp: now.Add(-time.Hour)}
+	board := &models.Leaderboard{GameID: hook.GameID, Entries: []models.ScoreEntry{entry, beaten}, Version: 1}
+
+	event := models.WebhookEvent{Type: eventType}
+	switch eventType {
+	case models.WebhookEventPosition:
+		event.Condition = hook.Condition
+		event.Changes = []models.PositionChange{{Initials: entry.Initials, Score: entry.Score, PreviousRank: 2, Rank: 1}}
+		event.Leaderboard = board
+	case models.WebhookEventHighScore:
+		previous := models.ScoreEntry{Initials: entry.Initials, Score: 9000, Timestamp: now.Add(-24 * time.Hour)}
+		event.Entry, event.PreviousHighScore = &entry, &previous
+	case models.WebhookEventTopScore:
+		event.Entry, event.PreviousHighScore = &entry, &beaten
+		event.Leaderboard = board
+	case models.WebhookEventAchievement:
+		event.Entry = &entry
+		event.Achievements = []models.Achievement{{
+			ID: "score_10k", Name: "High Achiever", Description: "Reach 10000 points", UnlockedAt: now, Icon: "💫",
+		}}
+	}
+	return event
+}
@@ -0,0 +1,312 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// keyPrefix is the database key prefix for each game's webhook list
+	keyPrefix = "webhooks:"
+	// deadLetterPrefix is the database key prefix for each game's failed deliveries
+	deadLetterPrefix = "webhook_dead_letters:"
+	// secretPrefix marks webhook signing secrets
+	secretPrefix = "whsec_"
+)
+
+// Store errors
+var (
+	ErrNotFound      = errors.New("webhook not found")
+	ErrInvalidURL    = errors.New("webhook url must be an absolute http or https URL")
+	ErrInvalidEvents = fmt.Errorf("webhook events must be some of %s", strings.Join(models.WebhookEventTypes, ", "))
+	ErrTooMany       = fmt.Errorf("a game can have at most %d webhooks", models.MaxWebhooksPerGame)
+	ErrInvalidFormat = fmt.Errorf("webhook format must be %s or %s, and %s webhooks only take %s and %s events",
+		models.WebhookFormatJSON, models.WebhookFormatProtobuf, models.WebhookFormatProtobuf, models.WebhookEventHighScore, models.WebhookEventAchievement)
+)
+
+// record is a webhook as stored, with the secret its deliveries are signed with
+type record struct {
+	models.Webhook
+	Secret string `json:"secret,omitempty"` // Empty for webhooks registered before signing
+}
+
+// Store manages the webhooks registered for each game
+type Store struct {
+	db database.DB
+	mu sync.Mutex
+}
+
+// NewStore creates a new webhook store
+func NewStore(db database.DB) *Store {
+	return &Store{db: db}
+}
+
+// GameKeys returns the keys a store sharing its database with a game's scores keeps for
+// the game, so they can be deleted with it
+func GameKeys(gameID string) []string {
+	return []string{keyPrefix + gameID, deadLetterPrefix + gameID}
+}
+
+// List returns a game's webhooks, oldest first
+func (s *Store) List(ctx context.Context, gameID string) ([]models.Webhook, error) {
+	records, err := s.load(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+
+	hooks := make([]models.Webhook, len(records))
+	for i, record := range records {
+		hooks[i] = record.Webhook
+	}
+	return hooks, nil
+}
+
+// Create registers a webhook for the given events, or for position events when none are
+// given, storing its condition in canonical form. The condition is required for
+// position events and ignored otherwise. The returned secret signs every delivery.
+func (s *Store) Create(ctx context.Context, gameID, rawURL, condition string, events ...string) (*models.CreatedWebhook, error) {
+	return s.CreateWithFormat(ctx, gameID, rawURL, condition, models.WebhookFormatJSON, events...)
+}
+
+// CreateWithFormat registers a webhook like Create, delivering its events in format:
+// JSON, or protobuf for webhooks that only take high score and achievement events
+func (s *Store) CreateWithFormat(ctx context.Context, gameID, rawURL, condition, format string, events ...string) (*models.CreatedWebhook, error) {
+	if !validURL(rawURL) {
+		return nil, ErrInvalidURL
+	}
+	events, err := normalizeEvents(events)
+	if err != nil {
+		return nil, err
+	}
+	format, err = normalizeFormat(format, events)
+	if err != nil {
+		return nil, err
+	}
+
+	hook := models.Webhook{
+		ID:        uuid.New().String(),
+		GameID:    gameID,
+		URL:       rawURL,
+		Events:    events,
+		Format:    format,
+		Signed:    true,
+		CreatedAt: time.Now().UTC(),
+	}
+	if hook.Subscribes(models.WebhookEventPosition) {
+		cond, err := ParseCondition(condition)
+		if err != nil {
+			return nil, err
+		}
+		hook.Condition = cond.String()
+	}
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) >= models.MaxWebhooksPerGame {
+		return nil, ErrTooMany
+	}
+
+	if err := s.save(ctx, gameID, append(records, record{Webhook: hook, Secret: secret})); err != nil {
+		return nil, err
+	}
+	return &models.CreatedWebhook{Webhook: hook, Secret: secret}, nil
+}
+
+// Delete removes a webhook, returning it
+func (s *Store) Delete(ctx context.Context, gameID, id string) (*models.Webhook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for i, record := range records {
+		if record.ID == id {
+			if err := s.save(ctx, gameID, append(records[:i:i], records[i+1:]...)); err != nil {
+				return nil, err
+			}
+			return &record.Webhook, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// get returns one of a game's webhooks with its secret
+func (s *Store) get(ctx context.Context, gameID, id string) (record, error) {
+	records, err := s.load(ctx, gameID)
+	if err != nil {
+		return record{}, err
+	}
+	for _, record := range records {
+		if record.ID == id {
+			return record, nil
+		}
+	}
+	return record{}, ErrNotFound
+}
+
+// DeadLetters returns a game's failed deliveries, newest first
+func (s *Store) DeadLetters(ctx context.Context, gameID string) ([]models.WebhookDeadLetter, error) {
+	letters, err := s.loadDeadLetters(ctx, gameID)
+	if err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(letters)-1; i < j; i, j = i+1, j-1 {
+		letters[i], letters[j] = letters[j], letters[i]
+	}
+	return letters, nil
+}
+
+// AddDeadLetter records a delivery that failed every attempt, keeping the newest
+// models.MaxWebhookDeadLetters per game
+func (s *Store) AddDeadLetter(ctx context.Context, letter models.WebhookDeadLetter) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gameID := letter.Event.GameID
+	letters, err := s.loadDeadLetters(ctx, gameID)
+	if err != nil {
+		letters = nil // Start over rather than lose the new failure
+	}
+	letters = append(letters, letter)
+	if len(letters) > models.MaxWebhookDeadLetters {
+		letters = letters[len(letters)-models.MaxWebhookDeadLetters:]
+	}
+
+	data, err := json.Marshal(letters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letters: %w", err)
+	}
+	if err := s.db.Set(ctx, deadLetterPrefix+gameID, string(data)); err != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err)
+	}
+	return nil
+}
+
+// load reads a game's webhooks with their secrets
+func (s *Store) load(ctx context.Context, gameID string) ([]record, error) {
+	value, err := s.db.Get(ctx, keyPrefix+gameID)
+	if errors.Is(err, redis.Nil) {
+		return []record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var records []record
+	if err := json.Unmarshal([]byte(value), &records); err != nil {
+		return nil, fmt.Errorf("failed to parse webhooks for %s: %w", gameID, err)
+	}
+	return records, nil
+}
+
+// loadDeadLetters reads a game's failed deliveries, oldest first
+func (s *Store) loadDeadLetters(ctx context.Context, gameID string) ([]models.WebhookDeadLetter, error) {
+	value, err := s.db.Get(ctx, deadLetterPrefix+gameID)
+	if errors.Is(err, redis.Nil) {
+		return []models.WebhookDeadLetter{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var letters []models.WebhookDeadLetter
+	if err := json.Unmarshal([]byte(value), &letters); err != nil {
+		return nil, fmt.Errorf("failed to parse dead letters for %s: %w", gameID, err)
+	}
+	return letters, nil
+}
+
+// save writes a game's webhook list; s.mu must be held
+func (s *Store) save(ctx context.Context, gameID string, hooks []record) error {
+	data, err := json.Marshal(hooks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhooks: %w", err)
+	}
+	if err := s.db.Set(ctx, keyPrefix+gameID, string(data)); err != nil {
+		return fmt.Errorf("failed to save webhooks: %w", err)
+	}
+	return nil
+}
+
+// validURL reports whether rawURL is an absolute http or https URL with a host
+func validURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(u.Scheme)
+	return scheme == "http" || scheme == "https"
+}
+
+// normalizeEvents checks the event types, dropping duplicates, and defaults to position
+// events
+func normalizeEvents(events []string) ([]string, error) {
+	if len(events) == 0 {
+		return []string{models.WebhookEventPosition}, nil
+	}
+
+	var normalized []string
+	for _, event := range events {
+		event = strings.ToLower(strings.TrimSpace(event))
+		if !slices.Contains(models.WebhookEventTypes, event) {
+			return nil, fmt.Errorf("%w, got %q", ErrInvalidEvents, event)
+		}
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized, nil
+}
+
+// normalizeFormat checks a delivery format, defaulting to JSON, which is stored as no
+// format. Protobuf only carries the events the domain event schema has.
+func normalizeFormat(format string, events []string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "", models.WebhookFormatJSON:
+		return "", nil
+	case models.WebhookFormatProtobuf:
+		for _, event := range events {
+			if event != models.WebhookEventHighScore && event != models.WebhookEventAchievement {
+				return "", fmt.Errorf("%w, got %q", ErrInvalidFormat, event)
+			}
+		}
+		return models.WebhookFormatProtobuf, nil
+	default:
+		return "", fmt.Errorf("%w, got %q", ErrInvalidFormat, format)
+	}
+}
+
+// generateSecret returns a new random signing secret
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return secretPrefix + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,601 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rawboard/internal/database"
+	"rawboard/internal/events"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+	"rawboard/internal/rpc/rawboardv1"
+	"rawboard/internal/tenants"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// board builds a leaderboard with initials in rank order
+func board(initials ...string) *models.Leaderboard {
+	lb := &models.Leaderboard{GameID: "pacman"}
+	for i, player := range initials {
+		lb.Entries = append(lb.Entries, models.ScoreEntry{Initials: player, Score: int64(1000 * (len(initials) - i))})
+	}
+	return lb
+}
+
+func TestParseCondition(t *testing.T) {
+	for text, want := range map[string]string{
+		"any enters top 3":                "any enters top 3",
+		"Any score enters TOP 3":          "any enters top 3",
+		"player xyz drops out of top 10":  "player XYZ leaves top 10",
+		"  player AAA   leaves top 1 ":    "player AAA leaves top 1",
+		"any player drops out of top 100": "any leaves top 100",
+	} {
+		cond, err := ParseCondition(text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", text, err)
+			continue
+		}
+		if cond.String() != want {
+			t.Errorf("%q: expected %q, got %q", text, want, cond.String())
+		}
+	}
+
+	for _, text := range []string{
+		"",
+		"someone enters top 3",
+		"player XY enters top 3",
+		"any climbs top 3",
+		"any enters top",
+		"any enters top 0",
+		"any enters top 101",
+		"any enters bottom 3",
+		"any enters top 3 quickly",
+	} {
+		if _, err := ParseCondition(text); !errors.Is(err, ErrInvalidCondition) {
+			t.Errorf("%q: expected ErrInvalidCondition, got %v", text, err)
+		}
+	}
+}
+
+func TestConditionMatch(t *testing.T) {
+	cases := []struct {
+		condition string
+		previous  *models.Leaderboard
+		current   *models.Leaderboard
+		want      []models.PositionChange
+	}{
+		{
+			condition: "any enters top 3",
+			previous:  board("AAA", "BBB", "CCC", "DDD"),
+			current:   board("AAA", "DDD", "BBB", "CCC"),
+			want:      []models.PositionChange{{Initials: "DDD", Score: 3000, PreviousRank: 4, Rank: 2}},
+		},
+		{
+			condition: "any enters top 3",
+			previous:  board("AAA", "BBB", "CCC"),
+			current:   board("BBB", "AAA", "CCC"), // Reordered within the top 3
+		},
+		{
+			condition: "any enters top 2",
+			previous:  nil, // A game's first board
+			current:   board("NEW"),
+			want:      []models.PositionChange{{Initials: "NEW", Score: 1000, Rank: 1}},
+		},
+		{
+			condition: "player CCC drops out of top 3",
+			previous:  board("AAA", "BBB", "CCC", "DDD"),
+			current:   board("EEE", "AAA", "BBB", "CCC", "DDD"),
+			want:      []models.PositionChange{{Initials: "CCC", Score: 2000, PreviousRank: 3, Rank: 4}},
+		},
+		{
+			condition: "player DDD leaves top 3",
+			previous:  board("AAA", "BBB", "CCC", "DDD"),
+			current:   board("EEE", "AAA", "BBB", "CCC", "DDD"), // Someone else dropped out
+		},
+		{
+			condition: "any leaves top 2",
+			previous:  board("AAA", "BBB"),
+			current:   board("AAA"), // BBB was removed by moderation
+			want:      []models.PositionChange{{Initials: "BBB", Score: 1000, PreviousRank: 2}},
+		},
+	}
+
+	for _, tc := range cases {
+		cond, err := ParseCondition(tc.condition)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := cond.Match(tc.previous, tc.current)
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: expected %+v, got %+v", tc.condition, tc.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: expected %+v, got %+v", tc.condition, tc.want[i], got[i])
+			}
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(database.NewFake())
+
+	hook, err := store.Create(ctx, "pacman", "https://hooks.example.com/a", "any score enters top 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.Condition != "any enters top 3" || hook.ID == "" {
+		t.Errorf("Expected a canonical condition and an ID, got %+v", hook)
+	}
+
+	if _, err := store.Create(ctx, "pacman", "ftp://hooks.example.com", "any enters top 3"); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Expected ErrInvalidURL, got %v", err)
+	}
+	if _, err := store.Create(ctx, "pacman", "/relative", "any enters top 3"); !errors.Is(err, ErrInvalidURL) {
+		t.Errorf("Expected ErrInvalidURL for a relative URL, got %v", err)
+	}
+	if _, err := store.Create(ctx, "pacman", "https://hooks.example.com", "whenever"); !errors.Is(err, ErrInvalidCondition) {
+		t.Errorf("Expected ErrInvalidCondition, got %v", err)
+	}
+
+	if hooks, _ := store.List(ctx, "tetris"); len(hooks) != 0 {
+		t.Errorf("Expected no webhooks for another game, got %v", hooks)
+	}
+
+	if _, err := store.Delete(ctx, "pacman", "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+	if _, err := store.Delete(ctx, "pacman", hook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if hooks, _ := store.List(ctx, "pacman"); len(hooks) != 0 {
+		t.Errorf("Expected the webhook to be deleted, got %v", hooks)
+	}
+
+	for range models.MaxWebhooksPerGame {
+		if _, err := store.Create(ctx, "galaga", "https://hooks.example.com", "any enters top 1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Create(ctx, "galaga", "https://hooks.example.com", "any enters top 1"); !errors.Is(err, ErrTooMany) {
+		t.Errorf("Expected ErrTooMany, got %v", err)
+	}
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	received := make(chan *http.Request, 10)
+	payloads := make(chan models.WebhookEvent, 10)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event models.WebhookEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("Bad payload: %v", err)
+		}
+		received <- r
+		payloads <- event
+	}))
+	defer receiver.Close()
+
+	db := database.NewFake()
+	store := NewStore(db)
+	dispatcher := NewDispatcher(store)
+	service := leaderboard.NewService(db, leaderboard.WithPublisher(dispatcher))
+
+	podium, err := store.Create(ctx, "pacman", receiver.URL+"/podium", "any enters top 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Create(ctx, "pacman", receiver.URL+"/bbb", "player BBB drops out of top 2"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, submission := range []struct {
+		initials string
+		score    int64
+	}{
+		{"AAA", 5000}, // Enters the top 2
+		{"BBB", 4000}, // Enters the top 2
+		{"CCC", 1000}, // Stays outside, nothing fires
+		{"DDD", 9000}, // Enters the top 2 and pushes BBB out
+	} {
+		if err := service.SubmitScore(ctx, "pacman", submission.initials, submission.score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	dispatcher.Close(closeCtx)
+	close(received)
+	close(payloads)
+
+	var paths []string
+	for r := range received {
+		paths = append(paths, r.URL.Path)
+		if r.Header.Get("X-Rawboard-Event") != models.WebhookEventPosition || r.Header.Get("X-Rawboard-Delivery") == "" {
+			t.Errorf("Missing event headers: %v", r.Header)
+		}
+	}
+	want := []string{"/podium", "/podium", "/podium", "/bbb"}
+	if len(paths) != len(want) {
+		t.Fatalf("Expected deliveries to %v, got %v", want, paths)
+	}
+	for i := range want {
+		if paths[i] != want[i] {
+			t.Fatalf("Expected deliveries to %v, got %v", want, paths)
+		}
+	}
+
+	var last models.WebhookEvent
+	for event := range payloads {
+		if event.WebhookID == podium.ID {
+			last = event
+		}
+	}
+	if len(last.Changes) != 1 || last.Changes[0].Initials != "DDD" || last.Changes[0].Rank != 1 || last.Leaderboard == nil {
+		t.Errorf("Expected DDD entering at rank 1, got %+v", last)
+	}
+
+	// Changes after Close are ignored rather than panicking
+	if err := service.SubmitScore(ctx, "pacman", "EEE", 99999); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestStoreEvents(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore(database.NewFake())
+
+	hook, err := store.Create(ctx, "pacman", "https://hooks.example.com", "", "Score.High_Score", "leaderboard.top_1", "score.high_score")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hook.Events) != 2 || hook.Condition != "" || !hook.Signed {
+		t.Errorf("Expected two deduplicated events, no condition and signing, got %+v", hook.Webhook)
+	}
+	if len(hook.Secret) != len(secretPrefix)+64 {
+		t.Errorf("Expected a generated secret, got %q", hook.Secret)
+	}
+	if hook.Subscribes(models.WebhookEventPosition) || !hook.Subscribes(models.WebhookEventTopScore) {
+		t.Errorf("Expected a subscription to exactly the given events, got %v", hook.Events)
+	}
+
+	hooks, _ := store.List(ctx, "pacman")
+	if data, _ := json.Marshal(hooks); strings.Contains(string(data), hook.Secret) {
+		t.Error("Expected listed webhooks to leave out their secret")
+	}
+
+	if _, err := store.Create(ctx, "pacman", "https://hooks.example.com", "", "score.low_score"); !errors.Is(err, ErrInvalidEvents) {
+		t.Errorf("Expected ErrInvalidEvents, got %v", err)
+	}
+	if _, err := store.Create(ctx, "pacman", "https://hooks.example.com", "", models.WebhookEventPosition); !errors.Is(err, ErrInvalidCondition) {
+		t.Errorf("Expected position events to need a condition, got %v", err)
+	}
+
+	// Webhooks stored before event subscriptions keep receiving position events
+	legacy := models.Webhook{Condition: "any enters top 3"}
+	if !legacy.Subscribes(models.WebhookEventPosition) || legacy.Subscribes(models.WebhookEventHighScore) {
+		t.Error("Expected a webhook without events to receive only position events")
+	}
+}
+
+func TestDispatcherEvents(t *testing.T) {
+	ctx := context.Background()
+	type delivery struct {
+		request *http.Request
+		body    []byte
+		event   models.WebhookEvent
+	}
+	deliveries := make(chan delivery, 20)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var event models.WebhookEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			t.Errorf("Bad payload: %v", err)
+		}
+		deliveries <- delivery{r, body, event}
+	}))
+	defer receiver.Close()
+
+	db := database.NewFake()
+	store := NewStore(db)
+	dispatcher := NewDispatcher(store)
+	service := leaderboard.NewService(db, leaderboard.WithPublisher(dispatcher), leaderboard.WithScoreListener(dispatcher))
+
+	hook, err := store.Create(ctx, "pacman", receiver.URL, "", models.WebhookEventHighScore, models.WebhookEventTopScore, models.WebhookEventAchievement)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, submission := range []struct {
+		initials string
+		score    int64
+	}{
+		{"AAA", 5000}, // First place, first high score, first_score and score_1k/score_5k
+		{"BBB", 4000}, // A first high score and achievements, but not first place
+		{"BBB", 3000}, // Nothing new
+		{"AAA", 6000}, // Beats their own first place
+	} {
+		if err := service.SubmitScore(ctx, "pacman", submission.initials, submission.score); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	dispatcher.Close(closeCtx)
+	close(deliveries)
+
+	counts := map[string]int{}
+	var lastTop models.WebhookEvent
+	for d := range deliveries {
+		counts[d.event.Type]++
+		if d.request.Header.Get("X-Rawboard-Event") != d.event.Type {
+			t.Errorf("Expected the event header to match the payload, got %v", d.request.Header)
+		}
+		timestamp, _ := strconv.ParseInt(d.request.Header.Get("X-Rawboard-Timestamp"), 10, 64)
+		if d.request.Header.Get("X-Rawboard-Signature") != Sign(hook.Secret, timestamp, d.body) {
+			t.Errorf("Expected a valid signature, got %q", d.request.Header.Get("X-Rawboard-Signature"))
+		}
+		if d.event.Type == models.WebhookEventTopScore {
+			lastTop = d.event
+		}
+		if d.event.Type == models.WebhookEventAchievement && d.event.Entry.Initials == "AAA" && len(d.event.Achievements) != 3 {
+			t.Errorf("Expected AAA's first score to unlock 3 achievements, got %+v", d.event.Achievements)
+		}
+	}
+
+	if counts[models.WebhookEventHighScore] != 3 || counts[models.WebhookEventTopScore] != 2 || counts[models.WebhookEventAchievement] != 2 {
+		t.Errorf("Expected 3 high score, 2 first place and 2 achievement events, got %v", counts)
+	}
+	if lastTop.Entry == nil || lastTop.Entry.Score != 6000 || lastTop.PreviousHighScore == nil || lastTop.PreviousHighScore.Score != 5000 {
+		t.Errorf("Expected AAA's 6000 to replace their 5000 at first place, got %+v", lastTop)
+	}
+}
+
+func TestDispatcherProtobuf(t *testing.T) {
+	ctx := context.Background()
+	type delivery struct {
+		contentType string
+		event       *rawboardv1.Event
+	}
+	deliveries := make(chan delivery, 20)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		event := &rawboardv1.Event{}
+		if err := proto.Unmarshal(body, event); err != nil {
+			t.Errorf("Bad payload: %v", err)
+		}
+		deliveries <- delivery{r.Header.Get("Content-Type"), event}
+	}))
+	defer receiver.Close()
+
+	db := database.NewFake()
+	store := NewStore(db)
+	dispatcher := NewDispatcher(store)
+	service := leaderboard.NewService(db, leaderboard.WithScoreListener(dispatcher))
+
+	if _, err := store.CreateWithFormat(ctx, "pacman", receiver.URL, "any enters top 3", models.WebhookFormatProtobuf, models.WebhookEventPosition); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected position events to have no protobuf form, got %v", err)
+	}
+	if _, err := store.CreateWithFormat(ctx, "pacman", receiver.URL, "", "xml", models.WebhookEventHighScore); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Expected an unknown format to be refused, got %v", err)
+	}
+	hook, err := store.CreateWithFormat(ctx, "pacman", receiver.URL, "", "Protobuf", models.WebhookEventHighScore, models.WebhookEventAchievement)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.Format != models.WebhookFormatProtobuf {
+		t.Errorf("Expected the protobuf format, got %q", hook.Format)
+	}
+
+	if err := service.SubmitScore(ctx, "pacman", "AAA", 5000); err != nil {
+		t.Fatal(err)
+	}
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	dispatcher.Close(closeCtx)
+	close(deliveries)
+
+	types := map[string]bool{}
+	for d := range deliveries {
+		types[d.event.GetType()] = true
+		if d.contentType != "application/x-protobuf" || d.event.GetSchemaVersion() != events.SchemaVersion || d.event.GetGameId() != "pacman" {
+			t.Errorf("Unexpected delivery: %s %v", d.contentType, d.event)
+		}
+		if d.event.GetType() == events.TypeHighScoreNew && d.event.GetHighScoreNew().GetEntry().GetScore() != 5000 {
+			t.Errorf("Expected AAA's 5000, got %v", d.event.GetHighScoreNew())
+		}
+	}
+	if !types[events.TypeHighScoreNew] || !types[events.TypeAchievementUnlocked] || len(types) != 2 {
+		t.Errorf("Expected highscore.new and achievement.unlocked events, got %v", types)
+	}
+}
+
+func TestDeliveryLog(t *testing.T) {
+	ctx := tenants.WithTenant(context.Background(), "acme")
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Receiver", "bot")
+		w.WriteHeader(http.StatusNotFound)
+		io.WriteString(w, strings.Repeat("x", maxLoggedResponseBody+10))
+	}))
+	defer receiver.Close()
+
+	db := database.NewFake()
+	store := NewStore(db)
+	log := NewDeliveryLog(2)
+	dispatcher := NewDispatcher(store, WithDeliveryLog(log), WithRetries(0, 0))
+
+	hook, err := store.Create(ctx, "pacman", receiver.URL, "", models.WebhookEventHighScore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, eventType := range []string{"", models.WebhookEventHighScore, ""} {
+		if _, err := dispatcher.Test(ctx, "pacman", hook.ID, eventType); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deliveries := log.Recent("acme", "", 0)
+	if len(deliveries) != 2 {
+		t.Fatalf("Expected the log to keep the last 2 attempts, got %d", len(deliveries))
+	}
+	if deliveries[0].EventType != models.WebhookEventTest || deliveries[1].EventType != models.WebhookEventHighScore {
+		t.Errorf("Expected the newest attempt first, got %s then %s", deliveries[0].EventType, deliveries[1].EventType)
+	}
+	d := deliveries[1]
+	if d.Delivered || d.StatusCode != http.StatusNotFound || d.Error == "" || !d.Test || d.Attempt != 1 {
+		t.Errorf("Expected a failed test attempt, got %+v", d)
+	}
+	if d.RequestHeaders["X-Rawboard-Event"] != models.WebhookEventHighScore || !strings.Contains(d.Body, `"score.high_score"`) {
+		t.Errorf("Expected the sent headers and JSON body, got %v %s", d.RequestHeaders, d.Body)
+	}
+	if d.ResponseHeaders["X-Receiver"] != "bot" || len(d.ResponseBody) != maxLoggedResponseBody || !d.ResponseTruncated {
+		t.Errorf("Expected the response headers and a truncated body, got %v, %d bytes", d.ResponseHeaders, len(d.ResponseBody))
+	}
+
+	if got := log.Recent("acme", "pacman", 1); len(got) != 1 || got[0].EventType != models.WebhookEventTest {
+		t.Errorf("Expected the newest pacman attempt, got %+v", got)
+	}
+	if got := log.Recent("", "", 0); len(got) != 0 {
+		t.Errorf("Expected another tenant to see nothing, got %d attempts", len(got))
+	}
+	if got := log.Recent("acme", "galaga", 0); len(got) != 0 {
+		t.Errorf("Expected another game to see nothing, got %d attempts", len(got))
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	ctx := context.Background()
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			if attempts.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	db := database.NewFake()
+	store := NewStore(db)
+	dispatcher := NewDispatcher(store, WithRetries(2, time.Millisecond))
+	service := leaderboard.NewService(db, leaderboard.WithScoreListener(dispatcher))
+
+	for _, path := range []string{"/flaky", "/gone", "/down"} {
+		if _, err := store.Create(ctx, "pacman", receiver.URL+path, "", models.WebhookEventHighScore); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := service.SubmitScore(ctx, "pacman", "AAA", 100); err != nil {
+		t.Fatal(err)
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	dispatcher.Close(closeCtx)
+
+	if attempts.Load() != 3 {
+		t.Errorf("Expected the flaky receiver to succeed on its third attempt, got %d attempts", attempts.Load())
+	}
+
+	letters, err := store.DeadLetters(ctx, "pacman")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(letters) != 2 {
+		t.Fatalf("Expected dead letters for the gone and down receivers, got %+v", letters)
+	}
+	byURL := map[string]models.WebhookDeadLetter{}
+	for _, letter := range letters {
+		byURL[strings.TrimPrefix(letter.URL, receiver.URL)] = letter
+	}
+	if byURL["/gone"].Attempts != 1 {
+		t.Errorf("Expected a 410 not to be retried, got %+v", byURL["/gone"])
+	}
+	if down := byURL["/down"]; down.Attempts != 3 || down.Event.Type != models.WebhookEventHighScore || !strings.Contains(down.LastError, "500") {
+		t.Errorf("Expected a 500 to be tried 3 times before becoming a dead letter, got %+v", down)
+	}
+}
+
+func TestDispatcherTest(t *testing.T) {
+	ctx := context.Background()
+	var received models.WebhookEvent
+	var secret string
+	signed := false
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Rawboard-Timestamp"), 10, 64)
+		signed = r.Header.Get("X-Rawboard-Signature") == Sign(secret, timestamp, body)
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer receiver.Close()
+
+	store := NewStore(database.NewFake())
+	dispatcher := NewDispatcher(store, WithRetries(2, time.Millisecond))
+	defer dispatcher.Close(ctx)
+
+	hook, err := store.Create(ctx, "pacman", receiver.URL, "", models.WebhookEventHighScore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret = hook.Secret
+
+	result, err := dispatcher.Test(ctx, "pacman", hook.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Delivered || result.StatusCode != http.StatusOK || received.Type != models.WebhookEventTest || !received.Test {
+		t.Errorf("Expected a delivered webhook.test ping, got %+v (received %+v)", result, received)
+	}
+
+	result, err = dispatcher.Test(ctx, "pacman", hook.ID, models.WebhookEventHighScore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Type != models.WebhookEventHighScore || received.Entry == nil || received.PreviousHighScore == nil || received.ID != result.Event.ID {
+		t.Errorf("Expected a sample high score event, got %+v", received)
+	}
+	if !signed {
+		t.Error("Expected the test event to be signed with the webhook's secret")
+	}
+
+	if _, err := dispatcher.Test(ctx, "pacman", hook.ID, models.WebhookEventTopScore); !errors.Is(err, ErrInvalidEvents) {
+		t.Errorf("Expected an unsubscribed event to be refused, got %v", err)
+	}
+	if _, err := dispatcher.Test(ctx, "pacman", "missing", ""); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+
+	broken, _ := store.Create(ctx, "pacman", receiver.URL+"/broken", "", models.WebhookEventHighScore)
+	result, err = dispatcher.Test(ctx, "pacman", broken.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Delivered || result.StatusCode != http.StatusNotFound || result.Error == "" {
+		t.Errorf("Expected the receiver's 404 to be reported, got %+v", result)
+	}
+	if letters, _ := store.DeadLetters(ctx, "pacman"); len(letters) != 0 {
+		t.Errorf("Expected failed tests not to become dead letters, got %+v", letters)
+	}
+}
@@ -0,0 +1,724 @@
+// Package rawboard embeds the rawboard leaderboard server in another Go program.
+//
+// New wires up everything the standalone server runs from a Config. The Server is an
+// http.Handler, so a game backend can mount the leaderboard API under its own router
+// instead of running a separate process:
+//
+//	cfg, err := rawboard.LoadConfig()
+//	...
+//	board, err := rawboard.New(*cfg, rawboard.WithLogger(logger))
+//	...
+//	board.Start(ctx)
+//	mux.Handle("/leaderboard/", http.StripPrefix("/leaderboard", board))
+//	...
+//	httpServer.RegisterOnShutdown(board.CloseStreams)
+//	httpServer.Shutdown(ctx)
+//	board.Shutdown(ctx)
+//
+// Run does all of that on the configured ports, as cmd/server does. Servers keep their
+// configuration to themselves, so a process may run several, e.g. one per database.
+// Gin's debug output is process-wide and left to the program to set.
+package rawboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+
+	"rawboard/internal/apikeys"
+	"rawboard/internal/audit"
+	"rawboard/internal/broadcast"
+	"rawboard/internal/config"
+	"rawboard/internal/database"
+	"rawboard/internal/devices"
+	"rawboard/internal/displays"
+	"rawboard/internal/edge"
+	"rawboard/internal/errorreport"
+	"rawboard/internal/events"
+	"rawboard/internal/export"
+	"rawboard/internal/handlers"
+	"rawboard/internal/hooks"
+	"rawboard/internal/inbound"
+	"rawboard/internal/jobs"
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/middleware"
+	"rawboard/internal/models"
+	"rawboard/internal/objectstore"
+	"rawboard/internal/retention"
+	"rawboard/internal/rpc"
+	"rawboard/internal/rpc/rawboardv1"
+	"rawboard/internal/selfcheck"
+	"rawboard/internal/tenants"
+	"rawboard/internal/tournaments"
+	"rawboard/internal/webhooks"
+)
+
+// usageFlushInterval is how often API key usage counts are written to the database
+const usageFlushInterval = time.Minute
+
+// rateLimitCleanupInterval is how often in-memory rate limit buckets that have refilled
+// are forgotten
+const rateLimitCleanupInterval = 10 * time.Minute
+
+// displayMonitorInterval is how often display devices are checked for going dark
+const displayMonitorInterval = 30 * time.Second
+
+// seasonEndInterval is how often seasons past their end time are archived; submissions
+// archive a game's due season themselves
+const seasonEndInterval = time.Minute
+
+// leaderboardSnapshotInterval is how often boards are snapshotted for top movers; each
+// game keeps at most one snapshot an hour however often this runs
+const leaderboardSnapshotInterval = 10 * time.Minute
+
+// Config configures a Server, as the standalone server's environment variables do
+type Config = config.Config
+
+// LoadConfig reads a Config from the environment variables the standalone server uses,
+// with the same defaults
+func LoadConfig() (*Config, error) {
+	return config.Load()
+}
+
+// Server is a rawboard leaderboard server: its HTTP API, gRPC service and the
+// background work behind them
+type Server struct {
+	cfg     *Config
+	logger  *slog.Logger
+	handler http.Handler
+	grpc    *grpc.Server
+
+	db             database.DB
+	hub            *broadcast.Hub
+	eventBus       *events.Bus
+	dispatcher     *webhooks.Dispatcher
+	submissionHook *hooks.WASMHook // Nil without SUBMISSION_HOOK_WASM
+	leaderboard    *leaderboard.Service
+	valkey         *database.ValkeyDB // Nil for the in-memory and SQLite databases
+	mirror         *edge.Mirror       // Nil without EDGE_UPSTREAM_URL
+	tenants        *tenants.Registry
+	scheduler      *jobs.Scheduler
+	usageTracker   *audit.UsageTracker
+	reporter       errorreport.Reporter
+	drain          *selfcheck.Drain
+
+	mu           sync.Mutex
+	stopWatching context.CancelFunc // Nil until Start
+	closed       bool
+}
+
+// Option configures optional Server behavior
+type Option func(*options)
+
+type options struct {
+	logger    *slog.Logger
+	hooks     []SubmissionHook
+	listeners []ScoreListener
+}
+
+// WithLogger sets the logger, slog.Default() otherwise
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithSubmissionHook runs hook on every submission, after any hooks added before it
+// and before a SUBMISSION_HOOK_WASM module
+func WithSubmissionHook(hook SubmissionHook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// WithScoreListener sends every counted submission to listener, for side effects in the
+// embedding program
+func WithScoreListener(listener ScoreListener) Option {
+	return func(o *options) {
+		o.listeners = append(o.listeners, listener)
+	}
+}
+
+// New connects to the configured database and builds the server, running its startup
+// self-check. Nothing runs in the background until Start. Configurations that fail
+// validation, and production deployments the self-check finds critical problems with,
+// are refused.
+func New(cfg Config, opts ...Option) (_ *Server, err error) {
+	o := options{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("configuration validation failed: %w", err)
+	}
+	s := &Server{cfg: &cfg, logger: o.logger, drain: &selfcheck.Drain{}}
+	logger := s.logger
+
+	// Release what was opened if the server can't be built
+	defer func() {
+		if err != nil {
+			if s.submissionHook != nil {
+				s.submissionHook.Close(context.Background())
+			}
+			if s.db != nil {
+				s.db.Close()
+			}
+		}
+	}()
+
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger(logger))
+	router.Use(middleware.Compression())
+	router.Use(handlers.CacheControl())
+
+	// Report panics and server errors to Bugsnag or Sentry if either is configured
+	if provider := cfg.ErrorReportingProvider(); provider != "" {
+		s.reporter, err = errorreport.New(errorreport.Config{
+			Provider:      provider,
+			BugsnagAPIKey: cfg.BugsnagAPIKey,
+			SentryDSN:     cfg.SentryDSN,
+			Environment:   cfg.Environment,
+			Release:       cfg.Release,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error reporting configuration invalid: %w", err)
+		}
+		router.Use(s.reporter.Middleware())
+		logger.Info("error reporting enabled", "provider", provider, "release", cfg.Release)
+	}
+
+	// Initialize database - required for operation, though development servers may keep
+	// everything in memory instead
+	var memory *database.Fake
+	if cfg.InMemoryDatabase() {
+		memory = database.NewFake()
+		s.db = memory
+		logger.Warn("using the in-memory database, all data is lost when the server stops (development only)")
+	} else if cfg.SQLiteDatabase() {
+		s.db, err = database.NewSQLiteDB(cfg.SQLitePath, database.WithSQLiteLogger(logger))
+		if err != nil {
+			return nil, fmt.Errorf("database initialization failed: %w", err)
+		}
+		logger.Info("database opened", "mode", "sqlite", "path", cfg.SQLitePath)
+	} else {
+		s.valkey, err = database.NewValkeyDB(
+			database.WithLogger(logger),
+			database.WithURI(cfg.DatabaseURL),
+			database.WithTimeout(cfg.DatabaseTimeout),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("database initialization failed, rawboard requires a Redis/Valkey database to operate: %w", err)
+		}
+		s.db = s.valkey
+		logger.Info("database connected", "mode", s.valkey.Mode())
+	}
+	db := s.db
+
+	// Game data is kept per tenant; API keys, usage and rate limits span the deployment
+	tenantDB := tenants.NewDB(db)
+	tenantRegistry := tenants.NewRegistry(db)
+	s.tenants = tenantRegistry
+
+	// Initialize services
+	s.hub = broadcast.NewHub(
+		broadcast.WithBufferSize(cfg.StreamBufferSize),
+		broadcast.WithSlowClientTimeout(cfg.StreamSlowClientTimeout),
+		broadcast.WithLogger(logger),
+	)
+	webhookStore := webhooks.NewStore(tenantDB)
+	// Development servers keep recent deliveries, so integrators can see what was sent
+	webhookOpts := []webhooks.Option{webhooks.WithLogger(logger)}
+	var webhookLog *webhooks.DeliveryLog
+	if cfg.IsDevelopment() && cfg.WebhookLogSize > 0 {
+		webhookLog = webhooks.NewDeliveryLog(cfg.WebhookLogSize)
+		webhookOpts = append(webhookOpts, webhooks.WithDeliveryLog(webhookLog))
+	}
+	s.dispatcher = webhooks.NewDispatcher(webhookStore, webhookOpts...)
+	s.eventBus = events.NewBus(tenantDB, events.WithLogger(logger))
+
+	leaderboardOpts := []leaderboard.Option{
+		leaderboard.WithLogger(logger),
+		leaderboard.WithMaxEntries(cfg.MaxScoreEntries),
+		leaderboard.WithGameLimit(cfg.MaxGamesPerKey),
+		leaderboard.WithDefaultRetention(models.RetentionPolicy{
+			HistoryDays: cfg.RetentionHistoryDays,
+			MaxScores:   cfg.RetentionMaxScores,
+		}),
+		leaderboard.WithUndoWindow(cfg.ModerationUndoWindow),
+		leaderboard.WithPublisher(s.hub),
+		leaderboard.WithPublisher(s.dispatcher),
+		leaderboard.WithScoreListener(s.dispatcher),
+		leaderboard.WithScoreListener(s.eventBus),
+		leaderboard.WithResetListener(s.eventBus),
+		leaderboard.WithGameKeys(webhooks.GameKeys),
+		leaderboard.WithBlockedInitials(cfg.BlockedInitials),
+	}
+	for _, hook := range o.hooks {
+		leaderboardOpts = append(leaderboardOpts, leaderboard.WithSubmissionHook(hook))
+	}
+	for _, listener := range o.listeners {
+		leaderboardOpts = append(leaderboardOpts, leaderboard.WithScoreListener(listener))
+	}
+	// Edge instances forward every counted submission to the central instance
+	if cfg.IsEdge() {
+		s.mirror = edge.NewMirror(db, cfg.EdgeUpstreamURL, cfg.EdgeUpstreamAPIKey,
+			edge.WithLogger(logger),
+			edge.WithGames(cfg.EdgeGames...),
+		)
+		leaderboardOpts = append(leaderboardOpts, leaderboard.WithScoreListener(s.mirror))
+	}
+	// Operators may run their own WebAssembly module on every submission
+	if cfg.SubmissionHookWASM != "" {
+		s.submissionHook, err = hooks.LoadWASM(context.Background(), cfg.SubmissionHookWASM,
+			hooks.WithLogger(logger),
+			hooks.WithTimeout(cfg.SubmissionHookTimeout),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("submission hook %s failed to load: %w", cfg.SubmissionHookWASM, err)
+		}
+		leaderboardOpts = append(leaderboardOpts,
+			leaderboard.WithSubmissionHook(s.submissionHook),
+			leaderboard.WithScoreListener(s.submissionHook),
+		)
+		logger.Info("submission hook loaded", "path", cfg.SubmissionHookWASM)
+	}
+	s.leaderboard = leaderboard.NewService(tenantDB, leaderboardOpts...)
+	leaderboardService := s.leaderboard
+
+	auditLog := audit.NewLog(tenantDB)
+	keyStore := apikeys.NewStore(db)
+	s.usageTracker = audit.NewUsageTracker(db)
+	router.Use(middleware.UsageTracking(s.usageTracker))
+	router.Use(middleware.AuditWrites(auditLog, logger))
+	// Enforce the documented parameter and body constraints on every route
+	validateRequests, err := handlers.ValidateRequests()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the OpenAPI document for request validation: %w", err)
+	}
+	router.Use(validateRequests)
+
+	// Check the deployment before serving traffic
+	checker := selfcheck.NewChecker(&cfg, db, leaderboardService, logger)
+	report := checker.Run(context.Background())
+	selfcheck.Log(logger, report)
+	if cfg.IsProduction() && report.Status == models.CheckStatusCritical {
+		return nil, errors.New("startup self-check found critical problems, refusing to start in production")
+	}
+
+	// Setup background jobs
+	s.scheduler = jobs.NewScheduler(logger)
+	var exporter *export.Exporter
+	if cfg.HasObjectStore() {
+		store, err := objectstore.NewS3Store(objectstore.S3Config{
+			Endpoint:        cfg.ObjectStoreEndpoint,
+			Region:          cfg.ObjectStoreRegion,
+			Bucket:          cfg.ObjectStoreBucket,
+			AccessKeyID:     cfg.ObjectStoreAccessKeyID,
+			SecretAccessKey: cfg.ObjectStoreSecretAccessKey,
+			UsePathStyle:    cfg.ObjectStorePathStyle,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("object storage configuration invalid: %w", err)
+		}
+
+		exporter = export.NewExporter(leaderboardService, store, cfg.ExportPrefix)
+		s.scheduler.Add(jobs.Job{
+			Name:     "export",
+			Interval: cfg.ExportInterval,
+			Run: tenantRegistry.Each(func(ctx context.Context) error {
+				_, err := exporter.ExportAll(ctx)
+				return err
+			}),
+		})
+		logger.Info("scheduled exports enabled", "bucket", cfg.ObjectStoreBucket, "interval", cfg.ExportInterval.String())
+	}
+
+	pruner := retention.NewPruner(leaderboardService, auditLog, logger)
+	s.scheduler.Add(jobs.Job{
+		Name:     "retention",
+		Interval: cfg.RetentionInterval,
+		Run:      tenantRegistry.Each(pruner.Run),
+	})
+	if monitor := checker.SkewMonitor(); monitor != nil {
+		s.scheduler.Add(jobs.Job{
+			Name:     "clock-skew",
+			Interval: cfg.ClockSkewInterval,
+			Run:      monitor.Run,
+		})
+	}
+	s.scheduler.Add(jobs.Job{
+		Name:     "usage-flush",
+		Interval: usageFlushInterval,
+		Run:      s.usageTracker.Flush,
+	})
+	if s.mirror != nil {
+		pull := tenantRegistry.Each(func(ctx context.Context) error {
+			return s.mirror.Pull(ctx, leaderboardService)
+		})
+		s.scheduler.Add(jobs.Job{
+			Name:     "edge-sync",
+			Interval: cfg.EdgeSyncInterval,
+			Run: func(ctx context.Context) error {
+				_, err := s.mirror.Flush(ctx) // Retry what Run couldn't send
+				return errors.Join(err, pull(ctx))
+			},
+		})
+		logger.Info("edge mode enabled", "upstream", cfg.EdgeUpstreamURL, "sync_interval", cfg.EdgeSyncInterval.String())
+	}
+
+	// Setup API key authentication
+	if !cfg.HasAPIKey() {
+		if cfg.IsProduction() {
+			return nil, errors.New("API key is required in production environment, set RAWBOARD_API_KEY")
+		}
+		logger.Warn("no RAWBOARD_API_KEY set, authentication disabled (development mode only)")
+	} else {
+		logger.Info("API key authentication enabled")
+	}
+	rateLimitOverrides, _ := cfg.RateLimitOverrides() // Checked by Validate
+	rateLimiter := middleware.NewKeyRateLimiter(db, models.RateLimit{
+		RequestsPerSecond: cfg.APIRateLimit,
+		Burst:             cfg.APIRateBurst,
+	}, rateLimitOverrides, logger)
+	if cfg.APIRateLimit > 0 {
+		logger.Info("API rate limiting enabled", "requests_per_second", cfg.APIRateLimit, "burst", cfg.APIRateBurst,
+			"overrides", len(rateLimitOverrides), "distributed", rateLimiter.Distributed())
+	}
+	sessions := apikeys.NewSessions(cfg.APIKey, cfg.AdminSessionTTL)
+	authOptions := []middleware.AuthOption{middleware.WithRateLimit(rateLimiter), middleware.WithTenants(tenantRegistry), middleware.WithSessions(sessions)}
+	if cfg.HasJWT() {
+		verifier, err := newJWTVerifier(&cfg)
+		if err != nil {
+			return nil, fmt.Errorf("JWT authentication misconfigured: %w", err)
+		}
+		authOptions = append(authOptions, middleware.WithJWT(verifier))
+		logger.Info("JWT authentication enabled", "algorithm", cfg.JWTAlgorithm, "issuer", cfg.JWTIssuer, "audience", cfg.JWTAudience)
+	}
+	apiKeyMiddleware := middleware.APIKeyAuth(cfg.APIKey, keyStore, authOptions...)
+
+	// Infrastructure health check, plus liveness and readiness probes for orchestrators
+	router.GET("/health", healthCheck(s.valkey))
+	handlers.SetupHealthRoutes(router, cfg.ReadinessTimeout, s.drain, selfcheck.Dependency{Name: "database", Ping: db.Ping})
+
+	// Welcome endpoint with API documentation
+	router.GET("/", apiWelcomeHandler)
+
+	// Setup all API routes using the handlers package
+	handlers.SetupRoutes(router, leaderboardService, apiKeyMiddleware)
+	lookupRateLimiter := middleware.NewIPRateLimiter(middleware.RateLimitConfig{
+		RequestsPerSecond: cfg.ReceiptLookupRate,
+		BurstSize:         cfg.ReceiptLookupBurst,
+	})
+	handlers.SetupPublicRoutes(router, leaderboardService, lookupRateLimiter.Handler())
+	streamTokens := apikeys.NewStreamTokens(cfg.APIKey, cfg.StreamTokenTTL)
+	handlers.SetupStreamRoutes(router, leaderboardService, s.hub, streamTokens, apiKeyMiddleware, middleware.StreamAuth(cfg.APIKey, keyStore, streamTokens))
+	if cfg.HasEmailGateway() {
+		handlers.SetupEmailRoutes(router, leaderboardService, inbound.Config{
+			MailgunSigningKey: cfg.MailgunSigningKey,
+			SESSecret:         cfg.SESWebhookSecret,
+			AllowedSenders:    cfg.EmailAllowedSenders,
+		})
+		logger.Info("email gateway enabled", "allowed_senders", len(cfg.EmailAllowedSenders))
+	}
+	handlers.SetupAdminRoutes(router, leaderboardService, exporter, auditLog, keyStore, s.usageTracker, checker, apiKeyMiddleware)
+	handlers.SetupDrainRoutes(router, s.drain, auditLog, apiKeyMiddleware)
+	if s.mirror != nil {
+		handlers.SetupEdgeRoutes(router, s.mirror, auditLog, apiKeyMiddleware)
+	}
+	handlers.SetupSessionRoutes(router, sessions, auditLog, apiKeyMiddleware)
+	handlers.SetupWebhookRoutes(router, webhookStore, s.dispatcher, auditLog, apiKeyMiddleware)
+	displayStore := displays.NewStore(tenantDB)
+	displayMonitor := displays.NewMonitor(displayStore, auditLog, logger, cfg.DisplayOfflineAfter, cfg.DisplayAlertURL)
+	handlers.SetupDisplayRoutes(router, displayStore, displayMonitor, auditLog, apiKeyMiddleware)
+	handlers.SetupTournamentRoutes(router, tournaments.NewService(tenantDB, leaderboardService), auditLog, apiKeyMiddleware)
+	handlers.SetupPlayerRoutes(router, leaderboardService, auditLog, apiKeyMiddleware, lookupRateLimiter.Handler())
+	handlers.SetupDeviceRoutes(router, devices.NewService(tenantDB, keyStore), auditLog, apiKeyMiddleware, lookupRateLimiter.Handler())
+	handlers.SetupAdminUIRoutes(router)
+	if webhookLog != nil {
+		handlers.SetupWebhookLogRoutes(router, webhookLog, apiKeyMiddleware)
+	}
+	if memory != nil {
+		handlers.SetupDevRoutes(router, leaderboardService, func() {
+			memory.Reset()
+			tenantRegistry.Forget()
+		}, apiKeyMiddleware)
+		logger.Info("development reset enabled at POST /api/v1/dev/reset")
+	}
+
+	// Jobs that clean up after what the routes set up
+	s.scheduler.Add(jobs.Job{
+		Name:     "rate-limit-cleanup",
+		Interval: rateLimitCleanupInterval,
+		Run: func(ctx context.Context) error {
+			return errors.Join(rateLimiter.Cleanup(ctx), lookupRateLimiter.Cleanup(ctx))
+		},
+	})
+	s.scheduler.Add(jobs.Job{
+		Name:     "display-monitor",
+		Interval: displayMonitorInterval,
+		Run:      tenantRegistry.Each(displayMonitor.Run),
+	})
+	s.scheduler.Add(jobs.Job{
+		Name:     "leaderboard-snapshots",
+		Interval: leaderboardSnapshotInterval,
+		Run: tenantRegistry.Each(func(ctx context.Context) error {
+			_, err := leaderboardService.SnapshotLeaderboards(ctx, time.Now())
+			return err
+		}),
+	})
+	s.scheduler.Add(jobs.Job{
+		Name:     "season-end",
+		Interval: seasonEndInterval,
+		Run: tenantRegistry.Each(func(ctx context.Context) error {
+			ended, err := leaderboardService.EndDueSeasons(ctx, time.Now())
+			if ended > 0 {
+				logger.Info("seasons ended", "tenant", tenants.FromContext(ctx), "count", ended)
+			}
+			return err
+		}),
+	})
+
+	// The protobuf API, served natively by Run and to browser engines over gRPC-Web
+	s.grpc = rpc.NewGRPCServer(leaderboardService, s.eventBus, cfg.APIKey, keyStore, logger)
+	handlers.SetupGRPCWebRoutes(router, rawboardv1.LeaderboardService_ServiceDesc.ServiceName, rpc.GRPCWebHandler(s.grpc))
+
+	s.handler = middleware.TenantPaths(router)
+	return s, nil
+}
+
+// ServeHTTP serves the REST API, live streams, admin UI and gRPC-Web
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.handler.ServeHTTP(w, r)
+}
+
+// GRPCServer returns the server's native gRPC service, for embedding programs serving it
+// on a listener of their own. Shutdown stops it.
+func (s *Server) GRPCServer() *grpc.Server {
+	return s.grpc
+}
+
+// Start runs the background work: scheduled jobs, keeping caches coherent with other
+// replicas, relaying their events to this one's subscribers, and on edge instances
+// forwarding submissions to the central one. It returns at once;
+// the work stops with Shutdown, or when ctx is done.
+func (s *Server) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopWatching != nil || s.closed {
+		return
+	}
+
+	var watchCtx context.Context
+	watchCtx, s.stopWatching = context.WithCancel(ctx)
+	// Keep every replica's caches coherent with submissions handled elsewhere
+	go func() {
+		if err := s.leaderboard.WatchInvalidations(watchCtx); err != nil {
+			s.logger.Error("cache invalidation watcher stopped, cached reads may go stale", "error", err)
+		}
+	}()
+	// Stream every replica's events to this replica's gRPC subscribers
+	go func() {
+		if err := s.eventBus.Run(watchCtx); err != nil {
+			s.logger.Error("event bus watcher stopped, event streams will miss other replicas' events", "error", err)
+		}
+	}()
+	// Report cluster and Sentinel failovers so error spikes can be matched to them
+	if s.valkey != nil {
+		go s.valkey.WatchTopology(watchCtx, s.cfg.DatabaseTopologyInterval)
+	}
+	// Register games stored before the registry, once, so exports and retention see them
+	go func() {
+		backfill := s.tenants.Each(func(ctx context.Context) error {
+			_, err := s.leaderboard.BackfillRegistry(ctx)
+			return err
+		})
+		if err := backfill(watchCtx); err != nil && watchCtx.Err() == nil {
+			s.logger.Error("failed to register games stored before the registry, exports and retention may skip them until the next start", "error", err)
+		}
+	}()
+	// Forward submissions to the central instance as they are taken
+	if s.mirror != nil {
+		go s.mirror.Run(watchCtx)
+	}
+	s.scheduler.Start(watchCtx)
+}
+
+// CloseStreams ends live leaderboard streams, which never finish on their own. Register
+// it with the serving http.Server's RegisterOnShutdown, so its Shutdown doesn't wait
+// out its timeout on them.
+func (s *Server) CloseStreams() {
+	s.hub.Close()
+}
+
+// Shutdown fails readiness checks and stops the server once its HTTP requests are done:
+// it ends live and gRPC streams, lets in-flight gRPC calls finish, stops background
+// work, delivers what the last submissions queued for webhooks and hooks, flushes usage
+// counts and closes the database. It gives up waiting when ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.drain.Shutdown()
+	s.hub.Close()
+	s.eventBus.Close(ctx) // Publish the last events and end event streams, which never finish either
+	stopGRPC(ctx, s.grpc, s.logger)
+	s.scheduler.Stop()
+	s.dispatcher.Close(ctx) // Deliver changes from the last submissions
+	if s.submissionHook != nil {
+		s.submissionHook.Close(ctx) // Finish after_submit calls for the last submissions
+	}
+	if s.stopWatching != nil {
+		s.stopWatching()
+	}
+
+	var errs []error
+	if err := s.usageTracker.Flush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush API key usage: %w", err))
+	}
+	if err := s.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+	}
+	if s.reporter != nil {
+		s.reporter.Flush(5 * time.Second)
+	}
+	return errors.Join(errs...)
+}
+
+// Run starts the server and serves HTTP and gRPC on the configured ports until ctx is
+// done, then drains and shuts down within the configured timeouts
+func (s *Server) Run(ctx context.Context) error {
+	cfg := s.cfg
+	grpcListener, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("gRPC server failed to listen on port %s: %w", cfg.GRPCPort, err)
+	}
+	s.Start(context.Background())
+
+	go func() {
+		s.logger.Info("starting gRPC server", "port", cfg.GRPCPort)
+		if err := s.grpc.Serve(grpcListener); err != nil {
+			s.logger.Error("gRPC server stopped", "error", err)
+		}
+	}()
+
+	server := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: s,
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		s.logger.Info("starting rawboard server", "port", cfg.Port, "environment", cfg.Environment)
+		if cfg.HasTLS() {
+			serveErr <- server.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+			return
+		}
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		s.Shutdown(context.Background())
+		return fmt.Errorf("server failed to start: %w", err)
+	case <-ctx.Done():
+	}
+
+	// Fail readiness first, so load balancers stop routing here before connections are refused
+	s.drain.Shutdown()
+	if wait := s.drain.Remaining(cfg.DrainDelay); wait > 0 {
+		s.logger.Info("draining before shutdown", "wait", wait.String())
+		time.Sleep(wait)
+	}
+
+	s.logger.Info("shutting down", "timeout", cfg.ShutdownTimeout.String())
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Streams never finish on their own, so end them rather than wait out the timeout
+	server.RegisterOnShutdown(s.CloseStreams)
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		s.logger.Warn("HTTP server did not drain in time", "error", err)
+	}
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		s.logger.Error("shutdown incomplete", "error", err)
+	}
+	s.logger.Info("shutdown complete")
+	return nil
+}
+
+// stopGRPC lets in-flight gRPC calls finish, cutting them off if ctx expires first
+func stopGRPC(ctx context.Context, server *grpc.Server, logger *slog.Logger) {
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Warn("gRPC server did not drain in time")
+		server.Stop()
+	}
+}
+
+// newJWTVerifier builds the JWT verifier the configuration describes, reading the RS256
+// public key from its file
+func newJWTVerifier(cfg *Config) (*apikeys.JWTVerifier, error) {
+	jwtConfig := apikeys.JWTConfig{
+		Algorithm:  cfg.JWTAlgorithm,
+		Secret:     []byte(cfg.JWTSecret),
+		Issuer:     cfg.JWTIssuer,
+		Audience:   cfg.JWTAudience,
+		GamesClaim: cfg.JWTGamesClaim,
+		RoleClaim:  cfg.JWTRoleClaim,
+	}
+	if cfg.JWTAlgorithm == apikeys.JWTAlgorithmRS256 {
+		data, err := os.ReadFile(cfg.JWTPublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT_PUBLIC_KEY_FILE: %w", err)
+		}
+		if jwtConfig.PublicKey, err = apikeys.ParseRSAPublicKey(data); err != nil {
+			return nil, fmt.Errorf("JWT_PUBLIC_KEY_FILE: %w", err)
+		}
+	}
+	return apikeys.NewJWTVerifier(jwtConfig)
+}
+
+// healthCheck reports the server healthy, with Valkey's connection stats when there
+// is a Valkey database (nil for the in-memory one)
+func healthCheck(db *database.ValkeyDB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response := handlers.NewHealthResponse(
+			"healthy",
+			"rawboard",
+			"1.0.0",
+			time.Now().UTC().Format(time.RFC3339),
+		)
+		if db != nil {
+			stats := db.Stats()
+			response.Database = &stats
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func apiWelcomeHandler(c *gin.Context) {
+	response := handlers.NewWelcomeResponse()
+	c.JSON(http.StatusOK, response)
+}
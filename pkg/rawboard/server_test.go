@@ -0,0 +1,217 @@
+package rawboard
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestServer(t *testing.T, opts ...Option) *Server {
+	t.Helper()
+	return newConfiguredServer(t, func(*Config) {}, opts...)
+}
+
+// newConfiguredServer builds an in-memory development server after configure adjusts
+// its configuration
+func newConfiguredServer(t *testing.T, configure func(*Config), opts ...Option) *Server {
+	t.Helper()
+	cfg := testConfig(t)
+	configure(cfg)
+	server, err := New(*cfg, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+	return server
+}
+
+func testConfig(t *testing.T) *Config {
+	t.Helper()
+	t.Setenv("DATABASE_BACKEND", "memory")
+	t.Setenv("ENVIRONMENT", "development")
+	t.Setenv("RAWBOARD_API_KEY", "")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	return cfg
+}
+
+func TestServersKeepTheirOwnConfig(t *testing.T) {
+	strict := newConfiguredServer(t, func(cfg *Config) { cfg.BlockedInitials = []string{"ZZZ"} })
+	lenient := newTestServer(t)
+
+	for server, want := range map[*Server]int{strict: http.StatusBadRequest, lenient: http.StatusCreated} {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/games/pacman/scores", strings.NewReader(`{"initials":"ZZZ","score":100}`))
+		req.Header.Set("Content-Type", "application/json")
+		server.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("POST ZZZ = %d (%s), want %d", rec.Code, rec.Body.String(), want)
+		}
+	}
+
+	// The configured URI wins over the environment's
+	cfg := testConfig(t)
+	t.Setenv("VALKEY_URI", "redis://127.0.0.1:6379")
+	cfg.DatabaseBackend = "valkey"
+	cfg.DatabaseURL = "redis://127.0.0.1:1"
+	cfg.DatabaseTimeout = time.Second
+	if _, err := New(*cfg); err == nil || !strings.Contains(err.Error(), "127.0.0.1:1 ") {
+		t.Errorf("New() error = %v, want a failed connection to the configured 127.0.0.1:1", err)
+	}
+}
+
+func TestServerMountedUnderPrefix(t *testing.T) {
+	var mu sync.Mutex
+	var seen []ScoreEvent
+	listener := scoreListenerFunc(func(event ScoreEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, event)
+	})
+	double := SubmissionHookFunc(func(ctx context.Context, gameID string, submission *Submission) error {
+		submission.Score *= 2
+		return nil
+	})
+	server := newTestServer(t, WithSubmissionHook(double), WithScoreListener(listener))
+	server.Start(context.Background())
+
+	mux := http.NewServeMux()
+	mux.Handle("/leaderboard/", http.StripPrefix("/leaderboard", server))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/leaderboard/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /leaderboard/health = %d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	body := strings.NewReader(`{"initials":"AAA","score":100}`)
+	req := httptest.NewRequest(http.MethodPost, "/leaderboard/api/v1/games/pacman/scores", body)
+	req.Header.Set("Content-Type", "application/json")
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+		t.Fatalf("POST score = %d (%s), want success", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/leaderboard/api/v1/games/pacman/leaderboard", nil))
+	var leaderboard struct {
+		Entries []struct {
+			Initials string `json:"initials"`
+			Score    int64  `json:"score"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &leaderboard); err != nil {
+		t.Fatalf("leaderboard response %q: %v", rec.Body.String(), err)
+	}
+	if len(leaderboard.Entries) != 1 || leaderboard.Entries[0].Score != 200 {
+		t.Errorf("leaderboard = %+v, want the hook's 200", leaderboard.Entries)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(seen)
+		mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("listener saw %d scores, want 1", n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestServerShutdown(t *testing.T) {
+	server := newTestServer(t)
+	server.Start(context.Background())
+
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	// Readiness fails once shut down
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+	if rec.Code == http.StatusOK {
+		t.Error("GET /health/ready = 200 after Shutdown, want it failing")
+	}
+	if err := server.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown() error = %v, want nil", err)
+	}
+}
+
+func TestEdgeServer(t *testing.T) {
+	central := newTestServer(t)
+	upstream := httptest.NewServer(central)
+	defer upstream.Close()
+	post := func(handler http.Handler, initials string, score int) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/games/pacman/scores", strings.NewReader(`{"initials":"`+initials+`","score":`+strconv.Itoa(score)+`}`))
+		req.Header.Set("Content-Type", "application/json")
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("POST score = %d (%s), want success", rec.Code, rec.Body.String())
+		}
+	}
+	// board returns a game's leaderboard as initials -> score
+	board := func(handler http.Handler) map[string]int64 {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/games/pacman/leaderboard", nil))
+		var leaderboard struct {
+			Entries []struct {
+				Initials string `json:"initials"`
+				Score    int64  `json:"score"`
+			} `json:"entries"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &leaderboard)
+		scores := make(map[string]int64)
+		for _, entry := range leaderboard.Entries {
+			scores[entry.Initials] = entry.Score
+		}
+		return scores
+	}
+	post(central, "BBB", 5000)
+
+	t.Setenv("DATABASE_BACKEND", "sqlite")
+	t.Setenv("SQLITE_PATH", filepath.Join(t.TempDir(), "edge.db"))
+	t.Setenv("EDGE_UPSTREAM_URL", upstream.URL)
+	t.Setenv("EDGE_SYNC_INTERVAL", "1s")
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	edge, err := New(*cfg)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer edge.Shutdown(context.Background())
+	edge.Start(context.Background())
+	post(edge, "AAA", 1000)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		centralBoard, edgeBoard := board(central), board(edge)
+		if centralBoard["AAA"] == 1000 && edgeBoard["BBB"] == 5000 && edgeBoard["AAA"] == 1000 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("central board = %v, edge board = %v, want both with AAA's 1000 and BBB's 5000", centralBoard, edgeBoard)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+type scoreListenerFunc func(ScoreEvent)
+
+func (f scoreListenerFunc) ScoreSubmitted(event ScoreEvent) { f(event) }
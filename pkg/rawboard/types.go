@@ -0,0 +1,25 @@
+package rawboard
+
+import (
+	"rawboard/internal/leaderboard"
+	"rawboard/internal/models"
+)
+
+// SubmissionHook inspects, changes or rejects submissions before they are stored
+type SubmissionHook = leaderboard.SubmissionHook
+
+// SubmissionHookFunc adapts a function to a SubmissionHook
+type SubmissionHookFunc = leaderboard.SubmissionHookFunc
+
+// Submission is a score submission as a hook sees it
+type Submission = models.Submission
+
+// SubmissionRejectedError rejects a submission from a hook, sent to the client with its
+// reason
+type SubmissionRejectedError = models.SubmissionRejectedError
+
+// ScoreListener is told about every counted submission
+type ScoreListener = leaderboard.ScoreListener
+
+// ScoreEvent is a counted submission, as a ScoreListener sees it
+type ScoreEvent = models.ScoreEvent